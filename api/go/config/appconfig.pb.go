@@ -76,6 +76,59 @@ func (MetaDataType) EnumDescriptor() ([]byte, []int) {
 	return file_config_appconfig_proto_rawDescGZIP(), []int{0}
 }
 
+// Placement of an application instance on the NUMA node local to the
+// adapters assigned to it
+type AffinityPolicy int32
+
+const (
+	AffinityPolicy_AFFINITY_POLICY_NONE AffinityPolicy = 0
+	// Use the NUMA node of the adapters if known, otherwise run anywhere
+	AffinityPolicy_AFFINITY_POLICY_PREFER_ADAPTER_NUMA AffinityPolicy = 1
+	// Fail unless the adapters are all local to the same, known, NUMA node
+	AffinityPolicy_AFFINITY_POLICY_REQUIRE_ADAPTER_NUMA AffinityPolicy = 2
+)
+
+// Enum value maps for AffinityPolicy.
+var (
+	AffinityPolicy_name = map[int32]string{
+		0: "AFFINITY_POLICY_NONE",
+		1: "AFFINITY_POLICY_PREFER_ADAPTER_NUMA",
+		2: "AFFINITY_POLICY_REQUIRE_ADAPTER_NUMA",
+	}
+	AffinityPolicy_value = map[string]int32{
+		"AFFINITY_POLICY_NONE":                 0,
+		"AFFINITY_POLICY_PREFER_ADAPTER_NUMA":  1,
+		"AFFINITY_POLICY_REQUIRE_ADAPTER_NUMA": 2,
+	}
+)
+
+func (x AffinityPolicy) Enum() *AffinityPolicy {
+	p := new(AffinityPolicy)
+	*p = x
+	return p
+}
+
+func (x AffinityPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AffinityPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_config_appconfig_proto_enumTypes[1].Descriptor()
+}
+
+func (AffinityPolicy) Type() protoreflect.EnumType {
+	return &file_config_appconfig_proto_enumTypes[1]
+}
+
+func (x AffinityPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AffinityPolicy.Descriptor instead.
+func (AffinityPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_config_appconfig_proto_rawDescGZIP(), []int{1}
+}
+
 type InstanceOpsCmd struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -212,6 +265,19 @@ type AppInstanceConfig struct {
 	// be started independent of the global or local profile specified for the
 	// device.
 	ProfileList []string `protobuf:"bytes,18,rep,name=profile_list,json=profileList,proto3" json:"profile_list,omitempty"`
+	// Set to allow the local profile server to restart the application
+	// instance using LocalProfile.app_restarts.
+	AllowLocalRestart bool `protobuf:"varint,19,opt,name=allow_local_restart,json=allowLocalRestart,proto3" json:"allow_local_restart,omitempty"`
+	// Number of days the data volumes of the app instance are retained
+	// after the app instance is removed from the config, so that the removal
+	// can be undone by adding the app instance back. Zero means the volumes
+	// are deleted immediately. At most 30.
+	VolumeRetentionDays uint32 `protobuf:"varint,20,opt,name=volume_retention_days,json=volumeRetentionDays,proto3" json:"volume_retention_days,omitempty"`
+	// Filtering of the console and syslog output of the app instance by
+	// the device before it is sent to the controller
+	LogPolicy *AppLogPolicy `protobuf:"bytes,21,opt,name=log_policy,json=logPolicy,proto3" json:"log_policy,omitempty"`
+	// Placement on the NUMA node of the adapters
+	AffinityPolicy AffinityPolicy `protobuf:"varint,22,opt,name=affinity_policy,json=affinityPolicy,proto3,enum=org.lfedge.eve.config.AffinityPolicy" json:"affinity_policy,omitempty"`
 }
 
 func (x *AppInstanceConfig) Reset() {
@@ -358,6 +424,105 @@ func (x *AppInstanceConfig) GetProfileList() []string {
 	return nil
 }
 
+func (x *AppInstanceConfig) GetAllowLocalRestart() bool {
+	if x != nil {
+		return x.AllowLocalRestart
+	}
+	return false
+}
+
+func (x *AppInstanceConfig) GetVolumeRetentionDays() uint32 {
+	if x != nil {
+		return x.VolumeRetentionDays
+	}
+	return 0
+}
+
+func (x *AppInstanceConfig) GetLogPolicy() *AppLogPolicy {
+	if x != nil {
+		return x.LogPolicy
+	}
+	return nil
+}
+
+func (x *AppInstanceConfig) GetAffinityPolicy() AffinityPolicy {
+	if x != nil {
+		return x.AffinityPolicy
+	}
+	return AffinityPolicy_AFFINITY_POLICY_NONE
+}
+
+// AppLogPolicy filters the logs of an app instance. An invalid policy is
+// reported but not applied; the app instance is not affected.
+type AppLogPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Drop the log entries less severe than this, one of "panic", "fatal",
+	// "error", "warning", "info", "debug" and "trace". Empty keeps all.
+	MinSeverity string `protobuf:"bytes,1,opt,name=min_severity,json=minSeverity,proto3" json:"min_severity,omitempty"`
+	// Drop the log entries matching any of these regular expressions
+	// (RE2 syntax). At most 10.
+	DropRegex []string `protobuf:"bytes,2,rep,name=drop_regex,json=dropRegex,proto3" json:"drop_regex,omitempty"`
+	// Percentage of the remaining log entries which are kept, 1-100.
+	// Zero keeps all.
+	SamplePercent uint32 `protobuf:"varint,3,opt,name=sample_percent,json=samplePercent,proto3" json:"sample_percent,omitempty"`
+}
+
+func (x *AppLogPolicy) Reset() {
+	*x = AppLogPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_config_appconfig_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppLogPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppLogPolicy) ProtoMessage() {}
+
+func (x *AppLogPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_config_appconfig_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppLogPolicy.ProtoReflect.Descriptor instead.
+func (*AppLogPolicy) Descriptor() ([]byte, []int) {
+	return file_config_appconfig_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AppLogPolicy) GetMinSeverity() string {
+	if x != nil {
+		return x.MinSeverity
+	}
+	return ""
+}
+
+func (x *AppLogPolicy) GetDropRegex() []string {
+	if x != nil {
+		return x.DropRegex
+	}
+	return nil
+}
+
+func (x *AppLogPolicy) GetSamplePercent() uint32 {
+	if x != nil {
+		return x.SamplePercent
+	}
+	return 0
+}
+
 // Reference to a Volume specified separately in the API
 // If a volume is purged (re-created from scratch) it will either have a new
 // UUID or a new generationCount
@@ -376,7 +541,7 @@ type VolumeRef struct {
 func (x *VolumeRef) Reset() {
 	*x = VolumeRef{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_config_appconfig_proto_msgTypes[2]
+		mi := &file_config_appconfig_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -389,7 +554,7 @@ func (x *VolumeRef) String() string {
 func (*VolumeRef) ProtoMessage() {}
 
 func (x *VolumeRef) ProtoReflect() protoreflect.Message {
-	mi := &file_config_appconfig_proto_msgTypes[2]
+	mi := &file_config_appconfig_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -402,7 +567,7 @@ func (x *VolumeRef) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VolumeRef.ProtoReflect.Descriptor instead.
 func (*VolumeRef) Descriptor() ([]byte, []int) {
-	return file_config_appconfig_proto_rawDescGZIP(), []int{2}
+	return file_config_appconfig_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *VolumeRef) GetUuid() string {
@@ -443,7 +608,7 @@ var file_config_appconfig_proto_rawDesc = []byte{
 	0x6d, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20,
 	0x01, 0x28, 0x0d, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07,
 	0x6f, 0x70, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f,
-	0x70, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x8a, 0x07, 0x0a, 0x11, 0x41, 0x70, 0x70, 0x49, 0x6e,
+	0x70, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x82, 0x09, 0x0a, 0x11, 0x41, 0x70, 0x70, 0x49, 0x6e,
 	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x4d, 0x0a, 0x0e,
 	0x75, 0x75, 0x69, 0x64, 0x61, 0x6e, 0x64, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67,
@@ -500,20 +665,51 @@ var file_config_appconfig_proto_rawDesc = []byte{
 	0x70, 0x65, 0x52, 0x0c, 0x6d, 0x65, 0x74, 0x61, 0x44, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65,
 	0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x6c, 0x69, 0x73, 0x74,
 	0x18, 0x12, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x4c,
-	0x69, 0x73, 0x74, 0x22, 0x66, 0x0a, 0x09, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x65, 0x66,
-	0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x75, 0x75, 0x69, 0x64, 0x12, 0x28, 0x0a, 0x0f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x67,
-	0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1b,
-	0x0a, 0x09, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x08, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x44, 0x69, 0x72, 0x2a, 0x66, 0x0a, 0x0c, 0x4d,
-	0x65, 0x74, 0x61, 0x44, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x4d,
-	0x65, 0x74, 0x61, 0x44, 0x61, 0x74, 0x61, 0x44, 0x72, 0x69, 0x76, 0x65, 0x10, 0x00, 0x12, 0x10,
-	0x0a, 0x0c, 0x4d, 0x65, 0x74, 0x61, 0x44, 0x61, 0x74, 0x61, 0x4e, 0x6f, 0x6e, 0x65, 0x10, 0x01,
-	0x12, 0x15, 0x0a, 0x11, 0x4d, 0x65, 0x74, 0x61, 0x44, 0x61, 0x74, 0x61, 0x4f, 0x70, 0x65, 0x6e,
-	0x53, 0x74, 0x61, 0x63, 0x6b, 0x10, 0x02, 0x12, 0x1a, 0x0a, 0x16, 0x4d, 0x65, 0x74, 0x61, 0x44,
-	0x61, 0x74, 0x61, 0x44, 0x72, 0x69, 0x76, 0x65, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x72,
-	0x74, 0x10, 0x03, 0x42, 0x3d, 0x0a, 0x15, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67,
+	0x69, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x13, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x5f, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x11, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x12, 0x32, 0x0a, 0x15, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x72, 0x65,
+	0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x18, 0x14, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x13, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x65, 0x74, 0x65, 0x6e, 0x74,
+	0x69, 0x6f, 0x6e, 0x44, 0x61, 0x79, 0x73, 0x12, 0x42, 0x0a, 0x0a, 0x6c, 0x6f, 0x67, 0x5f, 0x70,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x6f, 0x72,
+	0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x2e, 0x41, 0x70, 0x70, 0x4c, 0x6f, 0x67, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x52, 0x09, 0x6c, 0x6f, 0x67, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x4e, 0x0a, 0x0f, 0x61,
+	0x66, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x79, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x16,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x25, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67,
+	0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x41, 0x66, 0x66,
+	0x69, 0x6e, 0x69, 0x74, 0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0e, 0x61, 0x66, 0x66,
+	0x69, 0x6e, 0x69, 0x74, 0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x22, 0x77, 0x0a, 0x0c, 0x41,
+	0x70, 0x70, 0x4c, 0x6f, 0x67, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x6d,
+	0x69, 0x6e, 0x5f, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x6d, 0x69, 0x6e, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x1d,
+	0x0a, 0x0a, 0x64, 0x72, 0x6f, 0x70, 0x5f, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x09, 0x64, 0x72, 0x6f, 0x70, 0x52, 0x65, 0x67, 0x65, 0x78, 0x12, 0x25, 0x0a,
+	0x0e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x50, 0x65, 0x72,
+	0x63, 0x65, 0x6e, 0x74, 0x22, 0x66, 0x0a, 0x09, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x65,
+	0x66, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x28, 0x0a, 0x0f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f,
+	0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x1b, 0x0a, 0x09, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x44, 0x69, 0x72, 0x2a, 0x66, 0x0a, 0x0c,
+	0x4d, 0x65, 0x74, 0x61, 0x44, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x12, 0x11, 0x0a, 0x0d,
+	0x4d, 0x65, 0x74, 0x61, 0x44, 0x61, 0x74, 0x61, 0x44, 0x72, 0x69, 0x76, 0x65, 0x10, 0x00, 0x12,
+	0x10, 0x0a, 0x0c, 0x4d, 0x65, 0x74, 0x61, 0x44, 0x61, 0x74, 0x61, 0x4e, 0x6f, 0x6e, 0x65, 0x10,
+	0x01, 0x12, 0x15, 0x0a, 0x11, 0x4d, 0x65, 0x74, 0x61, 0x44, 0x61, 0x74, 0x61, 0x4f, 0x70, 0x65,
+	0x6e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x10, 0x02, 0x12, 0x1a, 0x0a, 0x16, 0x4d, 0x65, 0x74, 0x61,
+	0x44, 0x61, 0x74, 0x61, 0x44, 0x72, 0x69, 0x76, 0x65, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61,
+	0x72, 0x74, 0x10, 0x03, 0x2a, 0x7d, 0x0a, 0x0e, 0x41, 0x66, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x79,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x18, 0x0a, 0x14, 0x41, 0x46, 0x46, 0x49, 0x4e, 0x49,
+	0x54, 0x59, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00,
+	0x12, 0x27, 0x0a, 0x23, 0x41, 0x46, 0x46, 0x49, 0x4e, 0x49, 0x54, 0x59, 0x5f, 0x50, 0x4f, 0x4c,
+	0x49, 0x43, 0x59, 0x5f, 0x50, 0x52, 0x45, 0x46, 0x45, 0x52, 0x5f, 0x41, 0x44, 0x41, 0x50, 0x54,
+	0x45, 0x52, 0x5f, 0x4e, 0x55, 0x4d, 0x41, 0x10, 0x01, 0x12, 0x28, 0x0a, 0x24, 0x41, 0x46, 0x46,
+	0x49, 0x4e, 0x49, 0x54, 0x59, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x52, 0x45, 0x51,
+	0x55, 0x49, 0x52, 0x45, 0x5f, 0x41, 0x44, 0x41, 0x50, 0x54, 0x45, 0x52, 0x5f, 0x4e, 0x55, 0x4d,
+	0x41, 0x10, 0x02, 0x42, 0x3d, 0x0a, 0x15, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67,
 	0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5a, 0x24, 0x67, 0x69,
 	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x66, 0x2d, 0x65, 0x64, 0x67, 0x65,
 	0x2f, 0x65, 0x76, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x67, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66,
@@ -532,36 +728,40 @@ func file_config_appconfig_proto_rawDescGZIP() []byte {
 	return file_config_appconfig_proto_rawDescData
 }
 
-var file_config_appconfig_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_config_appconfig_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_config_appconfig_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_config_appconfig_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_config_appconfig_proto_goTypes = []interface{}{
 	(MetaDataType)(0),         // 0: org.lfedge.eve.config.MetaDataType
-	(*InstanceOpsCmd)(nil),    // 1: org.lfedge.eve.config.InstanceOpsCmd
-	(*AppInstanceConfig)(nil), // 2: org.lfedge.eve.config.AppInstanceConfig
-	(*VolumeRef)(nil),         // 3: org.lfedge.eve.config.VolumeRef
-	(*UUIDandVersion)(nil),    // 4: org.lfedge.eve.config.UUIDandVersion
-	(*VmConfig)(nil),          // 5: org.lfedge.eve.config.VmConfig
-	(*Drive)(nil),             // 6: org.lfedge.eve.config.Drive
-	(*NetworkAdapter)(nil),    // 7: org.lfedge.eve.config.NetworkAdapter
-	(*Adapter)(nil),           // 8: org.lfedge.eve.config.Adapter
-	(*CipherBlock)(nil),       // 9: org.lfedge.eve.config.CipherBlock
+	(AffinityPolicy)(0),       // 1: org.lfedge.eve.config.AffinityPolicy
+	(*InstanceOpsCmd)(nil),    // 2: org.lfedge.eve.config.InstanceOpsCmd
+	(*AppInstanceConfig)(nil), // 3: org.lfedge.eve.config.AppInstanceConfig
+	(*AppLogPolicy)(nil),      // 4: org.lfedge.eve.config.AppLogPolicy
+	(*VolumeRef)(nil),         // 5: org.lfedge.eve.config.VolumeRef
+	(*UUIDandVersion)(nil),    // 6: org.lfedge.eve.config.UUIDandVersion
+	(*VmConfig)(nil),          // 7: org.lfedge.eve.config.VmConfig
+	(*Drive)(nil),             // 8: org.lfedge.eve.config.Drive
+	(*NetworkAdapter)(nil),    // 9: org.lfedge.eve.config.NetworkAdapter
+	(*Adapter)(nil),           // 10: org.lfedge.eve.config.Adapter
+	(*CipherBlock)(nil),       // 11: org.lfedge.eve.config.CipherBlock
 }
 var file_config_appconfig_proto_depIdxs = []int32{
-	4,  // 0: org.lfedge.eve.config.AppInstanceConfig.uuidandversion:type_name -> org.lfedge.eve.config.UUIDandVersion
-	5,  // 1: org.lfedge.eve.config.AppInstanceConfig.fixedresources:type_name -> org.lfedge.eve.config.VmConfig
-	6,  // 2: org.lfedge.eve.config.AppInstanceConfig.drives:type_name -> org.lfedge.eve.config.Drive
-	7,  // 3: org.lfedge.eve.config.AppInstanceConfig.interfaces:type_name -> org.lfedge.eve.config.NetworkAdapter
-	8,  // 4: org.lfedge.eve.config.AppInstanceConfig.adapters:type_name -> org.lfedge.eve.config.Adapter
-	1,  // 5: org.lfedge.eve.config.AppInstanceConfig.restart:type_name -> org.lfedge.eve.config.InstanceOpsCmd
-	1,  // 6: org.lfedge.eve.config.AppInstanceConfig.purge:type_name -> org.lfedge.eve.config.InstanceOpsCmd
-	9,  // 7: org.lfedge.eve.config.AppInstanceConfig.cipherData:type_name -> org.lfedge.eve.config.CipherBlock
-	3,  // 8: org.lfedge.eve.config.AppInstanceConfig.volumeRefList:type_name -> org.lfedge.eve.config.VolumeRef
+	6,  // 0: org.lfedge.eve.config.AppInstanceConfig.uuidandversion:type_name -> org.lfedge.eve.config.UUIDandVersion
+	7,  // 1: org.lfedge.eve.config.AppInstanceConfig.fixedresources:type_name -> org.lfedge.eve.config.VmConfig
+	8,  // 2: org.lfedge.eve.config.AppInstanceConfig.drives:type_name -> org.lfedge.eve.config.Drive
+	9,  // 3: org.lfedge.eve.config.AppInstanceConfig.interfaces:type_name -> org.lfedge.eve.config.NetworkAdapter
+	10, // 4: org.lfedge.eve.config.AppInstanceConfig.adapters:type_name -> org.lfedge.eve.config.Adapter
+	2,  // 5: org.lfedge.eve.config.AppInstanceConfig.restart:type_name -> org.lfedge.eve.config.InstanceOpsCmd
+	2,  // 6: org.lfedge.eve.config.AppInstanceConfig.purge:type_name -> org.lfedge.eve.config.InstanceOpsCmd
+	11, // 7: org.lfedge.eve.config.AppInstanceConfig.cipherData:type_name -> org.lfedge.eve.config.CipherBlock
+	5,  // 8: org.lfedge.eve.config.AppInstanceConfig.volumeRefList:type_name -> org.lfedge.eve.config.VolumeRef
 	0,  // 9: org.lfedge.eve.config.AppInstanceConfig.metaDataType:type_name -> org.lfedge.eve.config.MetaDataType
-	10, // [10:10] is the sub-list for method output_type
-	10, // [10:10] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	4,  // 10: org.lfedge.eve.config.AppInstanceConfig.log_policy:type_name -> org.lfedge.eve.config.AppLogPolicy
+	1,  // 11: org.lfedge.eve.config.AppInstanceConfig.affinity_policy:type_name -> org.lfedge.eve.config.AffinityPolicy
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_config_appconfig_proto_init() }
@@ -600,6 +800,18 @@ func file_config_appconfig_proto_init() {
 			}
 		}
 		file_config_appconfig_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AppLogPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_config_appconfig_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*VolumeRef); i {
 			case 0:
 				return &v.state
@@ -617,8 +829,8 @@ func file_config_appconfig_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_config_appconfig_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   3,
+			NumEnums:      2,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
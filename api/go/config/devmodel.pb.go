@@ -191,6 +191,16 @@ type SystemAdapter struct {
 	// Load spreading will apply when multiple adapters have the same cost.
 	// Higher cost adapters are only tried when none of the lower cost ones work.
 	Cost uint32 `protobuf:"varint,9,opt,name=cost,proto3" json:"cost,omitempty"`
+	// sharedLabels - labels shared by a group of ports, which a network
+	// instance can use in its port reference instead of the name of a port.
+	// A shared label must not be the name of a port nor one of the labels
+	// which EVE adds implicitly: "uplink" for the management ports and
+	// "freeuplink" for the management ports with a zero cost.
+	SharedLabels []string `protobuf:"bytes,10,rep,name=sharedLabels,proto3" json:"sharedLabels,omitempty"`
+	// addrs - the static addresses of a port, at most one per IP version,
+	// e.g. an IPv4 and an IPv6 address for dual stack. Each must be in the
+	// subnet of its IP version of the network. If empty addr is used.
+	Addrs []string `protobuf:"bytes,11,rep,name=addrs,proto3" json:"addrs,omitempty"`
 }
 
 func (x *SystemAdapter) Reset() {
@@ -281,6 +291,20 @@ func (x *SystemAdapter) GetCost() uint32 {
 	return 0
 }
 
+func (x *SystemAdapter) GetSharedLabels() []string {
+	if x != nil {
+		return x.SharedLabels
+	}
+	return nil
+}
+
+func (x *SystemAdapter) GetAddrs() []string {
+	if x != nil {
+		return x.Addrs
+	}
+	return nil
+}
+
 // Given additional details for EVE software to how to treat this
 // interface. Example policies could be limit use of LTE interface
 // or only use Eth1 only if Eth0 is not available etc
@@ -391,6 +415,12 @@ type PhysicalIO struct {
 	// physical and logical attributes
 	//    For example in WWAN to which firmware version to load etc
 	Cbattr map[string]string `protobuf:"bytes,8,rep,name=cbattr,proto3" json:"cbattr,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// accelerator_memory_mb - the memory of a GPU or other accelerator which
+	// app instances can reserve. Zero if not advertised.
+	AcceleratorMemoryMb uint32 `protobuf:"varint,9,opt,name=accelerator_memory_mb,json=acceleratorMemoryMb,proto3" json:"accelerator_memory_mb,omitempty"`
+	// numa - the NUMA node local to the adapter. Not set if it is not known,
+	// in which case app instances cannot be placed on the node of the adapter.
+	Numa *PhyIONuma `protobuf:"bytes,10,opt,name=numa,proto3" json:"numa,omitempty"`
 }
 
 func (x *PhysicalIO) Reset() {
@@ -481,6 +511,68 @@ func (x *PhysicalIO) GetCbattr() map[string]string {
 	return nil
 }
 
+func (x *PhysicalIO) GetAcceleratorMemoryMb() uint32 {
+	if x != nil {
+		return x.AcceleratorMemoryMb
+	}
+	return 0
+}
+
+func (x *PhysicalIO) GetNuma() *PhyIONuma {
+	if x != nil {
+		return x.Numa
+	}
+	return nil
+}
+
+// PhyIONuma is the NUMA topology of a PhysicalIO
+type PhyIONuma struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Node uint32 `protobuf:"varint,1,opt,name=node,proto3" json:"node,omitempty"`
+}
+
+func (x *PhyIONuma) Reset() {
+	*x = PhyIONuma{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_config_devmodel_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PhyIONuma) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PhyIONuma) ProtoMessage() {}
+
+func (x *PhyIONuma) ProtoReflect() protoreflect.Message {
+	mi := &file_config_devmodel_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PhyIONuma.ProtoReflect.Descriptor instead.
+func (*PhyIONuma) Descriptor() ([]byte, []int) {
+	return file_config_devmodel_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PhyIONuma) GetNode() uint32 {
+	if x != nil {
+		return x.Node
+	}
+	return 0
+}
+
 var File_config_devmodel_proto protoreflect.FileDescriptor
 
 var file_config_devmodel_proto_rawDesc = []byte{
@@ -500,7 +592,7 @@ var file_config_devmodel_proto_rawDesc = []byte{
 	0x76, 0x6c, 0x61, 0x6e, 0x49, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x76, 0x6c,
 	0x61, 0x6e, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x6f, 0x6e, 0x64, 0x67, 0x72, 0x6f, 0x75,
 	0x70, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x62, 0x6f, 0x6e, 0x64, 0x67, 0x72, 0x6f,
-	0x75, 0x70, 0x22, 0xe3, 0x01, 0x0a, 0x0d, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x41, 0x64, 0x61,
+	0x75, 0x70, 0x22, 0x9d, 0x02, 0x0a, 0x0d, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x41, 0x64, 0x61,
 	0x70, 0x74, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x72, 0x65, 0x65,
 	0x55, 0x70, 0x6c, 0x69, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x66, 0x72,
@@ -514,53 +606,66 @@ var file_config_devmodel_proto_rawDesc = []byte{
 	0x6c, 0x6f, 0x77, 0x65, 0x72, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x08,
 	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x6c, 0x6f, 0x77, 0x65, 0x72, 0x4c, 0x61, 0x79, 0x65, 0x72,
 	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x18, 0x09, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x22, 0x32, 0x0a, 0x10, 0x50, 0x68, 0x79, 0x49,
-	0x4f, 0x55, 0x73, 0x61, 0x67, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x1e, 0x0a, 0x0a,
-	0x66, 0x72, 0x65, 0x65, 0x55, 0x70, 0x6c, 0x69, 0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x0a, 0x66, 0x72, 0x65, 0x65, 0x55, 0x70, 0x6c, 0x69, 0x6e, 0x6b, 0x22, 0xb8, 0x04, 0x0a,
-	0x0a, 0x50, 0x68, 0x79, 0x73, 0x69, 0x63, 0x61, 0x6c, 0x49, 0x4f, 0x12, 0x36, 0x0a, 0x05, 0x70,
-	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x20, 0x2e, 0x6f, 0x72, 0x67,
-	0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x6d,
-	0x6f, 0x6e, 0x2e, 0x50, 0x68, 0x79, 0x49, 0x6f, 0x54, 0x79, 0x70, 0x65, 0x52, 0x05, 0x70, 0x74,
-	0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x68, 0x79, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x68, 0x79, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12,
-	0x4b, 0x0a, 0x08, 0x70, 0x68, 0x79, 0x61, 0x64, 0x64, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x2f, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65,
-	0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x50, 0x68, 0x79, 0x73, 0x69, 0x63,
-	0x61, 0x6c, 0x49, 0x4f, 0x2e, 0x50, 0x68, 0x79, 0x61, 0x64, 0x64, 0x72, 0x73, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x52, 0x08, 0x70, 0x68, 0x79, 0x61, 0x64, 0x64, 0x72, 0x73, 0x12, 0x22, 0x0a, 0x0c,
-	0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0c, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x6c, 0x61, 0x62, 0x65, 0x6c,
-	0x12, 0x1c, 0x0a, 0x09, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x67, 0x72, 0x70, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x67, 0x72, 0x70, 0x12, 0x3d,
-	0x0a, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x27, 0x2e,
-	0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63,
-	0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x68, 0x79, 0x49, 0x6f, 0x4d, 0x65, 0x6d, 0x62, 0x65,
-	0x72, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x12, 0x49, 0x0a,
-	0x0b, 0x75, 0x73, 0x61, 0x67, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x07, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x27, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e,
-	0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x50, 0x68, 0x79, 0x49, 0x4f,
-	0x55, 0x73, 0x61, 0x67, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0b, 0x75, 0x73, 0x61,
-	0x67, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x45, 0x0a, 0x06, 0x63, 0x62, 0x61, 0x74,
-	0x74, 0x72, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c,
-	0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x2e, 0x50, 0x68, 0x79, 0x73, 0x69, 0x63, 0x61, 0x6c, 0x49, 0x4f, 0x2e, 0x43, 0x62, 0x61, 0x74,
-	0x74, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x63, 0x62, 0x61, 0x74, 0x74, 0x72, 0x1a,
-	0x3b, 0x0a, 0x0d, 0x50, 0x68, 0x79, 0x61, 0x64, 0x64, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
-	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x39, 0x0a, 0x0b,
-	0x43, 0x62, 0x61, 0x74, 0x74, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
-	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x2a, 0x2f, 0x0a, 0x0d, 0x73, 0x57, 0x41, 0x64, 0x61,
-	0x70, 0x74, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x49, 0x47, 0x4e, 0x4f,
-	0x52, 0x45, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x56, 0x4c, 0x41, 0x4e, 0x10, 0x01, 0x12, 0x08,
-	0x0a, 0x04, 0x42, 0x4f, 0x4e, 0x44, 0x10, 0x02, 0x42, 0x3d, 0x0a, 0x15, 0x6f, 0x72, 0x67, 0x2e,
-	0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x5a, 0x24, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x66,
-	0x2d, 0x65, 0x64, 0x67, 0x65, 0x2f, 0x65, 0x76, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x67, 0x6f,
-	0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x28, 0x0d, 0x52, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x68, 0x61, 0x72,
+	0x65, 0x64, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c,
+	0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x61, 0x64, 0x64, 0x72, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x61, 0x64, 0x64,
+	0x72, 0x73, 0x22, 0x32, 0x0a, 0x10, 0x50, 0x68, 0x79, 0x49, 0x4f, 0x55, 0x73, 0x61, 0x67, 0x65,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x72, 0x65, 0x65, 0x55, 0x70,
+	0x6c, 0x69, 0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x66, 0x72, 0x65, 0x65,
+	0x55, 0x70, 0x6c, 0x69, 0x6e, 0x6b, 0x22, 0xa2, 0x05, 0x0a, 0x0a, 0x50, 0x68, 0x79, 0x73, 0x69,
+	0x63, 0x61, 0x6c, 0x49, 0x4f, 0x12, 0x36, 0x0a, 0x05, 0x70, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x20, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67,
+	0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x50, 0x68, 0x79,
+	0x49, 0x6f, 0x54, 0x79, 0x70, 0x65, 0x52, 0x05, 0x70, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x70, 0x68, 0x79, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x70, 0x68, 0x79, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x4b, 0x0a, 0x08, 0x70, 0x68, 0x79,
+	0x61, 0x64, 0x64, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x6f, 0x72,
+	0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x2e, 0x50, 0x68, 0x79, 0x73, 0x69, 0x63, 0x61, 0x6c, 0x49, 0x4f, 0x2e, 0x50,
+	0x68, 0x79, 0x61, 0x64, 0x64, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x70, 0x68,
+	0x79, 0x61, 0x64, 0x64, 0x72, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61,
+	0x6c, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6c, 0x6f,
+	0x67, 0x69, 0x63, 0x61, 0x6c, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x73,
+	0x73, 0x69, 0x67, 0x6e, 0x67, 0x72, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61,
+	0x73, 0x73, 0x69, 0x67, 0x6e, 0x67, 0x72, 0x70, 0x12, 0x3d, 0x0a, 0x05, 0x75, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x27, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66,
+	0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e,
+	0x50, 0x68, 0x79, 0x49, 0x6f, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x55, 0x73, 0x61, 0x67, 0x65,
+	0x52, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x12, 0x49, 0x0a, 0x0b, 0x75, 0x73, 0x61, 0x67, 0x65,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x6f,
+	0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x50, 0x68, 0x79, 0x49, 0x4f, 0x55, 0x73, 0x61, 0x67, 0x65, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0b, 0x75, 0x73, 0x61, 0x67, 0x65, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x12, 0x45, 0x0a, 0x06, 0x63, 0x62, 0x61, 0x74, 0x74, 0x72, 0x18, 0x08, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e,
+	0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x50, 0x68, 0x79, 0x73, 0x69,
+	0x63, 0x61, 0x6c, 0x49, 0x4f, 0x2e, 0x43, 0x62, 0x61, 0x74, 0x74, 0x72, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x06, 0x63, 0x62, 0x61, 0x74, 0x74, 0x72, 0x12, 0x32, 0x0a, 0x15, 0x61, 0x63, 0x63,
+	0x65, 0x6c, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f,
+	0x6d, 0x62, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x13, 0x61, 0x63, 0x63, 0x65, 0x6c, 0x65,
+	0x72, 0x61, 0x74, 0x6f, 0x72, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4d, 0x62, 0x12, 0x34, 0x0a,
+	0x04, 0x6e, 0x75, 0x6d, 0x61, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x6f, 0x72,
+	0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x2e, 0x50, 0x68, 0x79, 0x49, 0x4f, 0x4e, 0x75, 0x6d, 0x61, 0x52, 0x04, 0x6e,
+	0x75, 0x6d, 0x61, 0x1a, 0x3b, 0x0a, 0x0d, 0x50, 0x68, 0x79, 0x61, 0x64, 0x64, 0x72, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x1a, 0x39, 0x0a, 0x0b, 0x43, 0x62, 0x61, 0x74, 0x74, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x1f, 0x0a, 0x09, 0x50,
+	0x68, 0x79, 0x49, 0x4f, 0x4e, 0x75, 0x6d, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x2a, 0x2f, 0x0a, 0x0d,
+	0x73, 0x57, 0x41, 0x64, 0x61, 0x70, 0x74, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a,
+	0x06, 0x49, 0x47, 0x4e, 0x4f, 0x52, 0x45, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x56, 0x4c, 0x41,
+	0x4e, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x42, 0x4f, 0x4e, 0x44, 0x10, 0x02, 0x42, 0x3d, 0x0a,
+	0x15, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5a, 0x24, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x6c, 0x66, 0x2d, 0x65, 0x64, 0x67, 0x65, 0x2f, 0x65, 0x76, 0x65, 0x2f, 0x61,
+	0x70, 0x69, 0x2f, 0x67, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -576,30 +681,32 @@ func file_config_devmodel_proto_rawDescGZIP() []byte {
 }
 
 var file_config_devmodel_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_config_devmodel_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_config_devmodel_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_config_devmodel_proto_goTypes = []interface{}{
 	(SWAdapterType)(0),              // 0: org.lfedge.eve.config.sWAdapterType
 	(*SWAdapterParams)(nil),         // 1: org.lfedge.eve.config.sWAdapterParams
 	(*SystemAdapter)(nil),           // 2: org.lfedge.eve.config.SystemAdapter
 	(*PhyIOUsagePolicy)(nil),        // 3: org.lfedge.eve.config.PhyIOUsagePolicy
 	(*PhysicalIO)(nil),              // 4: org.lfedge.eve.config.PhysicalIO
-	nil,                             // 5: org.lfedge.eve.config.PhysicalIO.PhyaddrsEntry
-	nil,                             // 6: org.lfedge.eve.config.PhysicalIO.CbattrEntry
-	(evecommon.PhyIoType)(0),        // 7: org.lfedge.eve.common.PhyIoType
-	(evecommon.PhyIoMemberUsage)(0), // 8: org.lfedge.eve.common.PhyIoMemberUsage
+	(*PhyIONuma)(nil),               // 5: org.lfedge.eve.config.PhyIONuma
+	nil,                             // 6: org.lfedge.eve.config.PhysicalIO.PhyaddrsEntry
+	nil,                             // 7: org.lfedge.eve.config.PhysicalIO.CbattrEntry
+	(evecommon.PhyIoType)(0),        // 8: org.lfedge.eve.common.PhyIoType
+	(evecommon.PhyIoMemberUsage)(0), // 9: org.lfedge.eve.common.PhyIoMemberUsage
 }
 var file_config_devmodel_proto_depIdxs = []int32{
 	0, // 0: org.lfedge.eve.config.sWAdapterParams.aType:type_name -> org.lfedge.eve.config.sWAdapterType
-	7, // 1: org.lfedge.eve.config.PhysicalIO.ptype:type_name -> org.lfedge.eve.common.PhyIoType
-	5, // 2: org.lfedge.eve.config.PhysicalIO.phyaddrs:type_name -> org.lfedge.eve.config.PhysicalIO.PhyaddrsEntry
-	8, // 3: org.lfedge.eve.config.PhysicalIO.usage:type_name -> org.lfedge.eve.common.PhyIoMemberUsage
+	8, // 1: org.lfedge.eve.config.PhysicalIO.ptype:type_name -> org.lfedge.eve.common.PhyIoType
+	6, // 2: org.lfedge.eve.config.PhysicalIO.phyaddrs:type_name -> org.lfedge.eve.config.PhysicalIO.PhyaddrsEntry
+	9, // 3: org.lfedge.eve.config.PhysicalIO.usage:type_name -> org.lfedge.eve.common.PhyIoMemberUsage
 	3, // 4: org.lfedge.eve.config.PhysicalIO.usagePolicy:type_name -> org.lfedge.eve.config.PhyIOUsagePolicy
-	6, // 5: org.lfedge.eve.config.PhysicalIO.cbattr:type_name -> org.lfedge.eve.config.PhysicalIO.CbattrEntry
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	7, // 5: org.lfedge.eve.config.PhysicalIO.cbattr:type_name -> org.lfedge.eve.config.PhysicalIO.CbattrEntry
+	5, // 6: org.lfedge.eve.config.PhysicalIO.numa:type_name -> org.lfedge.eve.config.PhyIONuma
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_config_devmodel_proto_init() }
@@ -656,6 +763,18 @@ func file_config_devmodel_proto_init() {
 				return nil
 			}
 		}
+		file_config_devmodel_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PhyIONuma); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -663,7 +782,7 @@ func file_config_devmodel_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_config_devmodel_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   6,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -142,6 +142,10 @@ type ACEAction struct {
 	// port map action, and its associated parameter
 	Portmap bool   `protobuf:"varint,6,opt,name=portmap,proto3" json:"portmap,omitempty"`
 	AppPort uint32 `protobuf:"varint,7,opt,name=appPort,proto3" json:"appPort,omitempty"`
+	// dscp mark action, and its associated parameter, 0-63. Overrides
+	// the dscp mark of the network instance.
+	DscpMark bool   `protobuf:"varint,8,opt,name=dscpMark,proto3" json:"dscpMark,omitempty"`
+	Dscp     uint32 `protobuf:"varint,9,opt,name=dscp,proto3" json:"dscp,omitempty"`
 }
 
 func (x *ACEAction) Reset() {
@@ -225,6 +229,20 @@ func (x *ACEAction) GetAppPort() uint32 {
 	return 0
 }
 
+func (x *ACEAction) GetDscpMark() bool {
+	if x != nil {
+		return x.DscpMark
+	}
+	return false
+}
+
+func (x *ACEAction) GetDscp() uint32 {
+	if x != nil {
+		return x.Dscp
+	}
+	return 0
+}
+
 type ACE struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -318,7 +336,7 @@ var file_config_fw_proto_rawDesc = []byte{
 	0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x34, 0x0a, 0x08, 0x41, 0x43, 0x45, 0x4d,
 	0x61, 0x74, 0x63, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xc5,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xf5,
 	0x01, 0x0a, 0x09, 0x41, 0x43, 0x45, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04,
 	0x64, 0x72, 0x6f, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x64, 0x72, 0x6f, 0x70,
 	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
@@ -331,7 +349,10 @@ var file_config_fw_proto_rawDesc = []byte{
 	0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x6f, 0x72, 0x74, 0x6d, 0x61, 0x70, 0x18, 0x06, 0x20,
 	0x01, 0x28, 0x08, 0x52, 0x07, 0x70, 0x6f, 0x72, 0x74, 0x6d, 0x61, 0x70, 0x12, 0x18, 0x0a, 0x07,
 	0x61, 0x70, 0x70, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x61,
-	0x70, 0x70, 0x50, 0x6f, 0x72, 0x74, 0x22, 0xd7, 0x01, 0x0a, 0x03, 0x41, 0x43, 0x45, 0x12, 0x39,
+	0x70, 0x70, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x73, 0x63, 0x70, 0x4d, 0x61,
+	0x72, 0x6b, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x64, 0x73, 0x63, 0x70, 0x4d, 0x61,
+	0x72, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x73, 0x63, 0x70, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x04, 0x64, 0x73, 0x63, 0x70, 0x22, 0xd7, 0x01, 0x0a, 0x03, 0x41, 0x43, 0x45, 0x12, 0x39,
 	0x0a, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
 	0x1f, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65,
 	0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x41, 0x43, 0x45, 0x4d, 0x61, 0x74, 0x63, 0x68,
@@ -78,6 +78,69 @@ func (ProxyProto) EnumDescriptor() ([]byte, []int) {
 	return file_config_netcmn_proto_rawDescGZIP(), []int{0}
 }
 
+// proxyPolicy is the order in which the proxies of a network are used
+type ProxyPolicy int32
+
+const (
+	// use the PAC file if any, otherwise WPAD if enabled, otherwise
+	// the explicit proxies
+	ProxyPolicy_PROXY_POLICY_UNSPECIFIED ProxyPolicy = 0
+	// only the explicit proxies
+	ProxyPolicy_PROXY_POLICY_STATIC_ONLY ProxyPolicy = 1
+	// only the PAC file or the PAC file downloaded from networkProxyURL
+	ProxyPolicy_PROXY_POLICY_PAC_ONLY ProxyPolicy = 2
+	// the PAC file, and the explicit proxies if the PAC file fails
+	ProxyPolicy_PROXY_POLICY_PAC_THEN_STATIC ProxyPolicy = 3
+	// the PAC file discovered by WPAD from networkProxyURL or the
+	// DNS domain of the network
+	ProxyPolicy_PROXY_POLICY_WPAD ProxyPolicy = 4
+)
+
+// Enum value maps for ProxyPolicy.
+var (
+	ProxyPolicy_name = map[int32]string{
+		0: "PROXY_POLICY_UNSPECIFIED",
+		1: "PROXY_POLICY_STATIC_ONLY",
+		2: "PROXY_POLICY_PAC_ONLY",
+		3: "PROXY_POLICY_PAC_THEN_STATIC",
+		4: "PROXY_POLICY_WPAD",
+	}
+	ProxyPolicy_value = map[string]int32{
+		"PROXY_POLICY_UNSPECIFIED":     0,
+		"PROXY_POLICY_STATIC_ONLY":     1,
+		"PROXY_POLICY_PAC_ONLY":        2,
+		"PROXY_POLICY_PAC_THEN_STATIC": 3,
+		"PROXY_POLICY_WPAD":            4,
+	}
+)
+
+func (x ProxyPolicy) Enum() *ProxyPolicy {
+	p := new(ProxyPolicy)
+	*p = x
+	return p
+}
+
+func (x ProxyPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProxyPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_config_netcmn_proto_enumTypes[1].Descriptor()
+}
+
+func (ProxyPolicy) Type() protoreflect.EnumType {
+	return &file_config_netcmn_proto_enumTypes[1]
+}
+
+func (x ProxyPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProxyPolicy.Descriptor instead.
+func (ProxyPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_config_netcmn_proto_rawDescGZIP(), []int{1}
+}
+
 type DHCPType int32
 
 const (
@@ -117,11 +180,11 @@ func (x DHCPType) String() string {
 }
 
 func (DHCPType) Descriptor() protoreflect.EnumDescriptor {
-	return file_config_netcmn_proto_enumTypes[1].Descriptor()
+	return file_config_netcmn_proto_enumTypes[2].Descriptor()
 }
 
 func (DHCPType) Type() protoreflect.EnumType {
-	return &file_config_netcmn_proto_enumTypes[1]
+	return &file_config_netcmn_proto_enumTypes[2]
 }
 
 func (x DHCPType) Number() protoreflect.EnumNumber {
@@ -130,7 +193,64 @@ func (x DHCPType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use DHCPType.Descriptor instead.
 func (DHCPType) EnumDescriptor() ([]byte, []int) {
-	return file_config_netcmn_proto_rawDescGZIP(), []int{1}
+	return file_config_netcmn_proto_rawDescGZIP(), []int{2}
+}
+
+// How a port gets its IPv6 address when the DHCPType is Client
+type IPv6AddrMode int32
+
+const (
+	// Follow the router advertisements
+	IPv6AddrMode_IPV6_ADDR_MODE_UNSPECIFIED IPv6AddrMode = 0
+	// Stateless address autoconfiguration only
+	IPv6AddrMode_IPV6_ADDR_MODE_SLAAC IPv6AddrMode = 1
+	// Address and other configuration from DHCPv6
+	IPv6AddrMode_IPV6_ADDR_MODE_DHCPV6_STATEFUL IPv6AddrMode = 2
+	// Address from SLAAC, other configuration such as DNS from DHCPv6
+	IPv6AddrMode_IPV6_ADDR_MODE_DHCPV6_STATELESS IPv6AddrMode = 3
+)
+
+// Enum value maps for IPv6AddrMode.
+var (
+	IPv6AddrMode_name = map[int32]string{
+		0: "IPV6_ADDR_MODE_UNSPECIFIED",
+		1: "IPV6_ADDR_MODE_SLAAC",
+		2: "IPV6_ADDR_MODE_DHCPV6_STATEFUL",
+		3: "IPV6_ADDR_MODE_DHCPV6_STATELESS",
+	}
+	IPv6AddrMode_value = map[string]int32{
+		"IPV6_ADDR_MODE_UNSPECIFIED":      0,
+		"IPV6_ADDR_MODE_SLAAC":            1,
+		"IPV6_ADDR_MODE_DHCPV6_STATEFUL":  2,
+		"IPV6_ADDR_MODE_DHCPV6_STATELESS": 3,
+	}
+)
+
+func (x IPv6AddrMode) Enum() *IPv6AddrMode {
+	p := new(IPv6AddrMode)
+	*p = x
+	return p
+}
+
+func (x IPv6AddrMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (IPv6AddrMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_config_netcmn_proto_enumTypes[3].Descriptor()
+}
+
+func (IPv6AddrMode) Type() protoreflect.EnumType {
+	return &file_config_netcmn_proto_enumTypes[3]
+}
+
+func (x IPv6AddrMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use IPv6AddrMode.Descriptor instead.
+func (IPv6AddrMode) EnumDescriptor() ([]byte, []int) {
+	return file_config_netcmn_proto_rawDescGZIP(), []int{3}
 }
 
 type NetworkType int32
@@ -175,11 +295,11 @@ func (x NetworkType) String() string {
 }
 
 func (NetworkType) Descriptor() protoreflect.EnumDescriptor {
-	return file_config_netcmn_proto_enumTypes[2].Descriptor()
+	return file_config_netcmn_proto_enumTypes[4].Descriptor()
 }
 
 func (NetworkType) Type() protoreflect.EnumType {
-	return &file_config_netcmn_proto_enumTypes[2]
+	return &file_config_netcmn_proto_enumTypes[4]
 }
 
 func (x NetworkType) Number() protoreflect.EnumNumber {
@@ -188,7 +308,7 @@ func (x NetworkType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use NetworkType.Descriptor instead.
 func (NetworkType) EnumDescriptor() ([]byte, []int) {
-	return file_config_netcmn_proto_rawDescGZIP(), []int{2}
+	return file_config_netcmn_proto_rawDescGZIP(), []int{4}
 }
 
 type WirelessType int32
@@ -224,11 +344,11 @@ func (x WirelessType) String() string {
 }
 
 func (WirelessType) Descriptor() protoreflect.EnumDescriptor {
-	return file_config_netcmn_proto_enumTypes[3].Descriptor()
+	return file_config_netcmn_proto_enumTypes[5].Descriptor()
 }
 
 func (WirelessType) Type() protoreflect.EnumType {
-	return &file_config_netcmn_proto_enumTypes[3]
+	return &file_config_netcmn_proto_enumTypes[5]
 }
 
 func (x WirelessType) Number() protoreflect.EnumNumber {
@@ -237,7 +357,7 @@ func (x WirelessType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use WirelessType.Descriptor instead.
 func (WirelessType) EnumDescriptor() ([]byte, []int) {
-	return file_config_netcmn_proto_rawDescGZIP(), []int{3}
+	return file_config_netcmn_proto_rawDescGZIP(), []int{5}
 }
 
 type WiFiKeyScheme int32
@@ -246,6 +366,7 @@ const (
 	WiFiKeyScheme_SchemeNOOP WiFiKeyScheme = 0
 	WiFiKeyScheme_WPAPSK     WiFiKeyScheme = 1 // WPA-PSK
 	WiFiKeyScheme_WPAEAP     WiFiKeyScheme = 2 // WPA-EAP or WPA2 Enterprise
+	WiFiKeyScheme_WPA3SAE    WiFiKeyScheme = 3 // WPA3-SAE or WPA3 Personal
 )
 
 // Enum value maps for WiFiKeyScheme.
@@ -254,11 +375,13 @@ var (
 		0: "SchemeNOOP",
 		1: "WPAPSK",
 		2: "WPAEAP",
+		3: "WPA3SAE",
 	}
 	WiFiKeyScheme_value = map[string]int32{
 		"SchemeNOOP": 0,
 		"WPAPSK":     1,
 		"WPAEAP":     2,
+		"WPA3SAE":    3,
 	}
 )
 
@@ -273,11 +396,11 @@ func (x WiFiKeyScheme) String() string {
 }
 
 func (WiFiKeyScheme) Descriptor() protoreflect.EnumDescriptor {
-	return file_config_netcmn_proto_enumTypes[4].Descriptor()
+	return file_config_netcmn_proto_enumTypes[6].Descriptor()
 }
 
 func (WiFiKeyScheme) Type() protoreflect.EnumType {
-	return &file_config_netcmn_proto_enumTypes[4]
+	return &file_config_netcmn_proto_enumTypes[6]
 }
 
 func (x WiFiKeyScheme) Number() protoreflect.EnumNumber {
@@ -286,7 +409,7 @@ func (x WiFiKeyScheme) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use WiFiKeyScheme.Descriptor instead.
 func (WiFiKeyScheme) EnumDescriptor() ([]byte, []int) {
-	return file_config_netcmn_proto_rawDescGZIP(), []int{4}
+	return file_config_netcmn_proto_rawDescGZIP(), []int{6}
 }
 
 type IpRange struct {
@@ -352,6 +475,12 @@ type ProxyServer struct {
 	Proto  ProxyProto `protobuf:"varint,1,opt,name=proto,proto3,enum=org.lfedge.eve.config.ProxyProto" json:"proto,omitempty"`
 	Server string     `protobuf:"bytes,2,opt,name=server,proto3" json:"server,omitempty"`
 	Port   uint32     `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	// username for basic authentication with the proxy, which is sent as
+	// the user information in the proxy URL
+	Username string `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	// contains the password for the username as proxyPassword in the
+	// EncryptionBlock; required if the username is set
+	CipherData *CipherBlock `protobuf:"bytes,5,opt,name=cipherData,proto3" json:"cipherData,omitempty"`
 }
 
 func (x *ProxyServer) Reset() {
@@ -407,6 +536,20 @@ func (x *ProxyServer) GetPort() uint32 {
 	return 0
 }
 
+func (x *ProxyServer) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ProxyServer) GetCipherData() *CipherBlock {
+	if x != nil {
+		return x.CipherData
+	}
+	return nil
+}
+
 type ProxyConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -427,6 +570,8 @@ type ProxyConfig struct {
 	// this may be needed either in explicit (has ProxyServer items), automatic
 	// (networkProxyEnable) or transparent (network layer not aware of proxy)
 	ProxyCertPEM [][]byte `protobuf:"bytes,6,rep,name=proxyCertPEM,proto3" json:"proxyCertPEM,omitempty"`
+	// which of the above are used and in which order
+	Policy ProxyPolicy `protobuf:"varint,7,opt,name=policy,proto3,enum=org.lfedge.eve.config.ProxyPolicy" json:"policy,omitempty"`
 }
 
 func (x *ProxyConfig) Reset() {
@@ -503,6 +648,13 @@ func (x *ProxyConfig) GetProxyCertPEM() [][]byte {
 	return nil
 }
 
+func (x *ProxyConfig) GetPolicy() ProxyPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return ProxyPolicy_PROXY_POLICY_UNSPECIFIED
+}
+
 // deprecated use ZnetStaticDNSEntry
 type ZedServer struct {
 	state         protoimpl.MessageState
@@ -631,6 +783,20 @@ type Ipspec struct {
 	// for IPAM management when dhcp is turned on.
 	// If none provided, system will default pool.
 	DhcpRange *IpRange `protobuf:"bytes,9,opt,name=dhcpRange,proto3" json:"dhcpRange,omitempty"`
+	// static routes for the network instance
+	Routes []*IPRoute `protobuf:"bytes,10,rep,name=routes,proto3" json:"routes,omitempty"`
+	// DNS search domains. Appended to the domain above, which can also
+	// hold a comma or space separated list. The first one is the domain name.
+	Domains []string `protobuf:"bytes,11,rep,name=domains,proto3" json:"domains,omitempty"`
+	// Only for IPv6 networks with the dhcp set to Client
+	Ipv6AddrMode IPv6AddrMode `protobuf:"varint,12,opt,name=ipv6AddrMode,proto3,enum=org.lfedge.eve.config.IPv6AddrMode" json:"ipv6AddrMode,omitempty"`
+	// DHCP reservations of addresses in the subnet, outside of the
+	// dhcpRange, for the app interfaces with the MAC addresses
+	Reservations []*DhcpReservation `protobuf:"bytes,13,rep,name=reservations,proto3" json:"reservations,omitempty"`
+	// Only for the networks of system adapters with static addresses:
+	// the subnets of the other IP versions in CIDR format, e.g. the IPv6
+	// subnet of a dual stack network whose subnet above is IPv4
+	ExtraSubnets []string `protobuf:"bytes,14,rep,name=extraSubnets,proto3" json:"extraSubnets,omitempty"`
 }
 
 func (x *Ipspec) Reset() {
@@ -714,90 +880,305 @@ func (x *Ipspec) GetDhcpRange() *IpRange {
 	return nil
 }
 
+func (x *Ipspec) GetRoutes() []*IPRoute {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+func (x *Ipspec) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *Ipspec) GetIpv6AddrMode() IPv6AddrMode {
+	if x != nil {
+		return x.Ipv6AddrMode
+	}
+	return IPv6AddrMode_IPV6_ADDR_MODE_UNSPECIFIED
+}
+
+func (x *Ipspec) GetReservations() []*DhcpReservation {
+	if x != nil {
+		return x.Reservations
+	}
+	return nil
+}
+
+func (x *Ipspec) GetExtraSubnets() []string {
+	if x != nil {
+		return x.ExtraSubnets
+	}
+	return nil
+}
+
+// Reservation of an IP address for a MAC address by the DHCP server
+type DhcpReservation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mac string `protobuf:"bytes,1,opt,name=mac,proto3" json:"mac,omitempty"`
+	Ip  string `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	// Optional host name given to the app
+	Hostname string `protobuf:"bytes,3,opt,name=hostname,proto3" json:"hostname,omitempty"`
+}
+
+func (x *DhcpReservation) Reset() {
+	*x = DhcpReservation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_config_netcmn_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DhcpReservation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DhcpReservation) ProtoMessage() {}
+
+func (x *DhcpReservation) ProtoReflect() protoreflect.Message {
+	mi := &file_config_netcmn_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DhcpReservation.ProtoReflect.Descriptor instead.
+func (*DhcpReservation) Descriptor() ([]byte, []int) {
+	return file_config_netcmn_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DhcpReservation) GetMac() string {
+	if x != nil {
+		return x.Mac
+	}
+	return ""
+}
+
+func (x *DhcpReservation) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *DhcpReservation) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+// Static route to a destination subnet through a gateway
+type IPRoute struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// destination is in CIDR format...x.y.z.l/nn
+	Destination string `protobuf:"bytes,1,opt,name=destination,proto3" json:"destination,omitempty"`
+	// gateway has to be in the subnet of the ipspec
+	Gateway string `protobuf:"bytes,2,opt,name=gateway,proto3" json:"gateway,omitempty"`
+}
+
+func (x *IPRoute) Reset() {
+	*x = IPRoute{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_config_netcmn_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IPRoute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPRoute) ProtoMessage() {}
+
+func (x *IPRoute) ProtoReflect() protoreflect.Message {
+	mi := &file_config_netcmn_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPRoute.ProtoReflect.Descriptor instead.
+func (*IPRoute) Descriptor() ([]byte, []int) {
+	return file_config_netcmn_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *IPRoute) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *IPRoute) GetGateway() string {
+	if x != nil {
+		return x.Gateway
+	}
+	return ""
+}
+
 var File_config_netcmn_proto protoreflect.FileDescriptor
 
 var file_config_netcmn_proto_rawDesc = []byte{
 	0x0a, 0x13, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x6e, 0x65, 0x74, 0x63, 0x6d, 0x6e, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x15, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67,
-	0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x31, 0x0a, 0x07,
-	0x69, 0x70, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a,
-	0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22,
-	0x72, 0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x37,
-	0x0a, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e,
-	0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f,
-	0x52, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65,
-	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12,
-	0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x70,
-	0x6f, 0x72, 0x74, 0x22, 0x83, 0x02, 0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x43, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x12, 0x2e, 0x0a, 0x12, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x72,
-	0x6f, 0x78, 0x79, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x12, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x45, 0x6e, 0x61,
-	0x62, 0x6c, 0x65, 0x12, 0x3c, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x78, 0x69, 0x65, 0x73, 0x18, 0x02,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67,
-	0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x50, 0x72, 0x6f,
-	0x78, 0x79, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x78, 0x69, 0x65,
-	0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x63, 0x65, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x78, 0x63, 0x65, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x63, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6e,
-	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x55, 0x52, 0x4c, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x72, 0x6f,
-	0x78, 0x79, 0x55, 0x52, 0x4c, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x43, 0x65,
-	0x72, 0x74, 0x50, 0x45, 0x4d, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x70, 0x72, 0x6f,
-	0x78, 0x79, 0x43, 0x65, 0x72, 0x74, 0x50, 0x45, 0x4d, 0x22, 0x39, 0x0a, 0x09, 0x5a, 0x65, 0x64,
-	0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x48, 0x6f, 0x73, 0x74, 0x4e, 0x61,
-	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x48, 0x6f, 0x73, 0x74, 0x4e, 0x61,
-	0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x45, 0x49, 0x44, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
-	0x03, 0x45, 0x49, 0x44, 0x22, 0x4a, 0x0a, 0x12, 0x5a, 0x6e, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74,
-	0x69, 0x63, 0x44, 0x4e, 0x53, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x48, 0x6f,
-	0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x48, 0x6f,
-	0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73,
-	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
-	0x22, 0xe9, 0x01, 0x0a, 0x06, 0x69, 0x70, 0x73, 0x70, 0x65, 0x63, 0x12, 0x33, 0x0a, 0x04, 0x64,
-	0x68, 0x63, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x6f, 0x72, 0x67, 0x2e,
-	0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x2e, 0x44, 0x48, 0x43, 0x50, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x64, 0x68, 0x63, 0x70,
-	0x12, 0x16, 0x0a, 0x06, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x61, 0x74, 0x65,
-	0x77, 0x61, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x61, 0x74, 0x65, 0x77,
-	0x61, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x06, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6e, 0x74,
-	0x70, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6e, 0x74, 0x70, 0x12, 0x10, 0x0a, 0x03,
-	0x64, 0x6e, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x64, 0x6e, 0x73, 0x12, 0x3c,
-	0x0a, 0x09, 0x64, 0x68, 0x63, 0x70, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x1e, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65,
-	0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x69, 0x70, 0x52, 0x61, 0x6e, 0x67,
-	0x65, 0x52, 0x09, 0x64, 0x68, 0x63, 0x70, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x2a, 0x5f, 0x0a, 0x0a,
-	0x70, 0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0e, 0x0a, 0x0a, 0x50, 0x52,
-	0x4f, 0x58, 0x59, 0x5f, 0x48, 0x54, 0x54, 0x50, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x52,
-	0x4f, 0x58, 0x59, 0x5f, 0x48, 0x54, 0x54, 0x50, 0x53, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x50,
-	0x52, 0x4f, 0x58, 0x59, 0x5f, 0x53, 0x4f, 0x43, 0x4b, 0x53, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09,
-	0x50, 0x52, 0x4f, 0x58, 0x59, 0x5f, 0x46, 0x54, 0x50, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0b, 0x50,
-	0x52, 0x4f, 0x58, 0x59, 0x5f, 0x4f, 0x54, 0x48, 0x45, 0x52, 0x10, 0xff, 0x01, 0x2a, 0x3e, 0x0a,
-	0x08, 0x44, 0x48, 0x43, 0x50, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x44, 0x48, 0x43,
-	0x50, 0x4e, 0x6f, 0x6f, 0x70, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x69,
-	0x63, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x44, 0x48, 0x43, 0x50, 0x4e, 0x6f, 0x6e, 0x65, 0x10,
-	0x02, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x10, 0x04, 0x2a, 0x5d, 0x0a,
-	0x0b, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x12, 0x13, 0x0a, 0x0f,
-	0x4e, 0x45, 0x54, 0x57, 0x4f, 0x52, 0x4b, 0x54, 0x59, 0x50, 0x45, 0x4e, 0x4f, 0x4f, 0x50, 0x10,
-	0x00, 0x12, 0x06, 0x0a, 0x02, 0x56, 0x34, 0x10, 0x04, 0x12, 0x06, 0x0a, 0x02, 0x56, 0x36, 0x10,
-	0x06, 0x12, 0x0c, 0x0a, 0x08, 0x43, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x56, 0x34, 0x10, 0x18, 0x12,
-	0x0c, 0x0a, 0x08, 0x43, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x56, 0x36, 0x10, 0x1a, 0x12, 0x0d, 0x0a,
-	0x09, 0x43, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x45, 0x49, 0x44, 0x10, 0x0e, 0x2a, 0x34, 0x0a, 0x0c,
-	0x57, 0x69, 0x72, 0x65, 0x6c, 0x65, 0x73, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08,
-	0x54, 0x79, 0x70, 0x65, 0x4e, 0x4f, 0x4f, 0x50, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x57, 0x69,
-	0x46, 0x69, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x43, 0x65, 0x6c, 0x6c, 0x75, 0x6c, 0x61, 0x72,
-	0x10, 0x02, 0x2a, 0x37, 0x0a, 0x0d, 0x57, 0x69, 0x46, 0x69, 0x4b, 0x65, 0x79, 0x53, 0x63, 0x68,
-	0x65, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x0a, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x4e, 0x4f, 0x4f,
-	0x50, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x57, 0x50, 0x41, 0x50, 0x53, 0x4b, 0x10, 0x01, 0x12,
-	0x0a, 0x0a, 0x06, 0x57, 0x50, 0x41, 0x45, 0x41, 0x50, 0x10, 0x02, 0x42, 0x3d, 0x0a, 0x15, 0x6f,
-	0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x5a, 0x24, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2f, 0x6c, 0x66, 0x2d, 0x65, 0x64, 0x67, 0x65, 0x2f, 0x65, 0x76, 0x65, 0x2f, 0x61, 0x70, 0x69,
-	0x2f, 0x67, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x1a, 0x18, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x2f, 0x61, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x69, 0x6e, 0x66, 0x6f,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x31, 0x0a, 0x07, 0x69, 0x70, 0x52, 0x61, 0x6e, 0x67,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0xd2, 0x01, 0x0a, 0x0b, 0x50, 0x72,
+	0x6f, 0x78, 0x79, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x37, 0x0a, 0x05, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c,
+	0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x2e, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x05, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f,
+	0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x42, 0x0a, 0x0a, 0x63, 0x69,
+	0x70, 0x68, 0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22,
+	0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x52, 0x0a, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x22, 0xbf,
+	0x02, 0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x2e,
+	0x0a, 0x12, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x45, 0x6e,
+	0x61, 0x62, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x6e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x3c,
+	0x0a, 0x07, 0x70, 0x72, 0x6f, 0x78, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x22, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65,
+	0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x53, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x78, 0x69, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a,
+	0x65, 0x78, 0x63, 0x65, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x65, 0x78, 0x63, 0x65, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x70, 0x61, 0x63, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70,
+	0x61, 0x63, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x55, 0x52, 0x4c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x55, 0x52, 0x4c,
+	0x12, 0x22, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x43, 0x65, 0x72, 0x74, 0x50, 0x45, 0x4d,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x43, 0x65, 0x72,
+	0x74, 0x50, 0x45, 0x4d, 0x12, 0x3a, 0x0a, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67,
+	0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f,
+	0x78, 0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x22, 0x39, 0x0a, 0x09, 0x5a, 0x65, 0x64, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x1a, 0x0a,
+	0x08, 0x48, 0x6f, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x48, 0x6f, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x45, 0x49, 0x44,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x45, 0x49, 0x44, 0x22, 0x4a, 0x0a, 0x12, 0x5a,
+	0x6e, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x69, 0x63, 0x44, 0x4e, 0x53, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x1a, 0x0a, 0x08, 0x48, 0x6f, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x48, 0x6f, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0xf4, 0x03, 0x0a, 0x06, 0x69, 0x70, 0x73, 0x70,
+	0x65, 0x63, 0x12, 0x33, 0x0a, 0x04, 0x64, 0x68, 0x63, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x1f, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76,
+	0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x44, 0x48, 0x43, 0x50, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x04, 0x64, 0x68, 0x63, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x75, 0x62, 0x6e, 0x65,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69,
+	0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6e, 0x74, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6e, 0x74, 0x70, 0x12, 0x10, 0x0a, 0x03, 0x64, 0x6e, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x03, 0x64, 0x6e, 0x73, 0x12, 0x3c, 0x0a, 0x09, 0x64, 0x68, 0x63, 0x70, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c,
+	0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x2e, 0x69, 0x70, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x09, 0x64, 0x68, 0x63, 0x70, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x12, 0x36, 0x0a, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x18, 0x0a, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65,
+	0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x49, 0x50, 0x52, 0x6f,
+	0x75, 0x74, 0x65, 0x52, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x64,
+	0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x64, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x73, 0x12, 0x47, 0x0a, 0x0c, 0x69, 0x70, 0x76, 0x36, 0x41, 0x64, 0x64,
+	0x72, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x23, 0x2e, 0x6f, 0x72,
+	0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x2e, 0x49, 0x50, 0x76, 0x36, 0x41, 0x64, 0x64, 0x72, 0x4d, 0x6f, 0x64, 0x65,
+	0x52, 0x0c, 0x69, 0x70, 0x76, 0x36, 0x41, 0x64, 0x64, 0x72, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x4a,
+	0x0a, 0x0c, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0d,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67,
+	0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x44, 0x68, 0x63,
+	0x70, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x72, 0x65,
+	0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x78,
+	0x74, 0x72, 0x61, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0c, 0x65, 0x78, 0x74, 0x72, 0x61, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73, 0x22, 0x4f,
+	0x0a, 0x0f, 0x44, 0x68, 0x63, 0x70, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6d, 0x61, 0x63, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x69, 0x70, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x22,
+	0x45, 0x0a, 0x07, 0x49, 0x50, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65,
+	0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07,
+	0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67,
+	0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2a, 0x5f, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0e, 0x0a, 0x0a, 0x50, 0x52, 0x4f, 0x58, 0x59, 0x5f, 0x48, 0x54,
+	0x54, 0x50, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x52, 0x4f, 0x58, 0x59, 0x5f, 0x48, 0x54,
+	0x54, 0x50, 0x53, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x52, 0x4f, 0x58, 0x59, 0x5f, 0x53,
+	0x4f, 0x43, 0x4b, 0x53, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x50, 0x52, 0x4f, 0x58, 0x59, 0x5f,
+	0x46, 0x54, 0x50, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0b, 0x50, 0x52, 0x4f, 0x58, 0x59, 0x5f, 0x4f,
+	0x54, 0x48, 0x45, 0x52, 0x10, 0xff, 0x01, 0x2a, 0x9d, 0x01, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x78,
+	0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x1c, 0x0a, 0x18, 0x50, 0x52, 0x4f, 0x58, 0x59,
+	0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
+	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x50, 0x52, 0x4f, 0x58, 0x59, 0x5f, 0x50,
+	0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x49, 0x43, 0x5f, 0x4f, 0x4e, 0x4c,
+	0x59, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x50, 0x52, 0x4f, 0x58, 0x59, 0x5f, 0x50, 0x4f, 0x4c,
+	0x49, 0x43, 0x59, 0x5f, 0x50, 0x41, 0x43, 0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x10, 0x02, 0x12, 0x20,
+	0x0a, 0x1c, 0x50, 0x52, 0x4f, 0x58, 0x59, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59, 0x5f, 0x50,
+	0x41, 0x43, 0x5f, 0x54, 0x48, 0x45, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x49, 0x43, 0x10, 0x03,
+	0x12, 0x15, 0x0a, 0x11, 0x50, 0x52, 0x4f, 0x58, 0x59, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59,
+	0x5f, 0x57, 0x50, 0x41, 0x44, 0x10, 0x04, 0x2a, 0x3e, 0x0a, 0x08, 0x44, 0x48, 0x43, 0x50, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x44, 0x48, 0x43, 0x50, 0x4e, 0x6f, 0x6f, 0x70, 0x10,
+	0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x69, 0x63, 0x10, 0x01, 0x12, 0x0c, 0x0a,
+	0x08, 0x44, 0x48, 0x43, 0x50, 0x4e, 0x6f, 0x6e, 0x65, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x43,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x10, 0x04, 0x2a, 0x91, 0x01, 0x0a, 0x0c, 0x49, 0x50, 0x76, 0x36,
+	0x41, 0x64, 0x64, 0x72, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x1e, 0x0a, 0x1a, 0x49, 0x50, 0x56, 0x36,
+	0x5f, 0x41, 0x44, 0x44, 0x52, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x49, 0x50, 0x56, 0x36,
+	0x5f, 0x41, 0x44, 0x44, 0x52, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x4c, 0x41, 0x41, 0x43,
+	0x10, 0x01, 0x12, 0x22, 0x0a, 0x1e, 0x49, 0x50, 0x56, 0x36, 0x5f, 0x41, 0x44, 0x44, 0x52, 0x5f,
+	0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x44, 0x48, 0x43, 0x50, 0x56, 0x36, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x45, 0x46, 0x55, 0x4c, 0x10, 0x02, 0x12, 0x23, 0x0a, 0x1f, 0x49, 0x50, 0x56, 0x36, 0x5f, 0x41,
+	0x44, 0x44, 0x52, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x44, 0x48, 0x43, 0x50, 0x56, 0x36, 0x5f,
+	0x53, 0x54, 0x41, 0x54, 0x45, 0x4c, 0x45, 0x53, 0x53, 0x10, 0x03, 0x2a, 0x5d, 0x0a, 0x0b, 0x4e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x12, 0x13, 0x0a, 0x0f, 0x4e, 0x45,
+	0x54, 0x57, 0x4f, 0x52, 0x4b, 0x54, 0x59, 0x50, 0x45, 0x4e, 0x4f, 0x4f, 0x50, 0x10, 0x00, 0x12,
+	0x06, 0x0a, 0x02, 0x56, 0x34, 0x10, 0x04, 0x12, 0x06, 0x0a, 0x02, 0x56, 0x36, 0x10, 0x06, 0x12,
+	0x0c, 0x0a, 0x08, 0x43, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x56, 0x34, 0x10, 0x18, 0x12, 0x0c, 0x0a,
+	0x08, 0x43, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x56, 0x36, 0x10, 0x1a, 0x12, 0x0d, 0x0a, 0x09, 0x43,
+	0x72, 0x79, 0x70, 0x74, 0x6f, 0x45, 0x49, 0x44, 0x10, 0x0e, 0x2a, 0x34, 0x0a, 0x0c, 0x57, 0x69,
+	0x72, 0x65, 0x6c, 0x65, 0x73, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x79,
+	0x70, 0x65, 0x4e, 0x4f, 0x4f, 0x50, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x57, 0x69, 0x46, 0x69,
+	0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x43, 0x65, 0x6c, 0x6c, 0x75, 0x6c, 0x61, 0x72, 0x10, 0x02,
+	0x2a, 0x44, 0x0a, 0x0d, 0x57, 0x69, 0x46, 0x69, 0x4b, 0x65, 0x79, 0x53, 0x63, 0x68, 0x65, 0x6d,
+	0x65, 0x12, 0x0e, 0x0a, 0x0a, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x4e, 0x4f, 0x4f, 0x50, 0x10,
+	0x00, 0x12, 0x0a, 0x0a, 0x06, 0x57, 0x50, 0x41, 0x50, 0x53, 0x4b, 0x10, 0x01, 0x12, 0x0a, 0x0a,
+	0x06, 0x57, 0x50, 0x41, 0x45, 0x41, 0x50, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x57, 0x50, 0x41,
+	0x33, 0x53, 0x41, 0x45, 0x10, 0x03, 0x42, 0x3d, 0x0a, 0x15, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66,
+	0x65, 0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5a,
+	0x24, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x66, 0x2d, 0x65,
+	0x64, 0x67, 0x65, 0x2f, 0x65, 0x76, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x67, 0x6f, 0x2f, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -812,31 +1193,41 @@ func file_config_netcmn_proto_rawDescGZIP() []byte {
 	return file_config_netcmn_proto_rawDescData
 }
 
-var file_config_netcmn_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
-var file_config_netcmn_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_config_netcmn_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_config_netcmn_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_config_netcmn_proto_goTypes = []interface{}{
 	(ProxyProto)(0),            // 0: org.lfedge.eve.config.proxyProto
-	(DHCPType)(0),              // 1: org.lfedge.eve.config.DHCPType
-	(NetworkType)(0),           // 2: org.lfedge.eve.config.NetworkType
-	(WirelessType)(0),          // 3: org.lfedge.eve.config.WirelessType
-	(WiFiKeyScheme)(0),         // 4: org.lfedge.eve.config.WiFiKeyScheme
-	(*IpRange)(nil),            // 5: org.lfedge.eve.config.ipRange
-	(*ProxyServer)(nil),        // 6: org.lfedge.eve.config.ProxyServer
-	(*ProxyConfig)(nil),        // 7: org.lfedge.eve.config.ProxyConfig
-	(*ZedServer)(nil),          // 8: org.lfedge.eve.config.ZedServer
-	(*ZnetStaticDNSEntry)(nil), // 9: org.lfedge.eve.config.ZnetStaticDNSEntry
-	(*Ipspec)(nil),             // 10: org.lfedge.eve.config.ipspec
+	(ProxyPolicy)(0),           // 1: org.lfedge.eve.config.proxyPolicy
+	(DHCPType)(0),              // 2: org.lfedge.eve.config.DHCPType
+	(IPv6AddrMode)(0),          // 3: org.lfedge.eve.config.IPv6AddrMode
+	(NetworkType)(0),           // 4: org.lfedge.eve.config.NetworkType
+	(WirelessType)(0),          // 5: org.lfedge.eve.config.WirelessType
+	(WiFiKeyScheme)(0),         // 6: org.lfedge.eve.config.WiFiKeyScheme
+	(*IpRange)(nil),            // 7: org.lfedge.eve.config.ipRange
+	(*ProxyServer)(nil),        // 8: org.lfedge.eve.config.ProxyServer
+	(*ProxyConfig)(nil),        // 9: org.lfedge.eve.config.ProxyConfig
+	(*ZedServer)(nil),          // 10: org.lfedge.eve.config.ZedServer
+	(*ZnetStaticDNSEntry)(nil), // 11: org.lfedge.eve.config.ZnetStaticDNSEntry
+	(*Ipspec)(nil),             // 12: org.lfedge.eve.config.ipspec
+	(*DhcpReservation)(nil),    // 13: org.lfedge.eve.config.DhcpReservation
+	(*IPRoute)(nil),            // 14: org.lfedge.eve.config.IPRoute
+	(*CipherBlock)(nil),        // 15: org.lfedge.eve.config.CipherBlock
 }
 var file_config_netcmn_proto_depIdxs = []int32{
-	0, // 0: org.lfedge.eve.config.ProxyServer.proto:type_name -> org.lfedge.eve.config.proxyProto
-	6, // 1: org.lfedge.eve.config.ProxyConfig.proxies:type_name -> org.lfedge.eve.config.ProxyServer
-	1, // 2: org.lfedge.eve.config.ipspec.dhcp:type_name -> org.lfedge.eve.config.DHCPType
-	5, // 3: org.lfedge.eve.config.ipspec.dhcpRange:type_name -> org.lfedge.eve.config.ipRange
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	0,  // 0: org.lfedge.eve.config.ProxyServer.proto:type_name -> org.lfedge.eve.config.proxyProto
+	15, // 1: org.lfedge.eve.config.ProxyServer.cipherData:type_name -> org.lfedge.eve.config.CipherBlock
+	8,  // 2: org.lfedge.eve.config.ProxyConfig.proxies:type_name -> org.lfedge.eve.config.ProxyServer
+	1,  // 3: org.lfedge.eve.config.ProxyConfig.policy:type_name -> org.lfedge.eve.config.proxyPolicy
+	2,  // 4: org.lfedge.eve.config.ipspec.dhcp:type_name -> org.lfedge.eve.config.DHCPType
+	7,  // 5: org.lfedge.eve.config.ipspec.dhcpRange:type_name -> org.lfedge.eve.config.ipRange
+	14, // 6: org.lfedge.eve.config.ipspec.routes:type_name -> org.lfedge.eve.config.IPRoute
+	3,  // 7: org.lfedge.eve.config.ipspec.ipv6AddrMode:type_name -> org.lfedge.eve.config.IPv6AddrMode
+	13, // 8: org.lfedge.eve.config.ipspec.reservations:type_name -> org.lfedge.eve.config.DhcpReservation
+	9,  // [9:9] is the sub-list for method output_type
+	9,  // [9:9] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_config_netcmn_proto_init() }
@@ -844,6 +1235,7 @@ func file_config_netcmn_proto_init() {
 	if File_config_netcmn_proto != nil {
 		return
 	}
+	file_config_acipherinfo_proto_init()
 	if !protoimpl.UnsafeEnabled {
 		file_config_netcmn_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*IpRange); i {
@@ -917,14 +1309,38 @@ func file_config_netcmn_proto_init() {
 				return nil
 			}
 		}
+		file_config_netcmn_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DhcpReservation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_config_netcmn_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IPRoute); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_config_netcmn_proto_rawDesc,
-			NumEnums:      5,
-			NumMessages:   6,
+			NumEnums:      7,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -72,6 +72,61 @@ func (HashAlgorithm) EnumDescriptor() ([]byte, []int) {
 	return file_evecommon_evecommon_proto_rawDescGZIP(), []int{0}
 }
 
+// WipeScope - what a wipe of the device removes. The device identity
+// and the network config are kept in all scopes so that the device
+// re-syncs with the controller afterwards.
+type WipeScope int32
+
+const (
+	WipeScope_WIPE_SCOPE_UNSPECIFIED      WipeScope = 0
+	WipeScope_WIPE_SCOPE_APPS             WipeScope = 1 // app instances, volumes and their state
+	WipeScope_WIPE_SCOPE_APPS_AND_LOGS    WipeScope = 2 // the above and the logs
+	WipeScope_WIPE_SCOPE_ALL_BUT_IDENTITY WipeScope = 3 // all of /persist but the identity and network config
+)
+
+// Enum value maps for WipeScope.
+var (
+	WipeScope_name = map[int32]string{
+		0: "WIPE_SCOPE_UNSPECIFIED",
+		1: "WIPE_SCOPE_APPS",
+		2: "WIPE_SCOPE_APPS_AND_LOGS",
+		3: "WIPE_SCOPE_ALL_BUT_IDENTITY",
+	}
+	WipeScope_value = map[string]int32{
+		"WIPE_SCOPE_UNSPECIFIED":      0,
+		"WIPE_SCOPE_APPS":             1,
+		"WIPE_SCOPE_APPS_AND_LOGS":    2,
+		"WIPE_SCOPE_ALL_BUT_IDENTITY": 3,
+	}
+)
+
+func (x WipeScope) Enum() *WipeScope {
+	p := new(WipeScope)
+	*p = x
+	return p
+}
+
+func (x WipeScope) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WipeScope) Descriptor() protoreflect.EnumDescriptor {
+	return file_evecommon_evecommon_proto_enumTypes[1].Descriptor()
+}
+
+func (WipeScope) Type() protoreflect.EnumType {
+	return &file_evecommon_evecommon_proto_enumTypes[1]
+}
+
+func (x WipeScope) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WipeScope.Descriptor instead.
+func (WipeScope) EnumDescriptor() ([]byte, []int) {
+	return file_evecommon_evecommon_proto_rawDescGZIP(), []int{1}
+}
+
 var File_evecommon_evecommon_proto protoreflect.FileDescriptor
 
 var file_evecommon_evecommon_proto_rawDesc = []byte{
@@ -85,12 +140,20 @@ var file_evecommon_evecommon_proto_rawDesc = []byte{
 	0x4d, 0x5f, 0x53, 0x48, 0x41, 0x32, 0x35, 0x36, 0x5f, 0x31, 0x36, 0x42, 0x59, 0x54, 0x45, 0x53,
 	0x10, 0x01, 0x12, 0x21, 0x0a, 0x1d, 0x48, 0x41, 0x53, 0x48, 0x5f, 0x41, 0x4c, 0x47, 0x4f, 0x52,
 	0x49, 0x54, 0x48, 0x4d, 0x5f, 0x53, 0x48, 0x41, 0x32, 0x35, 0x36, 0x5f, 0x33, 0x32, 0x42, 0x59,
-	0x54, 0x45, 0x53, 0x10, 0x02, 0x42, 0x4d, 0x0a, 0x15, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65,
-	0x64, 0x67, 0x65, 0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x42, 0x09,
-	0x45, 0x76, 0x65, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x50, 0x01, 0x5a, 0x27, 0x67, 0x69, 0x74,
-	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x66, 0x2d, 0x65, 0x64, 0x67, 0x65, 0x2f,
-	0x65, 0x76, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x67, 0x6f, 0x2f, 0x65, 0x76, 0x65, 0x63, 0x6f,
-	0x6d, 0x6d, 0x6f, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x54, 0x45, 0x53, 0x10, 0x02, 0x2a, 0x7b, 0x0a, 0x09, 0x57, 0x69, 0x70, 0x65, 0x53, 0x63, 0x6f,
+	0x70, 0x65, 0x12, 0x1a, 0x0a, 0x16, 0x57, 0x49, 0x50, 0x45, 0x5f, 0x53, 0x43, 0x4f, 0x50, 0x45,
+	0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x13,
+	0x0a, 0x0f, 0x57, 0x49, 0x50, 0x45, 0x5f, 0x53, 0x43, 0x4f, 0x50, 0x45, 0x5f, 0x41, 0x50, 0x50,
+	0x53, 0x10, 0x01, 0x12, 0x1c, 0x0a, 0x18, 0x57, 0x49, 0x50, 0x45, 0x5f, 0x53, 0x43, 0x4f, 0x50,
+	0x45, 0x5f, 0x41, 0x50, 0x50, 0x53, 0x5f, 0x41, 0x4e, 0x44, 0x5f, 0x4c, 0x4f, 0x47, 0x53, 0x10,
+	0x02, 0x12, 0x1f, 0x0a, 0x1b, 0x57, 0x49, 0x50, 0x45, 0x5f, 0x53, 0x43, 0x4f, 0x50, 0x45, 0x5f,
+	0x41, 0x4c, 0x4c, 0x5f, 0x42, 0x55, 0x54, 0x5f, 0x49, 0x44, 0x45, 0x4e, 0x54, 0x49, 0x54, 0x59,
+	0x10, 0x03, 0x42, 0x4d, 0x0a, 0x15, 0x6f, 0x72, 0x67, 0x2e, 0x6c, 0x66, 0x65, 0x64, 0x67, 0x65,
+	0x2e, 0x65, 0x76, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x42, 0x09, 0x45, 0x76, 0x65,
+	0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x50, 0x01, 0x5a, 0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x66, 0x2d, 0x65, 0x64, 0x67, 0x65, 0x2f, 0x65, 0x76, 0x65,
+	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x67, 0x6f, 0x2f, 0x65, 0x76, 0x65, 0x63, 0x6f, 0x6d, 0x6d, 0x6f,
+	0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -105,9 +168,10 @@ func file_evecommon_evecommon_proto_rawDescGZIP() []byte {
 	return file_evecommon_evecommon_proto_rawDescData
 }
 
-var file_evecommon_evecommon_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_evecommon_evecommon_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
 var file_evecommon_evecommon_proto_goTypes = []interface{}{
 	(HashAlgorithm)(0), // 0: org.lfedge.eve.common.HashAlgorithm
+	(WipeScope)(0),     // 1: org.lfedge.eve.common.WipeScope
 }
 var file_evecommon_evecommon_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -127,7 +191,7 @@ func file_evecommon_evecommon_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_evecommon_evecommon_proto_rawDesc,
-			NumEnums:      1,
+			NumEnums:      2,
 			NumMessages:   0,
 			NumExtensions: 0,
 			NumServices:   0,
@@ -12,6 +12,7 @@ _sym_db = _symbol_database.Default()
 
 
 from evecommon import devmodelcommon_pb2 as evecommon_dot_devmodelcommon__pb2
+from evecommon import evecommon_pb2 as evecommon_dot_evecommon__pb2
 
 
 DESCRIPTOR = _descriptor.FileDescriptor(
@@ -20,9 +21,9 @@ DESCRIPTOR = _descriptor.FileDescriptor(
   syntax='proto3',
   serialized_options=b'\n\025org.lfedge.eve.configZ$github.com/lf-edge/eve/api/go/config',
   create_key=_descriptor._internal_create_key,
-  serialized_pb=b'\n\x16\x63onfig/devcommon.proto\x12\x15org.lfedge.eve.config\x1a\x1e\x65vecommon/devmodelcommon.proto\"/\n\x0eUUIDandVersion\x12\x0c\n\x04uuid\x18\x01 \x01(\t\x12\x0f\n\x07version\x18\x02 \x01(\t\"F\n\x0c\x44\x65viceOpsCmd\x12\x0f\n\x07\x63ounter\x18\x02 \x01(\r\x12\x14\n\x0c\x64\x65siredState\x18\x03 \x01(\x08\x12\x0f\n\x07opsTime\x18\x04 \x01(\t\"(\n\nConfigItem\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t\"G\n\x07\x41\x64\x61pter\x12.\n\x04type\x18\x01 \x01(\x0e\x32 .org.lfedge.eve.common.PhyIoType\x12\x0c\n\x04name\x18\x02 \x01(\tB=\n\x15org.lfedge.eve.configZ$github.com/lf-edge/eve/api/go/configb\x06proto3'
+  serialized_pb=b'\n\x16\x63onfig/devcommon.proto\x12\x15org.lfedge.eve.config\x1a\x1e\x65vecommon/devmodelcommon.proto\x1a\x19\x65vecommon/evecommon.proto\"/\n\x0eUUIDandVersion\x12\x0c\n\x04uuid\x18\x01 \x01(\t\x12\x0f\n\x07version\x18\x02 \x01(\t\"V\n\x0c\x44\x65viceOpsCmd\x12\x0f\n\x07\x63ounter\x18\x02 \x01(\r\x12\x14\n\x0c\x64\x65siredState\x18\x03 \x01(\x08\x12\x0f\n\x07opsTime\x18\x04 \x01(\t\x12\x0e\n\x06urgent\x18\x05 \x01(\x08\"g\n\x07WipeCmd\x12\x0f\n\x07\x63ounter\x18\x01 \x01(\r\x12/\n\x05scope\x18\x02 \x01(\x0e\x32 .org.lfedge.eve.common.WipeScope\x12\x1a\n\x12\x63onfirmation_token\x18\x03 \x01(\t\"(\n\nConfigItem\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t\"f\n\x07\x41\x64\x61pter\x12.\n\x04type\x18\x01 \x01(\x0e\x32 .org.lfedge.eve.common.PhyIoType\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x1d\n\x15\x61\x63\x63\x65lerator_memory_mb\x18\x03 \x01(\rB=\n\x15org.lfedge.eve.configZ$github.com/lf-edge/eve/api/go/configb\x06proto3'
   ,
-  dependencies=[evecommon_dot_devmodelcommon__pb2.DESCRIPTOR,])
+  dependencies=[evecommon_dot_devmodelcommon__pb2.DESCRIPTOR,evecommon_dot_evecommon__pb2.DESCRIPTOR,])
 
 
 
@@ -61,8 +62,8 @@ _UUIDANDVERSION = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=81,
-  serialized_end=128,
+  serialized_start=108,
+  serialized_end=155,
 )
 
 
@@ -95,6 +96,59 @@ _DEVICEOPSCMD = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='urgent', full_name='org.lfedge.eve.config.DeviceOpsCmd.urgent', index=3,
+      number=5, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=157,
+  serialized_end=243,
+)
+
+
+_WIPECMD = _descriptor.Descriptor(
+  name='WipeCmd',
+  full_name='org.lfedge.eve.config.WipeCmd',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='counter', full_name='org.lfedge.eve.config.WipeCmd.counter', index=0,
+      number=1, type=13, cpp_type=3, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='scope', full_name='org.lfedge.eve.config.WipeCmd.scope', index=1,
+      number=2, type=14, cpp_type=8, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='confirmation_token', full_name='org.lfedge.eve.config.WipeCmd.confirmation_token', index=2,
+      number=3, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
@@ -107,8 +161,8 @@ _DEVICEOPSCMD = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=130,
-  serialized_end=200,
+  serialized_start=245,
+  serialized_end=348,
 )
 
 
@@ -146,8 +200,8 @@ _CONFIGITEM = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=202,
-  serialized_end=242,
+  serialized_start=350,
+  serialized_end=390,
 )
 
 
@@ -173,6 +227,13 @@ _ADAPTER = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='accelerator_memory_mb', full_name='org.lfedge.eve.config.Adapter.accelerator_memory_mb', index=2,
+      number=3, type=13, cpp_type=3, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
@@ -185,13 +246,15 @@ _ADAPTER = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=244,
-  serialized_end=315,
+  serialized_start=392,
+  serialized_end=494,
 )
 
+_WIPECMD.fields_by_name['scope'].enum_type = evecommon_dot_evecommon__pb2._WIPESCOPE
 _ADAPTER.fields_by_name['type'].enum_type = evecommon_dot_devmodelcommon__pb2._PHYIOTYPE
 DESCRIPTOR.message_types_by_name['UUIDandVersion'] = _UUIDANDVERSION
 DESCRIPTOR.message_types_by_name['DeviceOpsCmd'] = _DEVICEOPSCMD
+DESCRIPTOR.message_types_by_name['WipeCmd'] = _WIPECMD
 DESCRIPTOR.message_types_by_name['ConfigItem'] = _CONFIGITEM
 DESCRIPTOR.message_types_by_name['Adapter'] = _ADAPTER
 _sym_db.RegisterFileDescriptor(DESCRIPTOR)
@@ -210,6 +273,13 @@ DeviceOpsCmd = _reflection.GeneratedProtocolMessageType('DeviceOpsCmd', (_messag
   })
 _sym_db.RegisterMessage(DeviceOpsCmd)
 
+WipeCmd = _reflection.GeneratedProtocolMessageType('WipeCmd', (_message.Message,), {
+  'DESCRIPTOR' : _WIPECMD,
+  '__module__' : 'config.devcommon_pb2'
+  # @@protoc_insertion_point(class_scope:org.lfedge.eve.config.WipeCmd)
+  })
+_sym_db.RegisterMessage(WipeCmd)
+
 ConfigItem = _reflection.GeneratedProtocolMessageType('ConfigItem', (_message.Message,), {
   'DESCRIPTOR' : _CONFIGITEM,
   '__module__' : 'config.devcommon_pb2'
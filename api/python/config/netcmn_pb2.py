@@ -12,6 +12,7 @@ from google.protobuf import symbol_database as _symbol_database
 _sym_db = _symbol_database.Default()
 
 
+from config import acipherinfo_pb2 as config_dot_acipherinfo__pb2
 
 
 DESCRIPTOR = _descriptor.FileDescriptor(
@@ -20,8 +21,9 @@ DESCRIPTOR = _descriptor.FileDescriptor(
   syntax='proto3',
   serialized_options=b'\n\025org.lfedge.eve.configZ$github.com/lf-edge/eve/api/go/config',
   create_key=_descriptor._internal_create_key,
-  serialized_pb=b'\n\x13\x63onfig/netcmn.proto\x12\x15org.lfedge.eve.config\"%\n\x07ipRange\x12\r\n\x05start\x18\x01 \x01(\t\x12\x0b\n\x03\x65nd\x18\x02 \x01(\t\"]\n\x0bProxyServer\x12\x30\n\x05proto\x18\x01 \x01(\x0e\x32!.org.lfedge.eve.config.proxyProto\x12\x0e\n\x06server\x18\x02 \x01(\t\x12\x0c\n\x04port\x18\x03 \x01(\r\"\xb2\x01\n\x0bProxyConfig\x12\x1a\n\x12networkProxyEnable\x18\x01 \x01(\x08\x12\x33\n\x07proxies\x18\x02 \x03(\x0b\x32\".org.lfedge.eve.config.ProxyServer\x12\x12\n\nexceptions\x18\x03 \x01(\t\x12\x0f\n\x07pacfile\x18\x04 \x01(\t\x12\x17\n\x0fnetworkProxyURL\x18\x05 \x01(\t\x12\x14\n\x0cproxyCertPEM\x18\x06 \x03(\x0c\"*\n\tZedServer\x12\x10\n\x08HostName\x18\x01 \x01(\t\x12\x0b\n\x03\x45ID\x18\x02 \x03(\t\"7\n\x12ZnetStaticDNSEntry\x12\x10\n\x08HostName\x18\x01 \x01(\t\x12\x0f\n\x07\x41\x64\x64ress\x18\x02 \x03(\t\"\xb5\x01\n\x06ipspec\x12-\n\x04\x64hcp\x18\x02 \x01(\x0e\x32\x1f.org.lfedge.eve.config.DHCPType\x12\x0e\n\x06subnet\x18\x03 \x01(\t\x12\x0f\n\x07gateway\x18\x05 \x01(\t\x12\x0e\n\x06\x64omain\x18\x06 \x01(\t\x12\x0b\n\x03ntp\x18\x07 \x01(\t\x12\x0b\n\x03\x64ns\x18\x08 \x03(\t\x12\x31\n\tdhcpRange\x18\t \x01(\x0b\x32\x1e.org.lfedge.eve.config.ipRange*_\n\nproxyProto\x12\x0e\n\nPROXY_HTTP\x10\x00\x12\x0f\n\x0bPROXY_HTTPS\x10\x01\x12\x0f\n\x0bPROXY_SOCKS\x10\x02\x12\r\n\tPROXY_FTP\x10\x03\x12\x10\n\x0bPROXY_OTHER\x10\xff\x01*>\n\x08\x44HCPType\x12\x0c\n\x08\x44HCPNoop\x10\x00\x12\n\n\x06Static\x10\x01\x12\x0c\n\x08\x44HCPNone\x10\x02\x12\n\n\x06\x43lient\x10\x04*]\n\x0bNetworkType\x12\x13\n\x0fNETWORKTYPENOOP\x10\x00\x12\x06\n\x02V4\x10\x04\x12\x06\n\x02V6\x10\x06\x12\x0c\n\x08\x43ryptoV4\x10\x18\x12\x0c\n\x08\x43ryptoV6\x10\x1a\x12\r\n\tCryptoEID\x10\x0e*4\n\x0cWirelessType\x12\x0c\n\x08TypeNOOP\x10\x00\x12\x08\n\x04WiFi\x10\x01\x12\x0c\n\x08\x43\x65llular\x10\x02*7\n\rWiFiKeyScheme\x12\x0e\n\nSchemeNOOP\x10\x00\x12\n\n\x06WPAPSK\x10\x01\x12\n\n\x06WPAEAP\x10\x02\x42=\n\x15org.lfedge.eve.configZ$github.com/lf-edge/eve/api/go/configb\x06proto3'
-)
+  serialized_pb=b'\n\x13\x63onfig/netcmn.proto\x12\x15org.lfedge.eve.config\x1a\x18\x63onfig/acipherinfo.proto\"%\n\x07ipRange\x12\r\n\x05start\x18\x01 \x01(\t\x12\x0b\n\x03\x65nd\x18\x02 \x01(\t\"\xa7\x01\n\x0bProxyServer\x12\x30\n\x05proto\x18\x01 \x01(\x0e\x32!.org.lfedge.eve.config.proxyProto\x12\x0e\n\x06server\x18\x02 \x01(\t\x12\x0c\n\x04port\x18\x03 \x01(\r\x12\x10\n\x08username\x18\x04 \x01(\t\x12\x36\n\ncipherData\x18\x05 \x01(\x0b\x32\".org.lfedge.eve.config.CipherBlock\"\xe6\x01\n\x0bProxyConfig\x12\x1a\n\x12networkProxyEnable\x18\x01 \x01(\x08\x12\x33\n\x07proxies\x18\x02 \x03(\x0b\x32\".org.lfedge.eve.config.ProxyServer\x12\x12\n\nexceptions\x18\x03 \x01(\t\x12\x0f\n\x07pacfile\x18\x04 \x01(\t\x12\x17\n\x0fnetworkProxyURL\x18\x05 \x01(\t\x12\x14\n\x0cproxyCertPEM\x18\x06 \x03(\x0c\x12\x32\n\x06policy\x18\x07 \x01(\x0e\x32\".org.lfedge.eve.config.proxyPolicy\"*\n\tZedServer\x12\x10\n\x08HostName\x18\x01 \x01(\t\x12\x0b\n\x03\x45ID\x18\x02 \x03(\t\"7\n\x12ZnetStaticDNSEntry\x12\x10\n\x08HostName\x18\x01 \x01(\t\x12\x0f\n\x07\x41\x64\x64ress\x18\x02 \x03(\t\"\x85\x03\n\x06ipspec\x12-\n\x04\x64hcp\x18\x02 \x01(\x0e\x32\x1f.org.lfedge.eve.config.DHCPType\x12\x0e\n\x06subnet\x18\x03 \x01(\t\x12\x0f\n\x07gateway\x18\x05 \x01(\t\x12\x0e\n\x06\x64omain\x18\x06 \x01(\t\x12\x0b\n\x03ntp\x18\x07 \x01(\t\x12\x0b\n\x03\x64ns\x18\x08 \x03(\t\x12\x31\n\tdhcpRange\x18\t \x01(\x0b\x32\x1e.org.lfedge.eve.config.ipRange\x12.\n\x06routes\x18\n \x03(\x0b\x32\x1e.org.lfedge.eve.config.IPRoute\x12\x0f\n\x07\x64omains\x18\x0b \x03(\t\x12\x39\n\x0cipv6AddrMode\x18\x0c \x01(\x0e\x32#.org.lfedge.eve.config.IPv6AddrMode\x12<\n\x0creservations\x18\r \x03(\x0b\x32&.org.lfedge.eve.config.DhcpReservation\x12\x14\n\x0c\x65xtraSubnets\x18\x0e \x03(\t\"<\n\x0f\x44hcpReservation\x12\x0b\n\x03mac\x18\x01 \x01(\t\x12\n\n\x02ip\x18\x02 \x01(\t\x12\x10\n\x08hostname\x18\x03 \x01(\t\"/\n\x07IPRoute\x12\x13\n\x0b\x64\x65stination\x18\x01 \x01(\t\x12\x0f\n\x07gateway\x18\x02 \x01(\t*_\n\nproxyProto\x12\x0e\n\nPROXY_HTTP\x10\x00\x12\x0f\n\x0bPROXY_HTTPS\x10\x01\x12\x0f\n\x0bPROXY_SOCKS\x10\x02\x12\r\n\tPROXY_FTP\x10\x03\x12\x10\n\x0bPROXY_OTHER\x10\xff\x01*\x9d\x01\n\x0bproxyPolicy\x12\x1c\n\x18PROXY_POLICY_UNSPECIFIED\x10\x00\x12\x1c\n\x18PROXY_POLICY_STATIC_ONLY\x10\x01\x12\x19\n\x15PROXY_POLICY_PAC_ONLY\x10\x02\x12 \n\x1cPROXY_POLICY_PAC_THEN_STATIC\x10\x03\x12\x15\n\x11PROXY_POLICY_WPAD\x10\x04*>\n\x08\x44HCPType\x12\x0c\n\x08\x44HCPNoop\x10\x00\x12\n\n\x06Static\x10\x01\x12\x0c\n\x08\x44HCPNone\x10\x02\x12\n\n\x06\x43lient\x10\x04*\x91\x01\n\x0cIPv6AddrMode\x12\x1e\n\x1aIPV6_ADDR_MODE_UNSPECIFIED\x10\x00\x12\x18\n\x14IPV6_ADDR_MODE_SLAAC\x10\x01\x12\"\n\x1eIPV6_ADDR_MODE_DHCPV6_STATEFUL\x10\x02\x12#\n\x1fIPV6_ADDR_MODE_DHCPV6_STATELESS\x10\x03*]\n\x0bNetworkType\x12\x13\n\x0fNETWORKTYPENOOP\x10\x00\x12\x06\n\x02V4\x10\x04\x12\x06\n\x02V6\x10\x06\x12\x0c\n\x08\x43ryptoV4\x10\x18\x12\x0c\n\x08\x43ryptoV6\x10\x1a\x12\r\n\tCryptoEID\x10\x0e*4\n\x0cWirelessType\x12\x0c\n\x08TypeNOOP\x10\x00\x12\x08\n\x04WiFi\x10\x01\x12\x0c\n\x08\x43\x65llular\x10\x02*D\n\rWiFiKeyScheme\x12\x0e\n\nSchemeNOOP\x10\x00\x12\n\n\x06WPAPSK\x10\x01\x12\n\n\x06WPAEAP\x10\x02\x12\x0b\n\x07WPA3SAE\x10\x03\x42=\n\x15org.lfedge.eve.configZ$github.com/lf-edge/eve/api/go/configb\x06proto3'
+  ,
+  dependencies=[config_dot_acipherinfo__pb2.DESCRIPTOR,])
 
 _PROXYPROTO = _descriptor.EnumDescriptor(
   name='proxyProto',
@@ -58,12 +60,53 @@ _PROXYPROTO = _descriptor.EnumDescriptor(
   ],
   containing_type=None,
   serialized_options=None,
-  serialized_start=646,
-  serialized_end=741,
+  serialized_start=1118,
+  serialized_end=1213,
 )
 _sym_db.RegisterEnumDescriptor(_PROXYPROTO)
 
 proxyProto = enum_type_wrapper.EnumTypeWrapper(_PROXYPROTO)
+_PROXYPOLICY = _descriptor.EnumDescriptor(
+  name='proxyPolicy',
+  full_name='org.lfedge.eve.config.proxyPolicy',
+  filename=None,
+  file=DESCRIPTOR,
+  create_key=_descriptor._internal_create_key,
+  values=[
+    _descriptor.EnumValueDescriptor(
+      name='PROXY_POLICY_UNSPECIFIED', index=0, number=0,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+    _descriptor.EnumValueDescriptor(
+      name='PROXY_POLICY_STATIC_ONLY', index=1, number=1,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+    _descriptor.EnumValueDescriptor(
+      name='PROXY_POLICY_PAC_ONLY', index=2, number=2,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+    _descriptor.EnumValueDescriptor(
+      name='PROXY_POLICY_PAC_THEN_STATIC', index=3, number=3,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+    _descriptor.EnumValueDescriptor(
+      name='PROXY_POLICY_WPAD', index=4, number=4,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+  ],
+  containing_type=None,
+  serialized_options=None,
+  serialized_start=1216,
+  serialized_end=1373,
+)
+_sym_db.RegisterEnumDescriptor(_PROXYPOLICY)
+
+proxyPolicy = enum_type_wrapper.EnumTypeWrapper(_PROXYPOLICY)
 _DHCPTYPE = _descriptor.EnumDescriptor(
   name='DHCPType',
   full_name='org.lfedge.eve.config.DHCPType',
@@ -94,12 +137,48 @@ _DHCPTYPE = _descriptor.EnumDescriptor(
   ],
   containing_type=None,
   serialized_options=None,
-  serialized_start=743,
-  serialized_end=805,
+  serialized_start=1375,
+  serialized_end=1437,
 )
 _sym_db.RegisterEnumDescriptor(_DHCPTYPE)
 
 DHCPType = enum_type_wrapper.EnumTypeWrapper(_DHCPTYPE)
+_IPV6ADDRMODE = _descriptor.EnumDescriptor(
+  name='IPv6AddrMode',
+  full_name='org.lfedge.eve.config.IPv6AddrMode',
+  filename=None,
+  file=DESCRIPTOR,
+  create_key=_descriptor._internal_create_key,
+  values=[
+    _descriptor.EnumValueDescriptor(
+      name='IPV6_ADDR_MODE_UNSPECIFIED', index=0, number=0,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+    _descriptor.EnumValueDescriptor(
+      name='IPV6_ADDR_MODE_SLAAC', index=1, number=1,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+    _descriptor.EnumValueDescriptor(
+      name='IPV6_ADDR_MODE_DHCPV6_STATEFUL', index=2, number=2,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+    _descriptor.EnumValueDescriptor(
+      name='IPV6_ADDR_MODE_DHCPV6_STATELESS', index=3, number=3,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+  ],
+  containing_type=None,
+  serialized_options=None,
+  serialized_start=1440,
+  serialized_end=1585,
+)
+_sym_db.RegisterEnumDescriptor(_IPV6ADDRMODE)
+
+IPv6AddrMode = enum_type_wrapper.EnumTypeWrapper(_IPV6ADDRMODE)
 _NETWORKTYPE = _descriptor.EnumDescriptor(
   name='NetworkType',
   full_name='org.lfedge.eve.config.NetworkType',
@@ -140,8 +219,8 @@ _NETWORKTYPE = _descriptor.EnumDescriptor(
   ],
   containing_type=None,
   serialized_options=None,
-  serialized_start=807,
-  serialized_end=900,
+  serialized_start=1587,
+  serialized_end=1680,
 )
 _sym_db.RegisterEnumDescriptor(_NETWORKTYPE)
 
@@ -171,8 +250,8 @@ _WIRELESSTYPE = _descriptor.EnumDescriptor(
   ],
   containing_type=None,
   serialized_options=None,
-  serialized_start=902,
-  serialized_end=954,
+  serialized_start=1682,
+  serialized_end=1734,
 )
 _sym_db.RegisterEnumDescriptor(_WIRELESSTYPE)
 
@@ -199,11 +278,16 @@ _WIFIKEYSCHEME = _descriptor.EnumDescriptor(
       serialized_options=None,
       type=None,
       create_key=_descriptor._internal_create_key),
+    _descriptor.EnumValueDescriptor(
+      name='WPA3SAE', index=3, number=3,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
   ],
   containing_type=None,
   serialized_options=None,
-  serialized_start=956,
-  serialized_end=1011,
+  serialized_start=1736,
+  serialized_end=1804,
 )
 _sym_db.RegisterEnumDescriptor(_WIFIKEYSCHEME)
 
@@ -213,10 +297,19 @@ PROXY_HTTPS = 1
 PROXY_SOCKS = 2
 PROXY_FTP = 3
 PROXY_OTHER = 255
+PROXY_POLICY_UNSPECIFIED = 0
+PROXY_POLICY_STATIC_ONLY = 1
+PROXY_POLICY_PAC_ONLY = 2
+PROXY_POLICY_PAC_THEN_STATIC = 3
+PROXY_POLICY_WPAD = 4
 DHCPNoop = 0
 Static = 1
 DHCPNone = 2
 Client = 4
+IPV6_ADDR_MODE_UNSPECIFIED = 0
+IPV6_ADDR_MODE_SLAAC = 1
+IPV6_ADDR_MODE_DHCPV6_STATEFUL = 2
+IPV6_ADDR_MODE_DHCPV6_STATELESS = 3
 NETWORKTYPENOOP = 0
 V4 = 4
 V6 = 6
@@ -229,6 +322,7 @@ Cellular = 2
 SchemeNOOP = 0
 WPAPSK = 1
 WPAEAP = 2
+WPA3SAE = 3
 
 
 
@@ -266,8 +360,8 @@ _IPRANGE = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=46,
-  serialized_end=83,
+  serialized_start=72,
+  serialized_end=109,
 )
 
 
@@ -300,6 +394,20 @@ _PROXYSERVER = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='username', full_name='org.lfedge.eve.config.ProxyServer.username', index=3,
+      number=4, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='cipherData', full_name='org.lfedge.eve.config.ProxyServer.cipherData', index=4,
+      number=5, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
@@ -312,8 +420,8 @@ _PROXYSERVER = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=85,
-  serialized_end=178,
+  serialized_start=112,
+  serialized_end=279,
 )
 
 
@@ -367,6 +475,13 @@ _PROXYCONFIG = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='policy', full_name='org.lfedge.eve.config.ProxyConfig.policy', index=6,
+      number=7, type=14, cpp_type=8, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
@@ -379,8 +494,8 @@ _PROXYCONFIG = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=181,
-  serialized_end=359,
+  serialized_start=282,
+  serialized_end=512,
 )
 
 
@@ -418,8 +533,8 @@ _ZEDSERVER = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=361,
-  serialized_end=403,
+  serialized_start=514,
+  serialized_end=556,
 )
 
 
@@ -457,8 +572,8 @@ _ZNETSTATICDNSENTRY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=405,
-  serialized_end=460,
+  serialized_start=558,
+  serialized_end=613,
 )
 
 
@@ -519,6 +634,126 @@ _IPSPEC = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='routes', full_name='org.lfedge.eve.config.ipspec.routes', index=7,
+      number=10, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='domains', full_name='org.lfedge.eve.config.ipspec.domains', index=8,
+      number=11, type=9, cpp_type=9, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='ipv6AddrMode', full_name='org.lfedge.eve.config.ipspec.ipv6AddrMode', index=9,
+      number=12, type=14, cpp_type=8, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='reservations', full_name='org.lfedge.eve.config.ipspec.reservations', index=10,
+      number=13, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='extraSubnets', full_name='org.lfedge.eve.config.ipspec.extraSubnets', index=11,
+      number=14, type=9, cpp_type=9, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=616,
+  serialized_end=1005,
+)
+
+
+_DHCPRESERVATION = _descriptor.Descriptor(
+  name='DhcpReservation',
+  full_name='org.lfedge.eve.config.DhcpReservation',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='mac', full_name='org.lfedge.eve.config.DhcpReservation.mac', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='ip', full_name='org.lfedge.eve.config.DhcpReservation.ip', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='hostname', full_name='org.lfedge.eve.config.DhcpReservation.hostname', index=2,
+      number=3, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1007,
+  serialized_end=1067,
+)
+
+
+_IPROUTE = _descriptor.Descriptor(
+  name='IPRoute',
+  full_name='org.lfedge.eve.config.IPRoute',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='destination', full_name='org.lfedge.eve.config.IPRoute.destination', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='gateway', full_name='org.lfedge.eve.config.IPRoute.gateway', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
@@ -531,22 +766,31 @@ _IPSPEC = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=463,
-  serialized_end=644,
+  serialized_start=1069,
+  serialized_end=1116,
 )
 
 _PROXYSERVER.fields_by_name['proto'].enum_type = _PROXYPROTO
+_PROXYSERVER.fields_by_name['cipherData'].message_type = config_dot_acipherinfo__pb2._CIPHERBLOCK
 _PROXYCONFIG.fields_by_name['proxies'].message_type = _PROXYSERVER
+_PROXYCONFIG.fields_by_name['policy'].enum_type = _PROXYPOLICY
 _IPSPEC.fields_by_name['dhcp'].enum_type = _DHCPTYPE
 _IPSPEC.fields_by_name['dhcpRange'].message_type = _IPRANGE
+_IPSPEC.fields_by_name['routes'].message_type = _IPROUTE
+_IPSPEC.fields_by_name['ipv6AddrMode'].enum_type = _IPV6ADDRMODE
+_IPSPEC.fields_by_name['reservations'].message_type = _DHCPRESERVATION
 DESCRIPTOR.message_types_by_name['ipRange'] = _IPRANGE
 DESCRIPTOR.message_types_by_name['ProxyServer'] = _PROXYSERVER
 DESCRIPTOR.message_types_by_name['ProxyConfig'] = _PROXYCONFIG
 DESCRIPTOR.message_types_by_name['ZedServer'] = _ZEDSERVER
 DESCRIPTOR.message_types_by_name['ZnetStaticDNSEntry'] = _ZNETSTATICDNSENTRY
 DESCRIPTOR.message_types_by_name['ipspec'] = _IPSPEC
+DESCRIPTOR.message_types_by_name['DhcpReservation'] = _DHCPRESERVATION
+DESCRIPTOR.message_types_by_name['IPRoute'] = _IPROUTE
 DESCRIPTOR.enum_types_by_name['proxyProto'] = _PROXYPROTO
+DESCRIPTOR.enum_types_by_name['proxyPolicy'] = _PROXYPOLICY
 DESCRIPTOR.enum_types_by_name['DHCPType'] = _DHCPTYPE
+DESCRIPTOR.enum_types_by_name['IPv6AddrMode'] = _IPV6ADDRMODE
 DESCRIPTOR.enum_types_by_name['NetworkType'] = _NETWORKTYPE
 DESCRIPTOR.enum_types_by_name['WirelessType'] = _WIRELESSTYPE
 DESCRIPTOR.enum_types_by_name['WiFiKeyScheme'] = _WIFIKEYSCHEME
@@ -594,6 +838,20 @@ ipspec = _reflection.GeneratedProtocolMessageType('ipspec', (_message.Message,),
   })
 _sym_db.RegisterMessage(ipspec)
 
+DhcpReservation = _reflection.GeneratedProtocolMessageType('DhcpReservation', (_message.Message,), {
+  'DESCRIPTOR' : _DHCPRESERVATION,
+  '__module__' : 'config.netcmn_pb2'
+  # @@protoc_insertion_point(class_scope:org.lfedge.eve.config.DhcpReservation)
+  })
+_sym_db.RegisterMessage(DhcpReservation)
+
+IPRoute = _reflection.GeneratedProtocolMessageType('IPRoute', (_message.Message,), {
+  'DESCRIPTOR' : _IPROUTE,
+  '__module__' : 'config.netcmn_pb2'
+  # @@protoc_insertion_point(class_scope:org.lfedge.eve.config.IPRoute)
+  })
+_sym_db.RegisterMessage(IPRoute)
+
 
 DESCRIPTOR._options = None
 # @@protoc_insertion_point(module_scope)
@@ -20,7 +20,7 @@ DESCRIPTOR = _descriptor.FileDescriptor(
   syntax='proto3',
   serialized_options=b'\n\025org.lfedge.eve.commonB\tEvecommonP\001Z\'github.com/lf-edge/eve/api/go/evecommon',
   create_key=_descriptor._internal_create_key,
-  serialized_pb=b'\n\x19\x65vecommon/evecommon.proto\x12\x15org.lfedge.eve.common*q\n\rHashAlgorithm\x12\x1a\n\x16HASH_ALGORITHM_INVALID\x10\x00\x12!\n\x1dHASH_ALGORITHM_SHA256_16BYTES\x10\x01\x12!\n\x1dHASH_ALGORITHM_SHA256_32BYTES\x10\x02\x42M\n\x15org.lfedge.eve.commonB\tEvecommonP\x01Z\'github.com/lf-edge/eve/api/go/evecommonb\x06proto3'
+  serialized_pb=b'\n\x19\x65vecommon/evecommon.proto\x12\x15org.lfedge.eve.common*q\n\rHashAlgorithm\x12\x1a\n\x16HASH_ALGORITHM_INVALID\x10\x00\x12!\n\x1dHASH_ALGORITHM_SHA256_16BYTES\x10\x01\x12!\n\x1dHASH_ALGORITHM_SHA256_32BYTES\x10\x02*{\n\tWipeScope\x12\x1a\n\x16WIPE_SCOPE_UNSPECIFIED\x10\x00\x12\x13\n\x0fWIPE_SCOPE_APPS\x10\x01\x12\x1c\n\x18WIPE_SCOPE_APPS_AND_LOGS\x10\x02\x12\x1f\n\x1bWIPE_SCOPE_ALL_BUT_IDENTITY\x10\x03\x42M\n\x15org.lfedge.eve.commonB\tEvecommonP\x01Z\'github.com/lf-edge/eve/api/go/evecommonb\x06proto3'
 )
 
 _HASHALGORITHM = _descriptor.EnumDescriptor(
@@ -54,12 +54,53 @@ _HASHALGORITHM = _descriptor.EnumDescriptor(
 _sym_db.RegisterEnumDescriptor(_HASHALGORITHM)
 
 HashAlgorithm = enum_type_wrapper.EnumTypeWrapper(_HASHALGORITHM)
+_WIPESCOPE = _descriptor.EnumDescriptor(
+  name='WipeScope',
+  full_name='org.lfedge.eve.common.WipeScope',
+  filename=None,
+  file=DESCRIPTOR,
+  create_key=_descriptor._internal_create_key,
+  values=[
+    _descriptor.EnumValueDescriptor(
+      name='WIPE_SCOPE_UNSPECIFIED', index=0, number=0,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+    _descriptor.EnumValueDescriptor(
+      name='WIPE_SCOPE_APPS', index=1, number=1,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+    _descriptor.EnumValueDescriptor(
+      name='WIPE_SCOPE_APPS_AND_LOGS', index=2, number=2,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+    _descriptor.EnumValueDescriptor(
+      name='WIPE_SCOPE_ALL_BUT_IDENTITY', index=3, number=3,
+      serialized_options=None,
+      type=None,
+      create_key=_descriptor._internal_create_key),
+  ],
+  containing_type=None,
+  serialized_options=None,
+  serialized_start=167,
+  serialized_end=290,
+)
+_sym_db.RegisterEnumDescriptor(_WIPESCOPE)
+
+WipeScope = enum_type_wrapper.EnumTypeWrapper(_WIPESCOPE)
 HASH_ALGORITHM_INVALID = 0
 HASH_ALGORITHM_SHA256_16BYTES = 1
 HASH_ALGORITHM_SHA256_32BYTES = 2
+WIPE_SCOPE_UNSPECIFIED = 0
+WIPE_SCOPE_APPS = 1
+WIPE_SCOPE_APPS_AND_LOGS = 2
+WIPE_SCOPE_ALL_BUT_IDENTITY = 3
 
 
 DESCRIPTOR.enum_types_by_name['HashAlgorithm'] = _HASHALGORITHM
+DESCRIPTOR.enum_types_by_name['WipeScope'] = _WIPESCOPE
 _sym_db.RegisterFileDescriptor(DESCRIPTOR)
 
 
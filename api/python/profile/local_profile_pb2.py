@@ -19,7 +19,7 @@ DESCRIPTOR = _descriptor.FileDescriptor(
   syntax='proto3',
   serialized_options=b'\n\026org.lfedge.eve.profileZ%github.com/lf-edge/eve/api/go/profile',
   create_key=_descriptor._internal_create_key,
-  serialized_pb=b'\n\x1bprofile/local_profile.proto\x12\x16org.lfedge.eve.profile\";\n\x0cLocalProfile\x12\x15\n\rlocal_profile\x18\x01 \x01(\t\x12\x14\n\x0cserver_token\x18\x02 \x01(\tB?\n\x16org.lfedge.eve.profileZ%github.com/lf-edge/eve/api/go/profileb\x06proto3'
+  serialized_pb=b'\n\x1bprofile/local_profile.proto\x12\x16org.lfedge.eve.profile\"z\n\x0cLocalProfile\x12\x15\n\rlocal_profile\x18\x01 \x01(\t\x12\x14\n\x0cserver_token\x18\x02 \x01(\t\x12=\n\x0c\x61pp_restarts\x18\x03 \x03(\x0b\x32\'.org.lfedge.eve.profile.LocalAppRestart\"2\n\x0fLocalAppRestart\x12\x0e\n\x06\x61pp_id\x18\x01 \x01(\t\x12\x0f\n\x07\x63ounter\x18\x02 \x01(\rB?\n\x16org.lfedge.eve.profileZ%github.com/lf-edge/eve/api/go/profileb\x06proto3'
 )
 
 
@@ -47,6 +47,13 @@ _LOCALPROFILE = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='app_restarts', full_name='org.lfedge.eve.profile.LocalProfile.app_restarts', index=2,
+      number=3, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
@@ -60,10 +67,51 @@ _LOCALPROFILE = _descriptor.Descriptor(
   oneofs=[
   ],
   serialized_start=55,
-  serialized_end=114,
+  serialized_end=177,
 )
 
+
+_LOCALAPPRESTART = _descriptor.Descriptor(
+  name='LocalAppRestart',
+  full_name='org.lfedge.eve.profile.LocalAppRestart',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='app_id', full_name='org.lfedge.eve.profile.LocalAppRestart.app_id', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='counter', full_name='org.lfedge.eve.profile.LocalAppRestart.counter', index=1,
+      number=2, type=13, cpp_type=3, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=179,
+  serialized_end=229,
+)
+
+_LOCALPROFILE.fields_by_name['app_restarts'].message_type = _LOCALAPPRESTART
 DESCRIPTOR.message_types_by_name['LocalProfile'] = _LOCALPROFILE
+DESCRIPTOR.message_types_by_name['LocalAppRestart'] = _LOCALAPPRESTART
 _sym_db.RegisterFileDescriptor(DESCRIPTOR)
 
 LocalProfile = _reflection.GeneratedProtocolMessageType('LocalProfile', (_message.Message,), {
@@ -73,6 +121,13 @@ LocalProfile = _reflection.GeneratedProtocolMessageType('LocalProfile', (_messag
   })
 _sym_db.RegisterMessage(LocalProfile)
 
+LocalAppRestart = _reflection.GeneratedProtocolMessageType('LocalAppRestart', (_message.Message,), {
+  'DESCRIPTOR' : _LOCALAPPRESTART,
+  '__module__' : 'profile.local_profile_pb2'
+  # @@protoc_insertion_point(class_scope:org.lfedge.eve.profile.LocalAppRestart)
+  })
+_sym_db.RegisterMessage(LocalAppRestart)
+
 
 DESCRIPTOR._options = None
 # @@protoc_insertion_point(module_scope)
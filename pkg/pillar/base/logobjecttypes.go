@@ -92,6 +92,10 @@ const (
 	VolumeRefConfigLogType LogObjectType = "volume_ref_config"
 	// VolumeRefStatusLogType:
 	VolumeRefStatusLogType LogObjectType = "volume_ref_status"
+	// AppVolumeRetentionLogType:
+	AppVolumeRetentionLogType LogObjectType = "app_volume_retention"
+	// AppLogPolicyConfigLogType:
+	AppLogPolicyConfigLogType LogObjectType = "app_log_policy_config"
 	// ServiceInitType:
 	ServiceInitLogType LogObjectType = "service_init"
 	// AppAndImageToHashLogType:
@@ -102,6 +106,14 @@ const (
 	AssignableAdaptersLogType LogObjectType = "assignable_adapters"
 	// PhysicalIOAdapterListLogType:
 	PhysicalIOAdapterListLogType LogObjectType = "physical_io_adapter_list"
+	// SystemAdapterReportLogType:
+	SystemAdapterReportLogType LogObjectType = "system_adapter_report"
+	// DevicePortConfigParseStatusLogType:
+	DevicePortConfigParseStatusLogType LogObjectType = "device_port_config_parse_status"
+	// WipeRequestLogType:
+	WipeRequestLogType LogObjectType = "wipe_request"
+	// WipeStatusLogType:
+	WipeStatusLogType LogObjectType = "wipe_status"
 	// AttestNonceLogType:
 	AttestNonceLogType LogObjectType = "attest_nonce"
 	// AttestQuoteLogType:
@@ -25,6 +25,8 @@ func getEncryptionBlock(
 	decBlock.WifiUserName = zconfigDecBlockPtr.WifiUserName
 	decBlock.WifiPassword = zconfigDecBlockPtr.WifiPassword
 	decBlock.ProtectedUserData = zconfigDecBlockPtr.ProtectedUserData
+	decBlock.ProxyPassword = zconfigDecBlockPtr.ProxyPassword
+	decBlock.CellularPassword = zconfigDecBlockPtr.CellularPassword
 	return decBlock
 }
 
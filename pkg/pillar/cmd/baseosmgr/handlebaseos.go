@@ -766,6 +766,11 @@ func unpublishBaseOsStatus(ctx *baseOsMgrContext, key string) {
 // Check the number of image in this config
 func validateBaseOsConfig(ctx *baseOsMgrContext, config types.BaseOsConfig) error {
 
+	// Errors from parsing the config, such as invalid drives
+	if config.HasError() {
+		return errors.New(config.Error)
+	}
+
 	imageCount := len(config.ContentTreeConfigList)
 	if imageCount > BaseOsImageCount {
 		errStr := fmt.Sprintf("baseOs(%s) invalid image count %d",
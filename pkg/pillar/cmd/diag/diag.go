@@ -739,6 +739,10 @@ func printProxy(ctx *diagContext, port types.NetworkPortStatus,
 		fmt.Fprintf(outfile, "INFO: %s: no http(s) proxy\n", ifname)
 		return
 	}
+	if port.ProxyConfig.Policy != types.ProxyPolicyDefault {
+		fmt.Fprintf(outfile, "INFO: %s: proxy policy %s\n",
+			ifname, port.ProxyConfig.Policy)
+	}
 	if port.ProxyConfig.Exceptions != "" {
 		fmt.Fprintf(outfile, "INFO: %s: proxy exceptions %s\n",
 			ifname, port.ProxyConfig.Exceptions)
@@ -1046,12 +1050,12 @@ func myGet(ctx *diagContext, reqURL string, ifname string,
 		preqURL = "https://" + reqURL
 	}
 	proxyURL, err := zedcloud.LookupProxy(log, zedcloudCtx.DeviceNetworkStatus,
-		ifname, preqURL)
+		ifname, preqURL, zedcloudCtx.DecryptCipherContext)
 	if err != nil {
 		fmt.Fprintf(outfile, "ERROR: %s: LookupProxy failed: %s\n", ifname, err)
 	} else if proxyURL != nil {
 		fmt.Fprintf(outfile, "INFO: %s: Proxy %s to reach %s\n",
-			ifname, proxyURL.String(), reqURL)
+			ifname, proxyURL.Redacted(), reqURL)
 	}
 	const allowProxy = true
 	resp, contents, rtf, err := zedcloud.SendOnIntf(zedcloudCtx,
@@ -1107,12 +1111,12 @@ func myPost(ctx *diagContext, reqURL string, ifname string,
 		preqURL = "https://" + reqURL
 	}
 	proxyURL, err := zedcloud.LookupProxy(log, zedcloudCtx.DeviceNetworkStatus,
-		ifname, preqURL)
+		ifname, preqURL, zedcloudCtx.DecryptCipherContext)
 	if err != nil {
 		fmt.Fprintf(outfile, "ERROR: %s: LookupProxy failed: %s\n", ifname, err)
 	} else if proxyURL != nil {
 		fmt.Fprintf(outfile, "INFO: %s: Proxy %s to reach %s\n",
-			ifname, proxyURL.String(), reqURL)
+			ifname, proxyURL.Redacted(), reqURL)
 	}
 	const allowProxy = true
 	resp, contents, rtf, err := zedcloud.SendOnIntf(zedcloudCtx,
@@ -48,10 +48,11 @@ func download(ctx *downloaderContext, trType zedUpload.SyncTransportType,
 	}
 	// check for proxies on the selected management port interface
 	proxyLookupURL := zedcloud.IntfLookupProxyCfg(log, &ctx.deviceNetworkStatus, ifname, downloadURL, trType)
-	proxyURL, err := zedcloud.LookupProxy(log, &ctx.deviceNetworkStatus, ifname, proxyLookupURL)
+	proxyURL, err := zedcloud.LookupProxy(log, &ctx.deviceNetworkStatus, ifname,
+		proxyLookupURL, &ctx.decryptCipherContext)
 	if err == nil {
 		if proxyURL != nil {
-			log.Functionf("%s: Using proxy %s", trType, proxyURL.String())
+			log.Functionf("%s: Using proxy %s", trType, proxyURL.Redacted())
 			err = dEndPoint.WithSrcIPAndProxySelection(ipSrc, proxyURL)
 		} else {
 			if len(certs) > 0 {
@@ -178,9 +179,10 @@ func objectMetadata(ctx *downloaderContext, trType zedUpload.SyncTransportType,
 	// check for proxies on the selected management port interface
 	proxyLookupURL := zedcloud.IntfLookupProxyCfg(log, &ctx.deviceNetworkStatus, ifname, downloadURL, trType)
 
-	proxyURL, err := zedcloud.LookupProxy(log, &ctx.deviceNetworkStatus, ifname, proxyLookupURL)
+	proxyURL, err := zedcloud.LookupProxy(log, &ctx.deviceNetworkStatus, ifname,
+		proxyLookupURL, &ctx.decryptCipherContext)
 	if err == nil && proxyURL != nil {
-		log.Functionf("%s: Using proxy %s", trType, proxyURL.String())
+		log.Functionf("%s: Using proxy %s", trType, proxyURL.Redacted())
 		dEndPoint.WithSrcIPAndProxySelection(ipSrc, proxyURL)
 	} else {
 		dEndPoint.WithSrcIPSelection(ipSrc)
@@ -304,16 +304,14 @@ func handleSyncOpResponse(ctx *downloaderContext, config types.DownloaderConfig,
 
 // cloud storage interface functions/APIs
 func constructDatastoreContext(ctx *downloaderContext, configName string, NameIsURL bool, dst types.DatastoreConfig) (*types.DatastoreContext, error) {
+	if dst.ConfigError.HasError() {
+		return nil, fmt.Errorf("datastore %s: %s", dst.Key(),
+			dst.ConfigError.Error)
+	}
 	dpath := dst.Dpath
 	downloadURL := configName
 	if !NameIsURL {
-		downloadURL = dst.Fqdn
-		if len(dpath) > 0 {
-			downloadURL = downloadURL + "/" + dpath
-		}
-		if len(configName) > 0 {
-			downloadURL = downloadURL + "/" + configName
-		}
+		downloadURL = dst.DownloadURL(configName)
 	}
 
 	// get the decrypted encryption block
@@ -21,6 +21,7 @@ import (
 	"github.com/lf-edge/eve/api/go/logs"
 	"github.com/lf-edge/eve/pkg/pillar/agentlog"
 	"github.com/lf-edge/eve/pkg/pillar/base"
+	"github.com/lf-edge/eve/pkg/pillar/cipher"
 	"github.com/lf-edge/eve/pkg/pillar/flextimer"
 	"github.com/lf-edge/eve/pkg/pillar/hardware"
 	"github.com/lf-edge/eve/pkg/pillar/pidfile"
@@ -82,6 +83,7 @@ type loguploaderContext struct {
 	metricsPub             pubsub.Publication
 	enableFastUpload       bool
 	scheduleTimer          *time.Timer
+	decryptCipherContext   cipher.DecryptCipherContext // For the proxy passwords
 }
 
 // Run - an loguploader run
@@ -170,6 +172,59 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 	loguploaderCtx.subAppInstConfig = subAppInstConfig
 	subAppInstConfig.Activate()
 
+	loguploaderCtx.decryptCipherContext.Log = log
+
+	// Look for controller certs which will be used for decryption
+	subControllerCert, err := ps.NewSubscription(pubsub.SubscriptionOptions{
+		AgentName:   "zedagent",
+		MyAgentName: agentName,
+		TopicImpl:   types.ControllerCert{},
+		Activate:    false,
+		Ctx:         &loguploaderCtx,
+		WarningTime: warningTime,
+		ErrorTime:   errorTime,
+		Persistent:  true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	loguploaderCtx.decryptCipherContext.SubControllerCert = subControllerCert
+	subControllerCert.Activate()
+
+	// Look for cipher context which will be used for decryption
+	subCipherContext, err := ps.NewSubscription(pubsub.SubscriptionOptions{
+		AgentName:   "zedagent",
+		MyAgentName: agentName,
+		TopicImpl:   types.CipherContext{},
+		Activate:    false,
+		Ctx:         &loguploaderCtx,
+		WarningTime: warningTime,
+		ErrorTime:   errorTime,
+		Persistent:  true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	loguploaderCtx.decryptCipherContext.SubCipherContext = subCipherContext
+	subCipherContext.Activate()
+
+	// Look for edge node certs which will be used for decryption
+	subEdgeNodeCert, err := ps.NewSubscription(pubsub.SubscriptionOptions{
+		AgentName:   "tpmmgr",
+		MyAgentName: agentName,
+		TopicImpl:   types.EdgeNodeCert{},
+		Activate:    false,
+		Persistent:  true,
+		Ctx:         &loguploaderCtx,
+		WarningTime: warningTime,
+		ErrorTime:   errorTime,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	loguploaderCtx.decryptCipherContext.SubEdgeNodeCert = subEdgeNodeCert
+	subEdgeNodeCert.Activate()
+
 	sendCtxInit(&loguploaderCtx)
 
 	for loguploaderCtx.usableAddrCount == 0 {
@@ -256,6 +311,15 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 		case change := <-subAppInstConfig.MsgChan():
 			subAppInstConfig.ProcessChange(change)
 
+		case change := <-subControllerCert.MsgChan():
+			subControllerCert.ProcessChange(change)
+
+		case change := <-subCipherContext.MsgChan():
+			subCipherContext.ProcessChange(change)
+
+		case change := <-subEdgeNodeCert.MsgChan():
+			subEdgeNodeCert.ProcessChange(change)
+
 		case <-publishCloudTimer.C:
 			start := time.Now()
 			log.Tracef("publishCloudTimer cloud metrics at at %s", time.Now().String())
@@ -370,6 +434,8 @@ func sendCtxInit(ctx *loguploaderContext) {
 		Serial:           hardware.GetProductSerial(log),
 		SoftSerial:       hardware.GetSoftSerial(log),
 		AgentName:        agentName,
+
+		DecryptCipherContext: &ctx.decryptCipherContext,
 	})
 	zedcloudCtx.DevUUID = devUUID
 
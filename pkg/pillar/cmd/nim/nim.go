@@ -919,7 +919,7 @@ func tryDeviceConnectivityToCloud(ctx *devicenetwork.DeviceNetworkContext) bool
 	ctx.Iteration++
 	rtf, intfStatusMap, err := devicenetwork.VerifyDeviceNetworkStatus(
 		log, *ctx.DeviceNetworkStatus, successCount, ctx.Iteration,
-		ctx.TestSendTimeout)
+		ctx.TestSendTimeout, &ctx.DecryptCipherContext)
 	ctx.DevicePortConfig.UpdatePortStatusFromIntfStatusMap(intfStatusMap)
 	// Use TestResults to update the DevicePortConfigList and publish
 	// Note that the TestResults will at least have an updated timestamp
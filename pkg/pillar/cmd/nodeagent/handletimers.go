@@ -94,6 +94,15 @@ func handleUpgradeTestValidation(ctxPtr *nodeagentContext) {
 		return
 	}
 	if checkUpgradeValidationTestTimeExpiry(ctxPtr) {
+		// zedagent reports a verdict if the update has a canary app
+		if ctxPtr.canaryVerdict != types.CanaryVerdictNone &&
+			ctxPtr.canaryVerdict != types.CanaryVerdictHealthy {
+			errStr := fmt.Sprintf("Canary app %s at end of upgrade validation; rebooting\n",
+				ctxPtr.canaryVerdict)
+			log.Errorf(errStr)
+			scheduleNodeReboot(ctxPtr, errStr, types.BootReasonFallback)
+			return
+		}
 		log.Functionf("CurPart: %s, Upgrade Validation Test Complete",
 			ctxPtr.curPart)
 		resetTestStartTime(ctxPtr)
@@ -309,6 +318,9 @@ func handleNodeReboot(ctxPtr *nodeagentContext) {
 	// Wait for All Domains Halted
 	waitForAllDomainsHalted(ctxPtr)
 
+	// Wipe once nothing uses the state
+	doWipe(ctxPtr)
+
 	// do a sync
 	log.Functionf("Doing a sync..")
 	syscall.Sync()
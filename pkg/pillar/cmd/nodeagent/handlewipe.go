@@ -0,0 +1,204 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Handling of the WipeRequest from zedagent. The wipe is done as part of
+// a reboot, once all the app instances are halted, so that nothing uses
+// the removed state; the agents recreate it from the config after the
+// reboot. The state of the daemons which keep running, newlogd and
+// containerd, is not removed from under them: the logs are removed by
+// device-steps.sh at the next boot, and the containerd content through
+// containerd.
+
+package nodeagent
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/lf-edge/eve/pkg/pillar/containerd"
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	"github.com/lf-edge/eve/pkg/pillar/vault"
+	"github.com/lf-edge/eve/pkg/pillar/zfs"
+)
+
+// The paths removed for each scope, in addition to those of the smaller
+// scopes. The identity in /config and /persist/certs, and the network
+// config in /persist/status, are never removed.
+var (
+	wipeAppsPaths = []string{
+		types.VolumeEncryptedDirName,
+		types.VolumeClearDirName,
+	}
+	wipeAllButIdentityPaths = []string{
+		types.PersistDir + "/checkpoint",
+		types.PersistDebugDir,
+	}
+)
+
+// wipePaths returns the paths to remove for the scope
+func wipePaths(scope types.WipeScope) []string {
+	var paths []string
+	switch scope {
+	case types.WipeScopeAllButIdentity:
+		paths = append(paths, wipeAllButIdentityPaths...)
+		fallthrough
+	case types.WipeScopeAppsAndLogs, types.WipeScopeApps:
+		paths = append(paths, wipeAppsPaths...)
+	}
+	return paths
+}
+
+func handleWipeRequestCreate(ctxArg interface{}, key string,
+	configArg interface{}) {
+	handleWipeRequestImpl(ctxArg, key, configArg)
+}
+
+func handleWipeRequestModify(ctxArg interface{}, key string,
+	configArg interface{}, oldConfigArg interface{}) {
+	handleWipeRequestImpl(ctxArg, key, configArg)
+}
+
+// handleWipeRequestImpl schedules a reboot which does the wipe, unless
+// the WipeRequest was already handled. A wipe which was in progress when
+// the device rebooted is done again.
+func handleWipeRequestImpl(ctxArg interface{}, key string,
+	configArg interface{}) {
+
+	ctxPtr := ctxArg.(*nodeagentContext)
+	req := configArg.(types.WipeRequest)
+	log.Functionf("handleWipeRequestImpl: counter %d scope %s",
+		req.Counter, req.Scope)
+	status := lookupWipeStatus(ctxPtr)
+	if status != nil && status.Counter == req.Counter &&
+		status.State != types.WipeStateInProgress {
+		log.Functionf("handleWipeRequestImpl: counter %d already %s",
+			req.Counter, status.State)
+		return
+	}
+	publishWipeStatus(ctxPtr, types.WipeStatus{
+		Counter: req.Counter,
+		Scope:   req.Scope,
+		State:   types.WipeStateInProgress,
+	})
+	ctxPtr.lastLock.Lock()
+	ctxPtr.wipeRequest = &req
+	ctxPtr.lastLock.Unlock()
+	reasonStr := fmt.Sprintf("Wipe %d of %s requested; rebooting",
+		req.Counter, req.Scope)
+	scheduleNodeReboot(ctxPtr, reasonStr, types.BootReasonRebootCmd)
+}
+
+// doWipe removes the state for the scope of the pending WipeRequest, if
+// any, and reports the outcome in WipeStatus. Called from
+// handleNodeReboot once all domains are halted.
+func doWipe(ctxPtr *nodeagentContext) {
+	ctxPtr.lastLock.Lock()
+	req := ctxPtr.wipeRequest
+	ctxPtr.wipeRequest = nil
+	ctxPtr.lastLock.Unlock()
+	if req == nil {
+		return
+	}
+	log.Noticef("doWipe: counter %d scope %s", req.Counter, req.Scope)
+	status := types.WipeStatus{
+		Counter: req.Counter,
+		Scope:   req.Scope,
+		State:   types.WipeStateDone,
+	}
+	var errs []string
+	for _, path := range wipePaths(req.Scope) {
+		log.Noticef("doWipe: removing %s", path)
+		if err := os.RemoveAll(path); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	// On ZFS the volumes are zvols rather than files in the above
+	if vault.ReadPersistType() == types.PersistZFS {
+		errs = append(errs, wipeZVols()...)
+	}
+	if req.Scope == types.WipeScopeAppsAndLogs ||
+		req.Scope == types.WipeScopeAllButIdentity {
+		log.Noticef("doWipe: removing the logs at boot")
+		if err := ioutil.WriteFile(types.WipeLogsFile, nil, 0644); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if req.Scope == types.WipeScopeAllButIdentity {
+		errs = append(errs, wipeContainerdContent()...)
+	}
+	if len(errs) != 0 {
+		status.State = types.WipeStateFailed
+		status.Error = strings.Join(errs, "; ")
+		log.Errorf("doWipe: counter %d failed: %s", req.Counter,
+			status.Error)
+	}
+	publishWipeStatus(ctxPtr, status)
+}
+
+// wipeZVols destroys the zvols of the volumes
+func wipeZVols() []string {
+	datasets, err := zfs.GetVolumesInDataset(log, types.VolumeZFSPool)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	var errs []string
+	for _, dataset := range datasets {
+		log.Noticef("doWipe: destroying %s", dataset)
+		if output, err := zfs.DestroyDataset(log, dataset); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s: %s",
+				dataset, err, output))
+		}
+	}
+	return errs
+}
+
+// wipeContainerdContent removes the images and the blobs of the apps
+// through containerd, which keeps running
+func wipeContainerdContent() []string {
+	client, err := containerd.NewContainerdClient()
+	if err != nil {
+		return []string{err.Error()}
+	}
+	defer client.CloseClient()
+	ctrdCtx, done := client.CtrNewUserServicesCtx()
+	defer done()
+
+	var errs []string
+	imgs, err := client.CtrListImages(ctrdCtx)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	for _, img := range imgs {
+		log.Noticef("doWipe: deleting image %s", img.Name)
+		if err := client.CtrDeleteImage(ctrdCtx, img.Name); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	infos, err := client.CtrListBlobInfo(ctrdCtx)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	for _, info := range infos {
+		if err := client.CtrDeleteBlob(ctrdCtx, info.Digest.String()); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return errs
+}
+
+func lookupWipeStatus(ctxPtr *nodeagentContext) *types.WipeStatus {
+	item, _ := ctxPtr.pubWipeStatus.Get("global")
+	if item == nil {
+		return nil
+	}
+	status := item.(types.WipeStatus)
+	return &status
+}
+
+func publishWipeStatus(ctxPtr *nodeagentContext, status types.WipeStatus) {
+	log.Functionf("publishWipeStatus: counter %d state %s",
+		status.Counter, status.State)
+	ctxPtr.pubWipeStatus.Publish(status.Key(), status)
+}
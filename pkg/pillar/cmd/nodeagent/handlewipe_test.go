@@ -0,0 +1,51 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeagent
+
+import (
+	"testing"
+
+	"github.com/lf-edge/eve/pkg/pillar/types"
+)
+
+func TestWipePaths(t *testing.T) {
+	wipeTestParams := []struct {
+		scope    types.WipeScope
+		wiped    []string
+		notWiped []string
+	}{
+		{types.WipeScopeUnspecified, nil,
+			[]string{types.VolumeClearDirName}},
+		{types.WipeScopeApps,
+			[]string{types.VolumeEncryptedDirName, types.VolumeClearDirName},
+			[]string{types.PersistDebugDir}},
+		{types.WipeScopeAppsAndLogs,
+			[]string{types.VolumeEncryptedDirName, types.VolumeClearDirName},
+			[]string{types.PersistDebugDir}},
+		{types.WipeScopeAllButIdentity,
+			[]string{types.VolumeClearDirName, types.PersistDebugDir}, nil},
+	}
+	// The identity and the network config are always kept, and the state
+	// of the running daemons is not removed from under them
+	kept := []string{types.CertificateDirname, types.PersistStatusDir,
+		types.PersistDir, types.NewlogDir, types.ContainerdContentDir}
+	for _, test := range wipeTestParams {
+		paths := make(map[string]bool)
+		for _, path := range wipePaths(test.scope) {
+			paths[path] = true
+		}
+		for _, path := range test.wiped {
+			if !paths[path] {
+				t.Errorf("scope %s: want %s wiped, but it is not",
+					test.scope, path)
+			}
+		}
+		for _, path := range append(test.notWiped, kept...) {
+			if paths[path] {
+				t.Errorf("scope %s: want %s kept, but it is wiped",
+					test.scope, path)
+			}
+		}
+	}
+}
@@ -7,11 +7,13 @@
 // nodeagent publishes the following topic
 //   * zboot config                 <nodeagent>  / <zboot> / <config>
 //   * nodeagent status             <nodeagent>  / <status>
+//   * wipe status                  <nodeagent>  / <wipe> / <status>
 
 // nodeagent subscribes to the following topics
 //   * global config
 //   * zboot status                 <baseosmgr> / <zboot> / <status>
 //   * zedagent status              <zedagent>  / <status>
+//   * wipe request                 <zedagent>  / <wipe> / <request>
 
 package nodeagent
 
@@ -73,8 +75,10 @@ type nodeagentContext struct {
 	subZedAgentStatus           pubsub.Subscription
 	subDomainStatus             pubsub.Subscription
 	subVaultStatus              pubsub.Subscription
+	subWipeRequest              pubsub.Subscription
 	pubZbootConfig              pubsub.Publication
 	pubNodeAgentStatus          pubsub.Publication
+	pubWipeStatus               pubsub.Publication
 	curPart                     string
 	upgradeTestStartTime        uint32
 	tickerTimer                 *time.Ticker
@@ -87,6 +91,7 @@ type nodeagentContext struct {
 	updateComplete              bool
 	testComplete                bool
 	testInprogress              bool
+	canaryVerdict               types.CanaryVerdict // From zedagent
 	timeTickCount               uint32 // Don't get confused by NTP making time jump by tracking our own progression
 	rebootCmd                   bool   // Are we rebooting?
 	deviceReboot                bool
@@ -103,6 +108,7 @@ type nodeagentContext struct {
 	vaultTestStartTime          uint32                      // Time at which we should start waiting for vault to be operational
 	maintMode                   bool                        // whether Maintenance mode should be triggered
 	maintModeReason             types.MaintenanceModeReason //reason for entering Maintenance mode
+	wipeRequest                 *types.WipeRequest          // Wipe to do before the reboot
 
 	// Some contants.. Declared here as variables to enable unit tests
 	minRebootDelay          uint32
@@ -246,6 +252,18 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 	pubZbootConfig.ClearRestarted()
 	ctxPtr.pubZbootConfig = pubZbootConfig
 
+	// publisher of the progress of the WipeRequest
+	pubWipeStatus, err := ps.NewPublication(
+		pubsub.PublicationOptions{
+			AgentName:  agentName,
+			Persistent: true,
+			TopicType:  types.WipeStatus{},
+		})
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctxPtr.pubWipeStatus = pubWipeStatus
+
 	// Look for vault status
 	subVaultStatus, err := ps.NewSubscription(pubsub.SubscriptionOptions{
 		AgentName:     "vaultmgr",
@@ -349,6 +367,25 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 	ctxPtr.subZedAgentStatus = subZedAgentStatus
 	subZedAgentStatus.Activate()
 
+	// subscribe to the wipe requests from zedagent
+	subWipeRequest, err := ps.NewSubscription(pubsub.SubscriptionOptions{
+		AgentName:     "zedagent",
+		MyAgentName:   agentName,
+		TopicImpl:     types.WipeRequest{},
+		Persistent:    true,
+		Activate:      false,
+		Ctx:           ctxPtr,
+		CreateHandler: handleWipeRequestCreate,
+		ModifyHandler: handleWipeRequestModify,
+		WarningTime:   warningTime,
+		ErrorTime:     errorTime,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctxPtr.subWipeRequest = subWipeRequest
+	subWipeRequest.Activate()
+
 	log.Functionf("zedbox event loop")
 	for {
 		select {
@@ -364,6 +401,9 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 		case change := <-subZedAgentStatus.MsgChan():
 			subZedAgentStatus.ProcessChange(change)
 
+		case change := <-subWipeRequest.MsgChan():
+			subWipeRequest.ProcessChange(change)
+
 		case <-ctxPtr.tickerTimer.C:
 			handleDeviceTimers(ctxPtr)
 
@@ -446,6 +486,11 @@ func handleZedAgentStatusImpl(ctxArg interface{}, key string,
 
 	ctxPtr := ctxArg.(*nodeagentContext)
 	status := statusArg.(types.ZedAgentStatus)
+	if ctxPtr.canaryVerdict != status.CanaryVerdict {
+		log.Noticef("Canary app %s verdict %s",
+			status.CanaryAppUUID, status.CanaryVerdict)
+		ctxPtr.canaryVerdict = status.CanaryVerdict
+	}
 	handleRebootCmd(ctxPtr, status)
 	updateZedagentCloudConnectStatus(ctxPtr, status)
 	log.Functionf("handleZedAgentStatusImpl(%s) done", key)
@@ -0,0 +1,171 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// A http server bound to localhost which provides the latched content tree
+// hashes, the content tree status and some counters as json for on-device
+// debugging.
+// Only started when the debug.enable.volumemgr.http config item is set.
+// The published types do not contain any credentials.
+
+package volumemgr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	uuid "github.com/satori/go.uuid"
+)
+
+const debugHTTPAddr = "127.0.0.1:8087"
+
+// Provides AppAndImageToHash as json
+// GET /latches and /latches/{contentID}
+type latchesHandler struct {
+	ctx *volumemgrContext
+}
+
+// Provides ContentTreeStatus as json
+// GET /contenttrees/{contentID}
+type contentTreesHandler struct {
+	ctx *volumemgrContext
+}
+
+// Provides the volumemgr counters as json
+// GET /stats
+type statsHandler struct {
+	ctx *volumemgrContext
+}
+
+// debugStats is the json returned by statsHandler
+type debugStats struct {
+	RetagHits       uint64
+	RetagMismatches uint64
+}
+
+func newDebugHTTPMux(ctx *volumemgrContext) *http.ServeMux {
+	mux := http.NewServeMux()
+	lh := &latchesHandler{ctx: ctx}
+	mux.Handle("/latches", lh)
+	mux.Handle("/latches/", lh)
+	mux.Handle("/contenttrees/", &contentTreesHandler{ctx: ctx})
+	mux.Handle("/stats", &statsHandler{ctx: ctx})
+	return mux
+}
+
+// startDebugHTTPServer is a no-op if the server is already running
+func startDebugHTTPServer(ctx *volumemgrContext) {
+	if ctx.debugHTTPServer != nil {
+		return
+	}
+	srv := &http.Server{
+		Addr:    debugHTTPAddr,
+		Handler: newDebugHTTPMux(ctx),
+	}
+	ctx.debugHTTPServer = srv
+	go func() {
+		err := srv.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			log.Errorf("debug http server on %s failed: %s",
+				debugHTTPAddr, err)
+		}
+	}()
+	log.Noticef("started debug http server on %s", debugHTTPAddr)
+}
+
+func stopDebugHTTPServer(ctx *volumemgrContext) {
+	if ctx.debugHTTPServer == nil {
+		return
+	}
+	if err := ctx.debugHTTPServer.Shutdown(context.Background()); err != nil {
+		log.Errorf("debug http server on %s shutdown failed: %s",
+			debugHTTPAddr, err)
+	}
+	ctx.debugHTTPServer = nil
+	log.Noticef("stopped debug http server on %s", debugHTTPAddr)
+}
+
+func writeJSON(w http.ResponseWriter, item interface{}) {
+	resp, err := json.Marshal(item)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(resp)
+}
+
+// ServeHTTP for latchesHandler returns all latches, or the ones for
+// a particular content tree, sorted by key
+func (hdl latchesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var contentID uuid.UUID
+	idStr := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/latches"), "/")
+	if idStr != "" {
+		var err error
+		contentID, err = uuid.FromString(idStr)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	var latches []types.AppAndImageToHash
+	items := hdl.ctx.pubContentTreeToHash.GetAll()
+	for _, a := range items {
+		aih := a.(types.AppAndImageToHash)
+		if idStr != "" && aih.ImageID != contentID {
+			continue
+		}
+		latches = append(latches, aih)
+	}
+	if idStr != "" && len(latches) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	sort.Slice(latches, func(i, j int) bool {
+		return latches[i].Key() < latches[j].Key()
+	})
+	if latches == nil {
+		latches = []types.AppAndImageToHash{}
+	}
+	writeJSON(w, latches)
+}
+
+// ServeHTTP for contentTreesHandler returns the ContentTreeStatus
+func (hdl contentTreesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	idStr := strings.TrimPrefix(r.URL.Path, "/contenttrees/")
+	if idStr == "" {
+		http.NotFound(w, r)
+		return
+	}
+	st, _ := hdl.ctx.pubContentTreeStatus.Get(idStr)
+	if st == nil {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, st.(types.ContentTreeStatus))
+}
+
+// ServeHTTP for statsHandler returns the counters
+func (hdl statsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, debugStats{
+		RetagHits:       atomic.LoadUint64(&hdl.ctx.retagHits),
+		RetagMismatches: atomic.LoadUint64(&hdl.ctx.retagMismatches),
+	})
+}
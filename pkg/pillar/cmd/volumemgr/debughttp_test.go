@@ -0,0 +1,127 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package volumemgr
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lf-edge/eve/pkg/pillar/base"
+	"github.com/lf-edge/eve/pkg/pillar/pubsub"
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func initDebugHTTPCtx(t *testing.T) *volumemgrContext {
+	ctx := &volumemgrContext{}
+	logger := logrus.StandardLogger()
+	log = base.NewSourceLogObject(logger, "test", 1234)
+	ps := pubsub.New(&pubsub.EmptyDriver{}, logger, log)
+
+	pubContentTreeStatus, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.ContentTreeStatus{},
+	})
+	assert.Nil(t, err)
+	ctx.pubContentTreeStatus = pubContentTreeStatus
+	pubContentTreeToHash, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.AppAndImageToHash{},
+	})
+	assert.Nil(t, err)
+	ctx.pubContentTreeToHash = pubContentTreeToHash
+	return ctx
+}
+
+func TestDebugHTTP(t *testing.T) {
+	ctx := initDebugHTTPCtx(t)
+	// The latches are returned sorted by key
+	contentID1 := uuid.FromStringOrNil("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	contentID2 := uuid.FromStringOrNil("6ba7b811-9dad-11d1-80b4-00c04fd430c8")
+	unknownID := "6ba7b812-9dad-11d1-80b4-00c04fd430c8"
+
+	latch1 := types.AppAndImageToHash{ImageID: contentID1, Hash: "sha1"}
+	latch2 := types.AppAndImageToHash{ImageID: contentID1, Hash: "sha2",
+		PurgeCounter: 1}
+	latch3 := types.AppAndImageToHash{ImageID: contentID2, Hash: "sha3"}
+	for _, aih := range []types.AppAndImageToHash{latch1, latch2, latch3} {
+		ctx.pubContentTreeToHash.Publish(aih.Key(), aih)
+	}
+	ct := types.ContentTreeStatus{
+		ContentID:     contentID1,
+		DisplayName:   "test",
+		ContentSha256: "sha1",
+	}
+	ctx.pubContentTreeStatus.Publish(ct.Key(), ct)
+
+	testMatrix := map[string]struct {
+		path            string
+		expectedCode    int
+		expectedLatches []types.AppAndImageToHash
+		expectedTree    *types.ContentTreeStatus
+	}{
+		"All latches": {
+			path:            "/latches",
+			expectedCode:    http.StatusOK,
+			expectedLatches: []types.AppAndImageToHash{latch1, latch2, latch3},
+		},
+		"Latches for content tree": {
+			path:            "/latches/" + contentID1.String(),
+			expectedCode:    http.StatusOK,
+			expectedLatches: []types.AppAndImageToHash{latch1, latch2},
+		},
+		"Latches for unknown content tree": {
+			path:         "/latches/" + unknownID,
+			expectedCode: http.StatusNotFound,
+		},
+		"Latches for bad content tree": {
+			path:         "/latches/foo",
+			expectedCode: http.StatusBadRequest,
+		},
+		"Content tree": {
+			path:         "/contenttrees/" + contentID1.String(),
+			expectedCode: http.StatusOK,
+			expectedTree: &ct,
+		},
+		"Unknown content tree": {
+			path:         "/contenttrees/" + unknownID,
+			expectedCode: http.StatusNotFound,
+		},
+		"Stats": {
+			path:         "/stats",
+			expectedCode: http.StatusOK,
+		},
+		"Unknown path": {
+			path:         "/foo",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	mux := newDebugHTTPMux(ctx)
+	for testname, test := range testMatrix {
+		req := httptest.NewRequest(http.MethodGet, test.path, nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		assert.Equal(t, test.expectedCode, rec.Code, testname)
+		if test.expectedLatches != nil {
+			var latches []types.AppAndImageToHash
+			err := json.Unmarshal(rec.Body.Bytes(), &latches)
+			assert.Nil(t, err, testname)
+			assert.Equal(t, test.expectedLatches, latches, testname)
+		}
+		if test.expectedTree != nil {
+			var tree types.ContentTreeStatus
+			err := json.Unmarshal(rec.Body.Bytes(), &tree)
+			assert.Nil(t, err, testname)
+			assert.Equal(t, test.expectedTree.ContentID, tree.ContentID,
+				testname)
+			assert.Equal(t, test.expectedTree.ContentSha256,
+				tree.ContentSha256, testname)
+		}
+	}
+}
@@ -30,11 +30,15 @@ func handleContentTreeModify(ctxArg interface{}, key string,
 
 	log.Functionf("handleContentTreeModify(%s)", key)
 	config := configArg.(types.ContentTreeConfig)
+	oldConfig := oldConfigArg.(types.ContentTreeConfig)
 	ctx := ctxArg.(*volumemgrContext)
 	status := lookupContentTreeStatus(ctx, config.Key())
 	if status == nil {
 		log.Fatalf("Missing ContentTreeStatus for %s", config.Key())
 	}
+	if config.RelativeURL != oldConfig.RelativeURL {
+		startContentTreeRetag(ctx, status, config.RelativeURL)
+	}
 	updateContentTree(ctx, status)
 	log.Functionf("handleContentTree(%s) Done", key)
 }
@@ -309,5 +313,6 @@ func doDeleteContentTree(ctx *volumemgrContext, status *types.ContentTreeStatus)
 	}
 	unpublishContentTreeStatus(ctx, status)
 	deleteLatchContentTreeHash(ctx, status.ContentID, uint32(status.GenerationCounter))
+	purgeLatchContentTreeHashesForContentApps(ctx, status.ContentID)
 	log.Functionf("doDeleteContentTree for %v Done", status.ContentID)
 }
@@ -4,7 +4,13 @@
 package volumemgr
 
 import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
 	"github.com/lf-edge/eve/pkg/pillar/types"
+	"github.com/lf-edge/eve/pkg/pillar/utils"
 )
 
 // MaybeAddResolveConfig will publish the resolve config for
@@ -106,6 +112,13 @@ func handleResolveStatusImpl(ctxArg interface{}, key string,
 			status.DatastoreID != rs.DatastoreID {
 			continue
 		}
+		if status.ContentSha256 != "" {
+			// Already resolved hence this is a re-tag
+			if finishContentTreeRetag(ctx, &status, rs) {
+				publishContentTreeStatus(ctx, &status)
+			}
+			continue
+		}
 		log.Functionf("Updating SHA for content tree: %v",
 			status.ContentID)
 		changed, _ := doUpdateContentTree(ctx, &status)
@@ -118,3 +131,72 @@ func handleResolveStatusImpl(ctxArg interface{}, key string,
 	}
 	log.Functionf("handleResolveStatusImpl done for %s", key)
 }
+
+// startContentTreeRetag resolves the new name of a container content tree
+// which already has a sha. finishContentTreeRetag handles the result.
+func startContentTreeRetag(ctx *volumemgrContext,
+	status *types.ContentTreeStatus, relativeURL string) {
+
+	if !status.IsOCIRegistry() || status.ContentSha256 == "" {
+		return
+	}
+	log.Noticef("startContentTreeRetag(%s) from %s to %s",
+		status.ContentID, status.RelativeURL, relativeURL)
+	status.RelativeURL = relativeURL
+	status.HasResolverRef = true
+	MaybeAddResolveConfig(ctx, *status)
+	publishContentTreeStatus(ctx, status)
+}
+
+// finishContentTreeRetag updates the name of the content tree if the new
+// name resolved to the sha we already have, thus there is nothing to
+// download. A different sha is an error since that requires a purge.
+// Returns true if the status changed.
+func finishContentTreeRetag(ctx *volumemgrContext,
+	status *types.ContentTreeStatus, rs types.ResolveStatus) bool {
+
+	foundSha := strings.ToLower(rs.ImageSha256)
+	if rs.HasError() || foundSha == "" {
+		errStr := fmt.Sprintf("Resolving re-tag %s failed: %s",
+			status.ResolveKey(), rs.Error)
+		log.Error(errStr)
+		status.SetErrorWithSource(errStr, types.ResolveStatus{},
+			rs.ErrorTime)
+		return true
+	}
+	status.HasResolverRef = false
+	deleteResolveConfig(ctx, rs.Key())
+	if foundSha != status.ContentSha256 {
+		errStr := fmt.Sprintf("Re-tag %s resolved to sha %s which differs from %s; needs a purge",
+			status.RelativeURL, foundSha, status.ContentSha256)
+		log.Error(errStr)
+		status.SetErrorWithSource(errStr, types.ResolveStatus{},
+			time.Now())
+		atomic.AddUint64(&ctx.retagMismatches, 1)
+		publishContentTreeMetrics(ctx)
+		return true
+	}
+	if status.IsErrorSource(types.ResolveStatus{}) {
+		log.Functionf("Clearing resolver error %s", status.Error)
+		status.ClearErrorWithSource()
+	}
+	status.RelativeURL = utils.MaybeInsertSha(status.RelativeURL, foundSha)
+	rootBlob := lookupBlobStatus(ctx, foundSha)
+	if rootBlob != nil {
+		rootBlob.RelativeURL = status.RelativeURL
+		publishBlobStatus(ctx, rootBlob)
+	}
+	hits := atomic.AddUint64(&ctx.retagHits, 1)
+	log.Noticef("finishContentTreeRetag(%s) renamed to %s with sha %s (%d re-tag hits)",
+		status.ContentID, status.RelativeURL, foundSha, hits)
+	publishContentTreeMetrics(ctx)
+	return true
+}
+
+func publishContentTreeMetrics(ctx *volumemgrContext) {
+	metrics := types.ContentTreeMetrics{
+		RetagHits:       atomic.LoadUint64(&ctx.retagHits),
+		RetagMismatches: atomic.LoadUint64(&ctx.retagMismatches),
+	}
+	ctx.pubContentTreeMetrics.Publish(metrics.Key(), metrics)
+}
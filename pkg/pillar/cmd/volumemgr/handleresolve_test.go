@@ -0,0 +1,157 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package volumemgr
+
+import (
+	"testing"
+
+	zconfig "github.com/lf-edge/eve/api/go/config"
+	"github.com/lf-edge/eve/pkg/pillar/base"
+	"github.com/lf-edge/eve/pkg/pillar/pubsub"
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func initRetagCtx(t *testing.T) *volumemgrContext {
+	ctx := &volumemgrContext{}
+	logger := logrus.StandardLogger()
+	log = base.NewSourceLogObject(logger, "test", 1234)
+	ps := pubsub.New(&pubsub.EmptyDriver{}, logger, log)
+
+	pubContentTreeStatus, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.ContentTreeStatus{},
+	})
+	assert.Nil(t, err)
+	ctx.pubContentTreeStatus = pubContentTreeStatus
+	pubResolveConfig, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.ResolveConfig{},
+	})
+	assert.Nil(t, err)
+	ctx.pubResolveConfig = pubResolveConfig
+	pubBlobStatus, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.BlobStatus{},
+	})
+	assert.Nil(t, err)
+	ctx.pubBlobStatus = pubBlobStatus
+	pubContentTreeMetrics, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.ContentTreeMetrics{},
+	})
+	assert.Nil(t, err)
+	ctx.pubContentTreeMetrics = pubContentTreeMetrics
+	return ctx
+}
+
+func TestContentTreeRetag(t *testing.T) {
+	const (
+		latchedSha = "8f2a7c8b1e3c9b0a7d3c41e4b6b7d9a0c1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6"
+		otherSha   = "1f2a7c8b1e3c9b0a7d3c41e4b6b7d9a0c1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6"
+	)
+	testMatrix := map[string]struct {
+		resolvedSha     string
+		resolveError    string
+		expectedURL     string
+		expectedError   bool
+		expectedHits    uint64
+		expectedMisses  uint64
+		expectedBlobURL string
+	}{
+		"Same sha": {
+			resolvedSha:     latchedSha,
+			expectedURL:     "docker.io/library/nginx@sha256:" + latchedSha,
+			expectedHits:    1,
+			expectedBlobURL: "docker.io/library/nginx@sha256:" + latchedSha,
+		},
+		"Same sha in upper case": {
+			resolvedSha:     "8F2A7C8B1E3C9B0A7D3C41E4B6B7D9A0C1E2F3A4B5C6D7E8F9A0B1C2D3E4F5A6",
+			expectedURL:     "docker.io/library/nginx@sha256:" + latchedSha,
+			expectedHits:    1,
+			expectedBlobURL: "docker.io/library/nginx@sha256:" + latchedSha,
+		},
+		"Different sha": {
+			resolvedSha:     otherSha,
+			expectedURL:     "docker.io/library/nginx:stable",
+			expectedError:   true,
+			expectedMisses:  1,
+			expectedBlobURL: "docker.io/library/nginx@sha256:" + latchedSha,
+		},
+		"Resolver error": {
+			resolveError:    "no such tag",
+			expectedURL:     "docker.io/library/nginx:stable",
+			expectedError:   true,
+			expectedBlobURL: "docker.io/library/nginx@sha256:" + latchedSha,
+		},
+	}
+
+	contentID := uuid.NewV4()
+	datastoreID := uuid.NewV4()
+	for testname, test := range testMatrix {
+		ctx := initRetagCtx(t)
+		blob := types.BlobStatus{
+			Sha256:      latchedSha,
+			RelativeURL: "docker.io/library/nginx@sha256:" + latchedSha,
+			State:       types.VERIFIED,
+		}
+		publishBlobStatus(ctx, &blob)
+		status := types.ContentTreeStatus{
+			ContentID:     contentID,
+			DatastoreID:   datastoreID,
+			DatastoreType: zconfig.DsType_DsContainerRegistry.String(),
+			RelativeURL:   "docker.io/library/nginx@sha256:" + latchedSha,
+			Format:        zconfig.Format_CONTAINER,
+			ContentSha256: latchedSha,
+			State:         types.VERIFIED,
+			Blobs:         []string{latchedSha},
+		}
+		publishContentTreeStatus(ctx, &status)
+
+		startContentTreeRetag(ctx, &status, "docker.io/library/nginx:stable")
+		assert.True(t, status.HasResolverRef, testname)
+		rc := lookupResolveConfig(ctx, status.ResolveKey())
+		assert.NotNil(t, rc, testname)
+
+		// Fake resolver
+		rs := types.ResolveStatus{
+			DatastoreID: datastoreID,
+			Name:        "docker.io/library/nginx:stable",
+			ImageSha256: test.resolvedSha,
+		}
+		if test.resolveError != "" {
+			rs.SetErrorNow(test.resolveError)
+		}
+		handleResolveStatusImpl(ctx, rs.Key(), rs)
+
+		updated := lookupContentTreeStatus(ctx, contentID.String())
+		assert.NotNil(t, updated, testname)
+		assert.Equal(t, test.expectedURL, updated.RelativeURL, testname)
+		assert.Equal(t, latchedSha, updated.ContentSha256, testname)
+		assert.Equal(t, types.VERIFIED, updated.State, testname)
+		assert.Equal(t, test.expectedError, updated.HasError(), testname)
+		assert.Equal(t, []string{latchedSha}, updated.Blobs, testname)
+		assert.Equal(t, test.expectedHits, ctx.retagHits, testname)
+		assert.Equal(t, test.expectedMisses, ctx.retagMismatches, testname)
+		item, _ := ctx.pubContentTreeMetrics.Get("global")
+		if test.expectedHits == 0 && test.expectedMisses == 0 {
+			assert.Nil(t, item, testname)
+		} else if assert.NotNil(t, item, testname) {
+			assert.Equal(t, test.expectedHits,
+				item.(types.ContentTreeMetrics).RetagHits, testname)
+			assert.Equal(t, test.expectedMisses,
+				item.(types.ContentTreeMetrics).RetagMismatches, testname)
+		}
+		updatedBlob := lookupBlobStatus(ctx, latchedSha)
+		assert.NotNil(t, updatedBlob, testname)
+		assert.Equal(t, types.VERIFIED, updatedBlob.State, testname)
+		assert.Equal(t, test.expectedBlobURL, updatedBlob.RelativeURL,
+			testname)
+		if test.resolveError == "" {
+			assert.Nil(t, lookupResolveConfig(ctx, rs.Key()), testname)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Retain the unused volumes listed in an AppVolumeRetention from zedagent.
+// Such volumes keep their VolumeStatus with Retained set, and are
+// reattached when the VolumeConfig comes back, or deleted once no
+// unexpired AppVolumeRetention lists them.
+
+package volumemgr
+
+import (
+	"time"
+
+	"github.com/lf-edge/eve/pkg/pillar/types"
+)
+
+func handleAppVolumeRetentionModify(ctxArg interface{}, key string,
+	configArg interface{}, oldConfigArg interface{}) {
+
+	log.Functionf("handleAppVolumeRetentionModify(%s)", key)
+	ctx := ctxArg.(*volumemgrContext)
+	checkRetainedVolumes(ctx, time.Now())
+	log.Functionf("handleAppVolumeRetentionModify(%s) Done", key)
+}
+
+func handleAppVolumeRetentionDelete(ctxArg interface{}, key string,
+	configArg interface{}) {
+
+	log.Functionf("handleAppVolumeRetentionDelete(%s)", key)
+	ctx := ctxArg.(*volumemgrContext)
+	checkRetainedVolumes(ctx, time.Now())
+	log.Functionf("handleAppVolumeRetentionDelete(%s) Done", key)
+}
+
+// isVolumeRetained returns true if an unexpired AppVolumeRetention lists
+// the volume with the key
+func isVolumeRetained(ctx *volumemgrContext, key string, now time.Time) bool {
+	if ctx.subAppVolumeRetention == nil {
+		return false
+	}
+	for _, c := range ctx.subAppVolumeRetention.GetAll() {
+		retention := c.(types.AppVolumeRetention)
+		if retention.HasVolume(key, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRetainedVolumes deletes the retained volumes which are no longer
+// listed in an unexpired AppVolumeRetention
+func checkRetainedVolumes(ctx *volumemgrContext, now time.Time) {
+	for _, status := range getAllVolumeStatus(ctx) {
+		if !status.Retained || status.RefCount != 0 {
+			continue
+		}
+		if isVolumeRetained(ctx, status.Key(), now) {
+			continue
+		}
+		log.Noticef("checkRetainedVolumes: no longer retaining volume %s (%s)",
+			status.Key(), status.DisplayName)
+		status.Retained = false
+		deleteVolume(ctx, status)
+	}
+}
+
+// reattachRetainedVolume is called when the VolumeConfig comes back for a
+// retained volume
+func reattachRetainedVolume(ctx *volumemgrContext, status *types.VolumeStatus,
+	config *types.VolumeConfig) {
+
+	log.Noticef("reattachRetainedVolume: reattaching volume %s (%s)",
+		status.Key(), config.DisplayName)
+	status.Retained = false
+	status.DisplayName = config.DisplayName
+	updateVolumeStatusRefCount(ctx, status)
+	publishVolumeStatus(ctx, status)
+	updateVolumeRefStatus(ctx, status)
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package volumemgr
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lf-edge/eve/pkg/pillar/base"
+	"github.com/lf-edge/eve/pkg/pillar/pubsub"
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func initRetentionCtx(t *testing.T) *volumemgrContext {
+	ctx := &volumemgrContext{}
+	logger := logrus.StandardLogger()
+	log = base.NewSourceLogObject(logger, "test", 1234)
+	ps := pubsub.New(&pubsub.EmptyDriver{}, logger, log)
+
+	pubVolumeStatus, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName:  agentName,
+		AgentScope: types.AppImgObj,
+		TopicType:  types.VolumeStatus{},
+	})
+	assert.Nil(t, err)
+	ctx.pubVolumeStatus = pubVolumeStatus
+	pubVolumeRefStatus, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName:  agentName,
+		AgentScope: types.AppImgObj,
+		TopicType:  types.VolumeRefStatus{},
+	})
+	assert.Nil(t, err)
+	ctx.pubVolumeRefStatus = pubVolumeRefStatus
+	pubAppDiskMetric, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.AppDiskMetric{},
+	})
+	assert.Nil(t, err)
+	ctx.pubAppDiskMetric = pubAppDiskMetric
+	ctx.subVolumeConfig, err = ps.NewSubscription(pubsub.SubscriptionOptions{
+		AgentName: "zedagent",
+		TopicImpl: types.VolumeConfig{},
+		Ctx:       ctx,
+	})
+	assert.Nil(t, err)
+	ctx.subVolumeRefConfig, err = ps.NewSubscription(pubsub.SubscriptionOptions{
+		AgentName:  "zedmanager",
+		AgentScope: types.AppImgObj,
+		TopicImpl:  types.VolumeRefConfig{},
+		Ctx:        ctx,
+	})
+	assert.Nil(t, err)
+	ctx.subAppVolumeRetention, err = ps.NewSubscription(pubsub.SubscriptionOptions{
+		AgentName: "zedagent",
+		TopicImpl: types.AppVolumeRetention{},
+		Ctx:       ctx,
+	})
+	assert.Nil(t, err)
+	return ctx
+}
+
+// modifySubscription makes the item appear in the subscription as if
+// published by the other agent
+func modifySubscription(t *testing.T, sub pubsub.Subscription, key string,
+	item interface{}) {
+
+	b, err := json.Marshal(item)
+	assert.Nil(t, err)
+	sub.ProcessChange(pubsub.Change{Operation: pubsub.Modify, Key: key,
+		Value: b})
+}
+
+func TestRetainedVolumeExpiry(t *testing.T) {
+	volumeID := uuid.NewV4()
+	appUUID := uuid.NewV4()
+	now := time.Now()
+	testMatrix := map[string]struct {
+		retention       *types.AppVolumeRetention
+		deleteRetention bool
+		checkTime       time.Time
+		expectRetained  bool
+		expectDeleted   bool
+	}{
+		"No retention": {
+			expectDeleted: true,
+		},
+		"App configured": {
+			retention:      &types.AppVolumeRetention{},
+			checkTime:      now.Add(365 * 24 * time.Hour),
+			expectRetained: true,
+		},
+		"Before expiry": {
+			retention:      &types.AppVolumeRetention{Expiry: now.Add(time.Hour)},
+			checkTime:      now.Add(time.Minute),
+			expectRetained: true,
+		},
+		"After expiry": {
+			retention:      &types.AppVolumeRetention{Expiry: now.Add(time.Hour)},
+			checkTime:      now.Add(2 * time.Hour),
+			expectRetained: true,
+			expectDeleted:  true,
+		},
+		"Retention deleted": {
+			retention:       &types.AppVolumeRetention{Expiry: now.Add(time.Hour)},
+			deleteRetention: true,
+			checkTime:       now.Add(time.Minute),
+			expectRetained:  true,
+			expectDeleted:   true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		ctx := initRetentionCtx(t)
+		status := &types.VolumeStatus{
+			VolumeID:    volumeID,
+			DisplayName: "data",
+			State:       types.CREATED_VOLUME,
+		}
+		publishVolumeStatus(ctx, status)
+		if test.retention != nil {
+			retention := *test.retention
+			retention.AppUUID = appUUID
+			retention.RetentionDays = 1
+			retention.VolumeKeys = []string{status.Key()}
+			modifySubscription(t, ctx.subAppVolumeRetention,
+				retention.Key(), retention)
+		}
+
+		// The volume is no longer used
+		maybeDeleteVolume(ctx, status)
+		vs := lookupVolumeStatus(ctx, status.Key())
+		assert.Equal(t, test.expectRetained, vs != nil, testname)
+		if vs != nil {
+			assert.True(t, vs.Retained, testname)
+		}
+
+		if test.deleteRetention {
+			ctx.subAppVolumeRetention.ProcessChange(pubsub.Change{
+				Operation: pubsub.Delete, Key: appUUID.String()})
+		}
+		checkRetainedVolumes(ctx, test.checkTime)
+		vs = lookupVolumeStatus(ctx, status.Key())
+		assert.Equal(t, test.expectDeleted, vs == nil, testname)
+	}
+}
+
+func TestRetainedVolumeReattach(t *testing.T) {
+	volumeID := uuid.NewV4()
+	appUUID := uuid.NewV4()
+	ctx := initRetentionCtx(t)
+	status := &types.VolumeStatus{
+		VolumeID:     volumeID,
+		DisplayName:  "data",
+		State:        types.CREATED_VOLUME,
+		FileLocation: "/persist/vault/volumes/data",
+	}
+	publishVolumeStatus(ctx, status)
+	retention := types.AppVolumeRetention{
+		AppUUID:       appUUID,
+		RetentionDays: 1,
+		VolumeKeys:    []string{status.Key()},
+		Expiry:        time.Now().Add(time.Hour),
+	}
+	modifySubscription(t, ctx.subAppVolumeRetention, retention.Key(),
+		retention)
+	maybeDeleteVolume(ctx, status)
+	vs := lookupVolumeStatus(ctx, status.Key())
+	assert.NotNil(t, vs)
+	assert.True(t, vs.Retained)
+
+	// The app instance and its volume are added back
+	config := types.VolumeConfig{
+		VolumeID:    volumeID,
+		DisplayName: "data-restored",
+		RefCount:    1,
+	}
+	modifySubscription(t, ctx.subVolumeConfig, config.Key(), config)
+	handleDeferredVolumeCreate(ctx, config.Key(), &config)
+	vs = lookupVolumeStatus(ctx, status.Key())
+	assert.NotNil(t, vs)
+	assert.False(t, vs.Retained)
+	assert.Equal(t, uint(1), vs.RefCount)
+	assert.Equal(t, "data-restored", vs.DisplayName)
+	assert.Equal(t, status.FileLocation, vs.FileLocation)
+
+	// No longer deleted when the retention expires
+	checkRetainedVolumes(ctx, time.Now().Add(2*time.Hour))
+	assert.NotNil(t, lookupVolumeStatus(ctx, status.Key()))
+}
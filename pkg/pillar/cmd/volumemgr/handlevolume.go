@@ -98,6 +98,10 @@ func handleDeferredVolumeCreate(ctx *volumemgrContext, key string, config *types
 	log.Tracef("handleDeferredVolumeCreate(%s)", key)
 	status := lookupVolumeStatus(ctx, config.Key())
 	if status != nil {
+		if status.Retained {
+			reattachRetainedVolume(ctx, status, config)
+			return
+		}
 		log.Fatalf("status exists at handleVolumeCreate for %s", config.Key())
 	}
 	status = &types.VolumeStatus{
@@ -294,6 +298,24 @@ func maybeDeleteVolume(ctx *volumemgrContext, status *types.VolumeStatus) {
 		log.Functionf("maybeDeleteVolume for %v Done", status.Key())
 		return
 	}
+	if isVolumeRetained(ctx, status.Key(), time.Now()) {
+		if !status.Retained {
+			log.Noticef("maybeDeleteVolume: retaining unused volume %s (%s)",
+				status.Key(), status.DisplayName)
+			status.Retained = true
+		}
+		publishVolumeStatus(ctx, status)
+		log.Functionf("maybeDeleteVolume for %v Done", status.Key())
+		return
+	}
+	deleteVolume(ctx, status)
+	log.Functionf("maybeDeleteVolume for %v Done", status.Key())
+}
+
+// deleteVolume deletes the unused volume
+func deleteVolume(ctx *volumemgrContext, status *types.VolumeStatus) {
+
+	log.Functionf("deleteVolume for %v", status.Key())
 	if status.SubState == types.VolumeSubStateCreated {
 		// Asynch destruction; make sure we have a request for the work
 		AddWorkDestroy(ctx, status)
@@ -329,7 +351,7 @@ func maybeDeleteVolume(ctx *volumemgrContext, status *types.VolumeStatus) {
 	if appDiskMetric := lookupAppDiskMetric(ctx, status.FileLocation); appDiskMetric != nil {
 		unpublishAppDiskMetrics(ctx, appDiskMetric)
 	}
-	log.Functionf("maybeDeleteVolume for %v Done", status.Key())
+	log.Functionf("deleteVolume for %v Done", status.Key())
 }
 
 // updateVolumeStatusRefCount updates the refcount in volume status
@@ -14,6 +14,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	zconfig "github.com/lf-edge/eve/api/go/config"
 	"github.com/lf-edge/eve/pkg/pillar/types"
@@ -62,6 +63,10 @@ func gcObjects(ctx *volumemgrContext, dirName string) {
 			continue
 		}
 		vs := lookupVolumeStatus(ctx, key)
+		if vs == nil && isVolumeRetained(ctx, key, time.Now()) {
+			log.Functionf("gcObjects: Found retained volume %s", filelocation)
+			continue
+		}
 		if vs == nil {
 			log.Functionf("gcObjects: Found unused volume %s. Deleting it.",
 				filelocation)
@@ -89,6 +94,10 @@ func gcDatasets(ctx *volumemgrContext, dataset string) {
 	for _, location := range locations {
 		key := types.ZVolNameToKey(location)
 		vs := lookupVolumeStatus(ctx, key)
+		if vs == nil && isVolumeRetained(ctx, key, time.Now()) {
+			log.Functionf("gcDatasets: Found retained volume %s", location)
+			continue
+		}
 		if vs == nil {
 			log.Functionf("gcDatasets: Found unused volume %s. Deleting it.",
 				location)
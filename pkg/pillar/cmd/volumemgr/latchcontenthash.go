@@ -7,20 +7,25 @@
 package volumemgr
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/lf-edge/eve/pkg/pillar/types"
 	"github.com/lf-edge/eve/pkg/pillar/utils"
 	uuid "github.com/satori/go.uuid"
 )
 
-// Add or update
+// Add. A different hash which is already latched for the contentID and
+// generationCounter is not overwritten; an error is returned instead.
 func latchContentTreeHash(ctx *volumemgrContext, contentID uuid.UUID,
-	hash string, generationCounter uint32) {
+	hash string, generationCounter uint32) error {
 
 	log.Functionf("latchContentTreeHash(%s, %s, %d)", contentID, hash, generationCounter)
 	if hash == "" {
 		log.Errorf("latchContentTreeHash(%s, %d) empty hash",
 			contentID, generationCounter)
-		return
+		return nil
 	}
 	aih := types.AppAndImageToHash{
 		ImageID:      contentID,
@@ -33,13 +38,14 @@ func latchContentTreeHash(ctx *volumemgrContext, contentID uuid.UUID,
 		if old.Hash == aih.Hash {
 			log.Warnf("latchContentTreeHash(%s, %d) no change %s",
 				contentID, generationCounter, old.Hash)
-			return
+			return nil
 		}
-		log.Warnf("latchContentTreeHash(%s, %d) change from %s to %s",
+		return fmt.Errorf("content tree %s generation %d is already latched to sha %s; not changing it to %s",
 			contentID, generationCounter, old.Hash, aih.Hash)
 	}
 	ctx.pubContentTreeToHash.Publish(aih.Key(), aih)
 	log.Functionf("latchContentTreeHash(%s, %s, %d) done", contentID, hash, generationCounter)
+	return nil
 }
 
 // Delete for a specific content tree
@@ -77,6 +83,49 @@ func purgeLatchContentTreeHash(ctx *volumemgrContext, contentID uuid.UUID) {
 	log.Functionf("purgeLatchContentTreeHash(%s) done", contentID)
 }
 
+// Purge all for appUUID. The content trees latched by volumemgr are not
+// specific to an app and have a nil AppUUID, hence a nil appUUID purges
+// nothing.
+func purgeLatchContentTreeHashesForApp(ctx *volumemgrContext, appUUID uuid.UUID) {
+
+	log.Functionf("purgeLatchContentTreeHashesForApp(%s)", appUUID)
+	if appUUID == uuid.Nil {
+		log.Tracef("purgeLatchContentTreeHashesForApp: nil appUUID")
+		return
+	}
+	items := ctx.pubContentTreeToHash.GetAll()
+	for _, a := range items {
+		aih := a.(types.AppAndImageToHash)
+		if aih.AppUUID == appUUID {
+			log.Noticef("purgeLatchContentTreeHashesForApp(%s) deleting %s hash %s",
+				appUUID, aih.ImageID, aih.Hash)
+			ctx.pubContentTreeToHash.Unpublish(aih.Key())
+		}
+	}
+	log.Functionf("purgeLatchContentTreeHashesForApp(%s) done", appUUID)
+}
+
+// Purge all for the apps which latched contentID. Such entries are left
+// from when the hashes were latched per app, and lookupLatchContentTreeHash
+// no longer uses them, hence the other content trees of those apps are not
+// affected.
+func purgeLatchContentTreeHashesForContentApps(ctx *volumemgrContext,
+	contentID uuid.UUID) {
+
+	log.Functionf("purgeLatchContentTreeHashesForContentApps(%s)", contentID)
+	appUUIDs := make(map[uuid.UUID]bool)
+	for _, a := range ctx.pubContentTreeToHash.GetAll() {
+		aih := a.(types.AppAndImageToHash)
+		if aih.ImageID == contentID && aih.AppUUID != uuid.Nil {
+			appUUIDs[aih.AppUUID] = true
+		}
+	}
+	for appUUID := range appUUIDs {
+		purgeLatchContentTreeHashesForApp(ctx, appUUID)
+	}
+	log.Functionf("purgeLatchContentTreeHashesForContentApps(%s) done", contentID)
+}
+
 // Returns "" string if not found
 func lookupLatchContentTreeHash(ctx *volumemgrContext,
 	contentID uuid.UUID, generationCounter uint32) string {
@@ -98,6 +147,27 @@ func lookupLatchContentTreeHash(ctx *volumemgrContext,
 	return aih.Hash
 }
 
+// Returns all the latched entries for the sha, sorted by key, e.g., to
+// tell when nothing refers to a blob any more
+func lookupLatchContentTreeHashesBySha(ctx *volumemgrContext,
+	sha string) []types.AppAndImageToHash {
+
+	log.Tracef("lookupLatchContentTreeHashesBySha(%s)", sha)
+	var found []types.AppAndImageToHash
+	for _, a := range ctx.pubContentTreeToHash.GetAll() {
+		aih := a.(types.AppAndImageToHash)
+		if strings.EqualFold(aih.Hash, sha) {
+			found = append(found, aih)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Key() < found[j].Key()
+	})
+	log.Tracef("lookupLatchContentTreeHashesBySha(%s) found %d",
+		sha, len(found))
+	return found
+}
+
 // Can update status
 func maybeLatchContentTreeHash(ctx *volumemgrContext, status *types.ContentTreeStatus) {
 
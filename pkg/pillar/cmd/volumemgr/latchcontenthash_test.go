@@ -0,0 +1,203 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package volumemgr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lf-edge/eve/pkg/pillar/base"
+	"github.com/lf-edge/eve/pkg/pillar/pubsub"
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatchContentTreeHash(t *testing.T) {
+	const (
+		latchedSha = "8f2a7c8b1e3c9b0a7d3c41e4b6b7d9a0c1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6"
+		otherSha   = "1f2a7c8b1e3c9b0a7d3c41e4b6b7d9a0c1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6"
+	)
+	testMatrix := map[string]struct {
+		generationCounter uint32
+		hash              string
+		expectedError     bool
+		expectedHash      string
+	}{
+		"No change": {
+			generationCounter: 1,
+			hash:              latchedSha,
+			expectedHash:      latchedSha,
+		},
+		"Different sha": {
+			generationCounter: 1,
+			hash:              otherSha,
+			expectedError:     true,
+			expectedHash:      latchedSha,
+		},
+		"Different sha of another generation": {
+			generationCounter: 2,
+			hash:              otherSha,
+			expectedHash:      otherSha,
+		},
+		"Empty sha": {
+			generationCounter: 2,
+			expectedHash:      "",
+		},
+	}
+	contentID := uuid.NewV4()
+	for testname, test := range testMatrix {
+		ctx := &volumemgrContext{}
+		logger := logrus.StandardLogger()
+		log = base.NewSourceLogObject(logger, "test", 1234)
+		ps := pubsub.New(&pubsub.EmptyDriver{}, logger, log)
+		pubContentTreeToHash, err := ps.NewPublication(pubsub.PublicationOptions{
+			AgentName: agentName,
+			TopicType: types.AppAndImageToHash{},
+		})
+		assert.Nil(t, err)
+		ctx.pubContentTreeToHash = pubContentTreeToHash
+		assert.Nil(t, latchContentTreeHash(ctx, contentID, latchedSha, 1))
+
+		err = latchContentTreeHash(ctx, contentID, test.hash,
+			test.generationCounter)
+		if test.expectedError {
+			assert.NotNil(t, err, testname)
+		} else {
+			assert.Nil(t, err, testname)
+		}
+		assert.Equal(t, test.expectedHash, lookupLatchContentTreeHash(ctx,
+			contentID, test.generationCounter), testname)
+		assert.Equal(t, latchedSha, lookupLatchContentTreeHash(ctx,
+			contentID, 1), testname)
+	}
+}
+
+func TestLookupLatchContentTreeHashesBySha(t *testing.T) {
+	const (
+		shaA = "8f2a7c8b1e3c9b0a7d3c41e4b6b7d9a0c1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6"
+		shaB = "1f2a7c8b1e3c9b0a7d3c41e4b6b7d9a0c1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6"
+	)
+	contentID1 := uuid.NewV4()
+	contentID2 := uuid.NewV4()
+	ctx := &volumemgrContext{}
+	logger := logrus.StandardLogger()
+	log = base.NewSourceLogObject(logger, "test", 1234)
+	ps := pubsub.New(&pubsub.EmptyDriver{}, logger, log)
+	pubContentTreeToHash, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.AppAndImageToHash{},
+	})
+	assert.Nil(t, err)
+	ctx.pubContentTreeToHash = pubContentTreeToHash
+	lookup := func(sha string) []uint32 {
+		var generations []uint32
+		for _, aih := range lookupLatchContentTreeHashesBySha(ctx, sha) {
+			assert.Equal(t, shaA, aih.Hash)
+			generations = append(generations, aih.PurgeCounter)
+		}
+		return generations
+	}
+
+	assert.Empty(t, lookup(shaA))
+	assert.Nil(t, latchContentTreeHash(ctx, contentID1, shaA, 1))
+	assert.Nil(t, latchContentTreeHash(ctx, contentID1, shaA, 2))
+	assert.Nil(t, latchContentTreeHash(ctx, contentID2, shaA, 1))
+	assert.Nil(t, latchContentTreeHash(ctx, contentID2, shaB, 2))
+	assert.Equal(t, 3, len(lookupLatchContentTreeHashesBySha(ctx, shaA)))
+	assert.Equal(t, 3, len(lookupLatchContentTreeHashesBySha(ctx,
+		strings.ToUpper(shaA))))
+	assert.Equal(t, 1, len(lookupLatchContentTreeHashesBySha(ctx, shaB)))
+
+	deleteLatchContentTreeHash(ctx, contentID1, 2)
+	assert.Equal(t, []uint32{1, 1}, lookup(shaA))
+
+	purgeLatchContentTreeHash(ctx, contentID2)
+	assert.Equal(t, []uint32{1}, lookup(shaA))
+	assert.Empty(t, lookupLatchContentTreeHashesBySha(ctx, shaB))
+
+	purgeLatchContentTreeHash(ctx, contentID1)
+	assert.Empty(t, lookup(shaA))
+}
+
+func TestPurgeLatchContentTreeHashesForApp(t *testing.T) {
+	contentID := uuid.NewV4()
+	appUUID1 := uuid.NewV4()
+	appUUID2 := uuid.NewV4()
+	ctx := &volumemgrContext{}
+	logger := logrus.StandardLogger()
+	log = base.NewSourceLogObject(logger, "test", 1234)
+	ps := pubsub.New(&pubsub.EmptyDriver{}, logger, log)
+	pubContentTreeToHash, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.AppAndImageToHash{},
+	})
+	assert.Nil(t, err)
+	ctx.pubContentTreeToHash = pubContentTreeToHash
+
+	latches := []types.AppAndImageToHash{
+		{AppUUID: appUUID1, ImageID: contentID, Hash: "sha1"},
+		{AppUUID: appUUID1, ImageID: contentID, Hash: "sha2",
+			PurgeCounter: 1},
+		{AppUUID: appUUID2, ImageID: contentID, Hash: "sha3"},
+	}
+	for _, aih := range latches {
+		assert.Nil(t, pubContentTreeToHash.Publish(aih.Key(), aih))
+	}
+	// Latched by volumemgr with a nil AppUUID
+	assert.Nil(t, latchContentTreeHash(ctx, contentID, "sha4", 1))
+
+	purgeLatchContentTreeHashesForApp(ctx, uuid.Nil)
+	assert.Equal(t, 4, len(pubContentTreeToHash.GetAll()))
+
+	purgeLatchContentTreeHashesForApp(ctx, appUUID1)
+	items := pubContentTreeToHash.GetAll()
+	assert.Equal(t, 2, len(items))
+	for _, item := range items {
+		assert.NotEqual(t, appUUID1, item.(types.AppAndImageToHash).AppUUID)
+	}
+	assert.Equal(t, "sha4", lookupLatchContentTreeHash(ctx, contentID, 1))
+
+	// The purge for a content tree is not limited to an app
+	purgeLatchContentTreeHash(ctx, contentID)
+	assert.Empty(t, pubContentTreeToHash.GetAll())
+}
+
+func TestPurgeLatchContentTreeHashesForContentApps(t *testing.T) {
+	contentID1 := uuid.NewV4()
+	contentID2 := uuid.NewV4()
+	appUUID1 := uuid.NewV4()
+	appUUID2 := uuid.NewV4()
+	ctx := &volumemgrContext{}
+	logger := logrus.StandardLogger()
+	log = base.NewSourceLogObject(logger, "test", 1234)
+	ps := pubsub.New(&pubsub.EmptyDriver{}, logger, log)
+	pubContentTreeToHash, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.AppAndImageToHash{},
+	})
+	assert.Nil(t, err)
+	ctx.pubContentTreeToHash = pubContentTreeToHash
+
+	latches := []types.AppAndImageToHash{
+		{AppUUID: appUUID1, ImageID: contentID1, Hash: "sha1"},
+		{AppUUID: appUUID1, ImageID: contentID2, Hash: "sha2"},
+		{AppUUID: appUUID2, ImageID: contentID2, Hash: "sha3"},
+	}
+	for _, aih := range latches {
+		assert.Nil(t, pubContentTreeToHash.Publish(aih.Key(), aih))
+	}
+	assert.Nil(t, latchContentTreeHash(ctx, contentID1, "sha4", 0))
+
+	// Only the app which latched contentID1 is purged, and the entry
+	// latched by volumemgr is kept
+	purgeLatchContentTreeHashesForContentApps(ctx, contentID1)
+	items := pubContentTreeToHash.GetAll()
+	assert.Equal(t, 2, len(items))
+	for _, item := range items {
+		assert.NotEqual(t, appUUID1, item.(types.AppAndImageToHash).AppUUID)
+	}
+	assert.Equal(t, "sha4", lookupLatchContentTreeHash(ctx, contentID1, 0))
+}
@@ -69,14 +69,20 @@ func doUpdateContentTree(ctx *volumemgrContext, status *types.ContentTreeStatus)
 					changed = true
 				}
 				foundSha := strings.ToLower(rs.ImageSha256)
+				if err := latchContentTreeHash(ctx, status.ContentID,
+					foundSha, uint32(status.GenerationCounter)); err != nil {
+					log.Error(err)
+					status.SetErrorWithSource(err.Error(),
+						types.ResolveStatus{}, time.Now())
+					changed = true
+					return changed, false
+				}
 				log.Functionf("Added Image SHA (%s) for content tree (%s)",
 					foundSha, status.ContentID)
 				status.State = types.RESOLVED_TAG
 				status.ContentSha256 = foundSha
 				status.HasResolverRef = false
 				status.RelativeURL = utils.MaybeInsertSha(status.RelativeURL, status.ContentSha256)
-				latchContentTreeHash(ctx, status.ContentID,
-					status.ContentSha256, uint32(status.GenerationCounter))
 				maybeLatchContentTreeHash(ctx, status)
 				deleteResolveConfig(ctx, rs.Key())
 				changed = true
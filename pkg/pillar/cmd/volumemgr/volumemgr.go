@@ -9,6 +9,7 @@ package volumemgr
 import (
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
@@ -65,9 +66,11 @@ type volumemgrContext struct {
 	pubContentTreeToHash    pubsub.Publication
 	pubBlobStatus           pubsub.Publication
 	pubDiskMetric           pubsub.Publication
+	pubContentTreeMetrics   pubsub.Publication
 	pubAppDiskMetric        pubsub.Publication
 	subDatastoreConfig      pubsub.Subscription
 	subZVolStatus           pubsub.Subscription
+	subAppVolumeRetention   pubsub.Subscription
 	diskMetricsTickerHandle interface{}
 	gc                      *time.Ticker
 	deferDelete             *time.Ticker
@@ -91,6 +94,14 @@ type volumemgrContext struct {
 	volumeConfigCreateDeferredMap map[string]*types.VolumeConfig
 
 	persistType types.PersistType
+
+	debugHTTPServer *http.Server // Set when debug.enable.volumemgr.http
+
+	// Number of content trees re-tagged to the already latched sha, and
+	// to a different sha. Accessed using sync/atomic since read by the
+	// debug http server. Published in ContentTreeMetrics.
+	retagHits       uint64
+	retagMismatches uint64
 }
 
 var debug = false
@@ -250,6 +261,22 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 	}
 	ctx.pubDiskMetric = pubDiskMetric
 
+	pubContentTreeMetrics, err := ps.NewPublication(
+		pubsub.PublicationOptions{
+			AgentName: agentName,
+			TopicType: types.ContentTreeMetrics{},
+		},
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx.pubContentTreeMetrics = pubContentTreeMetrics
+	// Continue the counts of this boot if volumemgr restarted
+	if m, _ := pubContentTreeMetrics.Get("global"); m != nil {
+		ctx.retagHits = m.(types.ContentTreeMetrics).RetagHits
+		ctx.retagMismatches = m.(types.ContentTreeMetrics).RetagMismatches
+	}
+
 	pubAppDiskMetric, err := ps.NewPublication(
 		pubsub.PublicationOptions{
 			AgentName: agentName,
@@ -438,6 +465,23 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 	ctx.subZVolStatus = subZVolStatus
 	subZVolStatus.Activate()
 
+	subAppVolumeRetention, err := ps.NewSubscription(pubsub.SubscriptionOptions{
+		ModifyHandler: handleAppVolumeRetentionModify,
+		DeleteHandler: handleAppVolumeRetentionDelete,
+		WarningTime:   warningTime,
+		ErrorTime:     errorTime,
+		AgentName:     "zedagent",
+		MyAgentName:   agentName,
+		TopicImpl:     types.AppVolumeRetention{},
+		Persistent:    true,
+		Ctx:           &ctx,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx.subAppVolumeRetention = subAppVolumeRetention
+	subAppVolumeRetention.Activate()
+
 	// Pick up debug aka log level before we start real work
 	for !ctx.GCInitialized {
 		log.Functionf("waiting for GCInitialized")
@@ -557,8 +601,12 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 		case change := <-ctx.subZVolStatus.MsgChan():
 			ctx.subZVolStatus.ProcessChange(change)
 
+		case change := <-ctx.subAppVolumeRetention.MsgChan():
+			ctx.subAppVolumeRetention.ProcessChange(change)
+
 		case <-ctx.gc.C:
 			start := time.Now()
+			checkRetainedVolumes(&ctx, start)
 			gcObjects(&ctx, volumeEncryptedDirName)
 			gcObjects(&ctx, volumeClearDirName)
 			gcDatasets(&ctx, types.VolumeZFSPool)
@@ -643,6 +691,7 @@ func handleGlobalConfigDelete(ctxArg interface{}, key string,
 	debug, _ = agentlog.HandleGlobalConfig(log, ctx.subGlobalConfig, agentName,
 		debugOverride, logger)
 	*ctx.globalConfig = *types.DefaultConfigItemValueMap()
+	stopDebugHTTPServer(ctx)
 	log.Functionf("handleGlobalConfigDelete done for %s", key)
 }
 
@@ -719,4 +768,15 @@ func maybeUpdateConfigItems(ctx *volumemgrContext, newConfigItemValueMap *types.
 			ctx.deferDelete = time.NewTicker(duration * time.Second)
 		}
 	}
+	newDH := newConfigItemValueMap.GlobalValueBool(types.VolumemgrDebugHTTP)
+	oldDH := oldConfigItemValueMap.GlobalValueBool(types.VolumemgrDebugHTTP)
+	if newDH != oldDH {
+		log.Noticef("maybeUpdateConfigItems: Updating debug http from %t to %t",
+			oldDH, newDH)
+		if newDH {
+			startDebugHTTPServer(ctx)
+		} else {
+			stopDebugHTTPServer(ctx)
+		}
+	}
 }
@@ -403,7 +403,7 @@ func scanAIConfigs(ctx *wstunnelclientContext) {
 			}
 
 			proxyURL, _ := zedcloud.LookupProxy(log, deviceNetworkStatus,
-				ifname, destURL)
+				ifname, destURL, nil)
 			if err := wstunnelclient.TestConnection(deviceNetworkStatus, proxyURL, localAddr, ctx.devUUID); err != nil {
 				log.Function(err)
 				continue
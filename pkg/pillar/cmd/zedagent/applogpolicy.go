@@ -0,0 +1,86 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Parse the log policy of the app instances. The valid non-empty policies
+// are published as AppLogPolicyConfig for the log shipper, which applies
+// them. An invalid policy is recorded with its error in the
+// AppInstanceConfig and not applied, without affecting the app instance.
+
+package zedagent
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	zconfig "github.com/lf-edge/eve/api/go/config"
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	"github.com/sirupsen/logrus"
+)
+
+// parseAppLogPolicy parses and validates the log policy of an app instance
+func parseAppLogPolicy(cfgPolicy *zconfig.AppLogPolicy,
+	displayName string) types.AppLogPolicy {
+
+	policy := types.AppLogPolicy{
+		MinSeverity:   cfgPolicy.GetMinSeverity(),
+		DropRegexes:   cfgPolicy.GetDropRegex(),
+		SamplePercent: cfgPolicy.GetSamplePercent(),
+	}
+	var errStrs []string
+	if policy.MinSeverity != "" {
+		if _, err := logrus.ParseLevel(policy.MinSeverity); err != nil {
+			errStrs = append(errStrs, fmt.Sprintf("bad min severity: %s",
+				err))
+		}
+	}
+	if len(policy.DropRegexes) > types.MaxAppLogDropRegexes {
+		errStrs = append(errStrs, fmt.Sprintf("%d drop regexes exceed the maximum of %d",
+			len(policy.DropRegexes), types.MaxAppLogDropRegexes))
+	}
+	for _, re := range policy.DropRegexes {
+		if _, err := regexp.Compile(re); err != nil {
+			errStrs = append(errStrs, fmt.Sprintf("bad drop regex %q: %s",
+				re, err))
+		}
+	}
+	if policy.SamplePercent > 100 {
+		errStrs = append(errStrs, fmt.Sprintf("sample percent %d above 100",
+			policy.SamplePercent))
+	}
+	if len(errStrs) != 0 {
+		errStr := fmt.Sprintf("app %s: log policy not applied: %s",
+			displayName, strings.Join(errStrs, "; "))
+		log.Error(errStr)
+		policy.SetErrorNow(errStr)
+	}
+	return policy
+}
+
+// publishAppLogPolicyConfig publishes the log policy of the app instance
+// for the log shipper, or unpublishes it if it is empty or invalid
+func publishAppLogPolicyConfig(getconfigCtx *getconfigContext,
+	config types.AppInstanceConfig) {
+
+	key := config.UUIDandVersion.UUID.String()
+	if config.LogPolicy.IsEmpty() || config.LogPolicy.HasError() {
+		unpublishAppLogPolicyConfig(getconfigCtx, key)
+		return
+	}
+	policyConfig := types.AppLogPolicyConfig{
+		AppUUID:     config.UUIDandVersion.UUID,
+		DisplayName: config.DisplayName,
+		Policy:      config.LogPolicy,
+	}
+	getconfigCtx.pubAppLogPolicyConfig.Publish(key, policyConfig)
+}
+
+// unpublishAppLogPolicyConfig unpublishes the log policy of the app
+// instance if there is one
+func unpublishAppLogPolicyConfig(getconfigCtx *getconfigContext, key string) {
+	pub := getconfigCtx.pubAppLogPolicyConfig
+	if c, _ := pub.Get(key); c != nil {
+		log.Functionf("unpublishAppLogPolicyConfig: %s", key)
+		pub.Unpublish(key)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Canary app instance for base OS updates.
+// The controller can name an app instance in the activated BaseOsConfig
+// which must be healthy while the new base OS is tested. The verdict is
+// reported in ZedAgentStatus, and nodeagent only marks the test complete if
+// the canary is healthy at the end of the test; otherwise it reboots to
+// fall back to the previous base OS. Once the canary is unhealthy during
+// the test the verdict stays unhealthy.
+
+package zedagent
+
+import (
+	"fmt"
+
+	zconfig "github.com/lf-edge/eve/api/go/config"
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	uuid "github.com/satori/go.uuid"
+)
+
+// configAppUUIDs returns the UUIDs of the app instances in the config
+func configAppUUIDs(config *zconfig.EdgeDevConfig) []string {
+	var appUUIDs []string
+	for _, app := range config.GetApps() {
+		appUUIDs = append(appUUIDs, app.GetUuidandversion().GetUuid())
+	}
+	return appUUIDs
+}
+
+// parseCanaryAppUUID checks that the canary app instance, if any, is in
+// the config
+func parseCanaryAppUUID(canary string,
+	config *zconfig.EdgeDevConfig) (uuid.UUID, error) {
+
+	if canary == "" {
+		return nilUUID, nil
+	}
+	canaryUUID, err := uuid.FromString(canary)
+	if err != nil {
+		return nilUUID, fmt.Errorf("malformed UUID %s: %v", canary, err)
+	}
+	for _, appUUID := range configAppUUIDs(config) {
+		if appUUID == canaryUUID.String() {
+			return canaryUUID, nil
+		}
+	}
+	return nilUUID, fmt.Errorf("app instance %s not in config", canary)
+}
+
+// getCanaryAppUUID returns the canary app instance of the activated base
+// OS, or nilUUID if none
+func getCanaryAppUUID(getconfigCtx *getconfigContext) uuid.UUID {
+	for _, c := range getconfigCtx.pubBaseOsConfig.GetAll() {
+		config := c.(types.BaseOsConfig)
+		if config.Activate && !config.HasError() &&
+			config.CanaryAppUUID != nilUUID {
+			return config.CanaryAppUUID
+		}
+	}
+	return nilUUID
+}
+
+// getCanaryVerdict returns the current health of the canary app instance
+// while a base OS update is tested
+func getCanaryVerdict(getconfigCtx *getconfigContext,
+	canaryAppUUID uuid.UUID) types.CanaryVerdict {
+
+	if !getconfigCtx.updateInprogress || canaryAppUUID == nilUUID {
+		return types.CanaryVerdictNone
+	}
+	c, _ := getconfigCtx.subAppInstanceStatus.Get(canaryAppUUID.String())
+	if c == nil {
+		return types.CanaryVerdictMissing
+	}
+	status := c.(types.AppInstanceStatus)
+	if status.HasError() {
+		return types.CanaryVerdictUnhealthy
+	}
+	switch status.State {
+	case types.RUNNING:
+		return types.CanaryVerdictHealthy
+	case types.HALTING, types.HALTED, types.BROKEN, types.UNKNOWN:
+		return types.CanaryVerdictUnhealthy
+	default:
+		return types.CanaryVerdictPending
+	}
+}
+
+// updateCanaryVerdict publishes ZedAgentStatus when the canary verdict
+// changes
+func updateCanaryVerdict(getconfigCtx *getconfigContext) {
+	canaryAppUUID := getCanaryAppUUID(getconfigCtx)
+	verdict := getCanaryVerdict(getconfigCtx, canaryAppUUID)
+	if verdict == types.CanaryVerdictNone {
+		canaryAppUUID = nilUUID
+	}
+	if getconfigCtx.canaryVerdict == types.CanaryVerdictUnhealthy &&
+		getconfigCtx.canaryAppUUID == canaryAppUUID &&
+		verdict != types.CanaryVerdictNone {
+		// Stays unhealthy for the rest of the test
+		verdict = types.CanaryVerdictUnhealthy
+	}
+	if getconfigCtx.canaryAppUUID == canaryAppUUID &&
+		getconfigCtx.canaryVerdict == verdict {
+		return
+	}
+	log.Noticef("updateCanaryVerdict: canary app %s verdict %s",
+		canaryAppUUID, verdict)
+	getconfigCtx.canaryAppUUID = canaryAppUUID
+	getconfigCtx.canaryVerdict = verdict
+	publishZedAgentStatus(getconfigCtx)
+}
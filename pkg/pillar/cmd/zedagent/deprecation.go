@@ -0,0 +1,144 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Track the use of deprecated fields, or deprecated semantics of fields, in
+// the config so that we know when the compatibility code can be removed.
+// Each compatibility branch in the parsers records a hit on its field with
+// the key of the object. The hits are aggregated per parse run and reported
+// in the info and metrics.
+
+package zedagent
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Maximum number of object keys kept per field and parse run
+const maxDeprecatedFieldKeys = 16
+
+// deprecatedField counts the hits for one deprecated field. The counters
+// are atomic since hit is called in the parsers; the keys are protected by
+// deprecatedFieldsLock.
+type deprecatedField struct {
+	name        string
+	runHits     uint64 // Hits in the current parse run
+	lastRunHits uint64 // Hits in the last completed parse run
+	totalHits   uint64 // Hits since zedagent started
+	runKeys     []string
+	lastRunKeys []string
+}
+
+var (
+	deprecatedSystemAdapterUplink = &deprecatedField{
+		name: "SystemAdapter.uplink"}
+	deprecatedSystemAdapterFreeUplink = &deprecatedField{
+		name: "SystemAdapter.freeUplink"}
+	deprecatedPhyIOFreeUplink = &deprecatedField{
+		name: "PhyIOUsagePolicy.freeUplink"}
+	deprecatedNetworkNoopIpspec = &deprecatedField{
+		name: "NetworkConfig.ip with type NT_NOOP"}
+	deprecatedIntercertsurl = &deprecatedField{
+		name: "SignatureInfo.intercertsurl"}
+	deprecatedOverlayAddr = &deprecatedField{
+		name: "NetworkAdapter.addr with EID"}
+	deprecatedOverlayCryptoEid = &deprecatedField{
+		name: "NetworkAdapter.cryptoEid"}
+	deprecatedOverlayLispSignature = &deprecatedField{
+		name: "NetworkAdapter.lispsignature"}
+	deprecatedOverlayPemCert = &deprecatedField{
+		name: "NetworkAdapter.pemcert"}
+	deprecatedOverlayPemPrivateKey = &deprecatedField{
+		name: "NetworkAdapter.pemprivatekey"}
+)
+
+// deprecatedFields is the registry of the deprecated fields. Add an entry
+// here for a new one.
+var deprecatedFields = []*deprecatedField{
+	deprecatedSystemAdapterUplink,
+	deprecatedSystemAdapterFreeUplink,
+	deprecatedPhyIOFreeUplink,
+	deprecatedNetworkNoopIpspec,
+	deprecatedIntercertsurl,
+	deprecatedOverlayAddr,
+	deprecatedOverlayCryptoEid,
+	deprecatedOverlayLispSignature,
+	deprecatedOverlayPemCert,
+	deprecatedOverlayPemPrivateKey,
+}
+
+var deprecatedFieldsLock sync.Mutex
+
+// hit records a use of the field by the object with the key
+func (field *deprecatedField) hit(key string) {
+	atomic.AddUint64(&field.runHits, 1)
+	atomic.AddUint64(&field.totalHits, 1)
+	log.Functionf("deprecated field %s used by %s", field.name, key)
+	deprecatedFieldsLock.Lock()
+	if len(field.runKeys) < maxDeprecatedFieldKeys {
+		field.runKeys = append(field.runKeys, key)
+	}
+	deprecatedFieldsLock.Unlock()
+}
+
+// deprecatedFieldStatus is a snapshot of the hits for one field
+type deprecatedFieldStatus struct {
+	name        string
+	lastRunHits uint64
+	totalHits   uint64
+	keys        []string
+}
+
+// startDeprecatedFieldRun clears the hits of the current parse run
+func startDeprecatedFieldRun() {
+	deprecatedFieldsLock.Lock()
+	defer deprecatedFieldsLock.Unlock()
+	for _, field := range deprecatedFields {
+		atomic.StoreUint64(&field.runHits, 0)
+		field.runKeys = nil
+	}
+}
+
+// endDeprecatedFieldRun makes the hits of the current parse run the ones
+// reported, and logs them
+func endDeprecatedFieldRun() {
+	var used []string
+	deprecatedFieldsLock.Lock()
+	for _, field := range deprecatedFields {
+		hits := atomic.LoadUint64(&field.runHits)
+		atomic.StoreUint64(&field.lastRunHits, hits)
+		field.lastRunKeys = field.runKeys
+		field.runKeys = nil
+		if hits != 0 {
+			used = append(used, fmt.Sprintf("%s (%d)", field.name, hits))
+		}
+	}
+	deprecatedFieldsLock.Unlock()
+	if len(used) != 0 {
+		log.Noticef("endDeprecatedFieldRun: config uses deprecated fields: %s",
+			strings.Join(used, ", "))
+	}
+}
+
+// getDeprecatedFieldStatus returns the fields which have been used since
+// zedagent started
+func getDeprecatedFieldStatus() []deprecatedFieldStatus {
+	var status []deprecatedFieldStatus
+	deprecatedFieldsLock.Lock()
+	defer deprecatedFieldsLock.Unlock()
+	for _, field := range deprecatedFields {
+		totalHits := atomic.LoadUint64(&field.totalHits)
+		if totalHits == 0 {
+			continue
+		}
+		status = append(status, deprecatedFieldStatus{
+			name:        field.name,
+			lastRunHits: atomic.LoadUint64(&field.lastRunHits),
+			totalHits:   totalHits,
+			keys:        append([]string{}, field.lastRunKeys...),
+		})
+	}
+	return status
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Regression tests which parse the EdgeDevConfig fixtures in
+// testdata/parseconfig and compare what is published with the golden
+// files next to them. After a deliberate change in the parsing run
+//	go test ./cmd/zedagent -run TestParseConfigGolden -update
+// or make update-golden, and review the diff of the golden files.
+
+package zedagent
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	zconfig "github.com/lf-edge/eve/api/go/config"
+	"github.com/lf-edge/eve/pkg/pillar/pubsub"
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+var updateGolden = flag.Bool("update", false,
+	"update the golden files of TestParseConfigGolden")
+
+const goldenDir = "testdata/parseconfig"
+
+// Fields whose values are replaced in the golden files
+var goldenRedactedFields = map[string]bool{
+	"ApiKey":     true,
+	"Credential": true,
+	"Password":   true,
+	"VncPasswd":  true,
+}
+
+// resetPrevConfigHashes makes the next parse start from scratch
+func resetPrevConfigHashes() {
+	baseOSPrevConfigHash = nil
+	baseOSConfigPrevConfigHash = nil
+	networkConfigPrevConfigHash = nil
+	networkInstancePrevConfigHash = nil
+	appinstancePrevConfigHash = nil
+	appinstancePrevElementHash = make(map[string][]byte)
+	systemAdaptersPrevConfigHash = nil
+	deviceIoListPrevConfigHash = nil
+	datastoreConfigPrevConfigHash = nil
+	contentInfoHash = nil
+	volumeHash = nil
+	cipherCtxHash = nil
+}
+
+// goldenTopic is a publication which is dumped in the golden file
+type goldenTopic struct {
+	name string
+	pub  pubsub.Publication
+}
+
+func initGoldenConfigCtx(t *testing.T) (*getconfigContext, []goldenTopic) {
+	getconfigCtx := initGetConfigCtx(t)
+	ps := pubsub.New(&pubsub.EmptyDriver{}, logger, log)
+	pubCipherContext, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.CipherContext{},
+	})
+	assert.Nil(t, err)
+	pubVolumeConfig, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.VolumeConfig{},
+	})
+	assert.Nil(t, err)
+	pubBaseOs, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.BaseOs{},
+	})
+	assert.Nil(t, err)
+	getconfigCtx.pubBaseOs = pubBaseOs
+	getconfigCtx.pubCipherContext = pubCipherContext
+	getconfigCtx.pubVolumeConfig = pubVolumeConfig
+	getconfigCtx.zedagentCtx.specMap = types.NewConfigItemSpecMap()
+	getconfigCtx.zedagentCtx.physicalIoAdapterMap =
+		make(map[string]types.PhysicalIOAdapter)
+	topics := []goldenTopic{
+		{"AppInstanceConfig", getconfigCtx.pubAppInstanceConfig},
+		{"AppLogPolicyConfig", getconfigCtx.pubAppLogPolicyConfig},
+		{"AppVolumeRetention", getconfigCtx.pubAppVolumeRetention},
+		{"BaseOs", getconfigCtx.pubBaseOs},
+		{"BaseOsConfig", getconfigCtx.pubBaseOsConfig},
+		{"CipherContext", getconfigCtx.pubCipherContext},
+		{"ContentTreeConfig", getconfigCtx.pubContentTreeConfig},
+		{"DatastoreConfig", getconfigCtx.pubDatastoreConfig},
+		{"DevicePortConfig", getconfigCtx.pubDevicePortConfig},
+		{"DevicePortConfigParseStatus", getconfigCtx.pubDevicePortConfigParseStatus},
+		{"NetworkInstanceConfig", getconfigCtx.pubNetworkInstanceConfig},
+		{"NetworkXObjectConfig", getconfigCtx.pubNetworkXObjectConfig},
+		{"PhysicalIOAdapterList", getconfigCtx.pubPhysicalIOAdapters},
+		{"SystemAdapterReport", getconfigCtx.pubSystemAdapterReport},
+		{"VolumeConfig", getconfigCtx.pubVolumeConfig},
+	}
+	return getconfigCtx, topics
+}
+
+// parseGoldenConfig applies the config items as parseConfigItems does,
+// without the timers and the GlobalConfig publication, and parses the rest.
+// Invalid config items are ignored like parseConfigItems does.
+func parseGoldenConfig(getconfigCtx *getconfigContext,
+	config *zconfig.EdgeDevConfig) {
+
+	ctx := getconfigCtx.zedagentCtx
+	globalConfig, _, err := previewConfigItems(&ctx.specMap,
+		&ctx.globalConfig, config.GetConfigItems())
+	if err != nil {
+		log.Warnf("parseGoldenConfig: %v", err)
+	}
+	ctx.globalConfig = *globalConfig
+	parseConfigObjects(config, getconfigCtx, false)
+}
+
+// dumpGoldenTopics returns the published items by topic and key as JSON
+func dumpGoldenTopics(t *testing.T, topics []goldenTopic) []byte {
+	dump := make(map[string]map[string]interface{})
+	for _, topic := range topics {
+		items := topic.pub.GetAll()
+		if len(items) == 0 {
+			continue
+		}
+		dump[topic.name] = make(map[string]interface{})
+		for key, item := range items {
+			dump[topic.name][key] = item
+		}
+	}
+	b, err := json.Marshal(dump)
+	assert.Nil(t, err)
+	return b
+}
+
+// redactGolden replaces the timestamps and secrets and returns indented
+// JSON with the keys sorted
+func redactGolden(t *testing.T, dump []byte) []byte {
+	var v interface{}
+	assert.Nil(t, json.Unmarshal(dump, &v))
+	v = redactGoldenValue("", v)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	assert.Nil(t, enc.Encode(v))
+	return buf.Bytes()
+}
+
+func redactGoldenValue(field string, v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, elem := range val {
+			val[k] = redactGoldenValue(k, elem)
+		}
+		return val
+	case []interface{}:
+		for i, elem := range val {
+			val[i] = redactGoldenValue(field, elem)
+		}
+		return val
+	case string:
+		if val != "" && goldenRedactedFields[field] {
+			return "<redacted>"
+		}
+		if ts, err := time.Parse(time.RFC3339Nano, val); err == nil &&
+			!ts.IsZero() {
+			return "<time>"
+		}
+		return val
+	default:
+		return val
+	}
+}
+
+func TestParseConfigGolden(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join(goldenDir, "*.pbtxt"))
+	assert.Nil(t, err)
+	assert.True(t, len(fixtures) >= 8, "missing fixtures in %s", goldenDir)
+	sort.Strings(fixtures)
+
+	for _, fixture := range fixtures {
+		testname := strings.TrimSuffix(filepath.Base(fixture), ".pbtxt")
+		t.Logf("Running test case %s", testname)
+		b, err := ioutil.ReadFile(fixture)
+		assert.Nil(t, err, testname)
+		config := &zconfig.EdgeDevConfig{}
+		if !assert.Nil(t, prototext.Unmarshal(b, config), testname) {
+			continue
+		}
+
+		resetPrevConfigHashes()
+		getconfigCtx, topics := initGoldenConfigCtx(t)
+		parseGoldenConfig(getconfigCtx, config)
+		first := dumpGoldenTopics(t, topics)
+
+		// The second parse of the same config must not publish anything,
+		// which would for instance change the time of the errors
+		parseGoldenConfig(getconfigCtx, config)
+		second := dumpGoldenTopics(t, topics)
+		assert.JSONEq(t, string(first), string(second),
+			"%s: the second parse changed the published items", testname)
+
+		output := redactGolden(t, first)
+		golden := strings.TrimSuffix(fixture, ".pbtxt") + ".golden.json"
+		if *updateGolden {
+			assert.Nil(t, ioutil.WriteFile(golden, output, 0644), testname)
+			continue
+		}
+		expected, err := ioutil.ReadFile(golden)
+		if !assert.Nil(t, err, "%s: run with -update to create %s",
+			testname, golden) {
+			continue
+		}
+		assert.Equal(t, string(expected), string(output),
+			"%s: differs from %s; run with -update if intended",
+			testname, golden)
+	}
+}
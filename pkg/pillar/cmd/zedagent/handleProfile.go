@@ -23,6 +23,7 @@ import (
 const (
 	defaultLocalProfileServerPort = "8888"
 	savedLocalProfileFile         = "lastlocalprofile"
+	maxLocalRestartAuditEntries   = 32
 )
 
 // urlAndSrcIP structure for mapping source IP and url of local server
@@ -338,9 +339,87 @@ func getLocalProfile(ctx *getconfigContext, skipFetch bool) string {
 	}
 	localProfile := localProfileConfig.GetLocalProfile()
 	writeOrTouchReceivedLocalProfile(ctx, localProfileConfig)
+	triggerLocalAppRestarts(ctx, localProfileConfig.GetAppRestarts())
 	return localProfile
 }
 
+// triggerLocalAppRestarts passes the restarts to configTimerTask. Only the
+// latest ones are kept since they carry all the counters.
+func triggerLocalAppRestarts(ctx *getconfigContext,
+	restarts []*profile.LocalAppRestart) {
+
+	select {
+	case <-ctx.localAppRestarts:
+	default:
+	}
+	ctx.localAppRestarts <- restarts
+}
+
+// processLocalAppRestarts restarts the app instances for which the local
+// profile server changed the counter, provided the controller set
+// AllowLocalRestart for them. The counters in the first response after
+// boot are only recorded since they might have been applied before the
+// reboot. Called from configTimerTask so that it does not race with the
+// parsing of the app instance config.
+func processLocalAppRestarts(ctx *getconfigContext,
+	restarts []*profile.LocalAppRestart) {
+
+	first := ctx.localAppRestartCounters == nil
+	counters := make(map[string]uint32)
+	for _, restart := range restarts {
+		appID := restart.GetAppId()
+		counter := restart.GetCounter()
+		counters[appID] = counter
+		prevCounter, ok := ctx.localAppRestartCounters[appID]
+		if first || (ok && prevCounter == counter) {
+			continue
+		}
+		item, _ := ctx.pubAppInstanceConfig.Get(appID)
+		if item == nil {
+			recordLocalRestart(ctx, appID, counter,
+				"unknown app instance")
+			continue
+		}
+		config := item.(types.AppInstanceConfig)
+		if !config.AllowLocalRestart {
+			recordLocalRestart(ctx, appID, counter,
+				"local restart not allowed for app instance")
+			continue
+		}
+		config.LocalRestartCmd.Counter++
+		recordLocalRestart(ctx, appID, counter, "")
+		ctx.pubAppInstanceConfig.Publish(appID, config)
+	}
+	ctx.localAppRestartCounters = counters
+}
+
+// recordLocalRestart logs the restart request and adds it to the bounded
+// audit list which is published in ZedAgentStatus. An empty reason means
+// the request was accepted.
+func recordLocalRestart(ctx *getconfigContext, appID string, counter uint32,
+	reason string) {
+
+	entry := types.LocalRestartAudit{
+		Time:     time.Now(),
+		AppUUID:  appID,
+		Counter:  counter,
+		Accepted: reason == "",
+		Reason:   reason,
+	}
+	if entry.Accepted {
+		log.Noticef("Local restart of app instance %s counter %d",
+			appID, counter)
+	} else {
+		log.Warnf("Rejected local restart of app instance %s counter %d: %s",
+			appID, counter, reason)
+	}
+	ctx.localRestartAudit = append(ctx.localRestartAudit, entry)
+	if len(ctx.localRestartAudit) > maxLocalRestartAuditEntries {
+		ctx.localRestartAudit = ctx.localRestartAudit[len(ctx.localRestartAudit)-maxLocalRestartAuditEntries:]
+	}
+	publishZedAgentStatus(ctx)
+}
+
 //processSavedProfile reads saved local profile and set it
 func processSavedProfile(ctx *getconfigContext) {
 	localProfile, err := readSavedLocalProfile(ctx, false)
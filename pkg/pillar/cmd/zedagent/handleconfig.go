@@ -15,6 +15,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	zconfig "github.com/lf-edge/eve/api/go/config"
+	"github.com/lf-edge/eve/api/go/profile"
 	"github.com/lf-edge/eve/pkg/pillar/flextimer"
 	"github.com/lf-edge/eve/pkg/pillar/hardware"
 	"github.com/lf-edge/eve/pkg/pillar/pubsub"
@@ -33,47 +34,84 @@ var serverNameAndPort string
 type Notify struct{}
 
 type getconfigContext struct {
-	zedagentCtx              *zedagentContext // Cross link
-	ledManagerCount          int              // Current count
-	configReceived           bool
-	configGetStatus          types.ConfigGetStatus
-	updateInprogress         bool
-	readSavedConfig          bool // Did we already read it?
-	configTickerHandle       interface{}
-	metricsTickerHandle      interface{}
-	localProfileTickerHandle interface{}
-	pubDevicePortConfig      pubsub.Publication
-	pubPhysicalIOAdapters    pubsub.Publication
-	devicePortConfig         types.DevicePortConfig
-	pubNetworkXObjectConfig  pubsub.Publication
-	subAppInstanceStatus     pubsub.Subscription
-	subDomainMetric          pubsub.Subscription
-	subProcessMetric         pubsub.Subscription
-	subHostMemory            pubsub.Subscription
-	subNodeAgentStatus       pubsub.Subscription
-	pubZedAgentStatus        pubsub.Publication
-	pubAppInstanceConfig     pubsub.Publication
-	pubAppNetworkConfig      pubsub.Publication
-	subAppNetworkStatus      pubsub.Subscription
-	pubBaseOsConfig          pubsub.Publication
-	pubBaseOs                pubsub.Publication
-	pubDatastoreConfig       pubsub.Publication
-	pubNetworkInstanceConfig pubsub.Publication
-	pubControllerCert        pubsub.Publication
-	pubCipherContext         pubsub.Publication
-	subContentTreeStatus     pubsub.Subscription
-	pubContentTreeConfig     pubsub.Publication
-	subVolumeStatus          pubsub.Subscription
-	pubVolumeConfig          pubsub.Publication
-	rebootFlag               bool
-	lastReceivedConfig       time.Time
-	lastProcessedConfig      time.Time
-	localProfileServer       string
-	profileServerToken       string
-	currentProfile           string
-	globalProfile            string
-	localProfile             string
-	localProfileTrigger      chan Notify
+	zedagentCtx                    *zedagentContext // Cross link
+	ledManagerCount                int              // Current count
+	configReceived                 bool
+	configGetStatus                types.ConfigGetStatus
+	updateInprogress               bool
+	readSavedConfig                bool // Did we already read it?
+	configTickerHandle             interface{}
+	metricsTickerHandle            interface{}
+	infoTickerHandle               interface{}
+	localProfileTickerHandle       interface{}
+	pubDevicePortConfig            pubsub.Publication
+	pubSystemAdapterReport         pubsub.Publication
+	pubDevicePortConfigParseStatus pubsub.Publication
+	pubPhysicalIOAdapters          pubsub.Publication
+	devicePortConfig               types.DevicePortConfig
+	pubNetworkXObjectConfig        pubsub.Publication
+	subAppInstanceStatus           pubsub.Subscription
+	subDomainMetric                pubsub.Subscription
+	subProcessMetric               pubsub.Subscription
+	subHostMemory                  pubsub.Subscription
+	subNodeAgentStatus             pubsub.Subscription
+	pubZedAgentStatus              pubsub.Publication
+	pubAppInstanceConfig           pubsub.Publication
+	pubAppVolumeRetention          pubsub.Publication
+	pubAppLogPolicyConfig          pubsub.Publication
+	pubWipeRequest                 pubsub.Publication
+	pubAppNetworkConfig            pubsub.Publication
+	subAppNetworkStatus            pubsub.Subscription
+	pubBaseOsConfig                pubsub.Publication
+	pubBaseOs                      pubsub.Publication
+	pubDatastoreConfig             pubsub.Publication
+	pubNetworkInstanceConfig       pubsub.Publication
+	pubControllerCert              pubsub.Publication
+	pubCipherContext               pubsub.Publication
+	subContentTreeStatus           pubsub.Subscription
+	pubContentTreeConfig           pubsub.Publication
+	subVolumeStatus                pubsub.Subscription
+	pubVolumeConfig                pubsub.Publication
+	rebootFlag                     bool
+	lastReceivedConfig             time.Time
+	lastProcessedConfig            time.Time
+	localProfileServer             string
+	profileServerToken             string
+	currentProfile                 string
+	globalProfile                  string
+	localProfile                   string
+	localProfileTrigger            chan Notify
+	// The LocalAppRestarts from the local profile server, passed to
+	// configTimerTask which also parses the app instance config
+	localAppRestarts chan []*profile.LocalAppRestart
+	// Last seen LocalAppRestart counters; nil until the first response
+	// from the local profile server
+	localAppRestartCounters map[string]uint32
+	localRestartAudit       []types.LocalRestartAudit
+
+	// Fingerprints of the rebootRequiredAspects from the first config
+	// after boot, and the aspects which have changed since then
+	rebootRequiredBaseline map[string]string
+	rebootRequiredPending  []string
+
+	// Canary app instance of the base OS update being tested and its
+	// verdict as reported in ZedAgentStatus
+	canaryAppUUID uuid.UUID
+	canaryVerdict types.CanaryVerdict
+
+	// The config whose app instance and base OS changes, and the reboot
+	// command, which are deferred until quietHoursEnd. The counter of the
+	// reboot command is only saved when the reboot is done.
+	quietHoursConfig *zconfig.EdgeDevConfig
+	quietHoursReboot *types.DeviceOpsCmd
+	quietHoursEnd    time.Time
+
+	// The network UUIDs of system adapters which were not found when the
+	// system adapters were last parsed
+	unresolvedNetworkUUIDs map[string]bool
+
+	// Used to only load the saved prevConfigHashes in the same boot
+	bootID string
 
 	callProcessLocalProfileServerChange bool //did we already call processLocalProfileServerChange
 
@@ -162,6 +200,14 @@ func configTimerTask(handleChannel chan interface{},
 			start := time.Now()
 			iteration += 1
 			rebootFlag := getLatestConfig(configUrl, iteration, getconfigCtx)
+			if !rebootFlag {
+				rebootFlag = applyQuietHoursDeferred(getconfigCtx,
+					time.Now())
+			}
+			if !rebootFlag {
+				// The reboot window can open without a config change
+				rebootFlag = maybeAutoReboot(getconfigCtx, time.Now())
+			}
 			if rebootFlag != getconfigCtx.rebootFlag {
 				getconfigCtx.rebootFlag = rebootFlag
 				triggerPublishDevInfo(ctx)
@@ -170,6 +216,12 @@ func configTimerTask(handleChannel chan interface{},
 				warningTime, errorTime)
 			publishZedAgentStatus(getconfigCtx)
 
+		case restarts := <-getconfigCtx.localAppRestarts:
+			start := time.Now()
+			processLocalAppRestarts(getconfigCtx, restarts)
+			ctx.ps.CheckMaxTimeTopic(wdName, "localAppRestarts", start,
+				warningTime, errorTime)
+
 		case <-stillRunning.C:
 			if getconfigCtx.rebootFlag {
 				log.Noticef("reboot flag set")
@@ -566,14 +618,24 @@ func inhaleDeviceConfig(config *zconfig.EdgeDevConfig, getconfigCtx *getconfigCo
 func publishZedAgentStatus(getconfigCtx *getconfigContext) {
 	ctx := getconfigCtx.zedagentCtx
 	status := types.ZedAgentStatus{
-		Name:                 agentName,
-		ConfigGetStatus:      getconfigCtx.configGetStatus,
-		RebootCmd:            ctx.rebootCmd,
-		RebootReason:         ctx.currentRebootReason,
-		BootReason:           ctx.currentBootReason,
-		MaintenanceMode:      ctx.maintenanceMode,
-		ForceFallbackCounter: ctx.forceFallbackCounter,
-		CurrentProfile:       getconfigCtx.currentProfile,
+		Name:                  agentName,
+		ConfigGetStatus:       getconfigCtx.configGetStatus,
+		RebootCmd:             ctx.rebootCmd,
+		RebootReason:          ctx.currentRebootReason,
+		BootReason:            ctx.currentBootReason,
+		MaintenanceMode:       ctx.maintenanceMode,
+		ForceFallbackCounter:  ctx.forceFallbackCounter,
+		CurrentProfile:        getconfigCtx.currentProfile,
+		RebootRequired:        len(getconfigCtx.rebootRequiredPending) != 0,
+		RebootRequiredAspects: getconfigCtx.rebootRequiredPending,
+		CanaryAppUUID:         getconfigCtx.canaryAppUUID,
+		CanaryVerdict:         getconfigCtx.canaryVerdict,
+		LocalRestartAudit: append([]types.LocalRestartAudit{},
+			getconfigCtx.localRestartAudit...),
+	}
+	if getconfigCtx.quietHoursConfig != nil || getconfigCtx.quietHoursReboot != nil {
+		status.ConfigDeferred = true
+		status.ConfigDeferredUntil = getconfigCtx.quietHoursEnd
 	}
 	pub := getconfigCtx.pubZedAgentStatus
 	pub.Publish(agentName, status)
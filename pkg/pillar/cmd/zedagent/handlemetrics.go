@@ -138,11 +138,17 @@ func encodeTestResults(tr types.TestResults) *info.ErrorInfo {
 func metricsTimerTask(ctx *zedagentContext, handleChannel chan interface{}) {
 	iteration := 0
 	log.Functionln("starting report metrics timer task")
-	publishMetrics(ctx, iteration)
+	var lastReport [reportClassCount]time.Time
+	start := time.Now()
+	for class := range lastReport {
+		lastReport[class] = start
+	}
+	publishMetrics(ctx, iteration, allReportClasses)
 
-	interval := time.Duration(ctx.globalConfig.GlobalValueInt(types.MetricInterval)) * time.Second
+	intervals := metricIntervals(&ctx.globalConfig)
+	interval := time.Duration(intervals.tickerInterval()) * time.Second
 	max := float64(interval)
-	min := max * 0.3
+	min := max * reportTickerMinFraction
 	ticker := flextimer.NewRangeTicker(time.Duration(min), time.Duration(max))
 	// Return handle to caller
 	handleChannel <- ticker
@@ -159,7 +165,13 @@ func metricsTimerTask(ctx *zedagentContext, handleChannel chan interface{}) {
 		case <-ticker.C:
 			start := time.Now()
 			iteration++
-			publishMetrics(ctx, iteration)
+			due := metricIntervals(&ctx.globalConfig).due(lastReport, start)
+			for class := range due {
+				if due[class] {
+					lastReport[class] = start
+				}
+			}
+			publishMetrics(ctx, iteration, due)
 			ctx.ps.CheckMaxTimeTopic(wdName, "publishMetrics", start,
 				warningTime, errorTime)
 
@@ -180,7 +192,7 @@ func updateMetricsTimer(metricInterval uint32, tickerHandle interface{}) {
 	interval := time.Duration(metricInterval) * time.Second
 	log.Functionf("updateMetricsTimer() change to %v", interval)
 	max := float64(interval)
-	min := max * 0.3
+	min := max * reportTickerMinFraction
 	flextimer.UpdateRangeTicker(tickerHandle,
 		time.Duration(min), time.Duration(max))
 	// Force an immediate timout since timer could have decreased
@@ -210,7 +222,8 @@ func lookupAppContainerMetric(ctx *zedagentContext, uuidStr string) *types.AppCo
 	return &metric
 }
 
-func publishMetrics(ctx *zedagentContext, iteration int) {
+// publishMetrics sends the metrics of the classes
+func publishMetrics(ctx *zedagentContext, iteration int, classes reportClassSet) {
 
 	var ReportMetrics = &metrics.ZMetricMsg{}
 
@@ -518,6 +531,18 @@ func publishMetrics(ctx *zedagentContext, iteration int) {
 		setMetricAnyValue(item, i.Value)
 		ReportDeviceMetric.MetricItems = append(ReportDeviceMetric.MetricItems, item)
 	}
+	if m, _ := ctx.subContentTreeMetrics.Get("global"); m != nil {
+		item := new(metrics.MetricItem)
+		item.Key = "content-tree-retag-hits"
+		item.Type = metrics.MetricItemType_MetricItemCounter
+		setMetricAnyValue(item, m.(types.ContentTreeMetrics).RetagHits)
+		ReportDeviceMetric.MetricItems = append(ReportDeviceMetric.MetricItems, item)
+		item = new(metrics.MetricItem)
+		item.Key = "content-tree-retag-mismatches"
+		item.Type = metrics.MetricItemType_MetricItemCounter
+		setMetricAnyValue(item, m.(types.ContentTreeMetrics).RetagMismatches)
+		ReportDeviceMetric.MetricItems = append(ReportDeviceMetric.MetricItems, item)
+	}
 
 	// Get device info using nil UUID
 	dm := lookupDomainMetric(ctx, nilUUID.String())
@@ -556,15 +581,29 @@ func publishMetrics(ctx *zedagentContext, iteration int) {
 				ctx.getconfigCtx.lastProcessedConfig, err)
 		}
 	}
+	for _, field := range getDeprecatedFieldStatus() {
+		ReportDeviceMetric.DeprecatedConfigFields = append(
+			ReportDeviceMetric.DeprecatedConfigFields,
+			&metrics.DeprecatedConfigFieldMetric{
+				Name: field.name,
+				Hits: field.totalHits,
+			})
+	}
 
-	ReportMetrics.MetricContent = new(metrics.ZMetricMsg_Dm)
-	if x, ok := ReportMetrics.GetMetricContent().(*metrics.ZMetricMsg_Dm); ok {
-		x.Dm = ReportDeviceMetric
+	if classes[reportDevice] {
+		ReportMetrics.MetricContent = new(metrics.ZMetricMsg_Dm)
+		if x, ok := ReportMetrics.GetMetricContent().(*metrics.ZMetricMsg_Dm); ok {
+			x.Dm = ReportDeviceMetric
+		}
 	}
 
 	// Loop over AppInstanceStatus so we report before the instance has booted
 	sub = ctx.getconfigCtx.subAppInstanceStatus
 	items := sub.GetAll()
+	if !classes[reportApp] {
+		// Not due
+		items = nil
+	}
 	for _, st := range items {
 		aiStatus := st.(types.AppInstanceStatus)
 
@@ -707,9 +746,15 @@ func publishMetrics(ctx *zedagentContext, iteration int) {
 		ReportMetrics.Am = append(ReportMetrics.Am, ReportAppMetric)
 	}
 
-	createNetworkInstanceMetrics(ctx, ReportMetrics)
-	createVolumeInstanceMetrics(ctx, ReportMetrics)
-	createProcessMetrics(ctx, ReportMetrics)
+	if classes[reportNetworkInstance] {
+		createNetworkInstanceMetrics(ctx, ReportMetrics)
+	}
+	if classes[reportVolume] {
+		createVolumeInstanceMetrics(ctx, ReportMetrics)
+	}
+	if classes[reportDevice] {
+		createProcessMetrics(ctx, ReportMetrics)
+	}
 
 	log.Tracef("PublishMetricsToZedCloud sending %s", ReportMetrics)
 	SendMetricsProtobuf(ReportMetrics, iteration)
@@ -914,6 +959,9 @@ func PublishAppInfoToZedCloud(ctx *zedagentContext, uuid string,
 	if aiStatus != nil {
 		ReportAppInfo.AppName = aiStatus.DisplayName
 		ReportAppInfo.State = aiStatus.State.ZSwState()
+		ReportAppInfo.RestartedLocally = aiStatus.RestartedLocally
+		ReportAppInfo.NoNetworking = aiStatus.NoNetworking
+		ReportAppInfo.Warnings = aiStatus.OpsCmdWarnings
 		state = aiStatus.State
 		objErr = aiStatus.HasError()
 		if !aiStatus.ErrorTime.IsZero() {
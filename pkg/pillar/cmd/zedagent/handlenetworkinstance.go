@@ -44,7 +44,7 @@ func handleNetworkInstanceImpl(ctxArg interface{}, key string,
 		log.Errorf("Received NetworkInstance error %s",
 			status.Error)
 	}
-	prepareAndPublishNetworkInstanceInfoMsg(ctx, status, false)
+	prepareAndPublishNetworkInstanceInfoMsg(ctx, key, status, false)
 	log.Functionf("handleNetworkInstanceImpl(%s) done", key)
 }
 
@@ -54,11 +54,13 @@ func handleNetworkInstanceDelete(ctxArg interface{}, key string,
 	log.Functionf("handleNetworkInstanceDelete(%s)", key)
 	status := statusArg.(types.NetworkInstanceStatus)
 	ctx := ctxArg.(*zedagentContext)
-	prepareAndPublishNetworkInstanceInfoMsg(ctx, status, true)
+	prepareAndPublishNetworkInstanceInfoMsg(ctx, key, status, true)
 	log.Functionf("handleNetworkInstanceDelete(%s) done", key)
 }
 
-func prepareAndPublishNetworkInstanceInfoMsg(ctx *zedagentContext,
+// The key is normally the UUID of the status, but for a malformed UUID
+// from the controller it is that string
+func prepareAndPublishNetworkInstanceInfoMsg(ctx *zedagentContext, key string,
 	status types.NetworkInstanceStatus, deleted bool) {
 
 	infoMsg := &zinfo.ZInfoMsg{}
@@ -68,12 +70,13 @@ func prepareAndPublishNetworkInstanceInfoMsg(ctx *zedagentContext,
 	infoMsg.Ztype = *infoType
 	infoMsg.AtTimeStamp = ptypes.TimestampNow()
 
-	uuid := status.Key()
+	uuid := key
 	info := new(zinfo.ZInfoNetworkInstance)
 	info.NetworkID = uuid
 	info.NetworkVersion = status.UUIDandVersion.Version
 	info.Displayname = status.DisplayName
 	info.InstType = uint32(status.Type)
+	info.PassiveIpType = uint32(status.PassiveIpType)
 	info.CurrentUplinkIntf = status.CurrentUplinkIntf
 
 	if !status.ErrorTime.IsZero() {
@@ -83,6 +86,12 @@ func prepareAndPublishNetworkInstanceInfoMsg(ctx *zedagentContext,
 		errInfo.Timestamp = errTime
 		info.NetworkErr = append(info.NetworkErr, errInfo)
 	}
+	info.Warnings = append(info.Warnings, status.DnsNameToIPWarnings...)
+	info.Warnings = append(info.Warnings, status.DnsServerWarnings...)
+	// The invalid DHCP reservations and static routes were left out
+	// without failing the network instance
+	info.Warnings = append(info.Warnings, status.ReservationErrors...)
+	info.Warnings = append(info.Warnings, status.StaticRouteErrors...)
 
 	if deleted {
 		// XXX When a network instance is deleted it is ideal to
@@ -6,30 +6,41 @@ package zedagent
 import (
 	"bytes"
 	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"hash"
 	"io/ioutil"
+	"math"
 	"net"
+	"net/url"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/google/go-cmp/cmp"
 	zconfig "github.com/lf-edge/eve/api/go/config"
+	"github.com/lf-edge/eve/pkg/pillar/cipher"
 	"github.com/lf-edge/eve/pkg/pillar/types"
 	fileutils "github.com/lf-edge/eve/pkg/pillar/utils/file"
 	uuid "github.com/satori/go.uuid"
 )
 
 const (
-	MaxBaseOsCount       = 2
-	BaseOsImageCount     = 1
-	rebootConfigFilename = types.PersistStatusDir + "/rebootConfig"
+	MaxBaseOsCount   = 2
+	BaseOsImageCount = 1
 )
 
+// The last reboot command seen. A variable for the tests.
+var rebootConfigFilename = types.PersistStatusDir + "/rebootConfig"
+
 // Returns a rebootFlag
 func parseConfig(config *zconfig.EdgeDevConfig, getconfigCtx *getconfigContext,
 	usingSaved bool) bool {
@@ -61,8 +72,12 @@ func parseConfig(config *zconfig.EdgeDevConfig, getconfigCtx *getconfigContext,
 		publishZedAgentStatus(ctx.getconfigCtx)
 	}
 
+	// Inside the quiet hours the changes to the app instances and the
+	// base OS, and reboot commands which are not urgent, are deferred
+	quiet := inQuietHours(getconfigCtx, time.Now())
+
 	// Any new reboot command?
-	if !usingSaved && parseOpCmds(config, getconfigCtx) {
+	if !usingSaved && parseOpCmds(config, getconfigCtx, quiet) {
 		log.Noticeln("Reboot flag set, skipping config processing")
 		return true
 	}
@@ -72,32 +87,61 @@ func parseConfig(config *zconfig.EdgeDevConfig, getconfigCtx *getconfigContext,
 	} else if ctx.maintenanceMode {
 		log.Noticef("parseConfig: Ignoring config due to maintenanceMode")
 	} else {
+		startDeprecatedFieldRun()
 		handleControllerCertsSha(ctx, config)
-		parseCipherContext(getconfigCtx, config)
-		parseDatastoreConfig(config, getconfigCtx)
-		// DeviceIoList has some defaults for Usage and UsagePolicy
-		// used by systemAdapters
-		physioChanged := parseDeviceIoListConfig(config, getconfigCtx)
-		// Network objects are used for systemAdapters
-		networksChanged := parseNetworkXObjectConfig(config, getconfigCtx)
-		// system adapter configuration that we publish, depends
-		// on Physio configuration and Networks configuration. If either of
-		// Physio or Networks change, we should re-parse system adapters and
-		// publish updated configuration.
-		forceSystemAdaptersParse := physioChanged || networksChanged
-		parseSystemAdapterConfig(config, getconfigCtx, forceSystemAdaptersParse)
+		parseConfigObjects(config, getconfigCtx, quiet)
+		endDeprecatedFieldRun()
+		deferToQuietHoursEnd(getconfigCtx, config, quiet)
+		getconfigCtx.lastProcessedConfig = time.Now()
+		savePrevConfigHashes(prevConfigHashFilename, getconfigCtx.bootID)
+
+		// Any changes which need a reboot to take effect?
+		updateRebootRequired(getconfigCtx, config)
+		if maybeAutoReboot(getconfigCtx, time.Now()) {
+			log.Noticeln("Rebooting for config changes")
+			return true
+		}
+	}
+	return false
+}
+
+// parseConfigObjects parses and publishes the objects for the other
+// agents. Each parser skips the parts of the config whose hash has not
+// changed since the last call. The app instances and the base OS are left
+// out when deferred by the quiet hours.
+func parseConfigObjects(config *zconfig.EdgeDevConfig,
+	getconfigCtx *getconfigContext, quiet bool) {
+
+	parseCipherContext(getconfigCtx, config)
+	parseDatastoreConfig(config, getconfigCtx)
+	// DeviceIoList has some defaults for Usage and UsagePolicy
+	// used by systemAdapters
+	physioChanged := parseDeviceIoListConfig(config, getconfigCtx)
+	// Network objects are used for systemAdapters
+	networksChanged := parseNetworkXObjectConfig(config, getconfigCtx)
+	// system adapter configuration that we publish, depends
+	// on Physio configuration and Networks configuration. If either of
+	// Physio or Networks change, we should re-parse system adapters and
+	// publish updated configuration. The same if a network which a system
+	// adapter could not find has been published since.
+	forceSystemAdaptersParse := physioChanged || networksChanged ||
+		unresolvedNetworksAvailable(getconfigCtx)
+	parseSystemAdapterConfig(config, getconfigCtx, forceSystemAdaptersParse)
+	if !quiet {
 		parseBaseOS(getconfigCtx, config)
 		parseBaseOsConfig(getconfigCtx, config)
-		parseNetworkInstanceConfig(config, getconfigCtx)
-		parseContentInfoConfig(getconfigCtx, config)
-		parseVolumeConfig(getconfigCtx, config)
-
-		// parseProfile must be called before processing of app instances from config
-		parseProfile(getconfigCtx, config)
+	}
+	// The ports of the network instances are checked against the
+	// DeviceIoList, which can arrive after them
+	parseNetworkInstanceConfig(config, getconfigCtx, physioChanged)
+	parseContentInfoConfig(getconfigCtx, config)
+	parseVolumeConfig(getconfigCtx, config)
+
+	// parseProfile must be called before processing of app instances from config
+	parseProfile(getconfigCtx, config)
+	if !quiet {
 		parseAppInstanceConfig(config, getconfigCtx)
-		getconfigCtx.lastProcessedConfig = time.Now()
 	}
-	return false
 }
 
 // Walk published AppInstanceConfig's and set Activate=false
@@ -167,6 +211,13 @@ func parseBaseOsConfig(getconfigCtx *getconfigContext,
 	for _, os := range cfgOsList {
 		computeConfigElementSha(h, os)
 	}
+	// Re-check the canary app instances when the app instances change
+	for _, os := range cfgOsList {
+		if os.GetCanaryAppUUID() != "" {
+			computeConfigElementSha(h, configAppUUIDs(config))
+			break
+		}
+	}
 	configHash := h.Sum(nil)
 	same := bytes.Equal(configHash, baseOSConfigPrevConfigHash)
 	if same {
@@ -213,11 +264,32 @@ func parseBaseOsConfig(getconfigCtx *getconfigContext,
 		baseOs.ContentTreeConfigList = make([]types.ContentTreeConfig,
 			len(cfgOs.Drives))
 		parseContentTreeConfigList(baseOs.ContentTreeConfigList, cfgOs.Drives)
+		for _, drive := range cfgOs.Drives {
+			if err := validateDrive(drive); err != nil {
+				errStr := fmt.Sprintf("baseOs(%s) invalid drive: %v",
+					baseOs.BaseOsVersion, err)
+				log.Error(errStr)
+				baseOs.SetErrorNow(errStr)
+				break
+			}
+		}
+		if !baseOs.HasError() {
+			canaryAppUUID, err := parseCanaryAppUUID(
+				cfgOs.GetCanaryAppUUID(), config)
+			if err != nil {
+				errStr := fmt.Sprintf("baseOs(%s) invalid canary app: %v",
+					baseOs.BaseOsVersion, err)
+				log.Error(errStr)
+				baseOs.SetErrorNow(errStr)
+			}
+			baseOs.CanaryAppUUID = canaryAppUUID
+		}
 
 		log.Tracef("parseBaseOsConfig publishing %v",
 			baseOs)
 		publishBaseOsConfig(getconfigCtx, baseOs)
 	}
+	updateCanaryVerdict(getconfigCtx)
 }
 
 var networkConfigPrevConfigHash []byte
@@ -230,6 +302,8 @@ func parseNetworkXObjectConfig(config *zconfig.EdgeDevConfig,
 	for _, n := range nets {
 		computeConfigElementSha(h, n)
 	}
+	// Re-parse when the PAC file limit changes
+	computeConfigElementSha(h, getPacfileMaxBytes(getconfigCtx))
 	configHash := h.Sum(nil)
 	same := bytes.Equal(configHash, networkConfigPrevConfigHash)
 	if same {
@@ -279,11 +353,43 @@ func parseDnsNameToIpList(
 	config *types.NetworkInstanceConfig) {
 
 	// Parse and store DnsNameToIPList form Network configuration
-	dnsEntries := apiConfigEntry.GetDns()
-
-	// Parse and populate the DnsNameToIP list
 	// This is what we will publish to zedrouter
-	nameToIPs := []types.DnsNameToIP{}
+	nameToIPs, badAddrs, warnings := parseStaticDNSEntries(
+		apiConfigEntry.GetDns())
+	if len(badAddrs) != 0 {
+		// The valid addresses are still used
+		warnings = append(warnings, fmt.Sprintf(
+			"bad dnsEntry addresses ignored: %s",
+			formatBadDNSAddrs(badAddrs)))
+		// and the entries without one are skipped
+		validNameToIPs := []types.DnsNameToIP{}
+		for _, nameToIP := range nameToIPs {
+			if len(nameToIP.IPs) != 0 {
+				validNameToIPs = append(validNameToIPs, nameToIP)
+			}
+		}
+		nameToIPs = validNameToIPs
+	}
+	for _, warning := range warnings {
+		log.Warnf("Network Instance %s: %s", config.Key(), warning)
+	}
+	config.DnsNameToIPList = nameToIPs
+	config.DnsNameToIPWarnings = warnings
+}
+
+// parseStaticDNSEntries parses the static DNS entries of a network or
+// network instance. Entries with the same host name, ignoring case, are
+// merged into the first one without duplicate IPs, with a warning for each
+// merged host name. Addresses which do not parse are left out and returned
+// in badAddrs by host name.
+func parseStaticDNSEntries(dnsEntries []*zconfig.ZnetStaticDNSEntry) (
+	nameToIPs []types.DnsNameToIP, badAddrs map[string][]string,
+	warnings []string) {
+
+	nameToIPs = []types.DnsNameToIP{}
+	// Index in nameToIPs by lower case host name
+	indexByName := make(map[string]int)
+	merged := make(map[string]bool)
 	for _, dnsEntry := range dnsEntries {
 		hostName := dnsEntry.HostName
 
@@ -293,56 +399,103 @@ func parseDnsNameToIpList(
 			if ip != nil {
 				ips = append(ips, ip)
 			} else {
-				log.Errorf("Bad dnsEntry %s ignored",
-					strAddr)
+				if badAddrs == nil {
+					badAddrs = make(map[string][]string)
+				}
+				badAddrs[hostName] = append(badAddrs[hostName], strAddr)
 			}
 		}
 
-		nameToIP := types.DnsNameToIP{
-			HostName: hostName,
-			IPs:      ips,
+		name := strings.ToLower(hostName)
+		indx, ok := indexByName[name]
+		if !ok {
+			indexByName[name] = len(nameToIPs)
+			nameToIPs = append(nameToIPs, types.DnsNameToIP{
+				HostName: hostName,
+				IPs:      uniqueIPs(ips),
+			})
+			continue
+		}
+		nameToIP := &nameToIPs[indx]
+		nameToIP.IPs = uniqueIPs(append(nameToIP.IPs, ips...))
+		if !merged[name] {
+			merged[name] = true
+			warnings = append(warnings, fmt.Sprintf(
+				"duplicate DNS entries for %s merged", nameToIP.HostName))
 		}
-		nameToIPs = append(nameToIPs, nameToIP)
 	}
-	config.DnsNameToIPList = nameToIPs
+	return nameToIPs, badAddrs, warnings
 }
 
-func publishNetworkInstanceConfig(ctx *getconfigContext,
-	networkInstances []*zconfig.NetworkInstanceConfig) {
-
-	log.Functionf("Publish NetworkInstance Config: %+v", networkInstances)
+// formatBadDNSAddrs lists the bad addresses by host name, sorted by host
+// name, for an error string
+func formatBadDNSAddrs(badAddrs map[string][]string) string {
+	var hostNames []string
+	for hostName := range badAddrs {
+		hostNames = append(hostNames, hostName)
+	}
+	sort.Strings(hostNames)
+	var entries []string
+	for _, hostName := range hostNames {
+		entries = append(entries, fmt.Sprintf("%s: %s", hostName,
+			strings.Join(badAddrs[hostName], ", ")))
+	}
+	return strings.Join(entries, "; ")
+}
 
-	unpublishDeletedNetworkInstanceConfig(ctx, networkInstances)
-	// check we do not have more than one VPN network instance
-	vpnCount := 0
-	for _, netInstApiCfg := range networkInstances {
-		if oCfg := netInstApiCfg.Cfg; oCfg != nil {
-			opaqueCfg := oCfg.GetOconfig()
-			if opaqueCfg != "" {
-				opaqueType := oCfg.GetType()
-				if opaqueType == zconfig.ZNetworkOpaqueConfigType_ZNetOConfigVPN {
-					vpnCount++
-				}
+// uniqueIPs returns the IPs without duplicates, keeping the order
+func uniqueIPs(ips []net.IP) []net.IP {
+	unique := []net.IP{}
+	for _, ip := range ips {
+		found := false
+		for _, u := range unique {
+			if u.Equal(ip) {
+				found = true
+				break
 			}
 		}
+		if !found {
+			unique = append(unique, ip)
+		}
 	}
+	return unique
+}
 
-	if vpnCount > 1 {
-		log.Errorf("publishNetworkInstanceConfig(): more than one VPN instance configuration")
-		return
-	}
+func publishNetworkInstanceConfig(ctx *getconfigContext,
+	networkInstances []*zconfig.NetworkInstanceConfig) {
 
+	log.Functionf("Publish NetworkInstance Config: %+v",
+		withoutSecrets(networkInstances))
+
+	unpublishDeletedNetworkInstanceConfig(ctx, networkInstances)
+	// We only support one VPN network instance. The first one is used and
+	// any others are published with an error
+	firstVPN := ""
+	// Only the first entry for a UUID is published. An error is recorded
+	// on it if there are duplicates
+	duplicates := make(map[string]int)
+	for _, apiConfigEntry := range networkInstances {
+		duplicates[apiConfigEntry.Uuidandversion.Uuid]++
+	}
+	uplinkRates := sumUplinkRates(networkInstances)
+	vlanUsers := switchVlanUsers(networkInstances)
+	uplinkCapacity := getUplinkCapacityKbps(ctx)
+	published := make(map[string]bool)
 	for _, apiConfigEntry := range networkInstances {
+		uuidStr := apiConfigEntry.Uuidandversion.Uuid
+		if published[uuidStr] {
+			log.Errorf("publishNetworkInstanceConfig: ignoring duplicate %s %s",
+				uuidStr, apiConfigEntry.Displayname)
+			continue
+		}
+		published[uuidStr] = true
 		id, err := uuid.FromString(apiConfigEntry.Uuidandversion.Uuid)
 		version := apiConfigEntry.Uuidandversion.Version
 		if err != nil {
-			log.Errorf("NetworkInstanceConfig: Malformed UUID %s. ignored. Err: %s",
+			// Without a UUID it can not be published nor reported
+			log.Errorf("publishNetworkInstanceConfig: ignoring %s with malformed UUID %s: %s",
+				apiConfigEntry.Displayname,
 				apiConfigEntry.Uuidandversion.Uuid, err)
-			// XXX - We should propagate this error to Cloud.
-			// Why ignore only for this specific Check?
-			// Shouldn't we reject the config if any of the fields have errors?
-			// Or may be identify some fields as imp. fields and reject them only?
-			// Either way, it is good to propagate the error to Cloud.
 			continue
 		}
 		networkInstanceConfig := types.NetworkInstanceConfig{
@@ -355,17 +508,109 @@ func publishNetworkInstanceConfig(ctx *getconfigContext,
 			networkInstanceConfig.UUID.String(), networkInstanceConfig.DisplayName,
 			networkInstanceConfig.Type, networkInstanceConfig.Activate)
 
-		if apiConfigEntry.Port != nil {
-			networkInstanceConfig.Logicallabel = apiConfigEntry.Port.Name
+		// All the errors are reported, not just the last one
+		var errStrs []string
+		labels, err := parseNetworkInstancePorts(ctx, apiConfigEntry)
+		if err != nil {
+			errStr := fmt.Sprintf("Network Instance %s: %s",
+				networkInstanceConfig.Key(), err)
+			log.Error(errStr)
+			errStrs = append(errStrs, errStr)
+		}
+		if len(labels) != 0 {
+			networkInstanceConfig.Logicallabel = labels[0]
+			networkInstanceConfig.Logicallabels = labels
 		}
 		networkInstanceConfig.IpType = types.AddressType(apiConfigEntry.IpType)
+		networkInstanceConfig.UplinkRateKbps = apiConfigEntry.GetUplinkRateKbps()
+		networkInstanceConfig.BurstKB = apiConfigEntry.GetBurstKB()
+		if err := validateUplinkRate(apiConfigEntry); err != nil {
+			errStr := fmt.Sprintf("Network Instance %s: %s",
+				networkInstanceConfig.Key(), err)
+			log.Error(errStr)
+			errStrs = append(errStrs, errStr)
+		} else if networkInstanceConfig.UplinkRateKbps != 0 && uplinkCapacity != 0 {
+			// Oversubscribing the uplink is allowed, but reported
+			total := uplinkRates[apiConfigEntry.GetPort().GetName()]
+			if total > uint64(uplinkCapacity) {
+				warning := fmt.Sprintf("uplink %s oversubscribed: network instances limited to %d kbps in total, capacity %d kbps",
+					networkInstanceConfig.Logicallabel, total, uplinkCapacity)
+				log.Warnf("Network Instance %s: %s",
+					networkInstanceConfig.Key(), warning)
+				networkInstanceConfig.UplinkRateWarning = warning
+			}
+		}
+
+		if err := validateVlan(apiConfigEntry); err != nil {
+			errStr := fmt.Sprintf("Network Instance %s: %s",
+				networkInstanceConfig.Key(), err)
+			log.Error(errStr)
+			errStrs = append(errStrs, errStr)
+		} else if vlanID := apiConfigEntry.GetVlanId(); vlanID != 0 {
+			networkInstanceConfig.Vlan = uint16(vlanID)
+			users := vlanUsers[vlanKey(apiConfigEntry)]
+			if len(users) > 1 {
+				errStr := fmt.Sprintf("Network Instance %s: VLAN %d on port %s conflicts with %s",
+					networkInstanceConfig.Key(), vlanID,
+					networkInstanceConfig.Logicallabel,
+					strings.Join(otherUUIDs(users, uuidStr), ", "))
+				log.Error(errStr)
+				errStrs = append(errStrs, errStr)
+			}
+		}
+
+		passiveIpType, err := parsePassiveIpType(
+			apiConfigEntry.GetPassiveIpType(), networkInstanceConfig.Type)
+		if err != nil {
+			errStr := fmt.Sprintf("Network instance %s %s: %s",
+				networkInstanceConfig.UUID.String(),
+				networkInstanceConfig.DisplayName, err)
+			log.Error(errStr)
+			errStrs = append(errStrs, errStr)
+		}
+		networkInstanceConfig.PassiveIpType = passiveIpType
+		networkInstanceConfig.MaxVifs = apiConfigEntry.GetMaxVifs()
+		networkInstanceConfig.SuppressNtp = apiConfigEntry.GetSuppressNtp()
+
+		if apiConfigEntry.GetDscpMark() {
+			if dscp := apiConfigEntry.GetDscp(); dscp > types.MaxDscp {
+				errStr := fmt.Sprintf("Network Instance %s: DSCP %d out of range 0-%d",
+					networkInstanceConfig.Key(), dscp, types.MaxDscp)
+				log.Error(errStr)
+				errStrs = append(errStrs, errStr)
+			} else {
+				networkInstanceConfig.DscpMark = true
+				networkInstanceConfig.Dscp = uint8(dscp)
+			}
+		}
+
+		// Mesh is deprecated. Rather than half-working, it is reported
+		// with an error unless explicitly allowed
+		if isOverlayNetworkInstance(apiConfigEntry) {
+			var errStr string
+			if !ctx.zedagentCtx.globalConfig.GlobalValueBool(types.NetworkAllowMesh) {
+				errStr = fmt.Sprintf("Network Instance %s: mesh networking is deprecated and disabled; see %s",
+					networkInstanceConfig.Key(), types.NetworkAllowMesh)
+			} else if err := validateMeshConfig(apiConfigEntry); err != nil {
+				errStr = fmt.Sprintf("Network Instance %s: %s",
+					networkInstanceConfig.Key(), err)
+			} else {
+				networkInstanceConfig.MapServers = parseMapServers(ctx,
+					networkInstanceConfig.Key(),
+					apiConfigEntry.GetCfg().GetLispConfig())
+			}
+			if errStr != "" {
+				log.Error(errStr)
+				errStrs = append(errStrs, errStr)
+			}
+		}
 
 		switch networkInstanceConfig.Type {
 		case types.NetworkInstanceTypeSwitch:
 			// XXX controller should send AddressTypeNone type for switch
 			// network instances
 			if networkInstanceConfig.IpType != types.AddressTypeNone {
-				log.Errorf("Switch network instance %s %s with invalid IpType %d should be %d",
+				log.Warnf("Switch network instance %s %s with invalid IpType %d should be %d",
 					networkInstanceConfig.UUID.String(),
 					networkInstanceConfig.DisplayName,
 					networkInstanceConfig.IpType,
@@ -373,36 +618,81 @@ func publishNetworkInstanceConfig(ctx *getconfigContext,
 				// Let's relax the requirement until cloud side update the right IpType
 				networkInstanceConfig.IpType = types.AddressTypeNone
 			}
-			ctx.pubNetworkInstanceConfig.Publish(networkInstanceConfig.UUID.String(),
-				networkInstanceConfig)
+
+		case types.NetworkInstanceTypeLocal:
+			// Without an L3 IpType no addressing would be set up
+			if networkInstanceConfig.IpType == types.AddressTypeNone {
+				errStr := fmt.Sprintf("Local network instance %s %s needs an IpType",
+					networkInstanceConfig.UUID.String(),
+					networkInstanceConfig.DisplayName)
+				log.Error(errStr)
+				errStrs = append(errStrs, errStr)
+			}
 
 		// FIXME:XXX set encap flag, when the dummy interface
 		// is tested for the VPN
 		case types.NetworkInstanceTypeCloud:
 			// if opaque config not set, flag it
 			if apiConfigEntry.Cfg == nil {
-				log.Errorf("Network instance %s %s, %v, opaque not set",
+				errStr := fmt.Sprintf("Network instance %s %s, %v, opaque not set",
 					networkInstanceConfig.UUID.String(),
 					networkInstanceConfig.DisplayName,
 					networkInstanceConfig.IpType)
+				log.Error(errStr)
+				errStrs = append(errStrs, errStr)
 			} else {
 				ocfg := apiConfigEntry.Cfg
 				if ocfg.Type != zconfig.ZNetworkOpaqueConfigType_ZNetOConfigVPN {
-					log.Errorf("Network instance %s %s, %v invalid config",
+					errStr := fmt.Sprintf("Network instance %s %s, %v invalid config",
 						networkInstanceConfig.UUID.String(),
 						networkInstanceConfig.DisplayName,
 						networkInstanceConfig.IpType)
+					log.Error(errStr)
+					errStrs = append(errStrs, errStr)
 				}
 				networkInstanceConfig.OpaqueConfig = ocfg.Oconfig
 			}
 			// if not IPv4 type, flag it
 			if networkInstanceConfig.IpType != types.AddressTypeIPV4 {
-				log.Errorf("Network instance %s %s, %v not IPv4 type",
+				errStr := fmt.Sprintf("Network instance %s %s, %v not IPv4 type",
 					networkInstanceConfig.UUID.String(),
 					networkInstanceConfig.DisplayName,
 					networkInstanceConfig.IpType)
+				log.Error(errStr)
+				errStrs = append(errStrs, errStr)
+			}
+		}
+		// Checked once the IpType of a switch is corrected. A switch
+		// observing IPv6 traffic needs the IPv6 minimum MTU
+		mtuIpType := networkInstanceConfig.IpType
+		if networkInstanceConfig.Type == types.NetworkInstanceTypeSwitch {
+			mtuIpType = networkInstanceConfig.PassiveIpType
+		}
+		networkInstanceConfig.Mtu = apiConfigEntry.GetMtu()
+		if err := validateMtu(networkInstanceConfig.Mtu, mtuIpType); err != nil {
+			errStr := fmt.Sprintf("Network Instance %s: %s",
+				networkInstanceConfig.Key(), err)
+			log.Error(errStr)
+			errStrs = append(errStrs, errStr)
+		}
+		if isVPNNetworkInstance(apiConfigEntry) {
+			if firstVPN == "" {
+				firstVPN = networkInstanceConfig.Key()
+			} else {
+				errStr := fmt.Sprintf("Network instance %s %s: only one VPN instance supported, conflicting with %s",
+					networkInstanceConfig.UUID.String(),
+					networkInstanceConfig.DisplayName, firstVPN)
+				log.Error(errStr)
+				errStrs = append(errStrs, errStr)
 			}
 		}
+		if count := duplicates[uuidStr]; count > 1 {
+			errStr := fmt.Sprintf("Network instance %s %s: %d duplicate entries in config; using the first",
+				networkInstanceConfig.UUID.String(),
+				networkInstanceConfig.DisplayName, count-1)
+			log.Error(errStr)
+			errStrs = append(errStrs, errStr)
+		}
 
 		// other than switch-type(l2)
 		// if ip type is l3, do the needful
@@ -412,164 +702,1036 @@ func publishNetworkInstanceConfig(ctx *getconfigContext,
 				errStr := fmt.Sprintf("Network Instance %s parameter parse failed: %s",
 					networkInstanceConfig.Key(), err)
 				log.Error(errStr)
-				networkInstanceConfig.SetErrorNow(errStr)
+				errStrs = append(errStrs, errStr)
 				// Proceed to send error back to controller
 			}
-
+		}
+		// Switch network instances have no IP configuration but can
+		// carry static DNS entries, e.g., for a DNS forwarder of the apps
+		if networkInstanceConfig.IpType != types.AddressTypeNone ||
+			networkInstanceConfig.Type == types.NetworkInstanceTypeSwitch {
 			parseDnsNameToIpList(apiConfigEntry,
 				&networkInstanceConfig)
 		}
+		upstreamDNSServers, warning, err := parseUpstreamDNSServers(
+			apiConfigEntry.GetUpstreamDnsServers(),
+			networkInstanceConfig.IpType)
+		if err != nil {
+			errStr := fmt.Sprintf("Network Instance %s: %s",
+				networkInstanceConfig.Key(), err)
+			log.Error(errStr)
+			errStrs = append(errStrs, errStr)
+		} else if warning != "" {
+			networkInstanceConfig.DnsServerWarnings = append(
+				networkInstanceConfig.DnsServerWarnings, warning)
+		}
+		networkInstanceConfig.UpstreamDnsServers = upstreamDNSServers
+		if len(errStrs) != 0 {
+			networkInstanceConfig.SetErrorNow(strings.Join(errStrs, "; "))
+		}
 
 		ctx.pubNetworkInstanceConfig.Publish(networkInstanceConfig.UUID.String(),
 			networkInstanceConfig)
 	}
 }
 
-var networkInstancePrevConfigHash []byte
-
-func parseNetworkInstanceConfig(config *zconfig.EdgeDevConfig,
-	getconfigCtx *getconfigContext) {
-
-	networkInstances := config.GetNetworkInstances()
-
-	h := sha256.New()
-	for _, n := range networkInstances {
-		computeConfigElementSha(h, n)
+// parseNetworkInstancePorts returns the labels of the port and the further
+// ports of the network instance. Only switch network instances can have
+// further ports, which need to be network adapters in the DeviceIoList.
+// The ports which are not are left out and returned in the error.
+// A shared label of the system adapters is replaced by the logicallabels of
+// its ports, of which there can only be one unless the network instance is
+// a switch.
+func parseNetworkInstancePorts(getconfigCtx *getconfigContext,
+	apiConfigEntry *zconfig.NetworkInstanceConfig) ([]string, error) {
+
+	isSwitch := apiConfigEntry.GetInstType() == zconfig.ZNetworkInstType_ZnetInstSwitch
+	var labels []string
+	if name := apiConfigEntry.GetPort().GetName(); name != "" {
+		resolved := resolveSharedPortLabel(getconfigCtx, name)
+		if len(resolved) > 1 && !isSwitch {
+			return []string{name}, fmt.Errorf("port %s is ambiguous: "+
+				"shared label of ports %s", name, strings.Join(resolved, ", "))
+		}
+		labels = append(labels, resolved...)
 	}
-	configHash := h.Sum(nil)
-	same := bytes.Equal(configHash, networkInstancePrevConfigHash)
-	if same {
-		return
+	ports := apiConfigEntry.GetPorts()
+	if len(ports) == 0 && len(labels) <= 1 {
+		if len(labels) != 0 {
+			return labels, validateNetworkInstancePort(getconfigCtx,
+				labels[0])
+		}
+		return labels, nil
 	}
-	log.Functionf("parseNetworkInstanceConfig: Applying updated config "+
-		"prevSha: % x, "+
-		"NewSha : % x, "+
-		"networkInstances: %v",
-		networkInstancePrevConfigHash, configHash, networkInstances)
-	networkInstancePrevConfigHash = configHash
-	// Export NetworkInstanceConfig to zedrouter
-	publishNetworkInstanceConfig(getconfigCtx, networkInstances)
-}
-
-var appinstancePrevConfigHash []byte
-
-func parseAppInstanceConfig(config *zconfig.EdgeDevConfig,
-	getconfigCtx *getconfigContext) {
-
-	Apps := config.GetApps()
-	h := sha256.New()
-	for _, a := range Apps {
-		computeConfigElementSha(h, a)
+	if !isSwitch {
+		return labels, fmt.Errorf("multiple ports only supported for switch")
 	}
-	configHash := h.Sum(nil)
-	same := bytes.Equal(configHash, appinstancePrevConfigHash)
-	if same {
-		return
+	for _, port := range ports {
+		labels = append(labels,
+			resolveSharedPortLabel(getconfigCtx, port.GetName())...)
 	}
-	log.Functionf("parseAppInstanceConfig: Applying updated config "+
-		"prevSha: % x, "+
-		"NewSha : % x, "+
-		"Apps: %v",
-		appinstancePrevConfigHash, configHash, Apps)
-	appinstancePrevConfigHash = configHash
-
-	// First look for deleted ones
-	items := getconfigCtx.pubAppInstanceConfig.GetAll()
-	for uuidStr := range items {
-		found := false
-		for _, app := range Apps {
-			if app.Uuidandversion.Uuid == uuidStr {
-				found = true
-				break
-			}
+	netLabels := deviceIoNetLabels(getconfigCtx)
+	var valid, bad []string
+	seen := make(map[string]bool)
+	for _, label := range labels {
+		if seen[label] {
+			continue
 		}
-		if !found {
-			log.Functionf("Remove app config %s", uuidStr)
-			getconfigCtx.pubAppInstanceConfig.Unpublish(uuidStr)
+		seen[label] = true
+		if netLabels[label] {
+			valid = append(valid, label)
+		} else {
+			bad = append(bad, label)
 		}
 	}
+	if len(bad) != 0 {
+		return valid, fmt.Errorf("ports %s are not network adapters",
+			strings.Join(bad, ", "))
+	}
+	return valid, nil
+}
 
-	for _, cfgApp := range Apps {
-		// Note that we repeat this even if the app config didn't
-		// change but something else in the EdgeDeviceConfig did
-		log.Tracef("New/updated app instance %v", cfgApp)
-		var appInstance types.AppInstanceConfig
-
-		appInstance.UUIDandVersion.UUID, _ = uuid.FromString(cfgApp.Uuidandversion.Uuid)
-		appInstance.UUIDandVersion.Version = cfgApp.Uuidandversion.Version
-		appInstance.DisplayName = cfgApp.Displayname
-		appInstance.Activate = cfgApp.Activate
+// resolveSharedPortLabel returns the logicallabels of the ports with the
+// shared label, or the label itself if it is the label of a port, a
+// built-in label which zedrouter resolves, or not a shared label
+func resolveSharedPortLabel(getconfigCtx *getconfigContext,
+	label string) []string {
 
-		appInstance.FixedResources.Kernel = cfgApp.Fixedresources.Kernel
-		appInstance.FixedResources.BootLoader = cfgApp.Fixedresources.Bootloader
-		appInstance.FixedResources.Ramdisk = cfgApp.Fixedresources.Ramdisk
-		appInstance.FixedResources.MaxMem = int(cfgApp.Fixedresources.Maxmem)
-		appInstance.FixedResources.Memory = int(cfgApp.Fixedresources.Memory)
-		appInstance.FixedResources.RootDev = cfgApp.Fixedresources.Rootdev
-		appInstance.FixedResources.VCpus = int(cfgApp.Fixedresources.Vcpus)
-		appInstance.FixedResources.VirtualizationMode = types.VmMode(cfgApp.Fixedresources.VirtualizationMode)
-		appInstance.FixedResources.EnableVnc = cfgApp.Fixedresources.EnableVnc
-		appInstance.FixedResources.VncDisplay = cfgApp.Fixedresources.VncDisplay
-		appInstance.FixedResources.VncPasswd = cfgApp.Fixedresources.VncPasswd
-		appInstance.MetaDataType = types.MetaDataType(cfgApp.MetaDataType)
+	if isSharedPortLabel(label) || systemAdapterLabels(getconfigCtx)[label] {
+		return []string{label}
+	}
+	resolved := sharedPortLabels(getconfigCtx)[label]
+	if len(resolved) == 0 {
+		return []string{label}
+	}
+	return resolved
+}
 
-		appInstance.VolumeRefConfigList = make([]types.VolumeRefConfig,
-			len(cfgApp.VolumeRefList))
-		parseVolumeRefList(appInstance.VolumeRefConfigList, cfgApp.GetVolumeRefList())
+// sharedPortLabels returns the logicallabels of the ports in the
+// DevicePortConfig by shared label
+func sharedPortLabels(getconfigCtx *getconfigContext) map[string][]string {
+	labels := make(map[string][]string)
+	for _, port := range getconfigCtx.devicePortConfig.Ports {
+		for _, label := range port.SharedLabels {
+			labels[label] = append(labels[label], port.Logicallabel)
+		}
+	}
+	return labels
+}
 
-		// fill in the collect stats IP address of the App
-		appInstance.CollectStatsIPAddr = net.ParseIP(cfgApp.GetCollectStatsIPAddr())
+// isSharedPortLabel returns true for the built-in labels which refer to the
+// management ports
+func isSharedPortLabel(label string) bool {
+	return strings.EqualFold(label, "uplink") ||
+		strings.EqualFold(label, "freeuplink")
+}
 
-		// fill the app adapter config
-		parseAppNetworkConfig(&appInstance, cfgApp, config.Networks,
-			config.NetworkInstances)
+// validateNetworkInstancePort checks that the port of a network instance is
+// a network adapter in the DeviceIoList or a port from the SystemAdapterList.
+// Nothing can be checked until the DeviceIoList has arrived.
+func validateNetworkInstancePort(getconfigCtx *getconfigContext,
+	label string) error {
 
-		// I/O adapters
-		appInstance.IoAdapterList = nil
-		for _, adapter := range cfgApp.Adapters {
-			log.Tracef("Processing adapter type %d name %s",
-				adapter.Type, adapter.Name)
-			appInstance.IoAdapterList = append(appInstance.IoAdapterList,
-				types.IoAdapter{Type: types.IoType(adapter.Type),
-					Name: adapter.Name})
+	if isSharedPortLabel(label) ||
+		len(getconfigCtx.zedagentCtx.physicalIoAdapterMap) == 0 {
+		return nil
+	}
+	adapter := lookupDeviceIoLogicallabel(getconfigCtx, label)
+	if adapter == nil {
+		adapter = lookupDeviceIoPhylabel(getconfigCtx, label)
+	}
+	if adapter != nil {
+		if !types.IoType(adapter.Ptype).IsNet() {
+			return fmt.Errorf("port %s is not a network adapter", label)
 		}
-		log.Functionf("Got adapters %v", appInstance.IoAdapterList)
+		return nil
+	}
+	if systemAdapterLabels(getconfigCtx)[label] {
+		return nil
+	}
+	return fmt.Errorf("port %s not found in the DeviceIoList or the SystemAdapterList",
+		label)
+}
 
-		cmd := cfgApp.GetRestart()
-		if cmd != nil {
-			appInstance.RestartCmd.Counter = cmd.Counter
-			appInstance.RestartCmd.ApplyTime = cmd.OpsTime
+// systemAdapterLabels returns the logicallabels and interface names of the
+// ports in the DevicePortConfig parsed from the SystemAdapterList
+func systemAdapterLabels(getconfigCtx *getconfigContext) map[string]bool {
+	labels := make(map[string]bool)
+	for _, port := range getconfigCtx.devicePortConfig.Ports {
+		if port.Logicallabel != "" {
+			labels[port.Logicallabel] = true
 		}
-		cmd = cfgApp.GetPurge()
-		if cmd != nil {
-			appInstance.PurgeCmd.Counter = cmd.Counter
-			appInstance.PurgeCmd.ApplyTime = cmd.OpsTime
+		if port.IfName != "" {
+			labels[port.IfName] = true
 		}
-		userData := cfgApp.GetUserData()
-		if userData != "" {
-			appInstance.CloudInitUserData = &userData
+	}
+	return labels
+}
+
+// deviceIoNetLabels returns the logicallabels and phylabels of the network
+// adapters in the published PhysicalIOAdapterList
+func deviceIoNetLabels(getconfigCtx *getconfigContext) map[string]bool {
+	labels := make(map[string]bool)
+	item, err := getconfigCtx.pubPhysicalIOAdapters.Get("zedagent")
+	if err != nil {
+		return labels
+	}
+	for _, adapter := range item.(types.PhysicalIOAdapterList).AdapterList {
+		if !types.IoType(adapter.Ptype).IsNet() {
+			continue
 		}
-		appInstance.RemoteConsole = cfgApp.GetRemoteConsole()
-		appInstance.CipherBlockStatus = parseCipherBlock(getconfigCtx, appInstance.Key(),
-			cfgApp.GetCipherData())
-		appInstance.ProfileList = cfgApp.ProfileList
+		labels[adapter.Phylabel] = true
+		if adapter.Logicallabel != "" {
+			labels[adapter.Logicallabel] = true
+		}
+	}
+	return labels
+}
 
-		// Verify that it fits and if not publish with error
-		checkAndPublishAppInstanceConfig(getconfigCtx, appInstance)
+// parsePassiveIpType returns the address type a switch network instance
+// observes. Only switch network instances have one, and the IP types with
+// crypto are not supported.
+func parsePassiveIpType(ipType zconfig.AddressType,
+	niType types.NetworkInstanceType) (types.AddressType, error) {
+
+	if ipType != zconfig.AddressType_First &&
+		niType != types.NetworkInstanceTypeSwitch {
+		return types.AddressTypeNone,
+			fmt.Errorf("passive IpType %s only supported for switch", ipType)
+	}
+	switch ipType {
+	case zconfig.AddressType_First:
+		return types.AddressTypeNone, nil
+	case zconfig.AddressType_IPV4:
+		return types.AddressTypeIPV4, nil
+	case zconfig.AddressType_IPV6:
+		return types.AddressTypeIPV6, nil
+	default:
+		return types.AddressTypeNone,
+			fmt.Errorf("unsupported passive IpType %s", ipType)
 	}
 }
 
-var systemAdaptersPrevConfigHash []byte
+// isVPNNetworkInstance returns true if the network instance has
+// a VPN opaque config
+func isVPNNetworkInstance(apiConfigEntry *zconfig.NetworkInstanceConfig) bool {
+	oCfg := apiConfigEntry.Cfg
+	if oCfg == nil || oCfg.GetOconfig() == "" {
+		return false
+	}
+	return oCfg.GetType() == zconfig.ZNetworkOpaqueConfigType_ZNetOConfigVPN
+}
 
-func parseSystemAdapterConfig(config *zconfig.EdgeDevConfig,
-	getconfigCtx *getconfigContext, forceParse bool) {
+// parseUpstreamDNSServers parses the DNS servers a network instance
+// forwards to. Only network instances with an IP type run a DNS service.
+// The returned warning is set if the list was truncated.
+func parseUpstreamDNSServers(servers []string,
+	ipType types.AddressType) ([]net.IP, string, error) {
 
-	sysAdapters := config.GetSystemAdapterList()
-	h := sha256.New()
-	for _, a := range sysAdapters {
-		computeConfigElementSha(h, a)
+	if len(servers) == 0 {
+		return nil, "", nil
 	}
-	configHash := h.Sum(nil)
+	if ipType == types.AddressTypeNone {
+		return nil, "", fmt.Errorf("upstream DNS servers %v without an IP type",
+			servers)
+	}
+	var result []net.IP
+	for _, dsStr := range servers {
+		ds := net.ParseIP(dsStr)
+		if ds == nil {
+			return nil, "", fmt.Errorf("bad upstream DNS server IP %s", dsStr)
+		}
+		if ds.IsUnspecified() || ds.IsMulticast() {
+			return nil, "", fmt.Errorf("upstream DNS server IP %s is not unicast",
+				dsStr)
+		}
+		result = append(result, ds)
+	}
+	result, warning := uniqueDNSServers(result, "parseUpstreamDNSServers")
+	if warning != "" {
+		warning = "upstream " + warning
+	}
+	return result, warning, nil
+}
+
+const (
+	minMtuIPv4 = 576
+	minMtuIPv6 = 1280
+	maxMtu     = 9000
+)
+
+// validateMtu checks the MTU range for the address type of the network
+// instance. Zero means the default MTU
+func validateMtu(mtu uint32, ipType types.AddressType) error {
+	if mtu == 0 {
+		return nil
+	}
+	minMtu := uint32(minMtuIPv4)
+	if ipType == types.AddressTypeIPV6 ||
+		ipType == types.AddressTypeCryptoIPV6 {
+		minMtu = minMtuIPv6
+	}
+	if mtu < minMtu || mtu > maxMtu {
+		return fmt.Errorf("MTU %d out of range %d-%d", mtu, minMtu, maxMtu)
+	}
+	return nil
+}
+
+const (
+	// 100 Gbps
+	maxUplinkRateKbps = 100 * 1000 * 1000
+	// 1 Gbyte
+	maxBurstKB = 1024 * 1024
+)
+
+// validateUplinkRate checks the bounds of the uplink rate limit of the
+// network instance. The limit needs an uplink, and the burst size a limit.
+func validateUplinkRate(apiConfigEntry *zconfig.NetworkInstanceConfig) error {
+	rate := apiConfigEntry.GetUplinkRateKbps()
+	burst := apiConfigEntry.GetBurstKB()
+	if rate > maxUplinkRateKbps {
+		return fmt.Errorf("uplink rate %d kbps above maximum %d kbps",
+			rate, maxUplinkRateKbps)
+	}
+	if burst > maxBurstKB {
+		return fmt.Errorf("burst %d KB above maximum %d KB",
+			burst, maxBurstKB)
+	}
+	if burst != 0 && rate == 0 {
+		return fmt.Errorf("burst %d KB without an uplink rate", burst)
+	}
+	if rate != 0 && apiConfigEntry.GetPort().GetName() == "" {
+		return fmt.Errorf("uplink rate %d kbps without an uplink port", rate)
+	}
+	return nil
+}
+
+// sumUplinkRates returns the sum of the valid uplink rate limits of the
+// network instances per uplink port. Duplicate entries for a UUID are
+// not counted, since only the first one is published.
+func sumUplinkRates(networkInstances []*zconfig.NetworkInstanceConfig) map[string]uint64 {
+	rates := make(map[string]uint64)
+	seen := make(map[string]bool)
+	for _, apiConfigEntry := range networkInstances {
+		uuidStr := apiConfigEntry.GetUuidandversion().GetUuid()
+		if seen[uuidStr] {
+			continue
+		}
+		seen[uuidStr] = true
+		if validateUplinkRate(apiConfigEntry) != nil {
+			continue
+		}
+		port := apiConfigEntry.GetPort().GetName()
+		rates[port] += uint64(apiConfigEntry.GetUplinkRateKbps())
+	}
+	return rates
+}
+
+const (
+	minVlanID = 1
+	maxVlanID = 4094
+)
+
+// validateVlan checks the VLAN ID of the network instance. Only switch
+// network instances can be on a VLAN
+func validateVlan(apiConfigEntry *zconfig.NetworkInstanceConfig) error {
+	vlanID := apiConfigEntry.GetVlanId()
+	if vlanID == 0 {
+		return nil
+	}
+	if vlanID < minVlanID || vlanID > maxVlanID {
+		return fmt.Errorf("VLAN %d out of range %d-%d",
+			vlanID, minVlanID, maxVlanID)
+	}
+	if apiConfigEntry.GetInstType() != zconfig.ZNetworkInstType_ZnetInstSwitch {
+		return fmt.Errorf("VLAN %d set for a %s network instance; only supported for switch",
+			vlanID, apiConfigEntry.GetInstType())
+	}
+	return nil
+}
+
+// validateMeshConfig checks the LISP config of a mesh network instance,
+// which is only parsed when allowed by NetworkAllowMesh
+func validateMeshConfig(apiConfigEntry *zconfig.NetworkInstanceConfig) error {
+	lispConfig := apiConfigEntry.GetCfg().GetLispConfig()
+	if lispConfig == nil {
+		return fmt.Errorf("mesh network instance without a LISP config")
+	}
+	if len(lispConfig.GetLispMSs()) == 0 {
+		return fmt.Errorf("mesh network instance without map servers")
+	}
+	for i, ms := range lispConfig.GetLispMSs() {
+		if ms.GetNameOrIp() == "" {
+			return fmt.Errorf("map server %d without a name or IP", i)
+		}
+	}
+	prefix := lispConfig.GetAllocationprefix()
+	prefixLen := lispConfig.GetAllocationprefixlen()
+	if !lispConfig.GetAllocate() && len(prefix) == 0 && prefixLen == 0 {
+		return nil
+	}
+	var bits uint32
+	switch len(prefix) {
+	case net.IPv4len:
+		bits = 32
+	case net.IPv6len:
+		bits = 128
+	default:
+		return fmt.Errorf("allocation prefix of %d bytes is neither IPv4 nor IPv6",
+			len(prefix))
+	}
+	if prefixLen == 0 || prefixLen > bits {
+		return fmt.Errorf("allocation prefix length %d out of range 1-%d",
+			prefixLen, bits)
+	}
+	return nil
+}
+
+// parseMapServers returns the map servers of a mesh network instance. The
+// credentials are expected in cipher blocks; clear text ones are still
+// accepted but counted in the cipher metrics.
+func parseMapServers(ctx *getconfigContext, key string,
+	lispConfig *zconfig.NetworkInstanceLispConfig) []types.MapServer {
+
+	var mapServers []types.MapServer
+	for _, ms := range lispConfig.GetLispMSs() {
+		mapServer := types.MapServer{
+			ServiceType: types.MapServerType(ms.GetZsType()),
+			NameOrIp:    ms.GetNameOrIp(),
+		}
+		mapServer.CipherBlockStatus = parseCipherBlock(ctx,
+			fmt.Sprintf("%s-ms-%s", key, ms.GetNameOrIp()),
+			ms.GetCipherData())
+		if !mapServer.CipherBlockStatus.IsCipher && ms.GetCredential() != "" {
+			log.Warnf("parseMapServers: clear text credential for map server %s in %s",
+				ms.GetNameOrIp(), key)
+			cipher.RecordFailure(agentName, types.NoCipher)
+			mapServer.Credential = ms.GetCredential()
+		}
+		mapServers = append(mapServers, mapServer)
+	}
+	return mapServers
+}
+
+// withoutSecrets returns the network instances for logging, with the clear
+// text credentials of the map servers redacted. The config is not modified.
+func withoutSecrets(networkInstances []*zconfig.NetworkInstanceConfig) []*zconfig.NetworkInstanceConfig {
+	redacted := make([]*zconfig.NetworkInstanceConfig, len(networkInstances))
+	for i, ni := range networkInstances {
+		redacted[i] = ni
+		hasCredential := false
+		for _, ms := range ni.GetCfg().GetLispConfig().GetLispMSs() {
+			if ms.GetCredential() != "" {
+				hasCredential = true
+				break
+			}
+		}
+		if !hasCredential {
+			continue
+		}
+		ni = proto.Clone(ni).(*zconfig.NetworkInstanceConfig)
+		for _, ms := range ni.GetCfg().GetLispConfig().GetLispMSs() {
+			if ms.GetCredential() != "" {
+				ms.Credential = "<redacted>"
+			}
+		}
+		redacted[i] = ni
+	}
+	return redacted
+}
+
+// vlanKey identifies the VLAN of the port of a network instance
+func vlanKey(apiConfigEntry *zconfig.NetworkInstanceConfig) string {
+	return fmt.Sprintf("%s.%d", apiConfigEntry.GetPort().GetName(),
+		apiConfigEntry.GetVlanId())
+}
+
+// switchVlanUsers returns the UUIDs of the switch network instances with
+// a valid VLAN by the VLAN of the port. Duplicate entries for a UUID are
+// not counted, since only the first one is published.
+func switchVlanUsers(networkInstances []*zconfig.NetworkInstanceConfig) map[string][]string {
+	users := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, apiConfigEntry := range networkInstances {
+		uuidStr := apiConfigEntry.GetUuidandversion().GetUuid()
+		if seen[uuidStr] {
+			continue
+		}
+		seen[uuidStr] = true
+		if apiConfigEntry.GetVlanId() == 0 ||
+			validateVlan(apiConfigEntry) != nil {
+			continue
+		}
+		key := vlanKey(apiConfigEntry)
+		users[key] = append(users[key], uuidStr)
+	}
+	return users
+}
+
+// otherUUIDs returns the UUIDs except uuidStr
+func otherUUIDs(uuids []string, uuidStr string) []string {
+	var others []string
+	for _, u := range uuids {
+		if u != uuidStr {
+			others = append(others, u)
+		}
+	}
+	return others
+}
+
+// getUplinkCapacityKbps returns the configured capacity of an uplink;
+// zero means unchecked
+func getUplinkCapacityKbps(getconfigCtx *getconfigContext) uint32 {
+	return getconfigCtx.zedagentCtx.globalConfig.GlobalValueInt(
+		types.NetworkUplinkCapacityKbps)
+}
+
+var networkInstancePrevConfigHash []byte
+
+func parseNetworkInstanceConfig(config *zconfig.EdgeDevConfig,
+	getconfigCtx *getconfigContext, forceParse bool) {
+
+	networkInstances := config.GetNetworkInstances()
+
+	h := sha256.New()
+	for _, n := range networkInstances {
+		computeConfigElementSha(h, n)
+	}
+	computeConfigElementSha(h, getUplinkCapacityKbps(getconfigCtx))
+	// The further ports of switch network instances are checked against
+	// the network adapters
+	computeConfigElementSha(h, deviceIoNetLabels(getconfigCtx))
+	// The ports are checked against the system adapters, and their shared
+	// labels are resolved
+	computeConfigElementSha(h, systemAdapterLabels(getconfigCtx))
+	computeConfigElementSha(h, sharedPortLabels(getconfigCtx))
+	computeConfigElementSha(h, getconfigCtx.zedagentCtx.globalConfig.GlobalValueBool(
+		types.NetworkAllowMesh))
+	configHash := h.Sum(nil)
+	same := bytes.Equal(configHash, networkInstancePrevConfigHash)
+	if same && !forceParse {
+		return
+	}
+	log.Functionf("parseNetworkInstanceConfig: Applying updated config "+
+		"prevSha: % x, "+
+		"NewSha : % x, "+
+		"networkInstances: %v, "+
+		"Forced parsing: %v",
+		networkInstancePrevConfigHash, configHash,
+		withoutSecrets(networkInstances), forceParse)
+	networkInstancePrevConfigHash = configHash
+	// Export NetworkInstanceConfig to zedrouter
+	publishNetworkInstanceConfig(getconfigCtx, networkInstances)
+}
+
+// maxOpsCmdApplyAhead is how far in the future the ApplyTime of a restart
+// or purge command can plausibly be
+const maxOpsCmdApplyAhead = 30 * 24 * time.Hour
+
+// opsCmdTimeLayouts are the accepted formats of the OpsTime of a
+// DeviceOpsCmd; the API documents mm/dd/yy:hh:mm
+var opsCmdTimeLayouts = []string{time.RFC3339, "01/02/06:15:04"}
+
+// parseAppInstanceOpsCmd returns the restart or purge command of an app
+// instance. An ApplyTime too far in the future, relative to the device
+// time, is recorded in ApplyTimeWarning so that the operator can tell why
+// the command does not happen. ApplyTimes which do not parse are passed on
+// as is.
+func parseAppInstanceOpsCmd(displayName string, kind string,
+	cmd *zconfig.InstanceOpsCmd, now time.Time) types.AppInstanceOpsCmd {
+
+	opsCmd := types.AppInstanceOpsCmd{
+		Counter:   cmd.GetCounter(),
+		ApplyTime: cmd.GetOpsTime(),
+	}
+	if opsCmd.ApplyTime == "" {
+		return opsCmd
+	}
+	for _, layout := range opsCmdTimeLayouts {
+		applyTime, err := time.Parse(layout, opsCmd.ApplyTime)
+		if err != nil {
+			continue
+		}
+		if applyTime.Sub(now) > maxOpsCmdApplyAhead {
+			opsCmd.ApplyTimeWarning = fmt.Sprintf(
+				"%s %d apply time %s is more than %d days after the device time %s",
+				kind, opsCmd.Counter, opsCmd.ApplyTime,
+				int(maxOpsCmdApplyAhead.Hours()/24),
+				now.UTC().Format(time.RFC3339))
+			log.Warnf("App %s: %s", displayName, opsCmd.ApplyTimeWarning)
+		}
+		return opsCmd
+	}
+	log.Functionf("App %s: %s %d apply time %s not parsed",
+		displayName, kind, opsCmd.Counter, opsCmd.ApplyTime)
+	return opsCmd
+}
+
+var appinstancePrevConfigHash []byte
+
+// Per app instance hash of the config element, indexed by UUID string
+var appinstancePrevElementHash = make(map[string][]byte)
+
+// maxVncDisplay keeps the VNC tcp port, which is 5900 plus the display
+// number, within the valid port range
+const maxVncDisplay = 65535 - 5900
+
+// appVncPolicy is the part of the global config which the VNC settings of
+// the app instances are validated against
+type appVncPolicy struct {
+	DisplayMin      uint32
+	DisplayMax      uint32
+	RequirePassword bool
+}
+
+func getAppVncPolicy(getconfigCtx *getconfigContext) appVncPolicy {
+	gc := &getconfigCtx.zedagentCtx.globalConfig
+	policy := appVncPolicy{
+		DisplayMin:      gc.GlobalValueInt(types.AppVncDisplayMin),
+		DisplayMax:      gc.GlobalValueInt(types.AppVncDisplayMax),
+		RequirePassword: gc.GlobalValueBool(types.AppVncRequirePassword),
+	}
+	if policy.DisplayMax > maxVncDisplay {
+		policy.DisplayMax = maxVncDisplay
+	}
+	return policy
+}
+
+// validateAppVnc checks the VNC settings of an app instance. Nothing is
+// checked when VNC is disabled.
+func validateAppVnc(policy appVncPolicy, vmConfig types.VmConfig) error {
+	if !vmConfig.EnableVnc {
+		return nil
+	}
+	if vmConfig.VncDisplay < policy.DisplayMin ||
+		vmConfig.VncDisplay > policy.DisplayMax {
+		return fmt.Errorf("VNC display %d out of range %d-%d",
+			vmConfig.VncDisplay, policy.DisplayMin, policy.DisplayMax)
+	}
+	if vmConfig.VncPasswd == "" && policy.RequirePassword {
+		return fmt.Errorf("VNC enabled without a VNC password")
+	}
+	return nil
+}
+
+func parseAppInstanceConfig(config *zconfig.EdgeDevConfig,
+	getconfigCtx *getconfigContext) {
+
+	Apps := config.GetApps()
+	appUUIDs := make(map[string]bool)
+	for _, a := range Apps {
+		appUUIDs[a.Uuidandversion.Uuid] = true
+	}
+	expireAppVolumeRetention(getconfigCtx, appUUIDs, time.Now())
+	vncPolicy := getAppVncPolicy(getconfigCtx)
+	maxVifs := networkInstanceMaxVifs(config.GetNetworkInstances())
+	maxApps := getconfigCtx.zedagentCtx.globalConfig.GlobalValueInt(
+		types.AppMaxInstances)
+	accelDevices := acceleratorDevices(config.GetDeviceIoList())
+	numaDevices := adapterNumaNodes(config.GetDeviceIoList())
+	overlayCompat := getconfigCtx.zedagentCtx.globalConfig.GlobalValueBool(
+		types.NetworkOverlayCompat)
+	// The hash also covers the global config, the network instances and
+	// the adapters which the apps are validated against, so that the apps
+	// are validated again when those change. Only the exported fields of
+	// the structs are hashed.
+	h := sha256.New()
+	for _, a := range Apps {
+		computeConfigElementSha(h, a)
+	}
+	computeConfigElementSha(h, vncPolicy)
+	computeConfigElementSha(h, maxVifs)
+	computeConfigElementSha(h, maxApps)
+	computeConfigElementSha(h, accelDevices)
+	computeConfigElementSha(h, numaDevices)
+	computeConfigElementSha(h, overlayCompat)
+	for _, networkInstance := range config.GetNetworkInstances() {
+		computeConfigElementSha(h, networkInstance)
+	}
+	configHash := h.Sum(nil)
+	same := bytes.Equal(configHash, appinstancePrevConfigHash)
+	if same {
+		return
+	}
+	log.Functionf("parseAppInstanceConfig: Applying updated config "+
+		"prevSha: % x, "+
+		"NewSha : % x, "+
+		"Apps: %v",
+		appinstancePrevConfigHash, configHash, Apps)
+	appinstancePrevConfigHash = configHash
+
+	// First look for deleted ones
+	items := getconfigCtx.pubAppInstanceConfig.GetAll()
+	for uuidStr := range items {
+		found := false
+		for _, app := range Apps {
+			if app.Uuidandversion.Uuid == uuidStr {
+				found = true
+				break
+			}
+		}
+		if !found {
+			log.Functionf("Remove app config %s", uuidStr)
+			getconfigCtx.pubAppInstanceConfig.Unpublish(uuidStr)
+			delete(appPurgeBaselines, uuidStr)
+			unpublishAppLogPolicyConfig(getconfigCtx, uuidStr)
+		}
+	}
+	// Likewise the element hash of an app covers the findings which
+	// depend on the other apps or on the above, the VNC policy for the
+	// apps which enable VNC, and the overlay compatibility and the network
+	// instances of its interfaces
+	hostnameConflicts := findAppHostnameConflicts(Apps)
+	vncConflicts := findAppVncConflicts(Apps)
+	quotaErrors := findNetworkQuotaErrors(Apps, maxVifs)
+	portMapConflicts := findPortMapConflicts(Apps)
+	accelMemoryErrors := findAcceleratorMemoryErrors(Apps, accelDevices)
+	numaPlacements := findAppNumaPlacements(Apps, numaDevices)
+	tooManyApps := findAppsBeyondMax(Apps, maxApps)
+	elementHash := make(map[string][]byte)
+	for _, cfgApp := range Apps {
+		uuidStr := cfgApp.Uuidandversion.Uuid
+		h := sha256.New()
+		computeConfigElementSha(h, cfgApp)
+		computeConfigElementSha(h, hostnameConflicts[uuidStr])
+		computeConfigElementSha(h, vncConflicts[uuidStr])
+		computeConfigElementSha(h, quotaErrors[uuidStr])
+		computeConfigElementSha(h, portMapConflicts[uuidStr])
+		computeConfigElementSha(h, accelMemoryErrors[uuidStr])
+		computeConfigElementSha(h, numaPlacements[uuidStr])
+		computeConfigElementSha(h, tooManyApps[uuidStr])
+		if cfgApp.GetFixedresources().GetEnableVnc() {
+			computeConfigElementSha(h, vncPolicy)
+		}
+		if len(cfgApp.GetInterfaces()) != 0 {
+			computeConfigElementSha(h, overlayCompat)
+		}
+		for _, intfEnt := range cfgApp.GetInterfaces() {
+			computeConfigElementSha(h, lookupNetworkInstanceId(
+				intfEnt.NetworkId, config.GetNetworkInstances()))
+		}
+		elementHash[uuidStr] = h.Sum(nil)
+	}
+	for uuidStr := range appinstancePrevElementHash {
+		if _, ok := elementHash[uuidStr]; !ok {
+			delete(appinstancePrevElementHash, uuidStr)
+		}
+	}
+
+	for _, cfgApp := range Apps {
+		// Skip the apps whose config element did not change
+		uuidStr := cfgApp.Uuidandversion.Uuid
+		if bytes.Equal(elementHash[uuidStr],
+			appinstancePrevElementHash[uuidStr]) {
+			log.Tracef("Unchanged app instance %s", uuidStr)
+			continue
+		}
+		appinstancePrevElementHash[uuidStr] = elementHash[uuidStr]
+		log.Tracef("New/updated app instance %v", cfgApp)
+		var appInstance types.AppInstanceConfig
+
+		appInstance.UUIDandVersion.UUID, _ = uuid.FromString(cfgApp.Uuidandversion.Uuid)
+		appInstance.UUIDandVersion.Version = cfgApp.Uuidandversion.Version
+		appInstance.DisplayName = cfgApp.Displayname
+		appInstance.Activate = cfgApp.Activate
+
+		appInstance.FixedResources.Kernel = cfgApp.Fixedresources.Kernel
+		appInstance.FixedResources.BootLoader = cfgApp.Fixedresources.Bootloader
+		appInstance.FixedResources.Ramdisk = cfgApp.Fixedresources.Ramdisk
+		appInstance.FixedResources.MaxMem = int(cfgApp.Fixedresources.Maxmem)
+		appInstance.FixedResources.Memory = int(cfgApp.Fixedresources.Memory)
+		appInstance.FixedResources.RootDev = cfgApp.Fixedresources.Rootdev
+		appInstance.FixedResources.VCpus = int(cfgApp.Fixedresources.Vcpus)
+		appInstance.FixedResources.VirtualizationMode = types.VmMode(cfgApp.Fixedresources.VirtualizationMode)
+		appInstance.FixedResources.EnableVnc = cfgApp.Fixedresources.EnableVnc
+		appInstance.FixedResources.VncDisplay = cfgApp.Fixedresources.VncDisplay
+		appInstance.FixedResources.VncPasswd = cfgApp.Fixedresources.VncPasswd
+		if err := validateAppVnc(vncPolicy, appInstance.FixedResources); err != nil {
+			errStr := fmt.Sprintf("app %s: %v", appInstance.DisplayName, err)
+			log.Error(errStr)
+			appInstance.Errors = append(appInstance.Errors,
+				types.NewAppConfigError("", types.AppConfigErrorBadVnc, errStr))
+		}
+		if errStr, ok := vncConflicts[uuidStr]; ok {
+			log.Error(errStr)
+			appInstance.Errors = append(appInstance.Errors,
+				types.NewAppConfigError("", types.AppConfigErrorBadVnc, errStr))
+		}
+		appInstance.MetaDataType = types.MetaDataType(cfgApp.MetaDataType)
+
+		appInstance.VolumeRefConfigList = make([]types.VolumeRefConfig,
+			len(cfgApp.VolumeRefList))
+		parseVolumeRefList(appInstance.VolumeRefConfigList, cfgApp.GetVolumeRefList())
+
+		// fill in the collect stats IP address of the App
+		appInstance.CollectStatsIPAddr = net.ParseIP(cfgApp.GetCollectStatsIPAddr())
+
+		// fill the app adapter config
+		parseAppNetworkConfig(&appInstance, cfgApp, config.Networks,
+			config.NetworkInstances, overlayCompat)
+		for _, conflict := range hostnameConflicts[uuidStr] {
+			log.Error(conflict.ErrStr)
+			appInstance.Errors = append(appInstance.Errors,
+				types.NewAppConfigError(conflict.IntfName,
+					types.AppConfigErrorBadHostname, conflict.ErrStr))
+		}
+		for _, quotaErr := range quotaErrors[uuidStr] {
+			log.Error(quotaErr.ErrStr)
+			appInstance.Errors = append(appInstance.Errors,
+				types.NewAppConfigError(quotaErr.IntfName,
+					types.AppConfigErrorNetworkQuota, quotaErr.ErrStr))
+		}
+		for _, conflict := range portMapConflicts[uuidStr] {
+			log.Error(conflict.ErrStr)
+			appInstance.Errors = append(appInstance.Errors,
+				types.NewAppConfigError(conflict.IntfName,
+					types.AppConfigErrorBadACL, conflict.ErrStr))
+		}
+		if errStr, ok := tooManyApps[uuidStr]; ok {
+			log.Error(errStr)
+			appInstance.Errors = append(appInstance.Errors,
+				types.NewAppConfigError("", types.AppConfigErrorTooManyApps,
+					errStr))
+		}
+
+		// I/O adapters
+		appInstance.IoAdapterList = nil
+		for _, adapter := range cfgApp.Adapters {
+			log.Tracef("Processing adapter type %d name %s",
+				adapter.Type, adapter.Name)
+			appInstance.IoAdapterList = append(appInstance.IoAdapterList,
+				types.IoAdapter{Type: types.IoType(adapter.Type),
+					Name:                adapter.Name,
+					AcceleratorMemoryMB: adapter.AcceleratorMemoryMb})
+		}
+		log.Functionf("Got adapters %v", appInstance.IoAdapterList)
+		for _, accelErr := range accelMemoryErrors[uuidStr] {
+			if accelErr.Warning {
+				log.Warn(accelErr.ErrStr)
+				continue
+			}
+			log.Error(accelErr.ErrStr)
+			appInstance.Errors = append(appInstance.Errors,
+				types.NewAppConfigError("",
+					types.AppConfigErrorAcceleratorMemory, accelErr.ErrStr))
+		}
+		appInstance.AffinityPolicy = types.AffinityPolicy(
+			cfgApp.GetAffinityPolicy())
+		placement := numaPlacements[uuidStr]
+		appInstance.FixedResources.NumaNodeSet = placement.NodeSet
+		appInstance.FixedResources.NumaNode = placement.Node
+		if placement.Warning {
+			log.Warn(placement.ErrStr)
+		} else if placement.ErrStr != "" {
+			log.Error(placement.ErrStr)
+			appInstance.Errors = append(appInstance.Errors,
+				types.NewAppConfigError("", types.AppConfigErrorAffinity,
+					placement.ErrStr))
+		}
+
+		cmd := cfgApp.GetRestart()
+		if cmd != nil {
+			appInstance.RestartCmd = parseAppInstanceOpsCmd(
+				appInstance.DisplayName, "restart", cmd, time.Now())
+		}
+		cmd = cfgApp.GetPurge()
+		if cmd != nil {
+			appInstance.PurgeCmd = parseAppInstanceOpsCmd(
+				appInstance.DisplayName, "purge", cmd, time.Now())
+		}
+		userData := cfgApp.GetUserData()
+		if userData != "" {
+			appInstance.CloudInitUserData = &userData
+		}
+		appInstance.RemoteConsole = cfgApp.GetRemoteConsole()
+		appInstance.CipherBlockStatus = parseCipherBlock(getconfigCtx, appInstance.Key(),
+			cfgApp.GetCipherData())
+		appInstance.ProfileList = cfgApp.ProfileList
+		appInstance.AllowLocalRestart = cfgApp.GetAllowLocalRestart()
+		appInstance.VolumeRetentionDays = cfgApp.GetVolumeRetentionDays()
+		if appInstance.VolumeRetentionDays > types.MaxVolumeRetentionDays {
+			errStr := fmt.Sprintf("app %s: volume retention of %d days exceeds the maximum of %d",
+				appInstance.DisplayName, appInstance.VolumeRetentionDays,
+				types.MaxVolumeRetentionDays)
+			log.Error(errStr)
+			appInstance.Errors = append(appInstance.Errors,
+				types.NewAppConfigError("", types.AppConfigErrorOther, errStr))
+			appInstance.VolumeRetentionDays = types.MaxVolumeRetentionDays
+		}
+		// LocalRestartCmd is not part of the controller config
+		item, _ := getconfigCtx.pubAppInstanceConfig.Get(appInstance.Key())
+		if item != nil {
+			oldConfig := item.(types.AppInstanceConfig)
+			appInstance.LocalRestartCmd = oldConfig.LocalRestartCmd
+			if isSuspiciousIdentityChange(oldConfig, appInstance) {
+				holdSuspiciousIdentityChange(getconfigCtx, oldConfig,
+					appInstance)
+				continue
+			}
+		}
+
+		checkPurgeForChanges(&appInstance)
+		maybeHoldAppActivate(getconfigCtx, &appInstance)
+
+		appInstance.LogPolicy = parseAppLogPolicy(cfgApp.GetLogPolicy(),
+			appInstance.DisplayName)
+		publishAppLogPolicyConfig(getconfigCtx, appInstance)
+		publishAppVolumeRetention(getconfigCtx, appInstance)
+		// Verify that it fits and if not publish with error
+		checkAndPublishAppInstanceConfig(getconfigCtx, appInstance)
+	}
+}
+
+// maybeHoldAppActivate holds the activation of app instances which are not
+// already active while a new base OS is being tested, since a rollback could
+// leave them half-created. releaseHeldAppInstances undoes this.
+func maybeHoldAppActivate(getconfigCtx *getconfigContext,
+	appInstance *types.AppInstanceConfig) {
+
+	if !appInstance.Activate || !getconfigCtx.updateInprogress {
+		return
+	}
+	ctx := getconfigCtx.zedagentCtx
+	if !ctx.globalConfig.GlobalValueBool(types.HoldAppActivateDuringUpdate) {
+		return
+	}
+	// The test of the base OS waits for the canary to be healthy
+	if appInstance.UUIDandVersion.UUID == getCanaryAppUUID(getconfigCtx) {
+		return
+	}
+	item, _ := getconfigCtx.pubAppInstanceConfig.Get(appInstance.Key())
+	if item != nil && item.(types.AppInstanceConfig).Activate {
+		return
+	}
+	log.Noticef("Holding activate of app instance %s %s during base OS testing",
+		appInstance.Key(), appInstance.DisplayName)
+	appInstance.Activate = false
+	appInstance.HeldForBaseOsTesting = true
+}
+
+// releaseHeldAppInstances activates the app instances which were held
+// during base OS testing
+func releaseHeldAppInstances(getconfigCtx *getconfigContext) {
+	items := getconfigCtx.pubAppInstanceConfig.GetAll()
+	for key, item := range items {
+		config := item.(types.AppInstanceConfig)
+		if !config.HeldForBaseOsTesting {
+			continue
+		}
+		log.Noticef("Releasing activate of app instance %s %s",
+			key, config.DisplayName)
+		config.Activate = true
+		config.HeldForBaseOsTesting = false
+		checkAndPublishAppInstanceConfig(getconfigCtx, config)
+	}
+}
+
+// isSuspiciousIdentityChange returns true if the app instance with the same
+// UUID changed wholesale, i.e., both its name and all of its volumes, without
+// a bump of the purge counter. That is most likely the controller reusing
+// the UUID for a different app, which would otherwise get the volumes of
+// the old one.
+func isSuspiciousIdentityChange(oldConfig types.AppInstanceConfig,
+	newConfig types.AppInstanceConfig) bool {
+
+	if newConfig.PurgeCmd.Counter > oldConfig.PurgeCmd.Counter {
+		return false
+	}
+	if oldConfig.DisplayName == newConfig.DisplayName {
+		return false
+	}
+	if len(oldConfig.VolumeRefConfigList) == 0 ||
+		len(newConfig.VolumeRefConfigList) == 0 {
+		return false
+	}
+	oldVolumes := make(map[uuid.UUID]bool)
+	for _, vrc := range oldConfig.VolumeRefConfigList {
+		oldVolumes[vrc.VolumeID] = true
+	}
+	for _, vrc := range newConfig.VolumeRefConfigList {
+		if oldVolumes[vrc.VolumeID] {
+			return false
+		}
+	}
+	return true
+}
+
+// holdSuspiciousIdentityChange keeps the old config of the app instance
+// active and publishes it with an error until the purge counter is bumped
+func holdSuspiciousIdentityChange(getconfigCtx *getconfigContext,
+	oldConfig types.AppInstanceConfig, newConfig types.AppInstanceConfig) {
+
+	errStr := fmt.Sprintf("app %s: suspicious identity change from %s to %s; "+
+		"the name and all volumes changed without a purge",
+		oldConfig.Key(), oldConfig.DisplayName, newConfig.DisplayName)
+	log.Error(errStr)
+	var errs []types.AppConfigError
+	for _, appErr := range oldConfig.Errors {
+		if appErr.Category != types.AppConfigErrorIdentityChange {
+			errs = append(errs, appErr)
+		}
+	}
+	oldConfig.Errors = append(errs, types.NewAppConfigError("",
+		types.AppConfigErrorIdentityChange, errStr))
+	checkAndPublishAppInstanceConfig(getconfigCtx, oldConfig)
+}
+
+// appPurgeBaseline records the fields which only take effect after a purge,
+// as they were when the current PurgeCmd.Counter was first seen.
+type appPurgeBaseline struct {
+	purgeCounter      uint32
+	cloudInitUserData string
+	cipherData        []byte
+}
+
+// Indexed by UUID string
+var appPurgeBaselines = make(map[string]appPurgeBaseline)
+
+// checkPurgeForChanges sets NeedsPurgeForChanges if the cloud-init data
+// differs from what was in place for the current PurgeCmd.Counter.
+// A new counter, or reverting the change, clears the flag.
+func checkPurgeForChanges(appInstance *types.AppInstanceConfig) {
+	key := appInstance.Key()
+	current := appPurgeBaseline{
+		purgeCounter: appInstance.PurgeCmd.Counter,
+		cipherData:   appInstance.CipherData,
+	}
+	if appInstance.CloudInitUserData != nil {
+		current.cloudInitUserData = *appInstance.CloudInitUserData
+	}
+	appInstance.NeedsPurgeForChanges = false
+	appInstance.PendingPurgeChanges = nil
+	baseline, ok := appPurgeBaselines[key]
+	if !ok || baseline.purgeCounter != current.purgeCounter {
+		appPurgeBaselines[key] = current
+		return
+	}
+	var changed []string
+	if baseline.cloudInitUserData != current.cloudInitUserData {
+		changed = append(changed, "CloudInitUserData")
+	}
+	if !bytes.Equal(baseline.cipherData, current.cipherData) {
+		changed = append(changed, "CipherData")
+	}
+	if len(changed) != 0 {
+		log.Warnf("App instance %s: %v changed without purge",
+			key, changed)
+		appInstance.NeedsPurgeForChanges = true
+		appInstance.PendingPurgeChanges = changed
+	}
+}
+
+var systemAdaptersPrevConfigHash []byte
+
+func parseSystemAdapterConfig(config *zconfig.EdgeDevConfig,
+	getconfigCtx *getconfigContext, forceParse bool) {
+
+	sysAdapters := config.GetSystemAdapterList()
+	h := sha256.New()
+	for _, a := range sysAdapters {
+		computeConfigElementSha(h, a)
+	}
+	configHash := h.Sum(nil)
 	same := bytes.Equal(configHash, systemAdaptersPrevConfigHash)
 	if same && !forceParse {
 		return
@@ -584,6 +1746,7 @@ func parseSystemAdapterConfig(config *zconfig.EdgeDevConfig,
 			systemAdaptersPrevConfigHash, configHash, sysAdapters, forceParse)
 	}
 	systemAdaptersPrevConfigHash = configHash
+	getconfigCtx.unresolvedNetworkUUIDs = make(map[string]bool)
 
 	// Check if we have any with Uplink/IsMgmt set, in which case we
 	// infer the version
@@ -592,21 +1755,61 @@ func parseSystemAdapterConfig(config *zconfig.EdgeDevConfig,
 	version := types.DPCInitial
 	for _, sysAdapter := range sysAdapters {
 		if sysAdapter.Uplink {
+			deprecatedSystemAdapterUplink.hit(sysAdapter.Name)
 			version = types.DPCIsMgmt
 		}
 	}
 
 	newPorts := []types.NetworkPortConfig{}
+	report := types.SystemAdapterReport{}
+	// Index in report.Results for each of newPorts
+	resultIndex := []int{}
 	for _, sysAdapter := range sysAdapters {
-		port := parseOneSystemAdapterConfig(getconfigCtx, sysAdapter, version)
-		if port != nil {
+		result := types.SystemAdapterResult{
+			Name:           sysAdapter.Name,
+			LowerLayerName: sysAdapter.LowerLayerName,
+		}
+		port, err := parseOneSystemAdapterConfig(getconfigCtx, sysAdapter,
+			version)
+		if err != nil {
+			result.Disposition = types.SystemAdapterRejected
+			result.Reason = err.Error()
+		} else {
+			resultIndex = append(resultIndex, len(report.Results))
 			newPorts = append(newPorts, *port)
 		}
+		report.Results = append(report.Results, result)
+	}
+	checkPortDuplicateLabels(newPorts)
+	checkPortIfNameCollisions(getconfigCtx, newPorts)
+	checkPortSharedLabels(newPorts)
+	for i, port := range newPorts {
+		result := &report.Results[resultIndex[i]]
+		if port.HasError() {
+			result.Disposition = types.SystemAdapterAcceptedWithError
+			result.Reason = port.LastError
+		} else {
+			result.Disposition = types.SystemAdapterAccepted
+		}
 	}
+	// Do not take a working device off the air with a config where
+	// every port failed
+	held := allPortsFailed(newPorts) &&
+		hasWorkingMgmtPort(getconfigCtx.devicePortConfig)
+	publishSystemAdapterReport(getconfigCtx, report)
+	parseStatus := types.NewDevicePortConfigParseStatus(
+		hex.EncodeToString(configHash), report)
+	parseStatus.Held = held
+	publishDevicePortConfigParseStatus(getconfigCtx, parseStatus)
 	if len(newPorts) == 0 {
 		log.Functionf("parseSystemAdapterConfig: No Port configuration present")
 		return
 	}
+	if held {
+		log.Warnf("parseSystemAdapterConfig: all %d ports failed to parse; keeping the previous DevicePortConfig",
+			len(newPorts))
+		return
+	}
 	portConfig := &types.DevicePortConfig{}
 	portConfig.Version = version
 	portConfig.Ports = newPorts
@@ -661,11 +1864,203 @@ func parseSystemAdapterConfig(config *zconfig.EdgeDevConfig,
 	log.Functionf("parseSystemAdapterConfig: Done")
 }
 
+// allPortsFailed returns true if there are ports and all of them have
+// an error
+func allPortsFailed(ports []types.NetworkPortConfig) bool {
+	for _, port := range ports {
+		if !port.HasError() {
+			return false
+		}
+	}
+	return len(ports) != 0
+}
+
+// hasWorkingMgmtPort returns true if one of the management ports of the
+// DevicePortConfig has no error
+func hasWorkingMgmtPort(portConfig types.DevicePortConfig) bool {
+	for _, port := range portConfig.Ports {
+		if port.IsMgmt && !port.HasError() {
+			return true
+		}
+	}
+	return false
+}
+
+// publishSystemAdapterReport publishes the report unless unchanged
+func publishSystemAdapterReport(getconfigCtx *getconfigContext,
+	report types.SystemAdapterReport) {
+
+	pub := getconfigCtx.pubSystemAdapterReport
+	if item, _ := pub.Get(report.Key()); item != nil &&
+		cmp.Equal(item.(types.SystemAdapterReport), report) {
+		return
+	}
+	pub.Publish(report.Key(), report)
+}
+
+// publishDevicePortConfigParseStatus publishes the status unless unchanged
+func publishDevicePortConfigParseStatus(getconfigCtx *getconfigContext,
+	status types.DevicePortConfigParseStatus) {
+
+	if status.WarningCount != 0 || status.ErrorCount != 0 {
+		log.Warnf("parseSystemAdapterConfig: %d of %d system adapters failed to parse for config sha %s",
+			status.WarningCount+status.ErrorCount,
+			status.OkCount+status.WarningCount+status.ErrorCount,
+			status.ConfigSha)
+	}
+	pub := getconfigCtx.pubDevicePortConfigParseStatus
+	if item, _ := pub.Get(status.Key()); item != nil &&
+		cmp.Equal(item.(types.DevicePortConfigParseStatus), status) {
+		return
+	}
+	pub.Publish(status.Key(), status)
+}
+
+// checkPortDuplicateLabels records a failure on the ports whose
+// Logicallabel is the one of an earlier port, e.g., when the controller
+// sends two SystemAdapters with the same name. The first port is kept.
+func checkPortDuplicateLabels(ports []types.NetworkPortConfig) {
+	seen := make(map[string]bool)
+	for i := range ports {
+		port := &ports[i]
+		if port.Logicallabel == "" {
+			continue
+		}
+		if !seen[port.Logicallabel] {
+			seen[port.Logicallabel] = true
+			continue
+		}
+		errStr := fmt.Sprintf("Port %s is configured more than once; "+
+			"using the first", port.Logicallabel)
+		log.Errorf("parseSystemAdapterConfig: %s", errStr)
+		port.RecordFailure(errStr)
+	}
+}
+
+// checkPortIfNameCollisions records a failure on the ports whose IfName
+// collides with another port or phyio. A port with the ifname from its
+// phyio keeps it; ports which fell back to a label as IfName fail when the
+// label is the ifname of another phyio or the IfName of another port.
+func checkPortIfNameCollisions(getconfigCtx *getconfigContext,
+	ports []types.NetworkPortConfig) {
+
+	// Phylabel of the phyio with the ifname
+	ifNameOwners := make(map[string]string)
+	for _, phyio := range getconfigCtx.zedagentCtx.physicalIoAdapterMap {
+		if phyio.Phyaddr.Ifname != "" {
+			ifNameOwners[phyio.Phyaddr.Ifname] = phyio.Phylabel
+		}
+	}
+	for i := range ports {
+		port := &ports[i]
+		owner, hasOwner := ifNameOwners[port.IfName]
+		if hasOwner && owner != port.Phylabel {
+			errStr := fmt.Sprintf("Port %s has ifname %s which is the "+
+				"ifname of phyio %s", port.Logicallabel, port.IfName, owner)
+			log.Errorf("parseSystemAdapterConfig: %s", errStr)
+			port.RecordFailure(errStr)
+			continue
+		}
+		var others []string
+		otherOwner := false
+		for j := range ports {
+			if j == i || ports[j].IfName != port.IfName {
+				continue
+			}
+			if ports[j].Logicallabel == port.Logicallabel {
+				// Reported by checkPortDuplicateLabels
+				continue
+			}
+			others = append(others, ports[j].Logicallabel)
+			if hasOwner && ports[j].Phylabel == owner {
+				otherOwner = true
+			}
+		}
+		if len(others) == 0 || (hasOwner && !otherOwner) {
+			continue
+		}
+		errStr := fmt.Sprintf("Port %s has ifname %s which is also used "+
+			"by %s", port.Logicallabel, port.IfName,
+			strings.Join(others, ", "))
+		log.Errorf("parseSystemAdapterConfig: %s", errStr)
+		port.RecordFailure(errStr)
+	}
+}
+
+// parseSharedLabels returns the shared labels of the SystemAdapter followed
+// by the implicit ones. A reserved label set by the controller is recorded
+// as an error on the port and dropped.
+func parseSharedLabels(port *types.NetworkPortConfig, labels []string) []string {
+	var sharedLabels []string
+	seen := make(map[string]bool)
+	for _, label := range labels {
+		if label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+		if types.IsReservedSharedLabel(label) {
+			errStr := fmt.Sprintf("Port %s has the reserved shared label %s; "+
+				"ignored", port.Logicallabel, label)
+			log.Errorf("parseSystemAdapterConfig: %s", errStr)
+			port.RecordFailure(errStr)
+			continue
+		}
+		sharedLabels = append(sharedLabels, label)
+	}
+	if port.IsMgmt {
+		sharedLabels = append(sharedLabels, types.UplinkLabel)
+		if port.Cost == 0 {
+			sharedLabels = append(sharedLabels, types.FreeUplinkLabel)
+		}
+	}
+	return sharedLabels
+}
+
+// checkPortSharedLabels drops the shared labels from the controller which
+// are also the logicallabel of a port, and records the error on the port
+// with the shared label. The implicit labels are kept since a port can be
+// named after them.
+func checkPortSharedLabels(ports []types.NetworkPortConfig) {
+	portLabels := make(map[string]bool)
+	for _, port := range ports {
+		portLabels[port.Logicallabel] = true
+	}
+	for i := range ports {
+		port := &ports[i]
+		var sharedLabels []string
+		for _, label := range port.SharedLabels {
+			if !portLabels[label] || types.IsReservedSharedLabel(label) {
+				sharedLabels = append(sharedLabels, label)
+				continue
+			}
+			errStr := fmt.Sprintf("Port %s has the shared label %s which "+
+				"is the logicallabel of a port; ignored",
+				port.Logicallabel, label)
+			log.Errorf("parseSystemAdapterConfig: %s", errStr)
+			port.RecordFailure(errStr)
+		}
+		port.SharedLabels = sharedLabels
+	}
+}
+
+// unresolvedNetworksAvailable returns true if a network which a system
+// adapter could not find has been published since
+func unresolvedNetworksAvailable(getconfigCtx *getconfigContext) bool {
+	for networkUUID := range getconfigCtx.unresolvedNetworkUUIDs {
+		if _, err := getconfigCtx.pubNetworkXObjectConfig.Get(networkUUID); err == nil {
+			log.Noticef("unresolvedNetworksAvailable: network %s found",
+				networkUUID)
+			return true
+		}
+	}
+	return false
+}
+
 // Returns a port if it should be added to the list; some errors result in
 // adding a port to to DevicePortConfig with ErrorAndTime set.
 func parseOneSystemAdapterConfig(getconfigCtx *getconfigContext,
 	sysAdapter *zconfig.SystemAdapter,
-	version types.DevicePortConfigVersion) *types.NetworkPortConfig {
+	version types.DevicePortConfigVersion) (*types.NetworkPortConfig, error) {
 
 	log.Functionf("XXX parseOneSystemAdapterConfig name %s lowerLayerName %s",
 		sysAdapter.Name, sysAdapter.LowerLayerName)
@@ -689,13 +2084,21 @@ func parseOneSystemAdapterConfig(getconfigCtx *getconfigContext,
 		errStr := fmt.Sprintf("Missing phyio for %s lower %s; ignored",
 			sysAdapter.Name, sysAdapter.LowerLayerName)
 		log.Error(errStr)
-		return nil
+		return nil, errors.New(errStr)
+	}
+	if phyio.HasError() {
+		// Keep the port, e.g. a management port, with the error.
+		// We will re-check when phyio changes.
+		errStr := fmt.Sprintf("phyio for %s lower %s has error %s",
+			sysAdapter.Name, sysAdapter.LowerLayerName, phyio.Error)
+		log.Error(errStr)
+		port.RecordFailure(errStr)
 	}
 	if !types.IoType(phyio.Ptype).IsNet() {
 		errStr := fmt.Sprintf("phyio for %s lower %s not IsNet; ignored",
 			sysAdapter.Name, sysAdapter.LowerLayerName)
 		log.Error(errStr)
-		return nil
+		return nil, errors.New(errStr)
 	}
 	if port.Logicallabel != phyio.Logicallabel {
 		errStr := fmt.Sprintf("phyio for %s lower %s mismatched logicallabel %s vs %s",
@@ -716,27 +2119,31 @@ func parseOneSystemAdapterConfig(getconfigCtx *getconfigContext,
 		}
 	}
 	// We check if any phyio has FreeUplink set. If so we operate
-	// in old mode which means that cost is 1 if FreeUplink == false
+	// in old mode which means that the cost is the highest one if
+	// FreeUplink == false, and free otherwise.
 	// XXX Remove this when all controllers send cost.
 	oldController := anyDeviceIoWithFreeUplink(getconfigCtx)
 	log.Functionf("Found phyio for %s: free %t, oldController: %t",
 		sysAdapter.Name, phyio.UsagePolicy.FreeUplink, oldController)
 
 	var portCost uint8
-	if sysAdapter.Cost > 255 {
-		log.Warnf("SysAdpter cost %d for %s clamped to 255",
-			sysAdapter.Cost, sysAdapter.Name)
-		portCost = 255
+	if sysAdapter.Cost > uint32(types.PortCostMax) {
+		log.Warnf("SysAdpter cost %d for %s clamped to %d",
+			sysAdapter.Cost, sysAdapter.Name, types.PortCostMax)
+		portCost = types.PortCostMax
 	} else {
 		portCost = uint8(sysAdapter.Cost)
 	}
+	if sysAdapter.FreeUplink {
+		deprecatedSystemAdapterFreeUplink.hit(sysAdapter.Name)
+	}
 	if portCost == 0 {
 		if phyio.UsagePolicy.FreeUplink || sysAdapter.FreeUplink {
 			portCost = 0
 		} else if oldController {
-			log.Warnf("XXX oldController and !FreeUplink; assume %s cost=1",
-				sysAdapter.Name)
-			portCost = 1
+			log.Warnf("XXX oldController and !FreeUplink; assume %s cost=%d",
+				sysAdapter.Name, types.PortCostMax)
+			portCost = types.PortCostMax
 		}
 	}
 
@@ -756,20 +2163,17 @@ func parseOneSystemAdapterConfig(getconfigCtx *getconfigContext,
 
 	port.IsMgmt = isMgmt
 	port.Cost = portCost
+	port.SharedLabels = parseSharedLabels(port, sysAdapter.SharedLabels)
 	port.Dhcp = types.DT_NONE
-	var ip net.IP
 	var network *types.NetworkXObjectConfig
-	if sysAdapter.Addr != "" {
-		ip = net.ParseIP(sysAdapter.Addr)
-		if ip == nil {
-			errStr := fmt.Sprintf("Device Config Error. Port %s has Bad "+
-				"SysAdapter.Addr %s. The IP address is ignored. Please fix the "+
-				"device configuration.", sysAdapter.Name, sysAdapter.Addr)
-			log.Errorf("parseSystemAdapterConfig: %s", errStr)
-			port.RecordFailure(errStr)
-			// IP will not be set below
-		}
-		// Note that ip is not used unless we have a network UUID
+	// Note that ips are not used unless we have a network UUID
+	ips, addrErrStrs := parseSystemAdapterAddrs(sysAdapter)
+	for _, addrErrStr := range addrErrStrs {
+		errStr := fmt.Sprintf("Device Config Error. Port %s has %s. "+
+			"The IP address is ignored. Please fix the device configuration.",
+			sysAdapter.Name, addrErrStr)
+		log.Errorf("parseSystemAdapterConfig: %s", errStr)
+		port.RecordFailure(errStr)
 	}
 	if sysAdapter.NetworkUUID != "" &&
 		sysAdapter.NetworkUUID != nilUUID.String() {
@@ -778,7 +2182,11 @@ func parseOneSystemAdapterConfig(getconfigCtx *getconfigContext,
 		// and copy proxy and other configuration
 		networkXObject, err := getconfigCtx.pubNetworkXObjectConfig.Get(sysAdapter.NetworkUUID)
 		if err != nil {
-			// XXX when do we retry looking for the networkXObject?
+			// Parsed again once the network is published
+			if getconfigCtx.unresolvedNetworkUUIDs == nil {
+				getconfigCtx.unresolvedNetworkUUIDs = make(map[string]bool)
+			}
+			getconfigCtx.unresolvedNetworkUUIDs[sysAdapter.NetworkUUID] = true
 			errStr := fmt.Sprintf("Device Config Error. Port %s configured with "+
 				"UNKNOWN Network UUID (%s). Err: %s. Please fix the "+
 				"device configuration.",
@@ -798,28 +2206,81 @@ func parseOneSystemAdapterConfig(getconfigCtx *getconfigContext,
 			}
 		}
 
+		addrErr := false
 		if network != nil {
-			if ip != nil {
-				addrSubnet := network.Subnet
-				addrSubnet.IP = ip
-				port.AddrSubnet = addrSubnet.String()
+			// The valid addresses are kept when others do not fit
+			var ipsInSubnet []net.IP
+			var addrErrStrs []string
+			for _, ip := range ips {
+				subnet := networkSubnetFor(network, ip)
+				if subnet.IP == nil {
+					subnet = network.Subnet
+				}
+				addrSubnet, err := staticAddrSubnet(ip, subnet)
+				if err == nil {
+					port.AddrSubnets = append(port.AddrSubnets, addrSubnet)
+					ipsInSubnet = append(ipsInSubnet, ip)
+				} else if network.Dhcp == types.DT_STATIC {
+					addrErrStrs = append(addrErrStrs,
+						fmt.Sprintf("%s: %s", ip, err))
+				} else {
+					log.Warnf("parseSystemAdapterConfig: port %s ignoring SysAdapter address %s: %s",
+						port.IfName, ip, err)
+				}
+			}
+			ips = ipsInSubnet
+			if len(port.AddrSubnets) != 0 {
+				port.AddrSubnet = port.AddrSubnets[0]
+			}
+			if len(addrErrStrs) != 0 {
+				errStr := fmt.Sprintf("Port %s has SysAdapter addresses "+
+					"which do not fit network %s: %s",
+					port.IfName, network.UUID,
+					strings.Join(addrErrStrs, "; "))
+				log.Errorf("parseSystemAdapterConfig: %s", errStr)
+				port.RecordFailure(errStr)
+				addrErr = true
 			}
 			port.WirelessCfg = network.WirelessCfg
 			port.Gateway = network.Gateway
 			port.DomainName = network.DomainName
+			port.DomainNames = network.DomainNames
 			port.NtpServer = network.NtpServer
-			port.DnsServers = network.DnsServers
+			port.DnsServers, _ = uniqueDNSServers(network.DnsServers,
+				fmt.Sprintf("parseSystemAdapterConfig: port %s", port.IfName))
 			// Need to be careful since zedcloud can feed us bad Dhcp type
 			port.Dhcp = network.Dhcp
+			port.AddrMode = network.AddrMode
 		}
 		switch port.Dhcp {
 		case types.DT_STATIC:
-			if port.AddrSubnet == "" {
+			if network != nil && network.Type == types.NT_IPV6 &&
+				network.Subnet.IP == nil {
+				errStr := fmt.Sprintf("Port %s Configured as DT_STATIC on "+
+					"IPv6 network %s which has no subnet",
+					port.IfName, network.UUID)
+				log.Errorf("parseSystemAdapterConfig: %s", errStr)
+				port.RecordFailure(errStr)
+			} else if port.AddrSubnet == "" && !addrErr {
 				errStr := fmt.Sprintf("Port %s Configured as DT_STATIC but "+
 					"missing subnet address. SysAdapter - Name: %s, Addr:%s",
 					port.IfName, sysAdapter.Name, sysAdapter.Addr)
 				log.Errorf("parseSystemAdapterConfig: %s", errStr)
 				port.RecordFailure(errStr)
+			} else if port.AddrSubnet != "" && port.Gateway != nil &&
+				!port.Gateway.IsUnspecified() &&
+				!hasIPVersionOf(ips, port.Gateway) {
+				errStr := fmt.Sprintf("Port %s Configured as DT_STATIC "+
+					"with gateway %s which is not of the IP version of "+
+					"its addresses %s", port.IfName, port.Gateway,
+					strings.Join(port.AddrSubnets, ", "))
+				log.Errorf("parseSystemAdapterConfig: %s", errStr)
+				port.RecordFailure(errStr)
+			} else if len(ips) > 1 && port.Gateway != nil &&
+				!port.Gateway.IsUnspecified() {
+				// There is one gateway for both IP versions
+				log.Warnf("parseSystemAdapterConfig: port %s has no static default route for the IP version other than of gateway %s",
+					port.IfName, port.Gateway)
 			}
 		case types.DT_CLIENT:
 			// Do nothing
@@ -850,7 +2311,75 @@ func parseOneSystemAdapterConfig(getconfigCtx *getconfigContext,
 		log.Errorf("parseSystemAdapterConfig: %s", errStr)
 		port.RecordFailure(errStr)
 	}
-	return port
+	return port, nil
+}
+
+// parseSystemAdapterAddrs returns the static addresses of the system
+// adapter from Addrs, or from Addr if there are none, and an error string
+// for each one which is ignored
+func parseSystemAdapterAddrs(sysAdapter *zconfig.SystemAdapter) ([]net.IP, []string) {
+	addrs := sysAdapter.Addrs
+	if len(addrs) == 0 && sysAdapter.Addr != "" {
+		addrs = []string{sysAdapter.Addr}
+	}
+	var ips []net.IP
+	var errStrs []string
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			errStrs = append(errStrs,
+				fmt.Sprintf("Bad SysAdapter address %s", addr))
+			continue
+		}
+		if hasIPVersionOf(ips, ip) {
+			errStrs = append(errStrs,
+				fmt.Sprintf("SysAdapter address %s in addition to another "+
+					"address of its IP version", addr))
+			continue
+		}
+		ips = append(ips, ip)
+	}
+	return ips, errStrs
+}
+
+// hasIPVersionOf returns true if one of ips is of the IP version of ip
+func hasIPVersionOf(ips []net.IP, ip net.IP) bool {
+	for _, other := range ips {
+		if (other.To4() == nil) == (ip.To4() == nil) {
+			return true
+		}
+	}
+	return false
+}
+
+// networkSubnetFor returns the subnet of the network of the IP version of
+// ip, or an empty subnet if there is none
+func networkSubnetFor(network *types.NetworkXObjectConfig, ip net.IP) net.IPNet {
+	subnets := append([]net.IPNet{network.Subnet}, network.ExtraSubnets...)
+	for _, subnet := range subnets {
+		if subnet.IP != nil && (subnet.IP.To4() == nil) == (ip.To4() == nil) {
+			return subnet
+		}
+	}
+	return net.IPNet{}
+}
+
+// staticAddrSubnet returns the address in CIDR notation with the prefix
+// length of the subnet, which must contain it
+func staticAddrSubnet(ip net.IP, subnet net.IPNet) (string, error) {
+	if subnet.IP == nil {
+		return "", fmt.Errorf("no subnet for address %s", ip)
+	}
+	if (ip.To4() == nil) != (subnet.IP.To4() == nil) {
+		return "", fmt.Errorf("address %s is not of the IP version of subnet %s",
+			ip, subnet.String())
+	}
+	if !subnet.Contains(ip) {
+		return "", fmt.Errorf("address %s is not in subnet %s",
+			ip, subnet.String())
+	}
+	addrSubnet := net.IPNet{IP: ip, Mask: subnet.Mask}
+	return addrSubnet.String(), nil
 }
 
 var deviceIoListPrevConfigHash []byte
@@ -894,13 +2423,23 @@ func parseDeviceIoListConfig(config *zconfig.EdgeDevConfig,
 			Logicallabel: ioDevicePtr.Logicallabel,
 			Assigngrp:    ioDevicePtr.Assigngrp,
 			Usage:        ioDevicePtr.Usage,
+
+			AcceleratorMemoryMB: ioDevicePtr.AcceleratorMemoryMb,
+		}
+		if numa := ioDevicePtr.GetNuma(); numa != nil {
+			port.NumaNodeKnown = true
+			port.NumaNode = numa.GetNode()
 		}
 		if ioDevicePtr.UsagePolicy != nil {
 			// Need to keep this to make proper determination
 			// for SystemAdapter
 			port.UsagePolicy.FreeUplink = ioDevicePtr.UsagePolicy.FreeUplink
+			if port.UsagePolicy.FreeUplink {
+				deprecatedPhyIOFreeUplink.hit(ioDevicePtr.Phylabel)
+			}
 		}
 
+		var unknownKeys []string
 		for key, value := range ioDevicePtr.Phyaddrs {
 			key = strings.ToLower(key)
 			switch key {
@@ -918,12 +2457,22 @@ func parseDeviceIoListConfig(config *zconfig.EdgeDevConfig,
 				port.Phyaddr.UsbAddr = value
 			default:
 				port.Phyaddr.UnknownType = value
-				log.Warnf("Unrecognized Physical address Ignored: "+
-					"key: %s, value: %s", key, value)
+				unknownKeys = append(unknownKeys, key)
 			}
 		}
+		if len(unknownKeys) != 0 {
+			// Typically a misspelled key, which leaves the adapter
+			// without the intended address
+			sort.Strings(unknownKeys)
+			addDeviceIoError(&port, fmt.Sprintf("unknown phyaddrs keys %s",
+				strings.Join(unknownKeys, ", ")))
+		}
 		phyIoAdapterList.AdapterList = append(phyIoAdapterList.AdapterList,
 			port)
+	}
+	checkDuplicateDeviceIoLabels(phyIoAdapterList.AdapterList)
+	checkAssignmentGroups(phyIoAdapterList.AdapterList)
+	for _, port := range phyIoAdapterList.AdapterList {
 		getconfigCtx.zedagentCtx.physicalIoAdapterMap[port.Phylabel] = port
 	}
 	phyIoAdapterList.Initialized = true
@@ -933,6 +2482,147 @@ func parseDeviceIoListConfig(config *zconfig.EdgeDevConfig,
 	return true
 }
 
+// checkDuplicateDeviceIoLabels sets the error on all adapters which share
+// their Phylabel or Logicallabel with another adapter, in addition to any
+// error already set
+func checkDuplicateDeviceIoLabels(adapters []types.PhysicalIOAdapter) {
+	phylabels := make(map[string]int)
+	logicallabels := make(map[string]int)
+	for _, port := range adapters {
+		phylabels[port.Phylabel]++
+		if port.Logicallabel != "" {
+			logicallabels[port.Logicallabel]++
+		}
+	}
+	for i := range adapters {
+		port := &adapters[i]
+		var errStrs []string
+		if phylabels[port.Phylabel] > 1 {
+			errStrs = append(errStrs, fmt.Sprintf("duplicate phylabel %s",
+				port.Phylabel))
+		}
+		if logicallabels[port.Logicallabel] > 1 {
+			errStrs = append(errStrs, fmt.Sprintf("duplicate logicallabel %s",
+				port.Logicallabel))
+		}
+		if len(errStrs) == 0 {
+			continue
+		}
+		addDeviceIoError(port, strings.Join(errStrs, "; "))
+	}
+}
+
+// pciGetIOMMUGroup is replaced in tests
+var pciGetIOMMUGroup = types.PCIGetIOMMUGroup
+
+// deviceIoAssignKind returns how the adapter is assigned to an app, which
+// needs to be the same for the members of an assignment group. Empty if
+// the adapter has no address for assignment.
+func deviceIoAssignKind(port types.PhysicalIOAdapter) string {
+	switch {
+	case port.Phyaddr.PciLong != "":
+		return "PCI"
+	case port.Phyaddr.UsbAddr != "":
+		return "USB"
+	case port.Phyaddr.Serial != "" || port.Phyaddr.Irq != "" ||
+		port.Phyaddr.Ioports != "":
+		return "legacy"
+	case types.IoType(port.Ptype).IsNet() && port.Phyaddr.Ifname != "":
+		// PciLong is determined from the ifname
+		return "PCI"
+	}
+	return ""
+}
+
+// checkAssignmentGroups sets the error on all members of an assignment
+// group which mixes adapters assigned in different ways, e.g., USB and
+// PCI, or has PCI addresses in different IOMMU groups. Groups with a single
+// member are not checked.
+func checkAssignmentGroups(adapters []types.PhysicalIOAdapter) {
+	groups := make(map[string][]int)
+	var names []string
+	for i, port := range adapters {
+		if port.Assigngrp == "" {
+			continue
+		}
+		if _, ok := groups[port.Assigngrp]; !ok {
+			names = append(names, port.Assigngrp)
+		}
+		groups[port.Assigngrp] = append(groups[port.Assigngrp], i)
+	}
+	for _, name := range names {
+		members := groups[name]
+		if len(members) < 2 {
+			continue
+		}
+		var errStrs []string
+		kinds := make(map[string][]string)
+		var kindNames []string
+		iommuGroups := make(map[string][]string)
+		var iommuNames []string
+		for _, i := range members {
+			port := adapters[i]
+			if kind := deviceIoAssignKind(port); kind != "" {
+				if _, ok := kinds[kind]; !ok {
+					kindNames = append(kindNames, kind)
+				}
+				kinds[kind] = append(kinds[kind], port.Phylabel)
+			}
+			if port.Phyaddr.PciLong == "" {
+				continue
+			}
+			// Not all devices have the PCI device or an IOMMU
+			iommuGroup, err := pciGetIOMMUGroup(port.Phyaddr.PciLong)
+			if err != nil {
+				log.Functionf("checkAssignmentGroups: %s", err)
+				continue
+			}
+			if _, ok := iommuGroups[iommuGroup]; !ok {
+				iommuNames = append(iommuNames, iommuGroup)
+			}
+			iommuGroups[iommuGroup] = append(iommuGroups[iommuGroup],
+				port.Phylabel)
+		}
+		if len(kindNames) > 1 {
+			var mixed []string
+			for _, kind := range kindNames {
+				mixed = append(mixed, fmt.Sprintf("%s %s", kind,
+					strings.Join(kinds[kind], ", ")))
+			}
+			errStrs = append(errStrs, fmt.Sprintf(
+				"assigngrp %s mixes %s", name, strings.Join(mixed, " with ")))
+		}
+		if len(iommuNames) > 1 {
+			var mixed []string
+			for _, iommuGroup := range iommuNames {
+				mixed = append(mixed, fmt.Sprintf("%s in %s",
+					strings.Join(iommuGroups[iommuGroup], ", "), iommuGroup))
+			}
+			errStrs = append(errStrs, fmt.Sprintf(
+				"assigngrp %s spans IOMMU groups: %s", name,
+				strings.Join(mixed, "; ")))
+		}
+		if len(errStrs) == 0 {
+			continue
+		}
+		for _, i := range members {
+			addDeviceIoError(&adapters[i], strings.Join(errStrs, "; "))
+		}
+	}
+}
+
+// addDeviceIoError sets the error on the adapter, in addition to any
+// error already set
+func addDeviceIoError(port *types.PhysicalIOAdapter, errStr string) {
+	if port.HasError() {
+		errStr = fmt.Sprintf("%s; %s", port.Error, errStr)
+	} else {
+		errStr = fmt.Sprintf("phyio %s: %s", port.Phylabel, errStr)
+	}
+	log.Errorf("parseDeviceIoListConfig: %s", errStr)
+	port.SetErrorNow(errStr)
+}
+
 func lookupDeviceIoPhylabel(getconfigCtx *getconfigContext, label string) *types.PhysicalIOAdapter {
 	for _, port := range getconfigCtx.zedagentCtx.physicalIoAdapterMap {
 		if port.Phylabel == label {
@@ -1019,11 +2709,30 @@ func publishDatastoreConfig(ctx *getconfigContext,
 		datastore.Fqdn = ds.Fqdn
 		datastore.Dpath = ds.Dpath
 		datastore.DsType = ds.DType.String()
+		if err := validateDatastoreType(ds.DType); err != nil {
+			errStr := fmt.Sprintf("datastore %s: %s", ds.Id, err)
+			log.Errorf("publishDatastoreConfig: %s", errStr)
+			datastore.ConfigError.SetErrorNow(errStr)
+		} else if ds.DType == zconfig.DsType_DsContainerRegistryCache {
+			datastore.UpstreamFqdn = ds.GetUpstreamFqdn()
+			datastore.UpstreamDpath = ds.GetUpstreamDpath()
+			datastore.UpstreamCipherBlockStatus = parseCipherBlock(ctx,
+				datastore.Key()+"-upstream", ds.GetUpstreamCipherData())
+			if err := validateRegistryCache(ds); err != nil {
+				errStr := fmt.Sprintf("datastore %s: %s", ds.Id, err)
+				log.Errorf("publishDatastoreConfig: %s", errStr)
+				datastore.ConfigError.SetErrorNow(errStr)
+			}
+		}
 		datastore.ApiKey = ds.ApiKey
 		datastore.Password = ds.Password
 		datastore.Region = ds.Region
 		// XXX compatibility with unmodified zedcloud datastores
-		// default to "us-west-2"
+		// default to the configured region, or "us-west-2"
+		if datastore.Region == "" {
+			datastore.Region = ctx.zedagentCtx.globalConfig.GlobalValueString(
+				types.DefaultDatastoreRegion)
+		}
 		if datastore.Region == "" {
 			datastore.Region = "us-west-2"
 		}
@@ -1032,10 +2741,156 @@ func publishDatastoreConfig(ctx *getconfigContext,
 
 		datastore.CipherBlockStatus = parseCipherBlock(ctx, datastore.Key(),
 			ds.GetCipherData())
+		if item, _ := ctx.pubDatastoreConfig.Get(datastore.Key()); item != nil {
+			prev := item.(types.DatastoreConfig)
+			// Unchanged errors keep their time
+			keepErrorTime(&datastore.ConfigError, prev.ConfigError)
+			keepErrorTime(&datastore.CipherBlockStatus.ErrorAndTime,
+				prev.CipherBlockStatus.ErrorAndTime)
+			keepErrorTime(&datastore.UpstreamCipherBlockStatus.ErrorAndTime,
+				prev.UpstreamCipherBlockStatus.ErrorAndTime)
+			if cmp.Equal(prev, *datastore) {
+				log.Tracef("publishDatastoreConfig: %s unchanged",
+					datastore.Key())
+				continue
+			}
+		}
 		ctx.pubDatastoreConfig.Publish(datastore.Key(), *datastore)
 	}
 }
 
+// keepErrorTime sets the time of the error to the previous one if the
+// error did not change
+func keepErrorTime(et *types.ErrorAndTime, prev types.ErrorAndTime) {
+	if et.HasError() && et.Error == prev.Error {
+		et.ErrorTime = prev.ErrorTime
+	}
+}
+
+// validateDatastoreType checks that the type is one which the downloader
+// supports
+func validateDatastoreType(dsType zconfig.DsType) error {
+	switch dsType {
+	case zconfig.DsType_DsHttp, zconfig.DsType_DsHttps, zconfig.DsType_DsS3,
+		zconfig.DsType_DsSFTP, zconfig.DsType_DsContainerRegistry,
+		zconfig.DsType_DsAzureBlob, zconfig.DsType_DsContainerRegistryCache:
+		return nil
+	case zconfig.DsType_DsUnknown:
+		return fmt.Errorf("datastore type not specified")
+	default:
+		return fmt.Errorf("unsupported datastore type %d", dsType)
+	}
+}
+
+// validateRegistryCache checks that both the cache and the upstream
+// registry of a DsContainerRegistryCache are OCI registry URLs
+func validateRegistryCache(ds *zconfig.DatastoreConfig) error {
+	if err := validateRegistryURL(ds.GetFqdn()); err != nil {
+		return fmt.Errorf("cache %s", err)
+	}
+	if err := validateRegistryURL(ds.GetUpstreamFqdn()); err != nil {
+		return fmt.Errorf("upstream %s", err)
+	}
+	if ds.GetFqdn() == ds.GetUpstreamFqdn() {
+		return fmt.Errorf("cache and upstream are both %s", ds.GetFqdn())
+	}
+	return nil
+}
+
+// validateRegistryURL checks that fqdn is a URL such as docker://docker.io
+// which the downloader can split into a registry and a repository
+func validateRegistryURL(fqdn string) error {
+	if fqdn == "" {
+		return fmt.Errorf("registry not specified")
+	}
+	u, err := url.Parse(fqdn)
+	if err != nil {
+		return fmt.Errorf("registry %s: %v", fqdn, err)
+	}
+	if u.Scheme != "docker" && u.Scheme != "oci" {
+		return fmt.Errorf("registry %s: unknown OCI registry scheme %q",
+			fqdn, u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("registry %s without a host", fqdn)
+	}
+	return nil
+}
+
+// driveAttrRule says whether a drive attribute must, must not, or may be set
+type driveAttrRule uint8
+
+const (
+	driveAttrAny driveAttrRule = iota
+	driveAttrRequired
+	driveAttrForbidden
+)
+
+// driveRule is what is allowed for a given Target and DriveType pair
+type driveRule struct {
+	readonly     driveAttrRule
+	maxsizebytes driveAttrRule
+}
+
+type driveTargetType struct {
+	target  zconfig.Target
+	drvtype zconfig.DriveType
+}
+
+// driveRules is the matrix of the allowed Target and DriveType combinations.
+// Any pair which is not listed is rejected. Add an entry here when a new
+// target or drive type is introduced.
+var driveRules = map[driveTargetType]driveRule{
+	{zconfig.Target_TgtUnknown, zconfig.DriveType_Unclassified}: {maxsizebytes: driveAttrForbidden},
+	{zconfig.Target_TgtUnknown, zconfig.DriveType_HDD}:          {},
+	{zconfig.Target_TgtUnknown, zconfig.DriveType_HDD_EMPTY}:    {readonly: driveAttrForbidden, maxsizebytes: driveAttrRequired},
+	{zconfig.Target_TgtUnknown, zconfig.DriveType_CDROM}:        {readonly: driveAttrRequired, maxsizebytes: driveAttrForbidden},
+	{zconfig.Target_TgtUnknown, zconfig.DriveType_NET}:          {maxsizebytes: driveAttrForbidden},
+	{zconfig.Target_Disk, zconfig.DriveType_Unclassified}:       {maxsizebytes: driveAttrForbidden},
+	{zconfig.Target_Disk, zconfig.DriveType_HDD}:                {},
+	{zconfig.Target_Disk, zconfig.DriveType_HDD_EMPTY}:          {readonly: driveAttrForbidden, maxsizebytes: driveAttrRequired},
+	{zconfig.Target_Disk, zconfig.DriveType_CDROM}:              {readonly: driveAttrRequired, maxsizebytes: driveAttrForbidden},
+	{zconfig.Target_Disk, zconfig.DriveType_NET}:                {maxsizebytes: driveAttrForbidden},
+	{zconfig.Target_Kernel, zconfig.DriveType_Unclassified}:     {maxsizebytes: driveAttrForbidden},
+	{zconfig.Target_Initrd, zconfig.DriveType_Unclassified}:     {maxsizebytes: driveAttrForbidden},
+	{zconfig.Target_RamDisk, zconfig.DriveType_Unclassified}:    {maxsizebytes: driveAttrForbidden},
+}
+
+func checkDriveAttr(name string, rule driveAttrRule, set bool) error {
+	switch {
+	case rule == driveAttrRequired && !set:
+		return fmt.Errorf("%s must be set", name)
+	case rule == driveAttrForbidden && set:
+		return fmt.Errorf("%s must not be set", name)
+	}
+	return nil
+}
+
+// validateDrive checks the Target, DriveType, readonly and maxsizebytes
+// combination against driveRules
+func validateDrive(drive *zconfig.Drive) error {
+	rule, ok := driveRules[driveTargetType{drive.Target, drive.Drvtype}]
+	if !ok {
+		return fmt.Errorf("drive type %s not supported for target %s",
+			drive.Drvtype, drive.Target)
+	}
+	if err := checkDriveAttr("readonly", rule.readonly,
+		drive.Readonly); err != nil {
+		return fmt.Errorf("drive type %s target %s: %v",
+			drive.Drvtype, drive.Target, err)
+	}
+	if err := checkDriveAttr("maxsizebytes", rule.maxsizebytes,
+		drive.Maxsizebytes != 0); err != nil {
+		return fmt.Errorf("drive type %s target %s: %v",
+			drive.Drvtype, drive.Target, err)
+	}
+	if drive.Maxsizebytes < 0 {
+		return fmt.Errorf("drive type %s target %s: negative maxsizebytes %d",
+			drive.Drvtype, drive.Target, drive.Maxsizebytes)
+	}
+	return nil
+}
+
 func parseContentTreeConfigList(contentTreeList []types.ContentTreeConfig, drives []*zconfig.Drive) {
 
 	var idx int = 0
@@ -1055,6 +2910,9 @@ func parseContentTreeConfigList(contentTreeList []types.ContentTreeConfig, drive
 			contentTree.ContentSha256 = strings.ToLower(drive.Image.Sha256)
 			contentTree.MaxDownloadSize = uint64(drive.Image.SizeBytes)
 			contentTree.DisplayName = drive.Image.Name
+			if drive.Image.GetSiginfo().GetIntercertsurl() != "" {
+				deprecatedIntercertsurl.hit(contentTree.Key())
+			}
 		}
 		contentTreeList[idx] = *contentTree
 		idx++
@@ -1120,12 +2978,37 @@ func publishNetworkXObjectConfig(ctx *getconfigContext,
 		ctx.pubNetworkXObjectConfig.Unpublish(k)
 	}
 
-	// XXX note that we currently get repeats in the same loop since
-	// the controller can send the same network multiple times.
-	// Should we track them and not rewrite them?
+	// The controller can send the same network multiple times.
+	// Identical repeats are ignored. If a repeat differs we use the first
+	// one and record an error on it.
+	first := make(map[string]*zconfig.NetworkConfig)
+	conflicts := make(map[string]int)
+	for _, netEnt := range cfgNetworks {
+		f, ok := first[netEnt.Id]
+		if !ok {
+			first[netEnt.Id] = netEnt
+		} else if !proto.Equal(f, netEnt) {
+			conflicts[netEnt.Id]++
+		}
+	}
 	for _, netEnt := range cfgNetworks {
+		if first[netEnt.Id] != netEnt {
+			log.Tracef("publishNetworkXObjectConfig: skipping repeat of %s",
+				netEnt.Id)
+			continue
+		}
 		config := parseOneNetworkXObjectConfig(ctx, netEnt)
 		if config != nil {
+			if count := conflicts[netEnt.Id]; count != 0 {
+				errStr := fmt.Sprintf("Network %s: %d conflicting duplicate entries in config; using the first",
+					netEnt.Id, count)
+				log.Error(errStr)
+				// Keep the error from the parse, if any
+				if config.HasError() {
+					errStr = config.Error + "; " + errStr
+				}
+				config.SetErrorNow(errStr)
+			}
 			ctx.pubNetworkXObjectConfig.Publish(config.Key(),
 				*config)
 		}
@@ -1158,13 +3041,46 @@ func parseOneNetworkXObjectConfig(ctx *getconfigContext, netEnt *zconfig.Network
 		log.Functionf("parseOneNetworkXObjectConfig: Proxy configuration present in %s",
 			netEnt.Id)
 
+		proxyPolicy, err := parseProxyPolicy(netProxyConfig.Policy)
+		if err != nil {
+			errStr := fmt.Sprintf("parseOneNetworkXObjectConfig: bad proxy policy in %s: %v",
+				config.Key(), err)
+			log.Error(errStr)
+			config.SetErrorNow(errStr)
+			return config
+		}
 		proxyConfig := types.ProxyConfig{
+			Policy:             proxyPolicy,
 			NetworkProxyEnable: netProxyConfig.NetworkProxyEnable,
 			NetworkProxyURL:    netProxyConfig.NetworkProxyURL,
 			Pacfile:            netProxyConfig.Pacfile,
 			ProxyCertPEM:       netProxyConfig.ProxyCertPEM,
 		}
+		if err := validatePacfile(netProxyConfig.Pacfile,
+			getPacfileMaxBytes(ctx)); err != nil {
+			errStr := fmt.Sprintf("parseOneNetworkXObjectConfig: bad PAC file in %s: %v",
+				config.Key(), err)
+			log.Error(errStr)
+			config.SetErrorNow(errStr)
+			return config
+		}
+		if proxyPolicy == types.ProxyPolicyDefault &&
+			netProxyConfig.Pacfile != "" && len(netProxyConfig.Proxies) != 0 &&
+			!netProxyConfig.NetworkProxyEnable {
+			errStr := fmt.Sprintf("parseOneNetworkXObjectConfig: %s has both a PAC file and explicit proxies",
+				config.Key())
+			log.Error(errStr)
+			config.SetErrorNow(errStr)
+			return config
+		}
 		proxyConfig.Exceptions = netProxyConfig.Exceptions
+		exceptionList, err := parseProxyExceptions(netProxyConfig.Exceptions)
+		if err != nil {
+			// The other exceptions are still used
+			log.Warnf("parseOneNetworkXObjectConfig: bad proxy exceptions ignored in %s: %v",
+				config.Key(), err)
+		}
+		proxyConfig.ExceptionList = exceptionList
 
 		// parse the static proxy entries
 		for _, proxy := range netProxyConfig.Proxies {
@@ -1183,16 +3099,46 @@ func parseOneNetworkXObjectConfig(ctx *getconfigContext, netEnt *zconfig.Network
 				proxyEntry.Type = types.NPT_FTP
 			default:
 			}
+			if proxy.Username != "" {
+				proxyEntry.Username = proxy.Username
+				key := fmt.Sprintf("%s-proxy-%s:%d", config.Key(),
+					proxy.Server, proxy.Port)
+				proxyEntry.CipherBlockStatus = parseCipherBlock(ctx, key,
+					proxy.GetCipherData())
+				if !proxyEntry.CipherBlockStatus.IsCipher {
+					errStr := fmt.Sprintf("parseOneNetworkXObjectConfig: missing password for proxy %s:%d username %s in %s",
+						proxy.Server, proxy.Port, proxy.Username,
+						config.Key())
+					log.Error(errStr)
+					config.SetErrorNow(errStr)
+					return config
+				}
+			}
 			proxyConfig.Proxies = append(proxyConfig.Proxies, proxyEntry)
 			log.Tracef("parseOneNetworkXObjectConfig: Adding proxy entry %s:%d in %s",
 				proxyEntry.Server, proxyEntry.Port, netEnt.Id)
 		}
+		// WPAD is only used when there are no explicit proxies
+		if proxyPolicy == types.ProxyPolicyDefault &&
+			proxyConfig.NetworkProxyEnable && len(proxyConfig.Proxies) != 0 {
+			log.Noticef("parseOneNetworkXObjectConfig: %s has %d explicit proxies; ignoring WPAD",
+				config.Key(), len(proxyConfig.Proxies))
+			proxyConfig.NetworkProxyEnable = false
+			proxyConfig.NetworkProxyURL = ""
+		}
 
 		config.Proxy = &proxyConfig
 	}
 
 	// wireless property configuration
 	config.WirelessCfg = parseNetworkWirelessConfig(ctx, config.Key(), netEnt)
+	if config.WirelessCfg.HasError() {
+		errStr := fmt.Sprintf("parseOneNetworkXObjectConfig: wireless config for %s: %s",
+			config.Key(), config.WirelessCfg.Error)
+		log.Error(errStr)
+		config.SetErrorNow(errStr)
+		return config
+	}
 
 	ipspec := netEnt.GetIp()
 	switch config.Type {
@@ -1217,6 +3163,7 @@ func parseOneNetworkXObjectConfig(ctx *getconfigContext, netEnt *zconfig.Network
 		if ipspec != nil {
 			log.Warnf("XXX NT_NOOP for %s with ipspec %v",
 				config.Key(), ipspec)
+			deprecatedNetworkNoopIpspec.hit(config.Key())
 			err := parseIpspecNetworkXObject(ipspec, config)
 			if err != nil {
 				errStr := fmt.Sprintf("Network parameter parse for %s failed: %s",
@@ -1235,36 +3182,34 @@ func parseOneNetworkXObjectConfig(ctx *getconfigContext, netEnt *zconfig.Network
 		return config
 	}
 
-	// Parse and store DnsNameToIPList form Network configuration
-	dnsEntries := netEnt.GetDns()
-
-	// Parse and populate the DnsNameToIP list
-	// This is what we will publish to zedrouter
-	nameToIPs := []types.DnsNameToIP{}
-	for _, dnsEntry := range dnsEntries {
-		hostName := dnsEntry.HostName
-
-		ips := []net.IP{}
-		for _, strAddr := range dnsEntry.Address {
-			ip := net.ParseIP(strAddr)
-			if ip != nil {
-				ips = append(ips, ip)
-			} else {
-				errStr := fmt.Sprintf("parseOneNetworkXObjectConfig: bad dnsEntry %s for %s",
-					strAddr, config.Key())
-				log.Error(errStr)
-				config.SetErrorNow(errStr)
-				return config
-			}
+	// The DNS domain of the network is needed to check WPAD
+	if config.Proxy != nil {
+		if err := applyProxyPolicy(config); err != nil {
+			errStr := fmt.Sprintf("parseOneNetworkXObjectConfig: proxy policy of %s: %v",
+				config.Key(), err)
+			log.Error(errStr)
+			config.SetErrorNow(errStr)
+			config.Proxy = nil
+			return config
 		}
+	}
 
-		nameToIP := types.DnsNameToIP{
-			HostName: hostName,
-			IPs:      ips,
-		}
-		nameToIPs = append(nameToIPs, nameToIP)
+	// Parse and store DnsNameToIPList form Network configuration
+	// This is what we will publish to zedrouter
+	nameToIPs, badAddrs, warnings := parseStaticDNSEntries(netEnt.GetDns())
+	if len(badAddrs) != 0 {
+		errStr := fmt.Sprintf("parseOneNetworkXObjectConfig: bad dnsEntry addresses for %s: %s",
+			config.Key(), formatBadDNSAddrs(badAddrs))
+		log.Error(errStr)
+		config.SetErrorNow(errStr)
+		return config
+	}
+	for _, warning := range warnings {
+		log.Warnf("parseOneNetworkXObjectConfig: %s: %s", config.Key(),
+			warning)
 	}
 	config.DnsNameToIPList = nameToIPs
+	config.DnsNameToIPWarnings = warnings
 	return config
 }
 
@@ -1283,24 +3228,43 @@ func parseNetworkWirelessConfig(ctx *getconfigContext, key string, netEnt *zconf
 		//
 		wconfig.WType = types.WirelessTypeCellular
 		cellulars := netWireless.GetCellularCfg()
+		var errStrs []string
 		for _, cellular := range cellulars {
-			var wcell types.CellConfig
-			wcell.APN = cellular.GetAPN()
+			wcell, errStr := parseCellularConfig(ctx, key, netEnt.Id, cellular)
+			if errStr != "" {
+				log.Error(errStr)
+				errStrs = append(errStrs, errStr)
+			}
 			wconfig.Cellular = append(wconfig.Cellular, wcell)
 		}
+		if len(errStrs) != 0 {
+			wconfig.SetErrorNow(strings.Join(errStrs, "; "))
+		}
 		log.Functionf("parseNetworkWirelessConfig: Wireless of network Cellular, %v", wconfig.Cellular)
 	case zconfig.WirelessType_WiFi:
 		//
 		wconfig.WType = types.WirelessTypeWifi
 		wificfgs := netWireless.GetWifiCfg()
 
+		var errStrs []string
 		for _, wificfg := range wificfgs {
 			var wifi types.WifiConfig
 			wifi.SSID = wificfg.GetWifiSSID()
-			if wificfg.GetKeyScheme() == zconfig.WiFiKeyScheme_WPAPSK {
+			switch wificfg.GetKeyScheme() {
+			case zconfig.WiFiKeyScheme_SchemeNOOP:
+				wifi.KeyScheme = types.KeySchemeNone
+			case zconfig.WiFiKeyScheme_WPAPSK:
 				wifi.KeyScheme = types.KeySchemeWpaPsk
-			} else if wificfg.GetKeyScheme() == zconfig.WiFiKeyScheme_WPAEAP {
+			case zconfig.WiFiKeyScheme_WPAEAP:
 				wifi.KeyScheme = types.KeySchemeWpaEap
+			case zconfig.WiFiKeyScheme_WPA3SAE:
+				wifi.KeyScheme = types.KeySchemeWpa3Sae
+			default:
+				wifi.KeyScheme = types.KeySchemeOther
+				errStr := fmt.Sprintf("parseNetworkWirelessConfig: unsupported key scheme %d for SSID %s in %s",
+					wificfg.GetKeyScheme(), wifi.SSID, netEnt.Id)
+				log.Error(errStr)
+				errStrs = append(errStrs, errStr)
 			}
 			wifi.Identity = wificfg.GetIdentity()
 			wifi.Password = wificfg.GetPassword()
@@ -1311,6 +3275,21 @@ func parseNetworkWirelessConfig(ctx *getconfigContext, key string, netEnt *zconf
 
 			wconfig.Wifi = append(wconfig.Wifi, wifi)
 		}
+		dupErrStrs, warnings := checkWifiDuplicates(netEnt.Id, wconfig.Wifi)
+		for _, errStr := range dupErrStrs {
+			log.Error(errStr)
+			errStrs = append(errStrs, errStr)
+		}
+		for _, warning := range warnings {
+			log.Warn(warning)
+		}
+		if len(errStrs) != 0 {
+			wconfig.SetErrorNow(strings.Join(errStrs, "; "))
+		}
+		// Highest priority first for a stable order
+		sort.SliceStable(wconfig.Wifi, func(i, j int) bool {
+			return wconfig.Wifi[i].Priority > wconfig.Wifi[j].Priority
+		})
 		log.Functionf("parseNetworkWirelessConfig: Wireless of network Wifi, %v", wconfig.Wifi)
 	default:
 		log.Errorf("parseNetworkWirelessConfig: unsupported wireless configure type %d", wType)
@@ -1318,9 +3297,286 @@ func parseNetworkWirelessConfig(ctx *getconfigContext, key string, netEnt *zconf
 	return wconfig
 }
 
+// parseCellularConfig parses the APN and its optional authentication.
+// An APN without a username has no authentication, as before. Returns an
+// error string if the authentication is incomplete or not supported.
+func parseCellularConfig(ctx *getconfigContext, key string, netID string,
+	cellular *zconfig.CellularConfig) (types.CellConfig, string) {
+
+	wcell := types.CellConfig{
+		APN:      cellular.GetAPN(),
+		Username: cellular.GetUsername(),
+	}
+	switch cellular.GetAuthProtocol() {
+	case zconfig.CellularAuthProtocol_CELLULAR_AUTH_PROTOCOL_NONE:
+		wcell.AuthProtocol = types.CellularAuthNone
+	case zconfig.CellularAuthProtocol_CELLULAR_AUTH_PROTOCOL_PAP:
+		wcell.AuthProtocol = types.CellularAuthPAP
+	case zconfig.CellularAuthProtocol_CELLULAR_AUTH_PROTOCOL_CHAP:
+		wcell.AuthProtocol = types.CellularAuthCHAP
+	case zconfig.CellularAuthProtocol_CELLULAR_AUTH_PROTOCOL_PAP_AND_CHAP:
+		wcell.AuthProtocol = types.CellularAuthPAPAndCHAP
+	default:
+		return wcell, fmt.Sprintf("parseCellularConfig: unsupported authentication protocol %d for APN %s in %s",
+			cellular.GetAuthProtocol(), wcell.APN, netID)
+	}
+	if wcell.AuthProtocol == types.CellularAuthNone {
+		if wcell.Username != "" {
+			return wcell, fmt.Sprintf("parseCellularConfig: username %s without authentication protocol for APN %s in %s",
+				wcell.Username, wcell.APN, netID)
+		}
+		return wcell, ""
+	}
+	if wcell.Username == "" {
+		return wcell, fmt.Sprintf("parseCellularConfig: missing username for authentication with APN %s in %s",
+			wcell.APN, netID)
+	}
+	key = fmt.Sprintf("%s-apn-%s", key, wcell.APN)
+	wcell.CipherBlockStatus = parseCipherBlock(ctx, key,
+		cellular.GetCipherData())
+	if !wcell.CipherBlockStatus.IsCipher {
+		return wcell, fmt.Sprintf("parseCellularConfig: missing password for APN %s username %s in %s",
+			wcell.APN, wcell.Username, netID)
+	}
+	return wcell, ""
+}
+
+// getPacfileMaxBytes returns the largest PAC file accepted in the proxy
+// configuration
+func getPacfileMaxBytes(getconfigCtx *getconfigContext) uint32 {
+	return getconfigCtx.zedagentCtx.globalConfig.GlobalValueInt(
+		types.NetworkProxyPacfileMaxBytes)
+}
+
+// validatePacfile checks that the base64 encoded PAC file decodes and is
+// no larger than maxBytes. The size is checked before decoding.
+func validatePacfile(pacfile string, maxBytes uint32) error {
+	if pacfile == "" {
+		return nil
+	}
+	if len(pacfile) > base64.StdEncoding.EncodedLen(int(maxBytes)) {
+		return fmt.Errorf("%d bytes encoded exceeds the limit of %d bytes",
+			len(pacfile), maxBytes)
+	}
+	pac, err := base64.StdEncoding.DecodeString(pacfile)
+	if err != nil {
+		return fmt.Errorf("not base64 encoded: %v", err)
+	}
+	if len(pac) > int(maxBytes) {
+		return fmt.Errorf("%d bytes exceeds the limit of %d bytes",
+			len(pac), maxBytes)
+	}
+	return nil
+}
+
+// parseProxyPolicy converts the proxy policy from the controller
+func parseProxyPolicy(policy zconfig.ProxyPolicy) (types.ProxyPolicy, error) {
+	switch policy {
+	case zconfig.ProxyPolicy_PROXY_POLICY_UNSPECIFIED:
+		return types.ProxyPolicyDefault, nil
+	case zconfig.ProxyPolicy_PROXY_POLICY_STATIC_ONLY:
+		return types.ProxyPolicyStaticOnly, nil
+	case zconfig.ProxyPolicy_PROXY_POLICY_PAC_ONLY:
+		return types.ProxyPolicyPacOnly, nil
+	case zconfig.ProxyPolicy_PROXY_POLICY_PAC_THEN_STATIC:
+		return types.ProxyPolicyPacThenStatic, nil
+	case zconfig.ProxyPolicy_PROXY_POLICY_WPAD:
+		return types.ProxyPolicyWpad, nil
+	default:
+		return types.ProxyPolicyDefault,
+			fmt.Errorf("unknown proxy policy %d", policy)
+	}
+}
+
+// applyProxyPolicy checks that the proxy configuration of the network has
+// what its policy uses, and drops what the policy does not use so that
+// NIM and zedcloud need not guess. A PAC file is either configured or
+// downloaded from NetworkProxyURL, which NIM does when NetworkProxyEnable
+// is set.
+func applyProxyPolicy(config *types.NetworkXObjectConfig) error {
+	proxyConfig := config.Proxy
+	policy := proxyConfig.Policy
+	hasProxies := len(proxyConfig.Proxies) != 0
+	hasPac := proxyConfig.Pacfile != "" || proxyConfig.NetworkProxyURL != ""
+	switch policy {
+	case types.ProxyPolicyDefault:
+		return nil
+	case types.ProxyPolicyStaticOnly:
+		if !hasProxies {
+			return fmt.Errorf("%s without explicit proxies", policy)
+		}
+		proxyConfig.Pacfile = ""
+		proxyConfig.NetworkProxyEnable = false
+		proxyConfig.NetworkProxyURL = ""
+	case types.ProxyPolicyPacOnly, types.ProxyPolicyPacThenStatic:
+		if !hasPac {
+			return fmt.Errorf("%s without a PAC file or a NetworkProxyURL",
+				policy)
+		}
+		if policy == types.ProxyPolicyPacThenStatic && !hasProxies {
+			return fmt.Errorf("%s without explicit proxies", policy)
+		}
+		if policy == types.ProxyPolicyPacOnly {
+			proxyConfig.Proxies = nil
+		}
+		if proxyConfig.Pacfile != "" {
+			proxyConfig.NetworkProxyEnable = false
+			proxyConfig.NetworkProxyURL = ""
+		} else {
+			proxyConfig.NetworkProxyEnable = true
+		}
+	case types.ProxyPolicyWpad:
+		// With DHCP the DNS domain can come from the DHCP server
+		if proxyConfig.NetworkProxyURL == "" && config.DomainName == "" &&
+			config.Dhcp != types.DT_CLIENT {
+			return fmt.Errorf("%s without a NetworkProxyURL or a DNS domain",
+				policy)
+		}
+		proxyConfig.Proxies = nil
+		proxyConfig.Pacfile = ""
+		proxyConfig.NetworkProxyEnable = true
+	default:
+		return fmt.Errorf("unknown proxy policy %s", policy)
+	}
+	log.Functionf("applyProxyPolicy: %s uses %s", config.Key(), policy)
+	return nil
+}
+
+// parseProxyExceptions parses the comma or space separated proxy
+// exceptions. Each entry is "*", a host name, which also matches its
+// subdomains as in NO_PROXY, a domain with a leading "." or "*." for only
+// its subdomains, an IP address or a subnet in CIDR notation. Host names
+// and IP addresses can have a port, with the IPv6 address in brackets.
+// The entries which are none of these are left out and listed in the
+// returned error.
+func parseProxyExceptions(exceptions string) ([]types.ProxyException, error) {
+	var exceptionList []types.ProxyException
+	var badTokens []string
+	tokens := strings.FieldsFunc(exceptions, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	for _, token := range tokens {
+		exception, err := parseProxyException(token)
+		if err != nil {
+			badTokens = append(badTokens, fmt.Sprintf("%q: %v", token, err))
+			continue
+		}
+		exceptionList = append(exceptionList, exception)
+	}
+	if len(badTokens) != 0 {
+		return exceptionList, errors.New(strings.Join(badTokens, ", "))
+	}
+	return exceptionList, nil
+}
+
+func parseProxyException(token string) (types.ProxyException, error) {
+	var exception types.ProxyException
+	if token == "*" {
+		exception.Type = types.ProxyExceptionAll
+		return exception, nil
+	}
+	if strings.Contains(token, "/") {
+		_, subnet, err := net.ParseCIDR(token)
+		if err != nil {
+			return exception, errors.New("bad subnet")
+		}
+		exception.Type = types.ProxyExceptionCIDR
+		exception.Value = subnet.String()
+		return exception, nil
+	}
+	host := token
+	// A port follows a bracketed IPv6 address or a host with a single colon
+	if strings.HasPrefix(token, "[") || strings.Count(token, ":") == 1 {
+		h, portStr, err := net.SplitHostPort(token)
+		if err != nil {
+			return exception, err
+		}
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil || port == 0 {
+			return exception, fmt.Errorf("bad port %s", portStr)
+		}
+		host = h
+		exception.Port = uint16(port)
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		exception.Type = types.ProxyExceptionIP
+		exception.Value = ip.String()
+		return exception, nil
+	}
+	if strings.HasPrefix(token, "[") {
+		return exception, errors.New("bad IPv6 address")
+	}
+	exception.Type = types.ProxyExceptionHost
+	if strings.HasPrefix(host, "*.") {
+		exception.Type = types.ProxyExceptionHostSuffix
+		host = host[2:]
+	} else if strings.HasPrefix(host, ".") {
+		exception.Type = types.ProxyExceptionHostSuffix
+		host = host[1:]
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "" {
+		return exception, errors.New("empty host name")
+	}
+	if err := validateDomainName(host); err != nil {
+		return exception, err
+	}
+	if exception.Type == types.ProxyExceptionHostSuffix {
+		host = "." + host
+	}
+	exception.Value = host
+	return exception, nil
+}
+
+// checkWifiDuplicates returns the errors for SSIDs which are listed more
+// than once, and warnings for priorities which are shared by several SSIDs.
+// Those are tried in the order of the config, and since the priority
+// defaults to 0 this is common.
+func checkWifiDuplicates(netID string, wifis []types.WifiConfig) ([]string, []string) {
+	var errStrs, warnings []string
+	ssids := make(map[string]bool)
+	prioritySSIDs := make(map[int32][]string)
+	var priorities []int32
+	for _, wifi := range wifis {
+		if ssids[wifi.SSID] {
+			errStrs = append(errStrs, fmt.Sprintf(
+				"parseNetworkWirelessConfig: duplicate SSID %s in %s",
+				wifi.SSID, netID))
+			continue
+		}
+		ssids[wifi.SSID] = true
+		if _, ok := prioritySSIDs[wifi.Priority]; !ok {
+			priorities = append(priorities, wifi.Priority)
+		}
+		prioritySSIDs[wifi.Priority] = append(prioritySSIDs[wifi.Priority],
+			wifi.SSID)
+	}
+	for _, priority := range priorities {
+		if len(prioritySSIDs[priority]) < 2 {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"parseNetworkWirelessConfig: SSIDs %s share priority %d in %s; tried in that order",
+			strings.Join(prioritySSIDs[priority], ", "), priority, netID))
+	}
+	return errStrs, warnings
+}
+
 func parseIpspecNetworkXObject(ipspec *zconfig.Ipspec, config *types.NetworkXObjectConfig) error {
 	config.Dhcp = types.DhcpType(ipspec.Dhcp)
-	config.DomainName = ipspec.GetDomain()
+	addrMode, err := parseAddrMode(ipspec, config.Type)
+	if err != nil {
+		return err
+	}
+	config.AddrMode = addrMode
+	domainNames, err := parseDomainNames(ipspec)
+	if err != nil {
+		return err
+	}
+	config.DomainNames = domainNames
+	if len(domainNames) != 0 {
+		config.DomainName = domainNames[0]
+	}
 	if s := ipspec.GetSubnet(); s != "" {
 		_, subnet, err := net.ParseCIDR(s)
 		if err != nil {
@@ -1329,6 +3585,17 @@ func parseIpspecNetworkXObject(ipspec *zconfig.Ipspec, config *types.NetworkXObj
 		}
 		config.Subnet = *subnet
 	}
+	for _, s := range ipspec.GetExtraSubnets() {
+		_, subnet, err := net.ParseCIDR(s)
+		if err != nil {
+			return fmt.Errorf("bad extra subnet %s: %s", s, err)
+		}
+		if networkSubnetFor(config, subnet.IP).IP != nil {
+			return fmt.Errorf("extra subnet %s is of the IP version of another subnet",
+				s)
+		}
+		config.ExtraSubnets = append(config.ExtraSubnets, *subnet)
+	}
 	if g := ipspec.GetGateway(); g != "" {
 		config.Gateway = net.ParseIP(g)
 		if config.Gateway == nil {
@@ -1351,6 +3618,8 @@ func parseIpspecNetworkXObject(ipspec *zconfig.Ipspec, config *types.NetworkXObj
 		}
 		config.DnsServers = append(config.DnsServers, ds)
 	}
+	config.DnsServers, _ = uniqueDNSServers(config.DnsServers,
+		"parseIpspecNetworkXObject")
 	if dr := ipspec.GetDhcpRange(); dr != nil && dr.GetStart() != "" {
 		start := net.ParseIP(dr.GetStart())
 		if start == nil {
@@ -1362,8 +3631,23 @@ func parseIpspecNetworkXObject(ipspec *zconfig.Ipspec, config *types.NetworkXObj
 			return errors.New(fmt.Sprintf("bad end IP %s",
 				dr.GetEnd()))
 		}
+		if end != nil && bytes.Compare(start.To16(), end.To16()) > 0 {
+			return fmt.Errorf("bad DHCP range: start %s after end %s",
+				start.String(), end.String())
+		}
 		config.DhcpRange.Start = start
 		config.DhcpRange.End = end
+		err := validateDhcpRange(config.Subnet, config.DhcpRange)
+		if err != nil {
+			return err
+		}
+	}
+	if config.Gateway != nil {
+		err := validateGateway(config.Subnet, config.Gateway,
+			config.DhcpRange)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -1371,7 +3655,14 @@ func parseIpspecNetworkXObject(ipspec *zconfig.Ipspec, config *types.NetworkXObj
 func parseIpspec(ipspec *zconfig.Ipspec,
 	config *types.NetworkInstanceConfig) error {
 
-	config.DomainName = ipspec.GetDomain()
+	domainNames, err := parseDomainNames(ipspec)
+	if err != nil {
+		return err
+	}
+	config.DomainNames = domainNames
+	if len(domainNames) != 0 {
+		config.DomainName = domainNames[0]
+	}
 	// Parse Subnet
 	if s := ipspec.GetSubnet(); s != "" {
 		_, subnet, err := net.ParseCIDR(s)
@@ -1406,6 +3697,12 @@ func parseIpspec(ipspec *zconfig.Ipspec,
 		}
 		config.DnsServers = append(config.DnsServers, ds)
 	}
+	var warning string
+	config.DnsServers, warning = uniqueDNSServers(config.DnsServers,
+		"parseIpspec")
+	if warning != "" {
+		config.DnsServerWarnings = append(config.DnsServerWarnings, warning)
+	}
 	// Parse DhcpRange
 	if dr := ipspec.GetDhcpRange(); dr != nil && dr.GetStart() != "" {
 		start := net.ParseIP(dr.GetStart())
@@ -1418,8 +3715,349 @@ func parseIpspec(ipspec *zconfig.Ipspec,
 			return errors.New(fmt.Sprintf("bad end IP %s",
 				dr.GetEnd()))
 		}
+		if end != nil && bytes.Compare(start.To16(), end.To16()) > 0 {
+			return fmt.Errorf("bad DHCP range: start %s after end %s",
+				start.String(), end.String())
+		}
 		config.DhcpRange.Start = start
 		config.DhcpRange.End = end
+		err := validateDhcpRange(config.Subnet, config.DhcpRange)
+		if err != nil {
+			return err
+		}
+	} else if config.Type == types.NetworkInstanceTypeLocal &&
+		config.Subnet.IP.To4() != nil {
+		// Make the range which is used visible in the config
+		dhcpRange, err := defaultDhcpRange(config.Subnet, config.Gateway)
+		if err != nil {
+			return err
+		}
+		log.Noticef("parseIpspec: using default DHCP range %s-%s for subnet %s",
+			dhcpRange.Start.String(), dhcpRange.End.String(),
+			config.Subnet.String())
+		config.DhcpRange = dhcpRange
+	}
+	// Validate Gateway against Subnet and DhcpRange
+	if config.Gateway != nil {
+		err := validateGateway(config.Subnet, config.Gateway,
+			config.DhcpRange)
+		if err != nil {
+			return err
+		}
+	}
+	// Parse static routes. Invalid ones are skipped and reported
+	// without failing the network instance
+	config.StaticRoutes = nil
+	config.StaticRouteErrors = nil
+	for _, r := range ipspec.GetRoutes() {
+		route, err := parseIPRoute(r, config.Subnet)
+		if err != nil {
+			errStr := fmt.Sprintf("bad route %s via %s: %s",
+				r.GetDestination(), r.GetGateway(), err)
+			log.Errorf("parseIpspec: %s", errStr)
+			config.StaticRouteErrors = append(config.StaticRouteErrors,
+				errStr)
+			continue
+		}
+		config.StaticRoutes = append(config.StaticRoutes, *route)
+	}
+	// Parse DHCP reservations. Invalid ones are skipped and reported
+	// without failing the network instance
+	config.Reservations = nil
+	config.ReservationErrors = nil
+	usedMacs := make(map[string]bool)
+	usedIPs := make(map[string]bool)
+	for _, r := range ipspec.GetReservations() {
+		reservation, err := parseDhcpReservation(r, config)
+		if err == nil && usedMacs[reservation.Mac.String()] {
+			err = errors.New("MAC already reserved")
+		}
+		if err == nil && usedIPs[reservation.IP.String()] {
+			err = errors.New("IP already reserved")
+		}
+		if err != nil {
+			errStr := fmt.Sprintf("bad DHCP reservation of %s for %s: %s",
+				r.GetIp(), r.GetMac(), err)
+			log.Errorf("parseIpspec: %s", errStr)
+			config.ReservationErrors = append(config.ReservationErrors,
+				errStr)
+			continue
+		}
+		usedMacs[reservation.Mac.String()] = true
+		usedIPs[reservation.IP.String()] = true
+		config.Reservations = append(config.Reservations, *reservation)
+	}
+	return nil
+}
+
+// parseMacAddr parses an EUI-48 unicast MAC address in the colon, dash or
+// dot separated forms, or as 12 hex digits. Its String() is the canonical
+// lower case colon separated form.
+func parseMacAddr(macStr string) (net.HardwareAddr, error) {
+	if len(macStr) == 12 {
+		if _, err := hex.DecodeString(macStr); err == nil {
+			var parts []string
+			for i := 0; i < len(macStr); i += 2 {
+				parts = append(parts, macStr[i:i+2])
+			}
+			macStr = strings.Join(parts, ":")
+		}
+	}
+	mac, err := net.ParseMAC(macStr)
+	if err != nil {
+		return nil, err
+	}
+	if len(mac) != 6 {
+		return nil, fmt.Errorf("%s is not an EUI-48 MAC address", macStr)
+	}
+	// Includes the broadcast address
+	if mac[0]&0x01 != 0 {
+		return nil, fmt.Errorf("%s is a multicast MAC address", macStr)
+	}
+	return mac, nil
+}
+
+// parseDhcpReservation checks that the IP is in the subnet of the network
+// instance, but neither in its DHCP range nor its gateway
+func parseDhcpReservation(r *zconfig.DhcpReservation,
+	config *types.NetworkInstanceConfig) (*types.DhcpReservation, error) {
+
+	mac, err := parseMacAddr(r.GetMac())
+	if err != nil {
+		return nil, fmt.Errorf("bad MAC: %s", err)
+	}
+	ip := net.ParseIP(r.GetIp())
+	if ip == nil {
+		return nil, errors.New("bad IP")
+	}
+	if config.Subnet.IP == nil || !config.Subnet.Contains(ip) {
+		return nil, errors.New("IP outside of the subnet")
+	}
+	if config.DhcpRange.Contains(ip) {
+		return nil, fmt.Errorf("IP in the DHCP range %s-%s",
+			config.DhcpRange.Start, config.DhcpRange.End)
+	}
+	if ip.Equal(config.Gateway) {
+		return nil, errors.New("IP is the gateway")
+	}
+	hostname := r.GetHostname()
+	if hostname != "" {
+		if err := validateDomainName(hostname); err != nil {
+			return nil, err
+		}
+	}
+	return &types.DhcpReservation{Mac: mac, IP: ip, Hostname: hostname}, nil
+}
+
+// parseIPRoute checks that the gateway is in the subnet of the network
+// instance, and that the destination does not overlap with that subnet
+func parseIPRoute(r *zconfig.IPRoute, subnet net.IPNet) (*types.IPRoute, error) {
+	_, dst, err := net.ParseCIDR(r.GetDestination())
+	if err != nil {
+		return nil, fmt.Errorf("bad destination: %s", err)
+	}
+	gateway := net.ParseIP(r.GetGateway())
+	if gateway == nil {
+		return nil, fmt.Errorf("bad gateway IP %s", r.GetGateway())
+	}
+	if subnet.IP == nil {
+		return nil, errors.New("no subnet to reach the gateway")
+	}
+	if !subnet.Contains(gateway) {
+		return nil, fmt.Errorf("gateway not in subnet %s",
+			subnet.String())
+	}
+	if (dst.IP.To4() == nil) != (gateway.To4() == nil) {
+		return nil, errors.New("destination and gateway address family mismatch")
+	}
+	if dst.Contains(subnet.IP) || subnet.Contains(dst.IP) {
+		return nil, fmt.Errorf("destination overlaps subnet %s",
+			subnet.String())
+	}
+	return &types.IPRoute{DstNetwork: *dst, Gateway: gateway}, nil
+}
+
+// validateDhcpRange checks that the start and end (if any) of the DHCP range
+// are part of the subnet (if one is specified)
+func validateDhcpRange(subnet net.IPNet, dhcpRange types.IpRange) error {
+	if subnet.IP == nil {
+		return nil
+	}
+	if !subnet.Contains(dhcpRange.Start) {
+		return fmt.Errorf("DHCP range start %s not in subnet %s",
+			dhcpRange.Start.String(), subnet.String())
+	}
+	if dhcpRange.End != nil && !subnet.Contains(dhcpRange.End) {
+		return fmt.Errorf("DHCP range end %s not in subnet %s",
+			dhcpRange.End.String(), subnet.String())
+	}
+	return nil
+}
+
+// defaultDhcpRange returns the DHCP range for an IPv4 subnet without one.
+// It is the lower half of the subnet starting at the second host address,
+// since the first one is normally the gateway, or the upper half if the
+// gateway is in the lower half. The broadcast address is excluded.
+func defaultDhcpRange(subnet net.IPNet, gateway net.IP) (types.IpRange, error) {
+	ip4 := subnet.IP.To4()
+	ones, bits := subnet.Mask.Size()
+	if ip4 == nil || bits != 8*net.IPv4len {
+		return types.IpRange{}, fmt.Errorf("no default DHCP range for non-IPv4 subnet %s",
+			subnet.String())
+	}
+	size := uint32(1) << uint(bits-ones)
+	network := binary.BigEndian.Uint32(ip4)
+	// Need room for the network, gateway, broadcast and at least
+	// two addresses to hand out
+	if size < 8 {
+		return types.IpRange{}, fmt.Errorf("subnet %s too small for a default DHCP range",
+			subnet.String())
+	}
+	toIP := func(n uint32) net.IP {
+		ip := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(ip, n)
+		return ip
+	}
+	dhcpRange := types.IpRange{
+		Start: toIP(network + 2),
+		End:   toIP(network + size/2 - 1),
+	}
+	if dhcpRange.Contains(gateway) {
+		dhcpRange = types.IpRange{
+			Start: toIP(network + size/2),
+			End:   toIP(network + size - 2),
+		}
+	}
+	return dhcpRange, nil
+}
+
+// parseDomainNames returns the DNS search domains from the domain field,
+// which can be a comma or space separated list, followed by the domains
+// field. Duplicates are removed.
+func parseDomainNames(ipspec *zconfig.Ipspec) ([]string, error) {
+	names := strings.FieldsFunc(ipspec.GetDomain(), func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	names = append(names, ipspec.GetDomains()...)
+	var domainNames []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if err := validateDomainName(name); err != nil {
+			return nil, err
+		}
+		duplicate := false
+		for _, dn := range domainNames {
+			if strings.EqualFold(dn, name) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			domainNames = append(domainNames, name)
+		}
+	}
+	return domainNames, nil
+}
+
+// validateDomainName checks the length and the labels of a domain name
+// following RFC 1123
+func validateDomainName(name string) error {
+	if len(name) > 253 {
+		return fmt.Errorf("bad domain name %s: longer than 253 characters",
+			name)
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return fmt.Errorf("bad domain name %s: label length %d",
+				name, len(label))
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("bad domain name %s: label %s starts or ends with a hyphen",
+				name, label)
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') &&
+				!(c >= '0' && c <= '9') && c != '-' {
+				return fmt.Errorf("bad domain name %s: invalid character %q",
+					name, c)
+			}
+		}
+	}
+	return nil
+}
+
+// maxDNSServers is the maximum number of DNS servers passed on for a network
+const maxDNSServers = 8
+
+// uniqueDNSServers removes duplicates, keeping the order of first occurrence,
+// and truncates the list to maxDNSServers. The returned warning is set if
+// the list was truncated.
+func uniqueDNSServers(servers []net.IP, context string) ([]net.IP, string) {
+	var result []net.IP
+	for _, ds := range servers {
+		duplicate := false
+		for _, r := range result {
+			if r.Equal(ds) {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			log.Warnf("%s: ignoring duplicate DNS server %s",
+				context, ds.String())
+			continue
+		}
+		result = append(result, ds)
+	}
+	var warning string
+	if len(result) > maxDNSServers {
+		warning = fmt.Sprintf("%d DNS servers truncated to %d",
+			len(result), maxDNSServers)
+		log.Warnf("%s: %s", context, warning)
+		result = result[:maxDNSServers]
+	}
+	return result, warning
+}
+
+// validateGateway checks that the gateway is part of the subnet (if one
+// is specified) without being its network or broadcast address, and that
+// it can not be handed out from the DHCP range
+func validateGateway(subnet net.IPNet, gateway net.IP,
+	dhcpRange types.IpRange) error {
+
+	if subnet.IP != nil {
+		if !subnet.Contains(gateway) {
+			return fmt.Errorf("gateway %s not in subnet %s",
+				gateway.String(), subnet.String())
+		}
+		// /31 and /32 IPv4 subnets (and /127, /128 for IPv6)
+		// have no separate network and broadcast addresses
+		ones, bits := subnet.Mask.Size()
+		if bits-ones > 1 {
+			if gateway.Equal(subnet.IP) {
+				return fmt.Errorf("gateway %s is the network address of subnet %s",
+					gateway.String(), subnet.String())
+			}
+			if ip4 := subnet.IP.To4(); ip4 != nil {
+				mask := subnet.Mask[len(subnet.Mask)-net.IPv4len:]
+				broadcast := make(net.IP, net.IPv4len)
+				for i := range ip4 {
+					broadcast[i] = ip4[i] | ^mask[i]
+				}
+				if gateway.Equal(broadcast) {
+					return fmt.Errorf("gateway %s is the broadcast address of subnet %s",
+						gateway.String(), subnet.String())
+				}
+			}
+		}
+	}
+	if dhcpRange.Contains(gateway) {
+		return fmt.Errorf("gateway %s inside DHCP range %s-%s",
+			gateway.String(), dhcpRange.Start.String(),
+			dhcpRange.End.String())
 	}
 	return nil
 }
@@ -1427,18 +4065,37 @@ func parseIpspec(ipspec *zconfig.Ipspec,
 func parseAppNetworkConfig(appInstance *types.AppInstanceConfig,
 	cfgApp *zconfig.AppInstanceConfig,
 	cfgNetworks []*zconfig.NetworkConfig,
-	cfgNetworkInstances []*zconfig.NetworkInstanceConfig) {
+	cfgNetworkInstances []*zconfig.NetworkInstanceConfig,
+	overlayCompat bool) {
 
 	parseUnderlayNetworkConfig(appInstance, cfgApp, cfgNetworks,
-		cfgNetworkInstances)
+		cfgNetworkInstances, overlayCompat)
 }
 
 func parseUnderlayNetworkConfig(appInstance *types.AppInstanceConfig,
 	cfgApp *zconfig.AppInstanceConfig,
 	cfgNetworks []*zconfig.NetworkConfig,
-	cfgNetworkInstances []*zconfig.NetworkInstanceConfig) {
+	cfgNetworkInstances []*zconfig.NetworkInstanceConfig,
+	overlayCompat bool) {
 
+	appInstance.OverlayCompatWarnings = nil
+	appInstance.NoNetworking = len(cfgApp.Interfaces) == 0
+	if appInstance.NoNetworking {
+		log.Functionf("App %s-%s: no networking", cfgApp.Displayname,
+			cfgApp.Uuidandversion.Uuid)
+		return
+	}
 	for _, intfEnt := range cfgApp.Interfaces {
+		if overlayCompat {
+			var warnings []string
+			intfEnt, warnings = translateOverlayInterface(cfgApp,
+				cfgNetworkInstances, intfEnt)
+			for _, warning := range warnings {
+				log.Warn(warning)
+			}
+			appInstance.OverlayCompatWarnings = append(
+				appInstance.OverlayCompatWarnings, warnings...)
+		}
 		ulCfg := parseUnderlayNetworkConfigEntry(
 			cfgApp, cfgNetworks, cfgNetworkInstances, intfEnt)
 		if ulCfg == nil {
@@ -1448,11 +4105,18 @@ func parseUnderlayNetworkConfig(appInstance *types.AppInstanceConfig,
 		appInstance.UnderlayNetworkList = append(appInstance.UnderlayNetworkList,
 			*ulCfg)
 		if ulCfg.Error != "" {
-			appInstance.Errors = append(appInstance.Errors, ulCfg.Error)
+			appInstance.Errors = append(appInstance.Errors,
+				types.NewAppConfigError(intfEnt.Name, ulCfg.ErrorCategory,
+					ulCfg.Error))
 			log.Errorf("Error in Interface(%s) config. Error: %s",
 				intfEnt.Name, ulCfg.Error)
 		}
 	}
+	if len(appInstance.UnderlayNetworkList) == 0 {
+		log.Warnf("App %s-%s: none of its %d interfaces is in use",
+			cfgApp.Displayname, cfgApp.Uuidandversion.Uuid,
+			len(cfgApp.Interfaces))
+	}
 	// sort based on intfOrder
 	// XXX remove? Debug?
 	if len(appInstance.UnderlayNetworkList) > 1 {
@@ -1469,6 +4133,512 @@ func parseUnderlayNetworkConfig(appInstance *types.AppInstanceConfig,
 	}
 }
 
+// appHostnameOwner is an app interface with a hostname
+type appHostnameOwner struct {
+	appUUID     string
+	displayName string
+	intfName    string
+}
+
+// appHostnameConflict is the error for an app interface with a hostname
+// which is also used by others
+type appHostnameConflict struct {
+	IntfName string
+	ErrStr   string
+}
+
+// findAppHostnameConflicts returns the errors, per app UUID, for hostnames
+// which are used by more than one app interface on the same network
+// instance. Each error names the other users of the hostname.
+func findAppHostnameConflicts(apps []*zconfig.AppInstanceConfig) map[string][]appHostnameConflict {
+	owners := make(map[string][]appHostnameOwner)
+	var keys []string
+	for _, cfgApp := range apps {
+		for _, intfEnt := range cfgApp.Interfaces {
+			for _, hostname := range intfEnt.Hostnames {
+				hostname = strings.ToLower(strings.TrimSuffix(hostname, "."))
+				key := intfEnt.NetworkId + " " + hostname
+				owner := appHostnameOwner{
+					appUUID:     cfgApp.Uuidandversion.Uuid,
+					displayName: cfgApp.Displayname,
+					intfName:    intfEnt.Name,
+				}
+				found := false
+				for _, o := range owners[key] {
+					if o == owner {
+						found = true
+						break
+					}
+				}
+				if found {
+					continue
+				}
+				if _, ok := owners[key]; !ok {
+					keys = append(keys, key)
+				}
+				owners[key] = append(owners[key], owner)
+			}
+		}
+	}
+	conflicts := make(map[string][]appHostnameConflict)
+	for _, key := range keys {
+		if len(owners[key]) < 2 {
+			continue
+		}
+		fields := strings.SplitN(key, " ", 2)
+		for i, owner := range owners[key] {
+			var others []string
+			for j, other := range owners[key] {
+				if i != j {
+					others = append(others, fmt.Sprintf("app %s interface %s",
+						other.displayName, other.intfName))
+				}
+			}
+			errStr := fmt.Sprintf("App %s interface %s: hostname %s on network instance %s is also used by %s",
+				owner.displayName, owner.intfName, fields[1], fields[0],
+				strings.Join(others, ", "))
+			conflicts[owner.appUUID] = append(conflicts[owner.appUUID],
+				appHostnameConflict{IntfName: owner.intfName, ErrStr: errStr})
+		}
+	}
+	return conflicts
+}
+
+// appNetworkQuotaError is the error for an app interface beyond the
+// maximum number of app interfaces of its network instance
+type appNetworkQuotaError struct {
+	IntfName string
+	ErrStr   string
+}
+
+// networkInstanceMaxVifs returns the maximum number of app interfaces of the
+// network instances which have one, indexed by UUID string
+func networkInstanceMaxVifs(networkInstances []*zconfig.NetworkInstanceConfig) map[string]uint32 {
+	maxVifs := make(map[string]uint32)
+	for _, ni := range networkInstances {
+		if ni.GetMaxVifs() != 0 {
+			maxVifs[ni.GetUuidandversion().GetUuid()] = ni.GetMaxVifs()
+		}
+	}
+	return maxVifs
+}
+
+// findNetworkQuotaErrors returns the errors, per app UUID, for the app
+// interfaces beyond the maximum of their network instance. The interfaces
+// are counted in the order of the apps in the config.
+func findNetworkQuotaErrors(apps []*zconfig.AppInstanceConfig,
+	maxVifs map[string]uint32) map[string][]appNetworkQuotaError {
+
+	counts := make(map[string]uint32)
+	for _, cfgApp := range apps {
+		for _, intfEnt := range cfgApp.Interfaces {
+			counts[intfEnt.NetworkId]++
+		}
+	}
+	quotaErrors := make(map[string][]appNetworkQuotaError)
+	used := make(map[string]uint32)
+	for _, cfgApp := range apps {
+		for _, intfEnt := range cfgApp.Interfaces {
+			niUUID := intfEnt.NetworkId
+			limit, ok := maxVifs[niUUID]
+			if !ok {
+				continue
+			}
+			used[niUUID]++
+			if used[niUUID] <= limit {
+				continue
+			}
+			errStr := fmt.Sprintf("App %s interface %s: network instance %s has %d app interfaces, exceeding its maximum of %d",
+				cfgApp.Displayname, intfEnt.Name, niUUID,
+				counts[niUUID], limit)
+			appUUID := cfgApp.Uuidandversion.Uuid
+			quotaErrors[appUUID] = append(quotaErrors[appUUID],
+				appNetworkQuotaError{IntfName: intfEnt.Name, ErrStr: errStr})
+		}
+	}
+	return quotaErrors
+}
+
+// findAppsBeyondMax returns the error, per app UUID, for the apps beyond the
+// maximum number of app instances in the order of the config. Zero means
+// no limit.
+func findAppsBeyondMax(apps []*zconfig.AppInstanceConfig,
+	maxApps uint32) map[string]string {
+
+	errs := make(map[string]string)
+	if maxApps == 0 || len(apps) <= int(maxApps) {
+		return errs
+	}
+	for _, cfgApp := range apps[maxApps:] {
+		errs[cfgApp.Uuidandversion.Uuid] = fmt.Sprintf(
+			"App %s: beyond the maximum of %d app instances; %d configured",
+			cfgApp.Displayname, maxApps, len(apps))
+	}
+	return errs
+}
+
+// acceleratorDevice is the device behind an adapter name which app
+// instances use to assign it
+type acceleratorDevice struct {
+	// Key identifies the device whichever name is used; the sorted
+	// Phylabels of its members
+	Key string
+	// MemoryMB is the accelerator memory of the members
+	MemoryMB uint32
+}
+
+// adapterMembers returns the adapters which are assigned together indexed
+// by the names which app instances can use to assign them: an assignment
+// group, with all of its members, or the Phylabel or Logicallabel of an
+// adapter, with the members of its assignment group if any. An assignment
+// group takes precedence over a label with the same name.
+func adapterMembers(deviceIoList []*zconfig.PhysicalIO) map[string][]*zconfig.PhysicalIO {
+	groups := make(map[string][]*zconfig.PhysicalIO)
+	for _, phyIO := range deviceIoList {
+		if phyIO.GetAssigngrp() != "" {
+			groups[phyIO.GetAssigngrp()] = append(
+				groups[phyIO.GetAssigngrp()], phyIO)
+		}
+	}
+	members := make(map[string][]*zconfig.PhysicalIO)
+	for name, phyIOs := range groups {
+		members[name] = phyIOs
+	}
+	for _, phyIO := range deviceIoList {
+		phyIOs, ok := groups[phyIO.GetAssigngrp()]
+		if !ok {
+			phyIOs = []*zconfig.PhysicalIO{phyIO}
+		}
+		for _, name := range []string{phyIO.GetPhylabel(), phyIO.GetLogicallabel()} {
+			if _, ok := members[name]; name != "" && !ok {
+				members[name] = phyIOs
+			}
+		}
+	}
+	return members
+}
+
+// acceleratorDevices returns the accelerator devices indexed by the names
+// which app instances can use to assign them, as adapterMembers does. An
+// assignment group has the accelerator memory of all of its members.
+func acceleratorDevices(deviceIoList []*zconfig.PhysicalIO) map[string]acceleratorDevice {
+	devices := make(map[string]acceleratorDevice)
+	for name, phyIOs := range adapterMembers(deviceIoList) {
+		var labels []string
+		var memoryMB uint32
+		for _, phyIO := range phyIOs {
+			labels = append(labels, phyIO.GetPhylabel())
+			memoryMB += phyIO.GetAcceleratorMemoryMb()
+		}
+		sort.Strings(labels)
+		devices[name] = acceleratorDevice{
+			Key:      strings.Join(labels, ","),
+			MemoryMB: memoryMB,
+		}
+	}
+	return devices
+}
+
+// appAcceleratorMemoryError is the error, or the warning, for the
+// accelerator memory an app instance reserves on an adapter
+type appAcceleratorMemoryError struct {
+	ErrStr  string
+	Warning bool
+}
+
+// findAcceleratorMemoryErrors returns the errors, per app UUID, for the
+// accelerator memory reservations beyond the memory of the device. The
+// reservations of the app instances sharing a device add up in the order
+// of the config, hence the later ones are in error. A reservation on an
+// adapter which does not advertise its accelerator memory is a warning.
+func findAcceleratorMemoryErrors(apps []*zconfig.AppInstanceConfig,
+	devices map[string]acceleratorDevice) map[string][]appAcceleratorMemoryError {
+
+	accelErrors := make(map[string][]appAcceleratorMemoryError)
+	reserved := make(map[string]uint32)
+	reservedBy := make(map[string][]string)
+	for _, cfgApp := range apps {
+		appUUID := cfgApp.Uuidandversion.Uuid
+		for _, adapter := range cfgApp.Adapters {
+			memoryMB := adapter.GetAcceleratorMemoryMb()
+			if memoryMB == 0 {
+				continue
+			}
+			device, ok := devices[adapter.GetName()]
+			if !ok || device.MemoryMB == 0 {
+				errStr := fmt.Sprintf("App %s adapter %s: reserving %d MB of accelerator memory on an adapter which does not advertise any",
+					cfgApp.Displayname, adapter.GetName(), memoryMB)
+				accelErrors[appUUID] = append(accelErrors[appUUID],
+					appAcceleratorMemoryError{ErrStr: errStr, Warning: true})
+				continue
+			}
+			total := reserved[device.Key] + memoryMB
+			if total <= device.MemoryMB {
+				reserved[device.Key] = total
+				reservedBy[device.Key] = append(reservedBy[device.Key],
+					cfgApp.Displayname)
+				continue
+			}
+			var errStr string
+			if reserved[device.Key] == 0 {
+				errStr = fmt.Sprintf("App %s adapter %s: accelerator memory of %d MB exceeds the %d MB of the adapter",
+					cfgApp.Displayname, adapter.GetName(), memoryMB,
+					device.MemoryMB)
+			} else {
+				errStr = fmt.Sprintf("App %s adapter %s: accelerator memory of %d MB over-commits the %d MB of the adapter; %d MB reserved by %s",
+					cfgApp.Displayname, adapter.GetName(), memoryMB,
+					device.MemoryMB, reserved[device.Key],
+					strings.Join(reservedBy[device.Key], ", "))
+			}
+			accelErrors[appUUID] = append(accelErrors[appUUID],
+				appAcceleratorMemoryError{ErrStr: errStr})
+		}
+	}
+	return accelErrors
+}
+
+// adapterNuma is the NUMA topology of an adapter, or of the members of an
+// assignment group
+type adapterNuma struct {
+	// Nodes are the known NUMA nodes, sorted
+	Nodes []uint32
+	// Unknown is set if the node of an adapter is not known
+	Unknown bool
+}
+
+// adapterNumaNodes returns the NUMA topology of the adapters indexed by the
+// names which app instances can use to assign them, as adapterMembers does.
+// Serial ports and USB devices without a NUMA node are not tied to one,
+// hence they are left out rather than unknown.
+func adapterNumaNodes(deviceIoList []*zconfig.PhysicalIO) map[string]adapterNuma {
+	devices := make(map[string]adapterNuma)
+	for name, phyIOs := range adapterMembers(deviceIoList) {
+		var numa adapterNuma
+		nodes := make(map[uint32]bool)
+		for _, phyIO := range phyIOs {
+			if phyIO.GetNuma() != nil {
+				nodes[phyIO.GetNuma().GetNode()] = true
+				continue
+			}
+			switch types.IoType(phyIO.GetPtype()) {
+			case types.IoCom, types.IoUSB:
+			default:
+				numa.Unknown = true
+			}
+		}
+		for node := range nodes {
+			numa.Nodes = append(numa.Nodes, node)
+		}
+		sort.Slice(numa.Nodes, func(i, j int) bool {
+			return numa.Nodes[i] < numa.Nodes[j]
+		})
+		devices[name] = numa
+	}
+	return devices
+}
+
+// appNumaPlacement is the NUMA node resolved from the affinity policy of
+// an app instance, or the error, or the warning, if it cannot be
+type appNumaPlacement struct {
+	NodeSet bool
+	Node    uint32
+	ErrStr  string
+	Warning bool
+}
+
+// findAppNumaPlacements returns the NUMA placement, per app UUID, of the
+// app instances with an affinity policy. The adapters of the app instance
+// must all be on the same known NUMA node; if not the prefer policy falls
+// back to no placement with a warning, and the require policy is an error.
+func findAppNumaPlacements(apps []*zconfig.AppInstanceConfig,
+	devices map[string]adapterNuma) map[string]appNumaPlacement {
+
+	placements := make(map[string]appNumaPlacement)
+	for _, cfgApp := range apps {
+		policy := types.AffinityPolicy(cfgApp.GetAffinityPolicy())
+		switch policy {
+		case types.AffinityPolicyNone:
+			continue
+		case types.AffinityPolicyPreferAdapterNuma,
+			types.AffinityPolicyRequireAdapterNuma:
+		default:
+			placements[cfgApp.Uuidandversion.Uuid] = appNumaPlacement{
+				ErrStr: fmt.Sprintf("App %s: %s", cfgApp.Displayname,
+					policy),
+			}
+			continue
+		}
+		nodes := make(map[uint32]bool)
+		var unknown []string
+		for _, adapter := range cfgApp.Adapters {
+			numa, ok := devices[adapter.GetName()]
+			if !ok || numa.Unknown {
+				unknown = append(unknown, adapter.GetName())
+			}
+			for _, node := range numa.Nodes {
+				nodes[node] = true
+			}
+		}
+		var reason string
+		switch {
+		case len(cfgApp.Adapters) == 0:
+			reason = "no adapters are assigned"
+		case len(unknown) != 0:
+			reason = fmt.Sprintf("the NUMA node of adapter %s is not known",
+				strings.Join(unknown, ", "))
+		case len(nodes) == 0:
+			reason = "none of the adapters is on a NUMA node"
+		case len(nodes) > 1:
+			var nodeList []int
+			for node := range nodes {
+				nodeList = append(nodeList, int(node))
+			}
+			sort.Ints(nodeList)
+			reason = fmt.Sprintf("the adapters are on NUMA nodes %v",
+				nodeList)
+		default:
+			placement := appNumaPlacement{NodeSet: true}
+			for node := range nodes {
+				placement.Node = node
+			}
+			placements[cfgApp.Uuidandversion.Uuid] = placement
+			continue
+		}
+		placement := appNumaPlacement{
+			ErrStr: fmt.Sprintf("App %s: affinity policy %s: %s",
+				cfgApp.Displayname, policy, reason),
+		}
+		if policy == types.AffinityPolicyPreferAdapterNuma {
+			placement.ErrStr += "; placed on any NUMA node"
+			placement.Warning = true
+		}
+		placements[cfgApp.Uuidandversion.Uuid] = placement
+	}
+	return placements
+}
+
+// findAppVncConflicts returns the error, per app UUID, for the apps which
+// enable VNC on a display number which is also used by other apps
+func findAppVncConflicts(apps []*zconfig.AppInstanceConfig) map[string]string {
+	users := make(map[uint32][]*zconfig.AppInstanceConfig)
+	for _, cfgApp := range apps {
+		fixedResources := cfgApp.GetFixedresources()
+		if !fixedResources.GetEnableVnc() {
+			continue
+		}
+		display := fixedResources.GetVncDisplay()
+		users[display] = append(users[display], cfgApp)
+	}
+	conflicts := make(map[string]string)
+	for display, cfgApps := range users {
+		if len(cfgApps) < 2 {
+			continue
+		}
+		for i, cfgApp := range cfgApps {
+			var others []string
+			for j, other := range cfgApps {
+				if i != j {
+					others = append(others, other.Displayname)
+				}
+			}
+			conflicts[cfgApp.Uuidandversion.Uuid] = fmt.Sprintf(
+				"app %s: VNC display %d is also used by %s",
+				cfgApp.Displayname, display, strings.Join(others, ", "))
+		}
+	}
+	return conflicts
+}
+
+// appPortMapOwner is an ACL of an app interface with a port map action
+type appPortMapOwner struct {
+	appUUID     string
+	displayName string
+	intfName    string
+	aclID       int32
+}
+
+// appPortMapConflict is the error for a port map of an app interface which
+// maps an external port also mapped by another ACL
+type appPortMapConflict struct {
+	IntfName string
+	ErrStr   string
+}
+
+// portMapExternalPort returns the protocol and the external port which an
+// ACL with a port map action maps. ok is false for the ACLs without one.
+func portMapExternalPort(acl *zconfig.ACE) (protocol string, lport string, ok bool) {
+	portMap := false
+	for _, action := range acl.GetActions() {
+		if action.GetPortmap() {
+			portMap = true
+			break
+		}
+	}
+	if !portMap {
+		return "", "", false
+	}
+	for _, match := range acl.GetMatches() {
+		switch match.GetType() {
+		case "protocol":
+			protocol = strings.ToLower(match.GetValue())
+		case "lport":
+			lport = match.GetValue()
+		}
+	}
+	return protocol, lport, lport != ""
+}
+
+// findPortMapConflicts returns the errors, per app UUID, for the port maps
+// of an external port which is also mapped by other ACLs on the same network
+// instance. zedrouter would fail to set them up. Each error names the other
+// ACLs mapping the port.
+func findPortMapConflicts(apps []*zconfig.AppInstanceConfig) map[string][]appPortMapConflict {
+	owners := make(map[string][]appPortMapOwner)
+	var keys []string
+	for _, cfgApp := range apps {
+		for _, intfEnt := range cfgApp.Interfaces {
+			for _, acl := range intfEnt.Acls {
+				protocol, lport, ok := portMapExternalPort(acl)
+				if !ok {
+					continue
+				}
+				key := intfEnt.NetworkId + " " + protocol + " " + lport
+				if _, ok := owners[key]; !ok {
+					keys = append(keys, key)
+				}
+				owners[key] = append(owners[key], appPortMapOwner{
+					appUUID:     cfgApp.Uuidandversion.Uuid,
+					displayName: cfgApp.Displayname,
+					intfName:    intfEnt.Name,
+					aclID:       acl.Id,
+				})
+			}
+		}
+	}
+	conflicts := make(map[string][]appPortMapConflict)
+	for _, key := range keys {
+		if len(owners[key]) < 2 {
+			continue
+		}
+		fields := strings.SplitN(key, " ", 3)
+		for i, owner := range owners[key] {
+			var others []string
+			for j, other := range owners[key] {
+				if i != j {
+					others = append(others, fmt.Sprintf("app %s interface %s ACL %d",
+						other.displayName, other.intfName, other.aclID))
+				}
+			}
+			errStr := fmt.Sprintf("App %s interface %s ACL %d: port map of %s port %s on network instance %s conflicts with %s",
+				owner.displayName, owner.intfName, owner.aclID, fields[1],
+				fields[2], fields[0], strings.Join(others, ", "))
+			conflicts[owner.appUUID] = append(conflicts[owner.appUUID],
+				appPortMapConflict{IntfName: owner.intfName, ErrStr: errStr})
+		}
+	}
+	return conflicts
+}
+
 func isOverlayNetwork(netEnt *zconfig.NetworkConfig) bool {
 	switch netEnt.Type {
 	case zconfig.NetworkType_CryptoV4, zconfig.NetworkType_CryptoV6:
@@ -1482,6 +4652,89 @@ func isOverlayNetworkInstance(netInstEntry *zconfig.NetworkInstanceConfig) bool
 	return netInstEntry.InstType == zconfig.ZNetworkInstType_ZnetInstMesh
 }
 
+// translateOverlayInterface returns the app interface without the fields
+// of a legacy overlay (LISP) interface, for network.overlay.compat, and a
+// warning for each dropped field in the order of the fields. An interface
+// on a mesh network instance, which uses them, is returned as is.
+func translateOverlayInterface(cfgApp *zconfig.AppInstanceConfig,
+	cfgNetworkInstances []*zconfig.NetworkInstanceConfig,
+	intfEnt *zconfig.NetworkAdapter) (*zconfig.NetworkAdapter, []string) {
+
+	networkInstanceEntry := lookupNetworkInstanceId(intfEnt.NetworkId,
+		cfgNetworkInstances)
+	if networkInstanceEntry == nil ||
+		isOverlayNetworkInstance(networkInstanceEntry) {
+		return intfEnt, nil
+	}
+	key := fmt.Sprintf("%s/%s", cfgApp.Uuidandversion.Uuid, intfEnt.Name)
+	translated := proto.Clone(intfEnt).(*zconfig.NetworkAdapter)
+	var warnings []string
+	drop := func(field *deprecatedField, what string) {
+		field.hit(key)
+		warnings = append(warnings, fmt.Sprintf(
+			"App %s interface %s: dropped the overlay %s",
+			cfgApp.Displayname, intfEnt.Name, what))
+	}
+	if intfEnt.CryptoEid != "" {
+		translated.CryptoEid = ""
+		drop(deprecatedOverlayCryptoEid, "cryptoEid")
+		// The address is the EID
+		if intfEnt.Addr != "" {
+			translated.Addr = ""
+			drop(deprecatedOverlayAddr, "EID "+intfEnt.Addr)
+		}
+	}
+	if intfEnt.Lispsignature != "" {
+		translated.Lispsignature = ""
+		drop(deprecatedOverlayLispSignature, "lispsignature")
+	}
+	if len(intfEnt.Pemcert) != 0 {
+		translated.Pemcert = nil
+		drop(deprecatedOverlayPemCert, "pemcert")
+	}
+	if len(intfEnt.Pemprivatekey) != 0 {
+		translated.Pemprivatekey = nil
+		drop(deprecatedOverlayPemPrivateKey, "pemprivatekey")
+	}
+	return translated, warnings
+}
+
+// limitUnits maps the units of an ACL rate limit, in lower case, to the
+// units of the iptables limit match. The packets are always counted.
+var limitUnits = map[string]string{
+	"":       "s",
+	"s":      "s",
+	"sec":    "s",
+	"second": "s",
+	"pps":    "s",
+	"m":      "m",
+	"min":    "m",
+	"minute": "m",
+	"ppm":    "m",
+	"h":      "h",
+	"hour":   "h",
+	"pph":    "h",
+	"d":      "d",
+	"day":    "d",
+}
+
+// parseLimitUnit returns the normalized unit of an ACL rate limit
+func parseLimitUnit(unit string) (string, error) {
+	normalized, ok := limitUnits[strings.ToLower(unit)]
+	if !ok {
+		return "", fmt.Errorf("unknown rate limit unit %q", unit)
+	}
+	return normalized, nil
+}
+
+// limitValue converts a rate or burst of a rate limit to a non-negative int
+func limitValue(value uint32) int {
+	if value > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int(value)
+}
+
 func parseUnderlayNetworkConfigEntry(
 	cfgApp *zconfig.AppInstanceConfig,
 	cfgNetworks []*zconfig.NetworkConfig,
@@ -1499,10 +4752,15 @@ func parseUnderlayNetworkConfigEntry(
 		ulCfg.Error = fmt.Sprintf("App %s-%s: Can't find %s in network instances.\n",
 			cfgApp.Displayname, cfgApp.Uuidandversion.Uuid,
 			intfEnt.NetworkId)
+		ulCfg.ErrorCategory = types.AppConfigErrorMissingNetwork
 		log.Errorf("%s", ulCfg.Error)
 		return ulCfg
 	}
 	if isOverlayNetworkInstance(networkInstanceEntry) {
+		if intfEnt.Addr != "" {
+			deprecatedOverlayAddr.hit(fmt.Sprintf("%s/%s",
+				cfgApp.Uuidandversion.Uuid, intfEnt.Name))
+		}
 		return nil
 	}
 	uuid, err := uuid.FromString(intfEnt.NetworkId)
@@ -1510,6 +4768,7 @@ func parseUnderlayNetworkConfigEntry(
 		ulCfg.Error = fmt.Sprintf("App %s-%s: Malformed Network UUID %s. Err: %s\n",
 			cfgApp.Displayname, cfgApp.Uuidandversion.Uuid,
 			intfEnt.NetworkId, err)
+		ulCfg.ErrorCategory = types.AppConfigErrorBadNetworkUUID
 		log.Errorf("%s", ulCfg.Error)
 		return ulCfg
 	}
@@ -1521,11 +4780,12 @@ func parseUnderlayNetworkConfigEntry(
 	if intfEnt.MacAddress != "" {
 		log.Functionf("parseUnderlayNetworkConfig: got static MAC %s",
 			intfEnt.MacAddress)
-		ulCfg.AppMacAddr, err = net.ParseMAC(intfEnt.MacAddress)
+		ulCfg.AppMacAddr, err = parseMacAddr(intfEnt.MacAddress)
 		if err != nil {
 			ulCfg.Error = fmt.Sprintf("App %s-%s: bad MAC:%s, Err: %s\n",
 				cfgApp.Displayname, cfgApp.Uuidandversion.Uuid, intfEnt.MacAddress,
 				err)
+			ulCfg.ErrorCategory = types.AppConfigErrorBadMAC
 			log.Errorf("%s", ulCfg.Error)
 			return ulCfg
 		}
@@ -1537,6 +4797,7 @@ func parseUnderlayNetworkConfigEntry(
 		if ulCfg.AppIPAddr == nil {
 			ulCfg.Error = fmt.Sprintf("App %s-%s: bad AppIPAddr:%s\n",
 				cfgApp.Displayname, cfgApp.Uuidandversion.Uuid, intfEnt.Addr)
+			ulCfg.ErrorCategory = types.AppConfigErrorBadIP
 			log.Errorf("%s", ulCfg.Error)
 			return ulCfg
 		}
@@ -1548,6 +4809,7 @@ func parseUnderlayNetworkConfigEntry(
 		if ulCfg.AppIPAddr.To4() == nil {
 			ulCfg.Error = fmt.Sprintf("Static IPv6 addressing (%s) not yet supported.\n",
 				intfEnt.Addr)
+			ulCfg.ErrorCategory = types.AppConfigErrorBadIP
 			log.Errorf("%s", ulCfg.Error)
 			return ulCfg
 		}
@@ -1571,18 +4833,50 @@ func parseUnderlayNetworkConfigEntry(
 			matchCfg := new(types.ACEMatch)
 			matchCfg.Type = match.Type
 			matchCfg.Value = match.Value
+			// An unknown type would make the rule a no-op
+			if !matchCfg.IsKnownType() {
+				ulCfg.Error = fmt.Sprintf("App %s-%s: ACL %d: unknown match type %q with value %q\n",
+					cfgApp.Displayname, cfgApp.Uuidandversion.Uuid,
+					acl.Id, match.Type, match.Value)
+				ulCfg.ErrorCategory = types.AppConfigErrorBadACL
+				log.Errorf("%s", ulCfg.Error)
+				return ulCfg
+			}
 			aclCfg.Matches[matchIdx] = *matchCfg
 		}
 
 		for actionIdx, action := range acl.Actions {
 			actionCfg := new(types.ACEAction)
+			actionCfg.Drop = action.Drop
 			actionCfg.Limit = action.Limit
-			actionCfg.LimitRate = int(action.Limitrate)
-			actionCfg.LimitUnit = action.Limitunit
-			actionCfg.LimitBurst = int(action.Limitburst)
+			if action.Limit {
+				unit, err := parseLimitUnit(action.Limitunit)
+				if err != nil {
+					ulCfg.Error = fmt.Sprintf("App %s-%s: ACL %d: %s\n",
+						cfgApp.Displayname, cfgApp.Uuidandversion.Uuid,
+						acl.Id, err)
+					ulCfg.ErrorCategory = types.AppConfigErrorBadACL
+					log.Errorf("%s", ulCfg.Error)
+					return ulCfg
+				}
+				actionCfg.LimitRate = limitValue(action.Limitrate)
+				actionCfg.LimitUnit = unit
+				actionCfg.LimitBurst = limitValue(action.Limitburst)
+			}
 			actionCfg.PortMap = action.Portmap
 			actionCfg.TargetPort = int(action.AppPort)
-			// XXX:FIXME actionCfg.Drop = <TBD>
+			if action.DscpMark {
+				if action.Dscp > types.MaxDscp {
+					ulCfg.Error = fmt.Sprintf("App %s-%s: ACL %d: DSCP %d out of range 0-%d\n",
+						cfgApp.Displayname, cfgApp.Uuidandversion.Uuid,
+						acl.Id, action.Dscp, types.MaxDscp)
+					ulCfg.ErrorCategory = types.AppConfigErrorBadACL
+					log.Errorf("%s", ulCfg.Error)
+					return ulCfg
+				}
+				actionCfg.DscpMark = true
+				actionCfg.Dscp = uint8(action.Dscp)
+			}
 			aclCfg.Actions[actionIdx] = *actionCfg
 		}
 		ulCfg.ACLs[aclIdx] = *aclCfg
@@ -1590,29 +4884,41 @@ func parseUnderlayNetworkConfigEntry(
 	// XXX set ulCfg.IntfOrder from API once available
 	ulCfg.IntfOrder = intfOrder
 	ulCfg.AccessVlanID = intfEnt.AccessVlanId
+	for _, hostname := range intfEnt.Hostnames {
+		if err := validateDomainName(hostname); err != nil {
+			ulCfg.Error = fmt.Sprintf("App %s-%s: interface %s: %s\n",
+				cfgApp.Displayname, cfgApp.Uuidandversion.Uuid,
+				intfEnt.Name, err)
+			ulCfg.ErrorCategory = types.AppConfigErrorBadHostname
+			log.Errorf("%s", ulCfg.Error)
+			return ulCfg
+		}
+		hostname = strings.ToLower(strings.TrimSuffix(hostname, "."))
+		duplicate := false
+		for _, h := range ulCfg.Hostnames {
+			if h == hostname {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			ulCfg.Hostnames = append(ulCfg.Hostnames, hostname)
+		}
+	}
 	return ulCfg
 }
 
 var itemsPrevConfigHash []byte
 
-func parseConfigItems(config *zconfig.EdgeDevConfig, ctx *getconfigContext) {
-
-	items := config.GetConfigItems()
-	h := sha256.New()
-	for _, i := range items {
-		computeConfigElementSha(h, i)
-	}
-	configHash := h.Sum(nil)
-	same := bytes.Equal(configHash, itemsPrevConfigHash)
-	itemsPrevConfigHash = configHash
-	if same {
-		return
-	}
-	log.Functionf("parseConfigItems: Applying updated config "+
-		"prevSha: % x, "+
-		"NewSha : % x, "+
-		"items: %v",
-		itemsPrevConfigHash, configHash, items)
+// previewConfigItems parses the config items into a new global config and
+// status without any side effects, so that the result of applying a
+// candidate config can be checked before it is applied.
+// The current global config is only read; it provides the value to retain
+// for an item with an error. The returned error summarizes the items with
+// errors and does not make the returned config and status invalid.
+func previewConfigItems(specMap *types.ConfigItemSpecMap,
+	curGlobalConfig *types.ConfigItemValueMap,
+	items []*zconfig.ConfigItem) (*types.ConfigItemValueMap, *types.GlobalStatus, error) {
 
 	// Start with the defaults so that we revert to default when no data
 	// 1) Use the specified Value if no Errors
@@ -1620,7 +4926,6 @@ func parseConfigItems(config *zconfig.EdgeDevConfig, ctx *getconfigContext) {
 	//  retain the previous value with Error set. In case of val > Max
 	//  or val < Min, Do not try to correct it. Either take the specified
 	//  value or retain the previous value.
-	gcPtr := &ctx.zedagentCtx.globalConfig
 	newGlobalConfig := types.DefaultConfigItemValueMap()
 	// Note: UsbAccess is special in that it has two defaults.
 	// When the device first boots the default is "true" as specified
@@ -1632,23 +4937,71 @@ func parseConfigItems(config *zconfig.EdgeDevConfig, ctx *getconfigContext) {
 	newGlobalConfig.SetGlobalValueBool(types.UsbAccess, false)
 	newGlobalStatus := types.NewGlobalStatus()
 
+	var errList []string
 	for _, item := range items {
-		itemValue, err := ctx.zedagentCtx.specMap.ParseItem(newGlobalConfig,
-			gcPtr, item.Key, item.Value)
+		itemValue, err := specMap.ParseItem(newGlobalConfig,
+			curGlobalConfig, item.Key, item.Value)
 		newGlobalStatus.ConfigItems[item.Key] = types.ConfigItemStatus{
-			Err:   err,
-			Value: itemValue.StringValue(),
+			Err:            err,
+			Value:          itemValue.StringValue(),
+			RequestedValue: item.Value,
+		}
+		if err != nil {
+			errList = append(errList, fmt.Sprintf("%s: %v", item.Key, err))
 		}
 		log.Tracef("Processed ConfigItem: key: %s, Value: %s, itemValue: %+v",
 			item.Key, item.Value, itemValue)
 	}
+	newGlobalStatus.UpdateEffectiveValues(newGlobalConfig,
+		types.DefaultConfigItemValueMap())
+	// Tell the user when a value was rejected, for instance since it is
+	// out of bounds, and which value is used instead
+	for key, itemStatus := range newGlobalStatus.ConfigItems {
+		if itemStatus.Err == nil {
+			continue
+		}
+		itemStatus.Note = fmt.Sprintf("requested value %q rejected; using %q",
+			itemStatus.RequestedValue, itemStatus.EffectiveValue)
+		newGlobalStatus.ConfigItems[key] = itemStatus
+	}
 	log.Tracef("Done with Parsing ConfigItems. globalStatus: %+v",
 		*newGlobalStatus)
+	if len(errList) != 0 {
+		return newGlobalConfig, newGlobalStatus,
+			fmt.Errorf("%d config items with errors: %s",
+				len(errList), strings.Join(errList, "; "))
+	}
+	return newGlobalConfig, newGlobalStatus, nil
+}
+
+func parseConfigItems(config *zconfig.EdgeDevConfig, ctx *getconfigContext) {
+
+	items := config.GetConfigItems()
+	h := sha256.New()
+	for _, i := range items {
+		computeConfigElementSha(h, i)
+	}
+	configHash := h.Sum(nil)
+	same := bytes.Equal(configHash, itemsPrevConfigHash)
+	itemsPrevConfigHash = configHash
+	if same {
+		return
+	}
+	log.Functionf("parseConfigItems: Applying updated config "+
+		"prevSha: % x, "+
+		"NewSha : % x, "+
+		"items: %v",
+		itemsPrevConfigHash, configHash, items)
+
+	gcPtr := &ctx.zedagentCtx.globalConfig
+	newGlobalConfig, newGlobalStatus, err := previewConfigItems(
+		&ctx.zedagentCtx.specMap, gcPtr, items)
+	if err != nil {
+		log.Errorf("parseConfigItems: %v", err)
+	}
+	// Rejected values, for instance ones outside the Min / Max of the
+	// item, are reported to the user in the Note of the item status
 	ctx.zedagentCtx.globalStatus = *newGlobalStatus
-	// XXX - Should we also not call EnforceGlobalConfigMinimums on
-	// newGlobalConfig here before checking if anything changed??
-	// Also - if we changed the Config Value based on Min / Max, we should
-	// report it to the user.
 	if !cmp.Equal(*gcPtr, newGlobalConfig) {
 		log.Functionf("parseConfigItems: change %v",
 			cmp.Diff(*gcPtr, newGlobalConfig))
@@ -1659,26 +5012,28 @@ func parseConfigItems(config *zconfig.EdgeDevConfig, ctx *getconfigContext) {
 		oldConfigInterval := oldGlobalConfig.GlobalValueInt(types.ConfigInterval)
 		newConfigInterval := newGlobalConfig.GlobalValueInt(types.ConfigInterval)
 
-		oldMetricInterval := oldGlobalConfig.GlobalValueInt(types.MetricInterval)
-		newMetricInterval := newGlobalConfig.GlobalValueInt(types.MetricInterval)
-
 		if newConfigInterval != oldConfigInterval {
 			log.Functionf("parseConfigItems: %s change from %d to %d",
 				"ConfigInterval", oldConfigInterval, newConfigInterval)
 			updateConfigTimer(newConfigInterval, ctx.configTickerHandle)
 			updateConfigTimer(newConfigInterval, ctx.localProfileTickerHandle)
 		}
-		if newMetricInterval != oldMetricInterval {
-			log.Functionf("parseConfigItems: %s change from %d to %d",
-				"MetricInterval", oldMetricInterval, newMetricInterval)
-			updateMetricsTimer(newMetricInterval, ctx.metricsTickerHandle)
-		}
+		// Includes the classes which follow MetricInterval
+		updateReportTimers(ctx, &oldGlobalConfig, newGlobalConfig)
 		oldMaintenanceMode := oldGlobalConfig.GlobalValueTriState(types.MaintenanceMode)
 		newMaintenanceMode := newGlobalConfig.GlobalValueTriState(types.MaintenanceMode)
 		if oldMaintenanceMode != newMaintenanceMode {
 			ctx.zedagentCtx.gcpMaintenanceMode = newMaintenanceMode
 			mergeMaintenanceMode(ctx.zedagentCtx)
 		}
+		oldRegion := oldGlobalConfig.GlobalValueString(types.DefaultDatastoreRegion)
+		newRegion := newGlobalConfig.GlobalValueString(types.DefaultDatastoreRegion)
+		if oldRegion != newRegion {
+			log.Functionf("parseConfigItems: %s change from %s to %s",
+				"DefaultDatastoreRegion", oldRegion, newRegion)
+			// Force republish of the datastores
+			datastoreConfigPrevConfigHash = nil
+		}
 
 		pub := ctx.zedagentCtx.pubGlobalConfig
 		err := pub.Publish("global", *gcPtr)
@@ -1758,7 +5113,9 @@ func checkAndPublishAppInstanceConfig(getconfigCtx *getconfigContext,
 			}
 		}
 		log.Error(err)
-		config.Errors = append(config.Errors, err.Error())
+		config.Errors = append(config.Errors,
+			types.NewAppConfigError("", types.AppConfigErrorTooLarge,
+				err.Error()))
 		// Clear out all the fields which can be large
 		config.CloudInitUserData = nil
 		config.CipherData = nil
@@ -1792,9 +5149,9 @@ func publishBaseOs(getconfigCtx *getconfigContext,
 // Get sha256 for a subset of the protobuf message.
 // Used to determine which pieces changed
 func computeConfigSha(msg interface{}) []byte {
-	data, err := json.Marshal(msg)
+	data, err := marshalConfigElement(msg)
 	if err != nil {
-		log.Fatalf("computeConfigSha: proto.Marshal: %s", err)
+		log.Fatalf("computeConfigSha: %s", err)
 	}
 	h := sha256.New()
 	h.Write(data)
@@ -1802,21 +5159,83 @@ func computeConfigSha(msg interface{}) []byte {
 }
 
 // Get sha256 for a subset of the protobuf message.
-// Used to determine which pieces changed
+// Used to determine which pieces changed. Each element is preceded by its
+// length so that an empty element, or a different split between the
+// elements, changes the hash.
 func computeConfigElementSha(h hash.Hash, msg interface{}) {
-	data, err := json.Marshal(msg)
+	data, err := marshalConfigElement(msg)
 	if err != nil {
-		log.Fatalf("computeConfigItemSha: json.Marshal: %s", err)
+		log.Fatalf("computeConfigElementSha: %s", err)
 	}
+	var length [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(length[:], uint64(len(data)))
+	h.Write(length[:n])
 	h.Write(data)
 }
 
+// marshalConfigElement returns a canonical encoding of a config element.
+// Protobuf messages use the deterministic marshaler, which orders map
+// entries such as Phyaddrs by key, hence the same config hashes the same
+// across restarts and versions of the generated code. Anything else falls
+// back to json.
+func marshalConfigElement(msg interface{}) ([]byte, error) {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal: %v", err)
+		}
+		return data, nil
+	}
+	if !proto.MessageReflect(m).IsValid() {
+		// A nil message encodes the same as an empty one
+		return []byte{}, nil
+	}
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	if err := buf.Marshal(m); err != nil {
+		return nil, fmt.Errorf("proto.Marshal: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// parseAddrMode returns the IPv6 address mode after checking that it is
+// compatible with the network type and the DHCP type
+func parseAddrMode(ipspec *zconfig.Ipspec,
+	networkType types.NetworkType) (types.AddrModeType, error) {
+
+	var addrMode types.AddrModeType
+	switch ipspec.GetIpv6AddrMode() {
+	case zconfig.IPv6AddrMode_IPV6_ADDR_MODE_UNSPECIFIED:
+		return types.AM_UNSPECIFIED, nil
+	case zconfig.IPv6AddrMode_IPV6_ADDR_MODE_SLAAC:
+		addrMode = types.AM_SLAAC
+	case zconfig.IPv6AddrMode_IPV6_ADDR_MODE_DHCPV6_STATEFUL:
+		addrMode = types.AM_DHCPV6_STATEFUL
+	case zconfig.IPv6AddrMode_IPV6_ADDR_MODE_DHCPV6_STATELESS:
+		addrMode = types.AM_DHCPV6_STATELESS
+	default:
+		return types.AM_UNSPECIFIED, fmt.Errorf("unknown IPv6 address mode %d",
+			ipspec.GetIpv6AddrMode())
+	}
+	if networkType != types.NT_IPV6 {
+		return types.AM_UNSPECIFIED, fmt.Errorf("IPv6 address mode %s for a non-IPv6 network",
+			addrMode)
+	}
+	if types.DhcpType(ipspec.Dhcp) != types.DT_CLIENT {
+		return types.AM_UNSPECIFIED, fmt.Errorf("IPv6 address mode %s requires DHCP type Client, not %d",
+			addrMode, ipspec.Dhcp)
+	}
+	return addrMode, nil
+}
+
 // Returns a rebootFlag
 func parseOpCmds(config *zconfig.EdgeDevConfig,
-	getconfigCtx *getconfigContext) bool {
+	getconfigCtx *getconfigContext, quiet bool) bool {
 
 	scheduleBackup(config.GetBackup())
-	return scheduleReboot(config.GetReboot(), getconfigCtx)
+	scheduleWipe(config.GetWipe(), getconfigCtx)
+	return scheduleReboot(config.GetReboot(), getconfigCtx, quiet)
 }
 
 // Returns the cmd if the file exists
@@ -1857,7 +5276,7 @@ var rebootPrevReturn bool
 
 // Returns a rebootFlag
 func scheduleReboot(reboot *zconfig.DeviceOpsCmd,
-	getconfigCtx *getconfigContext) bool {
+	getconfigCtx *getconfigContext, quiet bool) bool {
 	if reboot == nil {
 		log.Functionf("scheduleReboot - removing %s",
 			rebootConfigFilename)
@@ -1879,13 +5298,18 @@ func scheduleReboot(reboot *zconfig.DeviceOpsCmd,
 		rebootPrevReturn = false
 		return false
 	}
-	if rebootConfig == nil || rebootConfig.Counter != reboot.Counter {
+	rebootCmd := types.DeviceOpsCmd{
+		Counter:      reboot.Counter,
+		DesiredState: reboot.DesiredState,
+		OpsTime:      reboot.OpsTime,
+	}
+	ctx := getconfigCtx.zedagentCtx
+	// A reboot deferred by the quiet hours only saves the counter once it
+	// is done, so that the command is acted on again if we restart before
+	deferQuiet := rebootConfig != nil && quiet && !reboot.Urgent &&
+		!ctx.deviceReboot && !getconfigCtx.updateInprogress
+	if !deferQuiet {
 		// store current config, persistently
-		rebootCmd := types.DeviceOpsCmd{
-			Counter:      reboot.Counter,
-			DesiredState: reboot.DesiredState,
-			OpsTime:      reboot.OpsTime,
-		}
 		saveRebootConfig(rebootCmd)
 		// We read this into zedagentCtx.rebootConfigCounter and report that
 		// value to the controller once we have rebooted
@@ -1899,19 +5323,26 @@ func scheduleReboot(reboot *zconfig.DeviceOpsCmd,
 	}
 
 	// if device reboot is set, ignore op-command
-	if getconfigCtx.zedagentCtx.deviceReboot {
+	if ctx.deviceReboot {
 		log.Warnf("device reboot is set")
 		return false
 	}
 
 	// Defer if inprogress by returning
-	ctx := getconfigCtx.zedagentCtx
 	if getconfigCtx.updateInprogress {
 		// Wait until TestComplete
 		log.Warnf("Rebooting even though testing inprogress; defer")
 		ctx.rebootCmdDeferred = true
 		return false
 	}
+	if deferQuiet {
+		log.Noticef("scheduleReboot: deferred until the quiet hours end at %s",
+			getconfigCtx.quietHoursEnd.Format(time.RFC3339))
+		getconfigCtx.quietHoursReboot = &rebootCmd
+		publishZedAgentStatus(getconfigCtx)
+		rebootPrevReturn = false
+		return false
+	}
 
 	infoStr := "NORMAL: handleReboot rebooting"
 	handleRebootCmd(ctx, infoStr)
@@ -0,0 +1,8152 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package zedagent
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	zconfig "github.com/lf-edge/eve/api/go/config"
+	zcommon "github.com/lf-edge/eve/api/go/evecommon"
+	"github.com/lf-edge/eve/api/go/info"
+	"github.com/lf-edge/eve/api/go/profile"
+	"github.com/lf-edge/eve/pkg/pillar/base"
+	"github.com/lf-edge/eve/pkg/pillar/cipher"
+	"github.com/lf-edge/eve/pkg/pillar/pubsub"
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	"github.com/lf-edge/eve/pkg/pillar/utils"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	logger = logrus.StandardLogger()
+	log = base.NewSourceLogObject(logger, "zedagent", 1234)
+}
+
+func TestParseIpspecGateway(t *testing.T) {
+	testMatrix := map[string]struct {
+		ipspec        *zconfig.Ipspec
+		expectedError bool
+	}{
+		"Gateway in subnet": {
+			ipspec: &zconfig.Ipspec{
+				Subnet:  "192.168.1.0/24",
+				Gateway: "192.168.1.1",
+			},
+			expectedError: false,
+		},
+		"Gateway outside subnet": {
+			ipspec: &zconfig.Ipspec{
+				Subnet:  "192.168.1.0/24",
+				Gateway: "10.1.1.1",
+			},
+			expectedError: true,
+		},
+		"Gateway is network address": {
+			ipspec: &zconfig.Ipspec{
+				Subnet:  "192.168.1.0/24",
+				Gateway: "192.168.1.0",
+			},
+			expectedError: true,
+		},
+		"Gateway is broadcast address": {
+			ipspec: &zconfig.Ipspec{
+				Subnet:  "192.168.1.0/24",
+				Gateway: "192.168.1.255",
+			},
+			expectedError: true,
+		},
+		"Gateway in /31 subnet": {
+			ipspec: &zconfig.Ipspec{
+				Subnet:  "192.168.1.0/31",
+				Gateway: "192.168.1.0",
+			},
+			expectedError: false,
+		},
+		"Gateway is IPv6 network address": {
+			ipspec: &zconfig.Ipspec{
+				Subnet:  "fd00::/64",
+				Gateway: "fd00::",
+			},
+			expectedError: true,
+		},
+		"Gateway in IPv6 subnet": {
+			ipspec: &zconfig.Ipspec{
+				Subnet:  "fd00::/64",
+				Gateway: "fd00::1",
+			},
+			expectedError: false,
+		},
+		"Gateway without subnet": {
+			ipspec: &zconfig.Ipspec{
+				Gateway: "10.1.1.1",
+			},
+			expectedError: false,
+		},
+		"Gateway outside DhcpRange": {
+			ipspec: &zconfig.Ipspec{
+				Subnet:  "192.168.1.0/24",
+				Gateway: "192.168.1.1",
+				DhcpRange: &zconfig.IpRange{
+					Start: "192.168.1.10",
+					End:   "192.168.1.100",
+				},
+			},
+			expectedError: false,
+		},
+		"Gateway inside DhcpRange": {
+			ipspec: &zconfig.Ipspec{
+				Subnet:  "192.168.1.0/24",
+				Gateway: "192.168.1.50",
+				DhcpRange: &zconfig.IpRange{
+					Start: "192.168.1.10",
+					End:   "192.168.1.100",
+				},
+			},
+			expectedError: true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		var config types.NetworkInstanceConfig
+		err := parseIpspec(test.ipspec, &config)
+		assert.Equal(t, test.expectedError, err != nil, testname)
+
+		var xconfig types.NetworkXObjectConfig
+		err = parseIpspecNetworkXObject(test.ipspec, &xconfig)
+		assert.Equal(t, test.expectedError, err != nil, testname)
+	}
+}
+
+func TestParseIpspecDhcpRange(t *testing.T) {
+	testMatrix := map[string]struct {
+		subnet        string
+		gateway       string
+		dhcpRange     *zconfig.IpRange
+		expectedError bool
+	}{
+		"IPv4 range": {
+			dhcpRange: &zconfig.IpRange{
+				Start: "192.168.1.20",
+				End:   "192.168.1.200",
+			},
+			expectedError: false,
+		},
+		"IPv4 single address range": {
+			dhcpRange: &zconfig.IpRange{
+				Start: "192.168.1.20",
+				End:   "192.168.1.20",
+			},
+			expectedError: false,
+		},
+		"IPv4 range without end": {
+			dhcpRange: &zconfig.IpRange{
+				Start: "192.168.1.20",
+			},
+			expectedError: false,
+		},
+		"IPv4 reversed range": {
+			dhcpRange: &zconfig.IpRange{
+				Start: "192.168.1.200",
+				End:   "192.168.1.20",
+			},
+			expectedError: true,
+		},
+		"IPv4-in-IPv6 reversed range": {
+			dhcpRange: &zconfig.IpRange{
+				Start: "::ffff:192.168.1.200",
+				End:   "192.168.1.20",
+			},
+			expectedError: true,
+		},
+		"IPv6 range": {
+			dhcpRange: &zconfig.IpRange{
+				Start: "fd00::10",
+				End:   "fd00::ff",
+			},
+			expectedError: false,
+		},
+		"IPv6 single address range": {
+			dhcpRange: &zconfig.IpRange{
+				Start: "fd00::10",
+				End:   "fd00::10",
+			},
+			expectedError: false,
+		},
+		"IPv6 reversed range": {
+			dhcpRange: &zconfig.IpRange{
+				Start: "fd00::ff",
+				End:   "fd00::10",
+			},
+			expectedError: true,
+		},
+		"IPv4 range in subnet": {
+			subnet: "192.168.1.0/24",
+			dhcpRange: &zconfig.IpRange{
+				Start: "192.168.1.20",
+				End:   "192.168.1.200",
+			},
+			expectedError: false,
+		},
+		"IPv4 range without end in subnet": {
+			subnet: "192.168.1.0/24",
+			dhcpRange: &zconfig.IpRange{
+				Start: "192.168.1.20",
+			},
+			expectedError: false,
+		},
+		"IPv4 range outside subnet": {
+			subnet: "192.168.1.0/24",
+			dhcpRange: &zconfig.IpRange{
+				Start: "10.1.1.20",
+				End:   "10.1.1.200",
+			},
+			expectedError: true,
+		},
+		"IPv4 range end outside subnet": {
+			subnet: "192.168.1.0/24",
+			dhcpRange: &zconfig.IpRange{
+				Start: "192.168.1.20",
+				End:   "192.168.2.20",
+			},
+			expectedError: true,
+		},
+		"IPv4 range without end outside subnet": {
+			subnet: "192.168.1.0/24",
+			dhcpRange: &zconfig.IpRange{
+				Start: "192.168.2.20",
+			},
+			expectedError: true,
+		},
+		"IPv6 range outside subnet": {
+			subnet: "fd00::/64",
+			dhcpRange: &zconfig.IpRange{
+				Start: "fd01::10",
+				End:   "fd01::ff",
+			},
+			expectedError: true,
+		},
+		"Gateway outside range in subnet": {
+			subnet:  "192.168.1.0/24",
+			gateway: "192.168.1.1",
+			dhcpRange: &zconfig.IpRange{
+				Start: "192.168.1.20",
+				End:   "192.168.1.200",
+			},
+			expectedError: false,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		ipspec := &zconfig.Ipspec{
+			Subnet:    test.subnet,
+			Gateway:   test.gateway,
+			DhcpRange: test.dhcpRange,
+		}
+		var config types.NetworkInstanceConfig
+		err := parseIpspec(ipspec, &config)
+		assert.Equal(t, test.expectedError, err != nil, testname)
+
+		var xconfig types.NetworkXObjectConfig
+		err = parseIpspecNetworkXObject(ipspec, &xconfig)
+		assert.Equal(t, test.expectedError, err != nil, testname)
+	}
+}
+
+func TestParseIpspecDNSServers(t *testing.T) {
+	var manyServers, firstServers []string
+	for i := 1; i <= 20; i++ {
+		manyServers = append(manyServers, fmt.Sprintf("10.0.0.%d", i))
+	}
+	firstServers = manyServers[:maxDNSServers]
+
+	testMatrix := map[string]struct {
+		dns              []string
+		expectedDNS      []string
+		expectedWarnings []string
+	}{
+		"No DNS servers": {
+			dns:         nil,
+			expectedDNS: nil,
+		},
+		"Duplicate DNS servers": {
+			dns:         []string{"8.8.8.8", "1.1.1.1", "8.8.8.8", "8.8.8.8"},
+			expectedDNS: []string{"8.8.8.8", "1.1.1.1"},
+		},
+		"Duplicate IPv6 DNS servers": {
+			dns:         []string{"fd00::1", "fd00:0::1", "fd00::2"},
+			expectedDNS: []string{"fd00::1", "fd00::2"},
+		},
+		"Too many DNS servers": {
+			dns:              manyServers,
+			expectedDNS:      firstServers,
+			expectedWarnings: []string{"20 DNS servers truncated to 8"},
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		ipspec := &zconfig.Ipspec{Dns: test.dns}
+		var config types.NetworkInstanceConfig
+		err := parseIpspec(ipspec, &config)
+		assert.Nil(t, err, testname)
+		var xconfig types.NetworkXObjectConfig
+		err = parseIpspecNetworkXObject(ipspec, &xconfig)
+		assert.Nil(t, err, testname)
+
+		var dnsServers, xDNSServers []string
+		for _, ds := range config.DnsServers {
+			dnsServers = append(dnsServers, ds.String())
+		}
+		for _, ds := range xconfig.DnsServers {
+			xDNSServers = append(xDNSServers, ds.String())
+		}
+		assert.Equal(t, test.expectedDNS, dnsServers, testname)
+		assert.Equal(t, test.expectedDNS, xDNSServers, testname)
+		assert.Equal(t, test.expectedWarnings, config.DnsServerWarnings,
+			testname)
+	}
+
+	// The upstream DNS servers are truncated the same way
+	servers, warning, err := parseUpstreamDNSServers(manyServers,
+		types.AddressTypeIPV4)
+	assert.Nil(t, err)
+	assert.Equal(t, maxDNSServers, len(servers))
+	assert.Equal(t, "upstream 20 DNS servers truncated to 8", warning)
+}
+
+func TestParseIpspecDomainNames(t *testing.T) {
+	testMatrix := map[string]struct {
+		domain              string
+		domains             []string
+		expectedError       bool
+		expectedDomainNames []string
+	}{
+		"No domain": {
+			expectedDomainNames: nil,
+		},
+		"Single domain": {
+			domain:              "example.com",
+			expectedDomainNames: []string{"example.com"},
+		},
+		"Comma and space separated domain": {
+			domain:              "example.com, corp.example.com lab.example.com",
+			expectedDomainNames: []string{"example.com", "corp.example.com", "lab.example.com"},
+		},
+		"Repeated domains": {
+			domains:             []string{"example.com", "corp.example.com"},
+			expectedDomainNames: []string{"example.com", "corp.example.com"},
+		},
+		"Domain and repeated domains with duplicate": {
+			domain:              "example.com",
+			domains:             []string{"Example.com", "corp.example.com"},
+			expectedDomainNames: []string{"example.com", "corp.example.com"},
+		},
+		"Bad character": {
+			domain:        "example_com",
+			expectedError: true,
+		},
+		"Empty label": {
+			domains:       []string{"example..com"},
+			expectedError: true,
+		},
+		"Label starting with hyphen": {
+			domain:        "-example.com",
+			expectedError: true,
+		},
+		"Label too long": {
+			domain:        strings.Repeat("a", 64) + ".com",
+			expectedError: true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		ipspec := &zconfig.Ipspec{Domain: test.domain, Domains: test.domains}
+		var config types.NetworkInstanceConfig
+		err := parseIpspec(ipspec, &config)
+		var xconfig types.NetworkXObjectConfig
+		xerr := parseIpspecNetworkXObject(ipspec, &xconfig)
+		if test.expectedError {
+			assert.NotNil(t, err, testname)
+			assert.NotNil(t, xerr, testname)
+			continue
+		}
+		assert.Nil(t, err, testname)
+		assert.Nil(t, xerr, testname)
+		assert.Equal(t, test.expectedDomainNames, config.DomainNames, testname)
+		assert.Equal(t, test.expectedDomainNames, xconfig.DomainNames, testname)
+		expectedDomainName := ""
+		if len(test.expectedDomainNames) != 0 {
+			expectedDomainName = test.expectedDomainNames[0]
+		}
+		assert.Equal(t, expectedDomainName, config.DomainName, testname)
+		assert.Equal(t, expectedDomainName, xconfig.DomainName, testname)
+	}
+}
+
+func TestParseIpspecStaticRoutes(t *testing.T) {
+	testMatrix := map[string]struct {
+		subnet         string
+		route          *zconfig.IPRoute
+		expectedRoute  bool
+		expectedErrors int
+	}{
+		"Valid route": {
+			subnet: "192.168.1.0/24",
+			route: &zconfig.IPRoute{
+				Destination: "10.10.0.0/16",
+				Gateway:     "192.168.1.2",
+			},
+			expectedRoute: true,
+		},
+		"Valid IPv6 route": {
+			subnet: "fd00::/64",
+			route: &zconfig.IPRoute{
+				Destination: "fd01::/64",
+				Gateway:     "fd00::2",
+			},
+			expectedRoute: true,
+		},
+		"Bad destination": {
+			subnet: "192.168.1.0/24",
+			route: &zconfig.IPRoute{
+				Destination: "10.10.0.0",
+				Gateway:     "192.168.1.2",
+			},
+			expectedErrors: 1,
+		},
+		"Bad gateway": {
+			subnet: "192.168.1.0/24",
+			route: &zconfig.IPRoute{
+				Destination: "10.10.0.0/16",
+				Gateway:     "192.168.1",
+			},
+			expectedErrors: 1,
+		},
+		"Gateway outside subnet": {
+			subnet: "192.168.1.0/24",
+			route: &zconfig.IPRoute{
+				Destination: "10.10.0.0/16",
+				Gateway:     "192.168.2.2",
+			},
+			expectedErrors: 1,
+		},
+		"No subnet": {
+			route: &zconfig.IPRoute{
+				Destination: "10.10.0.0/16",
+				Gateway:     "192.168.1.2",
+			},
+			expectedErrors: 1,
+		},
+		"Destination is subnet": {
+			subnet: "192.168.1.0/24",
+			route: &zconfig.IPRoute{
+				Destination: "192.168.1.0/24",
+				Gateway:     "192.168.1.2",
+			},
+			expectedErrors: 1,
+		},
+		"Destination inside subnet": {
+			subnet: "192.168.0.0/16",
+			route: &zconfig.IPRoute{
+				Destination: "192.168.10.0/24",
+				Gateway:     "192.168.1.2",
+			},
+			expectedErrors: 1,
+		},
+		"Destination contains subnet": {
+			subnet: "192.168.1.0/24",
+			route: &zconfig.IPRoute{
+				Destination: "0.0.0.0/0",
+				Gateway:     "192.168.1.2",
+			},
+			expectedErrors: 1,
+		},
+		"Address family mismatch": {
+			subnet: "192.168.1.0/24",
+			route: &zconfig.IPRoute{
+				Destination: "fd01::/64",
+				Gateway:     "192.168.1.2",
+			},
+			expectedErrors: 1,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		ipspec := &zconfig.Ipspec{
+			Subnet: test.subnet,
+			Routes: []*zconfig.IPRoute{test.route},
+		}
+		var config types.NetworkInstanceConfig
+		err := parseIpspec(ipspec, &config)
+		assert.Nil(t, err, testname)
+		assert.Equal(t, test.expectedErrors, len(config.StaticRouteErrors),
+			testname)
+		if test.expectedRoute {
+			assert.Equal(t, 1, len(config.StaticRoutes), testname)
+			_, dst, _ := net.ParseCIDR(test.route.Destination)
+			assert.Equal(t, *dst, config.StaticRoutes[0].DstNetwork,
+				testname)
+			assert.True(t, net.ParseIP(test.route.Gateway).Equal(
+				config.StaticRoutes[0].Gateway), testname)
+		} else {
+			assert.Equal(t, 0, len(config.StaticRoutes), testname)
+		}
+	}
+
+	// Invalid routes do not affect the valid ones
+	ipspec := &zconfig.Ipspec{
+		Subnet: "192.168.1.0/24",
+		Routes: []*zconfig.IPRoute{
+			{Destination: "10.10.0.0/16", Gateway: "192.168.1.2"},
+			{Destination: "10.11.0.0/16", Gateway: "192.168.2.2"},
+			{Destination: "10.12.0.0/16", Gateway: "192.168.1.3"},
+		},
+	}
+	var config types.NetworkInstanceConfig
+	err := parseIpspec(ipspec, &config)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(config.StaticRoutes))
+	assert.Equal(t, 1, len(config.StaticRouteErrors))
+}
+
+func TestCheckPurgeForChanges(t *testing.T) {
+	userData1 := "#cloud-config\n"
+	userData2 := "#cloud-config\nhostname: app\n"
+	appUUID, _ := uuid.FromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+
+	// Each step is applied in order to the same app instance
+	steps := []struct {
+		name            string
+		userData        *string
+		cipherData      []byte
+		purgeCounter    uint32
+		expectedNeeds   bool
+		expectedChanges []string
+	}{
+		{
+			name:     "Initial config",
+			userData: &userData1,
+		},
+		{
+			name:            "Changed user data",
+			userData:        &userData2,
+			expectedNeeds:   true,
+			expectedChanges: []string{"CloudInitUserData"},
+		},
+		{
+			name:         "Cleared by purge",
+			userData:     &userData2,
+			purgeCounter: 1,
+		},
+		{
+			name:            "Changed user data and cipher data",
+			userData:        &userData1,
+			cipherData:      []byte{1, 2, 3},
+			purgeCounter:    1,
+			expectedNeeds:   true,
+			expectedChanges: []string{"CloudInitUserData", "CipherData"},
+		},
+		{
+			name:         "Cleared by revert",
+			userData:     &userData2,
+			purgeCounter: 1,
+		},
+		{
+			name:            "Removed user data",
+			purgeCounter:    1,
+			expectedNeeds:   true,
+			expectedChanges: []string{"CloudInitUserData"},
+		},
+	}
+
+	for _, step := range steps {
+		t.Logf("Running test case %s", step.name)
+		appInstance := types.AppInstanceConfig{
+			UUIDandVersion:    types.UUIDandVersion{UUID: appUUID},
+			CloudInitUserData: step.userData,
+		}
+		appInstance.CipherData = step.cipherData
+		appInstance.PurgeCmd.Counter = step.purgeCounter
+		checkPurgeForChanges(&appInstance)
+		assert.Equal(t, step.expectedNeeds,
+			appInstance.NeedsPurgeForChanges, step.name)
+		assert.Equal(t, step.expectedChanges,
+			appInstance.PendingPurgeChanges, step.name)
+	}
+}
+
+func newTestAppInstance(uuidStr string, name string) *zconfig.AppInstanceConfig {
+	return &zconfig.AppInstanceConfig{
+		Uuidandversion: &zconfig.UUIDandVersion{
+			Uuid:    uuidStr,
+			Version: "1",
+		},
+		Displayname:    name,
+		Fixedresources: &zconfig.VmConfig{},
+	}
+}
+
+func initGetConfigCtx(t *testing.T) *getconfigContext {
+	ps := pubsub.New(&pubsub.EmptyDriver{}, logger, log)
+	pubAppInstanceConfig, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.AppInstanceConfig{},
+	})
+	assert.Nil(t, err)
+	pubNetworkInstanceConfig, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.NetworkInstanceConfig{},
+	})
+	assert.Nil(t, err)
+	pubDatastoreConfig, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.DatastoreConfig{},
+	})
+	assert.Nil(t, err)
+	pubBaseOsConfig, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.BaseOsConfig{},
+	})
+	assert.Nil(t, err)
+	pubNetworkXObjectConfig, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.NetworkXObjectConfig{},
+	})
+	assert.Nil(t, err)
+	pubZedAgentStatus, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.ZedAgentStatus{},
+	})
+	assert.Nil(t, err)
+	pubPhysicalIOAdapters, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.PhysicalIOAdapterList{},
+	})
+	assert.Nil(t, err)
+	pubDevicePortConfig, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.DevicePortConfig{},
+	})
+	assert.Nil(t, err)
+	pubSystemAdapterReport, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.SystemAdapterReport{},
+	})
+	assert.Nil(t, err)
+	pubDevicePortConfigParseStatus, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.DevicePortConfigParseStatus{},
+	})
+	assert.Nil(t, err)
+	pubContentTreeConfig, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.ContentTreeConfig{},
+	})
+	assert.Nil(t, err)
+	pubAppVolumeRetention, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.AppVolumeRetention{},
+	})
+	assert.Nil(t, err)
+	pubAppLogPolicyConfig, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.AppLogPolicyConfig{},
+	})
+	assert.Nil(t, err)
+	pubWipeRequest, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName: agentName,
+		TopicType: types.WipeRequest{},
+	})
+	assert.Nil(t, err)
+	subAppInstanceStatus, err := ps.NewSubscription(pubsub.SubscriptionOptions{
+		AgentName: "zedmanager",
+		TopicImpl: types.AppInstanceStatus{},
+	})
+	assert.Nil(t, err)
+	getconfigCtx := &getconfigContext{
+		subAppInstanceStatus:           subAppInstanceStatus,
+		pubAppInstanceConfig:           pubAppInstanceConfig,
+		pubNetworkInstanceConfig:       pubNetworkInstanceConfig,
+		pubDatastoreConfig:             pubDatastoreConfig,
+		pubBaseOsConfig:                pubBaseOsConfig,
+		pubNetworkXObjectConfig:        pubNetworkXObjectConfig,
+		pubZedAgentStatus:              pubZedAgentStatus,
+		pubPhysicalIOAdapters:          pubPhysicalIOAdapters,
+		pubDevicePortConfig:            pubDevicePortConfig,
+		pubSystemAdapterReport:         pubSystemAdapterReport,
+		pubDevicePortConfigParseStatus: pubDevicePortConfigParseStatus,
+		pubContentTreeConfig:           pubContentTreeConfig,
+		pubAppVolumeRetention:          pubAppVolumeRetention,
+		pubAppLogPolicyConfig:          pubAppLogPolicyConfig,
+		pubWipeRequest:                 pubWipeRequest,
+	}
+	getconfigCtx.zedagentCtx = &zedagentContext{
+		getconfigCtx: getconfigCtx,
+		globalConfig: *types.DefaultConfigItemValueMap(),
+	}
+	return getconfigCtx
+}
+
+func TestParseAppInstanceConfigPerApp(t *testing.T) {
+	getconfigCtx := initGetConfigCtx(t)
+	pubAppInstanceConfig := getconfigCtx.pubAppInstanceConfig
+	appinstancePrevConfigHash = nil
+	appinstancePrevElementHash = make(map[string][]byte)
+
+	uuidA := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	uuidB := "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	getDisplayName := func(uuidStr string) string {
+		c, err := pubAppInstanceConfig.Get(uuidStr)
+		if err != nil {
+			return ""
+		}
+		return c.(types.AppInstanceConfig).DisplayName
+	}
+
+	config := &zconfig.EdgeDevConfig{
+		Apps: []*zconfig.AppInstanceConfig{
+			newTestAppInstance(uuidA, "appA"),
+			newTestAppInstance(uuidB, "appB"),
+		},
+	}
+	parseAppInstanceConfig(config, getconfigCtx)
+	assert.Equal(t, "appA", getDisplayName(uuidA))
+	assert.Equal(t, "appB", getDisplayName(uuidB))
+
+	// Modify what is published for appB so we can tell if it is
+	// republished when only appA changes
+	appB := types.AppInstanceConfig{DisplayName: "unchanged"}
+	appB.UUIDandVersion.UUID, _ = uuid.FromString(uuidB)
+	pubAppInstanceConfig.Publish(uuidB, appB)
+
+	config.Apps[0].Displayname = "appA-modified"
+	parseAppInstanceConfig(config, getconfigCtx)
+	assert.Equal(t, "appA-modified", getDisplayName(uuidA))
+	assert.Equal(t, "unchanged", getDisplayName(uuidB))
+
+	// Delete appA
+	config.Apps = config.Apps[1:]
+	parseAppInstanceConfig(config, getconfigCtx)
+	assert.Equal(t, "", getDisplayName(uuidA))
+	assert.Equal(t, "unchanged", getDisplayName(uuidB))
+	_, ok := appinstancePrevElementHash[uuidA]
+	assert.False(t, ok)
+
+	// Adding it back should publish it again
+	config.Apps = append(config.Apps, newTestAppInstance(uuidA, "appA"))
+	parseAppInstanceConfig(config, getconfigCtx)
+	assert.Equal(t, "appA", getDisplayName(uuidA))
+}
+
+func TestParseAppInstanceConfigPerAppNetworking(t *testing.T) {
+	getconfigCtx := initGetConfigCtx(t)
+	pubAppInstanceConfig := getconfigCtx.pubAppInstanceConfig
+	appinstancePrevConfigHash = nil
+	appinstancePrevElementHash = make(map[string][]byte)
+
+	networkedID := uuid.NewV4().String()
+	isolatedID := uuid.NewV4().String()
+	networkID := uuid.NewV4().String()
+	getDisplayName := func(uuidStr string) string {
+		c, err := pubAppInstanceConfig.Get(uuidStr)
+		assert.Nil(t, err)
+		return c.(types.AppInstanceConfig).DisplayName
+	}
+	// Modify what is published so we can tell if it is republished
+	markUnchanged := func(uuidStr string) {
+		app := types.AppInstanceConfig{DisplayName: "unchanged"}
+		app.UUIDandVersion.UUID, _ = uuid.FromString(uuidStr)
+		pubAppInstanceConfig.Publish(uuidStr, app)
+	}
+
+	networked := newTestAppInstance(networkedID, "networked")
+	networked.Interfaces = []*zconfig.NetworkAdapter{
+		{Name: "eth0", NetworkId: networkID},
+	}
+	config := &zconfig.EdgeDevConfig{
+		Apps: []*zconfig.AppInstanceConfig{
+			networked,
+			newTestAppInstance(isolatedID, "isolated"),
+		},
+		NetworkInstances: []*zconfig.NetworkInstanceConfig{
+			{Uuidandversion: &zconfig.UUIDandVersion{Uuid: networkID},
+				InstType: zconfig.ZNetworkInstType_ZnetInstLocal},
+		},
+	}
+	parseAppInstanceConfig(config, getconfigCtx)
+	assert.Equal(t, "networked", getDisplayName(networkedID))
+	assert.Equal(t, "isolated", getDisplayName(isolatedID))
+
+	// Only the app with interfaces depends on the overlay compatibility
+	markUnchanged(networkedID)
+	markUnchanged(isolatedID)
+	getconfigCtx.zedagentCtx.globalConfig.SetGlobalValueBool(
+		types.NetworkOverlayCompat, true)
+	parseAppInstanceConfig(config, getconfigCtx)
+	assert.Equal(t, "networked", getDisplayName(networkedID))
+	assert.Equal(t, "unchanged", getDisplayName(isolatedID))
+
+	// Likewise for its network instance
+	markUnchanged(networkedID)
+	config.NetworkInstances[0].InstType = zconfig.ZNetworkInstType_ZnetInstSwitch
+	parseAppInstanceConfig(config, getconfigCtx)
+	assert.Equal(t, "networked", getDisplayName(networkedID))
+	assert.Equal(t, "unchanged", getDisplayName(isolatedID))
+}
+
+func TestPublishNetworkInstanceConfigMtu(t *testing.T) {
+	testMatrix := map[string]struct {
+		instType      zconfig.ZNetworkInstType
+		ipType        zconfig.AddressType
+		passiveIpType zconfig.AddressType
+		mtu           uint32
+		expectedError bool
+	}{
+		"Default MTU": {
+			ipType:        zconfig.AddressType_IPV4,
+			mtu:           0,
+			expectedError: false,
+		},
+		"IPv4 jumbo MTU": {
+			ipType:        zconfig.AddressType_IPV4,
+			mtu:           9000,
+			expectedError: false,
+		},
+		"IPv4 minimum MTU": {
+			ipType:        zconfig.AddressType_IPV4,
+			mtu:           576,
+			expectedError: false,
+		},
+		"IPv4 MTU too small": {
+			ipType:        zconfig.AddressType_IPV4,
+			mtu:           575,
+			expectedError: true,
+		},
+		"IPv4 MTU too large": {
+			ipType:        zconfig.AddressType_IPV4,
+			mtu:           9001,
+			expectedError: true,
+		},
+		"IPv6 minimum MTU": {
+			ipType:        zconfig.AddressType_IPV6,
+			mtu:           1280,
+			expectedError: false,
+		},
+		"IPv6 MTU too small": {
+			ipType:        zconfig.AddressType_IPV6,
+			mtu:           1279,
+			expectedError: true,
+		},
+		"Switch jumbo MTU": {
+			instType: zconfig.ZNetworkInstType_ZnetInstSwitch,
+			mtu:      9000,
+		},
+		"Switch minimum MTU": {
+			instType: zconfig.ZNetworkInstType_ZnetInstSwitch,
+			mtu:      576,
+		},
+		"Switch MTU too large": {
+			instType:      zconfig.ZNetworkInstType_ZnetInstSwitch,
+			mtu:           9001,
+			expectedError: true,
+		},
+		"Switch observing IPv6 MTU too small": {
+			instType:      zconfig.ZNetworkInstType_ZnetInstSwitch,
+			passiveIpType: zconfig.AddressType_IPV6,
+			mtu:           1279,
+			expectedError: true,
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	uuidStr := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		instType := test.instType
+		if instType == zconfig.ZNetworkInstType_ZNetInstFirst {
+			instType = zconfig.ZNetworkInstType_ZnetInstLocal
+		}
+		networkInstances := []*zconfig.NetworkInstanceConfig{
+			{
+				Uuidandversion: &zconfig.UUIDandVersion{
+					Uuid:    uuidStr,
+					Version: "1",
+				},
+				InstType:      instType,
+				IpType:        test.ipType,
+				PassiveIpType: test.passiveIpType,
+				Ip:            &zconfig.Ipspec{},
+				Mtu:           test.mtu,
+			},
+		}
+		publishNetworkInstanceConfig(getconfigCtx, networkInstances)
+		c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+		assert.Nil(t, err, testname)
+		config := c.(types.NetworkInstanceConfig)
+		assert.Equal(t, test.expectedError, config.HasError(), testname)
+		assert.Equal(t, test.mtu, config.Mtu, testname)
+	}
+}
+
+func TestPublishNetworkInstanceConfigErrors(t *testing.T) {
+	testMatrix := map[string]struct {
+		uuid           string
+		instType       zconfig.ZNetworkInstType
+		ipType         zconfig.AddressType
+		opaque         *zconfig.NetworkInstanceOpaqueConfig
+		expectedError  bool
+		expectedIpType types.AddressType
+		notPublish     bool
+	}{
+		"Valid local": {
+			uuid:           "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			instType:       zconfig.ZNetworkInstType_ZnetInstLocal,
+			ipType:         zconfig.AddressType_IPV4,
+			expectedIpType: types.AddressTypeIPV4,
+		},
+		"Valid switch": {
+			uuid:           "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			instType:       zconfig.ZNetworkInstType_ZnetInstSwitch,
+			ipType:         zconfig.AddressType_First,
+			expectedIpType: types.AddressTypeNone,
+		},
+		"Switch with IpType": {
+			uuid:           "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			instType:       zconfig.ZNetworkInstType_ZnetInstSwitch,
+			ipType:         zconfig.AddressType_IPV4,
+			expectedIpType: types.AddressTypeNone,
+		},
+		"Cloud without opaque config": {
+			uuid:           "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			instType:       zconfig.ZNetworkInstType_ZnetInstCloud,
+			ipType:         zconfig.AddressType_IPV4,
+			expectedError:  true,
+			expectedIpType: types.AddressTypeIPV4,
+		},
+		"Cloud with wrong opaque type": {
+			uuid:     "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			instType: zconfig.ZNetworkInstType_ZnetInstCloud,
+			ipType:   zconfig.AddressType_IPV4,
+			opaque: &zconfig.NetworkInstanceOpaqueConfig{
+				Type: zconfig.ZNetworkOpaqueConfigType_ZNetOConfigLisp,
+			},
+			expectedError:  true,
+			expectedIpType: types.AddressTypeIPV4,
+		},
+		"Malformed UUID": {
+			uuid:       "not-a-uuid",
+			instType:   zconfig.ZNetworkInstType_ZnetInstLocal,
+			ipType:     zconfig.AddressType_IPV4,
+			notPublish: true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		networkInstances := []*zconfig.NetworkInstanceConfig{
+			{
+				Uuidandversion: &zconfig.UUIDandVersion{
+					Uuid:    test.uuid,
+					Version: "1",
+				},
+				Displayname: testname,
+				InstType:    test.instType,
+				IpType:      test.ipType,
+				Ip:          &zconfig.Ipspec{},
+				Cfg:         test.opaque,
+			},
+		}
+		publishNetworkInstanceConfig(getconfigCtx, networkInstances)
+		items := getconfigCtx.pubNetworkInstanceConfig.GetAll()
+		if test.notPublish {
+			assert.Equal(t, 0, len(items), testname)
+			continue
+		}
+		assert.Equal(t, 1, len(items), testname)
+		c, err := getconfigCtx.pubNetworkInstanceConfig.Get(test.uuid)
+		assert.Nil(t, err, testname)
+		config := c.(types.NetworkInstanceConfig)
+		assert.Equal(t, test.expectedError, config.HasError(), testname)
+		assert.Equal(t, test.expectedIpType, config.IpType, testname)
+		assert.Equal(t, testname, config.DisplayName, testname)
+
+		// Removed when the entry is removed from the config
+		publishNetworkInstanceConfig(getconfigCtx, nil)
+		assert.Equal(t, 0, len(getconfigCtx.pubNetworkInstanceConfig.GetAll()),
+			testname)
+	}
+}
+
+func TestHoldAppActivateDuringUpdate(t *testing.T) {
+	getconfigCtx := initGetConfigCtx(t)
+	zedagentCtx := &zedagentContext{
+		getconfigCtx: getconfigCtx,
+		globalConfig: *types.DefaultConfigItemValueMap(),
+	}
+	zedagentCtx.globalConfig.SetGlobalValueBool(
+		types.HoldAppActivateDuringUpdate, true)
+	getconfigCtx.zedagentCtx = zedagentCtx
+	appinstancePrevConfigHash = nil
+	appinstancePrevElementHash = make(map[string][]byte)
+	pub := getconfigCtx.pubAppInstanceConfig
+	getAppInstance := func(uuidStr string) types.AppInstanceConfig {
+		c, err := pub.Get(uuidStr)
+		assert.Nil(t, err)
+		return c.(types.AppInstanceConfig)
+	}
+
+	uuidA := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	uuidB := "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	uuidC := "6ba7b812-9dad-11d1-80b4-00c04fd430c8"
+	appA := newTestAppInstance(uuidA, "appA")
+	appA.Activate = true
+	config := &zconfig.EdgeDevConfig{
+		Apps: []*zconfig.AppInstanceConfig{appA},
+	}
+	parseAppInstanceConfig(config, getconfigCtx)
+	assert.True(t, getAppInstance(uuidA).Activate)
+
+	// Start testing a new base OS. Already active apps are untouched
+	// and new ones are held.
+	handleNodeAgentStatusImpl(getconfigCtx, "nodeagent",
+		types.NodeAgentStatus{UpdateInprogress: true})
+	appA.Displayname = "appA-modified"
+	appB := newTestAppInstance(uuidB, "appB")
+	appB.Activate = true
+	appC := newTestAppInstance(uuidC, "appC")
+	config.Apps = append(config.Apps, appB, appC)
+	parseAppInstanceConfig(config, getconfigCtx)
+	assert.True(t, getAppInstance(uuidA).Activate)
+	assert.False(t, getAppInstance(uuidA).HeldForBaseOsTesting)
+	assert.False(t, getAppInstance(uuidB).Activate)
+	assert.True(t, getAppInstance(uuidB).HeldForBaseOsTesting)
+	assert.False(t, getAppInstance(uuidC).Activate)
+	assert.False(t, getAppInstance(uuidC).HeldForBaseOsTesting)
+
+	// Released when testing completes
+	handleNodeAgentStatusImpl(getconfigCtx, "nodeagent",
+		types.NodeAgentStatus{UpdateInprogress: false})
+	assert.True(t, getAppInstance(uuidB).Activate)
+	assert.False(t, getAppInstance(uuidB).HeldForBaseOsTesting)
+	assert.False(t, getAppInstance(uuidC).Activate)
+
+	// Held apps stay inactive when the testing fails and we reboot
+	handleNodeAgentStatusImpl(getconfigCtx, "nodeagent",
+		types.NodeAgentStatus{UpdateInprogress: true})
+	appC.Activate = true
+	parseAppInstanceConfig(config, getconfigCtx)
+	assert.False(t, getAppInstance(uuidC).Activate)
+	assert.True(t, getAppInstance(uuidC).HeldForBaseOsTesting)
+	handleNodeAgentStatusImpl(getconfigCtx, "nodeagent",
+		types.NodeAgentStatus{UpdateInprogress: true, DeviceReboot: true})
+	assert.False(t, getAppInstance(uuidC).Activate)
+	assert.True(t, getAppInstance(uuidC).HeldForBaseOsTesting)
+}
+
+func TestHoldAppActivateDefault(t *testing.T) {
+	getconfigCtx := initGetConfigCtx(t)
+	zedagentCtx := &zedagentContext{
+		getconfigCtx: getconfigCtx,
+		globalConfig: *types.DefaultConfigItemValueMap(),
+	}
+	getconfigCtx.zedagentCtx = zedagentCtx
+	getconfigCtx.updateInprogress = true
+	appinstancePrevConfigHash = nil
+	appinstancePrevElementHash = make(map[string][]byte)
+
+	uuidA := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	appA := newTestAppInstance(uuidA, "appA")
+	appA.Activate = true
+	config := &zconfig.EdgeDevConfig{
+		Apps: []*zconfig.AppInstanceConfig{appA},
+	}
+	parseAppInstanceConfig(config, getconfigCtx)
+	c, err := getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+	assert.Nil(t, err)
+	assert.True(t, c.(types.AppInstanceConfig).Activate)
+	assert.False(t, c.(types.AppInstanceConfig).HeldForBaseOsTesting)
+}
+
+func TestHoldAppActivateCanary(t *testing.T) {
+	getconfigCtx := initGetConfigCtx(t)
+	getconfigCtx.zedagentCtx.globalConfig.SetGlobalValueBool(
+		types.HoldAppActivateDuringUpdate, true)
+	getconfigCtx.updateInprogress = true
+	appinstancePrevConfigHash = nil
+	appinstancePrevElementHash = make(map[string][]byte)
+
+	uuidA := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	uuidB := "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	baseOsConfig := types.BaseOsConfig{
+		UUIDandVersion: types.UUIDandVersion{
+			UUID:    uuid.FromStringOrNil("6ba7b812-9dad-11d1-80b4-00c04fd430c8"),
+			Version: "1"},
+		BaseOsVersion: "6.0.0",
+		Activate:      true,
+		CanaryAppUUID: uuid.FromStringOrNil(uuidA),
+	}
+	getconfigCtx.pubBaseOsConfig.Publish(baseOsConfig.Key(), baseOsConfig)
+	appA := newTestAppInstance(uuidA, "canary")
+	appA.Activate = true
+	appB := newTestAppInstance(uuidB, "appB")
+	appB.Activate = true
+	config := &zconfig.EdgeDevConfig{
+		Apps: []*zconfig.AppInstanceConfig{appA, appB},
+	}
+	parseAppInstanceConfig(config, getconfigCtx)
+
+	// The canary is not held since the test waits for it
+	c, err := getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+	assert.Nil(t, err)
+	assert.True(t, c.(types.AppInstanceConfig).Activate)
+	assert.False(t, c.(types.AppInstanceConfig).HeldForBaseOsTesting)
+	c, err = getconfigCtx.pubAppInstanceConfig.Get(uuidB)
+	assert.Nil(t, err)
+	assert.False(t, c.(types.AppInstanceConfig).Activate)
+	assert.True(t, c.(types.AppInstanceConfig).HeldForBaseOsTesting)
+}
+
+func TestPublishDatastoreConfigRegion(t *testing.T) {
+	testMatrix := map[string]struct {
+		region           string
+		setDefaultRegion bool
+		defaultRegion    string
+		expectedRegion   string
+	}{
+		"Region specified": {
+			region:         "ap-south-1",
+			expectedRegion: "ap-south-1",
+		},
+		"Default region": {
+			expectedRegion: "us-west-2",
+		},
+		"Configured default region": {
+			setDefaultRegion: true,
+			defaultRegion:    "eu-central-1",
+			expectedRegion:   "eu-central-1",
+		},
+		"Region overrides configured default": {
+			region:           "ap-south-1",
+			setDefaultRegion: true,
+			defaultRegion:    "eu-central-1",
+			expectedRegion:   "ap-south-1",
+		},
+		"Empty configured default region": {
+			setDefaultRegion: true,
+			defaultRegion:    "",
+			expectedRegion:   "us-west-2",
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	dsID := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx.zedagentCtx = &zedagentContext{
+			globalConfig: *types.DefaultConfigItemValueMap(),
+		}
+		if test.setDefaultRegion {
+			getconfigCtx.zedagentCtx.globalConfig.SetGlobalValueString(
+				types.DefaultDatastoreRegion, test.defaultRegion)
+		}
+		publishDatastoreConfig(getconfigCtx, []*zconfig.DatastoreConfig{
+			{
+				Id:     dsID,
+				DType:  zconfig.DsType_DsS3,
+				Region: test.region,
+			},
+		})
+		c, err := getconfigCtx.pubDatastoreConfig.Get(dsID)
+		assert.Nil(t, err, testname)
+		assert.Equal(t, test.expectedRegion,
+			c.(types.DatastoreConfig).Region, testname)
+	}
+}
+
+func TestProcessLocalAppRestarts(t *testing.T) {
+	getconfigCtx := initGetConfigCtx(t)
+	appinstancePrevConfigHash = nil
+	appinstancePrevElementHash = make(map[string][]byte)
+	pub := getconfigCtx.pubAppInstanceConfig
+	getAppInstance := func(uuidStr string) types.AppInstanceConfig {
+		c, err := pub.Get(uuidStr)
+		assert.Nil(t, err)
+		return c.(types.AppInstanceConfig)
+	}
+
+	uuidA := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	uuidB := "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	appA := newTestAppInstance(uuidA, "appA")
+	appA.AllowLocalRestart = true
+	appB := newTestAppInstance(uuidB, "appB")
+	config := &zconfig.EdgeDevConfig{
+		Apps: []*zconfig.AppInstanceConfig{appA, appB},
+	}
+	parseAppInstanceConfig(config, getconfigCtx)
+	assert.True(t, getAppInstance(uuidA).AllowLocalRestart)
+	assert.False(t, getAppInstance(uuidB).AllowLocalRestart)
+
+	// The first response only records the counters
+	restarts := []*profile.LocalAppRestart{
+		{AppId: uuidA, Counter: 1},
+		{AppId: uuidB, Counter: 1},
+	}
+	processLocalAppRestarts(getconfigCtx, restarts)
+	assert.Equal(t, uint32(0), getAppInstance(uuidA).LocalRestartCmd.Counter)
+	assert.Empty(t, getconfigCtx.localRestartAudit)
+
+	// Unchanged counters are ignored
+	processLocalAppRestarts(getconfigCtx, restarts)
+	assert.Equal(t, uint32(0), getAppInstance(uuidA).LocalRestartCmd.Counter)
+	assert.Empty(t, getconfigCtx.localRestartAudit)
+
+	// Allowed
+	restarts[0].Counter = 2
+	processLocalAppRestarts(getconfigCtx, restarts)
+	assert.Equal(t, uint32(1), getAppInstance(uuidA).LocalRestartCmd.Counter)
+	assert.Equal(t, 1, len(getconfigCtx.localRestartAudit))
+	entry := getconfigCtx.localRestartAudit[0]
+	assert.Equal(t, uuidA, entry.AppUUID)
+	assert.Equal(t, uint32(2), entry.Counter)
+	assert.True(t, entry.Accepted)
+	assert.Equal(t, "", entry.Reason)
+
+	// Disallowed
+	restarts[1].Counter = 2
+	processLocalAppRestarts(getconfigCtx, restarts)
+	assert.Equal(t, uint32(0), getAppInstance(uuidB).LocalRestartCmd.Counter)
+	assert.Equal(t, 2, len(getconfigCtx.localRestartAudit))
+	entry = getconfigCtx.localRestartAudit[1]
+	assert.Equal(t, uuidB, entry.AppUUID)
+	assert.Equal(t, uint32(2), entry.Counter)
+	assert.False(t, entry.Accepted)
+	assert.NotEmpty(t, entry.Reason)
+
+	// A controller config change keeps the local restart counter
+	appA.Displayname = "appA-modified"
+	parseAppInstanceConfig(config, getconfigCtx)
+	assert.Equal(t, "appA-modified", getAppInstance(uuidA).DisplayName)
+	assert.Equal(t, uint32(1), getAppInstance(uuidA).LocalRestartCmd.Counter)
+
+	// Unknown app instance
+	restarts = append(restarts, &profile.LocalAppRestart{
+		AppId: "6ba7b812-9dad-11d1-80b4-00c04fd430c8", Counter: 1})
+	processLocalAppRestarts(getconfigCtx, restarts)
+	assert.Equal(t, 3, len(getconfigCtx.localRestartAudit))
+	assert.False(t, getconfigCtx.localRestartAudit[2].Accepted)
+
+	// The audit list is bounded
+	for i := 0; i < maxLocalRestartAuditEntries; i++ {
+		restarts[0].Counter++
+		processLocalAppRestarts(getconfigCtx, restarts)
+	}
+	assert.Equal(t, maxLocalRestartAuditEntries,
+		len(getconfigCtx.localRestartAudit))
+	last := getconfigCtx.localRestartAudit[maxLocalRestartAuditEntries-1]
+	assert.Equal(t, restarts[0].Counter, last.Counter)
+
+	// The audit list is published in ZedAgentStatus
+	status := getZedAgentStatus(t, getconfigCtx)
+	assert.Equal(t, maxLocalRestartAuditEntries,
+		len(status.LocalRestartAudit))
+	assert.Equal(t, last.Counter,
+		status.LocalRestartAudit[maxLocalRestartAuditEntries-1].Counter)
+}
+
+func TestTriggerLocalAppRestarts(t *testing.T) {
+	getconfigCtx := &getconfigContext{
+		localAppRestarts: make(chan []*profile.LocalAppRestart, 1),
+	}
+	appID := uuid.NewV4().String()
+	older := []*profile.LocalAppRestart{{AppId: appID, Counter: 1}}
+	newer := []*profile.LocalAppRestart{{AppId: appID, Counter: 2}}
+
+	// Does not block and only the latest restarts are kept
+	triggerLocalAppRestarts(getconfigCtx, older)
+	triggerLocalAppRestarts(getconfigCtx, newer)
+	restarts := <-getconfigCtx.localAppRestarts
+	assert.Equal(t, uint32(2), restarts[0].Counter)
+	assert.Empty(t, getconfigCtx.localAppRestarts)
+}
+
+func TestPublishDatastoreConfigType(t *testing.T) {
+	testMatrix := map[string]struct {
+		dsType        zconfig.DsType
+		expectedError bool
+	}{
+		"HTTP": {
+			dsType: zconfig.DsType_DsHttp,
+		},
+		"SFTP": {
+			dsType: zconfig.DsType_DsSFTP,
+		},
+		"Container registry": {
+			dsType: zconfig.DsType_DsContainerRegistry,
+		},
+		"Unknown": {
+			dsType:        zconfig.DsType_DsUnknown,
+			expectedError: true,
+		},
+		"Unsupported": {
+			dsType:        zconfig.DsType(42),
+			expectedError: true,
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	getconfigCtx.zedagentCtx = &zedagentContext{
+		globalConfig: *types.DefaultConfigItemValueMap(),
+	}
+	dsID := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		publishDatastoreConfig(getconfigCtx, []*zconfig.DatastoreConfig{
+			{
+				Id:    dsID,
+				DType: test.dsType,
+			},
+		})
+		c, err := getconfigCtx.pubDatastoreConfig.Get(dsID)
+		assert.Nil(t, err, testname)
+		datastore := c.(types.DatastoreConfig)
+		assert.Equal(t, test.expectedError, datastore.ConfigError.HasError(),
+			testname)
+		assert.Equal(t, test.dsType.String(), datastore.DsType, testname)
+	}
+}
+
+func TestPublishDatastoreConfigUnchanged(t *testing.T) {
+	getconfigCtx := initGetConfigCtx(t)
+	getconfigCtx.zedagentCtx = &zedagentContext{
+		globalConfig: *types.DefaultConfigItemValueMap(),
+	}
+	goodID := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	badID := "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	getDatastore := func(id string) types.DatastoreConfig {
+		c, err := getconfigCtx.pubDatastoreConfig.Get(id)
+		assert.Nil(t, err, id)
+		return c.(types.DatastoreConfig)
+	}
+	datastores := []*zconfig.DatastoreConfig{
+		{Id: goodID, DType: zconfig.DsType_DsHttps, Fqdn: "https://a.com"},
+		{Id: badID, DType: zconfig.DsType_DsUnknown},
+	}
+
+	publishDatastoreConfig(getconfigCtx, datastores)
+	bad := getDatastore(badID)
+	assert.True(t, bad.ConfigError.HasError())
+
+	// The unchanged error keeps its time
+	time.Sleep(time.Millisecond)
+	publishDatastoreConfig(getconfigCtx, datastores)
+	assert.Equal(t, bad, getDatastore(badID))
+
+	// A change is published and a removal unpublished
+	publishDatastoreConfig(getconfigCtx, []*zconfig.DatastoreConfig{
+		{Id: goodID, DType: zconfig.DsType_DsHttps, Fqdn: "https://b.com"},
+	})
+	assert.Equal(t, "https://b.com", getDatastore(goodID).Fqdn)
+	_, err := getconfigCtx.pubDatastoreConfig.Get(badID)
+	assert.NotNil(t, err)
+}
+
+func TestPublishDatastoreConfigRegistryCache(t *testing.T) {
+	cipherBlock := func(ctxID string) *zconfig.CipherBlock {
+		return &zconfig.CipherBlock{
+			CipherContextId: ctxID,
+			CipherData:      []byte("encrypted"),
+		}
+	}
+	testMatrix := map[string]struct {
+		fqdn          string
+		upstreamFqdn  string
+		expectedError string
+	}{
+		"Cache and upstream": {
+			fqdn:         "docker://cache.local:5000",
+			upstreamFqdn: "docker://docker.io",
+		},
+		"OCI scheme": {
+			fqdn:         "oci://cache.local",
+			upstreamFqdn: "oci://registry.example.com",
+		},
+		"No cache": {
+			upstreamFqdn:  "docker://docker.io",
+			expectedError: "cache registry not specified",
+		},
+		"No upstream": {
+			fqdn:          "docker://cache.local:5000",
+			expectedError: "upstream registry not specified",
+		},
+		"Bad scheme": {
+			fqdn:          "https://cache.local",
+			upstreamFqdn:  "docker://docker.io",
+			expectedError: "unknown OCI registry scheme",
+		},
+		"No host": {
+			fqdn:          "docker://cache.local",
+			upstreamFqdn:  "docker:///library",
+			expectedError: "upstream registry docker:///library without a host",
+		},
+		"Same endpoint": {
+			fqdn:          "docker://docker.io",
+			upstreamFqdn:  "docker://docker.io",
+			expectedError: "cache and upstream are both docker://docker.io",
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	getconfigCtx.zedagentCtx = &zedagentContext{
+		globalConfig: *types.DefaultConfigItemValueMap(),
+	}
+	dsID := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	sha := "DE78803598BC4C940FC4591D412BFFE488205D5D953F94751C6308DEEAAA7EB8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		publishDatastoreConfig(getconfigCtx, []*zconfig.DatastoreConfig{
+			{
+				Id:                 dsID,
+				DType:              zconfig.DsType_DsContainerRegistryCache,
+				Fqdn:               test.fqdn,
+				UpstreamFqdn:       test.upstreamFqdn,
+				UpstreamDpath:      "library",
+				CipherData:         cipherBlock("cache"),
+				UpstreamCipherData: cipherBlock("upstream"),
+			},
+		})
+		c, err := getconfigCtx.pubDatastoreConfig.Get(dsID)
+		assert.Nil(t, err, testname)
+		datastore := c.(types.DatastoreConfig)
+		if test.expectedError != "" {
+			assert.True(t, datastore.ConfigError.HasError(), testname)
+			assert.Contains(t, datastore.ConfigError.Error, test.expectedError,
+				testname)
+			continue
+		}
+		assert.False(t, datastore.ConfigError.HasError(), testname)
+		assert.Equal(t, test.upstreamFqdn, datastore.UpstreamFqdn, testname)
+		assert.Equal(t, "library", datastore.UpstreamDpath, testname)
+		assert.Equal(t, "cache", datastore.CipherContextID, testname)
+		assert.True(t, datastore.IsCipher, testname)
+		assert.Equal(t, "upstream",
+			datastore.UpstreamCipherBlockStatus.CipherContextID, testname)
+		assert.True(t, datastore.UpstreamCipherBlockStatus.IsCipher, testname)
+
+		// A latched reference is valid against either endpoint
+		name := utils.MaybeInsertSha("alpine:3.10", sha)
+		expected := "alpine@sha256:" + strings.ToLower(sha)
+		assert.Equal(t, test.fqdn+"/"+expected,
+			datastore.DownloadURL(name), testname)
+		assert.Equal(t, test.upstreamFqdn+"/library/"+expected,
+			datastore.UpstreamDownloadURL(name), testname)
+	}
+}
+
+func TestValidateDrive(t *testing.T) {
+	// The accepted pairs with their readonly and maxsizebytes rules
+	type attrs struct {
+		readonly     driveAttrRule
+		maxsizebytes driveAttrRule
+	}
+	accepted := map[string]attrs{
+		"TgtUnknown/Unclassified": {maxsizebytes: driveAttrForbidden},
+		"TgtUnknown/HDD":          {},
+		"TgtUnknown/HDD_EMPTY":    {readonly: driveAttrForbidden, maxsizebytes: driveAttrRequired},
+		"TgtUnknown/CDROM":        {readonly: driveAttrRequired, maxsizebytes: driveAttrForbidden},
+		"TgtUnknown/NET":          {maxsizebytes: driveAttrForbidden},
+		"Disk/Unclassified":       {maxsizebytes: driveAttrForbidden},
+		"Disk/HDD":                {},
+		"Disk/HDD_EMPTY":          {readonly: driveAttrForbidden, maxsizebytes: driveAttrRequired},
+		"Disk/CDROM":              {readonly: driveAttrRequired, maxsizebytes: driveAttrForbidden},
+		"Disk/NET":                {maxsizebytes: driveAttrForbidden},
+		"Kernel/Unclassified":     {maxsizebytes: driveAttrForbidden},
+		"Initrd/Unclassified":     {maxsizebytes: driveAttrForbidden},
+		"RamDisk/Unclassified":    {maxsizebytes: driveAttrForbidden},
+	}
+	allowed := func(rule driveAttrRule, set bool) bool {
+		switch rule {
+		case driveAttrRequired:
+			return set
+		case driveAttrForbidden:
+			return !set
+		}
+		return true
+	}
+
+	for target := range zconfig.Target_name {
+		for drvtype := range zconfig.DriveType_name {
+			for _, readonly := range []bool{false, true} {
+				for _, maxsize := range []int64{0, 1024} {
+					drive := &zconfig.Drive{
+						Target:       zconfig.Target(target),
+						Drvtype:      zconfig.DriveType(drvtype),
+						Readonly:     readonly,
+						Maxsizebytes: maxsize,
+					}
+					testname := fmt.Sprintf("%s/%s readonly %t maxsizebytes %d",
+						drive.Target, drive.Drvtype, readonly, maxsize)
+					t.Logf("Running test case %s", testname)
+					a, ok := accepted[fmt.Sprintf("%s/%s",
+						drive.Target, drive.Drvtype)]
+					expectOK := ok && allowed(a.readonly, readonly) &&
+						allowed(a.maxsizebytes, maxsize != 0)
+					err := validateDrive(drive)
+					assert.Equal(t, expectOK, err == nil, testname)
+				}
+			}
+		}
+	}
+
+	// Unknown enum values and negative sizes are rejected
+	assert.NotNil(t, validateDrive(&zconfig.Drive{
+		Target: zconfig.Target(42), Drvtype: zconfig.DriveType_HDD}))
+	assert.NotNil(t, validateDrive(&zconfig.Drive{
+		Target: zconfig.Target_Disk, Drvtype: zconfig.DriveType(42)}))
+	assert.NotNil(t, validateDrive(&zconfig.Drive{
+		Target: zconfig.Target_Disk, Drvtype: zconfig.DriveType_HDD,
+		Maxsizebytes: -1}))
+}
+
+func TestParseBaseOsConfigDrives(t *testing.T) {
+	testMatrix := map[string]struct {
+		drive         *zconfig.Drive
+		expectedError bool
+	}{
+		"Valid drive": {
+			drive: &zconfig.Drive{
+				Target:  zconfig.Target_Disk,
+				Drvtype: zconfig.DriveType_HDD,
+			},
+		},
+		"CDROM read-write": {
+			drive: &zconfig.Drive{
+				Target:  zconfig.Target_Disk,
+				Drvtype: zconfig.DriveType_CDROM,
+			},
+			expectedError: true,
+		},
+		"Unclassified with maxsizebytes": {
+			drive: &zconfig.Drive{
+				Drvtype:      zconfig.DriveType_Unclassified,
+				Maxsizebytes: 1024,
+			},
+			expectedError: true,
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	baseOsID := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		baseOSConfigPrevConfigHash = nil
+		config := &zconfig.EdgeDevConfig{
+			Base: []*zconfig.BaseOSConfig{
+				{
+					Uuidandversion: &zconfig.UUIDandVersion{
+						Uuid:    baseOsID,
+						Version: "1",
+					},
+					BaseOSVersion: "6.0.0",
+					Drives:        []*zconfig.Drive{test.drive},
+				},
+			},
+		}
+		parseBaseOsConfig(getconfigCtx, config)
+		c, err := getconfigCtx.pubBaseOsConfig.Get(baseOsID)
+		assert.Nil(t, err, testname)
+		baseOs := c.(types.BaseOsConfig)
+		assert.Equal(t, test.expectedError, baseOs.HasError(), testname)
+		assert.Equal(t, 1, len(baseOs.ContentTreeConfigList), testname)
+	}
+}
+
+func TestParseBaseOsConfigCanary(t *testing.T) {
+	const baseOsID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	const appID = "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	testMatrix := map[string]struct {
+		canary         string
+		expectedCanary string
+		expectedError  bool
+	}{
+		"No canary": {
+			expectedCanary: nilUUID.String(),
+		},
+		"Canary app in config": {
+			canary:         appID,
+			expectedCanary: appID,
+		},
+		"Canary app not in config": {
+			canary:         "6ba7b812-9dad-11d1-80b4-00c04fd430c8",
+			expectedCanary: nilUUID.String(),
+			expectedError:  true,
+		},
+		"Malformed canary": {
+			canary:         "canary",
+			expectedCanary: nilUUID.String(),
+			expectedError:  true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		baseOSConfigPrevConfigHash = nil
+		config := &zconfig.EdgeDevConfig{
+			Base: []*zconfig.BaseOSConfig{
+				{
+					Uuidandversion: &zconfig.UUIDandVersion{
+						Uuid:    baseOsID,
+						Version: "1",
+					},
+					BaseOSVersion: "6.0.0",
+					Activate:      true,
+					CanaryAppUUID: test.canary,
+				},
+			},
+			Apps: []*zconfig.AppInstanceConfig{
+				{Uuidandversion: &zconfig.UUIDandVersion{Uuid: appID,
+					Version: "1"}},
+			},
+		}
+		parseBaseOsConfig(getconfigCtx, config)
+		c, err := getconfigCtx.pubBaseOsConfig.Get(baseOsID)
+		assert.Nil(t, err, testname)
+		baseOs := c.(types.BaseOsConfig)
+		assert.Equal(t, test.expectedError, baseOs.HasError(), testname)
+		assert.Equal(t, test.expectedCanary, baseOs.CanaryAppUUID.String(),
+			testname)
+		assert.Equal(t, test.expectedCanary,
+			getCanaryAppUUID(getconfigCtx).String(), testname)
+	}
+}
+
+// modifyAppInstanceStatus makes the AppInstanceStatus appear as if
+// published by zedmanager
+func modifyAppInstanceStatus(t *testing.T, getconfigCtx *getconfigContext,
+	status types.AppInstanceStatus) {
+
+	b, err := json.Marshal(status)
+	assert.Nil(t, err)
+	getconfigCtx.subAppInstanceStatus.ProcessChange(pubsub.Change{
+		Operation: pubsub.Modify, Key: status.Key(), Value: b})
+}
+
+func TestUpdateCanaryVerdict(t *testing.T) {
+	const baseOsID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	appID, _ := uuid.FromString("6ba7b811-9dad-11d1-80b4-00c04fd430c8")
+	testMatrix := map[string]struct {
+		updateInprogress bool
+		states           []types.SwState
+		errorState       bool
+		expectedVerdict  types.CanaryVerdict
+	}{
+		"No update": {
+			states:          []types.SwState{types.RUNNING},
+			expectedVerdict: types.CanaryVerdictNone,
+		},
+		"Healthy": {
+			updateInprogress: true,
+			states:           []types.SwState{types.BOOTING, types.RUNNING},
+			expectedVerdict:  types.CanaryVerdictHealthy,
+		},
+		"Booting": {
+			updateInprogress: true,
+			states:           []types.SwState{types.BOOTING},
+			expectedVerdict:  types.CanaryVerdictPending,
+		},
+		"Halted": {
+			updateInprogress: true,
+			states:           []types.SwState{types.RUNNING, types.HALTED},
+			expectedVerdict:  types.CanaryVerdictUnhealthy,
+		},
+		"Error": {
+			updateInprogress: true,
+			states:           []types.SwState{types.RUNNING},
+			errorState:       true,
+			expectedVerdict:  types.CanaryVerdictUnhealthy,
+		},
+		"Unhealthy then running": {
+			updateInprogress: true,
+			states: []types.SwState{types.RUNNING, types.BROKEN,
+				types.RUNNING},
+			expectedVerdict: types.CanaryVerdictUnhealthy,
+		},
+		"Missing app": {
+			updateInprogress: true,
+			expectedVerdict:  types.CanaryVerdictMissing,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		getconfigCtx.updateInprogress = test.updateInprogress
+		baseOsConfig := types.BaseOsConfig{
+			UUIDandVersion: types.UUIDandVersion{
+				UUID: uuid.FromStringOrNil(baseOsID), Version: "1"},
+			BaseOsVersion: "6.0.0",
+			Activate:      true,
+			CanaryAppUUID: appID,
+		}
+		getconfigCtx.pubBaseOsConfig.Publish(baseOsConfig.Key(), baseOsConfig)
+
+		for _, state := range test.states {
+			status := types.AppInstanceStatus{
+				UUIDandVersion: types.UUIDandVersion{UUID: appID},
+				DisplayName:    "canary",
+				State:          state,
+			}
+			modifyAppInstanceStatus(t, getconfigCtx, status)
+			updateCanaryVerdict(getconfigCtx)
+		}
+		if test.errorState {
+			status := types.AppInstanceStatus{
+				UUIDandVersion: types.UUIDandVersion{UUID: appID},
+				DisplayName:    "canary",
+				State:          types.RUNNING,
+			}
+			status.SetError("boot failed", time.Now())
+			modifyAppInstanceStatus(t, getconfigCtx, status)
+		}
+		updateCanaryVerdict(getconfigCtx)
+
+		c, err := getconfigCtx.pubZedAgentStatus.Get(agentName)
+		if test.expectedVerdict == types.CanaryVerdictNone {
+			// Nothing to report
+			assert.NotNil(t, err, testname)
+			continue
+		}
+		assert.Nil(t, err, testname)
+		status := c.(types.ZedAgentStatus)
+		assert.Equal(t, test.expectedVerdict, status.CanaryVerdict, testname)
+		assert.Equal(t, appID, status.CanaryAppUUID, testname)
+
+		// The verdict is reset when the test completes
+		getconfigCtx.updateInprogress = false
+		updateCanaryVerdict(getconfigCtx)
+		c, _ = getconfigCtx.pubZedAgentStatus.Get(agentName)
+		status = c.(types.ZedAgentStatus)
+		assert.Equal(t, types.CanaryVerdictNone, status.CanaryVerdict,
+			testname)
+		assert.Equal(t, nilUUID, status.CanaryAppUUID, testname)
+	}
+}
+
+func TestParseAppInstanceConfigVnc(t *testing.T) {
+	testMatrix := map[string]struct {
+		enableVnc       bool
+		vncDisplay      uint32
+		vncPasswd       string
+		requirePassword bool
+		displayMin      uint32
+		displayMax      uint32
+		expectedError   bool
+	}{
+		"VNC disabled": {
+			vncDisplay:      100000,
+			requirePassword: true,
+		},
+		"VNC enabled": {
+			enableVnc:  true,
+			vncDisplay: 1,
+		},
+		"Highest display": {
+			enableVnc:  true,
+			vncDisplay: maxVncDisplay,
+		},
+		"Display out of range": {
+			enableVnc:     true,
+			vncDisplay:    maxVncDisplay + 1,
+			expectedError: true,
+		},
+		"No password and not required": {
+			enableVnc: true,
+		},
+		"No password but required": {
+			enableVnc:       true,
+			requirePassword: true,
+			expectedError:   true,
+		},
+		"Password and required": {
+			enableVnc:       true,
+			vncPasswd:       "secret",
+			requirePassword: true,
+		},
+		"Display inside configured range": {
+			enableVnc:  true,
+			vncDisplay: 20,
+			displayMin: 10,
+			displayMax: 20,
+		},
+		"Display below configured range": {
+			enableVnc:     true,
+			vncDisplay:    9,
+			displayMin:    10,
+			displayMax:    20,
+			expectedError: true,
+		},
+		"Display above configured range": {
+			enableVnc:     true,
+			vncDisplay:    21,
+			displayMin:    10,
+			displayMax:    20,
+			expectedError: true,
+		},
+		"VNC disabled outside configured range": {
+			vncDisplay: 1,
+			displayMin: 10,
+			displayMax: 20,
+		},
+	}
+
+	uuidA := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		getconfigCtx.zedagentCtx = &zedagentContext{
+			globalConfig: *types.DefaultConfigItemValueMap(),
+		}
+		getconfigCtx.zedagentCtx.globalConfig.SetGlobalValueBool(
+			types.AppVncRequirePassword, test.requirePassword)
+		if test.displayMax != 0 {
+			getconfigCtx.zedagentCtx.globalConfig.SetGlobalValueInt(
+				types.AppVncDisplayMin, test.displayMin)
+			getconfigCtx.zedagentCtx.globalConfig.SetGlobalValueInt(
+				types.AppVncDisplayMax, test.displayMax)
+		}
+		appinstancePrevConfigHash = nil
+		appinstancePrevElementHash = make(map[string][]byte)
+
+		app := newTestAppInstance(uuidA, "appA")
+		app.Fixedresources.EnableVnc = test.enableVnc
+		app.Fixedresources.VncDisplay = test.vncDisplay
+		app.Fixedresources.VncPasswd = test.vncPasswd
+		parseAppInstanceConfig(&zconfig.EdgeDevConfig{
+			Apps: []*zconfig.AppInstanceConfig{app},
+		}, getconfigCtx)
+		c, err := getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+		assert.Nil(t, err, testname)
+		appInstance := c.(types.AppInstanceConfig)
+		assert.Equal(t, test.expectedError, len(appInstance.Errors) != 0,
+			testname)
+	}
+}
+
+func TestParseAppInstanceConfigVncConflicts(t *testing.T) {
+	uuidA := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	uuidB := "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	uuidC := "6ba7b812-9dad-11d1-80b4-00c04fd430c8"
+	testMatrix := map[string]struct {
+		enableVncB     bool
+		displayB       uint32
+		expectedErrors map[string]bool
+	}{
+		"Same display": {
+			enableVncB: true,
+			displayB:   1,
+			expectedErrors: map[string]bool{
+				uuidA: true, uuidB: true, uuidC: false},
+		},
+		"Different displays": {
+			enableVncB: true,
+			displayB:   3,
+			expectedErrors: map[string]bool{
+				uuidA: false, uuidB: false, uuidC: false},
+		},
+		"Same display without VNC": {
+			displayB: 1,
+			expectedErrors: map[string]bool{
+				uuidA: false, uuidB: false, uuidC: false},
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		appinstancePrevConfigHash = nil
+		appinstancePrevElementHash = make(map[string][]byte)
+
+		appA := newTestAppInstance(uuidA, "appA")
+		appA.Fixedresources.EnableVnc = true
+		appA.Fixedresources.VncDisplay = 1
+		appB := newTestAppInstance(uuidB, "appB")
+		appB.Fixedresources.EnableVnc = test.enableVncB
+		appB.Fixedresources.VncDisplay = test.displayB
+		appC := newTestAppInstance(uuidC, "appC")
+		appC.Fixedresources.EnableVnc = true
+		appC.Fixedresources.VncDisplay = 2
+		parseAppInstanceConfig(&zconfig.EdgeDevConfig{
+			Apps: []*zconfig.AppInstanceConfig{appA, appB, appC},
+		}, getconfigCtx)
+		for uuidStr, expectedError := range test.expectedErrors {
+			c, err := getconfigCtx.pubAppInstanceConfig.Get(uuidStr)
+			assert.Nil(t, err, testname)
+			appInstance := c.(types.AppInstanceConfig)
+			assert.Equal(t, expectedError, len(appInstance.Errors) != 0,
+				"%s: %s", testname, uuidStr)
+			for _, appErr := range appInstance.Errors {
+				assert.Equal(t, types.AppConfigErrorBadVnc, appErr.Category,
+					testname)
+			}
+		}
+	}
+}
+
+func TestPublishNetworkInstanceConfigVPN(t *testing.T) {
+	newNetworkInstance := func(uuidStr string, instType zconfig.ZNetworkInstType,
+		vpn bool) *zconfig.NetworkInstanceConfig {
+		ni := &zconfig.NetworkInstanceConfig{
+			Uuidandversion: &zconfig.UUIDandVersion{
+				Uuid:    uuidStr,
+				Version: "1",
+			},
+			Displayname: uuidStr,
+			InstType:    instType,
+			IpType:      zconfig.AddressType_IPV4,
+			Ip:          &zconfig.Ipspec{},
+		}
+		if vpn {
+			ni.Cfg = &zconfig.NetworkInstanceOpaqueConfig{
+				Oconfig: "{}",
+				Type:    zconfig.ZNetworkOpaqueConfigType_ZNetOConfigVPN,
+			}
+		}
+		return ni
+	}
+	uuidLocal := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	uuidVPN1 := "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	uuidVPN2 := "6ba7b812-9dad-11d1-80b4-00c04fd430c8"
+	getconfigCtx := initGetConfigCtx(t)
+	getError := func(uuidStr string) string {
+		c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+		assert.Nil(t, err, uuidStr)
+		return c.(types.NetworkInstanceConfig).Error
+	}
+
+	// All are published and only the second VPN instance has an error
+	publishNetworkInstanceConfig(getconfigCtx, []*zconfig.NetworkInstanceConfig{
+		newNetworkInstance(uuidLocal, zconfig.ZNetworkInstType_ZnetInstLocal, false),
+		newNetworkInstance(uuidVPN1, zconfig.ZNetworkInstType_ZnetInstCloud, true),
+		newNetworkInstance(uuidVPN2, zconfig.ZNetworkInstType_ZnetInstCloud, true),
+	})
+	assert.Equal(t, 3, len(getconfigCtx.pubNetworkInstanceConfig.GetAll()))
+	assert.Equal(t, "", getError(uuidLocal))
+	assert.Equal(t, "", getError(uuidVPN1))
+	assert.Contains(t, getError(uuidVPN2),
+		"only one VPN instance supported, conflicting with "+uuidVPN1)
+
+	// Removing the first VPN instance clears the error on the second one
+	publishNetworkInstanceConfig(getconfigCtx, []*zconfig.NetworkInstanceConfig{
+		newNetworkInstance(uuidLocal, zconfig.ZNetworkInstType_ZnetInstLocal, false),
+		newNetworkInstance(uuidVPN2, zconfig.ZNetworkInstType_ZnetInstCloud, true),
+	})
+	assert.Equal(t, 2, len(getconfigCtx.pubNetworkInstanceConfig.GetAll()))
+	assert.Equal(t, "", getError(uuidLocal))
+	assert.Equal(t, "", getError(uuidVPN2))
+}
+
+func TestPreviewConfigItems(t *testing.T) {
+	testMatrix := map[string]struct {
+		items            []*zconfig.ConfigItem
+		expectedError    bool
+		expectedInterval uint32
+	}{
+		"No items": {
+			expectedInterval: types.DefaultConfigItemValueMap().GlobalValueInt(
+				types.ConfigInterval),
+		},
+		"Valid item": {
+			items: []*zconfig.ConfigItem{
+				{Key: string(types.ConfigInterval), Value: "120"},
+			},
+			expectedInterval: 120,
+		},
+		"Invalid item retains the current value": {
+			items: []*zconfig.ConfigItem{
+				{Key: string(types.ConfigInterval), Value: "abc"},
+			},
+			expectedError:    true,
+			expectedInterval: 300,
+		},
+	}
+
+	specMap := types.NewConfigItemSpecMap()
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		curGlobalConfig := types.DefaultConfigItemValueMap()
+		curGlobalConfig.SetGlobalValueInt(types.ConfigInterval, 300)
+		savedGlobalConfig := types.DefaultConfigItemValueMap()
+		savedGlobalConfig.SetGlobalValueInt(types.ConfigInterval, 300)
+
+		newGlobalConfig, newGlobalStatus, err := previewConfigItems(
+			&specMap, curGlobalConfig, test.items)
+		assert.Equal(t, test.expectedError, err != nil, testname)
+		assert.Equal(t, test.expectedInterval,
+			newGlobalConfig.GlobalValueInt(types.ConfigInterval), testname)
+		assert.False(t, newGlobalConfig.GlobalValueBool(types.UsbAccess),
+			testname)
+		// UsbAccess differs from the default so it is always included
+		assert.Equal(t, len(test.items)+1, len(newGlobalStatus.ConfigItems),
+			testname)
+		for _, item := range test.items {
+			itemStatus := newGlobalStatus.ConfigItems[item.Key]
+			assert.Equal(t, test.expectedError, itemStatus.Err != nil,
+				testname)
+		}
+		// No side effects on the current config
+		assert.Equal(t, *savedGlobalConfig, *curGlobalConfig, testname)
+	}
+}
+
+func TestPublishNetworkInstanceConfigDuplicates(t *testing.T) {
+	uuidA := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	uuidB := "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	newNetworkInstance := func(uuidStr string, name string) *zconfig.NetworkInstanceConfig {
+		return &zconfig.NetworkInstanceConfig{
+			Uuidandversion: &zconfig.UUIDandVersion{
+				Uuid:    uuidStr,
+				Version: "1",
+			},
+			Displayname: name,
+			InstType:    zconfig.ZNetworkInstType_ZnetInstLocal,
+			IpType:      zconfig.AddressType_IPV4,
+			Ip:          &zconfig.Ipspec{},
+		}
+	}
+	getconfigCtx := initGetConfigCtx(t)
+	publishNetworkInstanceConfig(getconfigCtx, []*zconfig.NetworkInstanceConfig{
+		newNetworkInstance(uuidA, "first"),
+		newNetworkInstance(uuidB, "other"),
+		newNetworkInstance(uuidA, "second"),
+	})
+	assert.Equal(t, 2, len(getconfigCtx.pubNetworkInstanceConfig.GetAll()))
+	c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidA)
+	assert.Nil(t, err)
+	config := c.(types.NetworkInstanceConfig)
+	assert.Equal(t, "first", config.DisplayName)
+	assert.True(t, config.HasError())
+	c, err = getconfigCtx.pubNetworkInstanceConfig.Get(uuidB)
+	assert.Nil(t, err)
+	config = c.(types.NetworkInstanceConfig)
+	assert.False(t, config.HasError())
+
+	// Cleared when the duplicate is removed
+	publishNetworkInstanceConfig(getconfigCtx, []*zconfig.NetworkInstanceConfig{
+		newNetworkInstance(uuidA, "second"),
+		newNetworkInstance(uuidB, "other"),
+	})
+	c, err = getconfigCtx.pubNetworkInstanceConfig.Get(uuidA)
+	assert.Nil(t, err)
+	config = c.(types.NetworkInstanceConfig)
+	assert.Equal(t, "second", config.DisplayName)
+	assert.False(t, config.HasError())
+
+	// Reported along with the other errors of the first entry
+	first := newNetworkInstance(uuidA, "first")
+	first.Mtu = 100
+	publishNetworkInstanceConfig(getconfigCtx, []*zconfig.NetworkInstanceConfig{
+		first,
+		newNetworkInstance(uuidA, "second"),
+	})
+	c, err = getconfigCtx.pubNetworkInstanceConfig.Get(uuidA)
+	assert.Nil(t, err)
+	config = c.(types.NetworkInstanceConfig)
+	assert.Contains(t, config.Error, "MTU")
+	assert.Contains(t, config.Error, "duplicate entries")
+}
+
+func TestPublishNetworkXObjectConfigDuplicates(t *testing.T) {
+	netID := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	testMatrix := map[string]struct {
+		networks      []*zconfig.NetworkConfig
+		expectedError bool
+	}{
+		"Single": {
+			networks: []*zconfig.NetworkConfig{
+				{Id: netID, Type: zconfig.NetworkType_NETWORKTYPENOOP},
+			},
+		},
+		"Identical repeat": {
+			networks: []*zconfig.NetworkConfig{
+				{Id: netID, Type: zconfig.NetworkType_NETWORKTYPENOOP},
+				{Id: netID, Type: zconfig.NetworkType_NETWORKTYPENOOP},
+			},
+		},
+		"Conflicting repeat": {
+			networks: []*zconfig.NetworkConfig{
+				{Id: netID, Type: zconfig.NetworkType_NETWORKTYPENOOP},
+				{Id: netID, Type: zconfig.NetworkType_V4},
+			},
+			expectedError: true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		publishNetworkXObjectConfig(getconfigCtx, test.networks)
+		assert.Equal(t, 1, len(getconfigCtx.pubNetworkXObjectConfig.GetAll()),
+			testname)
+		c, err := getconfigCtx.pubNetworkXObjectConfig.Get(netID)
+		assert.Nil(t, err, testname)
+		config := c.(types.NetworkXObjectConfig)
+		assert.Equal(t, test.expectedError, config.HasError(), testname)
+		assert.Equal(t, types.NT_NOOP, config.Type, testname)
+	}
+
+	// Reported along with the parse error of the first entry
+	getconfigCtx := initGetConfigCtx(t)
+	publishNetworkXObjectConfig(getconfigCtx, []*zconfig.NetworkConfig{
+		{Id: netID, Type: zconfig.NetworkType_V4},
+		{Id: netID, Type: zconfig.NetworkType_NETWORKTYPENOOP},
+	})
+	c, err := getconfigCtx.pubNetworkXObjectConfig.Get(netID)
+	assert.Nil(t, err)
+	config := c.(types.NetworkXObjectConfig)
+	assert.Contains(t, config.Error, "Missing ipspec")
+	assert.Contains(t, config.Error, "conflicting duplicate entries")
+}
+
+func TestPreviewConfigItemsEffectiveValue(t *testing.T) {
+	specMap := types.NewConfigItemSpecMap()
+	curGlobalConfig := types.DefaultConfigItemValueMap()
+	curGlobalConfig.SetGlobalValueInt(types.ConfigInterval, 300)
+
+	// Clamped value retains the current value
+	_, newGlobalStatus, err := previewConfigItems(&specMap, curGlobalConfig,
+		[]*zconfig.ConfigItem{
+			{Key: string(types.ConfigInterval), Value: "1"},
+		})
+	assert.NotNil(t, err)
+	item := newGlobalStatus.ConfigItems[string(types.ConfigInterval)]
+	assert.NotNil(t, item.Err)
+	assert.Equal(t, "300", item.EffectiveValue)
+
+	// Omitted item with the default value is not included
+	_, ok := newGlobalStatus.ConfigItems[string(types.MetricInterval)]
+	assert.False(t, ok)
+
+	// UsbAccess defaults to true before we have a config from the
+	// controller
+	var globalStatus types.GlobalStatus
+	globalStatus.ConfigItems = make(map[string]types.ConfigItemStatus)
+	globalStatus.UpdateItemValuesFromGlobalConfig(
+		*types.DefaultConfigItemValueMap())
+	item = globalStatus.ConfigItems[string(types.UsbAccess)]
+	assert.Equal(t, "true", item.EffectiveValue)
+
+	// Omitted by the controller it is false, which is reported even
+	// though it was not sent
+	_, newGlobalStatus, err = previewConfigItems(&specMap, curGlobalConfig, nil)
+	assert.Nil(t, err)
+	item, ok = newGlobalStatus.ConfigItems[string(types.UsbAccess)]
+	assert.True(t, ok)
+	assert.Equal(t, "", item.Value)
+	assert.Equal(t, "false", item.EffectiveValue)
+
+	// Set by the controller
+	_, newGlobalStatus, err = previewConfigItems(&specMap, curGlobalConfig,
+		[]*zconfig.ConfigItem{
+			{Key: string(types.UsbAccess), Value: "true"},
+		})
+	assert.Nil(t, err)
+	item = newGlobalStatus.ConfigItems[string(types.UsbAccess)]
+	assert.Equal(t, "true", item.Value)
+	assert.Equal(t, "true", item.EffectiveValue)
+}
+
+func TestParseIpspecDefaultDhcpRange(t *testing.T) {
+	testMatrix := map[string]struct {
+		instType      types.NetworkInstanceType
+		subnet        string
+		gateway       string
+		expectedStart string
+		expectedEnd   string
+		expectedError bool
+	}{
+		"/24 with first gateway": {
+			instType:      types.NetworkInstanceTypeLocal,
+			subnet:        "10.1.0.0/24",
+			gateway:       "10.1.0.1",
+			expectedStart: "10.1.0.2",
+			expectedEnd:   "10.1.0.127",
+		},
+		"/24 without gateway": {
+			instType:      types.NetworkInstanceTypeLocal,
+			subnet:        "10.1.0.0/24",
+			expectedStart: "10.1.0.2",
+			expectedEnd:   "10.1.0.127",
+		},
+		"/24 with gateway in the lower half": {
+			instType:      types.NetworkInstanceTypeLocal,
+			subnet:        "10.1.0.0/24",
+			gateway:       "10.1.0.10",
+			expectedStart: "10.1.0.128",
+			expectedEnd:   "10.1.0.254",
+		},
+		"/29": {
+			instType:      types.NetworkInstanceTypeLocal,
+			subnet:        "10.1.0.8/29",
+			gateway:       "10.1.0.9",
+			expectedStart: "10.1.0.10",
+			expectedEnd:   "10.1.0.11",
+		},
+		"/30": {
+			instType:      types.NetworkInstanceTypeLocal,
+			subnet:        "10.1.0.0/30",
+			gateway:       "10.1.0.1",
+			expectedError: true,
+		},
+		"/31": {
+			instType:      types.NetworkInstanceTypeLocal,
+			subnet:        "10.1.0.0/31",
+			expectedError: true,
+		},
+		"No subnet": {
+			instType: types.NetworkInstanceTypeLocal,
+		},
+		"IPv6 subnet": {
+			instType: types.NetworkInstanceTypeLocal,
+			subnet:   "fd00::/64",
+		},
+		"Cloud instance": {
+			instType: types.NetworkInstanceTypeCloud,
+			subnet:   "10.1.0.0/24",
+			gateway:  "10.1.0.1",
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		config := types.NetworkInstanceConfig{Type: test.instType}
+		ipspec := &zconfig.Ipspec{
+			Subnet:    test.subnet,
+			Gateway:   test.gateway,
+			DhcpRange: &zconfig.IpRange{},
+		}
+		err := parseIpspec(ipspec, &config)
+		assert.Equal(t, test.expectedError, err != nil, testname)
+		if test.expectedError {
+			continue
+		}
+		if test.expectedStart == "" {
+			assert.Nil(t, config.DhcpRange.Start, testname)
+			continue
+		}
+		assert.Equal(t, test.expectedStart, config.DhcpRange.Start.String(),
+			testname)
+		assert.Equal(t, test.expectedEnd, config.DhcpRange.End.String(),
+			testname)
+	}
+}
+
+func TestPreviewConfigItemsRejected(t *testing.T) {
+	testMatrix := map[string]struct {
+		value        string
+		expectedNote string
+	}{
+		"Within bounds": {
+			value: "120",
+		},
+		"Below the minimum": {
+			value:        "1",
+			expectedNote: `requested value "1" rejected; using "300"`,
+		},
+		"Not a number": {
+			value:        "abc",
+			expectedNote: `requested value "abc" rejected; using "300"`,
+		},
+	}
+
+	specMap := types.NewConfigItemSpecMap()
+	curGlobalConfig := types.DefaultConfigItemValueMap()
+	curGlobalConfig.SetGlobalValueInt(types.ConfigInterval, 300)
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		_, newGlobalStatus, _ := previewConfigItems(&specMap, curGlobalConfig,
+			[]*zconfig.ConfigItem{
+				{Key: string(types.ConfigInterval), Value: test.value},
+			})
+		item := newGlobalStatus.ConfigItems[string(types.ConfigInterval)]
+		assert.Equal(t, test.value, item.RequestedValue, testname)
+		assert.Equal(t, test.expectedNote, item.Note, testname)
+
+		zinfo := createConfigItemStatus(*newGlobalStatus)
+		zitem := zinfo.ConfigItems[string(types.ConfigInterval)]
+		assert.Equal(t, test.value, zitem.RequestedValue, testname)
+		assert.Equal(t, test.expectedNote, zitem.Note, testname)
+	}
+}
+
+func TestRebootRequired(t *testing.T) {
+	makeConfig := func(assigngrp string) *zconfig.EdgeDevConfig {
+		return &zconfig.EdgeDevConfig{
+			DeviceIoList: []*zconfig.PhysicalIO{
+				{
+					Phylabel:  "eth0",
+					Assigngrp: assigngrp,
+					Phyaddrs:  map[string]string{"Ifname": "eth0"},
+				},
+			},
+		}
+	}
+	getRebootRequired := func(getconfigCtx *getconfigContext) bool {
+		st, err := getconfigCtx.pubZedAgentStatus.Get(agentName)
+		if err != nil {
+			return false
+		}
+		return st.(types.ZedAgentStatus).RebootRequired
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	getconfigCtx.zedagentCtx = &zedagentContext{
+		getconfigCtx: getconfigCtx,
+		globalConfig: *types.DefaultConfigItemValueMap(),
+	}
+
+	// The first config after boot is the baseline
+	pending := updateRebootRequired(getconfigCtx, makeConfig("eth0"))
+	assert.Empty(t, pending)
+	assert.False(t, getRebootRequired(getconfigCtx))
+
+	// Same adapters in a different map/key case is not a change
+	config := makeConfig("eth0")
+	config.DeviceIoList[0].Phyaddrs = map[string]string{"ifname": "eth0"}
+	pending = updateRebootRequired(getconfigCtx, config)
+	assert.Empty(t, pending)
+
+	// Moving the adapter to a different assignment group needs a reboot
+	pending = updateRebootRequired(getconfigCtx, makeConfig("group1"))
+	assert.Equal(t, []string{"deviceIoList"}, pending)
+	assert.True(t, getRebootRequired(getconfigCtx))
+	assert.Equal(t, []string{"deviceIoList"},
+		getconfigCtx.zedagentCtx.globalStatus.RebootRequiredAspects)
+
+	// Reverting the change clears it
+	pending = updateRebootRequired(getconfigCtx, makeConfig("eth0"))
+	assert.Empty(t, pending)
+	assert.False(t, getRebootRequired(getconfigCtx))
+	assert.Empty(t, getconfigCtx.zedagentCtx.globalStatus.RebootRequiredAspects)
+
+	// After a reboot the new config is the baseline
+	pending = updateRebootRequired(getconfigCtx, makeConfig("group1"))
+	assert.Equal(t, []string{"deviceIoList"}, pending)
+	getconfigCtx = initGetConfigCtx(t)
+	getconfigCtx.zedagentCtx = &zedagentContext{
+		getconfigCtx: getconfigCtx,
+		globalConfig: *types.DefaultConfigItemValueMap(),
+	}
+	pending = updateRebootRequired(getconfigCtx, makeConfig("group1"))
+	assert.Empty(t, pending)
+	assert.False(t, getRebootRequired(getconfigCtx))
+
+	// The saved baseline is kept across a restart of zedagent, but not
+	// across a reboot
+	rebootRequiredFilename = filepath.Join(t.TempDir(), "rebootRequiredBaseline")
+	defer func() {
+		rebootRequiredFilename = types.PersistStatusDir + "/rebootRequiredBaseline"
+	}()
+	restart := func(bootID string) {
+		getconfigCtx = initGetConfigCtx(t)
+		getconfigCtx.bootID = bootID
+		getconfigCtx.zedagentCtx = &zedagentContext{
+			getconfigCtx: getconfigCtx,
+			globalConfig: *types.DefaultConfigItemValueMap(),
+		}
+	}
+	restart("boot1")
+	pending = updateRebootRequired(getconfigCtx, makeConfig("eth0"))
+	assert.Empty(t, pending)
+	restart("boot1")
+	pending = updateRebootRequired(getconfigCtx, makeConfig("group1"))
+	assert.Equal(t, []string{"deviceIoList"}, pending)
+	assert.True(t, getRebootRequired(getconfigCtx))
+	restart("boot2")
+	pending = updateRebootRequired(getconfigCtx, makeConfig("group1"))
+	assert.Empty(t, pending)
+	restart("boot2")
+	pending = updateRebootRequired(getconfigCtx, makeConfig("eth0"))
+	assert.Equal(t, []string{"deviceIoList"}, pending)
+}
+
+func TestMaybeAutoReboot(t *testing.T) {
+	testMatrix := map[string]struct {
+		pending        []string
+		autoReboot     bool
+		windowStart    uint32
+		windowEnd      uint32
+		hour           int
+		updateInprog   bool
+		expectedReboot bool
+	}{
+		"Nothing pending": {
+			autoReboot:     true,
+			windowStart:    2,
+			windowEnd:      4,
+			hour:           3,
+			expectedReboot: false,
+		},
+		"Auto reboot disabled": {
+			pending:        []string{"deviceIoList"},
+			windowStart:    2,
+			windowEnd:      4,
+			hour:           3,
+			expectedReboot: false,
+		},
+		"Inside window": {
+			pending:        []string{"deviceIoList"},
+			autoReboot:     true,
+			windowStart:    2,
+			windowEnd:      4,
+			hour:           3,
+			expectedReboot: true,
+		},
+		"Before window": {
+			pending:        []string{"deviceIoList"},
+			autoReboot:     true,
+			windowStart:    2,
+			windowEnd:      4,
+			hour:           1,
+			expectedReboot: false,
+		},
+		"At window end": {
+			pending:        []string{"deviceIoList"},
+			autoReboot:     true,
+			windowStart:    2,
+			windowEnd:      4,
+			hour:           4,
+			expectedReboot: false,
+		},
+		"Window past midnight late": {
+			pending:        []string{"deviceIoList"},
+			autoReboot:     true,
+			windowStart:    22,
+			windowEnd:      2,
+			hour:           23,
+			expectedReboot: true,
+		},
+		"Window past midnight early": {
+			pending:        []string{"deviceIoList"},
+			autoReboot:     true,
+			windowStart:    22,
+			windowEnd:      2,
+			hour:           1,
+			expectedReboot: true,
+		},
+		"Window past midnight outside": {
+			pending:        []string{"deviceIoList"},
+			autoReboot:     true,
+			windowStart:    22,
+			windowEnd:      2,
+			hour:           12,
+			expectedReboot: false,
+		},
+		"Window any time": {
+			pending:        []string{"deviceIoList"},
+			autoReboot:     true,
+			windowStart:    5,
+			windowEnd:      5,
+			hour:           17,
+			expectedReboot: true,
+		},
+		"Base OS update in progress": {
+			pending:        []string{"deviceIoList"},
+			autoReboot:     true,
+			windowStart:    2,
+			windowEnd:      4,
+			hour:           3,
+			updateInprog:   true,
+			expectedReboot: false,
+		},
+	}
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		getconfigCtx.zedagentCtx = &zedagentContext{
+			getconfigCtx: getconfigCtx,
+			globalConfig: *types.DefaultConfigItemValueMap(),
+		}
+		gc := &getconfigCtx.zedagentCtx.globalConfig
+		gc.SetGlobalValueBool(types.RebootRequiredAutoReboot, test.autoReboot)
+		gc.SetGlobalValueInt(types.RebootRequiredWindowStart, test.windowStart)
+		gc.SetGlobalValueInt(types.RebootRequiredWindowEnd, test.windowEnd)
+		getconfigCtx.rebootRequiredPending = test.pending
+		getconfigCtx.updateInprogress = test.updateInprog
+
+		now := time.Date(2021, 6, 1, test.hour, 30, 0, 0, time.UTC)
+		rebooted := maybeAutoReboot(getconfigCtx, now)
+		assert.Equal(t, test.expectedReboot, rebooted, testname)
+		assert.Equal(t, test.expectedReboot,
+			getconfigCtx.zedagentCtx.rebootCmd, testname)
+	}
+}
+
+func TestComputeConfigElementSha(t *testing.T) {
+	makeIo := func(phyaddrs map[string]string) *zconfig.PhysicalIO {
+		return &zconfig.PhysicalIO{
+			Phylabel:  "eth0",
+			Assigngrp: "eth0",
+			Phyaddrs:  phyaddrs,
+		}
+	}
+	phyaddrs := map[string]string{
+		"Ifname":  "eth0",
+		"PciLong": "0000:00:03.0",
+		"Irq":     "10",
+		"Ioports": "c000-c0ff",
+	}
+	// Map iteration order is random hence repeat
+	expected := computeConfigSha(makeIo(phyaddrs))
+	for i := 0; i < 20; i++ {
+		copied := make(map[string]string)
+		for key, value := range phyaddrs {
+			copied[key] = value
+		}
+		assert.Equal(t, expected, computeConfigSha(makeIo(copied)))
+	}
+
+	changed := makeIo(phyaddrs)
+	changed.Assigngrp = "group1"
+	assert.NotEqual(t, expected, computeConfigSha(changed))
+
+	// Element hashes are the same when fed in the same order
+	h1 := sha256.New()
+	computeConfigElementSha(h1, makeIo(phyaddrs))
+	computeConfigElementSha(h1, changed)
+	h2 := sha256.New()
+	computeConfigElementSha(h2, makeIo(phyaddrs))
+	computeConfigElementSha(h2, changed)
+	assert.Equal(t, h1.Sum(nil), h2.Sum(nil))
+
+	// A nil message is the same as an empty one
+	var nilReboot *zconfig.DeviceOpsCmd
+	assert.Equal(t, computeConfigSha(&zconfig.DeviceOpsCmd{}),
+		computeConfigSha(nilReboot))
+
+	// An empty element changes the element hash
+	h1 = sha256.New()
+	computeConfigElementSha(h1, makeIo(phyaddrs))
+	computeConfigElementSha(h1, nilReboot)
+	h2 = sha256.New()
+	computeConfigElementSha(h2, makeIo(phyaddrs))
+	assert.NotEqual(t, h1.Sum(nil), h2.Sum(nil))
+}
+
+func TestParseIpspecAddrMode(t *testing.T) {
+	testMatrix := map[string]struct {
+		networkType      types.NetworkType
+		dhcp             zconfig.DHCPType
+		addrMode         zconfig.IPv6AddrMode
+		expectedAddrMode types.AddrModeType
+		expectedError    bool
+	}{
+		"IPv4 client unspecified": {
+			networkType:      types.NT_IPV4,
+			dhcp:             zconfig.DHCPType_Client,
+			expectedAddrMode: types.AM_UNSPECIFIED,
+		},
+		"IPv6 client unspecified": {
+			networkType:      types.NT_IPV6,
+			dhcp:             zconfig.DHCPType_Client,
+			expectedAddrMode: types.AM_UNSPECIFIED,
+		},
+		"IPv6 client SLAAC": {
+			networkType:      types.NT_IPV6,
+			dhcp:             zconfig.DHCPType_Client,
+			addrMode:         zconfig.IPv6AddrMode_IPV6_ADDR_MODE_SLAAC,
+			expectedAddrMode: types.AM_SLAAC,
+		},
+		"IPv6 client stateful DHCPv6": {
+			networkType:      types.NT_IPV6,
+			dhcp:             zconfig.DHCPType_Client,
+			addrMode:         zconfig.IPv6AddrMode_IPV6_ADDR_MODE_DHCPV6_STATEFUL,
+			expectedAddrMode: types.AM_DHCPV6_STATEFUL,
+		},
+		"IPv6 client stateless DHCPv6": {
+			networkType:      types.NT_IPV6,
+			dhcp:             zconfig.DHCPType_Client,
+			addrMode:         zconfig.IPv6AddrMode_IPV6_ADDR_MODE_DHCPV6_STATELESS,
+			expectedAddrMode: types.AM_DHCPV6_STATELESS,
+		},
+		"IPv4 client SLAAC": {
+			networkType:   types.NT_IPV4,
+			dhcp:          zconfig.DHCPType_Client,
+			addrMode:      zconfig.IPv6AddrMode_IPV6_ADDR_MODE_SLAAC,
+			expectedError: true,
+		},
+		"IPv6 static SLAAC": {
+			networkType:   types.NT_IPV6,
+			dhcp:          zconfig.DHCPType_Static,
+			addrMode:      zconfig.IPv6AddrMode_IPV6_ADDR_MODE_SLAAC,
+			expectedError: true,
+		},
+		"IPv6 none stateful DHCPv6": {
+			networkType:   types.NT_IPV6,
+			dhcp:          zconfig.DHCPType_DHCPNone,
+			addrMode:      zconfig.IPv6AddrMode_IPV6_ADDR_MODE_DHCPV6_STATEFUL,
+			expectedError: true,
+		},
+		"IPv6 client unknown mode": {
+			networkType:   types.NT_IPV6,
+			dhcp:          zconfig.DHCPType_Client,
+			addrMode:      zconfig.IPv6AddrMode(42),
+			expectedError: true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		ipspec := &zconfig.Ipspec{
+			Dhcp:         test.dhcp,
+			Ipv6AddrMode: test.addrMode,
+		}
+		xconfig := types.NetworkXObjectConfig{Type: test.networkType}
+		err := parseIpspecNetworkXObject(ipspec, &xconfig)
+		assert.Equal(t, test.expectedError, err != nil, testname)
+		if err == nil {
+			assert.Equal(t, test.expectedAddrMode, xconfig.AddrMode,
+				testname)
+		}
+	}
+}
+
+func TestParseOneSystemAdapterConfigIPv6(t *testing.T) {
+	networkUUID := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	testMatrix := map[string]struct {
+		network            types.NetworkXObjectConfig
+		addr               string
+		expectedAddrMode   types.AddrModeType
+		expectedAddrSubnet string
+		expectedError      bool
+	}{
+		"SLAAC client": {
+			network: types.NetworkXObjectConfig{
+				Type:     types.NT_IPV6,
+				Dhcp:     types.DT_CLIENT,
+				AddrMode: types.AM_SLAAC,
+			},
+			expectedAddrMode: types.AM_SLAAC,
+		},
+		"Stateful DHCPv6 client": {
+			network: types.NetworkXObjectConfig{
+				Type:     types.NT_IPV6,
+				Dhcp:     types.DT_CLIENT,
+				AddrMode: types.AM_DHCPV6_STATEFUL,
+			},
+			expectedAddrMode: types.AM_DHCPV6_STATEFUL,
+		},
+		"Static with subnet": {
+			network: types.NetworkXObjectConfig{
+				Type: types.NT_IPV6,
+				Dhcp: types.DT_STATIC,
+				Subnet: net.IPNet{
+					IP:   net.ParseIP("fd00::"),
+					Mask: net.CIDRMask(64, 128),
+				},
+			},
+			addr:               "fd00::10",
+			expectedAddrSubnet: "fd00::10/64",
+		},
+		"Static without subnet": {
+			network: types.NetworkXObjectConfig{
+				Type: types.NT_IPV6,
+				Dhcp: types.DT_STATIC,
+			},
+			addr:          "fd00::10",
+			expectedError: true,
+		},
+		"Static with a gateway": {
+			network: types.NetworkXObjectConfig{
+				Type: types.NT_IPV6,
+				Dhcp: types.DT_STATIC,
+				Subnet: net.IPNet{
+					IP:   net.ParseIP("fd00::"),
+					Mask: net.CIDRMask(64, 128),
+				},
+				Gateway: net.ParseIP("fd00::1"),
+			},
+			addr:               "fd00::10",
+			expectedAddrSubnet: "fd00::10/64",
+		},
+		"Static with an IPv4 gateway": {
+			network: types.NetworkXObjectConfig{
+				Type: types.NT_IPV6,
+				Dhcp: types.DT_STATIC,
+				Subnet: net.IPNet{
+					IP:   net.ParseIP("fd00::"),
+					Mask: net.CIDRMask(64, 128),
+				},
+				Gateway: net.ParseIP("192.168.1.1"),
+			},
+			addr:               "fd00::10",
+			expectedAddrSubnet: "fd00::10/64",
+			expectedError:      true,
+		},
+		"Static outside the subnet": {
+			network: types.NetworkXObjectConfig{
+				Type: types.NT_IPV6,
+				Dhcp: types.DT_STATIC,
+				Subnet: net.IPNet{
+					IP:   net.ParseIP("fd00::"),
+					Mask: net.CIDRMask(64, 128),
+				},
+			},
+			addr:          "fd01::10",
+			expectedError: true,
+		},
+		"Static IPv6 address in an IPv4 subnet": {
+			network: types.NetworkXObjectConfig{
+				Type: types.NT_IPV4,
+				Dhcp: types.DT_STATIC,
+				Subnet: net.IPNet{
+					IP:   net.ParseIP("192.168.1.0").To4(),
+					Mask: net.CIDRMask(24, 32),
+				},
+			},
+			addr:          "fd00::10",
+			expectedError: true,
+		},
+		"Static IPv4": {
+			network: types.NetworkXObjectConfig{
+				Type: types.NT_IPV4,
+				Dhcp: types.DT_STATIC,
+				Subnet: net.IPNet{
+					IP:   net.ParseIP("192.168.1.0").To4(),
+					Mask: net.CIDRMask(24, 32),
+				},
+				Gateway: net.ParseIP("192.168.1.1"),
+			},
+			addr:               "192.168.1.10",
+			expectedAddrSubnet: "192.168.1.10/24",
+		},
+		"Client outside the subnet": {
+			network: types.NetworkXObjectConfig{
+				Type: types.NT_IPV6,
+				Dhcp: types.DT_CLIENT,
+				Subnet: net.IPNet{
+					IP:   net.ParseIP("fd00::"),
+					Mask: net.CIDRMask(64, 128),
+				},
+			},
+			addr: "fd01::10",
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		getconfigCtx.zedagentCtx = &zedagentContext{
+			getconfigCtx: getconfigCtx,
+			physicalIoAdapterMap: map[string]types.PhysicalIOAdapter{
+				"eth0": {
+					Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+					Phylabel:     "eth0",
+					Logicallabel: "eth0",
+					Phyaddr:      types.PhysicalAddress{Ifname: "eth0"},
+				},
+			},
+		}
+		network := test.network
+		network.UUID, _ = uuid.FromString(networkUUID)
+		getconfigCtx.pubNetworkXObjectConfig.Publish(network.Key(), network)
+
+		sysAdapter := &zconfig.SystemAdapter{
+			Name:        "eth0",
+			Uplink:      true,
+			NetworkUUID: networkUUID,
+			Addr:        test.addr,
+		}
+		port, err := parseOneSystemAdapterConfig(getconfigCtx, sysAdapter,
+			types.DPCIsMgmt)
+		assert.Nil(t, err, testname)
+		assert.NotNil(t, port, testname)
+		assert.Equal(t, test.expectedAddrMode, port.AddrMode, testname)
+		assert.Equal(t, test.expectedAddrSubnet, port.AddrSubnet, testname)
+		assert.Equal(t, test.expectedError, port.HasError(), testname)
+	}
+}
+
+func TestParseOneSystemAdapterConfigAddrs(t *testing.T) {
+	networkUUID := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	ipv4Subnet := net.IPNet{
+		IP:   net.ParseIP("192.168.1.0").To4(),
+		Mask: net.CIDRMask(24, 32),
+	}
+	ipv6Subnet := net.IPNet{
+		IP:   net.ParseIP("fd00::"),
+		Mask: net.CIDRMask(64, 128),
+	}
+	dualStack := types.NetworkXObjectConfig{
+		Type:         types.NT_IPV4,
+		Dhcp:         types.DT_STATIC,
+		Subnet:       ipv4Subnet,
+		ExtraSubnets: []net.IPNet{ipv6Subnet},
+		Gateway:      net.ParseIP("192.168.1.1"),
+	}
+	testMatrix := map[string]struct {
+		network             types.NetworkXObjectConfig
+		addr                string
+		addrs               []string
+		expectedAddrSubnets []string
+		expectedError       bool
+	}{
+		"Dual stack": {
+			network:             dualStack,
+			addrs:               []string{"192.168.1.10", "fd00::10"},
+			expectedAddrSubnets: []string{"192.168.1.10/24", "fd00::10/64"},
+		},
+		"IPv6 first": {
+			network:             dualStack,
+			addrs:               []string{"fd00::10", "192.168.1.10"},
+			expectedAddrSubnets: []string{"fd00::10/64", "192.168.1.10/24"},
+		},
+		"Only the addr": {
+			network:             dualStack,
+			addr:                "192.168.1.10",
+			expectedAddrSubnets: []string{"192.168.1.10/24"},
+		},
+		"Addrs over the addr": {
+			network:             dualStack,
+			addr:                "192.168.1.99",
+			addrs:               []string{"192.168.1.10"},
+			expectedAddrSubnets: []string{"192.168.1.10/24"},
+		},
+		"IPv6 outside its subnet": {
+			network:             dualStack,
+			addrs:               []string{"192.168.1.10", "fd01::10"},
+			expectedAddrSubnets: []string{"192.168.1.10/24"},
+			expectedError:       true,
+		},
+		"No IPv6 subnet": {
+			network: types.NetworkXObjectConfig{
+				Type:   types.NT_IPV4,
+				Dhcp:   types.DT_STATIC,
+				Subnet: ipv4Subnet,
+			},
+			addrs:               []string{"192.168.1.10", "fd00::10"},
+			expectedAddrSubnets: []string{"192.168.1.10/24"},
+			expectedError:       true,
+		},
+		"Two IPv4 addresses": {
+			network:             dualStack,
+			addrs:               []string{"192.168.1.10", "192.168.1.11"},
+			expectedAddrSubnets: []string{"192.168.1.10/24"},
+			expectedError:       true,
+		},
+		"Bad address": {
+			network:             dualStack,
+			addrs:               []string{"192.168.1.300", "fd00::10"},
+			expectedAddrSubnets: []string{"fd00::10/64"},
+			expectedError:       true,
+		},
+		"Gateway of the other address": {
+			network:             dualStack,
+			addrs:               []string{"fd00::10", "192.168.1.10"},
+			expectedAddrSubnets: []string{"fd00::10/64", "192.168.1.10/24"},
+		},
+		"Gateway of no address": {
+			network:             dualStack,
+			addrs:               []string{"fd00::10"},
+			expectedAddrSubnets: []string{"fd00::10/64"},
+			expectedError:       true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		getconfigCtx.zedagentCtx = &zedagentContext{
+			getconfigCtx: getconfigCtx,
+			physicalIoAdapterMap: map[string]types.PhysicalIOAdapter{
+				"eth0": {
+					Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+					Phylabel:     "eth0",
+					Logicallabel: "eth0",
+					Phyaddr:      types.PhysicalAddress{Ifname: "eth0"},
+				},
+			},
+		}
+		network := test.network
+		network.UUID, _ = uuid.FromString(networkUUID)
+		getconfigCtx.pubNetworkXObjectConfig.Publish(network.Key(), network)
+
+		sysAdapter := &zconfig.SystemAdapter{
+			Name:        "eth0",
+			Uplink:      true,
+			NetworkUUID: networkUUID,
+			Addr:        test.addr,
+			Addrs:       test.addrs,
+		}
+		port, err := parseOneSystemAdapterConfig(getconfigCtx, sysAdapter,
+			types.DPCIsMgmt)
+		assert.Nil(t, err, testname)
+		assert.NotNil(t, port, testname)
+		assert.Equal(t, test.expectedAddrSubnets, port.AddrSubnets, testname)
+		assert.Equal(t, test.expectedAddrSubnets[0], port.AddrSubnet,
+			testname)
+		assert.Equal(t, test.expectedError, port.HasError(), testname)
+	}
+}
+
+func TestParseIpspecNetworkXObjectExtraSubnets(t *testing.T) {
+	testMatrix := map[string]struct {
+		subnet               string
+		extraSubnets         []string
+		expectedExtraSubnets []string
+		expectedError        bool
+	}{
+		"IPv6 extra subnet": {
+			subnet:               "192.168.1.0/24",
+			extraSubnets:         []string{"fd00::/64"},
+			expectedExtraSubnets: []string{"fd00::/64"},
+		},
+		"IPv4 extra subnet": {
+			subnet:               "fd00::/64",
+			extraSubnets:         []string{"192.168.1.0/24"},
+			expectedExtraSubnets: []string{"192.168.1.0/24"},
+		},
+		"Same IP version as the subnet": {
+			subnet:        "192.168.1.0/24",
+			extraSubnets:  []string{"192.168.2.0/24"},
+			expectedError: true,
+		},
+		"Two IPv6 extra subnets": {
+			subnet:        "192.168.1.0/24",
+			extraSubnets:  []string{"fd00::/64", "fd01::/64"},
+			expectedError: true,
+		},
+		"Bad extra subnet": {
+			subnet:        "192.168.1.0/24",
+			extraSubnets:  []string{"fd00::"},
+			expectedError: true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		ipspec := &zconfig.Ipspec{
+			Dhcp:         zconfig.DHCPType_Static,
+			Subnet:       test.subnet,
+			ExtraSubnets: test.extraSubnets,
+		}
+		config := &types.NetworkXObjectConfig{}
+		err := parseIpspecNetworkXObject(ipspec, config)
+		if test.expectedError {
+			assert.NotNil(t, err, testname)
+			continue
+		}
+		assert.Nil(t, err, testname)
+		var extraSubnets []string
+		for _, subnet := range config.ExtraSubnets {
+			extraSubnets = append(extraSubnets, subnet.String())
+		}
+		assert.Equal(t, test.expectedExtraSubnets, extraSubnets, testname)
+	}
+}
+
+func TestParseAppInstanceConfigHostnames(t *testing.T) {
+	const (
+		uuidA = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+		uuidB = "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+		niX   = "6ba7b812-9dad-11d1-80b4-00c04fd430c8"
+		niY   = "6ba7b813-9dad-11d1-80b4-00c04fd430c8"
+	)
+	testMatrix := map[string]struct {
+		networkA          string
+		hostnamesA        []string
+		networkB          string
+		hostnamesB        []string
+		expectedHostnames []string
+		expectedErrorA    string
+		expectedErrorB    string
+	}{
+		"Distinct hostnames": {
+			networkA:          niX,
+			hostnamesA:        []string{"db.local", "DB.local.", "db2"},
+			networkB:          niX,
+			hostnamesB:        []string{"cache.local"},
+			expectedHostnames: []string{"db.local", "db2"},
+		},
+		"Same hostname on different network instances": {
+			networkA:          niX,
+			hostnamesA:        []string{"db.local"},
+			networkB:          niY,
+			hostnamesB:        []string{"db.local"},
+			expectedHostnames: []string{"db.local"},
+		},
+		"Duplicate hostname across apps": {
+			networkA:          niX,
+			hostnamesA:        []string{"db.local"},
+			networkB:          niX,
+			hostnamesB:        []string{"Db.Local"},
+			expectedHostnames: []string{"db.local"},
+			expectedErrorA:    "also used by app appB interface eth0",
+			expectedErrorB:    "also used by app appA interface eth0",
+		},
+		"Invalid hostname": {
+			networkA:       niX,
+			hostnamesA:     []string{"db_local"},
+			networkB:       niX,
+			expectedErrorA: "invalid character",
+		},
+		"Hostname starting with a hyphen": {
+			networkA:       niX,
+			hostnamesA:     []string{"-db.local"},
+			networkB:       niX,
+			expectedErrorA: "starts or ends with a hyphen",
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		getconfigCtx.zedagentCtx = &zedagentContext{
+			globalConfig: *types.DefaultConfigItemValueMap(),
+		}
+		appinstancePrevConfigHash = nil
+		appinstancePrevElementHash = make(map[string][]byte)
+
+		appA := newTestAppInstance(uuidA, "appA")
+		appA.Interfaces = []*zconfig.NetworkAdapter{
+			{Name: "eth0", NetworkId: test.networkA,
+				Hostnames: test.hostnamesA},
+		}
+		appB := newTestAppInstance(uuidB, "appB")
+		appB.Interfaces = []*zconfig.NetworkAdapter{
+			{Name: "eth0", NetworkId: test.networkB,
+				Hostnames: test.hostnamesB},
+		}
+		parseAppInstanceConfig(&zconfig.EdgeDevConfig{
+			Apps: []*zconfig.AppInstanceConfig{appA, appB},
+			NetworkInstances: []*zconfig.NetworkInstanceConfig{
+				{Uuidandversion: &zconfig.UUIDandVersion{Uuid: niX}},
+				{Uuidandversion: &zconfig.UUIDandVersion{Uuid: niY}},
+			},
+		}, getconfigCtx)
+
+		for _, check := range []struct {
+			uuid          string
+			expectedError string
+		}{
+			{uuidA, test.expectedErrorA},
+			{uuidB, test.expectedErrorB},
+		} {
+			c, err := getconfigCtx.pubAppInstanceConfig.Get(check.uuid)
+			assert.Nil(t, err, testname)
+			appInstance := c.(types.AppInstanceConfig)
+			if check.expectedError == "" {
+				assert.Empty(t, appInstance.Errors, testname)
+			} else {
+				assert.Equal(t, 1, len(appInstance.Errors), testname)
+				assert.Contains(t, strings.Join(appInstance.ErrorStrings(), ""),
+					check.expectedError, testname)
+			}
+		}
+		if test.expectedHostnames != nil {
+			c, _ := getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+			appInstance := c.(types.AppInstanceConfig)
+			assert.Equal(t, test.expectedHostnames,
+				appInstance.UnderlayNetworkList[0].Hostnames, testname)
+		}
+	}
+}
+
+func TestParseAppInstanceConfigHostnameConflictUpdate(t *testing.T) {
+	const (
+		uuidA = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+		uuidB = "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+		niX   = "6ba7b812-9dad-11d1-80b4-00c04fd430c8"
+	)
+	getconfigCtx := initGetConfigCtx(t)
+	getconfigCtx.zedagentCtx = &zedagentContext{
+		globalConfig: *types.DefaultConfigItemValueMap(),
+	}
+	appinstancePrevConfigHash = nil
+	appinstancePrevElementHash = make(map[string][]byte)
+	getErrors := func(uuidStr string) []string {
+		c, err := getconfigCtx.pubAppInstanceConfig.Get(uuidStr)
+		assert.Nil(t, err)
+		return c.(types.AppInstanceConfig).ErrorStrings()
+	}
+	niList := []*zconfig.NetworkInstanceConfig{
+		{Uuidandversion: &zconfig.UUIDandVersion{Uuid: niX}},
+	}
+	appA := newTestAppInstance(uuidA, "appA")
+	appA.Interfaces = []*zconfig.NetworkAdapter{
+		{Name: "eth0", NetworkId: niX, Hostnames: []string{"db.local"}},
+	}
+	appB := newTestAppInstance(uuidB, "appB")
+	appB.Interfaces = []*zconfig.NetworkAdapter{
+		{Name: "eth0", NetworkId: niX, Hostnames: []string{"db.local"}},
+	}
+
+	parseAppInstanceConfig(&zconfig.EdgeDevConfig{
+		Apps:             []*zconfig.AppInstanceConfig{appA},
+		NetworkInstances: niList,
+	}, getconfigCtx)
+	assert.Empty(t, getErrors(uuidA))
+
+	// Adding appB flags the unchanged appA as well
+	parseAppInstanceConfig(&zconfig.EdgeDevConfig{
+		Apps:             []*zconfig.AppInstanceConfig{appA, appB},
+		NetworkInstances: niList,
+	}, getconfigCtx)
+	assert.Equal(t, 1, len(getErrors(uuidA)))
+	assert.Equal(t, 1, len(getErrors(uuidB)))
+
+	// Removing appB clears the error on appA
+	parseAppInstanceConfig(&zconfig.EdgeDevConfig{
+		Apps:             []*zconfig.AppInstanceConfig{appA},
+		NetworkInstances: niList,
+	}, getconfigCtx)
+	assert.Empty(t, getErrors(uuidA))
+}
+
+func TestParseAppInstanceConfigErrorCategories(t *testing.T) {
+	const (
+		uuidA = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+		uuidB = "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+		niX   = "6ba7b812-9dad-11d1-80b4-00c04fd430c8"
+		niY   = "6ba7b813-9dad-11d1-80b4-00c04fd430c8"
+	)
+	testMatrix := map[string]struct {
+		intfA            []*zconfig.NetworkAdapter
+		expectedErrors   []types.AppConfigError
+		expectedErrorStr string
+	}{
+		"No error": {
+			intfA: []*zconfig.NetworkAdapter{
+				{Name: "eth0", NetworkId: niX},
+			},
+		},
+		"Bad MAC": {
+			intfA: []*zconfig.NetworkAdapter{
+				{Name: "eth0", NetworkId: niX},
+				{Name: "eth1", NetworkId: niX, MacAddress: "00:16:3e"},
+			},
+			expectedErrors: []types.AppConfigError{
+				{IntfName: "eth1", Category: types.AppConfigErrorBadMAC},
+			},
+			expectedErrorStr: "bad MAC",
+		},
+		"Multicast MAC": {
+			intfA: []*zconfig.NetworkAdapter{
+				{Name: "eth0", NetworkId: niX, MacAddress: "01:00:5e:00:00:01"},
+			},
+			expectedErrors: []types.AppConfigError{
+				{IntfName: "eth0", Category: types.AppConfigErrorBadMAC},
+			},
+			expectedErrorStr: "multicast",
+		},
+		"Missing network": {
+			intfA: []*zconfig.NetworkAdapter{
+				{Name: "eth0", NetworkId: niY},
+			},
+			expectedErrors: []types.AppConfigError{
+				{IntfName: "eth0", Category: types.AppConfigErrorMissingNetwork},
+			},
+			expectedErrorStr: "Can't find",
+		},
+		"Hostname conflict": {
+			intfA: []*zconfig.NetworkAdapter{
+				{Name: "eth0", NetworkId: niX},
+				{Name: "eth1", NetworkId: niX, Hostnames: []string{"db.local"}},
+			},
+			expectedErrors: []types.AppConfigError{
+				{IntfName: "eth1", Category: types.AppConfigErrorBadHostname},
+			},
+			expectedErrorStr: "db.local",
+		},
+		"Two errors": {
+			intfA: []*zconfig.NetworkAdapter{
+				{Name: "eth0", NetworkId: niY},
+				{Name: "eth1", NetworkId: niX, Addr: "10.1.0.300"},
+			},
+			expectedErrors: []types.AppConfigError{
+				{IntfName: "eth0", Category: types.AppConfigErrorMissingNetwork},
+				{IntfName: "eth1", Category: types.AppConfigErrorBadIP},
+			},
+			expectedErrorStr: "bad AppIPAddr",
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		getconfigCtx.zedagentCtx = &zedagentContext{
+			globalConfig: *types.DefaultConfigItemValueMap(),
+		}
+		appinstancePrevConfigHash = nil
+		appinstancePrevElementHash = make(map[string][]byte)
+		appA := newTestAppInstance(uuidA, "appA")
+		appA.Interfaces = test.intfA
+		appB := newTestAppInstance(uuidB, "appB")
+		appB.Interfaces = []*zconfig.NetworkAdapter{
+			{Name: "eth0", NetworkId: niX, Hostnames: []string{"db.local"}},
+		}
+		parseAppInstanceConfig(&zconfig.EdgeDevConfig{
+			Apps: []*zconfig.AppInstanceConfig{appA, appB},
+			NetworkInstances: []*zconfig.NetworkInstanceConfig{
+				{Uuidandversion: &zconfig.UUIDandVersion{Uuid: niX}},
+			},
+		}, getconfigCtx)
+
+		c, err := getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+		assert.Nil(t, err, testname)
+		appInstance := c.(types.AppInstanceConfig)
+		assert.Equal(t, len(test.expectedErrors), len(appInstance.Errors),
+			testname)
+		if len(test.expectedErrors) != len(appInstance.Errors) {
+			continue
+		}
+		for i, expected := range test.expectedErrors {
+			actual := appInstance.Errors[i]
+			assert.Equal(t, expected.IntfName, actual.IntfName, testname)
+			assert.Equal(t, expected.Category, actual.Category, testname)
+			assert.NotEmpty(t, actual.Error, testname)
+			assert.False(t, actual.ErrorTime.IsZero(), testname)
+		}
+		errStrs := appInstance.ErrorStrings()
+		assert.Equal(t, len(appInstance.Errors), len(errStrs), testname)
+		if test.expectedErrorStr != "" {
+			assert.Contains(t, strings.Join(errStrs, ""),
+				test.expectedErrorStr, testname)
+		}
+	}
+}
+
+func TestPublishNetworkInstanceConfigUpstreamDNS(t *testing.T) {
+	testMatrix := map[string]struct {
+		instType         zconfig.ZNetworkInstType
+		ipType           zconfig.AddressType
+		dns              []string
+		upstreamDNS      []string
+		expectedDNS      []net.IP
+		expectedUpstream []net.IP
+		expectedError    bool
+	}{
+		"Client DNS only": {
+			instType:    zconfig.ZNetworkInstType_ZnetInstLocal,
+			ipType:      zconfig.AddressType_IPV4,
+			dns:         []string{"10.1.0.1"},
+			expectedDNS: []net.IP{net.ParseIP("10.1.0.1")},
+		},
+		"Client and upstream DNS": {
+			instType:    zconfig.ZNetworkInstType_ZnetInstLocal,
+			ipType:      zconfig.AddressType_IPV4,
+			dns:         []string{"10.1.0.1"},
+			upstreamDNS: []string{"192.168.100.53", "fd00::53", "192.168.100.53"},
+			expectedDNS: []net.IP{net.ParseIP("10.1.0.1")},
+			expectedUpstream: []net.IP{net.ParseIP("192.168.100.53"),
+				net.ParseIP("fd00::53")},
+		},
+		"Upstream DNS only": {
+			instType:         zconfig.ZNetworkInstType_ZnetInstLocal,
+			ipType:           zconfig.AddressType_IPV4,
+			upstreamDNS:      []string{"192.168.100.53"},
+			expectedUpstream: []net.IP{net.ParseIP("192.168.100.53")},
+		},
+		"Bad upstream DNS": {
+			instType:      zconfig.ZNetworkInstType_ZnetInstLocal,
+			ipType:        zconfig.AddressType_IPV4,
+			dns:           []string{"10.1.0.1"},
+			upstreamDNS:   []string{"192.168.100"},
+			expectedDNS:   []net.IP{net.ParseIP("10.1.0.1")},
+			expectedError: true,
+		},
+		"Unspecified upstream DNS": {
+			instType:      zconfig.ZNetworkInstType_ZnetInstLocal,
+			ipType:        zconfig.AddressType_IPV4,
+			upstreamDNS:   []string{"0.0.0.0"},
+			expectedError: true,
+		},
+		"Multicast upstream DNS": {
+			instType:      zconfig.ZNetworkInstType_ZnetInstLocal,
+			ipType:        zconfig.AddressType_IPV4,
+			upstreamDNS:   []string{"224.0.0.251"},
+			expectedError: true,
+		},
+		"Upstream DNS on switch": {
+			instType:      zconfig.ZNetworkInstType_ZnetInstSwitch,
+			ipType:        zconfig.AddressType_First,
+			upstreamDNS:   []string{"192.168.100.53"},
+			expectedError: true,
+		},
+		"Too many upstream DNS": {
+			instType: zconfig.ZNetworkInstType_ZnetInstLocal,
+			ipType:   zconfig.AddressType_IPV4,
+			upstreamDNS: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3",
+				"10.0.0.4", "10.0.0.5", "10.0.0.6", "10.0.0.7",
+				"10.0.0.8", "10.0.0.9"},
+			expectedUpstream: []net.IP{net.ParseIP("10.0.0.1"),
+				net.ParseIP("10.0.0.2"), net.ParseIP("10.0.0.3"),
+				net.ParseIP("10.0.0.4"), net.ParseIP("10.0.0.5"),
+				net.ParseIP("10.0.0.6"), net.ParseIP("10.0.0.7"),
+				net.ParseIP("10.0.0.8")},
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	uuidStr := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		networkInstances := []*zconfig.NetworkInstanceConfig{
+			{
+				Uuidandversion: &zconfig.UUIDandVersion{
+					Uuid:    uuidStr,
+					Version: "1",
+				},
+				InstType:           test.instType,
+				IpType:             test.ipType,
+				Ip:                 &zconfig.Ipspec{Dns: test.dns},
+				UpstreamDnsServers: test.upstreamDNS,
+			},
+		}
+		publishNetworkInstanceConfig(getconfigCtx, networkInstances)
+		c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+		assert.Nil(t, err, testname)
+		config := c.(types.NetworkInstanceConfig)
+		assert.Equal(t, test.expectedError, config.HasError(), testname)
+		assert.Equal(t, test.expectedDNS, config.DnsServers, testname)
+		assert.Equal(t, test.expectedUpstream, config.UpstreamDnsServers,
+			testname)
+	}
+}
+
+func TestPrevConfigHashes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "prevconfighash")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "prev-config-hash")
+	const bootID = "d3b5c9a1-0f39-4c1e-9a59-3c8b0e0b6a11"
+
+	resetHashes := func() {
+		baseOSConfigPrevConfigHash = nil
+		networkConfigPrevConfigHash = nil
+		networkInstancePrevConfigHash = nil
+		datastoreConfigPrevConfigHash = nil
+		appinstancePrevConfigHash = nil
+		appinstancePrevElementHash = make(map[string][]byte)
+		prevConfigHashSaved = nil
+	}
+	defer resetHashes()
+
+	testMatrix := map[string]struct {
+		content        []byte // Overwrites the saved file if set
+		remove         bool
+		loadBootID     string
+		expectedLoaded bool
+	}{
+		"Same boot": {
+			loadBootID:     bootID,
+			expectedLoaded: true,
+		},
+		"After a reboot": {
+			loadBootID: "0a1b2c3d-0f39-4c1e-9a59-3c8b0e0b6a11",
+		},
+		"Unknown boot": {
+			loadBootID: "",
+		},
+		"Missing file": {
+			remove:     true,
+			loadBootID: bootID,
+		},
+		"Corrupt file": {
+			content:    []byte("{\"BootID\": \"d3b5c9a1"),
+			loadBootID: bootID,
+		},
+	}
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		resetHashes()
+		networkInstancePrevConfigHash = []byte{1, 2, 3}
+		appinstancePrevConfigHash = []byte{4, 5, 6}
+		appinstancePrevElementHash["app1"] = []byte{7, 8, 9}
+		savePrevConfigHashes(filename, bootID)
+		if test.content != nil {
+			err := ioutil.WriteFile(filename, test.content, 0644)
+			assert.Nil(t, err, testname)
+		}
+		if test.remove {
+			os.Remove(filename)
+		}
+
+		resetHashes()
+		loadPrevConfigHashes(filename, test.loadBootID)
+		if test.expectedLoaded {
+			assert.Equal(t, []byte{1, 2, 3}, networkInstancePrevConfigHash,
+				testname)
+			assert.Equal(t, []byte{4, 5, 6}, appinstancePrevConfigHash,
+				testname)
+			assert.Equal(t, map[string][]byte{"app1": {7, 8, 9}},
+				appinstancePrevElementHash, testname)
+		} else {
+			assert.Nil(t, networkInstancePrevConfigHash, testname)
+			assert.Nil(t, appinstancePrevConfigHash, testname)
+			assert.Empty(t, appinstancePrevElementHash, testname)
+		}
+	}
+}
+
+func TestPrevConfigHashesSkipUnchanged(t *testing.T) {
+	dir, err := ioutil.TempDir("", "prevconfighash")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "prev-config-hash")
+	const bootID = "d3b5c9a1-0f39-4c1e-9a59-3c8b0e0b6a11"
+	uuidStr := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	networkInstances := []*zconfig.NetworkInstanceConfig{
+		{
+			Uuidandversion: &zconfig.UUIDandVersion{
+				Uuid:    uuidStr,
+				Version: "1",
+			},
+			InstType: zconfig.ZNetworkInstType_ZnetInstLocal,
+			IpType:   zconfig.AddressType_IPV4,
+			Ip:       &zconfig.Ipspec{},
+		},
+	}
+	config := &zconfig.EdgeDevConfig{NetworkInstances: networkInstances}
+	networkInstancePrevConfigHash = nil
+	prevConfigHashSaved = nil
+	defer func() {
+		networkInstancePrevConfigHash = nil
+		prevConfigHashSaved = nil
+	}()
+
+	getconfigCtx := initGetConfigCtx(t)
+	parseNetworkInstanceConfig(config, getconfigCtx, false)
+	_, err = getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+	assert.Nil(t, err)
+	savePrevConfigHashes(filename, bootID)
+
+	// Restart with the publications kept but nothing in memory
+	networkInstancePrevConfigHash = nil
+	getconfigCtx.pubNetworkInstanceConfig.Unpublish(uuidStr)
+	loadPrevConfigHashes(filename, bootID)
+	parseNetworkInstanceConfig(config, getconfigCtx, false)
+	_, err = getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+	assert.NotNil(t, err, "unchanged config should not be re-published")
+
+	// A changed config is parsed
+	networkInstances[0].Displayname = "changed"
+	parseNetworkInstanceConfig(config, getconfigCtx, false)
+	_, err = getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+	assert.Nil(t, err)
+}
+
+func TestDeprecatedFields(t *testing.T) {
+	const (
+		netID     = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+		contentID = "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+		appID     = "6ba7b812-9dad-11d1-80b4-00c04fd430c8"
+		meshID    = "6ba7b813-9dad-11d1-80b4-00c04fd430c8"
+	)
+	testMatrix := map[string]struct {
+		config        *zconfig.EdgeDevConfig
+		expectedField *deprecatedField
+		expectedKeys  []string
+	}{
+		"No deprecated fields": {
+			config: &zconfig.EdgeDevConfig{
+				DeviceIoList: []*zconfig.PhysicalIO{
+					{Ptype: zcommon.PhyIoType_PhyIoNetEth,
+						Phylabel: "eth0", Logicallabel: "eth0",
+						Usage:    zcommon.PhyIoMemberUsage_PhyIoUsageMgmtAndApps,
+						Phyaddrs: map[string]string{"ifname": "eth0"}},
+				},
+				Networks: []*zconfig.NetworkConfig{
+					{Id: netID, Type: zconfig.NetworkType_V4,
+						Ip: &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client}},
+				},
+				SystemAdapterList: []*zconfig.SystemAdapter{
+					{Name: "eth0", NetworkUUID: netID},
+				},
+			},
+		},
+		"SystemAdapter uplink": {
+			config: &zconfig.EdgeDevConfig{
+				DeviceIoList: []*zconfig.PhysicalIO{
+					{Ptype: zcommon.PhyIoType_PhyIoNetEth,
+						Phylabel: "eth0", Logicallabel: "eth0",
+						Phyaddrs: map[string]string{"ifname": "eth0"}},
+				},
+				SystemAdapterList: []*zconfig.SystemAdapter{
+					{Name: "eth0", Uplink: true},
+				},
+			},
+			expectedField: deprecatedSystemAdapterUplink,
+			expectedKeys:  []string{"eth0"},
+		},
+		"SystemAdapter freeUplink": {
+			config: &zconfig.EdgeDevConfig{
+				DeviceIoList: []*zconfig.PhysicalIO{
+					{Ptype: zcommon.PhyIoType_PhyIoNetEth,
+						Phylabel: "eth0", Logicallabel: "eth0",
+						Phyaddrs: map[string]string{"ifname": "eth0"}},
+				},
+				SystemAdapterList: []*zconfig.SystemAdapter{
+					{Name: "eth0", FreeUplink: true},
+				},
+			},
+			expectedField: deprecatedSystemAdapterFreeUplink,
+			expectedKeys:  []string{"eth0"},
+		},
+		"PhysicalIO freeUplink": {
+			config: &zconfig.EdgeDevConfig{
+				DeviceIoList: []*zconfig.PhysicalIO{
+					{Ptype: zcommon.PhyIoType_PhyIoNetEth,
+						Phylabel: "eth1", Logicallabel: "eth1",
+						Phyaddrs:    map[string]string{"ifname": "eth1"},
+						UsagePolicy: &zconfig.PhyIOUsagePolicy{FreeUplink: true}},
+				},
+			},
+			expectedField: deprecatedPhyIOFreeUplink,
+			expectedKeys:  []string{"eth1"},
+		},
+		"NT_NOOP with ipspec": {
+			config: &zconfig.EdgeDevConfig{
+				Networks: []*zconfig.NetworkConfig{
+					{Id: netID, Type: zconfig.NetworkType_NETWORKTYPENOOP,
+						Ip: &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client}},
+				},
+			},
+			expectedField: deprecatedNetworkNoopIpspec,
+			expectedKeys:  []string{netID},
+		},
+		"Intercertsurl": {
+			config: &zconfig.EdgeDevConfig{
+				ContentInfo: []*zconfig.ContentTree{
+					{Uuid: contentID,
+						Siginfo: &zconfig.SignatureInfo{
+							Intercertsurl: "intercert.pem"}},
+				},
+			},
+			expectedField: deprecatedIntercertsurl,
+			expectedKeys:  []string{contentID},
+		},
+		"EID in overlay addr": {
+			config: &zconfig.EdgeDevConfig{
+				NetworkInstances: []*zconfig.NetworkInstanceConfig{
+					{Uuidandversion: &zconfig.UUIDandVersion{Uuid: meshID},
+						InstType: zconfig.ZNetworkInstType_ZnetInstMesh},
+				},
+				Apps: []*zconfig.AppInstanceConfig{
+					{Uuidandversion: &zconfig.UUIDandVersion{Uuid: appID,
+						Version: "1"},
+						Displayname:    "app",
+						Fixedresources: &zconfig.VmConfig{},
+						Interfaces: []*zconfig.NetworkAdapter{
+							{Name: "eth0", NetworkId: meshID,
+								Addr: "fd00::1"},
+						}},
+				},
+			},
+			expectedField: deprecatedOverlayAddr,
+			expectedKeys:  []string{appID + "/eth0"},
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		getconfigCtx.zedagentCtx = &zedagentContext{
+			getconfigCtx:         getconfigCtx,
+			globalConfig:         *types.DefaultConfigItemValueMap(),
+			physicalIoAdapterMap: make(map[string]types.PhysicalIOAdapter),
+		}
+		deviceIoListPrevConfigHash = nil
+		networkConfigPrevConfigHash = nil
+		systemAdaptersPrevConfigHash = nil
+		contentInfoHash = nil
+		appinstancePrevConfigHash = nil
+		appinstancePrevElementHash = make(map[string][]byte)
+		totalHits := make(map[string]uint64)
+		for _, field := range deprecatedFields {
+			totalHits[field.name] = field.totalHits
+		}
+
+		startDeprecatedFieldRun()
+		parseDeviceIoListConfig(test.config, getconfigCtx)
+		parseNetworkXObjectConfig(test.config, getconfigCtx)
+		parseSystemAdapterConfig(test.config, getconfigCtx, true)
+		parseContentInfoConfig(getconfigCtx, test.config)
+		parseAppInstanceConfig(test.config, getconfigCtx)
+		endDeprecatedFieldRun()
+
+		for _, field := range deprecatedFields {
+			var expectedHits uint64
+			if field == test.expectedField {
+				expectedHits = 1
+			}
+			assert.Equal(t, expectedHits, field.lastRunHits,
+				"%s: %s", testname, field.name)
+			assert.Equal(t, totalHits[field.name]+expectedHits,
+				field.totalHits, "%s: %s", testname, field.name)
+		}
+		if test.expectedField == nil {
+			continue
+		}
+		var reported *info.ZInfoDeprecatedConfigField
+		for _, field := range createConfigItemStatus(
+			types.GlobalStatus{}).DeprecatedConfigFields {
+			if field.Name == test.expectedField.name {
+				reported = field
+			}
+		}
+		assert.NotNil(t, reported, testname)
+		if reported != nil {
+			assert.Equal(t, uint64(1), reported.LastRunHits, testname)
+			assert.Equal(t, test.expectedKeys, reported.Keys, testname)
+		}
+	}
+
+	// A run without hits clears the last run but keeps the totals
+	startDeprecatedFieldRun()
+	endDeprecatedFieldRun()
+	for _, field := range getDeprecatedFieldStatus() {
+		assert.Equal(t, uint64(0), field.lastRunHits, field.name)
+		assert.Empty(t, field.keys, field.name)
+		assert.NotEqual(t, uint64(0), field.totalHits, field.name)
+	}
+}
+
+func TestParseOneNetworkXObjectConfigProxyAuth(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	cipherData := &zconfig.CipherBlock{
+		CipherContextId: "6ba7b811-9dad-11d1-80b4-00c04fd430c8",
+		CipherData:      []byte{0x01, 0x02, 0x03},
+	}
+	testMatrix := map[string]struct {
+		proxy            *zconfig.ProxyServer
+		expectedError    bool
+		expectedUsername string
+		expectedIsCipher bool
+	}{
+		"No credentials": {
+			proxy: &zconfig.ProxyServer{Proto: zconfig.ProxyProto_PROXY_HTTP,
+				Server: "proxy.example.com", Port: 3128},
+		},
+		"Username and password": {
+			proxy: &zconfig.ProxyServer{Proto: zconfig.ProxyProto_PROXY_HTTP,
+				Server: "proxy.example.com", Port: 3128,
+				Username: "user1", CipherData: cipherData},
+			expectedUsername: "user1",
+			expectedIsCipher: true,
+		},
+		"Username without password": {
+			proxy: &zconfig.ProxyServer{Proto: zconfig.ProxyProto_PROXY_HTTP,
+				Server: "proxy.example.com", Port: 3128,
+				Username: "user1"},
+			expectedError: true,
+		},
+		"Username with incomplete password": {
+			proxy: &zconfig.ProxyServer{Proto: zconfig.ProxyProto_PROXY_HTTPS,
+				Server: "proxy.example.com", Port: 3128,
+				Username:   "user1",
+				CipherData: &zconfig.CipherBlock{CipherData: []byte{0x01}}},
+			expectedError: true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		getconfigCtx.zedagentCtx = &zedagentContext{
+			getconfigCtx: getconfigCtx,
+			physicalIoAdapterMap: map[string]types.PhysicalIOAdapter{
+				"eth0": {
+					Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+					Phylabel:     "eth0",
+					Logicallabel: "eth0",
+					Phyaddr:      types.PhysicalAddress{Ifname: "eth0"},
+				},
+			},
+		}
+		network := parseOneNetworkXObjectConfig(getconfigCtx,
+			&zconfig.NetworkConfig{
+				Id:   netID,
+				Type: zconfig.NetworkType_V4,
+				Ip:   &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client},
+				EntProxy: &zconfig.ProxyConfig{
+					Proxies: []*zconfig.ProxyServer{test.proxy},
+				},
+			})
+		assert.Equal(t, test.expectedError, network.HasError(), testname)
+		if test.expectedError {
+			continue
+		}
+		getconfigCtx.pubNetworkXObjectConfig.Publish(network.Key(), *network)
+
+		// The credentials are carried into the port
+		port, err := parseOneSystemAdapterConfig(getconfigCtx,
+			&zconfig.SystemAdapter{Name: "eth0", Uplink: true,
+				NetworkUUID: netID},
+			types.DPCIsMgmt)
+		assert.Nil(t, err, testname)
+		assert.NotNil(t, port, testname)
+		assert.False(t, port.HasError(), testname)
+		assert.Equal(t, 1, len(port.ProxyConfig.Proxies), testname)
+		if len(port.ProxyConfig.Proxies) != 1 {
+			continue
+		}
+		proxy := port.ProxyConfig.Proxies[0]
+		assert.Equal(t, test.proxy.Server, proxy.Server, testname)
+		assert.Equal(t, test.proxy.Port, proxy.Port, testname)
+		assert.Equal(t, test.expectedUsername, proxy.Username, testname)
+		assert.Equal(t, test.expectedIsCipher,
+			proxy.CipherBlockStatus.IsCipher, testname)
+		if test.expectedIsCipher {
+			assert.Equal(t, cipherData.CipherData,
+				proxy.CipherBlockStatus.CipherData, testname)
+		}
+	}
+}
+
+func TestParseSystemAdapterConfigIfNameCollisions(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	type phyio struct {
+		phylabel     string
+		logicallabel string
+		ifname       string
+	}
+	type sysAdapter struct {
+		name           string
+		lowerLayerName string
+	}
+	testMatrix := map[string]struct {
+		phyios         []phyio
+		sysAdapters    []sysAdapter
+		expectedIfName map[string]string
+		expectedFailed []string
+	}{
+		"No collision": {
+			phyios: []phyio{
+				{"eth0", "eth0", "eth0"},
+				{"eth1", "eth1", ""},
+			},
+			sysAdapters:    []sysAdapter{{"eth0", ""}, {"eth1", ""}},
+			expectedIfName: map[string]string{"eth0": "eth0", "eth1": "eth1"},
+		},
+		"Label vs real ifname of port": {
+			phyios: []phyio{
+				{"eth0", "lan", "eth0"},
+				{"wan", "eth0", ""},
+			},
+			sysAdapters:    []sysAdapter{{"lan", ""}, {"eth0", ""}},
+			expectedIfName: map[string]string{"lan": "eth0", "eth0": "eth0"},
+			expectedFailed: []string{"eth0"},
+		},
+		"Label vs real ifname of unused phyio": {
+			phyios: []phyio{
+				{"eth1", "lan1", "eth1"},
+				{"wan", "uplink", "eth0"},
+				{"lte", "eth1", ""},
+			},
+			sysAdapters:    []sysAdapter{{"uplink", ""}, {"eth1", ""}},
+			expectedIfName: map[string]string{"uplink": "eth0", "eth1": "eth1"},
+			expectedFailed: []string{"eth1"},
+		},
+		"Label vs label": {
+			phyios: []phyio{
+				{"modem", "wwan0", ""},
+				{"wwan0", "", ""},
+			},
+			sysAdapters:    []sysAdapter{{"wwan0", ""}, {"lte", "wwan0"}},
+			expectedIfName: map[string]string{"wwan0": "wwan0", "lte": "wwan0"},
+			expectedFailed: []string{"wwan0", "lte"},
+		},
+		"Same phyio twice": {
+			phyios: []phyio{
+				{"eth0", "eth0", "eth0"},
+			},
+			sysAdapters:    []sysAdapter{{"eth0", ""}, {"other", "eth0"}},
+			expectedIfName: map[string]string{"eth0": "eth0", "other": "eth0"},
+			expectedFailed: []string{"eth0", "other"},
+		},
+		"Same adapter twice": {
+			phyios: []phyio{
+				{"eth0", "eth0", "eth0"},
+			},
+			sysAdapters:    []sysAdapter{{"eth0", ""}, {"eth0", ""}},
+			expectedIfName: map[string]string{"eth0": "eth0"},
+			expectedFailed: []string{"eth0"},
+		},
+		"Same label adapter twice": {
+			phyios: []phyio{
+				{"modem", "wwan0", ""},
+			},
+			sysAdapters:    []sysAdapter{{"wwan0", ""}, {"wwan0", ""}},
+			expectedIfName: map[string]string{"wwan0": "wwan0"},
+			expectedFailed: []string{"wwan0"},
+		},
+		"Same adapter twice and another port": {
+			phyios: []phyio{
+				{"eth0", "eth0", "eth0"},
+			},
+			sysAdapters: []sysAdapter{{"eth0", ""}, {"eth0", ""},
+				{"other", "eth0"}},
+			expectedIfName: map[string]string{"eth0": "eth0", "other": "eth0"},
+			expectedFailed: []string{"eth0", "eth0", "other"},
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		getconfigCtx.zedagentCtx = &zedagentContext{
+			getconfigCtx:         getconfigCtx,
+			globalConfig:         *types.DefaultConfigItemValueMap(),
+			physicalIoAdapterMap: make(map[string]types.PhysicalIOAdapter),
+		}
+		deviceIoListPrevConfigHash = nil
+		networkConfigPrevConfigHash = nil
+		systemAdaptersPrevConfigHash = nil
+		config := &zconfig.EdgeDevConfig{
+			Networks: []*zconfig.NetworkConfig{
+				{Id: netID, Type: zconfig.NetworkType_V4,
+					Ip: &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client}},
+			},
+		}
+		for _, p := range test.phyios {
+			phyAddrs := make(map[string]string)
+			if p.ifname != "" {
+				phyAddrs["ifname"] = p.ifname
+			}
+			config.DeviceIoList = append(config.DeviceIoList,
+				&zconfig.PhysicalIO{
+					Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+					Phylabel:     p.phylabel,
+					Logicallabel: p.logicallabel,
+					Phyaddrs:     phyAddrs,
+				})
+		}
+		for _, a := range test.sysAdapters {
+			config.SystemAdapterList = append(config.SystemAdapterList,
+				&zconfig.SystemAdapter{
+					Name:           a.name,
+					LowerLayerName: a.lowerLayerName,
+					Uplink:         true,
+					NetworkUUID:    netID,
+				})
+		}
+		parseDeviceIoListConfig(config, getconfigCtx)
+		parseNetworkXObjectConfig(config, getconfigCtx)
+		parseSystemAdapterConfig(config, getconfigCtx, true)
+
+		ports := getconfigCtx.devicePortConfig.Ports
+		assert.Equal(t, len(test.sysAdapters), len(ports), testname)
+		var failed []string
+		for _, port := range ports {
+			assert.Equal(t, test.expectedIfName[port.Logicallabel],
+				port.IfName, "%s: %s", testname, port.Logicallabel)
+			if port.HasError() {
+				failed = append(failed, port.Logicallabel)
+			}
+		}
+		assert.Equal(t, test.expectedFailed, failed, testname)
+	}
+}
+
+func TestParseSystemAdapterConfigDuplicates(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	resetPrevConfigHashes()
+	getconfigCtx, _ := initGoldenConfigCtx(t)
+	config := &zconfig.EdgeDevConfig{
+		DeviceIoList: []*zconfig.PhysicalIO{
+			{
+				Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+				Phylabel:     "eth0",
+				Logicallabel: "eth0",
+				Phyaddrs:     map[string]string{"ifname": "eth0"},
+			},
+		},
+		Networks: []*zconfig.NetworkConfig{
+			{Id: netID, Type: zconfig.NetworkType_V4,
+				Ip: &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client}},
+		},
+		SystemAdapterList: []*zconfig.SystemAdapter{
+			{Name: "eth0", Uplink: true, NetworkUUID: netID},
+			{Name: "eth0", Uplink: true, NetworkUUID: netID, Cost: 5},
+		},
+	}
+	parseConfigObjects(config, getconfigCtx, false)
+
+	ports := getconfigCtx.devicePortConfig.Ports
+	assert.Equal(t, 2, len(ports))
+	assert.False(t, ports[0].HasError(), ports[0].LastError)
+	assert.Equal(t, uint8(0), ports[0].Cost)
+	assert.True(t, ports[1].HasError())
+	assert.Contains(t, ports[1].LastError, "configured more than once")
+	assert.False(t, getconfigCtx.devicePortConfig.HasError())
+
+	item, err := getconfigCtx.pubSystemAdapterReport.Get("global")
+	assert.Nil(t, err)
+	report := item.(types.SystemAdapterReport)
+	assert.Equal(t, 2, len(report.Results))
+	assert.Equal(t, types.SystemAdapterAccepted, report.Results[0].Disposition)
+	assert.Equal(t, types.SystemAdapterAcceptedWithError,
+		report.Results[1].Disposition)
+}
+
+func TestParseSystemAdapterConfigParseStatus(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	resetPrevConfigHashes()
+	getconfigCtx, _ := initGoldenConfigCtx(t)
+	config := &zconfig.EdgeDevConfig{
+		DeviceIoList: []*zconfig.PhysicalIO{
+			{
+				Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+				Phylabel:     "eth0",
+				Logicallabel: "eth0",
+				Phyaddrs:     map[string]string{"ifname": "eth0"},
+			},
+		},
+		Networks: []*zconfig.NetworkConfig{
+			{Id: netID, Type: zconfig.NetworkType_V4,
+				Ip: &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client}},
+		},
+		SystemAdapterList: []*zconfig.SystemAdapter{
+			{Name: "eth0", Uplink: true, NetworkUUID: netID},
+			{Name: "eth0", Uplink: true, NetworkUUID: netID, Cost: 5},
+			{Name: "eth1", Uplink: true, NetworkUUID: netID},
+		},
+	}
+	getStatus := func() types.DevicePortConfigParseStatus {
+		item, err := getconfigCtx.pubDevicePortConfigParseStatus.Get("global")
+		assert.Nil(t, err)
+		return item.(types.DevicePortConfigParseStatus)
+	}
+	parseConfigObjects(config, getconfigCtx, false)
+
+	status := getStatus()
+	assert.Equal(t, hex.EncodeToString(systemAdaptersPrevConfigHash),
+		status.ConfigSha)
+	assert.Equal(t, 1, status.OkCount)
+	assert.Equal(t, 1, status.WarningCount)
+	assert.Equal(t, 1, status.ErrorCount)
+	assert.Equal(t, 2, len(status.Failures))
+	assert.Equal(t, "eth0", status.Failures[0].Logicallabel)
+	assert.Equal(t, types.SystemAdapterAcceptedWithError,
+		status.Failures[0].Disposition)
+	assert.Contains(t, status.Failures[0].Error, "configured more than once")
+	assert.Equal(t, "eth1", status.Failures[1].Logicallabel)
+	assert.Equal(t, types.SystemAdapterRejected,
+		status.Failures[1].Disposition)
+	assert.Contains(t, status.Failures[1].Error, "Missing phyio")
+
+	// Also published without any system adapters
+	config.SystemAdapterList = nil
+	parseConfigObjects(config, getconfigCtx, false)
+	status = getStatus()
+	assert.Equal(t, hex.EncodeToString(systemAdaptersPrevConfigHash),
+		status.ConfigSha)
+	assert.Equal(t, types.DevicePortConfigParseStatus{
+		ConfigSha: status.ConfigSha}, status)
+}
+
+func TestParseSystemAdapterConfigAllPortsFailed(t *testing.T) {
+	const (
+		netID     = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+		missingID = "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	)
+	testMatrix := map[string]struct {
+		prevNetworkUUID string
+		networkUUID     string
+		expectedHeld    bool
+	}{
+		"Previous one working": {
+			prevNetworkUUID: netID,
+			networkUUID:     missingID,
+			expectedHeld:    true,
+		},
+		"Previous one failed too": {
+			prevNetworkUUID: missingID,
+			networkUUID:     missingID,
+		},
+		"New one working": {
+			prevNetworkUUID: netID,
+			networkUUID:     netID,
+		},
+	}
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		resetPrevConfigHashes()
+		getconfigCtx, _ := initGoldenConfigCtx(t)
+		config := &zconfig.EdgeDevConfig{
+			DeviceIoList: []*zconfig.PhysicalIO{
+				{
+					Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+					Phylabel:     "eth0",
+					Logicallabel: "eth0",
+					Phyaddrs:     map[string]string{"ifname": "eth0"},
+				},
+			},
+			Networks: []*zconfig.NetworkConfig{
+				{Id: netID, Type: zconfig.NetworkType_V4,
+					Ip: &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client}},
+			},
+			SystemAdapterList: []*zconfig.SystemAdapter{
+				{Name: "eth0", Uplink: true, NetworkUUID: test.prevNetworkUUID},
+			},
+		}
+		parseConfigObjects(config, getconfigCtx, false)
+		prevPortConfig := getconfigCtx.devicePortConfig
+
+		config.SystemAdapterList = []*zconfig.SystemAdapter{
+			{Name: "eth0", Uplink: true, NetworkUUID: test.networkUUID,
+				Cost: 1},
+		}
+		parseConfigObjects(config, getconfigCtx, false)
+		item, err := getconfigCtx.pubDevicePortConfigParseStatus.Get("global")
+		assert.Nil(t, err, testname)
+		status := item.(types.DevicePortConfigParseStatus)
+		assert.Equal(t, test.expectedHeld, status.Held, testname)
+		item, err = getconfigCtx.pubDevicePortConfig.Get("zedagent")
+		assert.Nil(t, err, testname)
+		published := item.(types.DevicePortConfig)
+		if test.expectedHeld {
+			assert.Equal(t, prevPortConfig, getconfigCtx.devicePortConfig,
+				testname)
+			assert.Equal(t, uint8(0), published.Ports[0].Cost, testname)
+		} else {
+			assert.Equal(t, uint8(1), published.Ports[0].Cost, testname)
+		}
+	}
+}
+
+func TestParseSystemAdapterConfigAddrsChange(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	resetPrevConfigHashes()
+	getconfigCtx, _ := initGoldenConfigCtx(t)
+	config := &zconfig.EdgeDevConfig{
+		DeviceIoList: []*zconfig.PhysicalIO{
+			{
+				Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+				Phylabel:     "eth0",
+				Logicallabel: "eth0",
+				Phyaddrs:     map[string]string{"ifname": "eth0"},
+			},
+		},
+		Networks: []*zconfig.NetworkConfig{
+			{Id: netID, Type: zconfig.NetworkType_V4,
+				Ip: &zconfig.Ipspec{
+					Dhcp:         zconfig.DHCPType_Static,
+					Subnet:       "192.168.1.0/24",
+					ExtraSubnets: []string{"fd00::/64"},
+				}},
+		},
+		SystemAdapterList: []*zconfig.SystemAdapter{
+			{Name: "eth0", Uplink: true, NetworkUUID: netID,
+				Addrs: []string{"192.168.1.10", "fd00::10"}},
+		},
+	}
+	parseConfigObjects(config, getconfigCtx, false)
+	timePriority := getconfigCtx.devicePortConfig.TimePriority
+	assert.Equal(t, []string{"192.168.1.10/24", "fd00::10/64"},
+		getconfigCtx.devicePortConfig.Ports[0].AddrSubnets)
+
+	// Parsed again without a change
+	resetPrevConfigHashes()
+	parseConfigObjects(config, getconfigCtx, false)
+	assert.Equal(t, timePriority, getconfigCtx.devicePortConfig.TimePriority)
+
+	// Only the second address changes
+	config.SystemAdapterList[0].Addrs = []string{"192.168.1.10", "fd00::20"}
+	parseConfigObjects(config, getconfigCtx, false)
+	assert.NotEqual(t, timePriority,
+		getconfigCtx.devicePortConfig.TimePriority)
+	item, err := getconfigCtx.pubDevicePortConfig.Get("zedagent")
+	assert.Nil(t, err)
+	published := item.(types.DevicePortConfig)
+	assert.Equal(t, []string{"192.168.1.10/24", "fd00::20/64"},
+		published.Ports[0].AddrSubnets)
+	assert.Equal(t, "192.168.1.10/24", published.Ports[0].AddrSubnet)
+}
+
+func TestParseNetworkWirelessConfigKeyScheme(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	testMatrix := map[string]struct {
+		keyScheme         zconfig.WiFiKeyScheme
+		expectedKeyScheme types.WifiKeySchemeType
+		expectedError     bool
+	}{
+		"No key scheme": {
+			keyScheme:         zconfig.WiFiKeyScheme_SchemeNOOP,
+			expectedKeyScheme: types.KeySchemeNone,
+		},
+		"WPA-PSK": {
+			keyScheme:         zconfig.WiFiKeyScheme_WPAPSK,
+			expectedKeyScheme: types.KeySchemeWpaPsk,
+		},
+		"WPA-EAP": {
+			keyScheme:         zconfig.WiFiKeyScheme_WPAEAP,
+			expectedKeyScheme: types.KeySchemeWpaEap,
+		},
+		"WPA3-SAE": {
+			keyScheme:         zconfig.WiFiKeyScheme_WPA3SAE,
+			expectedKeyScheme: types.KeySchemeWpa3Sae,
+		},
+		"Unknown key scheme": {
+			keyScheme:         zconfig.WiFiKeyScheme(100),
+			expectedKeyScheme: types.KeySchemeOther,
+			expectedError:     true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		netEnt := &zconfig.NetworkConfig{
+			Id:   netID,
+			Type: zconfig.NetworkType_V4,
+			Ip:   &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client},
+			Wireless: &zconfig.WirelessConfig{
+				Type: zconfig.WirelessType_WiFi,
+				WifiCfg: []*zconfig.WifiConfig{
+					{WifiSSID: "ssid1", KeyScheme: test.keyScheme},
+				},
+			},
+		}
+		wconfig := parseNetworkWirelessConfig(getconfigCtx, netID, netEnt)
+		assert.Equal(t, types.WirelessTypeWifi, wconfig.WType, testname)
+		assert.Equal(t, 1, len(wconfig.Wifi), testname)
+		assert.Equal(t, test.expectedKeyScheme, wconfig.Wifi[0].KeyScheme,
+			testname)
+		assert.Equal(t, test.expectedError, wconfig.HasError(), testname)
+
+		network := parseOneNetworkXObjectConfig(getconfigCtx, netEnt)
+		assert.Equal(t, test.expectedError, network.HasError(), testname)
+	}
+}
+
+func TestParseOneNetworkXObjectConfigWPAD(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	explicitProxies := []*zconfig.ProxyServer{
+		{Proto: zconfig.ProxyProto_PROXY_HTTP,
+			Server: "proxy.example.com", Port: 3128},
+	}
+	testMatrix := map[string]struct {
+		proxyConfig        *zconfig.ProxyConfig
+		expectedEnable     bool
+		expectedURL        string
+		expectedProxyCount int
+	}{
+		"Explicit proxies only": {
+			proxyConfig: &zconfig.ProxyConfig{
+				Proxies: explicitProxies,
+			},
+			expectedProxyCount: 1,
+		},
+		"WPAD only": {
+			proxyConfig: &zconfig.ProxyConfig{
+				NetworkProxyEnable: true,
+			},
+			expectedEnable: true,
+		},
+		"WPAD with URL": {
+			proxyConfig: &zconfig.ProxyConfig{
+				NetworkProxyEnable: true,
+				NetworkProxyURL:    "http://wpad.example.com/wpad.dat",
+			},
+			expectedEnable: true,
+			expectedURL:    "http://wpad.example.com/wpad.dat",
+		},
+		"WPAD and explicit proxies": {
+			proxyConfig: &zconfig.ProxyConfig{
+				NetworkProxyEnable: true,
+				NetworkProxyURL:    "http://wpad.example.com/wpad.dat",
+				Proxies:            explicitProxies,
+			},
+			expectedProxyCount: 1,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		getconfigCtx.zedagentCtx = &zedagentContext{
+			getconfigCtx: getconfigCtx,
+			physicalIoAdapterMap: map[string]types.PhysicalIOAdapter{
+				"eth0": {
+					Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+					Phylabel:     "eth0",
+					Logicallabel: "eth0",
+					Phyaddr:      types.PhysicalAddress{Ifname: "eth0"},
+				},
+			},
+		}
+		network := parseOneNetworkXObjectConfig(getconfigCtx,
+			&zconfig.NetworkConfig{
+				Id:       netID,
+				Type:     zconfig.NetworkType_V4,
+				Ip:       &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client},
+				EntProxy: test.proxyConfig,
+			})
+		assert.False(t, network.HasError(), testname)
+		getconfigCtx.pubNetworkXObjectConfig.Publish(network.Key(), *network)
+
+		port, err := parseOneSystemAdapterConfig(getconfigCtx,
+			&zconfig.SystemAdapter{Name: "eth0", Uplink: true,
+				NetworkUUID: netID},
+			types.DPCIsMgmt)
+		assert.Nil(t, err, testname)
+		assert.NotNil(t, port, testname)
+		assert.Equal(t, test.expectedEnable,
+			port.ProxyConfig.NetworkProxyEnable, testname)
+		assert.Equal(t, test.expectedURL,
+			port.ProxyConfig.NetworkProxyURL, testname)
+		assert.Equal(t, test.expectedProxyCount,
+			len(port.ProxyConfig.Proxies), testname)
+	}
+}
+
+func TestParseAppInstanceConfigVolumeRetention(t *testing.T) {
+	uuidA := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	volumeID := "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	volumeKey := volumeID + "#3"
+	newApp := func(retentionDays uint32) *zconfig.AppInstanceConfig {
+		app := newTestAppInstance(uuidA, "appA")
+		app.VolumeRetentionDays = retentionDays
+		app.VolumeRefList = []*zconfig.VolumeRef{
+			{Uuid: volumeID, GenerationCount: 3},
+		}
+		return app
+	}
+	parseApps := func(getconfigCtx *getconfigContext,
+		apps ...*zconfig.AppInstanceConfig) {
+		parseAppInstanceConfig(&zconfig.EdgeDevConfig{Apps: apps},
+			getconfigCtx)
+	}
+	getRetention := func(getconfigCtx *getconfigContext) *types.AppVolumeRetention {
+		c, _ := getconfigCtx.pubAppVolumeRetention.Get(uuidA)
+		if c == nil {
+			return nil
+		}
+		retention := c.(types.AppVolumeRetention)
+		return &retention
+	}
+
+	testMatrix := map[string]struct {
+		retentionDays         uint32
+		expectedRetentionDays uint32
+		expectedError         bool
+	}{
+		"No retention": {},
+		"Retention": {
+			retentionDays:         7,
+			expectedRetentionDays: 7,
+		},
+		"Retention above maximum": {
+			retentionDays:         types.MaxVolumeRetentionDays + 1,
+			expectedRetentionDays: types.MaxVolumeRetentionDays,
+			expectedError:         true,
+		},
+	}
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		appinstancePrevConfigHash = nil
+		appinstancePrevElementHash = make(map[string][]byte)
+
+		parseApps(getconfigCtx, newApp(test.retentionDays))
+		c, err := getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+		assert.Nil(t, err, testname)
+		appInstance := c.(types.AppInstanceConfig)
+		assert.Equal(t, test.expectedRetentionDays,
+			appInstance.VolumeRetentionDays, testname)
+		assert.Equal(t, test.expectedError, len(appInstance.Errors) != 0,
+			testname)
+		retention := getRetention(getconfigCtx)
+		if test.expectedRetentionDays == 0 {
+			assert.Nil(t, retention, testname)
+			continue
+		}
+		assert.NotNil(t, retention, testname)
+		assert.Equal(t, []string{volumeKey}, retention.VolumeKeys, testname)
+		assert.True(t, retention.Expiry.IsZero(), testname)
+	}
+
+	t.Logf("Running removal and restore")
+	getconfigCtx := initGetConfigCtx(t)
+	appinstancePrevConfigHash = nil
+	appinstancePrevElementHash = make(map[string][]byte)
+	parseApps(getconfigCtx, newApp(7))
+
+	// Removing the app sets the expiry
+	before := time.Now()
+	parseApps(getconfigCtx)
+	c, _ := getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+	assert.Nil(t, c)
+	retention := getRetention(getconfigCtx)
+	assert.NotNil(t, retention)
+	assert.False(t, retention.Expiry.Before(before.Add(7*24*time.Hour)))
+	assert.True(t, retention.Expiry.Before(time.Now().Add(7*24*time.Hour+time.Minute)))
+	expiry := retention.Expiry
+
+	// Another config without the app keeps the expiry
+	expireAppVolumeRetention(getconfigCtx, nil, time.Now())
+	retention = getRetention(getconfigCtx)
+	assert.NotNil(t, retention)
+	assert.Equal(t, expiry, retention.Expiry)
+
+	// Adding the app back within the window retains the volumes again
+	// without a time limit
+	parseApps(getconfigCtx, newApp(7))
+	retention = getRetention(getconfigCtx)
+	assert.NotNil(t, retention)
+	assert.True(t, retention.Expiry.IsZero())
+	assert.Equal(t, []string{volumeKey}, retention.VolumeKeys)
+
+	// Removing it again and passing the expiry unpublishes the retention
+	parseApps(getconfigCtx)
+	assert.NotNil(t, getRetention(getconfigCtx))
+	expireAppVolumeRetention(getconfigCtx, nil,
+		time.Now().Add(8*24*time.Hour))
+	assert.Nil(t, getRetention(getconfigCtx))
+}
+
+func TestParseNetworkWirelessConfigPriority(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	type ssidPriority struct {
+		ssid     string
+		priority int32
+	}
+	testMatrix := map[string]struct {
+		wifis         []ssidPriority
+		expectedOrder []string
+		expectedError bool
+	}{
+		"Single SSID": {
+			wifis:         []ssidPriority{{"ssid1", 0}},
+			expectedOrder: []string{"ssid1"},
+		},
+		"Sorted by descending priority": {
+			wifis: []ssidPriority{
+				{"ssid1", 1}, {"ssid2", 3}, {"ssid3", 2}},
+			expectedOrder: []string{"ssid2", "ssid3", "ssid1"},
+		},
+		"Duplicate priority": {
+			wifis: []ssidPriority{
+				{"ssid1", 1}, {"ssid2", 2}, {"ssid3", 1}},
+			expectedOrder: []string{"ssid2", "ssid1", "ssid3"},
+		},
+		"Default priority": {
+			wifis: []ssidPriority{
+				{"ssid1", 0}, {"ssid2", 0}, {"ssid3", 0}},
+			expectedOrder: []string{"ssid1", "ssid2", "ssid3"},
+		},
+		"Duplicate SSID": {
+			wifis: []ssidPriority{
+				{"ssid1", 1}, {"ssid2", 2}, {"ssid1", 3}},
+			expectedOrder: []string{"ssid1", "ssid2", "ssid1"},
+			expectedError: true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		var wifiCfgs []*zconfig.WifiConfig
+		for _, wifi := range test.wifis {
+			wifiCfgs = append(wifiCfgs, &zconfig.WifiConfig{
+				WifiSSID:  wifi.ssid,
+				KeyScheme: zconfig.WiFiKeyScheme_WPAPSK,
+				Priority:  wifi.priority,
+			})
+		}
+		netEnt := &zconfig.NetworkConfig{
+			Id:   netID,
+			Type: zconfig.NetworkType_V4,
+			Ip:   &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client},
+			Wireless: &zconfig.WirelessConfig{
+				Type:    zconfig.WirelessType_WiFi,
+				WifiCfg: wifiCfgs,
+			},
+		}
+		wconfig := parseNetworkWirelessConfig(getconfigCtx, netID, netEnt)
+		var order []string
+		for _, wifi := range wconfig.Wifi {
+			order = append(order, wifi.SSID)
+		}
+		assert.Equal(t, test.expectedOrder, order, testname)
+		assert.Equal(t, test.expectedError, wconfig.HasError(), testname)
+	}
+}
+
+func TestParseNetworkWirelessConfigCellularAuth(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	cipherData := &zconfig.CipherBlock{
+		CipherContextId: "6ba7b811-9dad-11d1-80b4-00c04fd430c8",
+		CipherData:      []byte{0x01, 0x02, 0x03},
+	}
+	testMatrix := map[string]struct {
+		cellular         *zconfig.CellularConfig
+		expectedError    bool
+		expectedAuth     types.CellularAuthProtocol
+		expectedIsCipher bool
+	}{
+		"No authentication": {
+			cellular:     &zconfig.CellularConfig{APN: "internet"},
+			expectedAuth: types.CellularAuthNone,
+		},
+		"PAP": {
+			cellular: &zconfig.CellularConfig{APN: "private.apn",
+				Username:     "user1",
+				AuthProtocol: zconfig.CellularAuthProtocol_CELLULAR_AUTH_PROTOCOL_PAP,
+				CipherData:   cipherData},
+			expectedAuth:     types.CellularAuthPAP,
+			expectedIsCipher: true,
+		},
+		"CHAP": {
+			cellular: &zconfig.CellularConfig{APN: "private.apn",
+				Username:     "user1",
+				AuthProtocol: zconfig.CellularAuthProtocol_CELLULAR_AUTH_PROTOCOL_CHAP,
+				CipherData:   cipherData},
+			expectedAuth:     types.CellularAuthCHAP,
+			expectedIsCipher: true,
+		},
+		"PAP and CHAP": {
+			cellular: &zconfig.CellularConfig{APN: "private.apn",
+				Username:     "user1",
+				AuthProtocol: zconfig.CellularAuthProtocol_CELLULAR_AUTH_PROTOCOL_PAP_AND_CHAP,
+				CipherData:   cipherData},
+			expectedAuth:     types.CellularAuthPAPAndCHAP,
+			expectedIsCipher: true,
+		},
+		"Username without authentication protocol": {
+			cellular: &zconfig.CellularConfig{APN: "private.apn",
+				Username: "user1", CipherData: cipherData},
+			expectedError: true,
+		},
+		"Authentication without username": {
+			cellular: &zconfig.CellularConfig{APN: "private.apn",
+				AuthProtocol: zconfig.CellularAuthProtocol_CELLULAR_AUTH_PROTOCOL_PAP,
+				CipherData:   cipherData},
+			expectedError: true,
+			expectedAuth:  types.CellularAuthPAP,
+		},
+		"Authentication without password": {
+			cellular: &zconfig.CellularConfig{APN: "private.apn",
+				Username:     "user1",
+				AuthProtocol: zconfig.CellularAuthProtocol_CELLULAR_AUTH_PROTOCOL_CHAP},
+			expectedError: true,
+			expectedAuth:  types.CellularAuthCHAP,
+		},
+		"Unsupported authentication protocol": {
+			cellular: &zconfig.CellularConfig{APN: "private.apn",
+				Username: "user1", AuthProtocol: 100,
+				CipherData: cipherData},
+			expectedError: true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		netEnt := &zconfig.NetworkConfig{
+			Id:   netID,
+			Type: zconfig.NetworkType_V4,
+			Ip:   &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client},
+			Wireless: &zconfig.WirelessConfig{
+				Type:        zconfig.WirelessType_Cellular,
+				CellularCfg: []*zconfig.CellularConfig{test.cellular},
+			},
+		}
+		wconfig := parseNetworkWirelessConfig(getconfigCtx, netID, netEnt)
+		assert.Equal(t, types.WirelessTypeCellular, wconfig.WType, testname)
+		assert.Equal(t, test.expectedError, wconfig.HasError(), testname)
+		assert.Equal(t, 1, len(wconfig.Cellular), testname)
+		if len(wconfig.Cellular) != 1 {
+			continue
+		}
+		cell := wconfig.Cellular[0]
+		assert.Equal(t, test.cellular.APN, cell.APN, testname)
+		assert.Equal(t, test.cellular.Username, cell.Username, testname)
+		assert.Equal(t, test.expectedAuth, cell.AuthProtocol, testname)
+		assert.Equal(t, test.expectedIsCipher,
+			cell.CipherBlockStatus.IsCipher, testname)
+		if test.expectedIsCipher {
+			assert.Equal(t, cipherData.CipherData,
+				cell.CipherBlockStatus.CipherData, testname)
+		}
+	}
+}
+
+func TestParseProxyExceptions(t *testing.T) {
+	testMatrix := map[string]struct {
+		exceptions    string
+		expectedList  []types.ProxyException
+		expectedError bool
+	}{
+		"Empty": {},
+		"Hosts and suffixes": {
+			exceptions: "Host.Example.com, .example.org *.example.net",
+			expectedList: []types.ProxyException{
+				{Type: types.ProxyExceptionHost, Value: "host.example.com"},
+				{Type: types.ProxyExceptionHostSuffix, Value: ".example.org"},
+				{Type: types.ProxyExceptionHostSuffix, Value: ".example.net"},
+			},
+		},
+		"Trailing dot hostnames": {
+			exceptions: "host.example.com.,.example.org.",
+			expectedList: []types.ProxyException{
+				{Type: types.ProxyExceptionHost, Value: "host.example.com"},
+				{Type: types.ProxyExceptionHostSuffix, Value: ".example.org"},
+			},
+		},
+		"IP addresses and CIDRs": {
+			exceptions: "10.1.2.3,192.168.1.77/24, fd00::1 2001:DB8::/32",
+			expectedList: []types.ProxyException{
+				{Type: types.ProxyExceptionIP, Value: "10.1.2.3"},
+				{Type: types.ProxyExceptionCIDR, Value: "192.168.1.0/24"},
+				{Type: types.ProxyExceptionIP, Value: "fd00::1"},
+				{Type: types.ProxyExceptionCIDR, Value: "2001:db8::/32"},
+			},
+		},
+		"Ports": {
+			exceptions: "host.example.com:8080,10.1.2.3:443,[fd00::1]:80",
+			expectedList: []types.ProxyException{
+				{Type: types.ProxyExceptionHost, Value: "host.example.com",
+					Port: 8080},
+				{Type: types.ProxyExceptionIP, Value: "10.1.2.3", Port: 443},
+				{Type: types.ProxyExceptionIP, Value: "fd00::1", Port: 80},
+			},
+		},
+		"Wildcard": {
+			exceptions: "*",
+			expectedList: []types.ProxyException{
+				{Type: types.ProxyExceptionAll},
+			},
+		},
+		"Bad IPv6 CIDR": {
+			exceptions:    "2001:db8::/129",
+			expectedError: true,
+		},
+		"Bad host name": {
+			exceptions: "host.example.com,bad_host!",
+			expectedList: []types.ProxyException{
+				{Type: types.ProxyExceptionHost, Value: "host.example.com"},
+			},
+			expectedError: true,
+		},
+		"Bad port": {
+			exceptions:    "host.example.com:http",
+			expectedError: true,
+		},
+		"Only dots": {
+			exceptions:    "..",
+			expectedError: true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		list, err := parseProxyExceptions(test.exceptions)
+		assert.Equal(t, test.expectedError, err != nil, testname)
+		assert.Equal(t, test.expectedList, list, testname)
+	}
+}
+
+func TestParseOneNetworkXObjectConfigProxyExceptions(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	testMatrix := map[string]struct {
+		exceptions    string
+		expectedCount int
+	}{
+		"Valid exceptions": {
+			exceptions:    ".example.com,fd00::/8",
+			expectedCount: 2,
+		},
+		"Garbage exception": {
+			exceptions:    ".example.com,[fd00::/8",
+			expectedCount: 1,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		netEnt := &zconfig.NetworkConfig{
+			Id:   netID,
+			Type: zconfig.NetworkType_V4,
+			Ip:   &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client},
+			EntProxy: &zconfig.ProxyConfig{
+				Exceptions: test.exceptions,
+			},
+		}
+		network := parseOneNetworkXObjectConfig(getconfigCtx, netEnt)
+		// A bad exception is left out without failing the network
+		assert.False(t, network.HasError(), testname)
+		assert.Equal(t, test.exceptions, network.Proxy.Exceptions, testname)
+		assert.Equal(t, test.expectedCount, len(network.Proxy.ExceptionList),
+			testname)
+	}
+}
+
+func TestParseOneNetworkXObjectConfigPacfile(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	pac := "function FindProxyForURL(url, host) { return \"DIRECT\"; }"
+	pacfile := base64.StdEncoding.EncodeToString([]byte(pac))
+	largePacfile := base64.StdEncoding.EncodeToString(
+		[]byte(strings.Repeat(" ", 64*1024+1)))
+	proxies := []*zconfig.ProxyServer{
+		{Proto: zconfig.ProxyProto_PROXY_HTTP,
+			Server: "proxy.example.com", Port: 3128},
+	}
+	testMatrix := map[string]struct {
+		proxyConfig   *zconfig.ProxyConfig
+		maxBytes      uint32
+		expectedError bool
+	}{
+		"PAC file": {
+			proxyConfig: &zconfig.ProxyConfig{Pacfile: pacfile},
+		},
+		"Not base64": {
+			proxyConfig:   &zconfig.ProxyConfig{Pacfile: pac},
+			expectedError: true,
+		},
+		"Exceeds default limit": {
+			proxyConfig:   &zconfig.ProxyConfig{Pacfile: largePacfile},
+			expectedError: true,
+		},
+		"Exceeds configured limit": {
+			proxyConfig:   &zconfig.ProxyConfig{Pacfile: pacfile},
+			maxBytes:      uint32(len(pac) - 1),
+			expectedError: true,
+		},
+		"At configured limit": {
+			proxyConfig: &zconfig.ProxyConfig{Pacfile: pacfile},
+			maxBytes:    uint32(len(pac)),
+		},
+		"PAC file and explicit proxies": {
+			proxyConfig: &zconfig.ProxyConfig{Pacfile: pacfile,
+				Proxies: proxies},
+			expectedError: true,
+		},
+		"PAC file and explicit proxies with WPAD": {
+			proxyConfig: &zconfig.ProxyConfig{Pacfile: pacfile,
+				Proxies: proxies, NetworkProxyEnable: true},
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		if test.maxBytes != 0 {
+			getconfigCtx.zedagentCtx.globalConfig.SetGlobalValueInt(
+				types.NetworkProxyPacfileMaxBytes, test.maxBytes)
+		}
+		netEnt := &zconfig.NetworkConfig{
+			Id:       netID,
+			Type:     zconfig.NetworkType_V4,
+			Ip:       &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client},
+			EntProxy: test.proxyConfig,
+		}
+		network := parseOneNetworkXObjectConfig(getconfigCtx, netEnt)
+		assert.Equal(t, test.expectedError, network.HasError(), testname)
+		if test.expectedError {
+			assert.Nil(t, network.Proxy, testname)
+		} else {
+			assert.Equal(t, pacfile, network.Proxy.Pacfile, testname)
+		}
+	}
+}
+
+func TestParseOneNetworkXObjectConfigProxyPolicy(t *testing.T) {
+	const (
+		netID  = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+		pacURL = "http://wpad.example.com/wpad.dat"
+	)
+	pacfile := base64.StdEncoding.EncodeToString(
+		[]byte("function FindProxyForURL(url, host) { return \"DIRECT\"; }"))
+	proxies := []*zconfig.ProxyServer{
+		{Proto: zconfig.ProxyProto_PROXY_HTTP,
+			Server: "proxy.example.com", Port: 3128},
+	}
+	dhcpClient := &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client}
+	static := &zconfig.Ipspec{
+		Dhcp:    zconfig.DHCPType_Static,
+		Subnet:  "192.168.1.0/24",
+		Gateway: "192.168.1.1",
+	}
+	staticWithDomain := &zconfig.Ipspec{
+		Dhcp:    zconfig.DHCPType_Static,
+		Subnet:  "192.168.1.0/24",
+		Gateway: "192.168.1.1",
+		Domain:  "example.com",
+	}
+	testMatrix := map[string]struct {
+		proxyConfig    *zconfig.ProxyConfig
+		ipspec         *zconfig.Ipspec
+		expectedError  bool
+		expectedPolicy types.ProxyPolicy
+		expectedPac    string
+		expectedURL    string
+		expectedWpad   bool
+		expectedCount  int
+	}{
+		"Default with a PAC file": {
+			proxyConfig: &zconfig.ProxyConfig{Pacfile: pacfile},
+			expectedPac: pacfile,
+		},
+		"Static only": {
+			proxyConfig: &zconfig.ProxyConfig{
+				Policy:             zconfig.ProxyPolicy_PROXY_POLICY_STATIC_ONLY,
+				Proxies:            proxies,
+				Pacfile:            pacfile,
+				NetworkProxyEnable: true,
+			},
+			expectedPolicy: types.ProxyPolicyStaticOnly,
+			expectedCount:  1,
+		},
+		"Static only without proxies": {
+			proxyConfig: &zconfig.ProxyConfig{
+				Policy:  zconfig.ProxyPolicy_PROXY_POLICY_STATIC_ONLY,
+				Pacfile: pacfile,
+			},
+			expectedError: true,
+		},
+		"PAC only": {
+			proxyConfig: &zconfig.ProxyConfig{
+				Policy:  zconfig.ProxyPolicy_PROXY_POLICY_PAC_ONLY,
+				Pacfile: pacfile,
+				Proxies: proxies,
+			},
+			expectedPolicy: types.ProxyPolicyPacOnly,
+			expectedPac:    pacfile,
+		},
+		"PAC only from a URL": {
+			proxyConfig: &zconfig.ProxyConfig{
+				Policy:          zconfig.ProxyPolicy_PROXY_POLICY_PAC_ONLY,
+				NetworkProxyURL: pacURL,
+			},
+			expectedPolicy: types.ProxyPolicyPacOnly,
+			expectedURL:    pacURL,
+			expectedWpad:   true,
+		},
+		"PAC only without a PAC file": {
+			proxyConfig: &zconfig.ProxyConfig{
+				Policy:  zconfig.ProxyPolicy_PROXY_POLICY_PAC_ONLY,
+				Proxies: proxies,
+			},
+			expectedError: true,
+		},
+		"PAC then static": {
+			proxyConfig: &zconfig.ProxyConfig{
+				Policy:  zconfig.ProxyPolicy_PROXY_POLICY_PAC_THEN_STATIC,
+				Pacfile: pacfile,
+				Proxies: proxies,
+			},
+			expectedPolicy: types.ProxyPolicyPacThenStatic,
+			expectedPac:    pacfile,
+			expectedCount:  1,
+		},
+		"PAC then static without a PAC file": {
+			proxyConfig: &zconfig.ProxyConfig{
+				Policy:  zconfig.ProxyPolicy_PROXY_POLICY_PAC_THEN_STATIC,
+				Proxies: proxies,
+			},
+			expectedError: true,
+		},
+		"PAC then static without proxies": {
+			proxyConfig: &zconfig.ProxyConfig{
+				Policy:  zconfig.ProxyPolicy_PROXY_POLICY_PAC_THEN_STATIC,
+				Pacfile: pacfile,
+			},
+			expectedError: true,
+		},
+		"WPAD with DHCP": {
+			proxyConfig: &zconfig.ProxyConfig{
+				Policy:  zconfig.ProxyPolicy_PROXY_POLICY_WPAD,
+				Proxies: proxies,
+			},
+			expectedPolicy: types.ProxyPolicyWpad,
+			expectedWpad:   true,
+		},
+		"WPAD with a static domain": {
+			proxyConfig: &zconfig.ProxyConfig{
+				Policy: zconfig.ProxyPolicy_PROXY_POLICY_WPAD,
+			},
+			ipspec:         staticWithDomain,
+			expectedPolicy: types.ProxyPolicyWpad,
+			expectedWpad:   true,
+		},
+		"WPAD with a URL": {
+			proxyConfig: &zconfig.ProxyConfig{
+				Policy:          zconfig.ProxyPolicy_PROXY_POLICY_WPAD,
+				NetworkProxyURL: pacURL,
+			},
+			ipspec:         static,
+			expectedPolicy: types.ProxyPolicyWpad,
+			expectedURL:    pacURL,
+			expectedWpad:   true,
+		},
+		"WPAD without a URL or a domain": {
+			proxyConfig: &zconfig.ProxyConfig{
+				Policy: zconfig.ProxyPolicy_PROXY_POLICY_WPAD,
+			},
+			ipspec:        static,
+			expectedError: true,
+		},
+		"Unknown policy": {
+			proxyConfig: &zconfig.ProxyConfig{
+				Policy:  zconfig.ProxyPolicy(100),
+				Proxies: proxies,
+			},
+			expectedError: true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		ipspec := test.ipspec
+		if ipspec == nil {
+			ipspec = dhcpClient
+		}
+		netEnt := &zconfig.NetworkConfig{
+			Id:       netID,
+			Type:     zconfig.NetworkType_V4,
+			Ip:       ipspec,
+			EntProxy: test.proxyConfig,
+		}
+		network := parseOneNetworkXObjectConfig(getconfigCtx, netEnt)
+		assert.Equal(t, test.expectedError, network.HasError(), testname)
+		if test.expectedError {
+			assert.Nil(t, network.Proxy, testname)
+			continue
+		}
+		assert.Equal(t, test.expectedPolicy, network.Proxy.Policy, testname)
+		assert.Equal(t, test.expectedPac, network.Proxy.Pacfile, testname)
+		assert.Equal(t, test.expectedURL, network.Proxy.NetworkProxyURL,
+			testname)
+		assert.Equal(t, test.expectedWpad, network.Proxy.NetworkProxyEnable,
+			testname)
+		assert.Equal(t, test.expectedCount, len(network.Proxy.Proxies),
+			testname)
+	}
+}
+
+func TestParseDeviceIoListConfigDuplicateLabels(t *testing.T) {
+	type phyio struct {
+		phylabel     string
+		logicallabel string
+		ifname       string
+	}
+	testMatrix := map[string]struct {
+		phyios          []phyio
+		expectedFlagged []string
+		sysAdapter      string
+		expectedIfName  string
+	}{
+		"Unique labels": {
+			phyios: []phyio{
+				{"eth0", "uplink", "eth0"},
+				{"eth1", "lan", "eth1"},
+			},
+			sysAdapter:     "uplink",
+			expectedIfName: "eth0",
+		},
+		"Duplicate logicallabel": {
+			phyios: []phyio{
+				{"eth0", "uplink", "eth0"},
+				{"eth1", "uplink", "eth1"},
+				{"eth2", "lan", "eth2"},
+			},
+			expectedFlagged: []string{"eth0", "eth1"},
+			sysAdapter:      "uplink",
+		},
+		"Duplicate phylabel": {
+			phyios: []phyio{
+				{"eth0", "uplink", "eth0"},
+				{"eth0", "lan", "eth1"},
+			},
+			expectedFlagged: []string{"eth0", "eth0"},
+			// The adapter map keeps the last one of a phylabel
+			sysAdapter: "lan",
+		},
+		"Empty logicallabels are not duplicates": {
+			phyios: []phyio{
+				{"eth0", "", "eth0"},
+				{"eth1", "", "eth1"},
+			},
+			sysAdapter:     "eth1",
+			expectedIfName: "eth1",
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		getconfigCtx.zedagentCtx = &zedagentContext{
+			getconfigCtx:         getconfigCtx,
+			globalConfig:         *types.DefaultConfigItemValueMap(),
+			physicalIoAdapterMap: make(map[string]types.PhysicalIOAdapter),
+		}
+		deviceIoListPrevConfigHash = nil
+		config := &zconfig.EdgeDevConfig{}
+		for _, p := range test.phyios {
+			config.DeviceIoList = append(config.DeviceIoList,
+				&zconfig.PhysicalIO{
+					Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+					Phylabel:     p.phylabel,
+					Logicallabel: p.logicallabel,
+					Phyaddrs:     map[string]string{"ifname": p.ifname},
+				})
+		}
+		assert.True(t, parseDeviceIoListConfig(config, getconfigCtx),
+			testname)
+
+		item, err := getconfigCtx.pubPhysicalIOAdapters.Get("zedagent")
+		assert.Nil(t, err, testname)
+		list := item.(types.PhysicalIOAdapterList)
+		assert.Equal(t, len(test.phyios), len(list.AdapterList), testname)
+		var flagged []string
+		for _, adapter := range list.AdapterList {
+			if adapter.HasError() {
+				flagged = append(flagged, adapter.Phylabel)
+			}
+		}
+		assert.Equal(t, test.expectedFlagged, flagged, testname)
+
+		// The system adapter of a flagged phyio is kept with the error
+		port, err := parseOneSystemAdapterConfig(getconfigCtx,
+			&zconfig.SystemAdapter{Name: test.sysAdapter},
+			types.DPCIsMgmt)
+		assert.Nil(t, err, testname)
+		if !assert.NotNil(t, port, testname) {
+			continue
+		}
+		if test.expectedIfName == "" {
+			assert.True(t, port.HasError(), testname)
+		} else {
+			assert.Equal(t, test.expectedIfName, port.IfName, testname)
+		}
+	}
+}
+
+func TestParseStaticDNSEntries(t *testing.T) {
+	testMatrix := map[string]struct {
+		dnsEntries       []*zconfig.ZnetStaticDNSEntry
+		expectedNameToIP []types.DnsNameToIP
+		expectedBadAddrs map[string][]string
+		expectedWarnings int
+	}{
+		"No entries": {
+			expectedNameToIP: []types.DnsNameToIP{},
+		},
+		"Unique host names": {
+			dnsEntries: []*zconfig.ZnetStaticDNSEntry{
+				{HostName: "printer.local", Address: []string{"10.1.0.5"}},
+				{HostName: "nas.local", Address: []string{"10.1.0.6"}},
+			},
+			expectedNameToIP: []types.DnsNameToIP{
+				{HostName: "printer.local", IPs: []net.IP{net.ParseIP("10.1.0.5")}},
+				{HostName: "nas.local", IPs: []net.IP{net.ParseIP("10.1.0.6")}},
+			},
+		},
+		"Duplicate host name": {
+			dnsEntries: []*zconfig.ZnetStaticDNSEntry{
+				{HostName: "printer.local", Address: []string{"10.1.0.5"}},
+				{HostName: "nas.local", Address: []string{"10.1.0.6"}},
+				{HostName: "Printer.Local",
+					Address: []string{"10.1.0.5", "fd00::5"}},
+			},
+			expectedNameToIP: []types.DnsNameToIP{
+				{HostName: "printer.local", IPs: []net.IP{
+					net.ParseIP("10.1.0.5"), net.ParseIP("fd00::5")}},
+				{HostName: "nas.local", IPs: []net.IP{net.ParseIP("10.1.0.6")}},
+			},
+			expectedWarnings: 1,
+		},
+		"Duplicate IP in one entry": {
+			dnsEntries: []*zconfig.ZnetStaticDNSEntry{
+				{HostName: "printer.local",
+					Address: []string{"10.1.0.5", "10.1.0.5"}},
+			},
+			expectedNameToIP: []types.DnsNameToIP{
+				{HostName: "printer.local", IPs: []net.IP{net.ParseIP("10.1.0.5")}},
+			},
+		},
+		"Bad address": {
+			dnsEntries: []*zconfig.ZnetStaticDNSEntry{
+				{HostName: "printer.local",
+					Address: []string{"10.1.0.5", "10.1.0"}},
+			},
+			expectedNameToIP: []types.DnsNameToIP{
+				{HostName: "printer.local", IPs: []net.IP{net.ParseIP("10.1.0.5")}},
+			},
+			expectedBadAddrs: map[string][]string{"printer.local": {"10.1.0"}},
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		nameToIPs, badAddrs, warnings := parseStaticDNSEntries(test.dnsEntries)
+		assert.Equal(t, test.expectedNameToIP, nameToIPs, testname)
+		assert.Equal(t, test.expectedBadAddrs, badAddrs, testname)
+		assert.Equal(t, test.expectedWarnings, len(warnings), testname)
+	}
+}
+
+func TestParseDnsNameToIPWarnings(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	dnsEntries := []*zconfig.ZnetStaticDNSEntry{
+		{HostName: "printer.local", Address: []string{"10.1.0.5"}},
+		{HostName: "PRINTER.local", Address: []string{"10.1.0.7"}},
+	}
+	expectedIPs := []net.IP{net.ParseIP("10.1.0.5"), net.ParseIP("10.1.0.7")}
+
+	// Network instance
+	var niConfig types.NetworkInstanceConfig
+	parseDnsNameToIpList(&zconfig.NetworkInstanceConfig{Dns: dnsEntries},
+		&niConfig)
+	assert.Equal(t, 1, len(niConfig.DnsNameToIPList))
+	assert.Equal(t, expectedIPs, niConfig.DnsNameToIPList[0].IPs)
+	assert.Equal(t, 1, len(niConfig.DnsNameToIPWarnings))
+
+	// Network
+	getconfigCtx := initGetConfigCtx(t)
+	network := parseOneNetworkXObjectConfig(getconfigCtx,
+		&zconfig.NetworkConfig{
+			Id:   netID,
+			Type: zconfig.NetworkType_V4,
+			Ip:   &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client},
+			Dns:  dnsEntries,
+		})
+	assert.False(t, network.HasError())
+	assert.Equal(t, niConfig.DnsNameToIPList, network.DnsNameToIPList)
+	assert.Equal(t, niConfig.DnsNameToIPWarnings, network.DnsNameToIPWarnings)
+}
+
+func TestParseDnsNameToIPBadAddrs(t *testing.T) {
+	testMatrix := map[string]struct {
+		dnsEntries      []*zconfig.ZnetStaticDNSEntry
+		expectedIPs     map[string][]net.IP
+		expectedWarning string
+	}{
+		"No bad address": {
+			dnsEntries: []*zconfig.ZnetStaticDNSEntry{
+				{HostName: "printer.local", Address: []string{"10.1.0.5"}},
+			},
+			expectedIPs: map[string][]net.IP{
+				"printer.local": {net.ParseIP("10.1.0.5")},
+			},
+		},
+		"Bad and valid addresses": {
+			dnsEntries: []*zconfig.ZnetStaticDNSEntry{
+				{HostName: "printer.local",
+					Address: []string{"10.1.0.5", "10.1.0"}},
+				{HostName: "nas.local", Address: []string{"nas"}},
+			},
+			expectedIPs: map[string][]net.IP{
+				"printer.local": {net.ParseIP("10.1.0.5")},
+			},
+			expectedWarning: "nas.local: nas; printer.local: 10.1.0",
+		},
+		"Bad addresses in merged entries": {
+			dnsEntries: []*zconfig.ZnetStaticDNSEntry{
+				{HostName: "printer.local", Address: []string{"10.1.0"}},
+				{HostName: "printer.local",
+					Address: []string{"10.1.0.5", "fd00::x"}},
+			},
+			expectedIPs: map[string][]net.IP{
+				"printer.local": {net.ParseIP("10.1.0.5")},
+			},
+			expectedWarning: "printer.local: 10.1.0, fd00::x",
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		var niConfig types.NetworkInstanceConfig
+		parseDnsNameToIpList(
+			&zconfig.NetworkInstanceConfig{Dns: test.dnsEntries}, &niConfig)
+		// The network instance is still used
+		assert.False(t, niConfig.HasError(), testname)
+		if test.expectedWarning != "" {
+			assert.Contains(t,
+				strings.Join(niConfig.DnsNameToIPWarnings, "\n"),
+				test.expectedWarning, testname)
+		} else {
+			assert.Empty(t, niConfig.DnsNameToIPWarnings, testname)
+		}
+		ips := make(map[string][]net.IP)
+		for _, nameToIP := range niConfig.DnsNameToIPList {
+			ips[nameToIP.HostName] = nameToIP.IPs
+		}
+		assert.Equal(t, test.expectedIPs, ips, testname)
+	}
+}
+
+func TestPublishNetworkInstanceConfigUplinkRate(t *testing.T) {
+	testMatrix := map[string]struct {
+		port          string
+		rate          uint32
+		burst         uint32
+		expectedError bool
+	}{
+		"No limit": {
+			port: "eth0",
+		},
+		"Rate and burst": {
+			port:  "eth0",
+			rate:  20000,
+			burst: 64,
+		},
+		"Maximum rate": {
+			port: "eth0",
+			rate: maxUplinkRateKbps,
+		},
+		"Rate too large": {
+			port:          "eth0",
+			rate:          maxUplinkRateKbps + 1,
+			expectedError: true,
+		},
+		"Burst too large": {
+			port:          "eth0",
+			rate:          20000,
+			burst:         maxBurstKB + 1,
+			expectedError: true,
+		},
+		"Burst without rate": {
+			port:          "eth0",
+			burst:         64,
+			expectedError: true,
+		},
+		"Rate without uplink": {
+			rate:          20000,
+			expectedError: true,
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	uuidStr := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		networkInstance := &zconfig.NetworkInstanceConfig{
+			Uuidandversion: &zconfig.UUIDandVersion{
+				Uuid:    uuidStr,
+				Version: "1",
+			},
+			InstType:       zconfig.ZNetworkInstType_ZnetInstLocal,
+			IpType:         zconfig.AddressType_IPV4,
+			Ip:             &zconfig.Ipspec{},
+			UplinkRateKbps: test.rate,
+			BurstKB:        test.burst,
+		}
+		if test.port != "" {
+			networkInstance.Port = &zconfig.Adapter{Name: test.port}
+		}
+		publishNetworkInstanceConfig(getconfigCtx,
+			[]*zconfig.NetworkInstanceConfig{networkInstance})
+		c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+		assert.Nil(t, err, testname)
+		config := c.(types.NetworkInstanceConfig)
+		assert.Equal(t, test.expectedError, config.HasError(), testname)
+		assert.Equal(t, test.rate, config.UplinkRateKbps, testname)
+		assert.Equal(t, test.burst, config.BurstKB, testname)
+		assert.Empty(t, config.UplinkRateWarning, testname)
+	}
+}
+
+func TestPublishNetworkInstanceConfigUplinkOversubscribed(t *testing.T) {
+	testMatrix := map[string]struct {
+		capacity        uint32
+		rate1           uint32
+		rate2           uint32
+		port2           string
+		expectedWarning bool
+	}{
+		"Capacity not set": {
+			rate1: 20000,
+			rate2: 20000,
+			port2: "eth0",
+		},
+		"Within capacity": {
+			capacity: 50000,
+			rate1:    20000,
+			rate2:    30000,
+			port2:    "eth0",
+		},
+		"Oversubscribed": {
+			capacity:        30000,
+			rate1:           20000,
+			rate2:           20000,
+			port2:           "eth0",
+			expectedWarning: true,
+		},
+		"Different uplinks": {
+			capacity: 30000,
+			rate1:    20000,
+			rate2:    20000,
+			port2:    "wwan0",
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	uuidStr1 := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	uuidStr2 := "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx.zedagentCtx.globalConfig.SetGlobalValueInt(
+			types.NetworkUplinkCapacityKbps, test.capacity)
+		networkInstances := []*zconfig.NetworkInstanceConfig{
+			{
+				Uuidandversion: &zconfig.UUIDandVersion{
+					Uuid:    uuidStr1,
+					Version: "1",
+				},
+				InstType:       zconfig.ZNetworkInstType_ZnetInstLocal,
+				IpType:         zconfig.AddressType_IPV4,
+				Ip:             &zconfig.Ipspec{},
+				Port:           &zconfig.Adapter{Name: "eth0"},
+				UplinkRateKbps: test.rate1,
+			},
+			{
+				Uuidandversion: &zconfig.UUIDandVersion{
+					Uuid:    uuidStr2,
+					Version: "1",
+				},
+				InstType:       zconfig.ZNetworkInstType_ZnetInstLocal,
+				IpType:         zconfig.AddressType_IPV4,
+				Ip:             &zconfig.Ipspec{},
+				Port:           &zconfig.Adapter{Name: test.port2},
+				UplinkRateKbps: test.rate2,
+			},
+		}
+		publishNetworkInstanceConfig(getconfigCtx, networkInstances)
+		for _, uuidStr := range []string{uuidStr1, uuidStr2} {
+			c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+			assert.Nil(t, err, testname)
+			config := c.(types.NetworkInstanceConfig)
+			assert.False(t, config.HasError(), testname)
+			assert.Equal(t, test.expectedWarning,
+				config.UplinkRateWarning != "", testname)
+		}
+	}
+}
+
+func TestParseDeviceIoListConfigUnknownPhyaddrs(t *testing.T) {
+	testMatrix := map[string]struct {
+		phyaddrs        map[string]string
+		logicallabel2   string
+		expectedError   string
+		expectedPciLong string
+	}{
+		"Known keys": {
+			phyaddrs: map[string]string{
+				"ifname":  "eth0",
+				"PciLong": "0000:04:00.0",
+			},
+			logicallabel2:   "lan",
+			expectedPciLong: "0000:04:00.0",
+		},
+		"Misspelled key": {
+			phyaddrs: map[string]string{
+				"ifname":   "eth0",
+				"pci_long": "0000:04:00.0",
+			},
+			logicallabel2: "lan",
+			expectedError: "unknown phyaddrs keys pci_long",
+		},
+		"Several unknown keys": {
+			phyaddrs: map[string]string{
+				"pci_long": "0000:04:00.0",
+				"if_name":  "eth0",
+			},
+			logicallabel2: "lan",
+			expectedError: "unknown phyaddrs keys if_name, pci_long",
+		},
+		"Unknown key and duplicate logicallabel": {
+			phyaddrs: map[string]string{
+				"ifname":   "eth0",
+				"pci_long": "0000:04:00.0",
+			},
+			logicallabel2: "uplink",
+			expectedError: "unknown phyaddrs keys pci_long; duplicate logicallabel uplink",
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		getconfigCtx.zedagentCtx.physicalIoAdapterMap =
+			make(map[string]types.PhysicalIOAdapter)
+		deviceIoListPrevConfigHash = nil
+		config := &zconfig.EdgeDevConfig{
+			DeviceIoList: []*zconfig.PhysicalIO{
+				{
+					Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+					Phylabel:     "ethernet0",
+					Logicallabel: "uplink",
+					Phyaddrs:     test.phyaddrs,
+				},
+				{
+					Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+					Phylabel:     "ethernet1",
+					Logicallabel: test.logicallabel2,
+					Phyaddrs:     map[string]string{"ifname": "eth1"},
+				},
+			},
+		}
+		assert.True(t, parseDeviceIoListConfig(config, getconfigCtx),
+			testname)
+
+		item, err := getconfigCtx.pubPhysicalIOAdapters.Get("zedagent")
+		assert.Nil(t, err, testname)
+		list := item.(types.PhysicalIOAdapterList)
+		adapter := list.LookupAdapter("ethernet0")
+		assert.NotNil(t, adapter, testname)
+		if test.expectedError == "" {
+			assert.False(t, adapter.HasError(), testname)
+		} else {
+			assert.True(t, adapter.HasError(), testname)
+			assert.Contains(t, adapter.Error, test.expectedError, testname)
+		}
+		assert.Equal(t, test.expectedPciLong, adapter.Phyaddr.PciLong,
+			testname)
+		if test.logicallabel2 != "uplink" {
+			adapter = list.LookupAdapter("ethernet1")
+			assert.False(t, adapter.HasError(), testname)
+		}
+	}
+}
+
+func TestParseSystemAdapterConfigReport(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	const unknownNetID = "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	getconfigCtx := initGetConfigCtx(t)
+	getconfigCtx.zedagentCtx.physicalIoAdapterMap =
+		make(map[string]types.PhysicalIOAdapter)
+	deviceIoListPrevConfigHash = nil
+	networkConfigPrevConfigHash = nil
+	systemAdaptersPrevConfigHash = nil
+	config := &zconfig.EdgeDevConfig{
+		Networks: []*zconfig.NetworkConfig{
+			{Id: netID, Type: zconfig.NetworkType_V4,
+				Ip: &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client}},
+		},
+		DeviceIoList: []*zconfig.PhysicalIO{
+			{
+				Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+				Phylabel:     "eth0",
+				Logicallabel: "uplink",
+				Phyaddrs:     map[string]string{"ifname": "eth0"},
+			},
+			{
+				Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+				Phylabel:     "eth1",
+				Logicallabel: "lan",
+				Phyaddrs:     map[string]string{"ifname": "eth1"},
+			},
+			{
+				Ptype:        zcommon.PhyIoType_PhyIoUSB,
+				Phylabel:     "usb0",
+				Logicallabel: "usb",
+				Phyaddrs:     map[string]string{"usbaddr": "1:1"},
+			},
+		},
+		SystemAdapterList: []*zconfig.SystemAdapter{
+			{Name: "uplink", Uplink: true, NetworkUUID: netID},
+			{Name: "lan", NetworkUUID: unknownNetID},
+			{Name: "usb", NetworkUUID: netID},
+			{Name: "wlan", LowerLayerName: "wlan0", NetworkUUID: netID},
+		},
+	}
+	parseDeviceIoListConfig(config, getconfigCtx)
+	parseNetworkXObjectConfig(config, getconfigCtx)
+	parseSystemAdapterConfig(config, getconfigCtx, true)
+
+	assert.Equal(t, 2, len(getconfigCtx.devicePortConfig.Ports))
+	item, err := getconfigCtx.pubSystemAdapterReport.Get("global")
+	assert.Nil(t, err)
+	report := item.(types.SystemAdapterReport)
+	expected := []struct {
+		name           string
+		lowerLayerName string
+		disposition    types.SystemAdapterDisposition
+		reason         string
+	}{
+		{"uplink", "", types.SystemAdapterAccepted, ""},
+		{"lan", "", types.SystemAdapterAcceptedWithError, "UNKNOWN Network UUID"},
+		{"usb", "", types.SystemAdapterRejected, "not IsNet"},
+		{"wlan", "wlan0", types.SystemAdapterRejected, "Missing phyio"},
+	}
+	assert.Equal(t, len(expected), len(report.Results))
+	for i, result := range report.Results {
+		assert.Equal(t, expected[i].name, result.Name)
+		assert.Equal(t, expected[i].lowerLayerName, result.LowerLayerName)
+		assert.Equal(t, expected[i].disposition, result.Disposition,
+			result.Name)
+		if expected[i].reason == "" {
+			assert.Empty(t, result.Reason, result.Name)
+		} else {
+			assert.Contains(t, result.Reason, expected[i].reason,
+				result.Name)
+		}
+	}
+
+	// Reported in the device info
+	parseResults := encodeSystemAdapterReport(getconfigCtx.zedagentCtx)
+	assert.Equal(t, len(expected), len(parseResults))
+	assert.Equal(t, info.SystemAdapterDisposition_SYSTEM_ADAPTER_DISPOSITION_REJECTED,
+		parseResults[2].Disposition)
+	assert.Equal(t, report.Results[2].Reason, parseResults[2].Reason)
+
+	// No port at all is still reported
+	config.SystemAdapterList = config.SystemAdapterList[2:]
+	parseSystemAdapterConfig(config, getconfigCtx, false)
+	item, err = getconfigCtx.pubSystemAdapterReport.Get("global")
+	assert.Nil(t, err)
+	report = item.(types.SystemAdapterReport)
+	assert.Equal(t, 2, len(report.Results))
+	for _, result := range report.Results {
+		assert.Equal(t, types.SystemAdapterRejected, result.Disposition,
+			result.Name)
+	}
+}
+
+func TestParseSystemAdapterConfigCost(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	testMatrix := map[string]struct {
+		cost         uint32
+		freeUplink   bool
+		phyioFree    bool
+		expectedCost uint8
+	}{
+		"Legacy not free": {
+			expectedCost: types.PortCostMax,
+		},
+		"Cost": {
+			cost:         10,
+			expectedCost: 10,
+		},
+		"Cost clamped": {
+			cost:         1000,
+			expectedCost: types.PortCostMax,
+		},
+		"Legacy free system adapter": {
+			freeUplink:   true,
+			expectedCost: 0,
+		},
+		"Legacy free phyio": {
+			phyioFree:    true,
+			expectedCost: 0,
+		},
+		"Cost overrides legacy free": {
+			cost:         20,
+			phyioFree:    true,
+			expectedCost: 20,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		getconfigCtx.zedagentCtx.physicalIoAdapterMap =
+			make(map[string]types.PhysicalIOAdapter)
+		deviceIoListPrevConfigHash = nil
+		networkConfigPrevConfigHash = nil
+		systemAdaptersPrevConfigHash = nil
+		config := &zconfig.EdgeDevConfig{
+			Networks: []*zconfig.NetworkConfig{
+				{Id: netID, Type: zconfig.NetworkType_V4,
+					Ip: &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client}},
+			},
+			DeviceIoList: []*zconfig.PhysicalIO{
+				{
+					Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+					Phylabel:     "eth0",
+					Logicallabel: "eth0",
+					Phyaddrs:     map[string]string{"ifname": "eth0"},
+					UsagePolicy: &zconfig.PhyIOUsagePolicy{
+						FreeUplink: test.phyioFree},
+				},
+				{
+					// An old controller sets FreeUplink on some port
+					Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+					Phylabel:     "eth1",
+					Logicallabel: "eth1",
+					Phyaddrs:     map[string]string{"ifname": "eth1"},
+					UsagePolicy: &zconfig.PhyIOUsagePolicy{
+						FreeUplink: true},
+				},
+			},
+			SystemAdapterList: []*zconfig.SystemAdapter{
+				{Name: "eth0", Uplink: true, NetworkUUID: netID,
+					Cost: test.cost, FreeUplink: test.freeUplink},
+			},
+		}
+		parseDeviceIoListConfig(config, getconfigCtx)
+		parseNetworkXObjectConfig(config, getconfigCtx)
+		parseSystemAdapterConfig(config, getconfigCtx, true)
+		item, err := getconfigCtx.pubDevicePortConfig.Get("zedagent")
+		assert.Nil(t, err, testname)
+		dpc := item.(types.DevicePortConfig)
+		if assert.Equal(t, 1, len(dpc.Ports), testname) {
+			assert.Equal(t, test.expectedCost, dpc.Ports[0].Cost, testname)
+		}
+	}
+}
+
+func TestParseSystemAdapterConfigCostChange(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	getconfigCtx := initGetConfigCtx(t)
+	getconfigCtx.zedagentCtx.physicalIoAdapterMap =
+		make(map[string]types.PhysicalIOAdapter)
+	deviceIoListPrevConfigHash = nil
+	networkConfigPrevConfigHash = nil
+	systemAdaptersPrevConfigHash = nil
+	config := &zconfig.EdgeDevConfig{
+		Networks: []*zconfig.NetworkConfig{
+			{Id: netID, Type: zconfig.NetworkType_V4,
+				Ip: &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client}},
+		},
+		DeviceIoList: []*zconfig.PhysicalIO{
+			{
+				Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+				Phylabel:     "eth0",
+				Logicallabel: "eth0",
+				Phyaddrs:     map[string]string{"ifname": "eth0"},
+				UsagePolicy:  &zconfig.PhyIOUsagePolicy{FreeUplink: true},
+			},
+			{
+				Ptype:        zcommon.PhyIoType_PhyIoNetWWAN,
+				Phylabel:     "wwan0",
+				Logicallabel: "wwan0",
+				Phyaddrs:     map[string]string{"ifname": "wwan0"},
+			},
+		},
+		SystemAdapterList: []*zconfig.SystemAdapter{
+			{Name: "eth0", Uplink: true, NetworkUUID: netID},
+			{Name: "wwan0", Uplink: true, NetworkUUID: netID},
+		},
+	}
+	parseDeviceIoListConfig(config, getconfigCtx)
+	parseNetworkXObjectConfig(config, getconfigCtx)
+	parseSystemAdapterConfig(config, getconfigCtx, true)
+	item, err := getconfigCtx.pubDevicePortConfig.Get("zedagent")
+	assert.Nil(t, err)
+	dpc := item.(types.DevicePortConfig)
+	if assert.Equal(t, 2, len(dpc.Ports)) {
+		// Not free for an old controller is the highest cost
+		assert.Equal(t, types.PortCostMin, dpc.Ports[0].Cost)
+		assert.Equal(t, types.PortCostMax, dpc.Ports[1].Cost)
+	}
+
+	// A change in only the cost is published
+	config.SystemAdapterList[1].Cost = 100
+	parseSystemAdapterConfig(config, getconfigCtx, false)
+	item, err = getconfigCtx.pubDevicePortConfig.Get("zedagent")
+	assert.Nil(t, err)
+	dpc = item.(types.DevicePortConfig)
+	assert.Equal(t, uint8(100), dpc.Ports[1].Cost)
+}
+
+func TestParseSystemAdapterConfigSharedLabels(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	getconfigCtx := initGetConfigCtx(t)
+	getconfigCtx.zedagentCtx.physicalIoAdapterMap =
+		make(map[string]types.PhysicalIOAdapter)
+	deviceIoListPrevConfigHash = nil
+	networkConfigPrevConfigHash = nil
+	systemAdaptersPrevConfigHash = nil
+	var deviceIoList []*zconfig.PhysicalIO
+	for _, label := range []string{"eth0", "eth1", "eth2"} {
+		deviceIoList = append(deviceIoList, &zconfig.PhysicalIO{
+			Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+			Phylabel:     label,
+			Logicallabel: label,
+			Phyaddrs:     map[string]string{"ifname": label},
+		})
+	}
+	config := &zconfig.EdgeDevConfig{
+		Networks: []*zconfig.NetworkConfig{
+			{Id: netID, Type: zconfig.NetworkType_V4,
+				Ip: &zconfig.Ipspec{Dhcp: zconfig.DHCPType_Client}},
+		},
+		DeviceIoList: deviceIoList,
+		SystemAdapterList: []*zconfig.SystemAdapter{
+			{Name: "eth0", Uplink: true, NetworkUUID: netID,
+				SharedLabels: []string{"wan", "wan", ""}},
+			{Name: "eth1", Uplink: true, NetworkUUID: netID, Cost: 10,
+				SharedLabels: []string{"wan", "Uplink"}},
+			{Name: "eth2", NetworkUUID: netID,
+				SharedLabels: []string{"lan", "eth0"}},
+		},
+	}
+	parseDeviceIoListConfig(config, getconfigCtx)
+	parseNetworkXObjectConfig(config, getconfigCtx)
+	parseSystemAdapterConfig(config, getconfigCtx, true)
+	item, err := getconfigCtx.pubDevicePortConfig.Get("zedagent")
+	assert.Nil(t, err)
+	dpc := item.(types.DevicePortConfig)
+	if !assert.Equal(t, 3, len(dpc.Ports)) {
+		return
+	}
+	assert.Equal(t, []string{"wan", "uplink", "freeuplink"},
+		dpc.Ports[0].SharedLabels)
+	assert.False(t, dpc.Ports[0].HasError())
+	assert.Equal(t, []string{"wan", "uplink"}, dpc.Ports[1].SharedLabels)
+	assert.Contains(t, dpc.Ports[1].LastError,
+		"reserved shared label Uplink")
+	assert.Equal(t, []string{"lan"}, dpc.Ports[2].SharedLabels)
+	assert.Contains(t, dpc.Ports[2].LastError,
+		"shared label eth0 which is the logicallabel of a port")
+}
+
+func TestParseSystemAdapterConfigLateNetwork(t *testing.T) {
+	const netID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	resetPrevConfigHashes()
+	getconfigCtx, _ := initGoldenConfigCtx(t)
+	config := &zconfig.EdgeDevConfig{
+		DeviceIoList: []*zconfig.PhysicalIO{
+			{
+				Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+				Phylabel:     "eth0",
+				Logicallabel: "eth0",
+				Phyaddrs:     map[string]string{"ifname": "eth0"},
+			},
+		},
+		SystemAdapterList: []*zconfig.SystemAdapter{
+			{Name: "eth0", Uplink: true, NetworkUUID: netID},
+		},
+	}
+	getPort := func() types.NetworkPortConfig {
+		item, err := getconfigCtx.pubDevicePortConfig.Get("zedagent")
+		assert.Nil(t, err)
+		dpc := item.(types.DevicePortConfig)
+		assert.Equal(t, 1, len(dpc.Ports))
+		return dpc.Ports[0]
+	}
+
+	parseConfigObjects(config, getconfigCtx, false)
+	port := getPort()
+	assert.True(t, port.HasError())
+	assert.Equal(t, nilUUID, port.NetworkUUID)
+	assert.True(t, getconfigCtx.unresolvedNetworkUUIDs[netID])
+
+	// Nothing changed
+	parseConfigObjects(config, getconfigCtx, false)
+	assert.Equal(t, nilUUID, getPort().NetworkUUID)
+
+	// The network is published without a change in the networks of the
+	// config, e.g., by an earlier parse which failed half way
+	id, err := uuid.FromString(netID)
+	assert.Nil(t, err)
+	getconfigCtx.pubNetworkXObjectConfig.Publish(netID,
+		types.NetworkXObjectConfig{UUID: id, Type: types.NT_IPV4,
+			Dhcp: types.DT_CLIENT})
+	parseConfigObjects(config, getconfigCtx, false)
+	port = getPort()
+	assert.False(t, port.HasError(), port.LastError)
+	assert.Equal(t, id, port.NetworkUUID)
+	assert.Equal(t, types.DT_CLIENT, port.Dhcp)
+	assert.Empty(t, getconfigCtx.unresolvedNetworkUUIDs)
+}
+
+func TestPublishNetworkInstanceConfigVlan(t *testing.T) {
+	testMatrix := map[string]struct {
+		instType      zconfig.ZNetworkInstType
+		ipType        zconfig.AddressType
+		vlan          uint32
+		expectedVlan  uint16
+		expectedError bool
+	}{
+		"Untagged switch": {
+			instType: zconfig.ZNetworkInstType_ZnetInstSwitch,
+		},
+		"Switch on VLAN": {
+			instType:     zconfig.ZNetworkInstType_ZnetInstSwitch,
+			vlan:         100,
+			expectedVlan: 100,
+		},
+		"Switch on highest VLAN": {
+			instType:     zconfig.ZNetworkInstType_ZnetInstSwitch,
+			vlan:         4094,
+			expectedVlan: 4094,
+		},
+		"VLAN out of range": {
+			instType:      zconfig.ZNetworkInstType_ZnetInstSwitch,
+			vlan:          4095,
+			expectedError: true,
+		},
+		"VLAN on local": {
+			instType:      zconfig.ZNetworkInstType_ZnetInstLocal,
+			ipType:        zconfig.AddressType_IPV4,
+			vlan:          100,
+			expectedError: true,
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	uuidStr := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		networkInstances := []*zconfig.NetworkInstanceConfig{
+			{
+				Uuidandversion: &zconfig.UUIDandVersion{
+					Uuid:    uuidStr,
+					Version: "1",
+				},
+				InstType: test.instType,
+				IpType:   test.ipType,
+				Ip:       &zconfig.Ipspec{},
+				Port:     &zconfig.Adapter{Name: "eth1"},
+				VlanId:   test.vlan,
+			},
+		}
+		publishNetworkInstanceConfig(getconfigCtx, networkInstances)
+		c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+		assert.Nil(t, err, testname)
+		config := c.(types.NetworkInstanceConfig)
+		assert.Equal(t, test.expectedError, config.HasError(), testname)
+		assert.Equal(t, test.expectedVlan, config.Vlan, testname)
+		assert.Equal(t, "eth1", config.Logicallabel, testname)
+	}
+}
+
+func TestPublishNetworkInstanceConfigVlanConflict(t *testing.T) {
+	testMatrix := map[string]struct {
+		vlan2            uint32
+		port2            string
+		expectedConflict bool
+	}{
+		"Same VLAN same port": {
+			vlan2:            100,
+			port2:            "eth1",
+			expectedConflict: true,
+		},
+		"Other VLAN same port": {
+			vlan2: 200,
+			port2: "eth1",
+		},
+		"Same VLAN other port": {
+			vlan2: 100,
+			port2: "eth2",
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	uuidStr1 := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	uuidStr2 := "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		networkInstances := []*zconfig.NetworkInstanceConfig{
+			{
+				Uuidandversion: &zconfig.UUIDandVersion{
+					Uuid:    uuidStr1,
+					Version: "1",
+				},
+				InstType: zconfig.ZNetworkInstType_ZnetInstSwitch,
+				Port:     &zconfig.Adapter{Name: "eth1"},
+				VlanId:   100,
+			},
+			{
+				Uuidandversion: &zconfig.UUIDandVersion{
+					Uuid:    uuidStr2,
+					Version: "1",
+				},
+				InstType: zconfig.ZNetworkInstType_ZnetInstSwitch,
+				Port:     &zconfig.Adapter{Name: test.port2},
+				VlanId:   test.vlan2,
+			},
+		}
+		publishNetworkInstanceConfig(getconfigCtx, networkInstances)
+		for _, uuidStr := range []string{uuidStr1, uuidStr2} {
+			c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+			assert.Nil(t, err, testname)
+			config := c.(types.NetworkInstanceConfig)
+			assert.Equal(t, test.expectedConflict, config.HasError(),
+				testname)
+			if test.expectedConflict {
+				assert.Contains(t, config.Error, "conflicts with", testname)
+			}
+		}
+	}
+}
+
+func TestParseDeviceIoListConfigAssignmentGroups(t *testing.T) {
+	type phyio struct {
+		phylabel  string
+		ptype     zcommon.PhyIoType
+		assigngrp string
+		phyaddrs  map[string]string
+	}
+	iommuGroups := map[string]string{
+		"0000:03:00.0": "12",
+		"0000:03:00.1": "12",
+		"0000:04:00.0": "13",
+	}
+	testMatrix := map[string]struct {
+		phyios          []phyio
+		expectedFlagged []string
+	}{
+		"Consistent PCI group": {
+			phyios: []phyio{
+				{"eth0", zcommon.PhyIoType_PhyIoNetEth, "grp1",
+					map[string]string{"pcilong": "0000:03:00.0"}},
+				{"eth1", zcommon.PhyIoType_PhyIoNetEth, "grp1",
+					map[string]string{"pcilong": "0000:03:00.1"}},
+			},
+		},
+		"USB controller with NIC in one IOMMU group": {
+			phyios: []phyio{
+				{"eth0", zcommon.PhyIoType_PhyIoNetEth, "grp1",
+					map[string]string{"pcilong": "0000:03:00.0"}},
+				{"usbctl", zcommon.PhyIoType_PhyIoUSB, "grp1",
+					map[string]string{"pcilong": "0000:03:00.1"}},
+			},
+		},
+		"USB device with PCI device": {
+			phyios: []phyio{
+				{"eth0", zcommon.PhyIoType_PhyIoNetEth, "grp1",
+					map[string]string{"pcilong": "0000:03:00.0"}},
+				{"usb1", zcommon.PhyIoType_PhyIoUSB, "grp1",
+					map[string]string{"usbaddr": "1:2"}},
+				{"eth1", zcommon.PhyIoType_PhyIoNetEth, "grp2",
+					map[string]string{"pcilong": "0000:04:00.0"}},
+			},
+			expectedFlagged: []string{"eth0", "usb1"},
+		},
+		"Different IOMMU groups": {
+			phyios: []phyio{
+				{"eth0", zcommon.PhyIoType_PhyIoNetEth, "grp1",
+					map[string]string{"pcilong": "0000:03:00.0"}},
+				{"eth1", zcommon.PhyIoType_PhyIoNetEth, "grp1",
+					map[string]string{"pcilong": "0000:04:00.0"}},
+			},
+			expectedFlagged: []string{"eth0", "eth1"},
+		},
+		"Unknown IOMMU group": {
+			phyios: []phyio{
+				{"eth0", zcommon.PhyIoType_PhyIoNetEth, "grp1",
+					map[string]string{"pcilong": "0000:03:00.0"}},
+				{"eth1", zcommon.PhyIoType_PhyIoNetEth, "grp1",
+					map[string]string{"pcilong": "0000:05:00.0"}},
+			},
+		},
+		"Single member groups": {
+			phyios: []phyio{
+				{"usb1", zcommon.PhyIoType_PhyIoUSB, "grp1",
+					map[string]string{"usbaddr": "1:2"}},
+				{"eth0", zcommon.PhyIoType_PhyIoNetEth, "grp2",
+					map[string]string{"pcilong": "0000:03:00.0"}},
+			},
+		},
+		"No assigngrp": {
+			phyios: []phyio{
+				{"usb1", zcommon.PhyIoType_PhyIoUSB, "",
+					map[string]string{"usbaddr": "1:2"}},
+				{"eth0", zcommon.PhyIoType_PhyIoNetEth, "",
+					map[string]string{"pcilong": "0000:03:00.0"}},
+			},
+		},
+	}
+
+	savedGetIOMMUGroup := pciGetIOMMUGroup
+	defer func() { pciGetIOMMUGroup = savedGetIOMMUGroup }()
+	pciGetIOMMUGroup = func(long string) (string, error) {
+		if group, ok := iommuGroups[long]; ok {
+			return group, nil
+		}
+		return "", fmt.Errorf("no iommu group for %s", long)
+	}
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		getconfigCtx.zedagentCtx.physicalIoAdapterMap =
+			make(map[string]types.PhysicalIOAdapter)
+		deviceIoListPrevConfigHash = nil
+		config := &zconfig.EdgeDevConfig{}
+		for _, p := range test.phyios {
+			config.DeviceIoList = append(config.DeviceIoList,
+				&zconfig.PhysicalIO{
+					Ptype:     p.ptype,
+					Phylabel:  p.phylabel,
+					Assigngrp: p.assigngrp,
+					Phyaddrs:  p.phyaddrs,
+				})
+		}
+		assert.True(t, parseDeviceIoListConfig(config, getconfigCtx),
+			testname)
+
+		item, err := getconfigCtx.pubPhysicalIOAdapters.Get("zedagent")
+		assert.Nil(t, err, testname)
+		list := item.(types.PhysicalIOAdapterList)
+		var flagged []string
+		for _, adapter := range list.AdapterList {
+			if adapter.HasError() {
+				assert.Contains(t, adapter.Error, "assigngrp grp1",
+					testname)
+				flagged = append(flagged, adapter.Phylabel)
+			}
+		}
+		assert.Equal(t, test.expectedFlagged, flagged, testname)
+	}
+}
+
+func TestPublishNetworkInstanceConfigPassiveIpType(t *testing.T) {
+	testMatrix := map[string]struct {
+		instType              zconfig.ZNetworkInstType
+		ipType                zconfig.AddressType
+		passiveIpType         zconfig.AddressType
+		expectedPassiveIpType types.AddressType
+		expectedError         bool
+	}{
+		"Pure L2 switch": {
+			instType:              zconfig.ZNetworkInstType_ZnetInstSwitch,
+			expectedPassiveIpType: types.AddressTypeNone,
+		},
+		"Switch observing IPv4": {
+			instType:              zconfig.ZNetworkInstType_ZnetInstSwitch,
+			passiveIpType:         zconfig.AddressType_IPV4,
+			expectedPassiveIpType: types.AddressTypeIPV4,
+		},
+		"Switch observing IPv6": {
+			instType:              zconfig.ZNetworkInstType_ZnetInstSwitch,
+			passiveIpType:         zconfig.AddressType_IPV6,
+			expectedPassiveIpType: types.AddressTypeIPV6,
+		},
+		"Switch with crypto passive IpType": {
+			instType:              zconfig.ZNetworkInstType_ZnetInstSwitch,
+			passiveIpType:         zconfig.AddressType_CryptoIPV4,
+			expectedPassiveIpType: types.AddressTypeNone,
+			expectedError:         true,
+		},
+		"Switch with IpType": {
+			instType:              zconfig.ZNetworkInstType_ZnetInstSwitch,
+			ipType:                zconfig.AddressType_IPV4,
+			expectedPassiveIpType: types.AddressTypeNone,
+		},
+		"Local with passive IpType": {
+			instType:              zconfig.ZNetworkInstType_ZnetInstLocal,
+			ipType:                zconfig.AddressType_IPV4,
+			passiveIpType:         zconfig.AddressType_IPV4,
+			expectedPassiveIpType: types.AddressTypeNone,
+			expectedError:         true,
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	uuidStr := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		networkInstances := []*zconfig.NetworkInstanceConfig{
+			{
+				Uuidandversion: &zconfig.UUIDandVersion{
+					Uuid:    uuidStr,
+					Version: "1",
+				},
+				InstType:      test.instType,
+				IpType:        test.ipType,
+				Ip:            &zconfig.Ipspec{},
+				Port:          &zconfig.Adapter{Name: "eth1"},
+				PassiveIpType: test.passiveIpType,
+			},
+		}
+		publishNetworkInstanceConfig(getconfigCtx, networkInstances)
+		c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+		assert.Nil(t, err, testname)
+		config := c.(types.NetworkInstanceConfig)
+		assert.Equal(t, test.expectedError, config.HasError(), testname)
+		assert.Equal(t, test.expectedPassiveIpType, config.PassiveIpType,
+			testname)
+		if test.instType == zconfig.ZNetworkInstType_ZnetInstSwitch {
+			// Recorded only
+			assert.Equal(t, types.AddressTypeNone, config.IpType, testname)
+		}
+	}
+}
+
+func TestPublishNetworkInstanceConfigPorts(t *testing.T) {
+	testMatrix := map[string]struct {
+		instType              zconfig.ZNetworkInstType
+		port                  string
+		ports                 []string
+		expectedLogicallabel  string
+		expectedLogicallabels []string
+		expectedError         bool
+	}{
+		"Single port": {
+			instType:              zconfig.ZNetworkInstType_ZnetInstSwitch,
+			port:                  "eth1",
+			expectedLogicallabel:  "eth1",
+			expectedLogicallabels: []string{"eth1"},
+		},
+		"Shared label not checked": {
+			instType:              zconfig.ZNetworkInstType_ZnetInstLocal,
+			port:                  "uplink",
+			expectedLogicallabel:  "uplink",
+			expectedLogicallabels: []string{"uplink"},
+		},
+		"Two ports": {
+			instType:              zconfig.ZNetworkInstType_ZnetInstSwitch,
+			port:                  "eth1",
+			ports:                 []string{"eth2"},
+			expectedLogicallabel:  "eth1",
+			expectedLogicallabels: []string{"eth1", "eth2"},
+		},
+		"Ports only": {
+			instType:              zconfig.ZNetworkInstType_ZnetInstSwitch,
+			ports:                 []string{"eth2", "eth1", "eth2"},
+			expectedLogicallabel:  "eth2",
+			expectedLogicallabels: []string{"eth2", "eth1"},
+		},
+		"Unknown and non-network ports": {
+			instType:              zconfig.ZNetworkInstType_ZnetInstSwitch,
+			port:                  "eth1",
+			ports:                 []string{"eth9", "usb", "eth2"},
+			expectedLogicallabel:  "eth1",
+			expectedLogicallabels: []string{"eth1", "eth2"},
+			expectedError:         true,
+		},
+		"Ports for local": {
+			instType:              zconfig.ZNetworkInstType_ZnetInstLocal,
+			port:                  "eth1",
+			ports:                 []string{"eth2"},
+			expectedLogicallabel:  "eth1",
+			expectedLogicallabels: []string{"eth1"},
+			expectedError:         true,
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	getconfigCtx.pubPhysicalIOAdapters.Publish("zedagent",
+		types.PhysicalIOAdapterList{
+			Initialized: true,
+			AdapterList: []types.PhysicalIOAdapter{
+				{Ptype: zcommon.PhyIoType_PhyIoNetEth, Phylabel: "ethernet1",
+					Logicallabel: "eth1"},
+				{Ptype: zcommon.PhyIoType_PhyIoNetEth, Phylabel: "eth2"},
+				{Ptype: zcommon.PhyIoType_PhyIoUSB, Phylabel: "usb0",
+					Logicallabel: "usb"},
+			},
+		})
+	uuidStr := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		networkInstance := &zconfig.NetworkInstanceConfig{
+			Uuidandversion: &zconfig.UUIDandVersion{
+				Uuid:    uuidStr,
+				Version: "1",
+			},
+			InstType: test.instType,
+			Ip:       &zconfig.Ipspec{},
+		}
+		if test.instType == zconfig.ZNetworkInstType_ZnetInstLocal {
+			networkInstance.IpType = zconfig.AddressType_IPV4
+		}
+		if test.port != "" {
+			networkInstance.Port = &zconfig.Adapter{Name: test.port}
+		}
+		for _, port := range test.ports {
+			networkInstance.Ports = append(networkInstance.Ports,
+				&zconfig.Adapter{Name: port})
+		}
+		publishNetworkInstanceConfig(getconfigCtx,
+			[]*zconfig.NetworkInstanceConfig{networkInstance})
+		c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+		assert.Nil(t, err, testname)
+		config := c.(types.NetworkInstanceConfig)
+		assert.Equal(t, test.expectedError, config.HasError(), testname)
+		assert.Equal(t, test.expectedLogicallabel, config.Logicallabel,
+			testname)
+		assert.Equal(t, test.expectedLogicallabels, config.Logicallabels,
+			testname)
+	}
+}
+
+func TestParseAppLogPolicy(t *testing.T) {
+	tooMany := make([]string, types.MaxAppLogDropRegexes+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("noise%d", i)
+	}
+	testMatrix := map[string]struct {
+		policy        *zconfig.AppLogPolicy
+		expectedEmpty bool
+		expectedError string
+	}{
+		"No policy": {
+			expectedEmpty: true,
+		},
+		"Valid policy": {
+			policy: &zconfig.AppLogPolicy{
+				MinSeverity:   "warning",
+				DropRegex:     []string{`^DEBUG `, `healthz?\s+ok`},
+				SamplePercent: 10,
+			},
+		},
+		"Bad min severity": {
+			policy: &zconfig.AppLogPolicy{
+				MinSeverity: "loud",
+			},
+			expectedError: "bad min severity",
+		},
+		"Bad drop regex": {
+			policy: &zconfig.AppLogPolicy{
+				DropRegex: []string{`^ok$`, `(unclosed`},
+			},
+			expectedError: `bad drop regex "(unclosed"`,
+		},
+		"Too many drop regexes": {
+			policy: &zconfig.AppLogPolicy{
+				DropRegex: tooMany,
+			},
+			expectedError: "exceed the maximum",
+		},
+		"Sample percent above 100": {
+			policy: &zconfig.AppLogPolicy{
+				SamplePercent: 101,
+			},
+			expectedError: "sample percent",
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		policy := parseAppLogPolicy(test.policy, "appA")
+		assert.Equal(t, test.expectedEmpty, policy.IsEmpty(), testname)
+		assert.Equal(t, test.expectedError != "", policy.HasError(),
+			testname)
+		if test.expectedError != "" {
+			assert.Contains(t, policy.Error, test.expectedError, testname)
+		}
+		assert.Equal(t, test.policy.GetDropRegex(), policy.DropRegexes,
+			testname)
+	}
+}
+
+func TestParseAppInstanceConfigLogPolicy(t *testing.T) {
+	uuidA := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	testMatrix := map[string]struct {
+		policy          *zconfig.AppLogPolicy
+		expectedError   bool
+		expectPublished bool
+	}{
+		"No policy": {},
+		"Valid policy": {
+			policy: &zconfig.AppLogPolicy{
+				MinSeverity: "error",
+				DropRegex:   []string{`^GET /healthz`},
+			},
+			expectPublished: true,
+		},
+		"Invalid policy": {
+			policy: &zconfig.AppLogPolicy{
+				MinSeverity: "error",
+				DropRegex:   []string{`[a-`},
+			},
+			expectedError: true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		appinstancePrevConfigHash = nil
+		appinstancePrevElementHash = make(map[string][]byte)
+		app := newTestAppInstance(uuidA, "appA")
+		app.LogPolicy = test.policy
+		parseAppInstanceConfig(&zconfig.EdgeDevConfig{
+			Apps: []*zconfig.AppInstanceConfig{app}}, getconfigCtx)
+
+		// The app instance is not affected by an invalid policy
+		c, err := getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+		assert.Nil(t, err, testname)
+		appInstance := c.(types.AppInstanceConfig)
+		assert.Empty(t, appInstance.Errors, testname)
+		assert.Equal(t, test.expectedError, appInstance.LogPolicy.HasError(),
+			testname)
+
+		c, _ = getconfigCtx.pubAppLogPolicyConfig.Get(uuidA)
+		assert.Equal(t, test.expectPublished, c != nil, testname)
+		if c != nil {
+			policyConfig := c.(types.AppLogPolicyConfig)
+			assert.Equal(t, test.policy.MinSeverity,
+				policyConfig.Policy.MinSeverity, testname)
+			assert.Equal(t, test.policy.DropRegex,
+				policyConfig.Policy.DropRegexes, testname)
+		}
+
+		// Removing the app removes its policy
+		parseAppInstanceConfig(&zconfig.EdgeDevConfig{}, getconfigCtx)
+		c, _ = getconfigCtx.pubAppLogPolicyConfig.Get(uuidA)
+		assert.Nil(t, c, testname)
+	}
+}
+
+func TestIsSuspiciousIdentityChange(t *testing.T) {
+	volA, _ := uuid.FromString("6ba7b811-9dad-11d1-80b4-00c04fd430c8")
+	volB, _ := uuid.FromString("6ba7b812-9dad-11d1-80b4-00c04fd430c8")
+	volC, _ := uuid.FromString("6ba7b813-9dad-11d1-80b4-00c04fd430c8")
+	oldConfig := types.AppInstanceConfig{
+		DisplayName: "appA",
+		VolumeRefConfigList: []types.VolumeRefConfig{
+			{VolumeID: volA}, {VolumeID: volB},
+		},
+		PurgeCmd: types.AppInstanceOpsCmd{Counter: 1},
+	}
+	testMatrix := map[string]struct {
+		name         string
+		volumes      []uuid.UUID
+		purgeCounter uint32
+		suspicious   bool
+	}{
+		"Unchanged": {
+			name:         "appA",
+			volumes:      []uuid.UUID{volA, volB},
+			purgeCounter: 1,
+		},
+		"Renamed": {
+			name:         "appB",
+			volumes:      []uuid.UUID{volA, volB},
+			purgeCounter: 1,
+		},
+		"All volumes changed": {
+			name:         "appA",
+			volumes:      []uuid.UUID{volC},
+			purgeCounter: 1,
+		},
+		"Renamed and some volumes changed": {
+			name:         "appB",
+			volumes:      []uuid.UUID{volB, volC},
+			purgeCounter: 1,
+		},
+		"Renamed and all volumes changed": {
+			name:         "appB",
+			volumes:      []uuid.UUID{volC},
+			purgeCounter: 1,
+			suspicious:   true,
+		},
+		"Renamed and all volumes changed with purge": {
+			name:         "appB",
+			volumes:      []uuid.UUID{volC},
+			purgeCounter: 2,
+		},
+		"Renamed and all volumes removed": {
+			name:         "appB",
+			purgeCounter: 1,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		newConfig := types.AppInstanceConfig{
+			DisplayName: test.name,
+			PurgeCmd:    types.AppInstanceOpsCmd{Counter: test.purgeCounter},
+		}
+		for _, vol := range test.volumes {
+			newConfig.VolumeRefConfigList = append(
+				newConfig.VolumeRefConfigList,
+				types.VolumeRefConfig{VolumeID: vol})
+		}
+		assert.Equal(t, test.suspicious,
+			isSuspiciousIdentityChange(oldConfig, newConfig), testname)
+	}
+}
+
+func TestParseAppInstanceConfigIdentityChange(t *testing.T) {
+	uuidA := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	volA := "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	volB := "6ba7b812-9dad-11d1-80b4-00c04fd430c8"
+	getconfigCtx := initGetConfigCtx(t)
+	appinstancePrevConfigHash = nil
+	appinstancePrevElementHash = make(map[string][]byte)
+	getApp := func() types.AppInstanceConfig {
+		c, err := getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+		assert.Nil(t, err)
+		return c.(types.AppInstanceConfig)
+	}
+
+	app := newTestAppInstance(uuidA, "appA")
+	app.VolumeRefList = []*zconfig.VolumeRef{{Uuid: volA}}
+	parseAppInstanceConfig(&zconfig.EdgeDevConfig{
+		Apps: []*zconfig.AppInstanceConfig{app}}, getconfigCtx)
+	appInstance := getApp()
+	assert.Equal(t, "appA", appInstance.DisplayName)
+	assert.Empty(t, appInstance.Errors)
+
+	// The UUID is reused for a different app; the old config is kept
+	app = newTestAppInstance(uuidA, "appB")
+	app.VolumeRefList = []*zconfig.VolumeRef{{Uuid: volB}}
+	parseAppInstanceConfig(&zconfig.EdgeDevConfig{
+		Apps: []*zconfig.AppInstanceConfig{app}}, getconfigCtx)
+	appInstance = getApp()
+	assert.Equal(t, "appA", appInstance.DisplayName)
+	assert.Equal(t, volA,
+		appInstance.VolumeRefConfigList[0].VolumeID.String())
+	if assert.Len(t, appInstance.Errors, 1) {
+		assert.Equal(t, types.AppConfigErrorIdentityChange,
+			appInstance.Errors[0].Category)
+		assert.Contains(t, appInstance.Errors[0].Error,
+			"suspicious identity change")
+	}
+
+	// Bumping the purge counter applies the new config
+	app.Purge = &zconfig.InstanceOpsCmd{Counter: 1}
+	parseAppInstanceConfig(&zconfig.EdgeDevConfig{
+		Apps: []*zconfig.AppInstanceConfig{app}}, getconfigCtx)
+	appInstance = getApp()
+	assert.Equal(t, "appB", appInstance.DisplayName)
+	assert.Equal(t, volB,
+		appInstance.VolumeRefConfigList[0].VolumeID.String())
+	assert.Empty(t, appInstance.Errors)
+}
+
+func TestPublishNetworkInstanceConfigPortLookup(t *testing.T) {
+	testMatrix := map[string]struct {
+		port          string
+		noDeviceIo    bool
+		expectedError string
+	}{
+		"Logicallabel": {
+			port: "eth1",
+		},
+		"Phylabel": {
+			port: "ethernet1",
+		},
+		"System adapter": {
+			port: "vlan100",
+		},
+		"Shared label": {
+			port: "uplink",
+		},
+		"Not a network adapter": {
+			port:          "usb",
+			expectedError: "port usb is not a network adapter",
+		},
+		"Unknown port": {
+			port:          "eth9",
+			expectedError: "port eth9 not found",
+		},
+		"Unknown port before the DeviceIoList": {
+			port:       "eth9",
+			noDeviceIo: true,
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	getconfigCtx.devicePortConfig.Ports = []types.NetworkPortConfig{
+		{IfName: "eth1.100", Logicallabel: "vlan100"},
+	}
+	uuidStr := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx.zedagentCtx.physicalIoAdapterMap = nil
+		if !test.noDeviceIo {
+			getconfigCtx.zedagentCtx.physicalIoAdapterMap =
+				map[string]types.PhysicalIOAdapter{
+					"ethernet1": {Ptype: zcommon.PhyIoType_PhyIoNetEth,
+						Phylabel: "ethernet1", Logicallabel: "eth1"},
+					"usb0": {Ptype: zcommon.PhyIoType_PhyIoUSB,
+						Phylabel: "usb0", Logicallabel: "usb"},
+				}
+		}
+		networkInstances := []*zconfig.NetworkInstanceConfig{
+			{
+				Uuidandversion: &zconfig.UUIDandVersion{
+					Uuid:    uuidStr,
+					Version: "1",
+				},
+				InstType: zconfig.ZNetworkInstType_ZnetInstLocal,
+				IpType:   zconfig.AddressType_IPV4,
+				Ip:       &zconfig.Ipspec{},
+				Port:     &zconfig.Adapter{Name: test.port},
+			},
+		}
+		publishNetworkInstanceConfig(getconfigCtx, networkInstances)
+		c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+		assert.Nil(t, err, testname)
+		config := c.(types.NetworkInstanceConfig)
+		assert.Equal(t, test.port, config.Logicallabel, testname)
+		if test.expectedError == "" {
+			assert.False(t, config.HasError(), testname)
+		} else {
+			assert.Contains(t, config.Error, test.expectedError, testname)
+		}
+	}
+}
+
+func TestPublishNetworkInstanceConfigSharedLabel(t *testing.T) {
+	testMatrix := map[string]struct {
+		instType       zconfig.ZNetworkInstType
+		port           string
+		expectedLabels []string
+		expectedError  string
+	}{
+		"Single port": {
+			instType:       zconfig.ZNetworkInstType_ZnetInstLocal,
+			port:           "lan",
+			expectedLabels: []string{"eth2"},
+		},
+		"Ambiguous": {
+			instType:       zconfig.ZNetworkInstType_ZnetInstLocal,
+			port:           "wan",
+			expectedLabels: []string{"wan"},
+			expectedError:  "port wan is ambiguous: shared label of ports eth0, eth1",
+		},
+		"Switch": {
+			instType:       zconfig.ZNetworkInstType_ZnetInstSwitch,
+			port:           "wan",
+			expectedLabels: []string{"eth0", "eth1"},
+		},
+		"Built-in label": {
+			instType:       zconfig.ZNetworkInstType_ZnetInstLocal,
+			port:           "uplink",
+			expectedLabels: []string{"uplink"},
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	getconfigCtx.zedagentCtx.physicalIoAdapterMap =
+		make(map[string]types.PhysicalIOAdapter)
+	var adapters []types.PhysicalIOAdapter
+	for _, label := range []string{"eth0", "eth1", "eth2"} {
+		adapter := types.PhysicalIOAdapter{
+			Ptype:        zcommon.PhyIoType_PhyIoNetEth,
+			Phylabel:     label,
+			Logicallabel: label,
+		}
+		getconfigCtx.zedagentCtx.physicalIoAdapterMap[label] = adapter
+		adapters = append(adapters, adapter)
+	}
+	getconfigCtx.pubPhysicalIOAdapters.Publish("zedagent",
+		types.PhysicalIOAdapterList{AdapterList: adapters})
+	getconfigCtx.devicePortConfig.Ports = []types.NetworkPortConfig{
+		{IfName: "eth0", Logicallabel: "eth0", IsMgmt: true,
+			SharedLabels: []string{"wan", "uplink", "freeuplink"}},
+		{IfName: "eth1", Logicallabel: "eth1", IsMgmt: true,
+			SharedLabels: []string{"wan", "uplink", "freeuplink"}},
+		{IfName: "eth2", Logicallabel: "eth2",
+			SharedLabels: []string{"lan"}},
+	}
+	uuidStr := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		networkInstances := []*zconfig.NetworkInstanceConfig{
+			{
+				Uuidandversion: &zconfig.UUIDandVersion{
+					Uuid:    uuidStr,
+					Version: "1",
+				},
+				InstType: test.instType,
+				Ip:       &zconfig.Ipspec{},
+				Port:     &zconfig.Adapter{Name: test.port},
+			},
+		}
+		if test.instType == zconfig.ZNetworkInstType_ZnetInstLocal {
+			networkInstances[0].IpType = zconfig.AddressType_IPV4
+		}
+		publishNetworkInstanceConfig(getconfigCtx, networkInstances)
+		c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+		assert.Nil(t, err, testname)
+		config := c.(types.NetworkInstanceConfig)
+		assert.Equal(t, test.expectedLabels, config.Logicallabels, testname)
+		assert.Equal(t, test.expectedLabels[0], config.Logicallabel, testname)
+		if test.expectedError == "" {
+			assert.False(t, config.HasError(), testname)
+		} else {
+			assert.Contains(t, config.Error, test.expectedError, testname)
+		}
+	}
+}
+
+func TestParseNetworkInstanceConfigDeviceIoArrives(t *testing.T) {
+	uuidStr := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	config := &zconfig.EdgeDevConfig{
+		NetworkInstances: []*zconfig.NetworkInstanceConfig{
+			{
+				Uuidandversion: &zconfig.UUIDandVersion{
+					Uuid:    uuidStr,
+					Version: "1",
+				},
+				InstType: zconfig.ZNetworkInstType_ZnetInstLocal,
+				IpType:   zconfig.AddressType_IPV4,
+				Ip:       &zconfig.Ipspec{},
+				Port:     &zconfig.Adapter{Name: "eth1"},
+			},
+		},
+	}
+	networkInstancePrevConfigHash = nil
+	defer func() {
+		networkInstancePrevConfigHash = nil
+	}()
+	getconfigCtx := initGetConfigCtx(t)
+	getconfigCtx.zedagentCtx.physicalIoAdapterMap =
+		map[string]types.PhysicalIOAdapter{
+			"usb0": {Ptype: zcommon.PhyIoType_PhyIoUSB,
+				Phylabel: "usb0", Logicallabel: "usb"},
+		}
+	parseNetworkInstanceConfig(config, getconfigCtx, false)
+	c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+	assert.Nil(t, err)
+	niConfig := c.(types.NetworkInstanceConfig)
+	assert.True(t, niConfig.HasError())
+
+	// The port arrives in a changed DeviceIoList
+	getconfigCtx.zedagentCtx.physicalIoAdapterMap["ethernet1"] =
+		types.PhysicalIOAdapter{Ptype: zcommon.PhyIoType_PhyIoNetEth,
+			Phylabel: "ethernet1", Logicallabel: "eth1"}
+	parseNetworkInstanceConfig(config, getconfigCtx, true)
+	c, err = getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+	assert.Nil(t, err)
+	niConfig = c.(types.NetworkInstanceConfig)
+	assert.False(t, niConfig.HasError())
+}
+
+func TestPublishNetworkInstanceConfigLocalIpType(t *testing.T) {
+	testMatrix := map[string]struct {
+		instType      zconfig.ZNetworkInstType
+		ipType        zconfig.AddressType
+		expectedError bool
+	}{
+		"Local IPv4": {
+			instType: zconfig.ZNetworkInstType_ZnetInstLocal,
+			ipType:   zconfig.AddressType_IPV4,
+		},
+		"Local without IpType": {
+			instType:      zconfig.ZNetworkInstType_ZnetInstLocal,
+			ipType:        zconfig.AddressType_First,
+			expectedError: true,
+		},
+		"Switch without IpType": {
+			instType: zconfig.ZNetworkInstType_ZnetInstSwitch,
+			ipType:   zconfig.AddressType_First,
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	uuidStr := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		networkInstances := []*zconfig.NetworkInstanceConfig{
+			{
+				Uuidandversion: &zconfig.UUIDandVersion{
+					Uuid:    uuidStr,
+					Version: "1",
+				},
+				InstType: test.instType,
+				IpType:   test.ipType,
+				Ip:       &zconfig.Ipspec{},
+			},
+		}
+		publishNetworkInstanceConfig(getconfigCtx, networkInstances)
+		c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+		assert.Nil(t, err, testname)
+		config := c.(types.NetworkInstanceConfig)
+		assert.Equal(t, test.expectedError, config.HasError(), testname)
+		if test.expectedError {
+			assert.Contains(t, config.Error, "needs an IpType", testname)
+		}
+	}
+}
+
+func TestPublishNetworkInstanceConfigDscp(t *testing.T) {
+	testMatrix := map[string]struct {
+		dscpMark      bool
+		dscp          uint32
+		expectedError bool
+		expectedMark  bool
+	}{
+		"No mark": {},
+		"Mark zero": {
+			dscpMark:     true,
+			expectedMark: true,
+		},
+		"Mark EF": {
+			dscpMark:     true,
+			dscp:         46,
+			expectedMark: true,
+		},
+		"Mark maximum": {
+			dscpMark:     true,
+			dscp:         63,
+			expectedMark: true,
+		},
+		"Mark too large": {
+			dscpMark:      true,
+			dscp:          64,
+			expectedError: true,
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	uuidStr := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		networkInstances := []*zconfig.NetworkInstanceConfig{
+			{
+				Uuidandversion: &zconfig.UUIDandVersion{
+					Uuid:    uuidStr,
+					Version: "1",
+				},
+				InstType: zconfig.ZNetworkInstType_ZnetInstLocal,
+				IpType:   zconfig.AddressType_IPV4,
+				Ip:       &zconfig.Ipspec{},
+				DscpMark: test.dscpMark,
+				Dscp:     test.dscp,
+			},
+		}
+		publishNetworkInstanceConfig(getconfigCtx, networkInstances)
+		c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+		assert.Nil(t, err, testname)
+		config := c.(types.NetworkInstanceConfig)
+		assert.Equal(t, test.expectedError, config.HasError(), testname)
+		assert.Equal(t, test.expectedMark, config.DscpMark, testname)
+		if test.expectedMark {
+			assert.Equal(t, uint8(test.dscp), config.Dscp, testname)
+		}
+	}
+}
+
+func TestParseAppInstanceConfigDscp(t *testing.T) {
+	const (
+		uuidA = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+		niX   = "6ba7b812-9dad-11d1-80b4-00c04fd430c8"
+	)
+	testMatrix := map[string]struct {
+		dscp          uint32
+		expectedError bool
+	}{
+		"Valid mark": {
+			dscp: 46,
+		},
+		"Mark too large": {
+			dscp:          64,
+			expectedError: true,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		appinstancePrevConfigHash = nil
+		appinstancePrevElementHash = make(map[string][]byte)
+		appA := newTestAppInstance(uuidA, "appA")
+		appA.Interfaces = []*zconfig.NetworkAdapter{
+			{
+				Name:      "eth0",
+				NetworkId: niX,
+				Acls: []*zconfig.ACE{
+					{
+						Id: 1,
+						Actions: []*zconfig.ACEAction{
+							{DscpMark: true, Dscp: test.dscp},
+						},
+					},
+				},
+			},
+		}
+		config := &zconfig.EdgeDevConfig{
+			Apps: []*zconfig.AppInstanceConfig{appA},
+			NetworkInstances: []*zconfig.NetworkInstanceConfig{
+				{Uuidandversion: &zconfig.UUIDandVersion{Uuid: niX}},
+			},
+		}
+		parseAppInstanceConfig(config, getconfigCtx)
+		c, err := getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+		assert.Nil(t, err, testname)
+		appInstance := c.(types.AppInstanceConfig)
+		if test.expectedError {
+			if assert.Len(t, appInstance.Errors, 1, testname) {
+				assert.Equal(t, types.AppConfigErrorBadACL,
+					appInstance.Errors[0].Category, testname)
+			}
+			continue
+		}
+		assert.Empty(t, appInstance.Errors, testname)
+		action := appInstance.UnderlayNetworkList[0].ACLs[0].Actions[0]
+		assert.True(t, action.DscpMark, testname)
+		assert.Equal(t, uint8(test.dscp), action.Dscp, testname)
+
+		// A change in only the mark is published
+		appA.Interfaces[0].Acls[0].Actions[0].Dscp = 10
+		parseAppInstanceConfig(config, getconfigCtx)
+		c, err = getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+		assert.Nil(t, err, testname)
+		appInstance = c.(types.AppInstanceConfig)
+		action = appInstance.UnderlayNetworkList[0].ACLs[0].Actions[0]
+		assert.Equal(t, uint8(10), action.Dscp, testname)
+	}
+}
+
+func TestParseAppInstanceConfigDrop(t *testing.T) {
+	const (
+		uuidA = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+		niX   = "6ba7b812-9dad-11d1-80b4-00c04fd430c8"
+	)
+	getconfigCtx := initGetConfigCtx(t)
+	appinstancePrevConfigHash = nil
+	appinstancePrevElementHash = make(map[string][]byte)
+	appA := newTestAppInstance(uuidA, "appA")
+	appA.Interfaces = []*zconfig.NetworkAdapter{
+		{
+			Name:      "eth0",
+			NetworkId: niX,
+			Acls: []*zconfig.ACE{
+				{
+					Id: 1,
+					Matches: []*zconfig.ACEMatch{
+						{Type: "host", Value: "www.example.com"},
+					},
+					Actions: []*zconfig.ACEAction{{Drop: true}},
+				},
+				{
+					Id: 2,
+					Matches: []*zconfig.ACEMatch{
+						{Type: "ip", Value: "0.0.0.0/0"},
+					},
+				},
+			},
+		},
+	}
+	config := &zconfig.EdgeDevConfig{
+		Apps: []*zconfig.AppInstanceConfig{appA},
+		NetworkInstances: []*zconfig.NetworkInstanceConfig{
+			{Uuidandversion: &zconfig.UUIDandVersion{Uuid: niX}},
+		},
+	}
+	parseAppInstanceConfig(config, getconfigCtx)
+	c, err := getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+	assert.Nil(t, err)
+	appInstance := c.(types.AppInstanceConfig)
+	assert.Empty(t, appInstance.Errors)
+	acls := appInstance.UnderlayNetworkList[0].ACLs
+	if assert.Len(t, acls, 2) {
+		assert.Equal(t, []types.ACEAction{{Drop: true}}, acls[0].Actions)
+		assert.Empty(t, acls[1].Actions)
+	}
+
+	// Turning a drop rule into an allow rule is published
+	appA.Interfaces[0].Acls[0].Actions[0].Drop = false
+	parseAppInstanceConfig(config, getconfigCtx)
+	c, err = getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+	assert.Nil(t, err)
+	appInstance = c.(types.AppInstanceConfig)
+	assert.False(t, appInstance.UnderlayNetworkList[0].ACLs[0].Actions[0].Drop)
+}
+
+func TestParseAppInstanceConfigAclMatch(t *testing.T) {
+	const (
+		uuidA = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+		niX   = "6ba7b812-9dad-11d1-80b4-00c04fd430c8"
+	)
+	testMatrix := map[string]struct {
+		matches       []*zconfig.ACEMatch
+		expectedError string
+	}{
+		"Known types": {
+			matches: []*zconfig.ACEMatch{
+				{Type: "protocol", Value: "tcp"},
+				{Type: "lport", Value: "8080"},
+				{Type: "host", Value: "example.com"},
+			},
+		},
+		"Misspelled type": {
+			matches: []*zconfig.ACEMatch{
+				{Type: "protocol", Value: "tcp"},
+				{Type: "porrt", Value: "8080"},
+			},
+			expectedError: `ACL 1: unknown match type "porrt" with value "8080"`,
+		},
+		"Empty type": {
+			matches: []*zconfig.ACEMatch{
+				{Value: "10.1.0.0/16"},
+			},
+			expectedError: `ACL 1: unknown match type ""`,
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		appinstancePrevConfigHash = nil
+		appinstancePrevElementHash = make(map[string][]byte)
+		appA := newTestAppInstance(uuidA, "appA")
+		appA.Interfaces = []*zconfig.NetworkAdapter{
+			{
+				Name:      "eth0",
+				NetworkId: niX,
+				Acls: []*zconfig.ACE{
+					{Id: 1, Matches: test.matches},
+				},
+			},
+		}
+		config := &zconfig.EdgeDevConfig{
+			Apps: []*zconfig.AppInstanceConfig{appA},
+			NetworkInstances: []*zconfig.NetworkInstanceConfig{
+				{Uuidandversion: &zconfig.UUIDandVersion{Uuid: niX}},
+			},
+		}
+		parseAppInstanceConfig(config, getconfigCtx)
+		c, err := getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+		assert.Nil(t, err, testname)
+		appInstance := c.(types.AppInstanceConfig)
+		if test.expectedError != "" {
+			if assert.Len(t, appInstance.Errors, 1, testname) {
+				assert.Equal(t, types.AppConfigErrorBadACL,
+					appInstance.Errors[0].Category, testname)
+				assert.Contains(t, appInstance.Errors[0].Error,
+					test.expectedError, testname)
+			}
+			continue
+		}
+		assert.Empty(t, appInstance.Errors, testname)
+		assert.Len(t, appInstance.UnderlayNetworkList[0].ACLs[0].Matches,
+			len(test.matches), testname)
+	}
+}
+
+func TestParseAppInstanceConfigOverlayCompat(t *testing.T) {
+	appID := uuid.NewV4().String()
+	localID := uuid.NewV4().String()
+	meshID := uuid.NewV4().String()
+	overlayIntf := func(networkID string) *zconfig.NetworkAdapter {
+		return &zconfig.NetworkAdapter{
+			Name:          "eth0",
+			NetworkId:     networkID,
+			Addr:          "fd45:efca:3607:4c1d:eef9:d4e6:8d1a:af2b",
+			CryptoEid:     "fd45:efca:3607:4c1d:eef9:d4e6:8d1a:af2b",
+			Lispsignature: "signature",
+			Pemcert:       []byte("cert"),
+			Pemprivatekey: []byte("key"),
+			MacAddress:    "02:00:00:00:00:01",
+		}
+	}
+	overlayCompatTestParams := []struct {
+		name             string
+		overlayCompat    bool
+		intf             *zconfig.NetworkAdapter
+		expectedError    string
+		expectedUnderlay bool
+		expectedWarnings []string
+	}{
+		{
+			name:             "mode off",
+			intf:             overlayIntf(localID),
+			expectedError:    "Static IPv6 addressing",
+			expectedUnderlay: true,
+		},
+		{
+			name:             "mode on",
+			overlayCompat:    true,
+			intf:             overlayIntf(localID),
+			expectedUnderlay: true,
+			expectedWarnings: []string{
+				"App overlay interface eth0: dropped the overlay cryptoEid",
+				"App overlay interface eth0: dropped the overlay EID fd45:efca:3607:4c1d:eef9:d4e6:8d1a:af2b",
+				"App overlay interface eth0: dropped the overlay lispsignature",
+				"App overlay interface eth0: dropped the overlay pemcert",
+				"App overlay interface eth0: dropped the overlay pemprivatekey",
+			},
+		},
+		{
+			name:          "mode on with an IPv4 address",
+			overlayCompat: true,
+			intf: &zconfig.NetworkAdapter{
+				Name:          "eth0",
+				NetworkId:     localID,
+				Addr:          "10.1.0.10",
+				Lispsignature: "signature",
+			},
+			expectedUnderlay: true,
+			expectedWarnings: []string{
+				"App overlay interface eth0: dropped the overlay lispsignature",
+			},
+		},
+		{
+			name:             "mode on without overlay fields",
+			overlayCompat:    true,
+			intf:             &zconfig.NetworkAdapter{Name: "eth0", NetworkId: localID},
+			expectedUnderlay: true,
+		},
+		{
+			name:          "mode on with a mesh network instance",
+			overlayCompat: true,
+			intf:          overlayIntf(meshID),
+		},
+	}
+	for _, test := range overlayCompatTestParams {
+		getconfigCtx := initGetConfigCtx(t)
+		getconfigCtx.zedagentCtx.globalConfig.SetGlobalValueBool(
+			types.NetworkOverlayCompat, test.overlayCompat)
+		appinstancePrevConfigHash = nil
+		appinstancePrevElementHash = make(map[string][]byte)
+		app := newTestAppInstance(appID, "overlay")
+		app.Interfaces = []*zconfig.NetworkAdapter{test.intf}
+		parseAppInstanceConfig(&zconfig.EdgeDevConfig{
+			Apps: []*zconfig.AppInstanceConfig{app},
+			NetworkInstances: []*zconfig.NetworkInstanceConfig{
+				{Uuidandversion: &zconfig.UUIDandVersion{Uuid: localID},
+					InstType: zconfig.ZNetworkInstType_ZnetInstLocal},
+				{Uuidandversion: &zconfig.UUIDandVersion{Uuid: meshID},
+					InstType: zconfig.ZNetworkInstType_ZnetInstMesh},
+			},
+		}, getconfigCtx)
+		c, err := getconfigCtx.pubAppInstanceConfig.Get(appID)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		appInstance := c.(types.AppInstanceConfig)
+		warnings := strings.Join(appInstance.OverlayCompatWarnings, "\n")
+		if expected := strings.Join(test.expectedWarnings, "\n"); warnings != expected {
+			t.Errorf("%s: want warnings %q, but got %q", test.name,
+				expected, warnings)
+		}
+		var errStr string
+		if len(appInstance.Errors) != 0 {
+			errStr = appInstance.Errors[0].Error
+		}
+		if test.expectedError == "" && errStr != "" ||
+			!strings.Contains(errStr, test.expectedError) {
+			t.Errorf("%s: want error %q, but got %q", test.name,
+				test.expectedError, errStr)
+		}
+		if len(appInstance.UnderlayNetworkList) != 0 != test.expectedUnderlay {
+			t.Errorf("%s: want underlay %t, but got %d", test.name,
+				test.expectedUnderlay, len(appInstance.UnderlayNetworkList))
+			continue
+		}
+		if test.expectedUnderlay && test.expectedError == "" {
+			ulCfg := appInstance.UnderlayNetworkList[0]
+			ipv4 := net.ParseIP(test.intf.Addr).To4()
+			if !ulCfg.AppIPAddr.To4().Equal(ipv4) {
+				t.Errorf("%s: want address %s, but got %s", test.name,
+					ipv4, ulCfg.AppIPAddr)
+			}
+			if test.intf.MacAddress != "" &&
+				ulCfg.AppMacAddr.String() != test.intf.MacAddress {
+				t.Errorf("%s: want MAC %s, but got %s", test.name,
+					test.intf.MacAddress, ulCfg.AppMacAddr)
+			}
+		}
+		// The config itself is not modified
+		if len(test.expectedWarnings) != 0 &&
+			test.intf.Lispsignature != "signature" {
+			t.Errorf("%s: want lispsignature in the config, but got %q",
+				test.name, test.intf.Lispsignature)
+		}
+	}
+}
+
+func TestParseAppInstanceConfigNoNetworking(t *testing.T) {
+	const (
+		uuidA  = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+		niX    = "6ba7b812-9dad-11d1-80b4-00c04fd430c8"
+		niMesh = "6ba7b813-9dad-11d1-80b4-00c04fd430c8"
+	)
+	testMatrix := map[string]struct {
+		interfaces           []*zconfig.NetworkAdapter
+		expectedNoNetworking bool
+		expectedUnderlays    int
+		expectedErrors       int
+	}{
+		"No interfaces": {
+			expectedNoNetworking: true,
+		},
+		"One interface": {
+			interfaces: []*zconfig.NetworkAdapter{
+				{Name: "eth0", NetworkId: niX},
+			},
+			expectedUnderlays: 1,
+		},
+		"Interface which failed to parse": {
+			interfaces: []*zconfig.NetworkAdapter{
+				{Name: "eth0", NetworkId: "6ba7b814-9dad-11d1-80b4-00c04fd430c8"},
+			},
+			expectedUnderlays: 1,
+			expectedErrors:    1,
+		},
+		"Interface not in use": {
+			interfaces: []*zconfig.NetworkAdapter{
+				{Name: "eth0", NetworkId: niMesh},
+			},
+		},
+	}
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		appinstancePrevConfigHash = nil
+		appinstancePrevElementHash = make(map[string][]byte)
+		appA := newTestAppInstance(uuidA, "appA")
+		appA.Interfaces = test.interfaces
+		config := &zconfig.EdgeDevConfig{
+			Apps: []*zconfig.AppInstanceConfig{appA},
+			NetworkInstances: []*zconfig.NetworkInstanceConfig{
+				{Uuidandversion: &zconfig.UUIDandVersion{Uuid: niX},
+					InstType: zconfig.ZNetworkInstType_ZnetInstLocal},
+				{Uuidandversion: &zconfig.UUIDandVersion{Uuid: niMesh},
+					InstType: zconfig.ZNetworkInstType_ZnetInstMesh},
+			},
+		}
+		parseAppInstanceConfig(config, getconfigCtx)
+		c, err := getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+		assert.Nil(t, err, testname)
+		appInstance := c.(types.AppInstanceConfig)
+		assert.Equal(t, test.expectedNoNetworking, appInstance.NoNetworking,
+			testname)
+		assert.Len(t, appInstance.UnderlayNetworkList,
+			test.expectedUnderlays, testname)
+		assert.Len(t, appInstance.Errors, test.expectedErrors, testname)
+	}
+}
+
+func TestParseAppInstanceConfigRateLimit(t *testing.T) {
+	const (
+		uuidA = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+		niX   = "6ba7b812-9dad-11d1-80b4-00c04fd430c8"
+	)
+	testMatrix := map[string]struct {
+		action         *zconfig.ACEAction
+		expectedAction types.ACEAction
+		expectedError  string
+	}{
+		"Short unit": {
+			action: &zconfig.ACEAction{Limit: true, Limitrate: 10,
+				Limitunit: "m", Limitburst: 20},
+			expectedAction: types.ACEAction{Limit: true, LimitRate: 10,
+				LimitUnit: "m", LimitBurst: 20},
+		},
+		"Normalized unit": {
+			action: &zconfig.ACEAction{Limit: true, Limitrate: 10,
+				Limitunit: "PPS"},
+			expectedAction: types.ACEAction{Limit: true, LimitRate: 10,
+				LimitUnit: "s"},
+		},
+		"Default unit": {
+			action:         &zconfig.ACEAction{Limit: true, Limitrate: 10},
+			expectedAction: types.ACEAction{Limit: true, LimitRate: 10, LimitUnit: "s"},
+		},
+		"Clamped": {
+			action: &zconfig.ACEAction{Limit: true, Limitrate: math.MaxUint32,
+				Limitunit: "hour", Limitburst: math.MaxUint32},
+			expectedAction: types.ACEAction{Limit: true,
+				LimitRate: math.MaxInt32, LimitUnit: "h",
+				LimitBurst: math.MaxInt32},
+		},
+		"Unknown unit": {
+			action: &zconfig.ACEAction{Limit: true, Limitrate: 10,
+				Limitunit: "kbps"},
+			expectedError: `ACL 1: unknown rate limit unit "kbps"`,
+		},
+		"No limit": {
+			action: &zconfig.ACEAction{Limitrate: 10, Limitunit: "kbps"},
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		appinstancePrevConfigHash = nil
+		appinstancePrevElementHash = make(map[string][]byte)
+		appA := newTestAppInstance(uuidA, "appA")
+		appA.Interfaces = []*zconfig.NetworkAdapter{
+			{
+				Name:      "eth0",
+				NetworkId: niX,
+				Acls: []*zconfig.ACE{
+					{Id: 1, Actions: []*zconfig.ACEAction{test.action}},
+				},
+			},
+		}
+		config := &zconfig.EdgeDevConfig{
+			Apps: []*zconfig.AppInstanceConfig{appA},
+			NetworkInstances: []*zconfig.NetworkInstanceConfig{
+				{Uuidandversion: &zconfig.UUIDandVersion{Uuid: niX}},
+			},
+		}
+		parseAppInstanceConfig(config, getconfigCtx)
+		c, err := getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+		assert.Nil(t, err, testname)
+		appInstance := c.(types.AppInstanceConfig)
+		if test.expectedError != "" {
+			if assert.Len(t, appInstance.Errors, 1, testname) {
+				assert.Equal(t, types.AppConfigErrorBadACL,
+					appInstance.Errors[0].Category, testname)
+				assert.Contains(t, appInstance.Errors[0].Error,
+					test.expectedError, testname)
+			}
+			continue
+		}
+		assert.Empty(t, appInstance.Errors, testname)
+		assert.Equal(t, test.expectedAction,
+			appInstance.UnderlayNetworkList[0].ACLs[0].Actions[0], testname)
+	}
+}
+
+func TestParseAppInstanceConfigNetworkQuota(t *testing.T) {
+	webID := uuid.NewV4().String()
+	dbID := uuid.NewV4().String()
+	networkID := uuid.NewV4().String()
+	quotaTestParams := []struct {
+		maxVifs      uint32
+		webErrorIntf string
+		dbErrors     int
+	}{
+		{0, "", 0},
+		{3, "", 0},
+		{2, "", 1},
+		{1, "eth1", 1},
+	}
+	for _, test := range quotaTestParams {
+		getconfigCtx := initGetConfigCtx(t)
+		appinstancePrevConfigHash = nil
+		appinstancePrevElementHash = make(map[string][]byte)
+		web := newTestAppInstance(webID, "web")
+		web.Interfaces = []*zconfig.NetworkAdapter{
+			{Name: "eth0", NetworkId: networkID},
+			{Name: "eth1", NetworkId: networkID},
+		}
+		db := newTestAppInstance(dbID, "db")
+		db.Interfaces = []*zconfig.NetworkAdapter{
+			{Name: "eth0", NetworkId: networkID},
+		}
+		parseAppInstanceConfig(&zconfig.EdgeDevConfig{
+			Apps: []*zconfig.AppInstanceConfig{web, db},
+			NetworkInstances: []*zconfig.NetworkInstanceConfig{
+				{
+					Uuidandversion: &zconfig.UUIDandVersion{Uuid: networkID},
+					MaxVifs:        test.maxVifs,
+				},
+			},
+		}, getconfigCtx)
+
+		c, _ := getconfigCtx.pubAppInstanceConfig.Get(webID)
+		webErrors := c.(types.AppInstanceConfig).Errors
+		c, _ = getconfigCtx.pubAppInstanceConfig.Get(dbID)
+		dbErrors := c.(types.AppInstanceConfig).Errors
+		var webErrorIntf string
+		if len(webErrors) != 0 {
+			webErrorIntf = webErrors[0].IntfName
+		}
+		if len(webErrors) > 1 || webErrorIntf != test.webErrorIntf {
+			t.Errorf("max %d: want web error on %q, but got %v",
+				test.maxVifs, test.webErrorIntf, webErrors)
+		}
+		if len(dbErrors) != test.dbErrors {
+			t.Errorf("max %d: want %d db errors, but got %v",
+				test.maxVifs, test.dbErrors, dbErrors)
+		}
+		for _, appErr := range append(webErrors, dbErrors...) {
+			if appErr.Category != types.AppConfigErrorNetworkQuota ||
+				!strings.Contains(appErr.Error, "network instance "+
+					networkID+" has 3 app interfaces") {
+				t.Errorf("max %d: want a network quota error, but got %+v",
+					test.maxVifs, appErr)
+			}
+		}
+	}
+}
+
+func TestParseIpspecDhcpReservations(t *testing.T) {
+	testMatrix := map[string]struct {
+		reservation        *zconfig.DhcpReservation
+		expectedHostname   string
+		expectedReserved   bool
+		expectedErrorMatch string
+	}{
+		"Valid reservation": {
+			reservation: &zconfig.DhcpReservation{
+				Mac:      "02:16:3e:00:00:01",
+				Ip:       "10.1.0.200",
+				Hostname: "db",
+			},
+			expectedHostname: "db",
+			expectedReserved: true,
+		},
+		"Upper case MAC": {
+			reservation: &zconfig.DhcpReservation{
+				Mac: "02:16:3E:00:00:01",
+				Ip:  "10.1.0.200",
+			},
+			expectedReserved: true,
+		},
+		"Bad MAC": {
+			reservation: &zconfig.DhcpReservation{
+				Mac: "02:16:3e",
+				Ip:  "10.1.0.200",
+			},
+			expectedErrorMatch: "bad MAC",
+		},
+		"Bad IP": {
+			reservation: &zconfig.DhcpReservation{
+				Mac: "02:16:3e:00:00:01",
+				Ip:  "10.1.0.300",
+			},
+			expectedErrorMatch: "bad IP",
+		},
+		"IP outside subnet": {
+			reservation: &zconfig.DhcpReservation{
+				Mac: "02:16:3e:00:00:01",
+				Ip:  "10.2.0.200",
+			},
+			expectedErrorMatch: "outside of the subnet",
+		},
+		"IP in DHCP range": {
+			reservation: &zconfig.DhcpReservation{
+				Mac: "02:16:3e:00:00:01",
+				Ip:  "10.1.0.20",
+			},
+			expectedErrorMatch: "in the DHCP range",
+		},
+		"IP is gateway": {
+			reservation: &zconfig.DhcpReservation{
+				Mac: "02:16:3e:00:00:01",
+				Ip:  "10.1.0.1",
+			},
+			expectedErrorMatch: "gateway",
+		},
+		"Bad hostname": {
+			reservation: &zconfig.DhcpReservation{
+				Mac:      "02:16:3e:00:00:01",
+				Ip:       "10.1.0.200",
+				Hostname: "db_1",
+			},
+			expectedErrorMatch: "db_1",
+		},
+	}
+
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		ipspec := &zconfig.Ipspec{
+			Subnet:       "10.1.0.0/24",
+			Gateway:      "10.1.0.1",
+			DhcpRange:    &zconfig.IpRange{Start: "10.1.0.10", End: "10.1.0.100"},
+			Reservations: []*zconfig.DhcpReservation{test.reservation},
+		}
+		var config types.NetworkInstanceConfig
+		err := parseIpspec(ipspec, &config)
+		assert.Nil(t, err, testname)
+		if !test.expectedReserved {
+			assert.Empty(t, config.Reservations, testname)
+			if assert.Len(t, config.ReservationErrors, 1, testname) {
+				assert.Contains(t, config.ReservationErrors[0],
+					test.expectedErrorMatch, testname)
+			}
+			continue
+		}
+		assert.Empty(t, config.ReservationErrors, testname)
+		if assert.Len(t, config.Reservations, 1, testname) {
+			reservation := config.Reservations[0]
+			assert.Equal(t, "02:16:3e:00:00:01", reservation.Mac.String(),
+				testname)
+			assert.True(t, net.ParseIP(test.reservation.Ip).Equal(
+				reservation.IP), testname)
+			assert.Equal(t, test.expectedHostname, reservation.Hostname,
+				testname)
+		}
+	}
+
+	// Duplicates are dropped without affecting the others
+	ipspec := &zconfig.Ipspec{
+		Subnet:    "10.1.0.0/24",
+		DhcpRange: &zconfig.IpRange{Start: "10.1.0.10", End: "10.1.0.100"},
+		Reservations: []*zconfig.DhcpReservation{
+			{Mac: "02:16:3e:00:00:01", Ip: "10.1.0.200"},
+			{Mac: "02:16:3E:00:00:01", Ip: "10.1.0.201"},
+			{Mac: "02:16:3e:00:00:02", Ip: "10.1.0.200"},
+			{Mac: "02:16:3e:00:00:03", Ip: "10.1.0.203"},
+		},
+	}
+	var config types.NetworkInstanceConfig
+	err := parseIpspec(ipspec, &config)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(config.Reservations))
+	if assert.Equal(t, 2, len(config.ReservationErrors)) {
+		assert.Contains(t, config.ReservationErrors[0], "MAC already reserved")
+		assert.Contains(t, config.ReservationErrors[1], "IP already reserved")
+	}
+}
+
+func TestPublishNetworkInstanceConfigSwitchDNS(t *testing.T) {
+	getconfigCtx := initGetConfigCtx(t)
+	uuidStr := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	networkInstances := []*zconfig.NetworkInstanceConfig{
+		{
+			Uuidandversion: &zconfig.UUIDandVersion{
+				Uuid:    uuidStr,
+				Version: "1",
+			},
+			InstType: zconfig.ZNetworkInstType_ZnetInstSwitch,
+			Ip:       &zconfig.Ipspec{Subnet: "10.1.0.0/24"},
+			Dns: []*zconfig.ZnetStaticDNSEntry{
+				{HostName: "printer.local", Address: []string{"10.1.0.5"}},
+				{HostName: "nas.local", Address: []string{"nas"}},
+			},
+		},
+	}
+	publishNetworkInstanceConfig(getconfigCtx, networkInstances)
+	c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+	assert.Nil(t, err)
+	config := c.(types.NetworkInstanceConfig)
+	assert.Equal(t, []types.DnsNameToIP{
+		{HostName: "printer.local", IPs: []net.IP{net.ParseIP("10.1.0.5")}},
+	}, config.DnsNameToIPList)
+	assert.False(t, config.HasError())
+	assert.Equal(t, 1, len(config.DnsNameToIPWarnings))
+	assert.Contains(t, config.DnsNameToIPWarnings[0], "nas.local: nas")
+	// No L3 configuration for a switch
+	assert.Nil(t, config.Subnet.IP)
+}
+
+func TestParseAppInstanceConfigMaxApps(t *testing.T) {
+	var appIDs []string
+	config := &zconfig.EdgeDevConfig{}
+	for i := 0; i < 3; i++ {
+		appID := uuid.NewV4().String()
+		appIDs = append(appIDs, appID)
+		config.Apps = append(config.Apps,
+			newTestAppInstance(appID, fmt.Sprintf("app%d", i)))
+	}
+	maxAppsTestParams := []struct {
+		maxApps  uint32
+		rejected []bool
+	}{
+		{0, []bool{false, false, false}},
+		{3, []bool{false, false, false}},
+		{1, []bool{false, true, true}},
+	}
+	for _, test := range maxAppsTestParams {
+		getconfigCtx := initGetConfigCtx(t)
+		appinstancePrevConfigHash = nil
+		appinstancePrevElementHash = make(map[string][]byte)
+		getconfigCtx.zedagentCtx.globalConfig.SetGlobalValueInt(
+			types.AppMaxInstances, test.maxApps)
+		parseAppInstanceConfig(config, getconfigCtx)
+		for i, appID := range appIDs {
+			c, _ := getconfigCtx.pubAppInstanceConfig.Get(appID)
+			appErrors := c.(types.AppInstanceConfig).Errors
+			if !test.rejected[i] {
+				if len(appErrors) != 0 {
+					t.Errorf("max %d: want no errors for app%d, but got %v",
+						test.maxApps, i, appErrors)
+				}
+				continue
+			}
+			if len(appErrors) != 1 ||
+				appErrors[0].Category != types.AppConfigErrorTooManyApps ||
+				!strings.Contains(appErrors[0].Error, "3 configured") {
+				t.Errorf("max %d: want too many apps for app%d, but got %v",
+					test.maxApps, i, appErrors)
+			}
+		}
+
+		// Lifting the limit publishes the apps without the errors
+		getconfigCtx.zedagentCtx.globalConfig.SetGlobalValueInt(
+			types.AppMaxInstances, 0)
+		parseAppInstanceConfig(config, getconfigCtx)
+		for i, appID := range appIDs {
+			c, _ := getconfigCtx.pubAppInstanceConfig.Get(appID)
+			if appErrors := c.(types.AppInstanceConfig).Errors; len(appErrors) != 0 {
+				t.Errorf("max %d lifted: want no errors for app%d, but got %v",
+					test.maxApps, i, appErrors)
+			}
+		}
+	}
+}
+
+func TestPublishNetworkInstanceConfigSuppressNtp(t *testing.T) {
+	testMatrix := map[string]struct {
+		ntp         string
+		suppressNtp bool
+		expected    []net.IP
+	}{
+		"Device servers": {
+			expected: []net.IP{net.ParseIP("192.168.1.10")},
+		},
+		"Suppressed": {
+			suppressNtp: true,
+		},
+		"Explicit server and suppressed": {
+			ntp:         "10.1.0.2",
+			suppressNtp: true,
+			expected:    []net.IP{net.ParseIP("10.1.0.2")},
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	uuidStr := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		networkInstances := []*zconfig.NetworkInstanceConfig{
+			{
+				Uuidandversion: &zconfig.UUIDandVersion{
+					Uuid:    uuidStr,
+					Version: "1",
+				},
+				InstType: zconfig.ZNetworkInstType_ZnetInstLocal,
+				IpType:   zconfig.AddressType_IPV4,
+				Ip: &zconfig.Ipspec{
+					Subnet: "10.1.0.0/24",
+					Ntp:    test.ntp,
+				},
+				SuppressNtp: test.suppressNtp,
+			},
+		}
+		publishNetworkInstanceConfig(getconfigCtx, networkInstances)
+		c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+		assert.Nil(t, err, testname)
+		config := c.(types.NetworkInstanceConfig)
+		assert.False(t, config.HasError(), testname)
+		assert.Equal(t, test.suppressNtp, config.SuppressNtp, testname)
+		assert.Equal(t, test.expected, config.DhcpNtpServers(
+			[]net.IP{net.ParseIP("192.168.1.10")}), testname)
+	}
+}
+
+func TestParseMacAddr(t *testing.T) {
+	testMatrix := map[string]struct {
+		macStr        string
+		expectedMac   string
+		expectedError string
+	}{
+		"Colon separated": {
+			macStr:      "02:16:3e:0a:0b:0c",
+			expectedMac: "02:16:3e:0a:0b:0c",
+		},
+		"Upper case": {
+			macStr:      "02:16:3E:0A:0B:0C",
+			expectedMac: "02:16:3e:0a:0b:0c",
+		},
+		"Dash separated": {
+			macStr:      "02-16-3E-0A-0B-0C",
+			expectedMac: "02:16:3e:0a:0b:0c",
+		},
+		"Dot separated": {
+			macStr:      "0216.3e0a.0b0c",
+			expectedMac: "02:16:3e:0a:0b:0c",
+		},
+		"Bare hex": {
+			macStr:      "02163E0A0B0C",
+			expectedMac: "02:16:3e:0a:0b:0c",
+		},
+		"Too short": {
+			macStr:        "02:16:3e",
+			expectedError: "invalid MAC address",
+		},
+		"Bare hex too short": {
+			macStr:        "02163e0a0b",
+			expectedError: "invalid MAC address",
+		},
+		"Not hex": {
+			macStr:        "02163e0a0b0g",
+			expectedError: "invalid MAC address",
+		},
+		"EUI-64": {
+			macStr:        "02:16:3e:ff:fe:0a:0b:0c",
+			expectedError: "not an EUI-48",
+		},
+		"Multicast": {
+			macStr:        "01:00:5e:00:00:01",
+			expectedError: "multicast",
+		},
+		"Broadcast": {
+			macStr:        "ff:ff:ff:ff:ff:ff",
+			expectedError: "multicast",
+		},
+	}
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		mac, err := parseMacAddr(test.macStr)
+		if test.expectedError != "" {
+			assert.NotNil(t, err, testname)
+			if err != nil {
+				assert.Contains(t, err.Error(), test.expectedError,
+					testname)
+			}
+			continue
+		}
+		assert.Nil(t, err, testname)
+		assert.Equal(t, test.expectedMac, mac.String(), testname)
+	}
+}
+
+func TestPublishNetworkInstanceConfigMesh(t *testing.T) {
+	lispConfig := func() *zconfig.NetworkInstanceLispConfig {
+		return &zconfig.NetworkInstanceLispConfig{
+			LispMSs: []*zconfig.ZcServicePoint{
+				{NameOrIp: "ms1.example.net", Credential: "secret"},
+			},
+			LispInstanceId:      1000,
+			Allocate:            true,
+			Allocationprefix:    net.ParseIP("fd00::").To16(),
+			Allocationprefixlen: 64,
+		}
+	}
+	testMatrix := map[string]struct {
+		allowMesh     bool
+		lispConfig    *zconfig.NetworkInstanceLispConfig
+		modify        func(*zconfig.NetworkInstanceLispConfig)
+		expectedError string
+	}{
+		"Disabled": {
+			lispConfig:    lispConfig(),
+			expectedError: "mesh networking is deprecated and disabled",
+		},
+		"Allowed": {
+			allowMesh:  true,
+			lispConfig: lispConfig(),
+		},
+		"Allowed IPv4 prefix": {
+			allowMesh: true,
+			modify: func(c *zconfig.NetworkInstanceLispConfig) {
+				c.Allocationprefix = net.ParseIP("10.10.0.0").To4()
+				c.Allocationprefixlen = 16
+			},
+		},
+		"Allowed without allocation": {
+			allowMesh: true,
+			modify: func(c *zconfig.NetworkInstanceLispConfig) {
+				c.Allocate = false
+				c.Allocationprefix = nil
+				c.Allocationprefixlen = 0
+			},
+		},
+		"No LISP config": {
+			allowMesh:     true,
+			expectedError: "without a LISP config",
+		},
+		"No map servers": {
+			allowMesh: true,
+			modify: func(c *zconfig.NetworkInstanceLispConfig) {
+				c.LispMSs = nil
+			},
+			expectedError: "without map servers",
+		},
+		"Empty map server": {
+			allowMesh: true,
+			modify: func(c *zconfig.NetworkInstanceLispConfig) {
+				c.LispMSs = append(c.LispMSs, &zconfig.ZcServicePoint{})
+			},
+			expectedError: "map server 1 without a name or IP",
+		},
+		"Bad prefix": {
+			allowMesh: true,
+			modify: func(c *zconfig.NetworkInstanceLispConfig) {
+				c.Allocationprefix = []byte{0xfd, 0}
+			},
+			expectedError: "allocation prefix of 2 bytes",
+		},
+		"Missing prefix": {
+			allowMesh: true,
+			modify: func(c *zconfig.NetworkInstanceLispConfig) {
+				c.Allocationprefix = nil
+			},
+			expectedError: "allocation prefix of 0 bytes",
+		},
+		"Prefix length too long": {
+			allowMesh: true,
+			modify: func(c *zconfig.NetworkInstanceLispConfig) {
+				c.Allocationprefix = net.ParseIP("10.10.0.0").To4()
+				c.Allocationprefixlen = 64
+			},
+			expectedError: "allocation prefix length 64 out of range 1-32",
+		},
+		"Zero prefix length": {
+			allowMesh: true,
+			modify: func(c *zconfig.NetworkInstanceLispConfig) {
+				c.Allocationprefixlen = 0
+			},
+			expectedError: "allocation prefix length 0 out of range 1-128",
+		},
+	}
+
+	getconfigCtx := initGetConfigCtx(t)
+	uuidStr := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx.zedagentCtx.globalConfig.SetGlobalValueBool(
+			types.NetworkAllowMesh, test.allowMesh)
+		apiConfigEntry := &zconfig.NetworkInstanceConfig{
+			Uuidandversion: &zconfig.UUIDandVersion{
+				Uuid:    uuidStr,
+				Version: "1",
+			},
+			InstType: zconfig.ZNetworkInstType_ZnetInstMesh,
+			IpType:   zconfig.AddressType_IPV6,
+		}
+		cfg := test.lispConfig
+		if test.modify != nil {
+			cfg = lispConfig()
+			test.modify(cfg)
+		}
+		if cfg != nil {
+			apiConfigEntry.Cfg = &zconfig.NetworkInstanceOpaqueConfig{
+				Type:       zconfig.ZNetworkOpaqueConfigType_ZNetOConfigLisp,
+				LispConfig: cfg,
+			}
+		}
+		publishNetworkInstanceConfig(getconfigCtx,
+			[]*zconfig.NetworkInstanceConfig{apiConfigEntry})
+		c, err := getconfigCtx.pubNetworkInstanceConfig.Get(uuidStr)
+		assert.Nil(t, err, testname)
+		config := c.(types.NetworkInstanceConfig)
+		if test.expectedError == "" {
+			assert.False(t, config.HasError(), testname)
+		} else {
+			assert.True(t, config.HasError(), testname)
+			assert.Contains(t, config.Error, test.expectedError, testname)
+		}
+	}
+}
+
+func TestParseAppInstanceOpsCmd(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	testMatrix := map[string]struct {
+		opsTime         string
+		expectedWarning string
+	}{
+		"No apply time": {},
+		"Past": {
+			opsTime: "2021-05-01T12:00:00Z",
+		},
+		"Near future": {
+			opsTime: "2021-06-02T12:00:00Z",
+		},
+		"Near future mm/dd/yy": {
+			opsTime: "06/20/21:02:30",
+		},
+		"Years out": {
+			opsTime:         "2031-06-01T12:00:00Z",
+			expectedWarning: "restart 3 apply time 2031-06-01T12:00:00Z is more than 30 days after the device time 2021-06-01T12:00:00Z",
+		},
+		"Years out mm/dd/yy": {
+			opsTime:         "06/01/29:12:00",
+			expectedWarning: "is more than 30 days after",
+		},
+		"Not parsed": {
+			opsTime: "tomorrow",
+		},
+	}
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		cmd := &zconfig.InstanceOpsCmd{Counter: 3, OpsTime: test.opsTime}
+		opsCmd := parseAppInstanceOpsCmd("app", "restart", cmd, now)
+		assert.Equal(t, uint32(3), opsCmd.Counter, testname)
+		assert.Equal(t, test.opsTime, opsCmd.ApplyTime, testname)
+		if test.expectedWarning == "" {
+			assert.Empty(t, opsCmd.ApplyTimeWarning, testname)
+		} else {
+			assert.Contains(t, opsCmd.ApplyTimeWarning,
+				test.expectedWarning, testname)
+		}
+	}
+
+	// Recorded on the app instance
+	getconfigCtx := initGetConfigCtx(t)
+	appinstancePrevConfigHash = nil
+	appinstancePrevElementHash = make(map[string][]byte)
+	uuidStr := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	app := newTestAppInstance(uuidStr, "app")
+	app.Restart = &zconfig.InstanceOpsCmd{Counter: 1}
+	app.Purge = &zconfig.InstanceOpsCmd{Counter: 2,
+		OpsTime: time.Now().Add(5 * 365 * 24 * time.Hour).Format(time.RFC3339)}
+	parseAppInstanceConfig(&zconfig.EdgeDevConfig{
+		Apps: []*zconfig.AppInstanceConfig{app},
+	}, getconfigCtx)
+	c, err := getconfigCtx.pubAppInstanceConfig.Get(uuidStr)
+	assert.Nil(t, err)
+	config := c.(types.AppInstanceConfig)
+	assert.Empty(t, config.RestartCmd.ApplyTimeWarning)
+	assert.Contains(t, config.PurgeCmd.ApplyTimeWarning, "purge 2 apply time")
+	assert.Empty(t, config.Errors)
+	assert.Equal(t, []string{config.PurgeCmd.ApplyTimeWarning},
+		config.OpsCmdWarnings())
+}
+
+func TestFindPortMapConflicts(t *testing.T) {
+	webID := uuid.NewV4().String()
+	proxyID := uuid.NewV4().String()
+	localID := uuid.NewV4().String()
+	otherID := uuid.NewV4().String()
+	portMap := func(id int32, protocol string, lport string,
+		appPort uint32) *zconfig.ACE {
+		return &zconfig.ACE{
+			Id: id,
+			Matches: []*zconfig.ACEMatch{
+				{Type: "protocol", Value: protocol},
+				{Type: "lport", Value: lport},
+			},
+			Actions: []*zconfig.ACEAction{
+				{Portmap: true, AppPort: appPort},
+			},
+		}
+	}
+	allow := func(id int32, protocol string, lport string) *zconfig.ACE {
+		return &zconfig.ACE{
+			Id: id,
+			Matches: []*zconfig.ACEMatch{
+				{Type: "protocol", Value: protocol},
+				{Type: "lport", Value: lport},
+			},
+			Actions: []*zconfig.ACEAction{{}},
+		}
+	}
+	intf := func(name string, networkID string,
+		acl *zconfig.ACE) *zconfig.NetworkAdapter {
+		return &zconfig.NetworkAdapter{Name: name, NetworkId: networkID,
+			Acls: []*zconfig.ACE{acl}}
+	}
+	webIntf := intf("eth0", localID, portMap(1, "tcp", "8080", 80))
+	proxyIntf := intf("eth1", localID, portMap(2, "TCP", "8080", 8080))
+	portMapTestParams := []struct {
+		name              string
+		webIntfs          []*zconfig.NetworkAdapter
+		proxyIntfs        []*zconfig.NetworkAdapter
+		expectedConflicts map[string][]string
+	}{
+		{
+			name:       "different ports",
+			webIntfs:   []*zconfig.NetworkAdapter{webIntf},
+			proxyIntfs: []*zconfig.NetworkAdapter{intf("eth0", localID, portMap(1, "tcp", "8081", 80))},
+		},
+		{
+			name:       "different protocols",
+			webIntfs:   []*zconfig.NetworkAdapter{webIntf},
+			proxyIntfs: []*zconfig.NetworkAdapter{intf("eth0", localID, portMap(1, "udp", "8080", 80))},
+		},
+		{
+			name:       "different network instances",
+			webIntfs:   []*zconfig.NetworkAdapter{webIntf},
+			proxyIntfs: []*zconfig.NetworkAdapter{intf("eth0", otherID, portMap(1, "tcp", "8080", 80))},
+		},
+		{
+			name:       "not a port map",
+			webIntfs:   []*zconfig.NetworkAdapter{webIntf},
+			proxyIntfs: []*zconfig.NetworkAdapter{intf("eth0", localID, allow(1, "tcp", "8080"))},
+		},
+		{
+			name:       "two apps",
+			webIntfs:   []*zconfig.NetworkAdapter{webIntf},
+			proxyIntfs: []*zconfig.NetworkAdapter{proxyIntf},
+			expectedConflicts: map[string][]string{
+				webID: {"App web interface eth0 ACL 1: port map of tcp port 8080 on network instance " +
+					localID + " conflicts with app proxy interface eth1 ACL 2"},
+				proxyID: {"App proxy interface eth1 ACL 2: port map of tcp port 8080 on network instance " +
+					localID + " conflicts with app web interface eth0 ACL 1"},
+			},
+		},
+		{
+			name: "two interfaces",
+			webIntfs: []*zconfig.NetworkAdapter{
+				intf("eth0", localID, portMap(1, "udp", "53", 53)),
+				intf("eth1", localID, portMap(2, "udp", "53", 5353)),
+			},
+			expectedConflicts: map[string][]string{
+				webID: {
+					"App web interface eth0 ACL 1: port map of udp port 53 on network instance " +
+						localID + " conflicts with app web interface eth1 ACL 2",
+					"App web interface eth1 ACL 2: port map of udp port 53 on network instance " +
+						localID + " conflicts with app web interface eth0 ACL 1",
+				},
+			},
+		},
+	}
+	for _, test := range portMapTestParams {
+		web := newTestAppInstance(webID, "web")
+		web.Interfaces = test.webIntfs
+		proxy := newTestAppInstance(proxyID, "proxy")
+		proxy.Interfaces = test.proxyIntfs
+		conflicts := findPortMapConflicts(
+			[]*zconfig.AppInstanceConfig{web, proxy})
+		errStrs := make(map[string][]string)
+		for appID, appConflicts := range conflicts {
+			for _, conflict := range appConflicts {
+				errStrs[appID] = append(errStrs[appID], conflict.ErrStr)
+			}
+		}
+		if fmt.Sprint(errStrs) != fmt.Sprint(test.expectedConflicts) {
+			t.Errorf("%s: want %v, but got %v", test.name,
+				test.expectedConflicts, errStrs)
+		}
+	}
+
+	// Recorded as ACL errors on the interfaces
+	getconfigCtx := initGetConfigCtx(t)
+	appinstancePrevConfigHash = nil
+	appinstancePrevElementHash = make(map[string][]byte)
+	web := newTestAppInstance(webID, "web")
+	web.Interfaces = []*zconfig.NetworkAdapter{webIntf}
+	proxy := newTestAppInstance(proxyID, "proxy")
+	proxy.Interfaces = []*zconfig.NetworkAdapter{proxyIntf}
+	parseAppInstanceConfig(&zconfig.EdgeDevConfig{
+		Apps: []*zconfig.AppInstanceConfig{web, proxy},
+		NetworkInstances: []*zconfig.NetworkInstanceConfig{
+			{Uuidandversion: &zconfig.UUIDandVersion{Uuid: localID}},
+		},
+	}, getconfigCtx)
+	c, _ := getconfigCtx.pubAppInstanceConfig.Get(proxyID)
+	appErrors := c.(types.AppInstanceConfig).Errors
+	if len(appErrors) != 1 ||
+		appErrors[0].Category != types.AppConfigErrorBadACL ||
+		appErrors[0].IntfName != "eth1" {
+		t.Errorf("want an ACL error on eth1, but got %v", appErrors)
+	}
+}
+
+func TestFindAcceleratorMemoryErrors(t *testing.T) {
+	trainID := uuid.NewV4().String()
+	inferID := uuid.NewV4().String()
+	renderID := uuid.NewV4().String()
+	deviceIoList := []*zconfig.PhysicalIO{
+		{Ptype: zcommon.PhyIoType_PhyIoOther, Phylabel: "gpu0",
+			Logicallabel: "GPU0", Assigngrp: "gpu0",
+			AcceleratorMemoryMb: 8192},
+		// A device with two functions assigned together
+		{Ptype: zcommon.PhyIoType_PhyIoOther, Phylabel: "gpu1.0",
+			Assigngrp: "gpu1", AcceleratorMemoryMb: 2048},
+		{Ptype: zcommon.PhyIoType_PhyIoOther, Phylabel: "gpu1.1",
+			Assigngrp: "gpu1", AcceleratorMemoryMb: 2048},
+		{Ptype: zcommon.PhyIoType_PhyIoHDMI, Phylabel: "hdmi",
+			Assigngrp: "hdmi"},
+	}
+	adapter := func(name string, memoryMB uint32) []*zconfig.Adapter {
+		return []*zconfig.Adapter{{Type: zcommon.PhyIoType_PhyIoOther,
+			Name: name, AcceleratorMemoryMb: memoryMB}}
+	}
+	acceleratorTestParams := []struct {
+		name             string
+		trainAdapters    []*zconfig.Adapter
+		inferAdapters    []*zconfig.Adapter
+		renderAdapters   []*zconfig.Adapter
+		expectedErrors   map[string]string
+		expectedWarnings map[string]string
+	}{
+		{
+			name:          "no reservations",
+			trainAdapters: adapter("gpu0", 0),
+		},
+		{
+			name:           "within capacity",
+			trainAdapters:  adapter("gpu0", 4096),
+			inferAdapters:  adapter("GPU0", 2048),
+			renderAdapters: adapter("gpu0", 2048),
+		},
+		{
+			name:          "group capacity",
+			trainAdapters: adapter("gpu1", 4096),
+		},
+		{
+			name:          "exceeds capacity",
+			trainAdapters: adapter("gpu0", 16384),
+			inferAdapters: adapter("gpu0", 8192),
+			expectedErrors: map[string]string{
+				trainID: "App train adapter gpu0: accelerator memory of 16384 MB exceeds the 8192 MB of the adapter",
+			},
+		},
+		{
+			name:           "over-commit",
+			trainAdapters:  adapter("gpu0", 4096),
+			inferAdapters:  adapter("GPU0", 4096),
+			renderAdapters: adapter("gpu0", 1024),
+			expectedErrors: map[string]string{
+				renderID: "App render adapter gpu0: accelerator memory of 1024 MB over-commits the 8192 MB of the adapter; 8192 MB reserved by train, infer",
+			},
+		},
+		{
+			name:          "over-commit of a group member",
+			trainAdapters: adapter("gpu1", 3072),
+			inferAdapters: adapter("gpu1.1", 2048),
+			expectedErrors: map[string]string{
+				inferID: "over-commits the 4096 MB of the adapter; 3072 MB reserved by train",
+			},
+		},
+		{
+			name:          "missing capacity advertisement",
+			trainAdapters: adapter("hdmi", 1024),
+			inferAdapters: adapter("gpu9", 1024),
+			expectedWarnings: map[string]string{
+				trainID: "App train adapter hdmi: reserving 1024 MB of accelerator memory on an adapter which does not advertise any",
+				inferID: "App infer adapter gpu9: reserving 1024 MB",
+			},
+		},
+	}
+	devices := acceleratorDevices(deviceIoList)
+	for _, test := range acceleratorTestParams {
+		train := newTestAppInstance(trainID, "train")
+		train.Adapters = test.trainAdapters
+		infer := newTestAppInstance(inferID, "infer")
+		infer.Adapters = test.inferAdapters
+		render := newTestAppInstance(renderID, "render")
+		render.Adapters = test.renderAdapters
+		accelErrors := findAcceleratorMemoryErrors(
+			[]*zconfig.AppInstanceConfig{train, infer, render}, devices)
+		errCount, warningCount := 0, 0
+		for appID, appErrors := range accelErrors {
+			if len(appErrors) != 1 {
+				t.Errorf("%s: want one finding, but got %v", test.name,
+					appErrors)
+				continue
+			}
+			expected := test.expectedErrors[appID]
+			if appErrors[0].Warning {
+				warningCount++
+				expected = test.expectedWarnings[appID]
+			} else {
+				errCount++
+			}
+			if expected == "" ||
+				!strings.Contains(appErrors[0].ErrStr, expected) {
+				t.Errorf("%s: want %q, but got %q", test.name, expected,
+					appErrors[0].ErrStr)
+			}
+		}
+		if errCount != len(test.expectedErrors) ||
+			warningCount != len(test.expectedWarnings) {
+			t.Errorf("%s: want %d errors and %d warnings, but got %d and %d",
+				test.name, len(test.expectedErrors),
+				len(test.expectedWarnings), errCount, warningCount)
+		}
+	}
+
+	// Only the errors are recorded on the app instances
+	getconfigCtx := initGetConfigCtx(t)
+	appinstancePrevConfigHash = nil
+	appinstancePrevElementHash = make(map[string][]byte)
+	train := newTestAppInstance(trainID, "train")
+	train.Adapters = append(adapter("gpu0", 8192), adapter("hdmi", 256)...)
+	infer := newTestAppInstance(inferID, "infer")
+	infer.Adapters = adapter("gpu0", 1)
+	parseAppInstanceConfig(&zconfig.EdgeDevConfig{
+		Apps:         []*zconfig.AppInstanceConfig{train, infer},
+		DeviceIoList: deviceIoList,
+	}, getconfigCtx)
+	c, _ := getconfigCtx.pubAppInstanceConfig.Get(trainID)
+	appInstance := c.(types.AppInstanceConfig)
+	if len(appInstance.Errors) != 0 {
+		t.Errorf("want no errors for train, but got %v", appInstance.Errors)
+	}
+	if memoryMB := appInstance.IoAdapterList[0].AcceleratorMemoryMB; memoryMB != 8192 {
+		t.Errorf("want 8192 MB for train, but got %d", memoryMB)
+	}
+	c, _ = getconfigCtx.pubAppInstanceConfig.Get(inferID)
+	appErrors := c.(types.AppInstanceConfig).Errors
+	if len(appErrors) != 1 ||
+		appErrors[0].Category != types.AppConfigErrorAcceleratorMemory {
+		t.Errorf("want an accelerator memory error for infer, but got %v",
+			appErrors)
+	}
+}
+
+func TestFindAppNumaPlacements(t *testing.T) {
+	appID := uuid.NewV4().String()
+	deviceIoList := []*zconfig.PhysicalIO{
+		{Ptype: zcommon.PhyIoType_PhyIoNetEth, Phylabel: "eth1",
+			Logicallabel: "nic1", Assigngrp: "eth1",
+			Numa: &zconfig.PhyIONuma{Node: 1}},
+		{Ptype: zcommon.PhyIoType_PhyIoOther, Phylabel: "gpu0",
+			Assigngrp: "gpu0", Numa: &zconfig.PhyIONuma{Node: 0}},
+		{Ptype: zcommon.PhyIoType_PhyIoOther, Phylabel: "gpu1",
+			Assigngrp: "gpu1", Numa: &zconfig.PhyIONuma{Node: 1}},
+		// A group with a member without NUMA info
+		{Ptype: zcommon.PhyIoType_PhyIoOther, Phylabel: "fpga.0",
+			Assigngrp: "fpga", Numa: &zconfig.PhyIONuma{Node: 1}},
+		{Ptype: zcommon.PhyIoType_PhyIoOther, Phylabel: "fpga.1",
+			Assigngrp: "fpga"},
+		{Ptype: zcommon.PhyIoType_PhyIoOther, Phylabel: "npu",
+			Assigngrp: "npu"},
+		// Not tied to a NUMA node
+		{Ptype: zcommon.PhyIoType_PhyIoUSB, Phylabel: "usb",
+			Assigngrp: "usb"},
+		{Ptype: zcommon.PhyIoType_PhyIoCOM, Phylabel: "COM1",
+			Logicallabel: "serial1"},
+	}
+	const (
+		none    = zconfig.AffinityPolicy_AFFINITY_POLICY_NONE
+		prefer  = zconfig.AffinityPolicy_AFFINITY_POLICY_PREFER_ADAPTER_NUMA
+		require = zconfig.AffinityPolicy_AFFINITY_POLICY_REQUIRE_ADAPTER_NUMA
+	)
+	numaTestParams := []struct {
+		policy       zconfig.AffinityPolicy
+		adapters     []string
+		expectedNode int // -1 when not placed on a node
+		expectedErr  string
+		warning      bool
+	}{
+		{none, []string{"eth1"}, -1, "", false},
+		{prefer, []string{"nic1", "gpu1"}, 1, "", false},
+		{prefer, []string{"eth1", "npu"}, -1,
+			"App numa: affinity policy prefer-adapter-numa: the NUMA node of adapter npu is not known; placed on any NUMA node", true},
+		{prefer, []string{"gpu0", "gpu1"}, -1,
+			"the adapters are on NUMA nodes [0 1]", true},
+		{require, []string{"gpu0"}, 0, "", false},
+		{require, []string{"npu"}, -1,
+			"App numa: affinity policy require-adapter-numa: the NUMA node of adapter npu is not known", false},
+		{require, []string{"gpu1", "usb", "serial1"}, 1, "", false},
+		{require, []string{"usb", "COM1"}, -1,
+			"none of the adapters is on a NUMA node", false},
+		{require, []string{"fpga"}, -1,
+			"the NUMA node of adapter fpga is not known", false},
+		{require, []string{"gpu9"}, -1,
+			"the NUMA node of adapter gpu9 is not known", false},
+		{require, nil, -1, "no adapters are assigned", false},
+		{zconfig.AffinityPolicy(7), []string{"gpu0"}, -1,
+			"unknown AffinityPolicy 7", false},
+	}
+	devices := adapterNumaNodes(deviceIoList)
+	for _, test := range numaTestParams {
+		app := newTestAppInstance(appID, "numa")
+		app.AffinityPolicy = test.policy
+		for _, name := range test.adapters {
+			app.Adapters = append(app.Adapters,
+				&zconfig.Adapter{Type: zcommon.PhyIoType_PhyIoOther,
+					Name: name})
+		}
+		placement := findAppNumaPlacements(
+			[]*zconfig.AppInstanceConfig{app}, devices)[appID]
+		node := -1
+		if placement.NodeSet {
+			node = int(placement.Node)
+		}
+		if node != test.expectedNode {
+			t.Errorf("%s %v: want node %d, but got %d", test.policy,
+				test.adapters, test.expectedNode, node)
+		}
+		if test.expectedErr == "" && placement.ErrStr != "" ||
+			!strings.Contains(placement.ErrStr, test.expectedErr) ||
+			placement.Warning != test.warning {
+			t.Errorf("%s %v: want %q (warning %t), but got %q (warning %t)",
+				test.policy, test.adapters, test.expectedErr, test.warning,
+				placement.ErrStr, placement.Warning)
+		}
+	}
+
+	// The node is in FixedResources and only the errors are recorded on
+	// the app instance
+	getconfigCtx := initGetConfigCtx(t)
+	appinstancePrevConfigHash = nil
+	appinstancePrevElementHash = make(map[string][]byte)
+	app := newTestAppInstance(appID, "numa")
+	app.AffinityPolicy = require
+	app.Adapters = []*zconfig.Adapter{
+		{Type: zcommon.PhyIoType_PhyIoNetEth, Name: "eth1"}}
+	config := &zconfig.EdgeDevConfig{
+		Apps:         []*zconfig.AppInstanceConfig{app},
+		DeviceIoList: deviceIoList,
+	}
+	parseAppInstanceConfig(config, getconfigCtx)
+	c, _ := getconfigCtx.pubAppInstanceConfig.Get(appID)
+	appInstance := c.(types.AppInstanceConfig)
+	if len(appInstance.Errors) != 0 {
+		t.Errorf("want no errors, but got %v", appInstance.Errors)
+	}
+	if appInstance.AffinityPolicy != types.AffinityPolicyRequireAdapterNuma {
+		t.Errorf("want policy %d, but got %d",
+			types.AffinityPolicyRequireAdapterNuma, appInstance.AffinityPolicy)
+	}
+	if !appInstance.FixedResources.NumaNodeSet ||
+		appInstance.FixedResources.NumaNode != 1 {
+		t.Errorf("want NUMA node 1, but got %+v", appInstance.FixedResources)
+	}
+
+	// The NUMA node of the adapter is no longer known
+	config.DeviceIoList = []*zconfig.PhysicalIO{
+		{Ptype: zcommon.PhyIoType_PhyIoNetEth, Phylabel: "eth1",
+			Assigngrp: "eth1"},
+	}
+	parseAppInstanceConfig(config, getconfigCtx)
+	c, _ = getconfigCtx.pubAppInstanceConfig.Get(appID)
+	appInstance = c.(types.AppInstanceConfig)
+	if appInstance.FixedResources.NumaNodeSet {
+		t.Errorf("want no NUMA node, but got %d",
+			appInstance.FixedResources.NumaNode)
+	}
+	if len(appInstance.Errors) != 1 ||
+		appInstance.Errors[0].Category != types.AppConfigErrorAffinity {
+		t.Errorf("want an affinity error, but got %v", appInstance.Errors)
+	}
+}
+
+func TestParseMapServers(t *testing.T) {
+	getconfigCtx := initGetConfigCtx(t)
+	lispConfig := &zconfig.NetworkInstanceLispConfig{
+		LispMSs: []*zconfig.ZcServicePoint{
+			{
+				ZsType:   zconfig.ZcServiceType_mapServer,
+				NameOrIp: "ms1.example.net",
+				CipherData: &zconfig.CipherBlock{
+					CipherContextId: "ctx1",
+					CipherData:      []byte("encrypted"),
+				},
+			},
+			{
+				ZsType:     zconfig.ZcServiceType_mapServer,
+				NameOrIp:   "ms2.example.net",
+				Credential: "secret",
+			},
+		},
+	}
+	clearText := func() uint64 {
+		m, ok := cipher.GetCipherMetrics()[agentName]
+		if !ok {
+			return 0
+		}
+		return m.TypeCounters[types.NoCipher]
+	}
+	before := clearText()
+	mapServers := parseMapServers(getconfigCtx, "ni1", lispConfig)
+	assert.Equal(t, 2, len(mapServers))
+
+	assert.Equal(t, types.MapServerType(zconfig.ZcServiceType_mapServer),
+		mapServers[0].ServiceType)
+	assert.Equal(t, "ms1.example.net", mapServers[0].NameOrIp)
+	assert.True(t, mapServers[0].IsCipher)
+	assert.Equal(t, "", mapServers[0].Credential)
+
+	assert.Equal(t, "ms2.example.net", mapServers[1].NameOrIp)
+	assert.False(t, mapServers[1].IsCipher)
+	assert.Equal(t, "secret", mapServers[1].Credential)
+	assert.NotContains(t, mapServers[1].String(), "secret")
+	assert.Equal(t, before+1, clearText())
+}
+
+func TestWithoutSecrets(t *testing.T) {
+	withCredential := &zconfig.NetworkInstanceConfig{
+		Displayname: "mesh",
+		Cfg: &zconfig.NetworkInstanceOpaqueConfig{
+			LispConfig: &zconfig.NetworkInstanceLispConfig{
+				LispMSs: []*zconfig.ZcServicePoint{
+					{NameOrIp: "ms1.example.net", Credential: "secret"},
+				},
+			},
+		},
+	}
+	withoutCredential := &zconfig.NetworkInstanceConfig{
+		Displayname: "local",
+	}
+	networkInstances := []*zconfig.NetworkInstanceConfig{
+		withCredential, withoutCredential}
+
+	redacted := withoutSecrets(networkInstances)
+	assert.Equal(t, 2, len(redacted))
+	assert.NotContains(t, fmt.Sprintf("%+v", redacted), "secret")
+	assert.Equal(t, "mesh", redacted[0].GetDisplayname())
+	assert.True(t, withoutCredential == redacted[1])
+
+	// The config itself is unchanged
+	assert.Equal(t, "secret",
+		withCredential.GetCfg().GetLispConfig().GetLispMSs()[0].GetCredential())
+}
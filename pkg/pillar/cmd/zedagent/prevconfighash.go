@@ -0,0 +1,112 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Save the hashes of the config which was last parsed, so that a restart
+// of zedagent with an unchanged config does not re-parse and re-publish
+// everything.
+// Only the parsers which do nothing but publish are included; the
+// publications are reloaded from /run when zedagent restarts. They do not
+// survive a reboot, hence the saved hashes are only used in the same boot.
+
+package zedagent
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	fileutils "github.com/lf-edge/eve/pkg/pillar/utils/file"
+)
+
+const (
+	prevConfigHashFilename = types.IdentityDirname + "/prev-config-hash"
+	bootIDFilename         = "/proc/sys/kernel/random/boot_id"
+)
+
+// prevConfigHashes is what we save in prevConfigHashFilename
+type prevConfigHashes struct {
+	BootID              string
+	BaseOsConfig        []byte
+	NetworkConfig       []byte
+	NetworkInstance     []byte
+	Datastore           []byte
+	AppInstance         []byte
+	AppInstanceElements map[string][]byte
+}
+
+// The last saved content to avoid rewriting an unchanged file
+var prevConfigHashSaved []byte
+
+func readBootID() string {
+	b, err := ioutil.ReadFile(bootIDFilename)
+	if err != nil {
+		log.Errorf("readBootID: %s", err)
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// loadPrevConfigHashes sets the previous config hashes from the file
+// if it was saved in this boot. Otherwise, including when the file is
+// missing or corrupt, they are left as is which forces a full parse.
+func loadPrevConfigHashes(filename string, bootID string) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		log.Functionf("loadPrevConfigHashes: %s", err)
+		return
+	}
+	var hashes prevConfigHashes
+	if err := json.Unmarshal(b, &hashes); err != nil {
+		log.Warnf("loadPrevConfigHashes: corrupt %s: %s", filename, err)
+		return
+	}
+	if bootID == "" || hashes.BootID != bootID {
+		log.Noticef("loadPrevConfigHashes: ignoring %s from boot %s",
+			filename, hashes.BootID)
+		return
+	}
+	baseOSConfigPrevConfigHash = hashes.BaseOsConfig
+	networkConfigPrevConfigHash = hashes.NetworkConfig
+	networkInstancePrevConfigHash = hashes.NetworkInstance
+	datastoreConfigPrevConfigHash = hashes.Datastore
+	appinstancePrevConfigHash = hashes.AppInstance
+	appinstancePrevElementHash = make(map[string][]byte)
+	for uuidStr, h := range hashes.AppInstanceElements {
+		appinstancePrevElementHash[uuidStr] = h
+	}
+	prevConfigHashSaved = b
+	log.Noticef("loadPrevConfigHashes: loaded from %s", filename)
+}
+
+// savePrevConfigHashes writes the previous config hashes to the file
+// if they changed since the last save
+func savePrevConfigHashes(filename string, bootID string) {
+	if bootID == "" {
+		return
+	}
+	hashes := prevConfigHashes{
+		BootID:              bootID,
+		BaseOsConfig:        baseOSConfigPrevConfigHash,
+		NetworkConfig:       networkConfigPrevConfigHash,
+		NetworkInstance:     networkInstancePrevConfigHash,
+		Datastore:           datastoreConfigPrevConfigHash,
+		AppInstance:         appinstancePrevConfigHash,
+		AppInstanceElements: appinstancePrevElementHash,
+	}
+	b, err := json.Marshal(hashes)
+	if err != nil {
+		log.Errorf("savePrevConfigHashes: %s", err)
+		return
+	}
+	if bytes.Equal(b, prevConfigHashSaved) {
+		return
+	}
+	if err := fileutils.WriteRename(filename, b); err != nil {
+		// Can fail if low on disk space
+		log.Errorf("savePrevConfigHashes: %s", err)
+		return
+	}
+	prevConfigHashSaved = b
+}
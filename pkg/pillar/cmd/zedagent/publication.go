@@ -0,0 +1,47 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Setup of the publications of zedagent
+
+package zedagent
+
+import (
+	"github.com/lf-edge/eve/pkg/pillar/base"
+	"github.com/lf-edge/eve/pkg/pillar/pubsub"
+)
+
+// zedagentTopic is a type published by zedagent. Publish and Unpublish
+// invoke its logging hooks, hence all of them need to be implemented.
+type zedagentTopic interface {
+	Key() string
+	base.LoggableObject
+}
+
+// zedagentPublicationOptions are the options of a zedagent publication
+type zedagentPublicationOptions struct {
+	// Persistent publications survive a reboot
+	Persistent bool
+	// ClearRestarted tells the subscribers that zedagent has nothing
+	// more to publish after a restart of zedagent
+	ClearRestarted bool
+}
+
+// newZedagentPublication returns the publication of topicType by zedagent.
+// As for the other publications setup when zedagent starts, any error is
+// fatal.
+func newZedagentPublication(ps *pubsub.PubSub, topicType zedagentTopic,
+	opts zedagentPublicationOptions) pubsub.Publication {
+
+	pub, err := ps.NewPublication(pubsub.PublicationOptions{
+		AgentName:  agentName,
+		TopicType:  topicType,
+		Persistent: opts.Persistent,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if opts.ClearRestarted {
+		pub.ClearRestarted()
+	}
+	return pub
+}
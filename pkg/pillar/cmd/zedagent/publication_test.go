@@ -0,0 +1,75 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package zedagent
+
+import (
+	"testing"
+
+	"github.com/lf-edge/eve/pkg/pillar/base"
+	"github.com/lf-edge/eve/pkg/pillar/pubsub"
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// The logging hooks invoked for testTopic, by hook and key
+var testTopicHooks = make(map[string][]string)
+
+type testTopic struct {
+	Name  string
+	Value int
+}
+
+func (topic testTopic) Key() string {
+	return topic.Name
+}
+
+func (topic testTopic) LogKey() string {
+	return "test_topic-" + topic.Key()
+}
+
+func (topic testTopic) LogCreate(logBase *base.LogObject) {
+	testTopicHooks["create"] = append(testTopicHooks["create"], topic.Key())
+}
+
+func (topic testTopic) LogModify(logBase *base.LogObject, old interface{}) {
+	testTopicHooks["modify"] = append(testTopicHooks["modify"], topic.Key())
+}
+
+func (topic testTopic) LogDelete(logBase *base.LogObject) {
+	testTopicHooks["delete"] = append(testTopicHooks["delete"], topic.Key())
+}
+
+func TestNewZedagentPublicationLogHooks(t *testing.T) {
+	testTopicHooks = make(map[string][]string)
+	ps := pubsub.New(&pubsub.EmptyDriver{}, logger, log)
+	pub := newZedagentPublication(ps, testTopic{},
+		zedagentPublicationOptions{})
+
+	pub.Publish("a", testTopic{Name: "a", Value: 1})
+	pub.Publish("b", testTopic{Name: "b", Value: 1})
+	assert.Equal(t, []string{"a", "b"}, testTopicHooks["create"])
+	assert.Empty(t, testTopicHooks["modify"])
+
+	// Unchanged items are not logged
+	pub.Publish("a", testTopic{Name: "a", Value: 1})
+	assert.Empty(t, testTopicHooks["modify"])
+	pub.Publish("a", testTopic{Name: "a", Value: 2})
+	assert.Equal(t, []string{"a"}, testTopicHooks["modify"])
+
+	pub.Unpublish("b")
+	assert.Equal(t, []string{"b"}, testTopicHooks["delete"])
+	assert.Equal(t, 1, len(pub.GetAll()))
+}
+
+func TestNewZedagentPublicationTypes(t *testing.T) {
+	ps := pubsub.New(&pubsub.EmptyDriver{}, logger, log)
+	pub := newZedagentPublication(ps, types.ZedAgentStatus{},
+		zedagentPublicationOptions{ClearRestarted: true})
+	status := types.ZedAgentStatus{Name: agentName}
+	assert.Nil(t, pub.Publish(status.Key(), status))
+	item, err := pub.Get(status.Key())
+	assert.Nil(t, err)
+	assert.Equal(t, status, item.(types.ZedAgentStatus))
+	assert.Nil(t, pub.Unpublish(status.Key()))
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Quiet hours are a daily window in the time zone of the device during
+// which changes to the app instances and the base OS, and reboot commands
+// which are not urgent, are deferred. They are applied when the window ends.
+
+package zedagent
+
+import (
+	"time"
+
+	zconfig "github.com/lf-edge/eve/api/go/config"
+	"github.com/lf-edge/eve/pkg/pillar/types"
+)
+
+// quietHoursWindow returns the window of the quiet hours, and false if
+// there are none
+func quietHoursWindow(gc *types.ConfigItemValueMap) (timeWindow, bool) {
+	start := gc.GlobalValueInt(types.QuietHoursStart)
+	end := gc.GlobalValueInt(types.QuietHoursEnd)
+	if start == end {
+		return timeWindow{}, false
+	}
+	tz := gc.GlobalValueString(types.Timezone)
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		log.Errorf("quietHoursWindow: timezone %s: %v; using UTC", tz, err)
+		loc = time.UTC
+	}
+	return timeWindow{start: start, end: end, loc: loc}, true
+}
+
+// inQuietHours returns true if now is inside the quiet hours, in which
+// case it records when they end
+func inQuietHours(getconfigCtx *getconfigContext, now time.Time) bool {
+	window, ok := quietHoursWindow(&getconfigCtx.zedagentCtx.globalConfig)
+	if !ok || !window.contains(now) {
+		return false
+	}
+	getconfigCtx.quietHoursEnd = window.endAfter(now)
+	return true
+}
+
+// deferToQuietHoursEnd records the config whose changes to the app
+// instances and the base OS were left out by parseConfigObjects
+func deferToQuietHoursEnd(getconfigCtx *getconfigContext,
+	config *zconfig.EdgeDevConfig, quiet bool) {
+
+	wasDeferred := getconfigCtx.quietHoursConfig != nil
+	if quiet {
+		getconfigCtx.quietHoursConfig = config
+	} else {
+		getconfigCtx.quietHoursConfig = nil
+	}
+	if quiet && !wasDeferred {
+		log.Noticef("deferToQuietHoursEnd: app instance and base OS changes deferred until %s",
+			getconfigCtx.quietHoursEnd.Format(time.RFC3339))
+	}
+	if quiet != wasDeferred {
+		publishZedAgentStatus(getconfigCtx)
+	}
+}
+
+// applyQuietHoursDeferred applies what was deferred once the quiet hours
+// have ended. Returns true if it initiated a reboot.
+func applyQuietHoursDeferred(getconfigCtx *getconfigContext,
+	now time.Time) bool {
+
+	if getconfigCtx.quietHoursConfig == nil && getconfigCtx.quietHoursReboot == nil {
+		return false
+	}
+	if inQuietHours(getconfigCtx, now) {
+		return false
+	}
+	config := getconfigCtx.quietHoursConfig
+	reboot := getconfigCtx.quietHoursReboot
+	getconfigCtx.quietHoursConfig = nil
+	getconfigCtx.quietHoursReboot = nil
+	publishZedAgentStatus(getconfigCtx)
+	if reboot != nil {
+		// The config is applied after the reboot
+		saveRebootConfig(*reboot)
+		handleRebootCmd(getconfigCtx.zedagentCtx,
+			"NORMAL: reboot command deferred by the quiet hours")
+		return true
+	}
+	log.Noticef("applyQuietHoursDeferred: applying app instance and base OS changes")
+	parseBaseOS(getconfigCtx, config)
+	parseBaseOsConfig(getconfigCtx, config)
+	parseAppInstanceConfig(config, getconfigCtx)
+	savePrevConfigHashes(prevConfigHashFilename, getconfigCtx.bootID)
+	return false
+}
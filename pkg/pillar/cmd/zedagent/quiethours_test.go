@@ -0,0 +1,213 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package zedagent
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	zconfig "github.com/lf-edge/eve/api/go/config"
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func setQuietHours(getconfigCtx *getconfigContext, start, end uint32,
+	tz string) {
+
+	gc := &getconfigCtx.zedagentCtx.globalConfig
+	gc.SetGlobalValueInt(types.QuietHoursStart, start)
+	gc.SetGlobalValueInt(types.QuietHoursEnd, end)
+	gc.SetGlobalValueString(types.Timezone, tz)
+}
+
+func getZedAgentStatus(t *testing.T,
+	getconfigCtx *getconfigContext) types.ZedAgentStatus {
+
+	item, err := getconfigCtx.pubZedAgentStatus.Get(agentName)
+	assert.Nil(t, err)
+	return item.(types.ZedAgentStatus)
+}
+
+func TestInQuietHours(t *testing.T) {
+	testMatrix := map[string]struct {
+		start       uint32
+		end         uint32
+		tz          string
+		now         time.Time
+		expectQuiet bool
+		expectedEnd time.Time
+	}{
+		"No quiet hours": {
+			tz:  "UTC",
+			now: time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+		},
+		"Inside": {
+			start:       9,
+			end:         17,
+			tz:          "UTC",
+			now:         time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+			expectQuiet: true,
+			expectedEnd: time.Date(2021, 6, 1, 17, 0, 0, 0, time.UTC),
+		},
+		"Just before the end": {
+			start:       9,
+			end:         17,
+			tz:          "UTC",
+			now:         time.Date(2021, 6, 1, 16, 59, 59, 0, time.UTC),
+			expectQuiet: true,
+			expectedEnd: time.Date(2021, 6, 1, 17, 0, 0, 0, time.UTC),
+		},
+		"At the end": {
+			start: 9,
+			end:   17,
+			tz:    "UTC",
+			now:   time.Date(2021, 6, 1, 17, 0, 0, 0, time.UTC),
+		},
+		"Inside in the local time": {
+			start:       9,
+			end:         17,
+			tz:          "America/New_York",
+			now:         time.Date(2021, 6, 1, 14, 0, 0, 0, time.UTC),
+			expectQuiet: true,
+			expectedEnd: time.Date(2021, 6, 1, 21, 0, 0, 0, time.UTC),
+		},
+		"Outside in the local time": {
+			start: 9,
+			end:   17,
+			tz:    "America/New_York",
+			now:   time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+		},
+		"Past midnight before midnight": {
+			start:       22,
+			end:         6,
+			tz:          "UTC",
+			now:         time.Date(2021, 6, 1, 23, 0, 0, 0, time.UTC),
+			expectQuiet: true,
+			expectedEnd: time.Date(2021, 6, 2, 6, 0, 0, 0, time.UTC),
+		},
+		"Past midnight after midnight": {
+			start:       22,
+			end:         6,
+			tz:          "UTC",
+			now:         time.Date(2021, 6, 2, 1, 0, 0, 0, time.UTC),
+			expectQuiet: true,
+			expectedEnd: time.Date(2021, 6, 2, 6, 0, 0, 0, time.UTC),
+		},
+		"Unknown timezone uses UTC": {
+			start:       9,
+			end:         17,
+			tz:          "Nowhere/Special",
+			now:         time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+			expectQuiet: true,
+			expectedEnd: time.Date(2021, 6, 1, 17, 0, 0, 0, time.UTC),
+		},
+	}
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		setQuietHours(getconfigCtx, test.start, test.end, test.tz)
+		quiet := inQuietHours(getconfigCtx, test.now)
+		assert.Equal(t, test.expectQuiet, quiet, testname)
+		if quiet {
+			assert.True(t, test.expectedEnd.Equal(getconfigCtx.quietHoursEnd),
+				"%s: end %s", testname, getconfigCtx.quietHoursEnd)
+		}
+	}
+}
+
+func TestQuietHoursDeferApps(t *testing.T) {
+	const uuidA = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	resetPrevConfigHashes()
+	getconfigCtx, _ := initGoldenConfigCtx(t)
+	setQuietHours(getconfigCtx, 9, 17, "UTC")
+	config := &zconfig.EdgeDevConfig{
+		Apps: []*zconfig.AppInstanceConfig{newTestAppInstance(uuidA, "appA")},
+	}
+
+	// Inside the quiet hours the app instance is not published
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	quiet := inQuietHours(getconfigCtx, now)
+	assert.True(t, quiet)
+	parseConfigObjects(config, getconfigCtx, quiet)
+	deferToQuietHoursEnd(getconfigCtx, config, quiet)
+	_, err := getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+	assert.NotNil(t, err)
+	status := getZedAgentStatus(t, getconfigCtx)
+	assert.True(t, status.ConfigDeferred)
+	assert.Equal(t, time.Date(2021, 6, 1, 17, 0, 0, 0, time.UTC),
+		status.ConfigDeferredUntil.UTC())
+
+	// Nor is it before the end
+	assert.False(t, applyQuietHoursDeferred(getconfigCtx,
+		time.Date(2021, 6, 1, 16, 59, 0, 0, time.UTC)))
+	_, err = getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+	assert.NotNil(t, err)
+
+	// At the end it is
+	assert.False(t, applyQuietHoursDeferred(getconfigCtx,
+		time.Date(2021, 6, 1, 17, 0, 0, 0, time.UTC)))
+	_, err = getconfigCtx.pubAppInstanceConfig.Get(uuidA)
+	assert.Nil(t, err)
+	assert.False(t, getZedAgentStatus(t, getconfigCtx).ConfigDeferred)
+	assert.Nil(t, getconfigCtx.quietHoursConfig)
+}
+
+func TestScheduleRebootQuietHours(t *testing.T) {
+	testMatrix := map[string]struct {
+		urgent         bool
+		expectedReboot bool
+	}{
+		"Deferred": {},
+		"Urgent": {
+			urgent:         true,
+			expectedReboot: true,
+		},
+	}
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		setQuietHours(getconfigCtx, 9, 17, "UTC")
+		rebootConfigFilename = filepath.Join(t.TempDir(), "rebootConfig")
+		rebootPrevConfigHash = nil
+		saveRebootConfig(types.DeviceOpsCmd{Counter: 1})
+
+		now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+		quiet := inQuietHours(getconfigCtx, now)
+		reboot := &zconfig.DeviceOpsCmd{Counter: 2, Urgent: test.urgent}
+		rebooted := scheduleReboot(reboot, getconfigCtx, quiet)
+		assert.Equal(t, test.expectedReboot, rebooted, testname)
+		assert.Equal(t, test.expectedReboot,
+			getconfigCtx.zedagentCtx.rebootCmd, testname)
+		if test.expectedReboot {
+			assert.Equal(t, uint32(2), readRebootConfig().Counter, testname)
+			assert.Nil(t, getconfigCtx.quietHoursReboot, testname)
+			continue
+		}
+		// The counter is saved when the reboot is done
+		assert.Equal(t, uint32(1), readRebootConfig().Counter, testname)
+		assert.True(t, getZedAgentStatus(t, getconfigCtx).ConfigDeferred,
+			testname)
+
+		// The same command is not acted on again
+		assert.False(t, scheduleReboot(reboot, getconfigCtx, quiet), testname)
+		assert.False(t, applyQuietHoursDeferred(getconfigCtx,
+			now.Add(time.Hour)), testname)
+		assert.False(t, getconfigCtx.zedagentCtx.rebootCmd, testname)
+
+		// Nor lost when zedagent restarts before the quiet hours end
+		rebootPrevConfigHash = nil
+		getconfigCtx.quietHoursReboot = nil
+		assert.False(t, scheduleReboot(reboot, getconfigCtx, quiet), testname)
+		assert.NotNil(t, getconfigCtx.quietHoursReboot, testname)
+
+		// The reboot happens when the quiet hours end
+		assert.True(t, applyQuietHoursDeferred(getconfigCtx,
+			time.Date(2021, 6, 1, 17, 30, 0, 0, time.UTC)), testname)
+		assert.True(t, getconfigCtx.zedagentCtx.rebootCmd, testname)
+		assert.Equal(t, uint32(2), readRebootConfig().Counter, testname)
+		assert.False(t, getZedAgentStatus(t, getconfigCtx).ConfigDeferred,
+			testname)
+	}
+}
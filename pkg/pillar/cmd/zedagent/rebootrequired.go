@@ -0,0 +1,226 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Detect config changes which only take effect after a reboot.
+// The config applied first after boot is the baseline. An aspect of the
+// config is pending when the current config differs from the baseline for
+// that aspect, hence the pending list is cleared by a reboot, and also if
+// the controller reverts the change. The baseline is saved with the boot
+// ID, so that it survives a restart of zedagent but not a reboot.
+// When enabled by the controller, zedagent reboots within a daily window
+// when there are pending aspects.
+
+package zedagent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	zconfig "github.com/lf-edge/eve/api/go/config"
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	fileutils "github.com/lf-edge/eve/pkg/pillar/utils/file"
+)
+
+// The saved baseline. A variable for the tests.
+var rebootRequiredFilename = types.PersistStatusDir + "/rebootRequiredBaseline"
+
+// rebootRequiredBaseline is what we save in rebootRequiredFilename
+type rebootRequiredBaseline struct {
+	BootID       string
+	Fingerprints map[string]string
+}
+
+// readRebootRequiredBaseline returns the baseline saved during bootID,
+// or nil if there is none. Without a boot ID a saved baseline can not be
+// told apart from one of an earlier boot, hence it is not used.
+func readRebootRequiredBaseline(bootID string) map[string]string {
+	if bootID == "" {
+		return nil
+	}
+	bytes, err := ioutil.ReadFile(rebootRequiredFilename)
+	if err != nil {
+		log.Functionf("readRebootRequiredBaseline - %s doesn't exist",
+			rebootRequiredFilename)
+		return nil
+	}
+	var baseline rebootRequiredBaseline
+	if err := json.Unmarshal(bytes, &baseline); err != nil {
+		// Treat the same way as a missing file
+		log.Error(err)
+		return nil
+	}
+	if baseline.BootID != bootID || baseline.Fingerprints == nil {
+		return nil
+	}
+	log.Noticef("readRebootRequiredBaseline: loaded from %s",
+		rebootRequiredFilename)
+	return baseline.Fingerprints
+}
+
+func saveRebootRequiredBaseline(bootID string, fingerprints map[string]string) {
+	if bootID == "" {
+		return
+	}
+	bytes, err := json.Marshal(rebootRequiredBaseline{
+		BootID:       bootID,
+		Fingerprints: fingerprints,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = fileutils.WriteRename(rebootRequiredFilename, bytes)
+	if err != nil {
+		// Can fail if low on disk space
+		log.Error(err)
+	}
+}
+
+// rebootRequiredAspect is a part of the config which only takes effect
+// after a reboot. fingerprint returns a string which changes when that
+// part of the config changes.
+type rebootRequiredAspect struct {
+	name        string
+	fingerprint func(config *zconfig.EdgeDevConfig) string
+}
+
+// rebootRequiredAspects is the registry of the parts of the config which
+// need a reboot. Add an entry here for a new one.
+var rebootRequiredAspects = []rebootRequiredAspect{
+	// Reassigning the physical addresses or the assignment group of the
+	// device IO adapters
+	{name: "deviceIoList", fingerprint: deviceIoFingerprint},
+}
+
+func deviceIoFingerprint(config *zconfig.EdgeDevConfig) string {
+	var adapters []string
+	for _, ioDevice := range config.GetDeviceIoList() {
+		if ioDevice == nil {
+			continue
+		}
+		var phyaddrs []string
+		for key, value := range ioDevice.Phyaddrs {
+			phyaddrs = append(phyaddrs,
+				strings.ToLower(key)+"="+value)
+		}
+		sort.Strings(phyaddrs)
+		adapters = append(adapters, fmt.Sprintf("%s:%s:%s",
+			ioDevice.Phylabel, ioDevice.Assigngrp,
+			strings.Join(phyaddrs, ",")))
+	}
+	sort.Strings(adapters)
+	return strings.Join(adapters, ";")
+}
+
+// updateRebootRequired records the baseline on the first call after boot,
+// or picks up the one saved earlier during this boot, and returns the names
+// of the aspects which differ from the baseline
+func updateRebootRequired(getconfigCtx *getconfigContext,
+	config *zconfig.EdgeDevConfig) []string {
+
+	if getconfigCtx.rebootRequiredBaseline == nil {
+		baseline := readRebootRequiredBaseline(getconfigCtx.bootID)
+		if baseline == nil {
+			baseline = make(map[string]string)
+			for _, aspect := range rebootRequiredAspects {
+				baseline[aspect.name] = aspect.fingerprint(config)
+			}
+			saveRebootRequiredBaseline(getconfigCtx.bootID, baseline)
+		}
+		getconfigCtx.rebootRequiredBaseline = baseline
+	}
+	var pending []string
+	for _, aspect := range rebootRequiredAspects {
+		if aspect.fingerprint(config) !=
+			getconfigCtx.rebootRequiredBaseline[aspect.name] {
+			pending = append(pending, aspect.name)
+		}
+	}
+	if strings.Join(pending, ",") !=
+		strings.Join(getconfigCtx.rebootRequiredPending, ",") {
+		if len(pending) == 0 {
+			log.Noticef("updateRebootRequired: no longer pending %v",
+				getconfigCtx.rebootRequiredPending)
+		} else {
+			log.Noticef("updateRebootRequired: reboot required for %v",
+				pending)
+		}
+		getconfigCtx.rebootRequiredPending = pending
+		publishZedAgentStatus(getconfigCtx)
+	}
+	getconfigCtx.zedagentCtx.globalStatus.RebootRequiredAspects = pending
+	return pending
+}
+
+// timeWindow is a daily window from the start hour up to the end hour in
+// UTC, or in loc if set. It wraps past midnight when end is before start,
+// and the same start and end means any time.
+type timeWindow struct {
+	start uint32
+	end   uint32
+	loc   *time.Location
+}
+
+func (w timeWindow) location() *time.Location {
+	if w.loc == nil {
+		return time.UTC
+	}
+	return w.loc
+}
+
+func (w timeWindow) contains(t time.Time) bool {
+	hour := uint32(t.In(w.location()).Hour())
+	switch {
+	case w.start == w.end:
+		return true
+	case w.start < w.end:
+		return hour >= w.start && hour < w.end
+	default:
+		return hour >= w.start || hour < w.end
+	}
+}
+
+// endAfter returns the first end of the window after t
+func (w timeWindow) endAfter(t time.Time) time.Time {
+	local := t.In(w.location())
+	end := time.Date(local.Year(), local.Month(), local.Day(), int(w.end),
+		0, 0, 0, w.location())
+	if !end.After(t) {
+		end = time.Date(local.Year(), local.Month(), local.Day()+1,
+			int(w.end), 0, 0, 0, w.location())
+	}
+	return end
+}
+
+// maybeAutoReboot reboots if there are pending aspects, the controller
+// enabled the automatic reboot, and now is inside the window. Called after
+// parsing a changed config and on every config timer tick.
+// Returns true if it initiated a reboot.
+func maybeAutoReboot(getconfigCtx *getconfigContext, now time.Time) bool {
+	ctx := getconfigCtx.zedagentCtx
+	if len(getconfigCtx.rebootRequiredPending) == 0 {
+		return false
+	}
+	gc := &ctx.globalConfig
+	if !gc.GlobalValueBool(types.RebootRequiredAutoReboot) {
+		return false
+	}
+	window := timeWindow{
+		start: gc.GlobalValueInt(types.RebootRequiredWindowStart),
+		end:   gc.GlobalValueInt(types.RebootRequiredWindowEnd),
+	}
+	if !window.contains(now) {
+		return false
+	}
+	if getconfigCtx.updateInprogress {
+		log.Noticef("maybeAutoReboot: deferred while testing a base OS update")
+		return false
+	}
+	infoStr := fmt.Sprintf("NORMAL: reboot required for config changes: %s",
+		strings.Join(getconfigCtx.rebootRequiredPending, ", "))
+	handleRebootCmd(ctx, infoStr)
+	return true
+}
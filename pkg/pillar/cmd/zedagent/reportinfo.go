@@ -97,7 +97,8 @@ func objectInfoTask(ctxPtr *zedagentContext, triggerInfo <-chan infoForObjectKey
 				sub := ctxPtr.subNetworkInstanceStatus
 				if c, err = sub.Get(infoForKeyMessage.objectKey); err == nil {
 					niStatus := c.(types.NetworkInstanceStatus)
-					prepareAndPublishNetworkInstanceInfoMsg(ctxPtr, niStatus, false)
+					prepareAndPublishNetworkInstanceInfoMsg(ctxPtr,
+						infoForKeyMessage.objectKey, niStatus, false)
 					ctxPtr.iteration++
 				}
 			case info.ZInfoTypes_ZiVolume:
@@ -470,6 +471,8 @@ func PublishDeviceInfoToZedCloud(ctx *zedagentContext) {
 
 	ReportDeviceInfo.RestartCounter = ctx.restartCounter
 	ReportDeviceInfo.RebootConfigCounter = ctx.rebootConfigCounter
+	ReportDeviceInfo.WipeConfirmationToken = ctx.wipeConfirmationToken
+	ReportDeviceInfo.Wipe = encodeWipeInfo(ctx)
 
 	//Operational information about TPM presence/absence/usage.
 	ReportDeviceInfo.HSMStatus = etpm.FetchTpmSwStatus()
@@ -720,10 +723,29 @@ func encodeSystemAdapterInfo(ctx *zedagentContext) *info.SystemAdapterInfo {
 		}
 		sainfo.Status[i] = dps
 	}
+	sainfo.ParseResults = encodeSystemAdapterReport(ctx)
 	log.Tracef("encodeSystemAdapterInfo: %+v", sainfo)
 	return sainfo
 }
 
+func encodeSystemAdapterReport(ctx *zedagentContext) []*info.SystemAdapterParseResult {
+	item, err := ctx.getconfigCtx.pubSystemAdapterReport.Get("global")
+	if err != nil {
+		return nil
+	}
+	report := item.(types.SystemAdapterReport)
+	results := make([]*info.SystemAdapterParseResult, len(report.Results))
+	for i, result := range report.Results {
+		results[i] = &info.SystemAdapterParseResult{
+			Name:           result.Name,
+			LowerLayerName: result.LowerLayerName,
+			Disposition:    info.SystemAdapterDisposition(result.Disposition),
+			Reason:         result.Reason,
+		}
+	}
+	return results
+}
+
 //getDataSecAtRestInfo prepares status related to Data security at Rest
 func getDataSecAtRestInfo(ctx *zedagentContext) *info.DataSecAtRest {
 	subVaultStatus := ctx.subVaultStatus
@@ -753,11 +775,16 @@ func createConfigItemStatus(
 	for key, statusCfgItem := range status.ConfigItems {
 		if statusCfgItem.Err != nil {
 			cfgItemsPtr.ConfigItems[key] = &info.ZInfoConfigItem{
-				Value: statusCfgItem.Value,
-				Error: statusCfgItem.Err.Error()}
+				Value:          statusCfgItem.Value,
+				Error:          statusCfgItem.Err.Error(),
+				EffectiveValue: statusCfgItem.EffectiveValue,
+				RequestedValue: statusCfgItem.RequestedValue,
+				Note:           statusCfgItem.Note}
 		} else {
 			cfgItemsPtr.ConfigItems[key] = &info.ZInfoConfigItem{
-				Value: statusCfgItem.Value}
+				Value:          statusCfgItem.Value,
+				EffectiveValue: statusCfgItem.EffectiveValue,
+				RequestedValue: statusCfgItem.RequestedValue}
 		}
 	}
 
@@ -768,6 +795,17 @@ func createConfigItemStatus(
 			Value: statusUnknownCfgItem.Value,
 			Error: statusUnknownCfgItem.Err.Error()}
 	}
+	cfgItemsPtr.RebootRequiredAspects = status.RebootRequiredAspects
+	for _, field := range getDeprecatedFieldStatus() {
+		cfgItemsPtr.DeprecatedConfigFields = append(
+			cfgItemsPtr.DeprecatedConfigFields,
+			&info.ZInfoDeprecatedConfigField{
+				Name:        field.name,
+				LastRunHits: field.lastRunHits,
+				TotalHits:   field.totalHits,
+				Keys:        field.keys,
+			})
+	}
 	return cfgItemsPtr
 }
 
@@ -0,0 +1,272 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// The info and the metrics of the device, the app instances, the network
+// instances and the volumes can each be reported at their own interval.
+// One ticker runs at the shortest of the intervals, and on each tick the
+// classes which are due are reported.
+
+package zedagent
+
+import (
+	"time"
+
+	"github.com/lf-edge/eve/api/go/info"
+	"github.com/lf-edge/eve/pkg/pillar/flextimer"
+	"github.com/lf-edge/eve/pkg/pillar/types"
+)
+
+// reportClass is a class of objects with its own reporting intervals
+type reportClass int
+
+const (
+	reportDevice reportClass = iota
+	reportApp
+	reportNetworkInstance
+	reportVolume
+	reportClassCount
+)
+
+func (class reportClass) String() string {
+	switch class {
+	case reportDevice:
+		return "device"
+	case reportApp:
+		return "app"
+	case reportNetworkInstance:
+		return "network instance"
+	case reportVolume:
+		return "volume"
+	default:
+		return "unknown"
+	}
+}
+
+// reportClassSet is indexed by reportClass
+type reportClassSet [reportClassCount]bool
+
+// reportIntervals in seconds are indexed by reportClass
+type reportIntervals [reportClassCount]uint32
+
+var allReportClasses = reportClassSet{true, true, true, true}
+
+var metricIntervalKeys = [reportClassCount]types.GlobalSettingKey{
+	types.MetricDeviceInterval,
+	types.MetricAppInterval,
+	types.MetricNetworkInstanceInterval,
+	types.MetricVolumeInterval,
+}
+
+var infoIntervalKeys = [reportClassCount]types.GlobalSettingKey{
+	types.InfoDeviceInterval,
+	types.InfoAppInterval,
+	types.InfoNetworkInstanceInterval,
+	types.InfoVolumeInterval,
+}
+
+// metricIntervals returns the metrics interval of each class, which is
+// timer.metric.interval unless the class has its own
+func metricIntervals(gc *types.ConfigItemValueMap) reportIntervals {
+	var intervals reportIntervals
+	for class, key := range metricIntervalKeys {
+		intervals[class] = gc.GlobalValueInt(key)
+		if intervals[class] == 0 {
+			intervals[class] = gc.GlobalValueInt(types.MetricInterval)
+		}
+	}
+	return intervals
+}
+
+// infoIntervals returns the info interval of each class, which is 0 if the
+// info is only reported when it changes
+func infoIntervals(gc *types.ConfigItemValueMap) reportIntervals {
+	var intervals reportIntervals
+	for class, key := range infoIntervalKeys {
+		intervals[class] = gc.GlobalValueInt(key)
+	}
+	return intervals
+}
+
+// tickerInterval returns the shortest non-zero interval, or an hour if
+// there is none
+func (intervals reportIntervals) tickerInterval() uint32 {
+	var shortest uint32
+	for _, interval := range intervals {
+		if interval != 0 && (shortest == 0 || interval < shortest) {
+			shortest = interval
+		}
+	}
+	if shortest == 0 {
+		shortest = types.HourInSec
+	}
+	return shortest
+}
+
+// The ticker fires at random between this fraction of its interval and
+// its interval
+const reportTickerMinFraction = 0.3
+
+// due returns the classes to report on a tick at now. A class is due once
+// its interval has elapsed, less the jitter of the ticker, hence a class
+// is reported at most the jitter early and at most a tick late. The
+// classes with the interval of the ticker are due on every tick.
+func (intervals reportIntervals) due(lastReport [reportClassCount]time.Time,
+	now time.Time) reportClassSet {
+
+	var due reportClassSet
+	tick := time.Duration(intervals.tickerInterval()) * time.Second
+	jitter := tick - time.Duration(float64(tick)*reportTickerMinFraction)
+	for class, interval := range intervals {
+		if interval == 0 {
+			continue
+		}
+		elapsed := now.Sub(lastReport[class])
+		due[class] = elapsed >= time.Duration(interval)*time.Second-jitter
+	}
+	return due
+}
+
+// changed returns the classes whose interval differs in newIntervals
+func (intervals reportIntervals) changed(newIntervals reportIntervals) []reportClass {
+	var classes []reportClass
+	for class := range intervals {
+		if intervals[class] != newIntervals[class] {
+			classes = append(classes, reportClass(class))
+		}
+	}
+	return classes
+}
+
+// updateReportTimers updates the metrics and the info timers when the
+// interval of a class changed in the global config. Returns the classes
+// whose metrics and whose info intervals changed.
+func updateReportTimers(ctx *getconfigContext,
+	oldGlobalConfig, newGlobalConfig *types.ConfigItemValueMap) ([]reportClass, []reportClass) {
+
+	oldMetricIntervals := metricIntervals(oldGlobalConfig)
+	newMetricIntervals := metricIntervals(newGlobalConfig)
+	metricClasses := oldMetricIntervals.changed(newMetricIntervals)
+	for _, class := range metricClasses {
+		log.Functionf("updateReportTimers: %s metrics interval change from %d to %d",
+			class, oldMetricIntervals[class], newMetricIntervals[class])
+	}
+	if len(metricClasses) != 0 {
+		updateMetricsTimer(newMetricIntervals.tickerInterval(),
+			ctx.metricsTickerHandle)
+	}
+	oldInfoIntervals := infoIntervals(oldGlobalConfig)
+	newInfoIntervals := infoIntervals(newGlobalConfig)
+	infoClasses := oldInfoIntervals.changed(newInfoIntervals)
+	for _, class := range infoClasses {
+		log.Functionf("updateReportTimers: %s info interval change from %d to %d",
+			class, oldInfoIntervals[class], newInfoIntervals[class])
+	}
+	if len(infoClasses) != 0 {
+		updateInfoTimer(newInfoIntervals.tickerInterval(),
+			ctx.infoTickerHandle)
+	}
+	return metricClasses, infoClasses
+}
+
+// Run a periodic post of the info of the classes with an info interval
+func infoTimerTask(ctx *zedagentContext, handleChannel chan interface{}) {
+	log.Functionln("starting report info timer task")
+	var lastReport [reportClassCount]time.Time
+	start := time.Now()
+	for class := range lastReport {
+		lastReport[class] = start
+	}
+
+	intervals := infoIntervals(&ctx.globalConfig)
+	interval := time.Duration(intervals.tickerInterval()) * time.Second
+	max := float64(interval)
+	min := max * reportTickerMinFraction
+	ticker := flextimer.NewRangeTicker(time.Duration(min), time.Duration(max))
+	// Return handle to caller
+	handleChannel <- ticker
+
+	wdName := agentName + "info"
+
+	// Run a periodic timer so we always update StillRunning
+	stillRunning := time.NewTicker(25 * time.Second)
+	ctx.ps.StillRunning(wdName, warningTime, errorTime)
+	ctx.ps.RegisterFileWatchdog(wdName)
+
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now()
+			due := infoIntervals(&ctx.globalConfig).due(lastReport, now)
+			for class := range due {
+				if due[class] {
+					lastReport[class] = now
+				}
+			}
+			triggerPublishClassInfo(ctx, due)
+
+		case <-stillRunning.C:
+		}
+		ctx.ps.StillRunning(wdName, warningTime, errorTime)
+	}
+}
+
+// Called when globalConfig changes
+// Assumes the caller has verifier that the interval has changed
+func updateInfoTimer(infoInterval uint32, tickerHandle interface{}) {
+
+	if tickerHandle == nil {
+		log.Warnf("updateInfoTimer: no infoTickerHandle yet")
+		return
+	}
+	interval := time.Duration(infoInterval) * time.Second
+	log.Functionf("updateInfoTimer() change to %v", interval)
+	max := float64(interval)
+	min := max * reportTickerMinFraction
+	flextimer.UpdateRangeTicker(tickerHandle,
+		time.Duration(min), time.Duration(max))
+	// Force an immediate timout since timer could have decreased
+	flextimer.TickNow(tickerHandle)
+}
+
+// triggerPublishClassInfo triggers the publish of the info of all the
+// objects of the classes
+func triggerPublishClassInfo(ctxPtr *zedagentContext, classes reportClassSet) {
+
+	log.Functionf("Triggered PublishClassInfo %v", classes)
+	if classes[reportDevice] {
+		triggerPublishDevInfo(ctxPtr)
+	}
+	if !classes[reportApp] && !classes[reportNetworkInstance] &&
+		!classes[reportVolume] {
+		return
+	}
+	// we use goroutine since every publish operation can take a long time
+	// and will block sending on TriggerObjectInfo channel
+	go func() {
+		if classes[reportApp] {
+			for _, c := range ctxPtr.getconfigCtx.subAppInstanceStatus.GetAll() {
+				ctxPtr.TriggerObjectInfo <- infoForObjectKey{
+					info.ZInfoTypes_ZiApp,
+					c.(types.AppInstanceStatus).Key(),
+				}
+			}
+		}
+		if classes[reportNetworkInstance] {
+			for _, c := range ctxPtr.subNetworkInstanceStatus.GetAll() {
+				niStatus := c.(types.NetworkInstanceStatus)
+				ctxPtr.TriggerObjectInfo <- infoForObjectKey{
+					info.ZInfoTypes_ZiNetworkInstance,
+					(&niStatus).Key(),
+				}
+			}
+		}
+		if classes[reportVolume] {
+			for _, c := range ctxPtr.getconfigCtx.subVolumeStatus.GetAll() {
+				ctxPtr.TriggerObjectInfo <- infoForObjectKey{
+					info.ZInfoTypes_ZiVolume,
+					c.(types.VolumeStatus).Key(),
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package zedagent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateReportTimers(t *testing.T) {
+	testMatrix := map[string]struct {
+		oldValues       map[types.GlobalSettingKey]uint32
+		newValues       map[types.GlobalSettingKey]uint32
+		expectedMetrics []reportClass
+		expectedInfo    []reportClass
+	}{
+		"No change": {},
+		"Network instance metrics": {
+			newValues: map[types.GlobalSettingKey]uint32{
+				types.MetricNetworkInstanceInterval: 10,
+			},
+			expectedMetrics: []reportClass{reportNetworkInstance},
+		},
+		"Same as the metric interval": {
+			newValues: map[types.GlobalSettingKey]uint32{
+				types.MetricAppInterval: 60,
+			},
+		},
+		"App metrics back to the metric interval": {
+			oldValues: map[types.GlobalSettingKey]uint32{
+				types.MetricAppInterval: 600,
+			},
+			expectedMetrics: []reportClass{reportApp},
+		},
+		"Metric interval": {
+			oldValues: map[types.GlobalSettingKey]uint32{
+				types.MetricAppInterval: 600,
+			},
+			newValues: map[types.GlobalSettingKey]uint32{
+				types.MetricInterval:    120,
+				types.MetricAppInterval: 600,
+			},
+			expectedMetrics: []reportClass{reportDevice,
+				reportNetworkInstance, reportVolume},
+		},
+		"Volume info": {
+			newValues: map[types.GlobalSettingKey]uint32{
+				types.InfoVolumeInterval: 300,
+			},
+			expectedInfo: []reportClass{reportVolume},
+		},
+	}
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		getconfigCtx := initGetConfigCtx(t)
+		oldGlobalConfig := types.DefaultConfigItemValueMap()
+		for key, value := range test.oldValues {
+			oldGlobalConfig.SetGlobalValueInt(key, value)
+		}
+		newGlobalConfig := types.DefaultConfigItemValueMap()
+		for key, value := range test.newValues {
+			newGlobalConfig.SetGlobalValueInt(key, value)
+		}
+		metricClasses, infoClasses := updateReportTimers(getconfigCtx,
+			oldGlobalConfig, newGlobalConfig)
+		assert.Equal(t, test.expectedMetrics, metricClasses, testname)
+		assert.Equal(t, test.expectedInfo, infoClasses, testname)
+	}
+}
+
+func TestReportIntervalsDue(t *testing.T) {
+	start := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	lastReport := [reportClassCount]time.Time{start, start, start, start}
+	intervals := reportIntervals{60, 600, 10, 0}
+	assert.Equal(t, uint32(10), intervals.tickerInterval())
+
+	testMatrix := map[string]struct {
+		elapsed     time.Duration
+		expectedDue reportClassSet
+	}{
+		"Earliest tick": {
+			elapsed:     3 * time.Second,
+			expectedDue: reportClassSet{false, false, true, false},
+		},
+		"Less than a tick but more than the jitter left of the device": {
+			elapsed:     51 * time.Second,
+			expectedDue: reportClassSet{false, false, true, false},
+		},
+		"Within the jitter of the device": {
+			elapsed:     53 * time.Second,
+			expectedDue: reportClassSet{true, false, true, false},
+		},
+		"Device interval": {
+			elapsed:     60 * time.Second,
+			expectedDue: reportClassSet{true, false, true, false},
+		},
+		"Within the jitter of the app": {
+			elapsed:     592 * time.Second,
+			expectedDue: reportClassSet{true, false, true, false},
+		},
+		"Device and app": {
+			elapsed:     600 * time.Second,
+			expectedDue: reportClassSet{true, true, true, false},
+		},
+	}
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		due := intervals.due(lastReport, start.Add(test.elapsed))
+		assert.Equal(t, test.expectedDue, due, testname)
+	}
+
+	assert.Equal(t, uint32(types.HourInSec), reportIntervals{}.tickerInterval())
+	assert.Equal(t, reportClassSet{},
+		reportIntervals{}.due(lastReport, start.Add(time.Hour)))
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Retain the volumes of removed app instances which set VolumeRetentionDays.
+// An AppVolumeRetention is published for such an app instance while it is
+// in the config, so that volumemgr already has it when the volumes become
+// unused, independent of the order in which it sees the changes from
+// zedagent and zedmanager. When the app instance is removed from the config
+// the expiry is set, after which the AppVolumeRetention is unpublished.
+// Adding the app instance back before the expiry reattaches the volumes.
+
+package zedagent
+
+import (
+	"time"
+
+	"github.com/lf-edge/eve/pkg/pillar/types"
+)
+
+// publishAppVolumeRetention publishes the AppVolumeRetention for the app
+// instance, or unpublishes it if the app instance does not retain its volumes
+func publishAppVolumeRetention(getconfigCtx *getconfigContext,
+	config types.AppInstanceConfig) {
+
+	pub := getconfigCtx.pubAppVolumeRetention
+	key := config.UUIDandVersion.UUID.String()
+	if config.VolumeRetentionDays == 0 || len(config.VolumeRefConfigList) == 0 {
+		if c, _ := pub.Get(key); c != nil {
+			log.Functionf("publishAppVolumeRetention: not retaining volumes of app %s",
+				config.DisplayName)
+			pub.Unpublish(key)
+		}
+		return
+	}
+	retention := types.AppVolumeRetention{
+		AppUUID:       config.UUIDandVersion.UUID,
+		DisplayName:   config.DisplayName,
+		RetentionDays: config.VolumeRetentionDays,
+	}
+	for _, vrc := range config.VolumeRefConfigList {
+		retention.VolumeKeys = append(retention.VolumeKeys, vrc.VolumeKey())
+	}
+	pub.Publish(key, retention)
+}
+
+// expireAppVolumeRetention sets the expiry of the AppVolumeRetention for
+// the app instances which are no longer in the config, and unpublishes the
+// expired ones
+func expireAppVolumeRetention(getconfigCtx *getconfigContext,
+	appUUIDs map[string]bool, now time.Time) {
+
+	pub := getconfigCtx.pubAppVolumeRetention
+	for key, c := range pub.GetAll() {
+		retention := c.(types.AppVolumeRetention)
+		if appUUIDs[key] {
+			continue
+		}
+		if retention.Expiry.IsZero() {
+			retention.Expiry = now.Add(time.Duration(retention.RetentionDays) *
+				24 * time.Hour)
+			log.Noticef("expireAppVolumeRetention: retaining volumes %v of removed app %s until %v",
+				retention.VolumeKeys, retention.DisplayName, retention.Expiry)
+			pub.Publish(key, retention)
+		} else if retention.Expired(now) {
+			log.Noticef("expireAppVolumeRetention: volumes %v of app %s expired",
+				retention.VolumeKeys, retention.DisplayName)
+			pub.Unpublish(key)
+		}
+	}
+}
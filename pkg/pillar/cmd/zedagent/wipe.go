@@ -0,0 +1,195 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Handling of the WipeCmd which wipes the local state of the device while
+// keeping its identity and network config
+
+package zedagent
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	zconfig "github.com/lf-edge/eve/api/go/config"
+	zcommon "github.com/lf-edge/eve/api/go/evecommon"
+	"github.com/lf-edge/eve/api/go/info"
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	fileutils "github.com/lf-edge/eve/pkg/pillar/utils/file"
+)
+
+// The last WipeCmd counter seen, and the scope when it was accepted.
+// A variable for the tests.
+var wipeConfigFilename = types.PersistStatusDir + "/wipeConfig"
+
+// newWipeConfirmationToken returns a new random token which the controller
+// has to echo in a WipeCmd
+func newWipeConfirmationToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatal(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// parseWipeScope maps the scope of a WipeCmd
+func parseWipeScope(scope zcommon.WipeScope) (types.WipeScope, error) {
+	switch scope {
+	case zcommon.WipeScope_WIPE_SCOPE_APPS:
+		return types.WipeScopeApps, nil
+	case zcommon.WipeScope_WIPE_SCOPE_APPS_AND_LOGS:
+		return types.WipeScopeAppsAndLogs, nil
+	case zcommon.WipeScope_WIPE_SCOPE_ALL_BUT_IDENTITY:
+		return types.WipeScopeAllButIdentity, nil
+	default:
+		return types.WipeScopeUnspecified,
+			fmt.Errorf("unsupported wipe scope %s", scope)
+	}
+}
+
+// Returns the last WipeCmd if the file exists
+func readWipeConfig() *types.WipeRequest {
+	bytes, err := ioutil.ReadFile(wipeConfigFilename)
+	if err != nil {
+		log.Functionf("readWipeConfig - %s doesn't exist",
+			wipeConfigFilename)
+		return nil
+	}
+	wipeConfig := types.WipeRequest{}
+	if err := json.Unmarshal(bytes, &wipeConfig); err != nil {
+		// Treat the same way as a missing file
+		log.Error(err)
+		return nil
+	}
+	return &wipeConfig
+}
+
+func saveWipeConfig(wipe types.WipeRequest) {
+	log.Functionf("saveWipeConfig - wipe.Counter: %d", wipe.Counter)
+	bytes, err := json.Marshal(wipe)
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = fileutils.WriteRename(wipeConfigFilename, bytes)
+	if err != nil {
+		// Can fail if low on disk space
+		log.Error(err)
+	}
+}
+
+var wipePrevConfigHash []byte
+
+// scheduleWipe publishes a WipeRequest when the counter of the WipeCmd
+// changes. As for the reboot command the first counter seen is only
+// recorded, hence a newly onboarded device is not wiped. The WipeCmd is
+// rejected unless it echoes the current confirmation token, which
+// prevents a WipeCmd sent to a fleet of devices from taking effect.
+func scheduleWipe(wipe *zconfig.WipeCmd, getconfigCtx *getconfigContext) {
+	if wipe == nil {
+		return
+	}
+	configHash := computeConfigSha(wipe)
+	same := bytes.Equal(configHash, wipePrevConfigHash)
+	wipePrevConfigHash = configHash
+	if same {
+		return
+	}
+	log.Functionf("scheduleWipe: Applying updated config counter %d scope %s",
+		wipe.GetCounter(), wipe.GetScope())
+	wipeConfig := readWipeConfig()
+	if wipeConfig != nil && wipeConfig.Counter == wipe.GetCounter() {
+		return
+	}
+	if wipeConfig == nil {
+		log.Noticef("scheduleWipe: recording counter %d without a wipe",
+			wipe.GetCounter())
+		saveWipeConfig(types.WipeRequest{Counter: wipe.GetCounter()})
+		return
+	}
+
+	ctx := getconfigCtx.zedagentCtx
+	status := types.WipeStatus{Counter: wipe.GetCounter()}
+	scope, err := parseWipeScope(wipe.GetScope())
+	if err == nil && wipe.GetConfirmationToken() != ctx.wipeConfirmationToken {
+		err = fmt.Errorf("confirmation token mismatch")
+	}
+	status.Scope = scope
+	if err != nil {
+		// The counter is not recorded, so that the command can be
+		// resent with the same counter
+		errStr := fmt.Sprintf("wipe %d rejected: %s", wipe.GetCounter(), err)
+		log.Error(errStr)
+		status.State = types.WipeStateRejected
+		status.Error = errStr
+		ctx.wipeStatus = status
+		triggerPublishDevInfo(ctx)
+		return
+	}
+	wipeRequest := types.WipeRequest{Counter: wipe.GetCounter(), Scope: scope}
+	saveWipeConfig(wipeRequest)
+	getconfigCtx.pubWipeRequest.Publish(wipeRequest.Key(), wipeRequest)
+	// A token is only good for a single wipe
+	ctx.wipeConfirmationToken = newWipeConfirmationToken()
+	status.State = types.WipeStateRequested
+	ctx.wipeStatus = status
+	triggerPublishDevInfo(ctx)
+}
+
+func handleWipeStatusCreate(ctxArg interface{}, key string,
+	statusArg interface{}) {
+	handleWipeStatusImpl(ctxArg, key, statusArg)
+}
+
+func handleWipeStatusModify(ctxArg interface{}, key string,
+	statusArg interface{}, oldStatusArg interface{}) {
+	handleWipeStatusImpl(ctxArg, key, statusArg)
+}
+
+func handleWipeStatusImpl(ctxArg interface{}, key string,
+	statusArg interface{}) {
+
+	ctx := ctxArg.(*zedagentContext)
+	status := statusArg.(types.WipeStatus)
+	log.Functionf("handleWipeStatusImpl: counter %d state %s",
+		status.Counter, status.State)
+	triggerPublishDevInfo(ctx)
+}
+
+// currentWipeStatus returns the progress reported by nodeagent unless
+// zedagent has a more recent WipeCmd
+func currentWipeStatus(own types.WipeStatus,
+	reported *types.WipeStatus) types.WipeStatus {
+
+	if reported == nil {
+		return own
+	}
+	if own.State == types.WipeStateUnspecified ||
+		(own.State == types.WipeStateRequested &&
+			own.Counter == reported.Counter) {
+		return *reported
+	}
+	return own
+}
+
+func encodeWipeInfo(ctx *zedagentContext) *info.ZInfoWipe {
+	var reported *types.WipeStatus
+	if ctx.subWipeStatus != nil {
+		if item, err := ctx.subWipeStatus.Get("global"); err == nil {
+			status := item.(types.WipeStatus)
+			reported = &status
+		}
+	}
+	status := currentWipeStatus(ctx.wipeStatus, reported)
+	if status.State == types.WipeStateUnspecified {
+		return nil
+	}
+	return &info.ZInfoWipe{
+		Counter: status.Counter,
+		Scope:   zcommon.WipeScope(status.Scope),
+		State:   info.ZWipeState(status.State),
+		Error:   status.Error,
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package zedagent
+
+import (
+	"path/filepath"
+	"testing"
+
+	zconfig "github.com/lf-edge/eve/api/go/config"
+	zcommon "github.com/lf-edge/eve/api/go/evecommon"
+	"github.com/lf-edge/eve/pkg/pillar/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWipeScope(t *testing.T) {
+	testMatrix := map[string]struct {
+		scope         zcommon.WipeScope
+		expectedScope types.WipeScope
+		expectedError bool
+	}{
+		"Unspecified": {
+			scope:         zcommon.WipeScope_WIPE_SCOPE_UNSPECIFIED,
+			expectedScope: types.WipeScopeUnspecified,
+			expectedError: true,
+		},
+		"Apps": {
+			scope:         zcommon.WipeScope_WIPE_SCOPE_APPS,
+			expectedScope: types.WipeScopeApps,
+		},
+		"Apps and logs": {
+			scope:         zcommon.WipeScope_WIPE_SCOPE_APPS_AND_LOGS,
+			expectedScope: types.WipeScopeAppsAndLogs,
+		},
+		"All but identity": {
+			scope:         zcommon.WipeScope_WIPE_SCOPE_ALL_BUT_IDENTITY,
+			expectedScope: types.WipeScopeAllButIdentity,
+		},
+		"Unknown": {
+			scope:         zcommon.WipeScope(17),
+			expectedScope: types.WipeScopeUnspecified,
+			expectedError: true,
+		},
+	}
+	for testname, test := range testMatrix {
+		scope, err := parseWipeScope(test.scope)
+		assert.Equal(t, test.expectedScope, scope, testname)
+		assert.Equal(t, test.expectedError, err != nil, testname)
+	}
+}
+
+func TestScheduleWipe(t *testing.T) {
+	getconfigCtx := initGetConfigCtx(t)
+	ctx := getconfigCtx.zedagentCtx
+	ctx.wipeConfirmationToken = "token1"
+	wipeConfigFilename = filepath.Join(t.TempDir(), "wipeConfig")
+	wipePrevConfigHash = nil
+	getRequest := func() *types.WipeRequest {
+		item, err := getconfigCtx.pubWipeRequest.Get("global")
+		if err != nil {
+			return nil
+		}
+		req := item.(types.WipeRequest)
+		return &req
+	}
+
+	// The first counter seen is only recorded
+	scheduleWipe(&zconfig.WipeCmd{
+		Counter:           1,
+		Scope:             zcommon.WipeScope_WIPE_SCOPE_APPS,
+		ConfirmationToken: "token1",
+	}, getconfigCtx)
+	assert.Nil(t, getRequest())
+	assert.Equal(t, uint32(1), readWipeConfig().Counter)
+	assert.Equal(t, types.WipeStateUnspecified, ctx.wipeStatus.State)
+
+	// A new counter with a stale token is rejected and not recorded
+	scheduleWipe(&zconfig.WipeCmd{
+		Counter:           2,
+		Scope:             zcommon.WipeScope_WIPE_SCOPE_APPS,
+		ConfirmationToken: "token0",
+	}, getconfigCtx)
+	assert.Nil(t, getRequest())
+	assert.Equal(t, uint32(1), readWipeConfig().Counter)
+	assert.Equal(t, types.WipeStateRejected, ctx.wipeStatus.State)
+	assert.Contains(t, ctx.wipeStatus.Error, "confirmation token mismatch")
+	assert.Equal(t, "token1", ctx.wipeConfirmationToken)
+
+	// As is an unspecified scope
+	scheduleWipe(&zconfig.WipeCmd{
+		Counter:           2,
+		ConfirmationToken: "token1",
+	}, getconfigCtx)
+	assert.Nil(t, getRequest())
+	assert.Equal(t, types.WipeStateRejected, ctx.wipeStatus.State)
+	assert.Contains(t, ctx.wipeStatus.Error, "unsupported wipe scope")
+
+	// Resent with the same counter and the right token
+	scheduleWipe(&zconfig.WipeCmd{
+		Counter:           2,
+		Scope:             zcommon.WipeScope_WIPE_SCOPE_APPS_AND_LOGS,
+		ConfirmationToken: "token1",
+	}, getconfigCtx)
+	expected := types.WipeRequest{Counter: 2, Scope: types.WipeScopeAppsAndLogs}
+	assert.Equal(t, &expected, getRequest())
+	assert.Equal(t, &expected, readWipeConfig())
+	assert.Equal(t, types.WipeStateRequested, ctx.wipeStatus.State)
+	assert.Empty(t, ctx.wipeStatus.Error)
+	// The token is rotated
+	assert.NotEqual(t, "token1", ctx.wipeConfirmationToken)
+	assert.Len(t, ctx.wipeConfirmationToken, 32)
+
+	// The same counter is not acted on again, even after a restart
+	wipePrevConfigHash = nil
+	getconfigCtx.pubWipeRequest.Unpublish("global")
+	scheduleWipe(&zconfig.WipeCmd{
+		Counter:           2,
+		Scope:             zcommon.WipeScope_WIPE_SCOPE_APPS_AND_LOGS,
+		ConfirmationToken: ctx.wipeConfirmationToken,
+	}, getconfigCtx)
+	assert.Nil(t, getRequest())
+
+	// No WipeCmd leaves the recorded counter
+	scheduleWipe(nil, getconfigCtx)
+	assert.Equal(t, uint32(2), readWipeConfig().Counter)
+}
+
+func TestCurrentWipeStatus(t *testing.T) {
+	requested := types.WipeStatus{Counter: 2, Scope: types.WipeScopeApps,
+		State: types.WipeStateRequested}
+	rejected := types.WipeStatus{Counter: 3, State: types.WipeStateRejected,
+		Error: "wipe 3 rejected: confirmation token mismatch"}
+	inProgress := types.WipeStatus{Counter: 2, Scope: types.WipeScopeApps,
+		State: types.WipeStateInProgress}
+	testMatrix := map[string]struct {
+		own      types.WipeStatus
+		reported *types.WipeStatus
+		expected types.WipeStatus
+	}{
+		"None": {},
+		"Requested": {
+			own:      requested,
+			expected: requested,
+		},
+		"Reported after a restart": {
+			reported: &inProgress,
+			expected: inProgress,
+		},
+		"Reported progress": {
+			own:      requested,
+			reported: &inProgress,
+			expected: inProgress,
+		},
+		"Rejected after progress": {
+			own:      rejected,
+			reported: &inProgress,
+			expected: rejected,
+		},
+	}
+	for testname, test := range testMatrix {
+		assert.Equal(t, test.expected,
+			currentWipeStatus(test.own, test.reported), testname)
+	}
+}
@@ -34,8 +34,10 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/lf-edge/eve/api/go/info"
+	"github.com/lf-edge/eve/api/go/profile"
 	"github.com/lf-edge/eve/pkg/pillar/agentlog"
 	"github.com/lf-edge/eve/pkg/pillar/base"
+	"github.com/lf-edge/eve/pkg/pillar/cipher"
 	"github.com/lf-edge/eve/pkg/pillar/pidfile"
 	"github.com/lf-edge/eve/pkg/pillar/pubsub"
 	"github.com/lf-edge/eve/pkg/pillar/types"
@@ -105,6 +107,7 @@ type zedagentContext struct {
 	subVaultStatus            pubsub.Subscription
 	subAttestQuote            pubsub.Subscription
 	subEncryptedKeyFromDevice pubsub.Subscription
+	decryptCipherContext      cipher.DecryptCipherContext // For the proxy passwords
 	subNewlogMetrics          pubsub.Subscription
 	subBlobStatus             pubsub.Subscription
 	GCInitialized             bool // Received initial GlobalConfig
@@ -112,6 +115,7 @@ type zedagentContext struct {
 	subAppContainerMetrics    pubsub.Subscription
 	subDiskMetric             pubsub.Subscription
 	subAppDiskMetric          pubsub.Subscription
+	subContentTreeMetrics     pubsub.Subscription
 	subCapabilities           pubsub.Subscription
 	rebootCmd                 bool
 	rebootCmdDeferred         bool
@@ -149,6 +153,13 @@ type zedagentContext struct {
 
 	// Track the counter from force.fallback.counter to detect changes
 	forceFallbackCounter int
+
+	// wipeConfirmationToken - the token the controller needs to echo in
+	// a WipeCmd; reported in device info
+	wipeConfirmationToken string
+	// wipeStatus - the outcome of the last WipeCmd seen by zedagent
+	wipeStatus    types.WipeStatus
+	subWipeStatus pubsub.Subscription
 }
 
 var debug = false
@@ -233,6 +244,8 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 	zedagentCtx.globalStatus.UnknownConfigItems = make(
 		map[string]types.ConfigItemStatus)
 
+	zedagentCtx.wipeConfirmationToken = newWipeConfirmationToken()
+
 	rebootConfig := readRebootConfig()
 	if rebootConfig != nil {
 		zedagentCtx.rebootConfigCounter = rebootConfig.Counter
@@ -296,6 +309,10 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 	attestCtx.zedagentCtx = &zedagentCtx
 	zedagentCtx.attestCtx = &attestCtx
 
+	// Avoid re-parsing an unchanged config after a restart
+	getconfigCtx.bootID = readBootID()
+	loadPrevConfigHashes(prevConfigHashFilename, getconfigCtx.bootID)
+
 	// Wait until we have been onboarded aka know our own UUID
 	onboard, err := utils.WaitForOnboarded(ps, log, agentName, warningTime, errorTime)
 	if err != nil {
@@ -347,6 +364,11 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 	}
 	getconfigCtx.pubDevicePortConfig = pubDevicePortConfig
 
+	getconfigCtx.pubSystemAdapterReport = newZedagentPublication(ps,
+		types.SystemAdapterReport{}, zedagentPublicationOptions{})
+	getconfigCtx.pubDevicePortConfigParseStatus = newZedagentPublication(ps,
+		types.DevicePortConfigParseStatus{}, zedagentPublicationOptions{})
+
 	// Publish NetworkXObjectConfig and for outselves. XXX remove
 	pubNetworkXObjectConfig, err := ps.NewPublication(pubsub.PublicationOptions{
 		AgentName: agentName,
@@ -389,15 +411,21 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 	// XXX defer this until we have some config from cloud or saved copy
 	pubAppInstanceConfig.SignalRestarted()
 
-	pubBaseOsConfig, err := ps.NewPublication(pubsub.PublicationOptions{
-		AgentName: agentName,
-		TopicType: types.BaseOsConfig{},
-	})
-	if err != nil {
-		log.Fatal(err)
-	}
-	pubBaseOsConfig.ClearRestarted()
-	getconfigCtx.pubBaseOsConfig = pubBaseOsConfig
+	// Persistent since the volumes are retained across reboots
+	getconfigCtx.pubAppVolumeRetention = newZedagentPublication(ps,
+		types.AppVolumeRetention{}, zedagentPublicationOptions{Persistent: true})
+
+	// For the log shipper
+	getconfigCtx.pubAppLogPolicyConfig = newZedagentPublication(ps,
+		types.AppLogPolicyConfig{}, zedagentPublicationOptions{})
+
+	// Persistent so that nodeagent can complete a wipe across reboots
+	getconfigCtx.pubWipeRequest = newZedagentPublication(ps,
+		types.WipeRequest{}, zedagentPublicationOptions{Persistent: true})
+
+	getconfigCtx.pubBaseOsConfig = newZedagentPublication(ps,
+		types.BaseOsConfig{},
+		zedagentPublicationOptions{ClearRestarted: true})
 
 	pubBaseOs, err := ps.NewPublication(pubsub.PublicationOptions{
 		AgentName: agentName,
@@ -409,24 +437,13 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 	pubBaseOs.ClearRestarted()
 	getconfigCtx.pubBaseOs = pubBaseOs
 
-	pubZedAgentStatus, err := ps.NewPublication(pubsub.PublicationOptions{
-		AgentName: agentName,
-		TopicType: types.ZedAgentStatus{},
-	})
-	if err != nil {
-		log.Fatal(err)
-	}
-	pubZedAgentStatus.ClearRestarted()
-	getconfigCtx.pubZedAgentStatus = pubZedAgentStatus
-	pubDatastoreConfig, err := ps.NewPublication(pubsub.PublicationOptions{
-		AgentName: agentName,
-		TopicType: types.DatastoreConfig{},
-	})
-	if err != nil {
-		log.Fatal(err)
-	}
-	getconfigCtx.pubDatastoreConfig = pubDatastoreConfig
-	pubDatastoreConfig.ClearRestarted()
+	getconfigCtx.pubZedAgentStatus = newZedagentPublication(ps,
+		types.ZedAgentStatus{},
+		zedagentPublicationOptions{ClearRestarted: true})
+
+	getconfigCtx.pubDatastoreConfig = newZedagentPublication(ps,
+		types.DatastoreConfig{},
+		zedagentPublicationOptions{ClearRestarted: true})
 
 	pubControllerCert, err := ps.NewPublication(
 		pubsub.PublicationOptions{
@@ -741,6 +758,44 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 	zedagentCtx.subEdgeNodeCert = subEdgeNodeCert
 	subEdgeNodeCert.Activate()
 
+	// Look for our controller certs and cipher contexts which will be used
+	// for decrypting the proxy passwords
+	subControllerCert, err := ps.NewSubscription(pubsub.SubscriptionOptions{
+		AgentName:   agentName,
+		MyAgentName: agentName,
+		TopicImpl:   types.ControllerCert{},
+		Activate:    false,
+		Ctx:         &zedagentCtx,
+		WarningTime: warningTime,
+		ErrorTime:   errorTime,
+		Persistent:  true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	subControllerCert.Activate()
+
+	subCipherContext, err := ps.NewSubscription(pubsub.SubscriptionOptions{
+		AgentName:   agentName,
+		MyAgentName: agentName,
+		TopicImpl:   types.CipherContext{},
+		Activate:    false,
+		Ctx:         &zedagentCtx,
+		WarningTime: warningTime,
+		ErrorTime:   errorTime,
+		Persistent:  true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	subCipherContext.Activate()
+
+	zedagentCtx.decryptCipherContext.Log = log
+	zedagentCtx.decryptCipherContext.SubControllerCert = subControllerCert
+	zedagentCtx.decryptCipherContext.SubCipherContext = subCipherContext
+	zedagentCtx.decryptCipherContext.SubEdgeNodeCert = subEdgeNodeCert
+	zedcloudCtx.DecryptCipherContext = &zedagentCtx.decryptCipherContext
+
 	subVaultStatus, err := ps.NewSubscription(pubsub.SubscriptionOptions{
 		AgentName:     "vaultmgr",
 		MyAgentName:   agentName,
@@ -899,6 +954,20 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 	zedagentCtx.subDiskMetric = subDiskMetric
 	subDiskMetric.Activate()
 
+	subContentTreeMetrics, err := ps.NewSubscription(pubsub.SubscriptionOptions{
+		AgentName:   "volumemgr",
+		MyAgentName: agentName,
+		TopicImpl:   types.ContentTreeMetrics{},
+		Activate:    true,
+		Ctx:         &zedagentCtx,
+		WarningTime: warningTime,
+		ErrorTime:   errorTime,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	zedagentCtx.subContentTreeMetrics = subContentTreeMetrics
+
 	subAppDiskMetric, err := ps.NewSubscription(pubsub.SubscriptionOptions{
 		AgentName:   "volumemgr",
 		MyAgentName: agentName,
@@ -929,6 +998,24 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 	zedagentCtx.subCapabilities = subCapabilities
 	subCapabilities.Activate()
 
+	subWipeStatus, err := ps.NewSubscription(pubsub.SubscriptionOptions{
+		AgentName:     "nodeagent",
+		MyAgentName:   agentName,
+		TopicImpl:     types.WipeStatus{},
+		Persistent:    true,
+		Activate:      false,
+		Ctx:           &zedagentCtx,
+		CreateHandler: handleWipeStatusCreate,
+		ModifyHandler: handleWipeStatusModify,
+		WarningTime:   warningTime,
+		ErrorTime:     errorTime,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	zedagentCtx.subWipeStatus = subWipeStatus
+	subWipeStatus.Activate()
+
 	subBaseOsMgrStatus, err := ps.NewSubscription(pubsub.SubscriptionOptions{
 		AgentName:   "baseosmgr",
 		MyAgentName: agentName,
@@ -1144,8 +1231,15 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 	metricsTickerHandle := <-handleChannel
 	getconfigCtx.metricsTickerHandle = metricsTickerHandle
 
+	// start the periodic info reporting task
+	log.Functionf("Creating %s at %s", "infoTimerTask", agentlog.GetMyStack())
+	go infoTimerTask(&zedagentCtx, handleChannel)
+	infoTickerHandle := <-handleChannel
+	getconfigCtx.infoTickerHandle = infoTickerHandle
+
 	//trigger channel for localProfile state machine
 	getconfigCtx.localProfileTrigger = make(chan Notify, 1)
+	getconfigCtx.localAppRestarts = make(chan []*profile.LocalAppRestart, 1)
 	//process saved local profile
 	processSavedProfile(&getconfigCtx)
 
@@ -1334,6 +1428,12 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 		case change := <-subEdgeNodeCert.MsgChan():
 			subEdgeNodeCert.ProcessChange(change)
 
+		case change := <-subControllerCert.MsgChan():
+			subControllerCert.ProcessChange(change)
+
+		case change := <-subCipherContext.MsgChan():
+			subCipherContext.ProcessChange(change)
+
 		case change := <-subVaultStatus.MsgChan():
 			subVaultStatus.ProcessChange(change)
 
@@ -1349,12 +1449,18 @@ func Run(ps *pubsub.PubSub, loggerArg *logrus.Logger, logArg *base.LogObject) in
 		case change := <-subDiskMetric.MsgChan():
 			subDiskMetric.ProcessChange(change)
 
+		case change := <-subContentTreeMetrics.MsgChan():
+			subContentTreeMetrics.ProcessChange(change)
+
 		case change := <-subAppDiskMetric.MsgChan():
 			subAppDiskMetric.ProcessChange(change)
 
 		case change := <-subCapabilities.MsgChan():
 			subCapabilities.ProcessChange(change)
 
+		case change := <-subWipeStatus.MsgChan():
+			subWipeStatus.ProcessChange(change)
+
 		case change := <-subBaseOsMgrStatus.MsgChan():
 			subBaseOsMgrStatus.ProcessChange(change)
 
@@ -1475,6 +1581,7 @@ func handleAppInstanceStatusCreate(ctxArg interface{}, key string,
 		ctx.iteration)
 	triggerPublishDevInfo(ctx)
 	ctx.iteration++
+	updateCanaryVerdict(ctx.getconfigCtx)
 	log.Functionf("handleAppInstanceStatusCreate(%s) DONE", key)
 }
 
@@ -1491,6 +1598,7 @@ func handleAppInstanceStatusModify(ctxArg interface{}, key string,
 	PublishAppInfoToZedCloud(ctx, uuidStr, &status, ctx.assignableAdapters,
 		ctx.iteration)
 	ctx.iteration++
+	updateCanaryVerdict(ctx.getconfigCtx)
 	log.Functionf("handleAppInstanceStatusModify(%s) DONE", key)
 }
 
@@ -1504,6 +1612,7 @@ func handleAppInstanceStatusDelete(ctxArg interface{}, key string,
 		ctx.iteration)
 	triggerPublishDevInfo(ctx)
 	ctx.iteration++
+	updateCanaryVerdict(ctx.getconfigCtx)
 	log.Functionf("handleAppInstanceStatusDelete(%s) DONE", key)
 }
 
@@ -1820,6 +1929,12 @@ func handleNodeAgentStatusImpl(ctxArg interface{}, key string,
 		infoStr := fmt.Sprintf("TestComplete and deferred Reboot Cmd")
 		handleRebootCmd(ctx, infoStr)
 	}
+	// Base OS testing completed successfully. On a rollback we reboot
+	// with updateInprogress still set, hence apps stay held.
+	if updateInprogress && !status.UpdateInprogress {
+		releaseHeldAppInstances(getconfigCtx)
+	}
+	updateCanaryVerdict(getconfigCtx)
 	if status.DeviceReboot {
 		handleDeviceReboot(ctx)
 	}
@@ -456,6 +456,8 @@ func handleCreate(ctxArg interface{}, key string,
 		UUIDandVersion:    config.UUIDandVersion,
 		DisplayName:       config.DisplayName,
 		FixedResources:    config.FixedResources,
+		NoNetworking:      config.NoNetworking,
+		OpsCmdWarnings:    config.OpsCmdWarnings(),
 		State:             types.INITIAL,
 	}
 
@@ -500,7 +502,7 @@ func handleCreate(ctxArg interface{}, key string,
 	allErrors := ""
 	if len(config.Errors) > 0 {
 		// Combine all errors from Config parsing state and send them in Status
-		for i, errStr := range config.Errors {
+		for i, errStr := range config.ErrorStrings() {
 			allErrors += errStr
 			log.Errorf("App Instance %s-%s: Error(%d): %s",
 				config.DisplayName, config.UUIDandVersion.UUID, i, errStr)
@@ -552,6 +554,8 @@ func handleModify(ctxArg interface{}, key string,
 
 	effectiveActivate := effectiveActivateCurrentProfile(config, ctx.currentProfile)
 	status.EffectiveActivate = effectiveActivate
+	status.NoNetworking = config.NoNetworking
+	status.OpsCmdWarnings = config.OpsCmdWarnings()
 	publishAppInstanceStatus(ctx, status)
 
 	// We handle at least ACL and activate changes. XXX What else?
@@ -567,18 +571,24 @@ func handleModify(ctxArg interface{}, key string,
 		needRestart = false
 	}
 
-	if config.RestartCmd.Counter != oldConfig.RestartCmd.Counter {
+	// A restart requested by the local profile server is handled
+	// like a restartCmd from the controller
+	localRestart := config.LocalRestartCmd.Counter != oldConfig.LocalRestartCmd.Counter
+	if config.RestartCmd.Counter != oldConfig.RestartCmd.Counter || localRestart {
 
 		log.Functionf("handleModify(%v) for %s restartcmd from %d to %d "+
-			"needRestart: %v",
+			"localrestartcmd from %d to %d needRestart: %v",
 			config.UUIDandVersion, config.DisplayName,
 			oldConfig.RestartCmd.Counter, config.RestartCmd.Counter,
+			oldConfig.LocalRestartCmd.Counter, config.LocalRestartCmd.Counter,
 			needRestart)
 		if effectiveActivate {
 			// Will restart even if we crash/power cycle since that
 			// would also restart the app. Hence we can update
 			// the status counter here.
 			status.RestartInprogress = types.BringDown
+			status.RestartedLocally = localRestart &&
+				config.RestartCmd.Counter == oldConfig.RestartCmd.Counter
 			status.State = types.RESTARTING
 		} else {
 			log.Functionf("handleModify(%v) for %s restartcmd ignored config !Activate",
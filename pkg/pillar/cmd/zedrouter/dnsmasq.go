@@ -149,10 +149,20 @@ func createDnsmasqConfiglet(
 		dnsmasqLeasePath(bridgeName)))
 
 	// Pick file where dnsmasq should send DNS read upstream
+	// If the network instance has upstream DNS servers we use those.
 	// If we have no uplink for this network instance that is nowhere
 	// If we have an uplink but no dnsServers for it, then we let
 	// dnsmasq use the host's /etc/resolv.conf
-	if uplink == "" {
+	if len(netconf.UpstreamDnsServers) != 0 {
+		for _, s := range netconf.UpstreamDnsServers {
+			if uplink == "" {
+				file.WriteString(fmt.Sprintf("server=%s\n", s))
+			} else {
+				file.WriteString(fmt.Sprintf("server=%s@%s\n", s, uplink))
+			}
+		}
+		file.WriteString("no-resolv\n")
+	} else if uplink == "" {
 		file.WriteString("no-resolv\n")
 	} else if len(dnsServers) != 0 {
 		for _, s := range dnsServers {
@@ -212,12 +222,20 @@ func createDnsmasqConfiglet(
 		advertizeRouter = false
 	}
 	if netconf.DomainName != "" {
+		domainNames := netconf.DomainNames
+		if len(domainNames) == 0 {
+			domainNames = []string{netconf.DomainName}
+		}
 		if isIPv6 {
 			file.WriteString(fmt.Sprintf("dhcp-option=option:domain-search,%s\n",
-				netconf.DomainName))
+				strings.Join(domainNames, ",")))
 		} else {
 			file.WriteString(fmt.Sprintf("dhcp-option=option:domain-name,%s\n",
 				netconf.DomainName))
+			if len(domainNames) > 1 {
+				file.WriteString(fmt.Sprintf("dhcp-option=option:domain-search,%s\n",
+					strings.Join(domainNames, ",")))
+			}
 		}
 	}
 	advertizeDns := false
@@ -226,17 +244,12 @@ func createDnsmasqConfiglet(
 		file.WriteString(fmt.Sprintf("dhcp-option=option:dns-server,%s\n",
 			ns.String()))
 	}
-	if netconf.NtpServer != nil {
-		file.WriteString(fmt.Sprintf("dhcp-option=option:ntp-server,%s\n",
-			netconf.NtpServer.String()))
-	} else {
-		ntpStr := ""
-		for _, s := range ntpServers {
-			ntpStr += "," + s.String()
-		}
-		if len(ntpStr) != 0 {
-			file.WriteString("dhcp-option=option:ntp-server" + ntpStr + "\n")
-		}
+	ntpStr := ""
+	for _, s := range netconf.DhcpNtpServers(ntpServers) {
+		ntpStr += "," + s.String()
+	}
+	if len(ntpStr) != 0 {
+		file.WriteString("dhcp-option=option:ntp-server" + ntpStr + "\n")
 	}
 	if netconf.Subnet.IP != nil {
 		ipv4Netmask = net.IP(netconf.Subnet.Mask).String()
@@ -90,6 +90,33 @@ func removeFromHostsConfiglet(cfgDirname string, hostname string) {
 	}
 }
 
+// updateAppHostnames replaces the hosts files for the old hostnames of an
+// app interface with ones for the new hostnames
+func updateAppHostnames(cfgDirname string, oldHostnames []string,
+	newHostnames []string, appIPAddr string) {
+
+	for _, hostname := range oldHostnames {
+		if !containsString(newHostnames, hostname) {
+			removeFromHostsConfiglet(cfgDirname, hostname)
+		}
+	}
+	for _, hostname := range newHostnames {
+		if !containsString(oldHostnames, hostname) {
+			addToHostsConfiglet(cfgDirname, hostname,
+				[]string{appIPAddr})
+		}
+	}
+}
+
+func containsString(list []string, str string) bool {
+	for _, s := range list {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
 func containsHostName(nameToIPList []types.DnsNameToIP, hostname string) bool {
 	for _, ne := range nameToIPList {
 		if hostname == ne.HostName {
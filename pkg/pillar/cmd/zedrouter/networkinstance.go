@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"reflect"
 	"strings"
 
 	"github.com/lf-edge/eve/pkg/pillar/base"
@@ -109,6 +110,9 @@ func doCreateBridge(bridgeName string, bridgeNum int,
 		log.Fatal("ParseMAC failed: ", bridgeMac, err)
 	}
 	attrs.HardwareAddr = hw
+	if status.Mtu != 0 {
+		attrs.MTU = int(status.Mtu)
+	}
 	link = &netlink.Bridge{LinkAttrs: attrs}
 	if err := netlink.LinkAdd(link); err != nil {
 		errStr := fmt.Sprintf("LinkAdd on %s failed: %s",
@@ -272,16 +276,30 @@ func handleNetworkInstanceModify(
 	if status != nil {
 		log.Functionf("handleNetworkInstanceModify(%s)\n", key)
 		status.ChangeInProgress = types.ChangeInProgressTypeModify
+		updateNetworkInstanceReported(status, config)
 		// Any error from parser?
 		if config.HasError() {
 			log.Errorf("handleNetworkInstanceModify(%s) returning parse error %s",
 				key, config.Error)
 			status.SetError(config.Error, config.ErrorTime)
 			status.ChangeInProgress = types.ChangeInProgressTypeNone
-			publishNetworkInstanceStatus(ctx, status)
+			if key != status.Key() {
+				// Malformed UUID from the controller; keep its key
+				pub.Publish(key, *status)
+			} else {
+				publishNetworkInstanceStatus(ctx, status)
+			}
 			log.Functionf("handleNetworkInstanceModify(%s) done\n", key)
 			return
 		}
+		if status.BridgeNum == 0 {
+			// Never created due to an earlier error, such as a parse
+			// error which has now been cleared
+			log.Noticef("handleNetworkInstanceModify(%s) creating after cleared error %s",
+				key, status.Error)
+			handleNetworkInstanceCreate(ctxArg, key, configArg)
+			return
+		}
 		pub.Publish(status.Key(), *status)
 		doNetworkInstanceModify(ctx, config, status)
 		niUpdateNIprobing(ctx, status)
@@ -293,6 +311,18 @@ func handleNetworkInstanceModify(
 	}
 }
 
+// updateNetworkInstanceReported copies the config which is only reported
+// to the controller and needs no change to the network instance
+func updateNetworkInstanceReported(status *types.NetworkInstanceStatus,
+	config types.NetworkInstanceConfig) {
+
+	status.DnsNameToIPWarnings = config.DnsNameToIPWarnings
+	status.DnsServerWarnings = config.DnsServerWarnings
+	status.PassiveIpType = config.PassiveIpType
+	status.ReservationErrors = config.ReservationErrors
+	status.StaticRouteErrors = config.StaticRouteErrors
+}
+
 func handleNetworkInstanceCreate(
 	ctxArg interface{},
 	key string,
@@ -321,7 +351,12 @@ func handleNetworkInstanceCreate(
 			key, config.Error)
 		status.SetError(config.Error, config.ErrorTime)
 		status.ChangeInProgress = types.ChangeInProgressTypeNone
-		publishNetworkInstanceStatus(ctx, &status)
+		if key != status.Key() {
+			// Malformed UUID from the controller; keep its key
+			pub.Publish(key, status)
+		} else {
+			publishNetworkInstanceStatus(ctx, &status)
+		}
 		log.Functionf("handleNetworkInstanceCreate(%s) done\n", key)
 		return
 	}
@@ -375,6 +410,12 @@ func handleNetworkInstanceDelete(ctxArg interface{}, key string,
 		log.Functionf("handleNetworkInstanceDelete: unknown %s\n", key)
 		return
 	}
+	if key != status.Key() {
+		// Only published for the parse error of a malformed UUID
+		pub.Unpublish(key)
+		log.Functionf("handleNetworkInstanceDelete(%s) done\n", key)
+		return
+	}
 	status.ChangeInProgress = types.ChangeInProgressTypeDelete
 	pub.Publish(status.Key(), *status)
 	if status.Activated {
@@ -679,6 +720,16 @@ func doNetworkInstanceModify(ctx *zedrouterContext,
 		return err
 	}
 
+	if !reflect.DeepEqual(config.UpstreamDnsServers,
+		status.UpstreamDnsServers) {
+		log.Functionf("doNetworkInstanceModify: upstream DNS servers from %v to %v",
+			status.UpstreamDnsServers, config.UpstreamDnsServers)
+		status.UpstreamDnsServers = config.UpstreamDnsServers
+		if status.Activated && status.BridgeIPAddr != "" {
+			restartDnsmasq(ctx, status)
+		}
+	}
+
 	if config.Activate && !status.Activated {
 		err := doNetworkInstanceActivate(ctx, status)
 		if err != nil {
@@ -306,6 +306,8 @@ func launchHostProbe(ctx *zedrouterContext) {
 		TLSConfig:     &tls.Config{InsecureSkipVerify: true},
 		NeedStatsFunc: true,
 		AgentName:     agentName,
+
+		DecryptCipherContext: &ctx.decryptCipherContext,
 	})
 
 	remoteURL := getSystemURL()
@@ -1029,6 +1029,8 @@ func appNetworkDoActivateUnderlayNetwork(
 	if appIPAddr != "" {
 		addToHostsConfiglet(hostsDirpath, config.DisplayName,
 			[]string{appIPAddr})
+		updateAppHostnames(hostsDirpath, nil, ulConfig.Hostnames,
+			appIPAddr)
 	}
 
 	// Default ipset
@@ -1502,6 +1504,12 @@ func doAppNetworkModifyUnderlayNetwork(
 	ulStatus.ACLDependList = dependList
 	setNetworkACLRules(ctx, appID, ulStatus.Name, ruleList)
 
+	if appIPAddr != "" {
+		hostsDirpath := runDirname + "/hosts." + bridgeName
+		updateAppHostnames(hostsDirpath, ulStatus.Hostnames,
+			ulConfig.Hostnames, appIPAddr)
+	}
+
 	newIpsets, staleIpsets, restartDnsmasq := diffIpsets(ipsets,
 		netstatus.BridgeIPSets)
 
@@ -1675,6 +1683,10 @@ func appNetworkDoInactivateUnderlayNetwork(
 	hostsDirpath := runDirname + "/hosts." + bridgeName
 	removeFromHostsConfiglet(hostsDirpath,
 		status.DisplayName)
+	if appIPAddr != "" {
+		updateAppHostnames(hostsDirpath, ulStatus.Hostnames, nil,
+			appIPAddr)
+	}
 	// Look for added or deleted ipsets
 	newIpsets, staleIpsets, restartDnsmasq := diffIpsets(ipsets,
 		netstatus.BridgeIPSets)
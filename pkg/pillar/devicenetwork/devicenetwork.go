@@ -82,7 +82,8 @@ func IsProxyConfigEmpty(proxyConfig types.ProxyConfig) bool {
 //      set Error ( If success, set to "")
 //      set ErrorTime to time of testing ( Even if verify Successful )
 func VerifyDeviceNetworkStatus(log *base.LogObject, status types.DeviceNetworkStatus,
-	successCount uint, iteration int, timeout uint32) (bool, types.IntfStatusMap, error) {
+	successCount uint, iteration int, timeout uint32,
+	decryptCtx *cipher.DecryptCipherContext) (bool, types.IntfStatusMap, error) {
 
 	log.Tracef("VerifyDeviceNetworkStatus() successCount %d, iteration %d",
 		successCount, iteration)
@@ -103,6 +104,8 @@ func VerifyDeviceNetworkStatus(log *base.LogObject, status types.DeviceNetworkSt
 		Serial:           hardware.GetProductSerial(log),
 		SoftSerial:       hardware.GetSoftSerial(log),
 		AgentName:        "devicenetwork",
+
+		DecryptCipherContext: decryptCtx,
 	})
 	log.Functionf("VerifyDeviceNetworkStatus: Use V2 API %v\n", zedcloud.UseV2API())
 	testURL := zedcloud.URLPathString(serverNameAndPort, zedcloudCtx.V2API, nilUUID, "ping")
@@ -276,7 +279,10 @@ func MakeDeviceNetworkStatus(log *base.LogObject, globalConfig types.DevicePortC
 
 // write the access-point name into /run/accesspoint directory
 // the filenames are the physical ports with access-point address/name in content
-func devPortInstallAPname(log *base.LogObject, ifname string, wconfig types.WirelessConfig) {
+// followed by the authentication protocol, the username and the password
+// on separate lines if there is authentication with the access-point
+func devPortInstallAPname(ctx *DeviceNetworkContext, ifname string, wconfig types.WirelessConfig) {
+	log := ctx.Log
 	if _, err := os.Stat(apDirname); err != nil {
 		if err := os.MkdirAll(apDirname, 0700); err != nil {
 			log.Errorln(err)
@@ -296,7 +302,8 @@ func devPortInstallAPname(log *base.LogObject, ifname string, wconfig types.Wire
 		return
 	}
 
-	file, err := os.Create(filepath)
+	// The file can have the password
+	file, err := os.OpenFile(filepath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		log.Errorln(err)
 		return
@@ -305,12 +312,54 @@ func devPortInstallAPname(log *base.LogObject, ifname string, wconfig types.Wire
 	for _, cell := range wconfig.Cellular {
 		s := fmt.Sprintf("%s\n", cell.APN)
 		file.WriteString(s)
+		if cell.AuthProtocol != types.CellularAuthNone {
+			password := getCellularPassword(ctx, cell)
+			s = fmt.Sprintf("%s\n%s\n%s\n",
+				cellularAuthName(cell.AuthProtocol), cell.Username,
+				password)
+			file.WriteString(s)
+		}
 		break // only handle the first APN for now utill we know how to handle multiple of APNs
 	}
 	file.Close()
 	log.Functionf("devPortInstallAPname: write file %s for name %v", filepath, wconfig.Cellular)
 }
 
+// cellularAuthName returns the authentication protocol as expected by the
+// wwan container
+func cellularAuthName(authProtocol types.CellularAuthProtocol) string {
+	switch authProtocol {
+	case types.CellularAuthPAP:
+		return "pap"
+	case types.CellularAuthCHAP:
+		return "chap"
+	case types.CellularAuthPAPAndCHAP:
+		return "both"
+	default:
+		return "none"
+	}
+}
+
+// getCellularPassword returns the decrypted password for the username of
+// the APN, or an empty string if it can not be decrypted
+func getCellularPassword(ctx *DeviceNetworkContext, cell types.CellConfig) string {
+	log := ctx.Log
+	if !cell.CipherBlockStatus.IsCipher {
+		log.Errorf("%s, no password for APN username %s", cell.APN,
+			cell.Username)
+		return ""
+	}
+	status, decBlock, err := cipher.GetCipherCredentials(&ctx.DecryptCipherContext,
+		"devicenetwork", cell.CipherBlockStatus)
+	ctx.PubCipherBlockStatus.Publish(status.Key(), status)
+	if err != nil {
+		log.Errorf("%s, cellular config cipherblock decryption unsuccessful: %v",
+			cell.APN, err)
+		return ""
+	}
+	return decBlock.CellularPassword
+}
+
 func devPortInstallWifiConfig(ctx *DeviceNetworkContext,
 	ifname string, wconfig types.WirelessConfig) bool {
 
@@ -373,6 +422,14 @@ func devPortInstallWifiConfig(ctx *DeviceNetworkContext,
 				// comment out the certifacation verify. file.WriteString("        ca_cert=\"/config/ca.pem\"\n")
 				tmpfile.WriteString("        phase1=\"peaplabel=1\"\n")
 				tmpfile.WriteString("        phase2=\"auth=MSCHAPV2\"\n")
+			case types.KeySchemeWpa3Sae: // WPA3-SAE
+				// SAE requires management frame protection
+				tmpfile.WriteString("        key_mgmt=SAE\n        ieee80211w=2\n")
+				// SAE needs the passphrase; it can not use a hashed one
+				if len(decBlock.WifiPassword) > 0 {
+					s = fmt.Sprintf("        sae_password=\"%s\"\n", decBlock.WifiPassword)
+					tmpfile.WriteString(s)
+				}
 			}
 			if wifi.Priority != 0 {
 				s = fmt.Sprintf("        priority=%d\n", wifi.Priority)
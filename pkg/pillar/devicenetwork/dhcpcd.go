@@ -63,8 +63,8 @@ func UpdateDhcpClient(log *base.LogObject, newConfig, oldConfig types.DevicePort
 
 func doDhcpClientActivate(log *base.LogObject, nuc types.NetworkPortConfig) {
 
-	log.Functionf("doDhcpClientActivate(%s) dhcp %v addr %s gateway %s\n",
-		nuc.IfName, nuc.Dhcp, nuc.AddrSubnet,
+	log.Functionf("doDhcpClientActivate(%s) dhcp %v addrs %v gateway %s\n",
+		nuc.IfName, nuc.Dhcp, nuc.StaticAddrSubnets(),
 		nuc.Gateway.String())
 	if strings.HasPrefix(nuc.IfName, "wwan") {
 		log.Functionf("doDhcpClientActivate: skipping %s\n",
@@ -94,6 +94,15 @@ func doDhcpClientActivate(log *base.LogObject, nuc types.NetworkPortConfig) {
 		if nuc.Gateway != nil && nuc.Gateway.String() == "0.0.0.0" {
 			extras = append(extras, "--nogateway")
 		}
+		switch nuc.AddrMode {
+		case types.AM_SLAAC:
+			extras = append(extras, "--nodhcp6")
+		case types.AM_DHCPV6_STATEFUL:
+			extras = append(extras, "--ia_na")
+		default:
+			// Stateless DHCPv6 is requested by the router
+			// advertisements like when unspecified
+		}
 		if !dhcpcdCmd(log, "--request", extras, nuc.IfName, true) {
 			log.Errorf("doDhcpClientActivate: request failed for %s\n",
 				nuc.IfName)
@@ -121,23 +130,38 @@ func doDhcpClientActivate(log *base.LogObject, nuc types.NetworkPortConfig) {
 				nuc.IfName)
 			return
 		}
-		// Check that we can parse it
-		_, _, err := net.ParseCIDR(nuc.AddrSubnet)
-		if err != nil {
-			log.Errorf("doDhcpClientActivate: failed to parse %s for %s: %s\n",
-				nuc.AddrSubnet, nuc.IfName, err)
-			return
+		// Check that we can parse them; at most one per IP version
+		var args []string
+		for _, addrSubnet := range nuc.StaticAddrSubnets() {
+			ip, _, err := net.ParseCIDR(addrSubnet)
+			if err != nil {
+				log.Errorf("doDhcpClientActivate: failed to parse %s for %s: %s\n",
+					addrSubnet, nuc.IfName, err)
+				return
+			}
+			if len(args) != 0 {
+				args = append(args, "--static")
+			}
+			if ip.To4() == nil {
+				args = append(args, fmt.Sprintf("ip6_address=%s", addrSubnet))
+			} else {
+				args = append(args, fmt.Sprintf("ip_address=%s", addrSubnet))
+			}
 		}
 		for dhcpcdExists(log, nuc.IfName) {
 			log.Warnf("dhcpcd %s already exists", nuc.IfName)
 			time.Sleep(10 * time.Second)
 		}
 		log.Functionf("dhcpcd %s not running", nuc.IfName)
-		args := []string{fmt.Sprintf("ip_address=%s", nuc.AddrSubnet)}
 
 		extras := []string{"-f", "/dhcpcd.conf", "-b", "-t", "0"}
-		if nuc.Gateway == nil || nuc.Gateway.String() == "0.0.0.0" {
+		// dhcpcd has no static IPv6 router; we add the default route
+		// once dhcpcd is running
+		var ipv6Gateway net.IP
+		if nuc.Gateway == nil || nuc.Gateway.IsUnspecified() {
 			extras = append(extras, "--nogateway")
+		} else if nuc.Gateway.To4() == nil {
+			ipv6Gateway = nuc.Gateway
 		} else if nuc.Gateway.String() != "" {
 			args = append(args, "--static",
 				fmt.Sprintf("routers=%s", nuc.Gateway.String()))
@@ -151,6 +175,11 @@ func doDhcpClientActivate(log *base.LogObject, nuc types.NetworkPortConfig) {
 			args = append(args, "--static",
 				fmt.Sprintf("domain_name=%s", nuc.DomainName))
 		}
+		if len(nuc.DomainNames) > 1 {
+			args = append(args, "--static",
+				fmt.Sprintf("domain_search=%s",
+					strings.Join(nuc.DomainNames, " ")))
+		}
 		if nuc.NtpServer != nil && !nuc.NtpServer.IsUnspecified() {
 			args = append(args, "--static",
 				fmt.Sprintf("ntp_servers=%s",
@@ -178,6 +207,13 @@ func doDhcpClientActivate(log *base.LogObject, nuc types.NetworkPortConfig) {
 		if !failed {
 			log.Functionf("dhcpcd %s is running", nuc.IfName)
 		}
+		if !failed && ipv6Gateway != nil {
+			err := replaceDefaultRoute(log, nuc.IfName, ipv6Gateway)
+			if err != nil {
+				log.Errorf("doDhcpClientActivate: IPv6 gateway %s for %s failed: %s",
+					ipv6Gateway, nuc.IfName, err)
+			}
+		}
 	default:
 		log.Errorf("doDhcpClientActivate: unsupported dhcp %v\n",
 			nuc.Dhcp)
@@ -295,7 +295,7 @@ func VerifyPending(ctx *DeviceNetworkContext, pending *DPCPending,
 	const successCount uint = 1
 	ctx.Iteration++
 	rtf, intfStatusMap, err := VerifyDeviceNetworkStatus(log, pending.PendDNS,
-		successCount, ctx.Iteration, timeout)
+		successCount, ctx.Iteration, timeout, &ctx.DecryptCipherContext)
 	// Use TestResults to update the DevicePortConfigList and DeviceNetworkStatus
 	// Note that the TestResults will at least have an updated timestamp
 	// for one of the ports.
@@ -885,7 +885,7 @@ func checkAndUpdateWireless(ctx *DeviceNetworkContext, oCfg *types.DevicePortCon
 		if oldPortCfg == nil || !reflect.DeepEqual(oldPortCfg.WirelessCfg, pCfg.WirelessCfg) {
 			if pCfg.WirelessCfg.WType == types.WirelessTypeCellular ||
 				oldPortCfg != nil && oldPortCfg.WirelessCfg.WType == types.WirelessTypeCellular {
-				devPortInstallAPname(log, pCfg.IfName, pCfg.WirelessCfg)
+				devPortInstallAPname(ctx, pCfg.IfName, pCfg.WirelessCfg)
 			} else if pCfg.WirelessCfg.WType == types.WirelessTypeWifi ||
 				oldPortCfg != nil && oldPortCfg.WirelessCfg.WType == types.WirelessTypeWifi {
 				status := devPortInstallWifiConfig(ctx, pCfg.IfName, pCfg.WirelessCfg)
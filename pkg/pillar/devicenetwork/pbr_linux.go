@@ -10,6 +10,7 @@
 package devicenetwork
 
 import (
+	"net"
 	"syscall"
 
 	"github.com/lf-edge/eve/pkg/pillar/base"
@@ -67,3 +68,21 @@ func CopyRoutesTable(log *base.LogObject, srcTable int, ifindex int, dstTable in
 		}
 	}
 }
+
+// replaceDefaultRoute sets the default route of ifname in the main table
+// through gateway. The gateway is marked onlink since the address of
+// ifname might not be configured yet.
+func replaceDefaultRoute(log *base.LogObject, ifname string, gateway net.IP) error {
+	link, err := netlink.LinkByName(ifname)
+	if err != nil {
+		return err
+	}
+	// The family of the route is the one of the gateway
+	rt := netlink.Route{
+		LinkIndex: link.Attrs().Index,
+		Gw:        gateway,
+		Flags:     int(netlink.FLAG_ONLINK),
+	}
+	log.Functionf("replaceDefaultRoute(%s) %v", ifname, rt)
+	return netlink.RouteReplace(&rt)
+}
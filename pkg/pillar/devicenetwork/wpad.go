@@ -35,6 +35,13 @@ func CheckAndGetNetworkProxy(log *base.LogObject, deviceNetworkStatus *types.Dev
 			ifname)
 		return nil
 	}
+	// The explicit proxies are a fallback for pac-then-static
+	if len(proxyConfig.Proxies) != 0 &&
+		proxyConfig.Policy != types.ProxyPolicyPacThenStatic {
+		log.Tracef("CheckAndGetNetworkProxy(%s): have explicit proxies\n",
+			ifname)
+		return nil
+	}
 	if proxyConfig.NetworkProxyURL != "" {
 		pac, err := getPacFile(log, deviceNetworkStatus,
 			proxyConfig.NetworkProxyURL, ifname)
@@ -217,6 +217,18 @@ then
     echo "Used percentage of /persist after recovery: $diskspace_used"
 fi
 
+# A wipe by nodeagent before the reboot leaves the removal of the logs to
+# this boot, before anything is uploaded
+if [ -f $PERSISTDIR/status/wipe-logs ]; then
+    echo "$(date -Ins -u) Removing the logs for the wipe"
+    for DIR in log newlog/keepSentQueue newlog/failedUpload newlog/appUpload newlog/devUpload
+    do
+        dir_del=$PERSISTDIR/$DIR
+        rm -rf "${dir_del:?}/"*
+    done
+    rm -f $PERSISTDIR/status/wipe-logs
+fi
+
 # Run upgradeconverter
 echo "$(date -Ins -u) device-steps: Starting upgradeconverter (pre-vault)"
 $BINDIR/upgradeconverter pre-vault
@@ -146,6 +146,10 @@ func (ib IoBundle) HasAdapterChanged(log *base.LogObject, phyAdapter PhysicalIOA
 		log.Functionf("Usage changed from %d to %d", ib.Usage, phyAdapter.Usage)
 		return true
 	}
+	if phyAdapter.Error != "" && phyAdapter.Error != ib.Error {
+		log.Functionf("Error changed from %s to %s", ib.Error, phyAdapter.Error)
+		return true
+	}
 	return false
 }
 
@@ -164,6 +168,9 @@ func IoBundleFromPhyAdapter(log *base.LogObject, phyAdapter PhysicalIOAdapter) *
 	ib.Ioports = phyAdapter.Phyaddr.Ioports
 	ib.Serial = phyAdapter.Phyaddr.Serial
 	ib.Usage = phyAdapter.Usage
+	// Errors in the config of the adapter are reported with the IoBundle
+	ib.Error = phyAdapter.Error
+	ib.ErrorTime = phyAdapter.ErrorTime
 	// Guard against models without ifname for network adapters
 	if ib.Type.IsNet() && ib.Ifname == "" {
 		log.Warnf("phyAdapter IsNet without ifname: phylabel %s logicallabel %s",
@@ -173,6 +173,16 @@ func TestIoBundleFromPhyAdapter(t *testing.T) {
 	assert.Equal(t, phyAdapter.Phyaddr.Ioports, ibPtr.Ioports)
 	assert.Equal(t, phyAdapter.Phyaddr.Serial, ibPtr.Serial)
 	assert.Equal(t, phyAdapter.Usage, ibPtr.Usage)
+	assert.Equal(t, "", ibPtr.Error)
+	assert.False(t, ibPtr.HasAdapterChanged(log, phyAdapter))
+
+	// A config error is reported with the IoBundle
+	phyAdapter.SetErrorNow("unknown phyaddrs key pci_long")
+	assert.True(t, ibPtr.HasAdapterChanged(log, phyAdapter))
+	ibPtr = IoBundleFromPhyAdapter(log, phyAdapter)
+	assert.Equal(t, phyAdapter.Error, ibPtr.Error)
+	assert.Equal(t, phyAdapter.ErrorTime, ibPtr.ErrorTime)
+	assert.False(t, ibPtr.HasAdapterChanged(log, phyAdapter))
 }
 
 var aa2 AssignableAdapters = AssignableAdapters{
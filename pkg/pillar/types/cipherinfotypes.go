@@ -143,4 +143,6 @@ type EncryptionBlock struct {
 	WifiUserName      string // If the authentication type is EAP
 	WifiPassword      string
 	ProtectedUserData string
+	ProxyPassword     string // For the Username in ProxyEntry
+	CellularPassword  string // For the Username in CellConfig
 }
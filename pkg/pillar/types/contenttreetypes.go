@@ -147,7 +147,9 @@ func (status ContentTreeStatus) IsContainer() bool {
 
 // IsOCIRegistry will return true if datastore is an OCI registry
 func (status ContentTreeStatus) IsOCIRegistry() bool {
-	if status.DatastoreType == zconfig.DsType_DsContainerRegistry.String() {
+	switch status.DatastoreType {
+	case zconfig.DsType_DsContainerRegistry.String(),
+		zconfig.DsType_DsContainerRegistryCache.String():
 		return true
 	}
 	return false
@@ -236,3 +238,20 @@ func (status ContentTreeStatus) LogDelete(logBase *base.LogObject) {
 func (status ContentTreeStatus) LogKey() string {
 	return string(base.ContentTreeStatusLogType) + "-" + status.Key()
 }
+
+// ContentTreeMetrics - Counters of volumemgr for the content trees,
+// reported in the device metrics. The counters are per boot; they are
+// kept across a restart of volumemgr but not across a reboot.
+type ContentTreeMetrics struct {
+	// Content trees renamed to a tag which resolved to the already
+	// latched sha, hence without a download
+	RetagHits uint64
+	// Content trees renamed to a tag which resolved to a different sha,
+	// which is an error on the content tree since it needs a purge
+	RetagMismatches uint64
+}
+
+// Key returns the key for pubsub
+func (metrics ContentTreeMetrics) Key() string {
+	return "global"
+}
@@ -203,6 +203,10 @@ type VmConfig struct {
 	EnableVnc          bool
 	VncDisplay         uint32
 	VncPasswd          string
+	// NUMA node on which to place the app instance if NumaNodeSet. Set
+	// by zedagent from the AffinityPolicy of the app instance.
+	NumaNodeSet bool
+	NumaNode    uint32
 }
 
 type VmMode uint8
@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus" // OK for logrus.Fatal
 )
@@ -55,12 +56,22 @@ type ConfigItemStatus struct {
 	Value string
 	// Err - Error from last config. nil if no error.
 	Err error
+	// EffectiveValue - Value in use after applying the defaults and
+	// retaining the previous value on error
+	EffectiveValue string
+	// RequestedValue - Value in the config from the controller
+	RequestedValue string
+	// Note - Set when the RequestedValue was rejected
+	Note string
 }
 
 // GlobalStatus - Status of Global Config Items.
 type GlobalStatus struct {
 	ConfigItems        map[string]ConfigItemStatus
 	UnknownConfigItems map[string]ConfigItemStatus
+	// RebootRequiredAspects - Config changes which only take effect
+	// after a reboot
+	RebootRequiredAspects []string
 }
 
 // NewGlobalStatus - Creates a new global status
@@ -76,6 +87,7 @@ func NewGlobalStatus() *GlobalStatus {
 func (gs *GlobalStatus) setItemValue(key, value string) {
 	item := gs.ConfigItems[key]
 	item.Value = value
+	item.EffectiveValue = value
 	gs.ConfigItems[key] = item
 }
 
@@ -109,6 +121,33 @@ func (gs *GlobalStatus) UpdateItemValuesFromGlobalConfig(gc ConfigItemValueMap)
 	}
 }
 
+// UpdateEffectiveValues - Set the EffectiveValue of the ConfigItems from
+// the global config in use, and add the items which are not in ConfigItems
+// but whose value differs from the default
+func (gs *GlobalStatus) UpdateEffectiveValues(gc *ConfigItemValueMap,
+	defaults *ConfigItemValueMap) {
+
+	update := func(key string, val ConfigItemValue, isDefault bool) {
+		item, ok := gs.ConfigItems[key]
+		if !ok && isDefault {
+			return
+		}
+		item.EffectiveValue = val.StringValue()
+		gs.ConfigItems[key] = item
+	}
+	for key, val := range gc.GlobalSettings {
+		defVal, ok := defaults.GlobalSettings[key]
+		update(string(key), val, ok && defVal == val)
+	}
+	for agentName, agentSettingMap := range gc.AgentSettings {
+		for setting, val := range agentSettingMap {
+			key := "agent." + agentName + "." + string(setting)
+			defVal, err := defaults.agentConfigItemValue(agentName, setting)
+			update(key, val, err == nil && defVal == val)
+		}
+	}
+}
+
 // GlobalConfig is used for log levels and timer values which are preserved
 // across reboots and baseimage-updates.
 
@@ -130,6 +169,22 @@ const (
 	MetricInterval GlobalSettingKey = "timer.metric.interval"
 	// DiskScanMetricInterval global setting key
 	DiskScanMetricInterval GlobalSettingKey = "timer.metric.diskscan.interval"
+	// MetricDeviceInterval global setting key
+	MetricDeviceInterval GlobalSettingKey = "timer.metric.device.interval"
+	// MetricAppInterval global setting key
+	MetricAppInterval GlobalSettingKey = "timer.metric.app.interval"
+	// MetricNetworkInstanceInterval global setting key
+	MetricNetworkInstanceInterval GlobalSettingKey = "timer.metric.networkinstance.interval"
+	// MetricVolumeInterval global setting key
+	MetricVolumeInterval GlobalSettingKey = "timer.metric.volume.interval"
+	// InfoDeviceInterval global setting key
+	InfoDeviceInterval GlobalSettingKey = "timer.info.device.interval"
+	// InfoAppInterval global setting key
+	InfoAppInterval GlobalSettingKey = "timer.info.app.interval"
+	// InfoNetworkInstanceInterval global setting key
+	InfoNetworkInstanceInterval GlobalSettingKey = "timer.info.networkinstance.interval"
+	// InfoVolumeInterval global setting key
+	InfoVolumeInterval GlobalSettingKey = "timer.info.volume.interval"
 	// ResetIfCloudGoneTime global setting key
 	ResetIfCloudGoneTime GlobalSettingKey = "timer.reboot.no.network"
 	// FallbackIfCloudGoneTime global setting key
@@ -181,6 +236,30 @@ const (
 	// how the EVE microservices will use free and non-free (e.g., WWAN)
 	// ports for image downloads.
 	DownloadMaxPortCost GlobalSettingKey = "network.download.max.cost"
+	// RebootRequiredWindowStart global setting key; the hour in UTC from
+	// which the device reboots when enabled by RebootRequiredAutoReboot
+	RebootRequiredWindowStart GlobalSettingKey = "reboot.required.window.start"
+	// RebootRequiredWindowEnd global setting key; the hour in UTC up to
+	// which the device reboots when enabled by RebootRequiredAutoReboot
+	RebootRequiredWindowEnd GlobalSettingKey = "reboot.required.window.end"
+	// QuietHoursStart global setting key; the hour in the Timezone from
+	// which changes to the app instances and the base OS, and reboot
+	// commands which are not urgent, are deferred
+	QuietHoursStart GlobalSettingKey = "quiet.hours.start"
+	// QuietHoursEnd global setting key; the hour in the Timezone up to
+	// which the changes are deferred. The same start and end means no
+	// quiet hours.
+	QuietHoursEnd GlobalSettingKey = "quiet.hours.end"
+	// NetworkProxyPacfileMaxBytes global setting key; the largest PAC file
+	// accepted in the proxy configuration of a network
+	NetworkProxyPacfileMaxBytes GlobalSettingKey = "network.proxy.pacfile.maxbytes"
+	// NetworkUplinkCapacityKbps global setting key; the capacity of an
+	// uplink against which the uplink rate limits of the network instances
+	// sharing it are checked. Zero disables the check.
+	NetworkUplinkCapacityKbps GlobalSettingKey = "network.uplink.capacity.kbps"
+	// AppMaxInstances global setting key; the app instances beyond this
+	// number, in the order of the config, are rejected. Zero means no limit.
+	AppMaxInstances GlobalSettingKey = "app.max.instances"
 
 	// Bool Items
 	// UsbAccess global setting key
@@ -195,6 +274,31 @@ const (
 	IgnoreDiskCheckForApps GlobalSettingKey = "storage.apps.ignore.disk.check"
 	// AllowLogFastupload global setting key
 	AllowLogFastupload GlobalSettingKey = "newlog.allow.fastupload"
+	// HoldAppActivateDuringUpdate global setting key; when set, app instances
+	// are not activated while a new base OS is being tested
+	HoldAppActivateDuringUpdate GlobalSettingKey = "app.hold.activate.during.update"
+	// VolumemgrDebugHTTP global setting key enables a localhost http
+	// server in volumemgr for debugging
+	VolumemgrDebugHTTP GlobalSettingKey = "debug.enable.volumemgr.http"
+	// AppVncRequirePassword global setting key; when set, app instances
+	// which enable VNC must specify a VNC password
+	AppVncRequirePassword GlobalSettingKey = "app.vnc.require.password"
+	// AppVncDisplayMin global setting key; the lowest VNC display number
+	// app instances may use
+	AppVncDisplayMin GlobalSettingKey = "app.vnc.display.min"
+	// AppVncDisplayMax global setting key; the highest VNC display number
+	// app instances may use
+	AppVncDisplayMax GlobalSettingKey = "app.vnc.display.max"
+	// RebootRequiredAutoReboot global setting key; when set, the device
+	// reboots in the window when config changes need a reboot
+	RebootRequiredAutoReboot GlobalSettingKey = "reboot.required.auto"
+	// NetworkAllowMesh global setting key; mesh network instances are
+	// deprecated and rejected unless set
+	NetworkAllowMesh GlobalSettingKey = "network.allow.mesh"
+	// NetworkOverlayCompat global setting key; the app interfaces with
+	// legacy overlay (LISP) fields on a network instance which is not mesh
+	// are run without those fields
+	NetworkOverlayCompat GlobalSettingKey = "network.overlay.compat"
 
 	// TriState Items
 	// NetworkFallbackAnyEth global setting key
@@ -210,6 +314,12 @@ const (
 	DefaultLogLevel GlobalSettingKey = "debug.default.loglevel"
 	// DefaultRemoteLogLevel global setting key
 	DefaultRemoteLogLevel GlobalSettingKey = "debug.default.remote.loglevel"
+	// DefaultDatastoreRegion global setting key; used for datastores
+	// for which the controller does not specify a region
+	DefaultDatastoreRegion GlobalSettingKey = "storage.datastore.default.region"
+	// Timezone global setting key; the IANA name of the time zone of the
+	// device, in which the quiet hours are
+	Timezone GlobalSettingKey = "timezone"
 
 	// XXX Temporary flag to disable RFC 3442 classless static route usage
 	DisableDHCPAllOnesNetMask GlobalSettingKey = "debug.disable.dhcp.all-ones.netmask"
@@ -257,6 +367,8 @@ type ConfigItemSpec struct {
 	IntMin     uint32
 	IntMax     uint32
 	IntDefault uint32
+	// IntUnset allows 0, meaning not set, outside of IntMin / IntMax
+	IntUnset bool
 
 	StringValidator Validator
 	StringDefault   string
@@ -309,6 +421,20 @@ func (specMap *ConfigItemSpecMap) AddIntItem(key GlobalSettingKey,
 	specMap.GlobalSettings[key] = configItem
 }
 
+// AddUnsetIntItem - Adds integer item to specMap which defaults to 0,
+// meaning not set, and is otherwise within min / max
+func (specMap *ConfigItemSpecMap) AddUnsetIntItem(key GlobalSettingKey,
+	min uint32, max uint32) {
+	configItem := ConfigItemSpec{
+		ItemType: ConfigItemTypeInt,
+		Key:      string(key),
+		IntMin:   min,
+		IntMax:   max,
+		IntUnset: true,
+	}
+	specMap.GlobalSettings[key] = configItem
+}
+
 // AddBoolItem - Adds boolean item to specMap
 func (specMap *ConfigItemSpecMap) AddBoolItem(key GlobalSettingKey, defaultBool bool) {
 	configItem := ConfigItemSpec{
@@ -674,7 +800,9 @@ func (configSpec ConfigItemSpec) parseValue(itemValue string) (ConfigItemValue,
 		i64, err := strconv.ParseUint(itemValue, 10, 32)
 		if err == nil {
 			val := uint32(i64)
-			if val > configSpec.IntMax || val < configSpec.IntMin {
+			if val == 0 && configSpec.IntUnset {
+				value.IntValue = val
+			} else if val > configSpec.IntMax || val < configSpec.IntMin {
 				retErr = fmt.Errorf("value out of bounds. Parsed value: %d, Max: %d, Min: %d",
 					val, configSpec.IntMax, configSpec.IntMin)
 			} else {
@@ -733,6 +861,18 @@ func NewConfigItemSpecMap() ConfigItemSpecMap {
 	// Need to be careful about max value. Controller may use metric message to
 	// update status of device (online / suspect etc ).
 	configItemSpecMap.AddIntItem(MetricInterval, 60, 5, HourInSec)
+	// timer.metric.<class>.interval (seconds) - metrics of the class
+	// default to timer.metric.interval if not set
+	configItemSpecMap.AddUnsetIntItem(MetricDeviceInterval, 10, HourInSec)
+	configItemSpecMap.AddUnsetIntItem(MetricAppInterval, 10, HourInSec)
+	configItemSpecMap.AddUnsetIntItem(MetricNetworkInstanceInterval, 10, HourInSec)
+	configItemSpecMap.AddUnsetIntItem(MetricVolumeInterval, 10, HourInSec)
+	// timer.info.<class>.interval (seconds) - info of the class is
+	// only sent when it changes if not set
+	configItemSpecMap.AddUnsetIntItem(InfoDeviceInterval, 10, HourInSec)
+	configItemSpecMap.AddUnsetIntItem(InfoAppInterval, 10, HourInSec)
+	configItemSpecMap.AddUnsetIntItem(InfoNetworkInstanceInterval, 10, HourInSec)
+	configItemSpecMap.AddUnsetIntItem(InfoVolumeInterval, 10, HourInSec)
 	// timer.reboot.no.network (seconds) - reboot after no cloud connectivity
 	// Max designed to allow the option of never rebooting even if device
 	//  can't connect to the cloud
@@ -764,6 +904,21 @@ func NewConfigItemSpecMap() ConfigItemSpecMap {
 	// LogRemainToSendMBytes - Default is 2 Gbytes, minimum is 10 Mbytes
 	configItemSpecMap.AddIntItem(LogRemainToSendMBytes, 2048, 10, 0xFFFFFFFF)
 	configItemSpecMap.AddIntItem(DownloadMaxPortCost, 0, 0, 255)
+	configItemSpecMap.AddIntItem(RebootRequiredWindowStart, 2, 0, 23)
+	configItemSpecMap.AddIntItem(RebootRequiredWindowEnd, 4, 0, 23)
+	// QuietHoursStart and QuietHoursEnd - Default is no quiet hours
+	configItemSpecMap.AddIntItem(QuietHoursStart, 0, 0, 23)
+	configItemSpecMap.AddIntItem(QuietHoursEnd, 0, 0, 23)
+	// NetworkProxyPacfileMaxBytes - Default is 64 Kbytes, minimum is 1 Kbyte
+	configItemSpecMap.AddIntItem(NetworkProxyPacfileMaxBytes, 64*1024, 1024,
+		16*1024*1024)
+	// NetworkUplinkCapacityKbps - Default is 0, i.e., not checked
+	configItemSpecMap.AddIntItem(NetworkUplinkCapacityKbps, 0, 0, 0xFFFFFFFF)
+	// AppMaxInstances - Default is 0, i.e., no limit
+	configItemSpecMap.AddIntItem(AppMaxInstances, 0, 0, 0xFFFFFFFF)
+	// The VNC tcp port is 5900 plus the display number
+	configItemSpecMap.AddIntItem(AppVncDisplayMin, 0, 0, 65535-5900)
+	configItemSpecMap.AddIntItem(AppVncDisplayMax, 65535-5900, 0, 65535-5900)
 
 	// Add Bool Items
 	configItemSpecMap.AddBoolItem(UsbAccess, true) // Controller likely default to false
@@ -771,6 +926,12 @@ func NewConfigItemSpecMap() ConfigItemSpecMap {
 	configItemSpecMap.AddBoolItem(IgnoreMemoryCheckForApps, false)
 	configItemSpecMap.AddBoolItem(IgnoreDiskCheckForApps, false)
 	configItemSpecMap.AddBoolItem(AllowLogFastupload, false)
+	configItemSpecMap.AddBoolItem(VolumemgrDebugHTTP, false)
+	configItemSpecMap.AddBoolItem(HoldAppActivateDuringUpdate, false)
+	configItemSpecMap.AddBoolItem(AppVncRequirePassword, false)
+	configItemSpecMap.AddBoolItem(RebootRequiredAutoReboot, false)
+	configItemSpecMap.AddBoolItem(NetworkAllowMesh, false)
+	configItemSpecMap.AddBoolItem(NetworkOverlayCompat, false)
 	configItemSpecMap.AddBoolItem(DisableDHCPAllOnesNetMask, false)
 	configItemSpecMap.AddBoolItem(ProcessCloudInitMultiPart, false)
 
@@ -782,6 +943,9 @@ func NewConfigItemSpecMap() ConfigItemSpecMap {
 	configItemSpecMap.AddStringItem(SSHAuthorizedKeys, "", blankValidator)
 	configItemSpecMap.AddStringItem(DefaultLogLevel, "info", parseLevel)
 	configItemSpecMap.AddStringItem(DefaultRemoteLogLevel, "info", parseLevel)
+	configItemSpecMap.AddStringItem(DefaultDatastoreRegion, "us-west-2",
+		blankValidator)
+	configItemSpecMap.AddStringItem(Timezone, "UTC", parseTimezone)
 
 	// Add Agent Settings
 	configItemSpecMap.AddAgentSettingStringItem(LogLevel, "info", parseLevel)
@@ -796,6 +960,12 @@ func parseLevel(level string) error {
 	return err
 }
 
+// parseTimezone - A validator that accepts the time zones known to the device
+func parseTimezone(tz string) error {
+	_, err := time.LoadLocation(tz)
+	return err
+}
+
 // blankValidator - A validator that accepts any string
 func blankValidator(s string) error {
 	return nil
@@ -149,6 +149,14 @@ func TestNewConfigItemSpecMap(t *testing.T) {
 		ConfigInterval,
 		MetricInterval,
 		DiskScanMetricInterval,
+		MetricDeviceInterval,
+		MetricAppInterval,
+		MetricNetworkInstanceInterval,
+		MetricVolumeInterval,
+		InfoDeviceInterval,
+		InfoAppInterval,
+		InfoNetworkInstanceInterval,
+		InfoVolumeInterval,
 		ResetIfCloudGoneTime,
 		FallbackIfCloudGoneTime,
 		MintimeUpdateSuccess,
@@ -172,6 +180,15 @@ func TestNewConfigItemSpecMap(t *testing.T) {
 		ForceFallbackCounter,
 		LogRemainToSendMBytes,
 		DownloadMaxPortCost,
+		RebootRequiredWindowStart,
+		RebootRequiredWindowEnd,
+		QuietHoursStart,
+		QuietHoursEnd,
+		NetworkProxyPacfileMaxBytes,
+		NetworkUplinkCapacityKbps,
+		AppMaxInstances,
+		AppVncDisplayMin,
+		AppVncDisplayMax,
 		// Bool Items
 		UsbAccess,
 		AllowAppVnc,
@@ -179,6 +196,12 @@ func TestNewConfigItemSpecMap(t *testing.T) {
 		IgnoreMemoryCheckForApps,
 		IgnoreDiskCheckForApps,
 		AllowLogFastupload,
+		VolumemgrDebugHTTP,
+		HoldAppActivateDuringUpdate,
+		AppVncRequirePassword,
+		RebootRequiredAutoReboot,
+		NetworkAllowMesh,
+		NetworkOverlayCompat,
 		// TriState Items
 		NetworkFallbackAnyEth,
 		MaintenanceMode,
@@ -186,6 +209,8 @@ func TestNewConfigItemSpecMap(t *testing.T) {
 		SSHAuthorizedKeys,
 		DefaultLogLevel,
 		DefaultRemoteLogLevel,
+		DefaultDatastoreRegion,
+		Timezone,
 		DisableDHCPAllOnesNetMask,
 		ProcessCloudInitMultiPart,
 	}
@@ -349,6 +374,23 @@ func TestParseGlobalItem(t *testing.T) {
 			expectedValue: "20",
 			oldValue:      "20",
 		},
+		"Global Setting - Unset int Value": {
+			item: configItemStruct{
+				key:   string(MetricAppInterval),
+				value: "0",
+			},
+			itemType: ConfigItemTypeInt,
+		},
+		"Global Setting - Invalid unset int Value - Retain Old Value": {
+			item: configItemStruct{
+				key:   string(MetricAppInterval),
+				value: "5",
+			},
+			itemType:      ConfigItemTypeInt,
+			expectError:   true,
+			expectedValue: "30",
+			oldValue:      "30",
+		},
 	}
 	for testname, test := range testMatrix {
 		t.Logf("Running test case %s, test: %+v", testname, test)
@@ -82,4 +82,7 @@ const (
 
 	// ContainerdContentDir - path to containerd`s content store
 	ContainerdContentDir = PersistDir + "/containerd/io.containerd.content.v1.content"
+	// WipeLogsFile - tells device-steps.sh to remove the logs at boot, as
+	// part of a wipe, before newlogd gets to upload them
+	WipeLogsFile = PersistStatusDir + "/wipe-logs"
 )
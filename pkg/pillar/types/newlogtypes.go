@@ -5,6 +5,10 @@ package types
 
 import (
 	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lf-edge/eve/pkg/pillar/base"
+	uuid "github.com/satori/go.uuid"
 )
 
 const (
@@ -72,3 +76,81 @@ type NewlogMetrics struct {
 	DevMetrics logfileMetrics // Device metrics
 	AppMetrics logfileMetrics // App metrics
 }
+
+// MaxAppLogDropRegexes is the largest number of drop regular expressions
+// in an AppLogPolicy
+const MaxAppLogDropRegexes = 10
+
+// AppLogPolicy filters the console and syslog output of an app instance
+// before it is sent to the controller
+type AppLogPolicy struct {
+	// MinSeverity is a logrus level name; the log entries less severe are
+	// dropped. Empty keeps all.
+	MinSeverity string
+	// DropRegexes drops the log entries matching any of them
+	DropRegexes []string
+	// SamplePercent of the remaining log entries are kept; zero keeps all
+	SamplePercent uint32
+	// ErrorAndTime is set if the policy is invalid, in which case it is
+	// not applied. Does not affect the app instance.
+	ErrorAndTime
+}
+
+// IsEmpty returns true if the policy does not filter anything
+func (policy AppLogPolicy) IsEmpty() bool {
+	return policy.MinSeverity == "" && len(policy.DropRegexes) == 0 &&
+		policy.SamplePercent == 0
+}
+
+// AppLogPolicyConfig is published by zedagent for each app instance with
+// a valid non-empty AppLogPolicy, for the log shipper to apply.
+type AppLogPolicyConfig struct {
+	AppUUID     uuid.UUID
+	DisplayName string
+	Policy      AppLogPolicy
+}
+
+// Key : AppLogPolicyConfig unique key
+func (config AppLogPolicyConfig) Key() string {
+	return config.AppUUID.String()
+}
+
+// LogCreate :
+func (config AppLogPolicyConfig) LogCreate(logBase *base.LogObject) {
+	logObject := base.NewLogObject(logBase, base.AppLogPolicyConfigLogType,
+		config.DisplayName, config.AppUUID, config.LogKey())
+	if logObject == nil {
+		return
+	}
+	logObject.CloneAndAddField("min-severity", config.Policy.MinSeverity).
+		AddField("drop-regex-count", len(config.Policy.DropRegexes)).
+		AddField("sample-percent", config.Policy.SamplePercent).
+		Noticef("App log policy config create")
+}
+
+// LogModify :
+func (config AppLogPolicyConfig) LogModify(logBase *base.LogObject, old interface{}) {
+	logObject := base.EnsureLogObject(logBase, base.AppLogPolicyConfigLogType,
+		config.DisplayName, config.AppUUID, config.LogKey())
+
+	oldConfig, ok := old.(AppLogPolicyConfig)
+	if !ok {
+		logObject.Clone().Fatalf("LogModify: Old object interface passed is not of AppLogPolicyConfig type")
+	}
+	logObject.CloneAndAddField("diff", cmp.Diff(oldConfig, config)).
+		Noticef("App log policy config modify")
+}
+
+// LogDelete :
+func (config AppLogPolicyConfig) LogDelete(logBase *base.LogObject) {
+	logObject := base.EnsureLogObject(logBase, base.AppLogPolicyConfigLogType,
+		config.DisplayName, config.AppUUID, config.LogKey())
+	logObject.Noticef("App log policy config delete")
+
+	base.DeleteLogObject(logBase, config.LogKey())
+}
+
+// LogKey :
+func (config AppLogPolicyConfig) LogKey() string {
+	return string(base.AppLogPolicyConfigLogType) + "-" + config.Key()
+}
@@ -43,6 +43,16 @@ type PhysicalIOAdapter struct {
 	Assigngrp    string
 	Usage        zcommon.PhyIoMemberUsage
 	UsagePolicy  PhyIOUsagePolicy
+	// AcceleratorMemoryMB is the memory of a GPU or other accelerator which
+	// app instances can reserve; zero if not advertised
+	AcceleratorMemoryMB uint32
+	// NumaNode is the NUMA node local to the adapter if NumaNodeKnown
+	NumaNodeKnown bool
+	NumaNode      uint32
+	// ErrorAndTime is set if the Phylabel or Logicallabel is not unique,
+	// if Phyaddrs in the config has unknown keys, or if the members of the
+	// Assigngrp are inconsistent
+	ErrorAndTime
 	// FIXME: cbattr - This needs to be thought through to be made into
 	//  a structure OR may be even various attributes in PhysicalIO structure
 	// itself.
@@ -0,0 +1,198 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lf-edge/eve/pkg/pillar/base"
+)
+
+// SystemAdapterDisposition - What became of a SystemAdapter from the config.
+// Must match SystemAdapterDisposition in api/proto/info/info.proto
+type SystemAdapterDisposition uint8
+
+const (
+	// SystemAdapterDispositionUnspecified : not parsed
+	SystemAdapterDispositionUnspecified SystemAdapterDisposition = iota
+	// SystemAdapterAccepted : a port in the DevicePortConfig
+	SystemAdapterAccepted
+	// SystemAdapterAcceptedWithError : a port in the DevicePortConfig
+	// with an error
+	SystemAdapterAcceptedWithError
+	// SystemAdapterRejected : left out of the DevicePortConfig
+	SystemAdapterRejected
+)
+
+// String returns the string name
+func (disposition SystemAdapterDisposition) String() string {
+	switch disposition {
+	case SystemAdapterDispositionUnspecified:
+		return "Unspecified"
+	case SystemAdapterAccepted:
+		return "Accepted"
+	case SystemAdapterAcceptedWithError:
+		return "AcceptedWithError"
+	case SystemAdapterRejected:
+		return "Rejected"
+	default:
+		return fmt.Sprintf("Unknown SystemAdapterDisposition %d", disposition)
+	}
+}
+
+// SystemAdapterResult - The result of parsing one SystemAdapter
+type SystemAdapterResult struct {
+	Name           string
+	LowerLayerName string
+	Disposition    SystemAdapterDisposition
+	// Reason is the error for AcceptedWithError and Rejected
+	Reason string
+}
+
+// SystemAdapterReport - Published by zedagent each time it parses the
+// SystemAdapterList from the config, with a result for every SystemAdapter
+// in the config order, so that the adapters left out of the
+// DevicePortConfig are reported too.
+type SystemAdapterReport struct {
+	Results []SystemAdapterResult
+}
+
+// Key returns the key for pubsub
+func (report SystemAdapterReport) Key() string {
+	return "global"
+}
+
+// LogCreate :
+func (report SystemAdapterReport) LogCreate(logBase *base.LogObject) {
+	logObject := base.NewLogObject(logBase, base.SystemAdapterReportLogType, "",
+		nilUUID, report.LogKey())
+	if logObject == nil {
+		return
+	}
+	logObject.CloneAndAddField("results", len(report.Results)).
+		Noticef("System adapter report create")
+}
+
+// LogModify :
+func (report SystemAdapterReport) LogModify(logBase *base.LogObject, old interface{}) {
+	logObject := base.EnsureLogObject(logBase, base.SystemAdapterReportLogType, "",
+		nilUUID, report.LogKey())
+
+	oldReport, ok := old.(SystemAdapterReport)
+	if !ok {
+		logObject.Clone().Fatalf("LogModify: Old object interface passed is not of SystemAdapterReport type")
+	}
+	logObject.CloneAndAddField("diff", cmp.Diff(oldReport, report)).
+		Noticef("System adapter report modify")
+}
+
+// LogDelete :
+func (report SystemAdapterReport) LogDelete(logBase *base.LogObject) {
+	logObject := base.EnsureLogObject(logBase, base.SystemAdapterReportLogType, "",
+		nilUUID, report.LogKey())
+	logObject.Noticef("System adapter report delete")
+
+	base.DeleteLogObject(logBase, report.LogKey())
+}
+
+// LogKey :
+func (report SystemAdapterReport) LogKey() string {
+	return string(base.SystemAdapterReportLogType) + "-" + report.Key()
+}
+
+// DevicePortConfigParseFailure - A SystemAdapter which failed to parse
+type DevicePortConfigParseFailure struct {
+	Logicallabel string
+	Disposition  SystemAdapterDisposition
+	Error        string
+}
+
+// DevicePortConfigParseStatus - Published by zedagent each time it parses
+// the SystemAdapterList from the config, with a summary of the results.
+// The ports accepted with an error count as warnings and the rejected
+// ones as errors.
+type DevicePortConfigParseStatus struct {
+	// ConfigSha is the sha256 of the SystemAdapterList
+	ConfigSha    string
+	OkCount      int
+	WarningCount int
+	ErrorCount   int
+	Failures     []DevicePortConfigParseFailure
+	// Held is set when the DevicePortConfig was not published since all
+	// its ports failed while the previous one has a working management port
+	Held bool
+}
+
+// NewDevicePortConfigParseStatus summarizes the report
+func NewDevicePortConfigParseStatus(configSha string,
+	report SystemAdapterReport) DevicePortConfigParseStatus {
+
+	status := DevicePortConfigParseStatus{ConfigSha: configSha}
+	for _, result := range report.Results {
+		switch result.Disposition {
+		case SystemAdapterAccepted:
+			status.OkCount++
+			continue
+		case SystemAdapterAcceptedWithError:
+			status.WarningCount++
+		default:
+			status.ErrorCount++
+		}
+		status.Failures = append(status.Failures,
+			DevicePortConfigParseFailure{
+				Logicallabel: result.Name,
+				Disposition:  result.Disposition,
+				Error:        result.Reason,
+			})
+	}
+	return status
+}
+
+// Key returns the key for pubsub
+func (status DevicePortConfigParseStatus) Key() string {
+	return "global"
+}
+
+// LogCreate :
+func (status DevicePortConfigParseStatus) LogCreate(logBase *base.LogObject) {
+	logObject := base.NewLogObject(logBase,
+		base.DevicePortConfigParseStatusLogType, "", nilUUID, status.LogKey())
+	if logObject == nil {
+		return
+	}
+	logObject.CloneAndAddField("config-sha", status.ConfigSha).
+		AddField("ok-count", status.OkCount).
+		AddField("warning-count", status.WarningCount).
+		AddField("error-count", status.ErrorCount).
+		AddField("held", status.Held).
+		Noticef("DevicePortConfig parse status create")
+}
+
+// LogModify :
+func (status DevicePortConfigParseStatus) LogModify(logBase *base.LogObject, old interface{}) {
+	logObject := base.EnsureLogObject(logBase,
+		base.DevicePortConfigParseStatusLogType, "", nilUUID, status.LogKey())
+
+	oldStatus, ok := old.(DevicePortConfigParseStatus)
+	if !ok {
+		logObject.Clone().Fatalf("LogModify: Old object interface passed is not of DevicePortConfigParseStatus type")
+	}
+	logObject.CloneAndAddField("diff", cmp.Diff(oldStatus, status)).
+		Noticef("DevicePortConfig parse status modify")
+}
+
+// LogDelete :
+func (status DevicePortConfigParseStatus) LogDelete(logBase *base.LogObject) {
+	logObject := base.EnsureLogObject(logBase,
+		base.DevicePortConfigParseStatusLogType, "", nilUUID, status.LogKey())
+	logObject.Noticef("DevicePortConfig parse status delete")
+
+	base.DeleteLogObject(logBase, status.LogKey())
+}
+
+// LogKey :
+func (status DevicePortConfigParseStatus) LogKey() string {
+	return string(base.DevicePortConfigParseStatusLogType) + "-" + status.Key()
+}
@@ -129,6 +129,9 @@ type VolumeStatus struct {
 	LastUse                 time.Time
 	PreReboot               bool // Was volume last use prior to device reboot?
 	ReferenceName           string
+	// Retained is set when the volume is no longer used but is kept due
+	// to an AppVolumeRetention
+	Retained bool
 
 	ErrorAndTimeWithSource
 }
@@ -292,6 +295,87 @@ func (config VolumeRefConfig) LogKey() string {
 	return string(base.VolumeRefConfigLogType) + "-" + config.Key()
 }
 
+// MaxVolumeRetentionDays is the longest the volumes of a removed app
+// instance can be retained
+const MaxVolumeRetentionDays = 30
+
+// AppVolumeRetention is published by zedagent for each app instance with
+// VolumeRetentionDays. volumemgr does not delete the listed volumes when
+// they are no longer used before Expiry, so that adding the app instance
+// back reattaches them. Expiry is set when the app instance is removed from
+// the config; until then the volumes are retained without a time limit.
+type AppVolumeRetention struct {
+	AppUUID       uuid.UUID
+	DisplayName   string
+	RetentionDays uint32
+	VolumeKeys    []string  // VolumeStatus keys
+	Expiry        time.Time // Zero while the app instance is configured
+}
+
+// Key : AppVolumeRetention unique key
+func (retention AppVolumeRetention) Key() string {
+	return retention.AppUUID.String()
+}
+
+// Expired returns true if the app instance was removed more than
+// RetentionDays before the time
+func (retention AppVolumeRetention) Expired(now time.Time) bool {
+	return !retention.Expiry.IsZero() && !now.Before(retention.Expiry)
+}
+
+// HasVolume returns true if the volume with the key is retained at the time
+func (retention AppVolumeRetention) HasVolume(key string, now time.Time) bool {
+	if retention.Expired(now) {
+		return false
+	}
+	for _, k := range retention.VolumeKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// LogCreate :
+func (retention AppVolumeRetention) LogCreate(logBase *base.LogObject) {
+	logObject := base.NewLogObject(logBase, base.AppVolumeRetentionLogType,
+		retention.DisplayName, retention.AppUUID, retention.LogKey())
+	if logObject == nil {
+		return
+	}
+	logObject.CloneAndAddField("volumes", strings.Join(retention.VolumeKeys, ",")).
+		AddField("expiry", retention.Expiry).
+		Noticef("App volume retention create")
+}
+
+// LogModify :
+func (retention AppVolumeRetention) LogModify(logBase *base.LogObject, old interface{}) {
+	logObject := base.EnsureLogObject(logBase, base.AppVolumeRetentionLogType,
+		retention.DisplayName, retention.AppUUID, retention.LogKey())
+
+	oldRetention, ok := old.(AppVolumeRetention)
+	if !ok {
+		logObject.Clone().Fatalf("LogModify: Old object interface passed is not of AppVolumeRetention type")
+	}
+	logObject.CloneAndAddField("diff", cmp.Diff(oldRetention, retention)).
+		Noticef("App volume retention modify")
+}
+
+// LogDelete :
+func (retention AppVolumeRetention) LogDelete(logBase *base.LogObject) {
+	logObject := base.EnsureLogObject(logBase, base.AppVolumeRetentionLogType,
+		retention.DisplayName, retention.AppUUID, retention.LogKey())
+	logObject.CloneAndAddField("volumes", strings.Join(retention.VolumeKeys, ",")).
+		Noticef("App volume retention delete")
+
+	base.DeleteLogObject(logBase, retention.LogKey())
+}
+
+// LogKey :
+func (retention AppVolumeRetention) LogKey() string {
+	return string(base.AppVolumeRetentionLogType) + "-" + retention.Key()
+}
+
 // VolumeRefStatus : Reference to a Volume specified separately in the API
 // If a volume is purged (re-created from scratch) it will either have a new
 // UUID or a new generationCount
@@ -0,0 +1,188 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lf-edge/eve/pkg/pillar/base"
+)
+
+// WipeScope - What a wipe removes. The device identity and the network
+// config are always kept.
+// Must match WipeScope in api/proto/evecommon/evecommon.proto
+type WipeScope uint8
+
+const (
+	// WipeScopeUnspecified : not a valid scope
+	WipeScopeUnspecified WipeScope = iota
+	// WipeScopeApps : the app instances, their volumes and state
+	WipeScopeApps
+	// WipeScopeAppsAndLogs : the apps and the logs
+	WipeScopeAppsAndLogs
+	// WipeScopeAllButIdentity : all of /persist but the identity and the
+	// network config
+	WipeScopeAllButIdentity
+)
+
+// String returns the string name
+func (scope WipeScope) String() string {
+	switch scope {
+	case WipeScopeUnspecified:
+		return "Unspecified"
+	case WipeScopeApps:
+		return "Apps"
+	case WipeScopeAppsAndLogs:
+		return "AppsAndLogs"
+	case WipeScopeAllButIdentity:
+		return "AllButIdentity"
+	default:
+		return fmt.Sprintf("Unknown WipeScope %d", scope)
+	}
+}
+
+// WipeState - The progress of a wipe.
+// Must match ZWipeState in api/proto/info/info.proto
+type WipeState uint8
+
+const (
+	// WipeStateUnspecified : no wipe
+	WipeStateUnspecified WipeState = iota
+	// WipeStateRejected : the WipeCmd was not accepted
+	WipeStateRejected
+	// WipeStateRequested : a WipeRequest is published
+	WipeStateRequested
+	// WipeStateInProgress : the wipe started
+	WipeStateInProgress
+	// WipeStateDone : the wipe completed
+	WipeStateDone
+	// WipeStateFailed : the wipe failed
+	WipeStateFailed
+)
+
+// String returns the string name
+func (state WipeState) String() string {
+	switch state {
+	case WipeStateUnspecified:
+		return "Unspecified"
+	case WipeStateRejected:
+		return "Rejected"
+	case WipeStateRequested:
+		return "Requested"
+	case WipeStateInProgress:
+		return "InProgress"
+	case WipeStateDone:
+		return "Done"
+	case WipeStateFailed:
+		return "Failed"
+	default:
+		return fmt.Sprintf("Unknown WipeState %d", state)
+	}
+}
+
+// WipeRequest - Published by zedagent, persistently, for each accepted
+// WipeCmd from the controller. nodeagent does the wipe as part of a
+// reboot and reports its progress in WipeStatus.
+type WipeRequest struct {
+	Counter uint32
+	Scope   WipeScope
+}
+
+// Key returns the key for pubsub
+func (req WipeRequest) Key() string {
+	return "global"
+}
+
+// LogCreate :
+func (req WipeRequest) LogCreate(logBase *base.LogObject) {
+	logObject := base.NewLogObject(logBase, base.WipeRequestLogType, "",
+		nilUUID, req.LogKey())
+	if logObject == nil {
+		return
+	}
+	logObject.CloneAndAddField("counter", req.Counter).
+		AddField("scope", req.Scope.String()).
+		Noticef("Wipe request create")
+}
+
+// LogModify :
+func (req WipeRequest) LogModify(logBase *base.LogObject, old interface{}) {
+	logObject := base.EnsureLogObject(logBase, base.WipeRequestLogType, "",
+		nilUUID, req.LogKey())
+
+	oldReq, ok := old.(WipeRequest)
+	if !ok {
+		logObject.Clone().Fatalf("LogModify: Old object interface passed is not of WipeRequest type")
+	}
+	logObject.CloneAndAddField("diff", cmp.Diff(oldReq, req)).
+		Noticef("Wipe request modify")
+}
+
+// LogDelete :
+func (req WipeRequest) LogDelete(logBase *base.LogObject) {
+	logObject := base.EnsureLogObject(logBase, base.WipeRequestLogType, "",
+		nilUUID, req.LogKey())
+	logObject.Noticef("Wipe request delete")
+
+	base.DeleteLogObject(logBase, req.LogKey())
+}
+
+// LogKey :
+func (req WipeRequest) LogKey() string {
+	return string(base.WipeRequestLogType) + "-" + req.Key()
+}
+
+// WipeStatus - Published by nodeagent with the progress of the
+// WipeRequest with the same Counter
+type WipeStatus struct {
+	Counter uint32
+	Scope   WipeScope
+	State   WipeState
+	Error   string
+}
+
+// Key returns the key for pubsub
+func (status WipeStatus) Key() string {
+	return "global"
+}
+
+// LogCreate :
+func (status WipeStatus) LogCreate(logBase *base.LogObject) {
+	logObject := base.NewLogObject(logBase, base.WipeStatusLogType, "",
+		nilUUID, status.LogKey())
+	if logObject == nil {
+		return
+	}
+	logObject.CloneAndAddField("counter", status.Counter).
+		AddField("state", status.State.String()).
+		Noticef("Wipe status create")
+}
+
+// LogModify :
+func (status WipeStatus) LogModify(logBase *base.LogObject, old interface{}) {
+	logObject := base.EnsureLogObject(logBase, base.WipeStatusLogType, "",
+		nilUUID, status.LogKey())
+
+	oldStatus, ok := old.(WipeStatus)
+	if !ok {
+		logObject.Clone().Fatalf("LogModify: Old object interface passed is not of WipeStatus type")
+	}
+	logObject.CloneAndAddField("diff", cmp.Diff(oldStatus, status)).
+		Noticef("Wipe status modify")
+}
+
+// LogDelete :
+func (status WipeStatus) LogDelete(logBase *base.LogObject) {
+	logObject := base.EnsureLogObject(logBase, base.WipeStatusLogType, "",
+		nilUUID, status.LogKey())
+	logObject.Noticef("Wipe status delete")
+
+	base.DeleteLogObject(logBase, status.LogKey())
+}
+
+// LogKey :
+func (status WipeStatus) LogKey() string {
+	return string(base.WipeStatusLogType) + "-" + status.Key()
+}
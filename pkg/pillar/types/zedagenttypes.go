@@ -25,6 +25,12 @@ type BaseOsConfig struct {
 	ContentTreeConfigList []ContentTreeConfig
 	RetryCount            int32
 	Activate              bool
+	// CanaryAppUUID if set is an app instance which must be healthy
+	// during the test of this base OS after the update
+	CanaryAppUUID uuid.UUID
+	// Any errors from the parser
+	// ErrorAndTime provides SetErrorNow() and ClearError()
+	ErrorAndTime
 }
 
 func (config BaseOsConfig) Key() string {
@@ -200,6 +206,16 @@ type DatastoreConfig struct {
 
 	// CipherBlockStatus, for encrypted credentials
 	CipherBlockStatus
+
+	// For DsContainerRegistryCache the registry behind the cache in
+	// Fqdn and Dpath, with its own encrypted credentials
+	UpstreamFqdn              string
+	UpstreamDpath             string
+	UpstreamCipherBlockStatus CipherBlockStatus
+
+	// Any errors from the parser. Not embedded since CipherBlockStatus
+	// embeds an ErrorAndTime, and the two would hide each other in JSON.
+	ConfigError ErrorAndTime
 }
 
 // Key is the key in pubsub
@@ -207,6 +223,32 @@ func (config DatastoreConfig) Key() string {
 	return config.UUID.String()
 }
 
+// DownloadURL returns the URL of the named content on the datastore.
+// For a registry cache this is the URL on the cache.
+func (config DatastoreConfig) DownloadURL(name string) string {
+	return datastoreURL(config.Fqdn, config.Dpath, name)
+}
+
+// UpstreamDownloadURL returns the URL of the named content on the registry
+// behind a registry cache, and "" for other datastores.
+func (config DatastoreConfig) UpstreamDownloadURL(name string) string {
+	if config.UpstreamFqdn == "" {
+		return ""
+	}
+	return datastoreURL(config.UpstreamFqdn, config.UpstreamDpath, name)
+}
+
+func datastoreURL(fqdn string, dpath string, name string) string {
+	url := fqdn
+	if len(dpath) > 0 {
+		url = url + "/" + dpath
+	}
+	if len(name) > 0 {
+		url = url + "/" + name
+	}
+	return url
+}
+
 // LogCreate :
 func (config DatastoreConfig) LogCreate(logBase *base.LogObject) {
 	logObject := base.NewLogObject(logBase, base.DatastoreConfigLogType, "",
@@ -478,6 +520,41 @@ const (
 	ConfigGetReadSaved
 )
 
+// CanaryVerdict : health of the canary app instance while a base OS
+// update is tested
+type CanaryVerdict uint8
+
+const (
+	// CanaryVerdictNone : no base OS update tested with a canary
+	CanaryVerdictNone CanaryVerdict = iota
+	// CanaryVerdictPending : the canary is not yet running
+	CanaryVerdictPending
+	// CanaryVerdictHealthy : the canary is running without errors
+	CanaryVerdictHealthy
+	// CanaryVerdictUnhealthy : the canary failed or stopped during the test
+	CanaryVerdictUnhealthy
+	// CanaryVerdictMissing : there is no status for the canary
+	CanaryVerdictMissing
+)
+
+// String returns the string name
+func (verdict CanaryVerdict) String() string {
+	switch verdict {
+	case CanaryVerdictNone:
+		return "none"
+	case CanaryVerdictPending:
+		return "pending"
+	case CanaryVerdictHealthy:
+		return "healthy"
+	case CanaryVerdictUnhealthy:
+		return "unhealthy"
+	case CanaryVerdictMissing:
+		return "missing"
+	default:
+		return fmt.Sprintf("Unknown CanaryVerdict %d", verdict)
+	}
+}
+
 // ZedAgentStatus :
 type ZedAgentStatus struct {
 	Name                 string
@@ -488,6 +565,29 @@ type ZedAgentStatus struct {
 	MaintenanceMode      bool       // Don't run apps etc
 	ForceFallbackCounter int        // Try image fallback when counter changes
 	CurrentProfile       string     // Current profile
+	// Config changes which only take effect after a reboot
+	RebootRequired        bool
+	RebootRequiredAspects []string
+	// Canary app instance of the base OS update being tested, if any
+	CanaryAppUUID uuid.UUID
+	CanaryVerdict CanaryVerdict
+	// Changes to the app instances and the base OS, or a reboot command,
+	// deferred by the quiet hours until ConfigDeferredUntil
+	ConfigDeferred      bool
+	ConfigDeferredUntil time.Time
+	// The most recent app instance restart requests from the local
+	// profile server, oldest first
+	LocalRestartAudit []LocalRestartAudit
+}
+
+// LocalRestartAudit records a restart request from the local profile server
+// and whether it was applied
+type LocalRestartAudit struct {
+	Time     time.Time
+	AppUUID  string
+	Counter  uint32
+	Accepted bool
+	Reason   string // Set if not accepted
 }
 
 // Key :
@@ -0,0 +1,28 @@
+// Copyright (c) 2021 Zededa, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The parser error and the cipher error of a DatastoreConfig must both
+// survive the JSON encoding used by pubsub
+func TestDatastoreConfigErrorsJSON(t *testing.T) {
+	config := DatastoreConfig{}
+	config.ConfigError.SetErrorNow("unsupported datastore type")
+	config.CipherBlockStatus.SetErrorNow("cipher context not found")
+	b, err := json.Marshal(config)
+	assert.Nil(t, err)
+	var decoded DatastoreConfig
+	assert.Nil(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, "unsupported datastore type", decoded.ConfigError.Error)
+	assert.Equal(t, "cipher context not found",
+		decoded.CipherBlockStatus.Error)
+	assert.True(t, decoded.ConfigError.ErrorTime.Equal(
+		config.ConfigError.ErrorTime))
+}
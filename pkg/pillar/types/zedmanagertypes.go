@@ -39,6 +39,93 @@ type UUIDandVersion struct {
 	Version string
 }
 
+// AppConfigErrorCategory is the kind of error found when parsing the
+// config of an app instance
+type AppConfigErrorCategory uint8
+
+const (
+	AppConfigErrorOther             AppConfigErrorCategory = iota
+	AppConfigErrorMissingNetwork                           // Network instance not found
+	AppConfigErrorBadNetworkUUID                           // Malformed network instance UUID
+	AppConfigErrorBadMAC                                   // Malformed MAC address
+	AppConfigErrorBadIP                                    // Malformed or unsupported IP address
+	AppConfigErrorBadHostname                              // Invalid or duplicate hostname
+	AppConfigErrorBadVnc                                   // Invalid VNC settings
+	AppConfigErrorTooLarge                                 // Config too large to publish
+	AppConfigErrorIdentityChange                           // UUID likely reused for a different app
+	AppConfigErrorBadACL                                   // Invalid ACL
+	AppConfigErrorNetworkQuota                             // Too many app interfaces on the network instance
+	AppConfigErrorTooManyApps                              // Beyond the maximum number of app instances
+	AppConfigErrorAcceleratorMemory                        // Accelerator memory beyond the adapter capacity
+	AppConfigErrorAffinity                                 // Affinity policy which cannot be satisfied
+)
+
+// String returns the name of the AppConfigErrorCategory
+func (category AppConfigErrorCategory) String() string {
+	switch category {
+	case AppConfigErrorOther:
+		return "other"
+	case AppConfigErrorMissingNetwork:
+		return "missing network"
+	case AppConfigErrorBadNetworkUUID:
+		return "bad network UUID"
+	case AppConfigErrorBadMAC:
+		return "bad MAC"
+	case AppConfigErrorBadIP:
+		return "bad IP"
+	case AppConfigErrorBadHostname:
+		return "bad hostname"
+	case AppConfigErrorBadVnc:
+		return "bad VNC"
+	case AppConfigErrorTooLarge:
+		return "too large"
+	case AppConfigErrorIdentityChange:
+		return "identity change"
+	case AppConfigErrorBadACL:
+		return "bad ACL"
+	case AppConfigErrorNetworkQuota:
+		return "network quota"
+	case AppConfigErrorTooManyApps:
+		return "too many apps"
+	case AppConfigErrorAcceleratorMemory:
+		return "accelerator memory"
+	case AppConfigErrorAffinity:
+		return "affinity"
+	default:
+		return fmt.Sprintf("unknown AppConfigErrorCategory %d", category)
+	}
+}
+
+// AppConfigError is an error found when parsing the config of an app
+// instance. IntfName is set if the error is for one of its interfaces.
+type AppConfigError struct {
+	IntfName  string
+	Category  AppConfigErrorCategory
+	Error     string
+	ErrorTime time.Time
+}
+
+// NewAppConfigError returns an AppConfigError with the current time
+func NewAppConfigError(intfName string, category AppConfigErrorCategory,
+	errStr string) AppConfigError {
+	return AppConfigError{
+		IntfName:  intfName,
+		Category:  category,
+		Error:     errStr,
+		ErrorTime: time.Now(),
+	}
+}
+
+// ErrorStrings returns the Errors as strings for the consumers which do not
+// care about the interface and category
+func (config AppInstanceConfig) ErrorStrings() []string {
+	var errStrs []string
+	for _, err := range config.Errors {
+		errStrs = append(errStrs, err.Error)
+	}
+	return errStrs
+}
+
 // This is what we assume will come from the ZedControl for each
 // application instance. Note that we can have different versions
 // configured for the same UUID, hence the key is the UUIDandVersion
@@ -52,11 +139,12 @@ type AppInstanceConfig struct {
 	// Error
 	//	If this is set, do not process further.. Just set the status to error
 	//	so the cloud gets it.
-	Errors              []string
+	Errors              []AppConfigError
 	FixedResources      VmConfig // CPU etc
 	VolumeRefConfigList []VolumeRefConfig
 	Activate            bool //EffectiveActivate in AppInstanceStatus must be used for the actual activation
 	UnderlayNetworkList []UnderlayNetworkConfig
+	NoNetworking        bool // Configured without any interfaces
 	IoAdapterList       []IoAdapter
 	RestartCmd          AppInstanceOpsCmd
 	PurgeCmd            AppInstanceOpsCmd
@@ -72,17 +160,83 @@ type AppInstanceConfig struct {
 	MetaDataType MetaDataType
 
 	ProfileList []string
+
+	// NeedsPurgeForChanges is set when fields which only take effect
+	// after a purge (e.g., the cloud-init user data) have changed but
+	// PurgeCmd.Counter has not. PendingPurgeChanges lists those fields.
+	NeedsPurgeForChanges bool
+	PendingPurgeChanges  []string
+
+	// HeldForBaseOsTesting is set when Activate was cleared since a new
+	// base OS is being tested. Activated once the testing completes.
+	HeldForBaseOsTesting bool
+
+	// AllowLocalRestart permits the local profile server to restart the
+	// app instance. LocalRestartCmd is bumped by zedagent for such requests.
+	AllowLocalRestart bool
+	LocalRestartCmd   AppInstanceOpsCmd
+
+	// VolumeRetentionDays is the number of days the volumes are retained
+	// after the app instance is removed from the config. Zero means they
+	// are deleted immediately.
+	VolumeRetentionDays uint32
+
+	// LogPolicy filters the logs of the app instance
+	LogPolicy AppLogPolicy
+
+	// OverlayCompatWarnings lists the overlay (LISP) fields of the app
+	// interfaces which were dropped to run them on a network instance
+	// which is not mesh
+	OverlayCompatWarnings []string
+
+	// AffinityPolicy is the placement on the NUMA node of the adapters.
+	// The resolved node is in FixedResources.
+	AffinityPolicy AffinityPolicy
+}
+
+// AffinityPolicy places an app instance on the NUMA node local to its
+// adapters; must match the values in the proto definition
+type AffinityPolicy uint8
+
+const (
+	AffinityPolicyNone AffinityPolicy = iota // Default
+	// AffinityPolicyPreferAdapterNuma uses the NUMA node of the adapters
+	// if known, and any node otherwise
+	AffinityPolicyPreferAdapterNuma
+	// AffinityPolicyRequireAdapterNuma fails unless the adapters are on
+	// the same known NUMA node
+	AffinityPolicyRequireAdapterNuma
+)
+
+// String returns the name of the AffinityPolicy
+func (policy AffinityPolicy) String() string {
+	switch policy {
+	case AffinityPolicyNone:
+		return "none"
+	case AffinityPolicyPreferAdapterNuma:
+		return "prefer-adapter-numa"
+	case AffinityPolicyRequireAdapterNuma:
+		return "require-adapter-numa"
+	default:
+		return fmt.Sprintf("unknown AffinityPolicy %d", policy)
+	}
 }
 
 type AppInstanceOpsCmd struct {
 	Counter   uint32
 	ApplyTime string // XXX not currently used
+	// ApplyTimeWarning is set when the ApplyTime is implausibly far in
+	// the future, e.g., due to clock skew on the controller
+	ApplyTimeWarning string
 }
 
 // IoAdapter specifies that a group of ports should be assigned
 type IoAdapter struct {
 	Type IoType
 	Name string // Short hand name such as "COM1" or "eth1-2"
+	// AcceleratorMemoryMB is the accelerator memory reserved on the
+	// adapter, e.g., of a GPU
+	AcceleratorMemoryMB uint32
 }
 
 // LogCreate :
@@ -141,6 +295,18 @@ func (config AppInstanceConfig) Key() string {
 	return config.UUIDandVersion.UUID.String()
 }
 
+// OpsCmdWarnings returns the warnings about the restart and purge commands
+// which are reported to the controller
+func (config AppInstanceConfig) OpsCmdWarnings() []string {
+	var warnings []string
+	for _, opsCmd := range []AppInstanceOpsCmd{config.RestartCmd, config.PurgeCmd} {
+		if opsCmd.ApplyTimeWarning != "" {
+			warnings = append(warnings, opsCmd.ApplyTimeWarning)
+		}
+	}
+	return warnings
+}
+
 // Indexed by UUIDandVersion as above
 type AppInstanceStatus struct {
 	UUIDandVersion      UUIDandVersion
@@ -151,9 +317,12 @@ type AppInstanceStatus struct {
 	FixedResources      VmConfig // CPU etc
 	VolumeRefStatusList []VolumeRefStatus
 	UnderlayNetworks    []UnderlayNetworkStatus
+	NoNetworking        bool     // Configured without any interfaces
+	OpsCmdWarnings      []string // From AppInstanceConfig.OpsCmdWarnings
 	BootTime            time.Time
 	IoAdapterList       []IoAdapter // Report what was actually used
 	RestartInprogress   Inprogress
+	RestartedLocally    bool // Last restart was due to LocalRestartCmd
 	PurgeInprogress     Inprogress
 
 	// Mininum state across all steps and all StorageStatus.
@@ -4,12 +4,14 @@
 package types
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"net"
 	"os"
 	"reflect"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/eriknordmark/ipinfo"
@@ -737,7 +739,8 @@ func (config *DevicePortConfig) MostlyEqual(config2 *DevicePortConfig) bool {
 			p1.Cost != p2.Cost {
 			return false
 		}
-		if !reflect.DeepEqual(p1.DhcpConfig, p2.DhcpConfig) ||
+		if !reflect.DeepEqual(p1.SharedLabels, p2.SharedLabels) ||
+			!reflect.DeepEqual(p1.DhcpConfig, p2.DhcpConfig) ||
 			!reflect.DeepEqual(p1.ProxyConfig, p2.ProxyConfig) ||
 			!reflect.DeepEqual(p1.WirelessCfg, p2.WirelessCfg) {
 			return false
@@ -830,6 +833,7 @@ const (
 	KeySchemeWpaPsk
 	KeySchemeWpaEap
 	KeySchemeOther
+	KeySchemeWpa3Sae
 )
 
 // WirelessType - types of wireless media
@@ -846,12 +850,88 @@ type ProxyEntry struct {
 	Type   NetworkProxyType
 	Server string
 	Port   uint32
+	// Username for basic authentication with the proxy
+	Username string
+	// CipherBlockStatus has the encrypted password for the Username. It
+	// is only decrypted when the proxy is used.
+	CipherBlockStatus CipherBlockStatus
+}
+
+// ProxyExceptionType is the kind of a proxy exception
+type ProxyExceptionType uint8
+
+const (
+	// ProxyExceptionAll matches all hosts ("*")
+	ProxyExceptionAll ProxyExceptionType = iota + 1
+	// ProxyExceptionHostSuffix matches the hosts in a domain
+	// (".example.com" or "*.example.com") but not the domain itself
+	ProxyExceptionHostSuffix
+	// ProxyExceptionHost matches a host name and its subdomains, as
+	// a host name in NO_PROXY
+	ProxyExceptionHost
+	// ProxyExceptionIP matches one IP address
+	ProxyExceptionIP
+	// ProxyExceptionCIDR matches the IP addresses in a subnet
+	ProxyExceptionCIDR
+)
+
+// ProxyPolicy is the order in which the proxies of a port are used
+type ProxyPolicy uint8
+
+const (
+	// ProxyPolicyDefault uses the PAC file if any, otherwise WPAD if
+	// enabled, otherwise the explicit proxies
+	ProxyPolicyDefault ProxyPolicy = iota
+	// ProxyPolicyStaticOnly uses only the explicit proxies
+	ProxyPolicyStaticOnly
+	// ProxyPolicyPacOnly uses only the PAC file, which is either
+	// configured or downloaded from NetworkProxyURL
+	ProxyPolicyPacOnly
+	// ProxyPolicyPacThenStatic uses the PAC file, and the explicit
+	// proxies when the PAC file can not be evaluated
+	ProxyPolicyPacThenStatic
+	// ProxyPolicyWpad uses the PAC file discovered by WPAD
+	ProxyPolicyWpad
+)
+
+func (policy ProxyPolicy) String() string {
+	switch policy {
+	case ProxyPolicyDefault:
+		return "default"
+	case ProxyPolicyStaticOnly:
+		return "static-only"
+	case ProxyPolicyPacOnly:
+		return "pac-only"
+	case ProxyPolicyPacThenStatic:
+		return "pac-then-static"
+	case ProxyPolicyWpad:
+		return "wpad"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(policy))
+	}
+}
+
+// ProxyException is a validated entry of ProxyConfig.Exceptions
+type ProxyException struct {
+	Type ProxyExceptionType
+	// Value is in lower case without a trailing dot for host names, with
+	// the leading dot for suffixes, and in the canonical form for IP
+	// addresses and subnets
+	Value string
+	// Port restricts the match to the port if not zero
+	Port uint16
 }
 
 type ProxyConfig struct {
-	Proxies    []ProxyEntry
+	// Policy is set by the controller and says which of the below are used
+	Policy  ProxyPolicy
+	Proxies []ProxyEntry
+	// Exceptions is the comma or space separated list from the controller
 	Exceptions string
-	Pacfile    string
+	// ExceptionList has the parsed Exceptions. Empty in a DevicePortConfig
+	// saved by an older version, in which case Exceptions is used.
+	ExceptionList []ProxyException
+	Pacfile       string
 	// If Enable is set we use WPAD. If the URL is not set we try
 	// the various DNS suffixes until we can download a wpad.dat file
 	NetworkProxyEnable bool     // Enable WPAD
@@ -860,13 +940,63 @@ type ProxyConfig struct {
 	ProxyCertPEM       [][]byte // List of certs which will be added to TLS trust
 }
 
+// MatchesException returns true if the host and port match an entry of
+// ExceptionList, hence are reached without a proxy
+func (config ProxyConfig) MatchesException(host string, port uint16) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	ip := net.ParseIP(host)
+	for _, exception := range config.ExceptionList {
+		if exception.Port != 0 && exception.Port != port {
+			continue
+		}
+		switch exception.Type {
+		case ProxyExceptionAll:
+			return true
+		case ProxyExceptionHostSuffix:
+			if strings.HasSuffix(host, exception.Value) {
+				return true
+			}
+		case ProxyExceptionHost:
+			if host == exception.Value ||
+				strings.HasSuffix(host, "."+exception.Value) {
+				return true
+			}
+		case ProxyExceptionIP:
+			if ip != nil && ip.Equal(net.ParseIP(exception.Value)) {
+				return true
+			}
+		case ProxyExceptionCIDR:
+			_, subnet, err := net.ParseCIDR(exception.Value)
+			if err == nil && ip != nil && subnet.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 type DhcpConfig struct {
-	Dhcp       DhcpType // If DT_STATIC use below; if DT_NONE do nothing
-	AddrSubnet string   // In CIDR e.g., 192.168.1.44/24
+	Dhcp       DhcpType     // If DT_STATIC use below; if DT_NONE do nothing
+	AddrMode   AddrModeType // IPv6 with DT_CLIENT only
+	AddrSubnet string       // In CIDR e.g., 192.168.1.44/24
 	Gateway    net.IP
-	DomainName string
-	NtpServer  net.IP
-	DnsServers []net.IP // If not set we use Gateway as DNS server
+	DomainName string // First entry in DomainNames
+	// DNS search domains
+	DomainNames []string
+	NtpServer   net.IP
+	DnsServers  []net.IP // If not set we use Gateway as DNS server
+	// AddrSubnets has all the static addresses in CIDR, at most one per
+	// IP version, and AddrSubnet is the first one. Empty in a
+	// DevicePortConfig saved by an older version.
+	AddrSubnets []string
+}
+
+// StaticAddrSubnets returns all the static addresses in CIDR
+func (config DhcpConfig) StaticAddrSubnets() []string {
+	if len(config.AddrSubnets) == 0 && config.AddrSubnet != "" {
+		return []string{config.AddrSubnet}
+	}
+	return config.AddrSubnets
 }
 
 // WifiConfig - Wifi structure
@@ -885,9 +1015,25 @@ type WifiConfig struct {
 	CipherBlockStatus
 }
 
+// CellularAuthProtocol - authentication with the APN
+type CellularAuthProtocol uint8
+
+// enum cellular authentication protocol
+const (
+	CellularAuthNone CellularAuthProtocol = iota // no authentication
+	CellularAuthPAP
+	CellularAuthCHAP
+	CellularAuthPAPAndCHAP
+)
+
 // CellConfig - Cellular part of the configure
 type CellConfig struct {
 	APN string // LTE APN
+	// Username for the authentication with the APN
+	Username     string
+	AuthProtocol CellularAuthProtocol
+	// CipherBlockStatus has the encrypted password for the Username
+	CipherBlockStatus CipherBlockStatus
 }
 
 // WirelessConfig - wireless structure
@@ -895,6 +1041,8 @@ type WirelessConfig struct {
 	WType    WirelessType // Wireless Type
 	Cellular []CellConfig // LTE APN
 	Wifi     []WifiConfig // Wifi Config params
+	// ErrorAndTime is set if some of the config is not supported
+	ErrorAndTime
 }
 
 const (
@@ -904,6 +1052,21 @@ const (
 	PortCostMax = uint8(255)
 )
 
+const (
+	// UplinkLabel is the shared label of the management ports
+	UplinkLabel = "uplink"
+	// FreeUplinkLabel is the shared label of the management ports with
+	// a zero cost
+	FreeUplinkLabel = "freeuplink"
+)
+
+// IsReservedSharedLabel returns true for the shared labels which are
+// implicitly added to the ports and can not be set by the controller
+func IsReservedSharedLabel(label string) bool {
+	return strings.EqualFold(label, UplinkLabel) ||
+		strings.EqualFold(label, FreeUplinkLabel)
+}
+
 // NetworkPortConfig has the configuration and some status like TestResults
 // for one IfName.
 // XXX odd to have ParseErrors and/or TestResults here but we don't have
@@ -918,6 +1081,9 @@ type NetworkPortConfig struct {
 	NetworkUUID uuid.UUID
 	IsMgmt      bool  // Used to talk to controller
 	Cost        uint8 // Zero is free
+	// SharedLabels - From SystemAdapter's sharedLabels plus the implicit
+	// UplinkLabel and FreeUplinkLabel
+	SharedLabels []string
 	DhcpConfig
 	ProxyConfig
 	WirelessCfg WirelessConfig
@@ -1731,6 +1897,28 @@ const (
 	MST_LAST = 255
 )
 
+// MapServer is a LISP map server of a mesh network instance
+type MapServer struct {
+	ServiceType MapServerType
+	NameOrIp    string
+
+	// XXX: to be deprecated, use CipherBlockStatus instead
+	Credential string
+
+	// CipherBlockStatus, for the encrypted credential
+	CipherBlockStatus
+}
+
+// String hides the Credential when logging
+func (ms MapServer) String() string {
+	credential := ""
+	if ms.Credential != "" {
+		credential = "<redacted>"
+	}
+	return fmt.Sprintf("{ServiceType:%d NameOrIp:%s Credential:%s IsCipher:%t}",
+		ms.ServiceType, ms.NameOrIp, credential, ms.CipherBlockStatus.IsCipher)
+}
+
 // CurrIntfStatusType - enum for probe current uplink intf UP/Down status
 type CurrIntfStatusType uint8
 
@@ -1790,6 +1978,33 @@ const (
 	DT_CLIENT              // Device client on external port
 )
 
+// AddrModeType is how an IPv6 port with DT_CLIENT gets its address.
+// Values match the IPv6AddrMode in the proto.
+type AddrModeType uint8
+
+const (
+	AM_UNSPECIFIED      AddrModeType = iota // Follow router advertisements
+	AM_SLAAC                                // SLAAC only
+	AM_DHCPV6_STATEFUL                      // Address from DHCPv6
+	AM_DHCPV6_STATELESS                     // SLAAC address, DHCPv6 for the rest
+)
+
+// String returns the name of the AddrModeType
+func (mode AddrModeType) String() string {
+	switch mode {
+	case AM_UNSPECIFIED:
+		return "unspecified"
+	case AM_SLAAC:
+		return "SLAAC"
+	case AM_DHCPV6_STATEFUL:
+		return "stateful DHCPv6"
+	case AM_DHCPV6_STATELESS:
+		return "stateless DHCPv6"
+	default:
+		return fmt.Sprintf("unknown AddrModeType %d", mode)
+	}
+}
+
 type UnderlayNetworkConfig struct {
 	Name       string           // From proto message
 	AppMacAddr net.HardwareAddr // If set use it for vif
@@ -1808,6 +2023,11 @@ type UnderlayNetworkConfig struct {
 	Network      uuid.UUID // Points to a NetworkInstance.
 	ACLs         []ACE
 	AccessVlanID uint32
+	// Hostnames are extra DNS names resolving to the address of the app
+	// on this interface
+	Hostnames []string
+	// ErrorCategory is the kind of Error
+	ErrorCategory AppConfigErrorCategory
 }
 
 type UnderlayNetworkStatus struct {
@@ -1852,16 +2072,21 @@ const (
 type NetworkXObjectConfig struct {
 	UUID            uuid.UUID
 	Type            NetworkType
-	Dhcp            DhcpType // If DT_STATIC or DT_CLIENT use below
+	Dhcp            DhcpType     // If DT_STATIC or DT_CLIENT use below
+	AddrMode        AddrModeType // IPv6 with DT_CLIENT only
 	Subnet          net.IPNet
+	ExtraSubnets    []net.IPNet // Other IP versions for static port addresses
 	Gateway         net.IP
-	DomainName      string
+	DomainName      string   // First entry in DomainNames
+	DomainNames     []string // DNS search domains
 	NtpServer       net.IP
 	DnsServers      []net.IP // If not set we use Gateway as DNS server
 	DhcpRange       IpRange
 	DnsNameToIPList []DnsNameToIP // Used for DNS and ACL ipset
-	Proxy           *ProxyConfig
-	WirelessCfg     WirelessConfig
+	// Host names which appeared more than once and were merged
+	DnsNameToIPWarnings []string
+	Proxy               *ProxyConfig
+	WirelessCfg         WirelessConfig
 	// Any errrors from the parser
 	// ErrorAndTime provides SetErrorNow() and ClearError()
 	ErrorAndTime
@@ -1872,6 +2097,35 @@ type IpRange struct {
 	End   net.IP
 }
 
+// DhcpReservation reserves IP for the app interface with Mac. Hostname is
+// optional.
+type DhcpReservation struct {
+	Mac      net.HardwareAddr
+	IP       net.IP
+	Hostname string
+}
+
+// IPRoute is a static route to DstNetwork through Gateway
+type IPRoute struct {
+	DstNetwork net.IPNet
+	Gateway    net.IP
+}
+
+// Contains used to evaluate whether an IP address
+// is within the range. An empty End means a single address range.
+func (ipRange IpRange) Contains(ipAddr net.IP) bool {
+	if ipRange.Start == nil || ipAddr == nil {
+		return false
+	}
+	end := ipRange.End
+	if end == nil {
+		end = ipRange.Start
+	}
+	ipAddr = ipAddr.To16()
+	return bytes.Compare(ipAddr, ipRange.Start.To16()) >= 0 &&
+		bytes.Compare(ipAddr, end.To16()) <= 0
+}
+
 func (config NetworkXObjectConfig) Key() string {
 	return config.UUID.String()
 }
@@ -2201,16 +2455,74 @@ type NetworkInstanceConfig struct {
 	// Logicallabel - name specified in the Device Config.
 	// Can be a specific logicallabel for an interface, or a tag like "uplink"
 	Logicallabel string
+	// Logicallabels has all ports of a switch network instance spanning
+	// several ports; Logicallabel is the first one
+	Logicallabels []string
 
 	// IP configuration for the Application
 	IpType          AddressType
 	Subnet          net.IPNet
 	Gateway         net.IP
-	DomainName      string
+	DomainName      string   // First entry in DomainNames
+	DomainNames     []string // DNS search domains
 	NtpServer       net.IP
 	DnsServers      []net.IP // If not set we use Gateway as DNS server
 	DhcpRange       IpRange
 	DnsNameToIPList []DnsNameToIP // Used for DNS and ACL ipset
+	// Host names which appeared more than once and were merged, and the
+	// bad addresses which were ignored. Reported to the controller.
+	DnsNameToIPWarnings []string
+	StaticRoutes        []IPRoute
+	// UpstreamDnsServers if set are used by our DNS service instead of
+	// the DNS servers of the uplink
+	UpstreamDnsServers []net.IP
+	// Set when DnsServers or UpstreamDnsServers were truncated to the
+	// maximum. Reported to the controller.
+	DnsServerWarnings []string
+	// Invalid static routes are left out of StaticRoutes and reported to
+	// the controller in StaticRouteErrors
+	StaticRouteErrors []string
+	// DHCP reservations, turned into dhcp-host entries by zedrouter.
+	// Invalid reservations are left out and reported to the controller
+	// in ReservationErrors
+	Reservations      []DhcpReservation
+	ReservationErrors []string
+
+	// Mtu for the network instance; zero means the default
+	Mtu uint32
+
+	// UplinkRateKbps limits the aggregate traffic of the network instance
+	// on its uplink; zero means no limit. BurstKB is the burst size for
+	// the limit; zero means the default.
+	UplinkRateKbps uint32
+	BurstKB        uint32
+	// Set when the network instances sharing the uplink exceed its
+	// configured capacity. Not an error.
+	UplinkRateWarning string
+
+	// Vlan is the tagged VLAN of the port onto which a switch network
+	// instance bridges the apps; zero means untagged
+	Vlan uint16
+
+	// PassiveIpType is the address type a switch network instance is
+	// meant to observe. Only reported; IpType stays AddressTypeNone
+	PassiveIpType AddressType
+
+	// DscpMark is set when the traffic of the apps is marked with Dscp by
+	// default. See ACE.DscpMark for the precedence.
+	DscpMark bool
+	Dscp     uint8
+
+	// MaxVifs is the maximum number of app interfaces on the network
+	// instance; zero means unlimited. Enforced when parsing the apps.
+	MaxVifs uint32
+
+	// SuppressNtp is set when the NTP servers of the device are not
+	// handed out to the apps. See DhcpNtpServers.
+	SuppressNtp bool
+
+	// MapServers of a mesh network instance
+	MapServers []MapServer
 
 	// For other network services - Proxy / StrongSwan etc..
 	OpaqueConfig string
@@ -2224,6 +2536,19 @@ func (config *NetworkInstanceConfig) Key() string {
 	return config.UUID.String()
 }
 
+// DhcpNtpServers returns the NTP servers handed out to the apps with DHCP.
+// An explicit NtpServer takes precedence, even with SuppressNtp set.
+// Otherwise the NTP servers of the device are handed out unless suppressed.
+func (config *NetworkInstanceConfig) DhcpNtpServers(deviceNtpServers []net.IP) []net.IP {
+	if config.NtpServer != nil {
+		return []net.IP{config.NtpServer}
+	}
+	if config.SuppressNtp {
+		return nil
+	}
+	return deviceNtpServers
+}
+
 // LogCreate :
 func (config NetworkInstanceConfig) LogCreate(logBase *base.LogObject) {
 	logObject := base.NewLogObject(logBase, base.NetworkInstanceConfigLogType, "",
@@ -2244,10 +2569,28 @@ func (config NetworkInstanceConfig) LogModify(logBase *base.LogObject, old inter
 		logObject.Clone().Fatalf("LogModify: Old object interface passed is not of NetworkInstanceConfig type")
 	}
 	// XXX remove?
-	logObject.CloneAndAddField("diff", cmp.Diff(oldConfig, config)).
+	logObject.CloneAndAddField("diff",
+		cmp.Diff(oldConfig.withoutSecrets(), config.withoutSecrets())).
 		Noticef("Network instance config modify")
 }
 
+// withoutSecrets returns a copy of the config for logging, with the
+// clear text credentials redacted
+func (config NetworkInstanceConfig) withoutSecrets() NetworkInstanceConfig {
+	if len(config.MapServers) == 0 {
+		return config
+	}
+	mapServers := make([]MapServer, len(config.MapServers))
+	for i, ms := range config.MapServers {
+		if ms.Credential != "" {
+			ms.Credential = "<redacted>"
+		}
+		mapServers[i] = ms
+	}
+	config.MapServers = mapServers
+	return config
+}
+
 // LogDelete :
 func (config NetworkInstanceConfig) LogDelete(logBase *base.LogObject) {
 	logObject := base.EnsureLogObject(logBase, base.NetworkInstanceConfigLogType, "",
@@ -2503,16 +2846,52 @@ type ACEMatch struct {
 	Value string
 }
 
+// aceMatchTypes are the Types of ACEMatch which zedrouter handles
+var aceMatchTypes = map[string]bool{
+	"ip":       true,
+	"host":     true,
+	"eidset":   true,
+	"protocol": true,
+	"fport":    true,
+	"lport":    true,
+}
+
+// IsKnownType returns true if zedrouter handles the Type of the match
+func (match ACEMatch) IsKnownType() bool {
+	return aceMatchTypes[match.Type]
+}
+
 type ACEAction struct {
 	Drop bool // Otherwise accept
 
 	Limit      bool   // Is limiter enabled?
 	LimitRate  int    // Packets per unit
-	LimitUnit  string // "s", "m", "h", for second, minute, hour
+	LimitUnit  string // "s", "m", "h", "d", for second, minute, hour, day
 	LimitBurst int    // Packets
 
 	PortMap    bool // Is port mapping part of action?
 	TargetPort int  // Internal port
+
+	DscpMark bool  // Is DSCP marking part of action?
+	Dscp     uint8 // 0-63
+}
+
+// MaxDscp is the largest DSCP value
+const MaxDscp = 63
+
+// DscpMark returns the DSCP mark for the traffic matching the ACE, if any.
+// A mark set by an action of the ACE takes precedence over the default mark
+// of the network instance.
+func (ace ACE) DscpMark(niConfig NetworkInstanceConfig) (uint8, bool) {
+	for _, action := range ace.Actions {
+		if action.DscpMark {
+			return action.Dscp, true
+		}
+	}
+	if niConfig.DscpMark {
+		return niConfig.Dscp, true
+	}
+	return 0, false
 }
 
 // Retrieved from geolocation service for device underlay connectivity
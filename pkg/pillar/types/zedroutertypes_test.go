@@ -1380,3 +1380,152 @@ func TestGetLocalAddrList(t *testing.T) {
 		}
 	}
 }
+
+func TestProxyConfigMatchesException(t *testing.T) {
+	proxyConfig := ProxyConfig{
+		ExceptionList: []ProxyException{
+			{Type: ProxyExceptionHost, Value: "host.example.com"},
+			{Type: ProxyExceptionHostSuffix, Value: ".example.org"},
+			{Type: ProxyExceptionIP, Value: "10.1.2.3", Port: 443},
+			{Type: ProxyExceptionCIDR, Value: "2001:db8::/32"},
+		},
+	}
+	testMatrix := map[string]struct {
+		host          string
+		port          uint16
+		expectedMatch bool
+	}{
+		"Exact host": {
+			host:          "host.example.com",
+			port:          443,
+			expectedMatch: true,
+		},
+		"Exact host with trailing dot and upper case": {
+			host:          "HOST.example.com.",
+			port:          443,
+			expectedMatch: true,
+		},
+		"Subdomain of host": {
+			host:          "a.host.example.com",
+			port:          443,
+			expectedMatch: true,
+		},
+		"Other host ending with the host": {
+			host: "otherhost.example.com",
+			port: 443,
+		},
+		"Host in suffix": {
+			host:          "a.b.example.org",
+			port:          80,
+			expectedMatch: true,
+		},
+		"Suffix domain itself": {
+			host: "example.org",
+			port: 80,
+		},
+		"IP with port": {
+			host:          "10.1.2.3",
+			port:          443,
+			expectedMatch: true,
+		},
+		"IP with other port": {
+			host: "10.1.2.3",
+			port: 80,
+		},
+		"IPv6 in CIDR": {
+			host:          "2001:db8:1::5",
+			port:          443,
+			expectedMatch: true,
+		},
+		"IPv6 outside CIDR": {
+			host: "2001:db9::5",
+			port: 443,
+		},
+	}
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		assert.Equal(t, test.expectedMatch,
+			proxyConfig.MatchesException(test.host, test.port), testname)
+	}
+	all := ProxyConfig{ExceptionList: []ProxyException{
+		{Type: ProxyExceptionAll}}}
+	assert.True(t, all.MatchesException("any.example.com", 443))
+}
+
+func TestACEDscpMark(t *testing.T) {
+	testMatrix := map[string]struct {
+		actions       []ACEAction
+		niConfig      NetworkInstanceConfig
+		expectedMark  bool
+		expectedValue uint8
+	}{
+		"No mark": {
+			actions: []ACEAction{{Limit: true}},
+		},
+		"Network instance default": {
+			actions:       []ACEAction{{Limit: true}},
+			niConfig:      NetworkInstanceConfig{DscpMark: true, Dscp: 10},
+			expectedMark:  true,
+			expectedValue: 10,
+		},
+		"ACE mark": {
+			actions:       []ACEAction{{DscpMark: true, Dscp: 46}},
+			expectedMark:  true,
+			expectedValue: 46,
+		},
+		"ACE mark overrides default": {
+			actions:       []ACEAction{{DscpMark: true, Dscp: 46}},
+			niConfig:      NetworkInstanceConfig{DscpMark: true, Dscp: 10},
+			expectedMark:  true,
+			expectedValue: 46,
+		},
+		"ACE mark of zero overrides default": {
+			actions:       []ACEAction{{DscpMark: true}},
+			niConfig:      NetworkInstanceConfig{DscpMark: true, Dscp: 10},
+			expectedMark:  true,
+			expectedValue: 0,
+		},
+	}
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		ace := ACE{Actions: test.actions}
+		value, mark := ace.DscpMark(test.niConfig)
+		assert.Equal(t, test.expectedMark, mark, testname)
+		assert.Equal(t, test.expectedValue, value, testname)
+	}
+}
+
+func TestDhcpNtpServers(t *testing.T) {
+	explicit := net.ParseIP("10.1.0.2")
+	device := []net.IP{net.ParseIP("192.168.1.10"), net.ParseIP("192.168.1.11")}
+	testMatrix := map[string]struct {
+		ntpServer   net.IP
+		suppressNtp bool
+		expected    []net.IP
+	}{
+		"Device servers passed through": {
+			expected: device,
+		},
+		"Device servers suppressed": {
+			suppressNtp: true,
+		},
+		"Explicit server": {
+			ntpServer: explicit,
+			expected:  []net.IP{explicit},
+		},
+		"Explicit server with device servers suppressed": {
+			ntpServer:   explicit,
+			suppressNtp: true,
+			expected:    []net.IP{explicit},
+		},
+	}
+	for testname, test := range testMatrix {
+		t.Logf("Running test case %s", testname)
+		config := NetworkInstanceConfig{
+			NtpServer:   test.ntpServer,
+			SuppressNtp: test.suppressNtp,
+		}
+		assert.Equal(t, test.expected, config.DhcpNtpServers(device),
+			testname)
+	}
+}
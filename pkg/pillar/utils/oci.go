@@ -16,8 +16,9 @@ func MaybeInsertSha(name string, sha string) string {
 		return name
 	}
 	sha = strings.ToLower(sha)
+	// A ":" before the last "/" separates the port of the registry host
 	last := strings.LastIndex(name, ":")
-	if last == -1 {
+	if last == -1 || last < strings.LastIndex(name, "/") {
 		return name + "@sha256:" + sha
 	}
 	return name[:last] + "@sha256:" + sha
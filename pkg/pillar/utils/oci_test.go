@@ -28,6 +28,26 @@ func TestMaybeInsertSha(t *testing.T) {
 			imageSHA:          "de78803598bc4c940fc4591d412bffe488205d5d953f94751c6308deeaaa7eb8",
 			expectedImageName: "alpine@sha256:de78803598bc4c940fc4591d412bffe488205d5d953f94751c6308deeaaa7eb8",
 		},
+		"Port and tag in image name": {
+			imageName:         "registry.example.com:5000/foo/bar:1.2",
+			imageSHA:          "de78803598bc4c940fc4591d412bffe488205d5d953f94751c6308deeaaa7eb8",
+			expectedImageName: "registry.example.com:5000/foo/bar@sha256:de78803598bc4c940fc4591d412bffe488205d5d953f94751c6308deeaaa7eb8",
+		},
+		"Port and no tag in image name": {
+			imageName:         "registry.example.com:5000/foo/bar",
+			imageSHA:          "de78803598bc4c940fc4591d412bffe488205d5d953f94751c6308deeaaa7eb8",
+			expectedImageName: "registry.example.com:5000/foo/bar@sha256:de78803598bc4c940fc4591d412bffe488205d5d953f94751c6308deeaaa7eb8",
+		},
+		"Sha already in image name": {
+			imageName:         "registry.example.com:5000/foo/bar@sha256:4ff33d2d5a1b1d0a3c3b4b59a6e3fbb23b0c1cbd7b4e1a0b8e1e1a4a2c9a7f10",
+			imageSHA:          "de78803598bc4c940fc4591d412bffe488205d5d953f94751c6308deeaaa7eb8",
+			expectedImageName: "registry.example.com:5000/foo/bar@sha256:4ff33d2d5a1b1d0a3c3b4b59a6e3fbb23b0c1cbd7b4e1a0b8e1e1a4a2c9a7f10",
+		},
+		"Upper case sha": {
+			imageName:         "alpine",
+			imageSHA:          "DE78803598BC4C940FC4591D412BFFE488205D5D953F94751C6308DEEAAA7EB8",
+			expectedImageName: "alpine@sha256:de78803598bc4c940fc4591d412bffe488205d5d953f94751c6308deeaaa7eb8",
+		},
 	}
 	for testname, test := range testInsertSHA {
 		t.Logf("Running test case %s", testname)
@@ -8,16 +8,31 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/lf-edge/eve/libs/zedUpload"
 	"github.com/lf-edge/eve/pkg/pillar/base"
+	"github.com/lf-edge/eve/pkg/pillar/cipher"
 	"github.com/lf-edge/eve/pkg/pillar/types"
 	"github.com/lf-edge/eve/pkg/pillar/zedpac"
 )
 
+// LookupProxy returns the proxy to reach rawUrl from ifname, or nil if
+// there is none. The proxy passwords are decrypted using decryptCtx. The
+// callers which use a proxy need to pass one; without it the proxies with
+// a username are used without credentials, which is logged as an error.
 func LookupProxy(log *base.LogObject, status *types.DeviceNetworkStatus, ifname string,
-	rawUrl string) (*url.URL, error) {
+	rawUrl string, decryptCtx *cipher.DecryptCipherContext) (*url.URL, error) {
+
+	return lookupProxy(log, status, ifname, rawUrl, decryptCtx, true)
+}
+
+// lookupProxy is LookupProxy, where the credentials are only added to the
+// proxy if withCredentials is set
+func lookupProxy(log *base.LogObject, status *types.DeviceNetworkStatus, ifname string,
+	rawUrl string, decryptCtx *cipher.DecryptCipherContext,
+	withCredentials bool) (*url.URL, error) {
 
 	for _, port := range status.Ports {
 		log.Tracef("LookupProxy: Looking for proxy config on port %s",
@@ -41,46 +56,14 @@ func LookupProxy(log *base.LogObject, status *types.DeviceNetworkStatus, ifname
 
 		// Check if we have a PAC file
 		if len(proxyConfig.Pacfile) > 0 {
-			pacFile, err := base64.StdEncoding.DecodeString(proxyConfig.Pacfile)
-			if err != nil {
-				errStr := fmt.Sprintf("LookupProxy: Decoding proxy file failed: %s", err)
-				log.Errorf(errStr)
-				return nil, errors.New(errStr)
-			}
-			proxyString, err := zedpac.Find_proxy_sync(
-				string(pacFile), rawUrl, u.Host)
-			if err != nil {
-				errStr := fmt.Sprintf("LookupProxy: PAC file could not find proxy for %s: %s",
-					rawUrl, err)
-				log.Errorf(errStr)
-				return nil, errors.New(errStr)
-			}
-			//if proxyString == "DIRECT" {
-			if strings.HasPrefix(proxyString, "DIRECT") {
-				return nil, nil
-			}
-			proxies := strings.Split(proxyString, ";")
-			if len(proxies) == 0 {
-				log.Errorf("LookupProxy: Number of proxies in PAC file result is Zero")
-				return nil, nil
-			}
-
-			// XXX Take the first proxy for now. Failing over to the next
-			// proxy should be implemented
-			proxy0 := proxies[0]
-			proxy0 = strings.Split(proxy0, " ")[1]
-			// Proxy address returned by PAC does not have the URL scheme.
-			// We prepend the scheme (http/https) of the incoming raw URL.
-			proxy0 = "http://" + proxy0
-			proxy, err := url.Parse(proxy0)
-			if err != nil {
-				errStr := fmt.Sprintf("LookupProxy: PAC file returned invalid proxy %s: %s",
-					proxyString, err)
-				log.Errorf(errStr)
-				return nil, errors.New(errStr)
+			proxy, err := lookupPacProxy(log, proxyConfig.Pacfile, rawUrl,
+				u.Host)
+			if err == nil ||
+				proxyConfig.Policy != types.ProxyPolicyPacThenStatic {
+				return proxy, err
 			}
-			log.Tracef("LookupProxy: PAC proxy being used is %s", proxy0)
-			return proxy, err
+			log.Warnf("LookupProxy: using the explicit proxies for %s: %s",
+				rawUrl, err)
 		}
 
 		config := &Config{}
@@ -94,8 +77,12 @@ func LookupProxy(log *base.LogObject, status *types.DeviceNetworkStatus, ifname
 					httpProxy = fmt.Sprintf("%s", proxy.Server)
 				}
 				config.HTTPProxy = httpProxy
+				if withCredentials {
+					config.HTTPProxy = proxyUserInfo(log, proxy, decryptCtx) +
+						httpProxy
+				}
 				log.Tracef("LookupProxy: Adding HTTP proxy %s for port %s",
-					config.HTTPProxy, ifname)
+					httpProxy, ifname)
 			case types.NPT_HTTPS:
 				var httpsProxy string
 				if proxy.Port > 0 {
@@ -104,13 +91,25 @@ func LookupProxy(log *base.LogObject, status *types.DeviceNetworkStatus, ifname
 					httpsProxy = fmt.Sprintf("%s", proxy.Server)
 				}
 				config.HTTPSProxy = httpsProxy
+				if withCredentials {
+					config.HTTPSProxy = proxyUserInfo(log, proxy, decryptCtx) +
+						httpsProxy
+				}
 				log.Tracef("LookupProxy: Adding HTTPS proxy %s for port %s",
-					config.HTTPSProxy, ifname)
+					httpsProxy, ifname)
 			default:
 				// XXX We should take care of Socks proxy, FTP proxy also in future
 			}
 		}
-		config.NoProxy = proxyConfig.Exceptions
+		if len(proxyConfig.ExceptionList) != 0 {
+			if proxyConfig.MatchesException(u.Hostname(), urlPort(u)) {
+				log.Tracef("LookupProxy: %s is a proxy exception for port %s",
+					u.Host, ifname)
+				return nil, nil
+			}
+		} else {
+			config.NoProxy = proxyConfig.Exceptions
+		}
 		proxyFunc := config.ProxyFunc()
 		proxy, err := proxyFunc(u)
 		if err != nil {
@@ -124,6 +123,95 @@ func LookupProxy(log *base.LogObject, status *types.DeviceNetworkStatus, ifname
 	return nil, nil
 }
 
+// lookupPacProxy returns the proxy for rawUrl from the base64 encoded PAC
+// file, or nil if the URL is reached directly
+func lookupPacProxy(log *base.LogObject, pacfile string, rawUrl string,
+	host string) (*url.URL, error) {
+
+	pacFile, err := base64.StdEncoding.DecodeString(pacfile)
+	if err != nil {
+		errStr := fmt.Sprintf("LookupProxy: Decoding proxy file failed: %s", err)
+		log.Errorf(errStr)
+		return nil, errors.New(errStr)
+	}
+	proxyString, err := zedpac.Find_proxy_sync(
+		string(pacFile), rawUrl, host)
+	if err != nil {
+		errStr := fmt.Sprintf("LookupProxy: PAC file could not find proxy for %s: %s",
+			rawUrl, err)
+		log.Errorf(errStr)
+		return nil, errors.New(errStr)
+	}
+	//if proxyString == "DIRECT" {
+	if strings.HasPrefix(proxyString, "DIRECT") {
+		return nil, nil
+	}
+	proxies := strings.Split(proxyString, ";")
+	if len(proxies) == 0 {
+		log.Errorf("LookupProxy: Number of proxies in PAC file result is Zero")
+		return nil, nil
+	}
+
+	// XXX Take the first proxy for now. Failing over to the next
+	// proxy should be implemented
+	proxy0 := proxies[0]
+	proxy0 = strings.Split(proxy0, " ")[1]
+	// Proxy address returned by PAC does not have the URL scheme.
+	// We prepend the scheme (http/https) of the incoming raw URL.
+	proxy0 = "http://" + proxy0
+	proxy, err := url.Parse(proxy0)
+	if err != nil {
+		errStr := fmt.Sprintf("LookupProxy: PAC file returned invalid proxy %s: %s",
+			proxyString, err)
+		log.Errorf(errStr)
+		return nil, errors.New(errStr)
+	}
+	log.Tracef("LookupProxy: PAC proxy being used is %s", proxy0)
+	return proxy, err
+}
+
+// urlPort returns the port of the URL, or the default port of the scheme
+func urlPort(u *url.URL) uint16 {
+	if p := u.Port(); p != "" {
+		port, err := strconv.ParseUint(p, 10, 16)
+		if err != nil {
+			return 0
+		}
+		return uint16(port)
+	}
+	switch u.Scheme {
+	case "http":
+		return 80
+	case "https":
+		return 443
+	}
+	return 0
+}
+
+// proxyUserInfo returns the escaped credentials with the trailing "@" to
+// prefix to the proxy address, or an empty string if there are none or
+// the password can not be decrypted
+func proxyUserInfo(log *base.LogObject, proxy types.ProxyEntry,
+	decryptCtx *cipher.DecryptCipherContext) string {
+	if proxy.Username == "" {
+		return ""
+	}
+	if decryptCtx == nil {
+		log.Errorf("LookupProxy: no cipher context to decrypt the password for proxy %s username %s",
+			proxy.Server, proxy.Username)
+		return ""
+	}
+	_, decBlock, err := cipher.GetCipherCredentials(decryptCtx,
+		"zedcloud", proxy.CipherBlockStatus)
+	if err != nil {
+		log.Errorf("LookupProxy: proxy %s cipherblock decryption unsuccessful: %v",
+			proxy.Server, err)
+		return ""
+	}
+	return url.UserPassword(proxy.Username,
+		decBlock.ProxyPassword).String() + "@"
+}
+
 // IntfLookupProxyCfg - check if the intf has proxy configured
 func IntfLookupProxyCfg(log *base.LogObject, status *types.DeviceNetworkStatus, ifname, downloadURL string,
 	trType zedUpload.SyncTransportType) string {
@@ -152,14 +240,17 @@ func IntfLookupProxyCfg(log *base.LogObject, status *types.DeviceNetworkStatus,
 		return downloadURL
 	}
 
+	// Only checking for a proxy, hence without the credentials
 	tmpURL := passURL
 	tmpURL.Scheme = "https"
-	proxyURL, err := LookupProxy(log, status, ifname, tmpURL.String())
+	proxyURL, err := lookupProxy(log, status, ifname, tmpURL.String(),
+		nil, false)
 	if err == nil && proxyURL != nil {
 		return tmpURL.String()
 	}
 	tmpURL.Scheme = "http"
-	proxyURL, err = LookupProxy(log, status, ifname, tmpURL.String())
+	proxyURL, err = lookupProxy(log, status, ifname, tmpURL.String(),
+		nil, false)
 	if err == nil && proxyURL != nil {
 		return tmpURL.String()
 	}
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/lf-edge/eve/pkg/pillar/base"
+	"github.com/lf-edge/eve/pkg/pillar/cipher"
 	"github.com/lf-edge/eve/pkg/pillar/types"
 	"github.com/lf-edge/eve/pkg/pillar/utils"
 	"github.com/satori/go.uuid"
@@ -42,6 +43,8 @@ type ZedCloudContext struct {
 	NetworkSendTimeout  uint32 // In seconds
 	V2API               bool   // XXX Needed?
 	AgentName           string // the agent process name
+	// DecryptCipherContext is used to decrypt the proxy passwords
+	DecryptCipherContext *cipher.DecryptCipherContext
 	// V2 related items
 	PrevCertPEM           [][]byte // cached proxy certs for later comparison
 	onBoardCert           *tls.Certificate
@@ -64,6 +67,8 @@ type ContextOptions struct {
 	Serial           string
 	SoftSerial       string
 	AgentName        string // XXX replace by NoLogFailures?
+	// DecryptCipherContext is needed for authenticated proxies
+	DecryptCipherContext *cipher.DecryptCipherContext
 }
 
 var nilUUID = uuid.UUID{}
@@ -329,13 +334,18 @@ func SendOnIntf(ctx *ZedCloudContext, destURL string, intf string, reqlen int64,
 		return nil, nil, senderStatus, errors.New(errStr)
 	}
 
-	// Get the transport header with proxy information filled
-	proxyUrl, err := LookupProxy(ctx.log, ctx.DeviceNetworkStatus, intf, reqUrl)
+	// Get the transport header with proxy information filled. Only looked
+	// up if allowed, since that decrypts the proxy passwords
+	var proxyUrl *url.URL
+	if allowProxy {
+		proxyUrl, err = LookupProxy(ctx.log, ctx.DeviceNetworkStatus, intf,
+			reqUrl, ctx.DecryptCipherContext)
+	}
 	var transport *http.Transport
 	var usedProxy bool
-	if err == nil && proxyUrl != nil && allowProxy {
+	if err == nil && proxyUrl != nil {
 		log.Tracef("sendOnIntf: For input URL %s, proxy found is %s",
-			reqUrl, proxyUrl.String())
+			reqUrl, proxyUrl.Redacted())
 		usedProxy = true
 		transport = &http.Transport{
 			TLSClientConfig: ctx.TlsConfig,
@@ -806,6 +816,8 @@ func NewContext(log *base.LogObject, opt ContextOptions) ZedCloudContext {
 		DevSoftSerial:       opt.SoftSerial,
 		AgentName:           opt.AgentName,
 		log:                 log,
+
+		DecryptCipherContext: opt.DecryptCipherContext,
 	}
 	if opt.NeedStatsFunc {
 		ctx.FailureFunc = ZedCloudFailure
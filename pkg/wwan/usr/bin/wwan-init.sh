@@ -22,11 +22,26 @@ MODE=qmi
 WATCHDOG_TIMEOUT=300
 LTESTAT_TIMEOUT=120
 
+# /run/accesspoint/$IFACE has the APN, optionally followed by the
+# authentication protocol (pap, chap or both), the username and the password
 get_apn() {
-  APN="$(cat /run/accesspoint/"$IFACE" 2>/dev/null)"
+  APN="$(sed -n 1p /run/accesspoint/"$IFACE" 2>/dev/null)"
   echo "${APN:-internetd.gdsp}"
 }
 
+get_auth() {
+  AUTH="$(sed -n 2p /run/accesspoint/"$IFACE" 2>/dev/null)"
+  echo "${AUTH:-none}"
+}
+
+get_username() {
+  sed -n 3p /run/accesspoint/"$IFACE" 2>/dev/null
+}
+
+get_password() {
+  sed -n 4p /run/accesspoint/"$IFACE" 2>/dev/null
+}
+
 mbus_publish() {
   [ -d "$BBS" ] || mkdir -p $BBS || exit 1
   cat > "$BBS/${1}.json"
@@ -66,14 +81,26 @@ start_network() {
      # may be useful to check --query-packet-service-state just in case.
      mbim --attach-packet-service
      sleep 10
-     mbim --connect="apn='$(get_apn)'"
+     case "$(get_auth)" in
+        pap) AUTH=",auth='PAP',username='$(get_username)',password='$(get_password)'" ;;
+        # MBIM has no combined PAP and CHAP; CHAP is the safer one
+        chap|both) AUTH=",auth='CHAP',username='$(get_username)',password='$(get_password)'" ;;
+        *) AUTH="" ;;
+     esac
+     mbim --connect="apn='$(get_apn)'$AUTH"
   else
      ip link set $IFACE down
      echo Y > /sys/class/net/$IFACE/qmi/raw_ip
      ip link set $IFACE up
 
-     qmi --start-network --apn "$(get_apn)" --keep-client-id wds |\
-         mbus_publish pdh_$IFACE
+     if [ "$(get_auth)" = none ]; then
+        qmi --start-network --apn "$(get_apn)" --keep-client-id wds |\
+            mbus_publish pdh_$IFACE
+     else
+        qmi --start-network --apn "$(get_apn)" --auth-type "$(get_auth)" \
+            --username "$(get_username)" --password "$(get_password)" \
+            --keep-client-id wds | mbus_publish pdh_$IFACE
+     fi
   fi
 }
 